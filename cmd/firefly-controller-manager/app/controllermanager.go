@@ -19,13 +19,13 @@ package app
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	"go.opentelemetry.io/otel"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/uuid"
@@ -33,13 +33,9 @@ import (
 	"k8s.io/apiserver/pkg/server/healthz"
 	"k8s.io/apiserver/pkg/server/mux"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
-	cacheddiscovery "k8s.io/client-go/discovery/cached"
-	"k8s.io/client-go/informers"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
-	"k8s.io/client-go/metadata"
-	"k8s.io/client-go/metadata/metadatainformer"
 	restclient "k8s.io/client-go/rest"
-	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	cliflag "k8s.io/component-base/cli/flag"
@@ -51,9 +47,7 @@ import (
 	"k8s.io/component-base/version"
 	"k8s.io/component-base/version/verflag"
 	genericcontrollermanager "k8s.io/controller-manager/app"
-	"k8s.io/controller-manager/controller"
 	controllerhealthz "k8s.io/controller-manager/pkg/healthz"
-	"k8s.io/controller-manager/pkg/informerfactory"
 	"k8s.io/controller-manager/pkg/leadermigration"
 	"k8s.io/klog/v2"
 
@@ -61,17 +55,23 @@ import (
 	"github.com/carlory/firefly/cmd/firefly-controller-manager/app/options"
 	"github.com/carlory/firefly/pkg/clientbuilder"
 	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
-	fireflyversioned "github.com/carlory/firefly/pkg/generated/clientset/versioned"
-	fireflyinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions"
+	"github.com/carlory/firefly/pkg/controllermanager"
+	"github.com/carlory/firefly/pkg/features"
+	"github.com/carlory/firefly/pkg/helm"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/backoff"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	"github.com/carlory/firefly/pkg/util/kms"
+	leaderelectionutil "github.com/carlory/firefly/pkg/util/leaderelection"
+	"github.com/carlory/firefly/pkg/util/notification"
 )
 
 func init() {
 	utilruntime.Must(logsapi.AddFeatureGates(utilfeature.DefaultMutableFeatureGate))
+	utilruntime.Must(features.AddFeatureGates(utilfeature.DefaultMutableFeatureGate))
 }
 
 const (
-	// ControllerStartJitter is the Jitter used when starting controller managers
-	ControllerStartJitter = 1.0
 	// ConfigzName is the name used for register firefly-controller manager /configz, same with GroupName.
 	ConfigzName = "fireflycontrollermanager.config.firefly.io"
 )
@@ -144,10 +144,7 @@ controller, and serviceaccounts controller.`,
 // invoked; this is so that multiple controllers don't get into lock-step and all
 // hammer the apiserver with list requests simultaneously.
 func ResyncPeriod(c *config.CompletedConfig) func() time.Duration {
-	return func() time.Duration {
-		factor := rand.Float64() + 1
-		return time.Duration(float64(c.ComponentConfig.Generic.MinResyncPeriod.Nanoseconds()) * factor)
-	}
+	return controllermanager.ResyncPeriod(c.ComponentConfig.Generic.MinResyncPeriod.Duration)
 }
 
 // Run runs the FireflyControllerManagerOptions.
@@ -168,6 +165,16 @@ func Run(c *config.CompletedConfig, stopCh <-chan struct{}) error {
 		klog.Errorf("unable to register configz: %v", err)
 	}
 
+	kubeconfigCtx, _ := wait.ContextForChannel(stopCh)
+	for _, reloader := range c.KubeconfigReloaders {
+		go reloader.Run(kubeconfigCtx)
+	}
+
+	// The controllers create the spans of their reconciles by the global tracer provider.
+	if c.TracerProvider != nil {
+		otel.SetTracerProvider(c.TracerProvider)
+	}
+
 	// Setup any healthz checks we will want to use.
 	var checks []healthz.HealthChecker
 	var electionChecker *leaderelection.HealthzAdaptor
@@ -177,11 +184,36 @@ func Run(c *config.CompletedConfig, stopCh <-chan struct{}) error {
 	}
 	healthzHandler := controllerhealthz.NewMutableHealthzHandler(checks...)
 
+	// controllersStarting is closed when this instance starts the controllers, and controllersReady
+	// is closed once they have been started and the caches of the shared informers have synced.
+	// The readyz check fails in between. A standby instance waiting for the leader lease is ready,
+	// otherwise a rolling update would never complete.
+	controllersStarting := make(chan struct{})
+	controllersReady := make(chan struct{})
+	var controllersStartingOnce, controllersReadyOnce sync.Once
+	if !c.ComponentConfig.Generic.LeaderElection.LeaderElect {
+		close(controllersStarting)
+	}
+	readyzCheck := healthz.NamedCheck("controllers", func(_ *http.Request) error {
+		select {
+		case <-controllersReady:
+			return nil
+		default:
+		}
+		select {
+		case <-controllersStarting:
+			return fmt.Errorf("controllers are not started or the informer caches are not synced yet")
+		default:
+			return nil
+		}
+	})
+
 	// Start the controller manager HTTP server
 	// unsecuredMux is the handler for these controller *after* authn/authz filters have been applied
 	var unsecuredMux *mux.PathRecorderMux
 	if c.SecureServing != nil {
 		unsecuredMux = genericcontrollermanager.NewBaseHandler(&c.ComponentConfig.Generic.Debugging, healthzHandler)
+		healthz.InstallReadyzHandler(unsecuredMux, readyzCheck)
 		handler := genericcontrollermanager.BuildHandlerChain(unsecuredMux, &c.Authorization, &c.Authentication)
 		// TODO: handle stoppedCh and listenerStoppedCh returned by c.SecureServing.Serve
 		if _, _, err := c.SecureServing.Serve(handler, 0, stopCh); err != nil {
@@ -192,19 +224,54 @@ func Run(c *config.CompletedConfig, stopCh <-chan struct{}) error {
 	clientBuilder, rootClientBuilder := createClientBuilders(c)
 
 	run := func(ctx context.Context, initializersFunc ControllerInitializersFunc) {
+		controllersStartingOnce.Do(func() { close(controllersStarting) })
+
 		controllerContext, err := CreateControllerContext(c, rootClientBuilder, clientBuilder, ctx.Done())
+		if err != nil && backoff.IsTransient(err) {
+			klog.Fatalf("error building controller context, the apiserver is still unreachable after %s: %v", c.ComponentConfig.StartupRetry.Timeout.Duration, err)
+		}
 		if err != nil {
 			klog.Fatalf("error building controller context: %v", err)
 		}
+		if cm := c.ComponentConfig.Audit.ConfigMap; cm != "" {
+			// the audit events are only recorded by the leader, so it owns the configmap.
+			namespace, name, _ := cache.SplitMetaNamespaceKey(cm)
+			sink := audit.NewConfigMapSink(rootClientBuilder.ClientOrDie("firefly-audit"), namespace, name, int(c.ComponentConfig.Audit.MaxEvents))
+			audit.RegisterSink(sink)
+			go sink.Run(ctx)
+		}
+		if cfg := c.ComponentConfig.SecretsEncryption; cfg.KMSEndpoint != "" {
+			encrypter, err := newSecretsEncrypter(cfg)
+			if err != nil {
+				klog.Fatalf("error configuring secrets encryption: %v", err)
+			}
+			kms.SetDefault(encrypter)
+		}
+		helm.SetInsecureHosts(c.ComponentConfig.Charts.InsecureHosts)
+		notification.Configure(c.ComponentConfig.Notification.MinInterval.Duration, int(c.ComponentConfig.Notification.ReconcileFailureThreshold))
+		if path := c.ComponentConfig.Notification.WebhookURLFile; path != "" {
+			webhookURL, err := notification.WebhookURLFromFile(path)
+			if err != nil {
+				klog.Fatalf("error configuring notification: %v", err)
+			}
+			sink := notification.NewWebhookSink(webhookURL, notification.Format(c.ComponentConfig.Notification.Format))
+			notification.RegisterSink(sink)
+			go sink.Run(ctx)
+		}
+		if c.ComponentConfig.DryRun {
+			klog.InfoS("Controllers are running in dry-run mode, nothing is mutated")
+			ctx = dryrun.WithDryRun(ctx)
+		}
 		controllerInitializers := initializersFunc()
-		if err := StartControllers(ctx, controllerContext, controllerInitializers, unsecuredMux, healthzHandler); err != nil {
+		if err := controllermanager.StartControllers(ctx, controllerContext, controllerInitializers, unsecuredMux, healthzHandler); err != nil {
 			klog.Fatalf("error starting controllers: %v", err)
 		}
 
-		controllerContext.KubeInformerFactory.Start(stopCh)
-		controllerContext.FireflyInformerFactory.Start(stopCh)
-		controllerContext.ObjectOrMetadataInformerFactory.Start(stopCh)
-		close(controllerContext.InformersStarted)
+		controllerContext.StartInformers(stopCh)
+
+		if controllerContext.WaitForCacheSync(ctx.Done()) {
+			controllersReadyOnce.Do(func() { close(controllersReady) })
+		}
 
 		<-ctx.Done()
 	}
@@ -223,6 +290,10 @@ func Run(c *config.CompletedConfig, stopCh <-chan struct{}) error {
 
 	// add a uniquifier so that two processes on the same host don't accidentally both become active
 	id = id + "_" + string(uuid.NewUUID())
+	// name the deployment of the holder, so that the holders of several deployments can be told apart
+	if suffix := c.ComponentConfig.LeaderElectionIdentitySuffix; suffix != "" {
+		id = id + "_" + suffix
+	}
 
 	// leaderMigrator will be non-nil if and only if Leader Migration is enabled.
 	var leaderMigrator *leadermigration.LeaderMigrator = nil
@@ -285,56 +356,10 @@ func Run(c *config.CompletedConfig, stopCh <-chan struct{}) error {
 }
 
 // ControllerContext defines the context object for controller
-type ControllerContext struct {
-	// ClientBuilder will provide a client for this controller to use
-	ClientBuilder clientbuilder.FireflyControllerClientBuilder
-
-	// KubeInformerFactory gives access to kubernetes informers for the controller.
-	KubeInformerFactory informers.SharedInformerFactory
-
-	// FireflyInformerFactory gives access to firefly informers for the controller.
-	FireflyInformerFactory fireflyinformers.SharedInformerFactory
-
-	// ObjectOrMetadataInformerFactory gives access to informers for typed resources
-	// and dynamic resources by their metadata. All generic controllers currently use
-	// object metadata - if a future controller needs access to the full object this
-	// would become GenericInformerFactory and take a dynamic client.
-	ObjectOrMetadataInformerFactory informerfactory.InformerFactory
-
-	// ComponentConfig provides access to init options for a given controller
-	ComponentConfig fireflyctrlmgrconfig.FireflyControllerManagerConfiguration
-
-	// DeferredDiscoveryRESTMapper is a RESTMapper that will defer
-	// initialization of the RESTMapper until the first mapping is
-	// requested.
-	RESTMapper *restmapper.DeferredDiscoveryRESTMapper
+type ControllerContext = controllermanager.ControllerContext
 
-	// AvailableResources is a map listing currently available resources
-	AvailableResources map[schema.GroupVersionResource]bool
-
-	// InformersStarted is closed after all of the controllers have been initialized and are running.  After this point it is safe,
-	// for an individual controller to start the shared informers. Before it is closed, they should not.
-	InformersStarted chan struct{}
-
-	// ResyncPeriod generates a duration each time it is invoked; this is so that
-	// multiple controllers don't get into lock-step and all hammer the apiserver
-	// with list requests simultaneously.
-	ResyncPeriod func() time.Duration
-}
-
-// IsControllerEnabled checks if the context's controllers enabled or not
-func (c ControllerContext) IsControllerEnabled(name string) bool {
-	return genericcontrollermanager.IsControllerEnabled(name, ControllersDisabledByDefault, c.ComponentConfig.Generic.Controllers)
-}
-
-// InitFunc is used to launch a particular controller. It returns a controller
-// that can optionally implement other interfaces so that the controller manager
-// can support the requested features.
-// The returned controller may be nil, which will be considered an anonymous controller
-// that requests no additional features from the controller manager.
-// Any error returned will cause the controller process to `Fatal`
-// The bool indicates whether the controller was enabled.
-type InitFunc func(ctx context.Context, controllerCtx ControllerContext) (controller controller.Interface, enabled bool, err error)
+// InitFunc is used to launch a particular controller.
+type InitFunc = controllermanager.InitFunc
 
 // ControllerInitializersFunc is used to create a collection of initializers.
 type ControllerInitializersFunc func() (initializers map[string]InitFunc)
@@ -346,7 +371,16 @@ func KnownControllers() []string {
 }
 
 // ControllersDisabledByDefault is the set of controllers which is disabled by default
-var ControllersDisabledByDefault = sets.NewString()
+var ControllersDisabledByDefault = controllermanager.ControllersDisabledByDefault
+
+// newSecretsEncrypter returns the encrypter of the secrets generated by the controllers with the configured KMS.
+func newSecretsEncrypter(cfg fireflyctrlmgrconfig.SecretsEncryptionConfiguration) (*kms.Encrypter, error) {
+	provider, err := kms.NewProvider(cfg.KMSProvider, cfg.KMSEndpoint, cfg.KMSTimeout.Duration)
+	if err != nil {
+		return nil, err
+	}
+	return kms.NewEncrypter(provider)
+}
 
 // NewControllerInitializers is a public map of named controller groups (you can start more than one in an init func)
 // paired to their InitFunc.  This allows for structured downstream composition and subdivision.
@@ -354,137 +388,39 @@ func NewControllerInitializers() map[string]InitFunc {
 	controllers := map[string]InitFunc{}
 	controllers["karmada"] = startKarmadaController
 	controllers["clusterpedia"] = startClusterpediaController
+	controllers["addon"] = startAddonController
+	controllers["clusterregistration"] = startClusterRegistrationController
+	controllers["clusteragent"] = startClusterAgentController
+	controllers["karmadahealth"] = startKarmadaHealthController
+	controllers["etcdmaintenance"] = startEtcdMaintenanceController
+	controllers["observability"] = startObservabilityController
+	controllers["orphan"] = startOrphanController
+	controllers["disasterrecovery"] = startDisasterRecoveryController
+	controllers["submariner"] = startSubmarinerController
+	controllers["secretsync"] = startSecretSyncController
+	controllers["notification"] = startNotificationController
 	return controllers
 }
 
-// GetAvailableResources gets the map which contains all available resources of the apiserver
-// TODO: In general, any controller checking this needs to be dynamic so
-// users don't have to restart their controller manager if they change the apiserver.
-// Until we get there, the structure here needs to be exposed for the construction of a proper ControllerContext.
-func GetAvailableResources(clientBuilder clientbuilder.FireflyControllerClientBuilder) (map[schema.GroupVersionResource]bool, error) {
-	client := clientBuilder.ClientOrDie("controller-discovery")
-	discoveryClient := client.Discovery()
-	_, resourceMap, err := discoveryClient.ServerGroupsAndResources()
-	if err != nil {
-		utilruntime.HandleError(fmt.Errorf("unable to get all supported resources from server: %v", err))
-	}
-	if len(resourceMap) == 0 {
-		return nil, fmt.Errorf("unable to get any supported resources from server")
-	}
-
-	allResources := map[schema.GroupVersionResource]bool{}
-	for _, apiResourceList := range resourceMap {
-		version, err := schema.ParseGroupVersion(apiResourceList.GroupVersion)
-		if err != nil {
-			return nil, err
-		}
-		for _, apiResource := range apiResourceList.APIResources {
-			allResources[version.WithResource(apiResource.Name)] = true
-		}
-	}
-
-	return allResources, nil
-}
-
 // CreateControllerContext creates a context struct containing references to resources needed by the
-// controllers such as the cloud provider and clientBuilder. rootClientBuilder is only used for
-// the shared-informers client and token controller.
+// controllers such as the clientBuilder. rootClientBuilder is only used for the shared-informers client.
+// It's retried with a backoff according to StartupRetry while the apiserver is unreachable.
 func CreateControllerContext(s *config.CompletedConfig, rootClientBuilder, clientBuilder clientbuilder.FireflyControllerClientBuilder, stop <-chan struct{}) (ControllerContext, error) {
-	versionedClient := rootClientBuilder.ClientOrDie("firefly-kube-shared-informers")
-	kubeSharedInformers := informers.NewSharedInformerFactory(versionedClient, ResyncPeriod(s)())
+	ctx, cancel := wait.ContextForChannel(stop)
+	defer cancel()
 
-	clientConfig := rootClientBuilder.ConfigOrDie("firefly-shared-informers")
-	fireflyClient := fireflyversioned.NewForConfigOrDie(clientConfig)
-	fireflySharedInformers := fireflyinformers.NewSharedInformerFactory(fireflyClient, ResyncPeriod(s)())
-
-	metadataClient := metadata.NewForConfigOrDie(rootClientBuilder.ConfigOrDie("firefly-metadata-informers"))
-	metadataInformers := metadatainformer.NewSharedInformerFactory(metadataClient, ResyncPeriod(s)())
-
-	// If apiserver is not running we should wait for some time and fail only then. This is particularly
-	// important when we start apiserver and controller manager at the same time.
-	if err := genericcontrollermanager.WaitForAPIServer(versionedClient, 10*time.Second); err != nil {
-		return ControllerContext{}, fmt.Errorf("failed to wait for apiserver being healthy: %v", err)
-	}
-
-	// Use a discovery client capable of being refreshed.
-	discoveryClient := rootClientBuilder.DiscoveryClientOrDie("firelfy-controller-discovery")
-	cachedClient := cacheddiscovery.NewMemCacheClient(discoveryClient)
-	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedClient)
-	go wait.Until(func() {
-		restMapper.Reset()
-	}, 30*time.Second, stop)
-
-	availableResources, err := GetAvailableResources(rootClientBuilder)
-	if err != nil {
-		return ControllerContext{}, err
-	}
-
-	ctx := ControllerContext{
-		ClientBuilder:                   clientBuilder,
-		KubeInformerFactory:             kubeSharedInformers,
-		FireflyInformerFactory:          fireflySharedInformers,
-		ObjectOrMetadataInformerFactory: informerfactory.NewInformerFactory(kubeSharedInformers, metadataInformers),
-		ComponentConfig:                 s.ComponentConfig,
-		RESTMapper:                      restMapper,
-		AvailableResources:              availableResources,
-		InformersStarted:                make(chan struct{}),
-		ResyncPeriod:                    ResyncPeriod(s),
-	}
-	return ctx, nil
-}
-
-// StartControllers starts a set of controllers with a specified ControllerContext
-func StartControllers(ctx context.Context, controllerCtx ControllerContext, controllers map[string]InitFunc,
-	unsecuredMux *mux.PathRecorderMux, healthzHandler *controllerhealthz.MutableHealthzHandler) error {
-	var controllerChecks []healthz.HealthChecker
-
-	for controllerName, initFn := range controllers {
-		if !controllerCtx.IsControllerEnabled(controllerName) {
-			klog.Warningf("%q is disabled", controllerName)
-			continue
-		}
-
-		time.Sleep(wait.Jitter(controllerCtx.ComponentConfig.Generic.ControllerStartInterval.Duration, ControllerStartJitter))
-
-		klog.V(1).Infof("Starting %q", controllerName)
-		ctrl, started, err := initFn(ctx, controllerCtx)
-		if err != nil {
-			klog.Errorf("Error starting %q", controllerName)
-			return err
-		}
-		if !started {
-			klog.Warningf("Skipping %q", controllerName)
-			continue
-		}
-		check := controllerhealthz.NamedPingChecker(controllerName)
-		if ctrl != nil {
-			// check if the controller supports and requests a debugHandler
-			// and it needs the unsecuredMux to mount the handler onto.
-			if debuggable, ok := ctrl.(controller.Debuggable); ok && unsecuredMux != nil {
-				if debugHandler := debuggable.DebuggingHandler(); debugHandler != nil {
-					basePath := "/debug/controllers/" + controllerName
-					unsecuredMux.UnlistedHandle(basePath, http.StripPrefix(basePath, debugHandler))
-					unsecuredMux.UnlistedHandlePrefix(basePath+"/", http.StripPrefix(basePath, debugHandler))
-				}
-			}
-			if healthCheckable, ok := ctrl.(controller.HealthCheckable); ok {
-				if realCheck := healthCheckable.HealthChecker(); realCheck != nil {
-					check = controllerhealthz.NamedHealthChecker(controllerName, realCheck)
-				}
-			}
-		}
-		controllerChecks = append(controllerChecks, check)
-
-		klog.Infof("Started %q", controllerName)
-	}
-
-	healthzHandler.AddHealthChecker(controllerChecks...)
-	return nil
+	var controllerContext ControllerContext
+	retry := s.ComponentConfig.StartupRetry
+	err := backoff.Retry(ctx, "building controller context", retry.Timeout.Duration, retry.InitialBackoff.Duration, func() (err error) {
+		controllerContext, err = controllermanager.NewControllerContext(s.ComponentConfig, rootClientBuilder, clientBuilder, stop)
+		return err
+	})
+	return controllerContext, err
 }
 
 // createClientBuilders creates clientBuilder and rootClientBuilder from the given configuration
 func createClientBuilders(c *config.CompletedConfig) (clientBuilder clientbuilder.FireflyControllerClientBuilder, rootClientBuilder clientbuilder.FireflyControllerClientBuilder) {
-	rootClientBuilder = clientbuilder.NewSimpleFireflyControllerClientBuilder(c.Kubeconfig)
+	rootClientBuilder = clientbuilder.NewSimpleFireflyControllerClientBuilder(c.Kubeconfig, options.FireflyControllerManagerUserAgent, c.ClientConnectionOverrides)
 	clientBuilder = rootClientBuilder
 	return
 }
@@ -492,9 +428,10 @@ func createClientBuilders(c *config.CompletedConfig) (clientBuilder clientbuilde
 // leaderElectAndRun runs the leader election, and runs the callbacks once the leader lease is acquired.
 // TODO: extract this function into staging/controller-manager
 func leaderElectAndRun(c *config.CompletedConfig, lockIdentity string, electionChecker *leaderelection.HealthzAdaptor, resourceLock string, leaseName string, callbacks leaderelection.LeaderCallbacks) {
-	rl, err := resourcelock.NewFromKubeconfig(resourceLock,
+	rl, err := leaderelectionutil.NewFromKubeconfig(resourceLock,
 		c.ComponentConfig.Generic.LeaderElection.ResourceNamespace,
 		leaseName,
+		c.ComponentConfig.LeaderElectionLabels,
 		resourcelock.ResourceLockConfig{
 			Identity:      lockIdentity,
 			EventRecorder: c.EventRecorder,
@@ -20,34 +20,336 @@ import (
 	"context"
 	"fmt"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/controller-manager/controller"
 
+	"github.com/carlory/firefly/pkg/controller/addon"
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+	"github.com/carlory/firefly/pkg/controller/clusteragent"
 	"github.com/carlory/firefly/pkg/controller/clusterpedia"
+	"github.com/carlory/firefly/pkg/controller/clusterregistration"
+	"github.com/carlory/firefly/pkg/controller/disasterrecovery"
+	"github.com/carlory/firefly/pkg/controller/etcdmaintenance"
 	"github.com/carlory/firefly/pkg/controller/karmada"
+	"github.com/carlory/firefly/pkg/controller/karmadahealth"
+	"github.com/carlory/firefly/pkg/controller/notification"
+	"github.com/carlory/firefly/pkg/controller/observability"
+	"github.com/carlory/firefly/pkg/controller/orphan"
+	"github.com/carlory/firefly/pkg/controller/secretsync"
+	"github.com/carlory/firefly/pkg/controller/submariner"
+	"github.com/carlory/firefly/pkg/util/backoff"
+	"github.com/carlory/firefly/pkg/util/capabilities"
+	informerutil "github.com/carlory/firefly/pkg/util/informer"
 )
 
 func startKarmadaController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	karmadaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas()
+	if err := informerutil.SetTransform(karmadaInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the karmada controller informers: %v", err)
+	}
+
 	ctrl, err := karmada.NewKarmadaController(
 		controllerContext.ClientBuilder.ClientOrDie("firefly-karmada-controller"),
 		controllerContext.ClientBuilder.FireflyClientOrDie("firefly-karmada-controller"),
-		controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas(),
+		karmadaInformer,
+		controllerContext.ClientBuilder.ConfigOrDie("firefly-karmada-controller"),
+		controllerContext.HostClusterCapabilities,
+		controllerContext.ComponentConfig.KarmadaController.Reconcile.ResyncPeriod.Duration,
+		reconcileRateLimiter(controllerContext.ComponentConfig.KarmadaController.Reconcile),
 	)
 	if err != nil {
 		return nil, true, fmt.Errorf("failed to start the karmada controller: %v", err)
 	}
-	go ctrl.Run(ctx, 1)
+	go ctrl.Run(ctx, int(controllerContext.ComponentConfig.KarmadaController.ConcurrentKarmadaSyncs))
 	return nil, true, nil
 }
 
 func startClusterpediaController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	clusterpediaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Clusterpedias()
+	if err := informerutil.SetTransform(clusterpediaInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the clusterpedia controller informers: %v", err)
+	}
+
 	ctrl, err := clusterpedia.NewClusterpediaController(
 		controllerContext.ClientBuilder.ClientOrDie("firefly-clusterpedia-controller"),
 		controllerContext.ClientBuilder.FireflyClientOrDie("firefly-clusterpedia-controller"),
-		controllerContext.FireflyInformerFactory.Install().V1alpha1().Clusterpedias(),
+		clusterpediaInformer,
+		controllerContext.ClientBuilder.ConfigOrDie("firefly-clusterpedia-controller"),
+		controllerContext.HostClusterCapabilities.Has(capabilities.PodMonitors),
+		controllerContext.AvailableResources[clusterpedia.InnoDBClusterGVR],
+		controllerContext.ComponentConfig.ClusterpediaController.Reconcile.ResyncPeriod.Duration,
+		reconcileRateLimiter(controllerContext.ComponentConfig.ClusterpediaController.Reconcile),
 	)
 	if err != nil {
 		return nil, true, fmt.Errorf("failed to start the clusterepedia controller: %v", err)
 	}
-	go ctrl.Run(ctx, 1)
+	go ctrl.Run(ctx, int(controllerContext.ComponentConfig.ClusterpediaController.ConcurrentClusterpediaSyncs))
+	return ctrl, true, nil
+}
+
+func startAddonController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	addonInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Addons()
+	karmadaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas()
+	if err := informerutil.SetTransform(addonInformer, karmadaInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the addon controller informers: %v", err)
+	}
+
+	ctrl, err := addon.NewAddonController(
+		controllerContext.ClientBuilder.ClientOrDie("firefly-addon-controller"),
+		controllerContext.ClientBuilder.FireflyClientOrDie("firefly-addon-controller"),
+		addonInformer,
+		karmadaInformer,
+		controllerContext.ComponentConfig.AddonController.Reconcile.ResyncPeriod.Duration,
+		reconcileRateLimiter(controllerContext.ComponentConfig.AddonController.Reconcile),
+	)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to start the addon controller: %v", err)
+	}
+	go ctrl.Run(ctx, int(controllerContext.ComponentConfig.AddonController.ConcurrentAddonSyncs))
+	return nil, true, nil
+}
+
+func startClusterRegistrationController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	clusterRegistrationInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().ClusterRegistrations()
+	karmadaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas()
+	if err := informerutil.SetTransform(clusterRegistrationInformer, karmadaInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the clusterregistration controller informers: %v", err)
+	}
+
+	ctrl, err := clusterregistration.NewClusterRegistrationController(
+		controllerContext.ClientBuilder.ClientOrDie("firefly-clusterregistration-controller"),
+		controllerContext.ClientBuilder.FireflyClientOrDie("firefly-clusterregistration-controller"),
+		clusterRegistrationInformer,
+		karmadaInformer,
+		controllerContext.ComponentConfig.ClusterRegistrationController.Reconcile.ResyncPeriod.Duration,
+		reconcileRateLimiter(controllerContext.ComponentConfig.ClusterRegistrationController.Reconcile),
+	)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to start the clusterregistration controller: %v", err)
+	}
+	go ctrl.Run(ctx, int(controllerContext.ComponentConfig.ClusterRegistrationController.ConcurrentClusterRegistrationSyncs))
+	return nil, true, nil
+}
+
+func startClusterAgentController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	clusterRegistrationInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().ClusterRegistrations()
+	karmadaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas()
+	if err := informerutil.SetTransform(clusterRegistrationInformer, karmadaInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the clusteragent controller informers: %v", err)
+	}
+
+	ctrl, err := clusteragent.NewClusterAgentController(
+		controllerContext.ClientBuilder.ClientOrDie("firefly-clusteragent-controller"),
+		controllerContext.ClientBuilder.FireflyClientOrDie("firefly-clusteragent-controller"),
+		clusterRegistrationInformer,
+		karmadaInformer,
+	)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to start the clusteragent controller: %v", err)
+	}
+	go ctrl.Run(ctx, int(controllerContext.ComponentConfig.ClusterAgentController.ConcurrentClusterAgentSyncs))
+	return nil, true, nil
+}
+
+func startKarmadaHealthController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	karmadaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas()
+	if err := informerutil.SetTransform(karmadaInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the karmadahealth controller informers: %v", err)
+	}
+
+	ctrl, err := karmadahealth.NewKarmadaHealthController(
+		controllerContext.ClientBuilder.ClientOrDie("firefly-karmadahealth-controller"),
+		controllerContext.ClientBuilder.FireflyClientOrDie("firefly-karmadahealth-controller"),
+		karmadaInformer,
+	)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to start the karmadahealth controller: %v", err)
+	}
+	go ctrl.Run(ctx, int(controllerContext.ComponentConfig.KarmadaHealthController.ConcurrentKarmadaHealthSyncs))
+	return nil, true, nil
+}
+
+func startEtcdMaintenanceController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	karmadaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas()
+	if err := informerutil.SetTransform(karmadaInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the etcdmaintenance controller informers: %v", err)
+	}
+
+	ctrl, err := etcdmaintenance.NewEtcdMaintenanceController(
+		controllerContext.ClientBuilder.ClientOrDie("firefly-etcdmaintenance-controller"),
+		controllerContext.ClientBuilder.FireflyClientOrDie("firefly-etcdmaintenance-controller"),
+		karmadaInformer,
+		controllerContext.ComponentConfig.EtcdMaintenanceController.SpaceThresholdPercent,
+		controllerContext.ComponentConfig.EtcdMaintenanceController.FragmentationThresholdPercent,
+		controllerContext.ComponentConfig.EtcdMaintenanceController.MinRemediationInterval.Duration,
+		controllerContext.ComponentConfig.EtcdMaintenanceController.CompactionRetentionRevisions,
+	)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to start the etcdmaintenance controller: %v", err)
+	}
+	go ctrl.Run(ctx, int(controllerContext.ComponentConfig.EtcdMaintenanceController.ConcurrentEtcdMaintenanceSyncs))
+	return nil, true, nil
+}
+
+func startObservabilityController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	karmadaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas()
+	clusterpediaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Clusterpedias()
+	if err := informerutil.SetTransform(karmadaInformer, clusterpediaInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the observability controller informers: %v", err)
+	}
+
+	ctrl, err := observability.NewObservabilityController(
+		controllerContext.ClientBuilder.ClientOrDie("firefly-observability-controller"),
+		karmadaInformer,
+		clusterpediaInformer,
+		controllerContext.ClientBuilder.ConfigOrDie("firefly-observability-controller"),
+		controllerContext.HostClusterCapabilities.Has(capabilities.PrometheusRules),
+	)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to start the observability controller: %v", err)
+	}
+	go ctrl.Run(ctx, int(controllerContext.ComponentConfig.ObservabilityController.ConcurrentObservabilitySyncs))
+	return nil, true, nil
+}
+
+func startOrphanController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	karmadaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas()
+	if err := informerutil.SetTransform(karmadaInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the orphan controller informers: %v", err)
+	}
+	// the orphan controller only cares about the labels of the resources managed by firefly.
+	factory := controllerContext.FilteredFactories.MetadataForController("orphan", metav1.NamespaceAll,
+		func(options *metav1.ListOptions) {
+			options.LabelSelector = orphan.ManagedSelector().String()
+		},
+	)
+	resourceInformers := make(map[schema.GroupVersionResource]informers.GenericInformer, len(orphan.ManagedResources))
+	for gvr := range orphan.ManagedResources {
+		informer := factory.ForResource(gvr)
+		if err := informerutil.SetTransform(informer); err != nil {
+			return nil, true, fmt.Errorf("failed to set transform of the orphan controller informers: %v", err)
+		}
+		resourceInformers[gvr] = informer
+	}
+
+	ctrl, err := orphan.NewOrphanController(
+		metadata.NewForConfigOrDie(controllerContext.ClientBuilder.ConfigOrDie("firefly-orphan-controller")),
+		controllerContext.ClientBuilder.FireflyClientOrDie("firefly-orphan-controller"),
+		karmadaInformer,
+		resourceInformers,
+	)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to start the orphan controller: %v", err)
+	}
+	go ctrl.Run(ctx, int(controllerContext.ComponentConfig.OrphanController.ConcurrentOrphanSyncs))
+	return nil, true, nil
+}
+
+func startDisasterRecoveryController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	if !controllerContext.AvailableResources[disasterrecovery.ScheduleGVR] {
+		// the controller is started only if Velero is installed.
+		return nil, false, nil
+	}
+	karmadaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas()
+	if err := informerutil.SetTransform(karmadaInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the disasterrecovery controller informers: %v", err)
+	}
+
+	ctrl, err := disasterrecovery.NewDisasterRecoveryController(
+		controllerContext.ClientBuilder.ClientOrDie("firefly-disasterrecovery-controller"),
+		controllerContext.ClientBuilder.FireflyClientOrDie("firefly-disasterrecovery-controller"),
+		karmadaInformer,
+		controllerContext.ClientBuilder.ConfigOrDie("firefly-disasterrecovery-controller"),
+		controllerContext.ComponentConfig.DisasterRecoveryController.VeleroNamespace,
+	)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to start the disasterrecovery controller: %v", err)
+	}
+	go ctrl.Run(ctx, int(controllerContext.ComponentConfig.DisasterRecoveryController.ConcurrentDisasterRecoverySyncs))
+	return nil, true, nil
+}
+
+func startSubmarinerController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	submarinerInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Submariners()
+	karmadaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas()
+	if err := informerutil.SetTransform(submarinerInformer, karmadaInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the submariner controller informers: %v", err)
+	}
+
+	ctrl, err := submariner.NewSubmarinerController(
+		controllerContext.ClientBuilder.ClientOrDie("firefly-submariner-controller"),
+		controllerContext.ClientBuilder.FireflyClientOrDie("firefly-submariner-controller"),
+		submarinerInformer,
+		karmadaInformer,
+		controllerContext.ComponentConfig.SubmarinerController.Reconcile.ResyncPeriod.Duration,
+		reconcileRateLimiter(controllerContext.ComponentConfig.SubmarinerController.Reconcile),
+	)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to start the submariner controller: %v", err)
+	}
+	go ctrl.Run(ctx, int(controllerContext.ComponentConfig.SubmarinerController.ConcurrentSubmarinerSyncs))
+	return nil, true, nil
+}
+
+func startSecretSyncController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	secretSyncInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().SecretSyncs()
+	karmadaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas()
+	secretInformer := controllerContext.KubeInformerFactory.Core().V1().Secrets()
+	configMapInformer := controllerContext.KubeInformerFactory.Core().V1().ConfigMaps()
+	if err := informerutil.SetTransform(secretSyncInformer, karmadaInformer, secretInformer, configMapInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the secretsync controller informers: %v", err)
+	}
+
+	ctrl, err := secretsync.NewSecretSyncController(
+		controllerContext.ClientBuilder.ClientOrDie("firefly-secretsync-controller"),
+		controllerContext.ClientBuilder.FireflyClientOrDie("firefly-secretsync-controller"),
+		secretSyncInformer,
+		karmadaInformer,
+		secretInformer,
+		configMapInformer,
+		controllerContext.ComponentConfig.SecretSyncController.Reconcile.ResyncPeriod.Duration,
+		reconcileRateLimiter(controllerContext.ComponentConfig.SecretSyncController.Reconcile),
+	)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to start the secretsync controller: %v", err)
+	}
+	go ctrl.Run(ctx, int(controllerContext.ComponentConfig.SecretSyncController.ConcurrentSecretSyncSyncs))
+	return nil, true, nil
+}
+
+func startNotificationController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	if controllerContext.ComponentConfig.Notification.WebhookURLFile == "" {
+		// the controller is started only if the alerts are posted to a webhook.
+		return nil, false, nil
+	}
+
+	karmadaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas()
+	clusterpediaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Clusterpedias()
+	addonInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Addons()
+	clusterRegistrationInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().ClusterRegistrations()
+	submarinerInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Submariners()
+	secretSyncInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().SecretSyncs()
+	if err := informerutil.SetTransform(karmadaInformer, clusterpediaInformer, addonInformer, clusterRegistrationInformer, submarinerInformer, secretSyncInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the notification controller informers: %v", err)
+	}
+
+	ctrl := notification.NewNotificationController(
+		karmadaInformer,
+		clusterpediaInformer,
+		addonInformer,
+		clusterRegistrationInformer,
+		submarinerInformer,
+		secretSyncInformer,
+	)
+	go ctrl.Run(ctx)
 	return nil, true, nil
 }
+
+// reconcileRateLimiter returns the rate limiter of the workqueue of a controller with the given reconcile configuration.
+func reconcileRateLimiter(cfg fireflyctrlmgrconfig.ReconcileConfiguration) workqueue.RateLimiter {
+	return backoff.RateLimiter(cfg.InitialBackoff.Duration, cfg.MaxBackoff.Duration)
+}
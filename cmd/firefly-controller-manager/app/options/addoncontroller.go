@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+)
+
+// AddonControllerOptions holds the AddonController options.
+type AddonControllerOptions struct {
+	*fireflyctrlmgrconfig.AddonControllerConfiguration
+}
+
+// AddFlags adds flags related to AddonController for controller manager to the specified FlagSet.
+func (o *AddonControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentAddonSyncs, "concurrent-addon-syncs", o.ConcurrentAddonSyncs, "The number of addon objects that are allowed to sync concurrently. Larger number = more responsive addons, but more CPU (and network) load")
+	addReconcileFlags(fs, "addon", &o.Reconcile)
+}
+
+// ApplyTo fills up AddonController config with options.
+func (o *AddonControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.AddonControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentAddonSyncs = o.ConcurrentAddonSyncs
+	cfg.Reconcile = o.Reconcile
+	return nil
+}
+
+// Validate checks validation of AddonControllerOptions.
+func (o *AddonControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentAddonSyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-addon-syncs must be greater than 0, got %d", o.ConcurrentAddonSyncs))
+	}
+	errs = append(errs, validateReconcile("addon", &o.Reconcile)...)
+	return errs
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/tools/cache"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+)
+
+// maxAuditEvents bounds the number of audit events kept by the configmap, whose size is limited to 1MiB.
+const maxAuditEvents = 2000
+
+// AuditOptions holds the audit options.
+type AuditOptions struct {
+	*fireflyctrlmgrconfig.AuditConfiguration
+}
+
+// AddFlags adds flags related to audit for controller manager to the specified FlagSet.
+func (o *AuditOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.StringVar(&o.ConfigMap, "audit-configmap", o.ConfigMap, "The namespace/name of the configmap which keeps the latest audit events of the mutations performed by the controllers, as a ring buffer. The audit events are only logged if it is empty.")
+	fs.Int32Var(&o.MaxEvents, "audit-max-events", o.MaxEvents, "The number of the latest audit events kept by the audit configmap.")
+}
+
+// ApplyTo fills up audit config with options.
+func (o *AuditOptions) ApplyTo(cfg *fireflyctrlmgrconfig.AuditConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConfigMap = o.ConfigMap
+	cfg.MaxEvents = o.MaxEvents
+	return nil
+}
+
+// Validate checks validation of AuditOptions.
+func (o *AuditOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConfigMap != "" {
+		if namespace, name, err := cache.SplitMetaNamespaceKey(o.ConfigMap); err != nil || namespace == "" || name == "" {
+			errs = append(errs, fmt.Errorf("audit-configmap must be in the form of namespace/name, got %q", o.ConfigMap))
+		}
+	}
+	if o.MaxEvents < 1 || o.MaxEvents > maxAuditEvents {
+		errs = append(errs, fmt.Errorf("audit-max-events must be between 1 and %d, got %d", maxAuditEvents, o.MaxEvents))
+	}
+	return errs
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+)
+
+// ChartsOptions holds the charts options.
+type ChartsOptions struct {
+	*fireflyctrlmgrconfig.ChartsConfiguration
+}
+
+// AddFlags adds flags related to charts for controller manager to the specified FlagSet.
+func (o *ChartsOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.StringSliceVar(&o.InsecureHosts, "chart-insecure-hosts", o.InsecureHosts, "The hosts, e.g. chartmuseum.firefly-system.svc:8080, which the charts referenced by the objects may be loaded from over plain http. The charts are only loaded over https from the other hosts.")
+}
+
+// ApplyTo fills up charts config with options.
+func (o *ChartsOptions) ApplyTo(cfg *fireflyctrlmgrconfig.ChartsConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.InsecureHosts = o.InsecureHosts
+	return nil
+}
+
+// Validate checks validation of ChartsOptions.
+func (o *ChartsOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, host := range o.InsecureHosts {
+		if host == "" || strings.ContainsAny(host, "/?#@") {
+			errs = append(errs, fmt.Errorf("chart-insecure-hosts must be a list of hosts with optional ports, got %q", host))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+)
+
+// ClusterAgentControllerOptions holds the ClusterAgentController options.
+type ClusterAgentControllerOptions struct {
+	*fireflyctrlmgrconfig.ClusterAgentControllerConfiguration
+}
+
+// AddFlags adds flags related to ClusterAgentController for controller manager to the specified FlagSet.
+func (o *ClusterAgentControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentClusterAgentSyncs, "concurrent-clusteragent-syncs", o.ConcurrentClusterAgentSyncs, "The number of cluster registration objects in pull mode that are allowed to sync concurrently. Larger number = more responsive cluster agents, but more CPU (and network) load")
+}
+
+// ApplyTo fills up ClusterAgentController config with options.
+func (o *ClusterAgentControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.ClusterAgentControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentClusterAgentSyncs = o.ConcurrentClusterAgentSyncs
+	return nil
+}
+
+// Validate checks validation of ClusterAgentControllerOptions.
+func (o *ClusterAgentControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentClusterAgentSyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-clusteragent-syncs must be greater than 0, got %d", o.ConcurrentClusterAgentSyncs))
+	}
+	return errs
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+)
+
+// ClusterpediaControllerOptions holds the ClusterpediaController options.
+type ClusterpediaControllerOptions struct {
+	*fireflyctrlmgrconfig.ClusterpediaControllerConfiguration
+}
+
+// AddFlags adds flags related to ClusterpediaController for controller manager to the specified FlagSet.
+func (o *ClusterpediaControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentClusterpediaSyncs, "concurrent-clusterpedia-syncs", o.ConcurrentClusterpediaSyncs, "The number of clusterpedia objects that are allowed to sync concurrently. Larger number = more responsive clusterpedias, but more CPU (and network) load")
+	addReconcileFlags(fs, "clusterpedia", &o.Reconcile)
+}
+
+// ApplyTo fills up ClusterpediaController config with options.
+func (o *ClusterpediaControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.ClusterpediaControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentClusterpediaSyncs = o.ConcurrentClusterpediaSyncs
+	cfg.Reconcile = o.Reconcile
+	return nil
+}
+
+// Validate checks validation of ClusterpediaControllerOptions.
+func (o *ClusterpediaControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentClusterpediaSyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-clusterpedia-syncs must be greater than 0, got %d", o.ConcurrentClusterpediaSyncs))
+	}
+	errs = append(errs, validateReconcile("clusterpedia", &o.Reconcile)...)
+	return errs
+}
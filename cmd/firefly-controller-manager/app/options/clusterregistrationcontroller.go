@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+)
+
+// ClusterRegistrationControllerOptions holds the ClusterRegistrationController options.
+type ClusterRegistrationControllerOptions struct {
+	*fireflyctrlmgrconfig.ClusterRegistrationControllerConfiguration
+}
+
+// AddFlags adds flags related to ClusterRegistrationController for controller manager to the specified FlagSet.
+func (o *ClusterRegistrationControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentClusterRegistrationSyncs, "concurrent-clusterregistration-syncs", o.ConcurrentClusterRegistrationSyncs, "The number of cluster registration objects that are allowed to sync concurrently. Larger number = more responsive cluster registrations, but more CPU (and network) load")
+	addReconcileFlags(fs, "clusterregistration", &o.Reconcile)
+}
+
+// ApplyTo fills up ClusterRegistrationController config with options.
+func (o *ClusterRegistrationControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.ClusterRegistrationControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentClusterRegistrationSyncs = o.ConcurrentClusterRegistrationSyncs
+	cfg.Reconcile = o.Reconcile
+	return nil
+}
+
+// Validate checks validation of ClusterRegistrationControllerOptions.
+func (o *ClusterRegistrationControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentClusterRegistrationSyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-clusterregistration-syncs must be greater than 0, got %d", o.ConcurrentClusterRegistrationSyncs))
+	}
+	errs = append(errs, validateReconcile("clusterregistration", &o.Reconcile)...)
+	return errs
+}
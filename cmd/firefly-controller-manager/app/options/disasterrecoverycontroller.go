@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+)
+
+// DisasterRecoveryControllerOptions holds the DisasterRecoveryController options.
+type DisasterRecoveryControllerOptions struct {
+	*fireflyctrlmgrconfig.DisasterRecoveryControllerConfiguration
+}
+
+// AddFlags adds flags related to DisasterRecoveryController for controller manager to the specified FlagSet.
+func (o *DisasterRecoveryControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentDisasterRecoverySyncs, "concurrent-disasterrecovery-syncs", o.ConcurrentDisasterRecoverySyncs, "The number of karmada objects whose backups and restores are allowed to sync concurrently. Larger number = more responsive backups and restores, but more CPU (and network) load")
+	fs.StringVar(&o.VeleroNamespace, "velero-namespace", o.VeleroNamespace, "The namespace Velero is installed in, where the Velero Schedules and Restores of the karmadas are created.")
+}
+
+// ApplyTo fills up DisasterRecoveryController config with options.
+func (o *DisasterRecoveryControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.DisasterRecoveryControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentDisasterRecoverySyncs = o.ConcurrentDisasterRecoverySyncs
+	cfg.VeleroNamespace = o.VeleroNamespace
+	return nil
+}
+
+// Validate checks validation of DisasterRecoveryControllerOptions.
+func (o *DisasterRecoveryControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentDisasterRecoverySyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-disasterrecovery-syncs must be greater than 0, got %d", o.ConcurrentDisasterRecoverySyncs))
+	}
+	if o.VeleroNamespace == "" {
+		errs = append(errs, fmt.Errorf("velero-namespace must not be empty"))
+	}
+	return errs
+}
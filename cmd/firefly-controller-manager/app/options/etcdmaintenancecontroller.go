@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+)
+
+// EtcdMaintenanceControllerOptions holds the EtcdMaintenanceController options.
+type EtcdMaintenanceControllerOptions struct {
+	*fireflyctrlmgrconfig.EtcdMaintenanceControllerConfiguration
+}
+
+// AddFlags adds flags related to EtcdMaintenanceController for controller manager to the specified FlagSet.
+func (o *EtcdMaintenanceControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentEtcdMaintenanceSyncs, "concurrent-etcdmaintenance-syncs", o.ConcurrentEtcdMaintenanceSyncs, "The number of karmada objects whose etcd is maintained that are allowed to sync concurrently. Larger number = more responsive maintenance, but more CPU (and network) load")
+	fs.Int32Var(&o.SpaceThresholdPercent, "etcd-space-threshold-percent", o.SpaceThresholdPercent, "The percentage of the space quota of the local etcd of a karmada which, once its database exceeds it, makes the database compacted and defragmented.")
+	fs.Int32Var(&o.FragmentationThresholdPercent, "etcd-fragmentation-threshold-percent", o.FragmentationThresholdPercent, "The percentage of the database of the local etcd of a karmada not in use which, once it's exceeded, makes the database defragmented.")
+	fs.DurationVar(&o.MinRemediationInterval.Duration, "etcd-min-remediation-interval", o.MinRemediationInterval.Duration, "The minimum interval between two compactions and defragmentations of the local etcd of a karmada, since a defragmentation blocks the writes to etcd while it's running.")
+	fs.Int64Var(&o.CompactionRetentionRevisions, "etcd-compaction-retention-revisions", o.CompactionRetentionRevisions, "The number of the latest revisions of the local etcd of a karmada kept by a compaction, so that the watchers of the karmada-apiserver which are slightly behind don't have to relist.")
+}
+
+// ApplyTo fills up EtcdMaintenanceController config with options.
+func (o *EtcdMaintenanceControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.EtcdMaintenanceControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentEtcdMaintenanceSyncs = o.ConcurrentEtcdMaintenanceSyncs
+	cfg.SpaceThresholdPercent = o.SpaceThresholdPercent
+	cfg.FragmentationThresholdPercent = o.FragmentationThresholdPercent
+	cfg.MinRemediationInterval = o.MinRemediationInterval
+	cfg.CompactionRetentionRevisions = o.CompactionRetentionRevisions
+	return nil
+}
+
+// Validate checks validation of EtcdMaintenanceControllerOptions.
+func (o *EtcdMaintenanceControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentEtcdMaintenanceSyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-etcdmaintenance-syncs must be greater than 0, got %d", o.ConcurrentEtcdMaintenanceSyncs))
+	}
+	if o.SpaceThresholdPercent < 1 || o.SpaceThresholdPercent > 100 {
+		errs = append(errs, fmt.Errorf("etcd-space-threshold-percent must be between 1 and 100, got %d", o.SpaceThresholdPercent))
+	}
+	if o.FragmentationThresholdPercent < 1 || o.FragmentationThresholdPercent > 100 {
+		errs = append(errs, fmt.Errorf("etcd-fragmentation-threshold-percent must be between 1 and 100, got %d", o.FragmentationThresholdPercent))
+	}
+	if o.MinRemediationInterval.Duration < 0 {
+		errs = append(errs, fmt.Errorf("etcd-min-remediation-interval must not be negative, got %v", o.MinRemediationInterval.Duration))
+	}
+	if o.CompactionRetentionRevisions < 0 {
+		errs = append(errs, fmt.Errorf("etcd-compaction-retention-revisions must not be negative, got %d", o.CompactionRetentionRevisions))
+	}
+	return errs
+}
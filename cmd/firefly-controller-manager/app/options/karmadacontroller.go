@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+)
+
+// KarmadaControllerOptions holds the KarmadaController options.
+type KarmadaControllerOptions struct {
+	*fireflyctrlmgrconfig.KarmadaControllerConfiguration
+}
+
+// AddFlags adds flags related to KarmadaController for controller manager to the specified FlagSet.
+func (o *KarmadaControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentKarmadaSyncs, "concurrent-karmada-syncs", o.ConcurrentKarmadaSyncs, "The number of karmada objects that are allowed to sync concurrently. Larger number = more responsive karmadas, but more CPU (and network) load")
+	addReconcileFlags(fs, "karmada", &o.Reconcile)
+}
+
+// ApplyTo fills up KarmadaController config with options.
+func (o *KarmadaControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.KarmadaControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentKarmadaSyncs = o.ConcurrentKarmadaSyncs
+	cfg.Reconcile = o.Reconcile
+	return nil
+}
+
+// Validate checks validation of KarmadaControllerOptions.
+func (o *KarmadaControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentKarmadaSyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-karmada-syncs must be greater than 0, got %d", o.ConcurrentKarmadaSyncs))
+	}
+	errs = append(errs, validateReconcile("karmada", &o.Reconcile)...)
+	return errs
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+)
+
+// KarmadaHealthControllerOptions holds the KarmadaHealthController options.
+type KarmadaHealthControllerOptions struct {
+	*fireflyctrlmgrconfig.KarmadaHealthControllerConfiguration
+}
+
+// AddFlags adds flags related to KarmadaHealthController for controller manager to the specified FlagSet.
+func (o *KarmadaHealthControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentKarmadaHealthSyncs, "concurrent-karmadahealth-syncs", o.ConcurrentKarmadaHealthSyncs, "The number of karmada objects whose health is probed that are allowed to sync concurrently. Larger number = more responsive health conditions, but more CPU (and network) load")
+}
+
+// ApplyTo fills up KarmadaHealthController config with options.
+func (o *KarmadaHealthControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.KarmadaHealthControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentKarmadaHealthSyncs = o.ConcurrentKarmadaHealthSyncs
+	return nil
+}
+
+// Validate checks validation of KarmadaHealthControllerOptions.
+func (o *KarmadaHealthControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentKarmadaHealthSyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-karmadahealth-syncs must be greater than 0, got %d", o.ConcurrentKarmadaHealthSyncs))
+	}
+	return errs
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+	"github.com/carlory/firefly/pkg/util/notification"
+)
+
+// NotificationOptions holds the notification options.
+type NotificationOptions struct {
+	*fireflyctrlmgrconfig.NotificationConfiguration
+}
+
+// AddFlags adds flags related to notification for controller manager to the specified FlagSet.
+func (o *NotificationOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.StringVar(&o.WebhookURLFile, "notification-webhook-url-file", o.WebhookURLFile, "The path of the file holding the url of the webhook the alerts are posted to when an installation degrades or a controller keeps failing to reconcile an object, e.g. a key of a mounted Secret. The alerts are only logged if it is empty.")
+	fs.StringVar(&o.Format, "notification-format", o.Format, "The format of the body posted to the notification webhook, one of json and slack.")
+	fs.Int32Var(&o.ReconcileFailureThreshold, "notification-reconcile-failure-threshold", o.ReconcileFailureThreshold, "The number of failed reconciles of an object in a row which is alerted. 0 disables the alerts of the failed reconciles.")
+	fs.DurationVar(&o.MinInterval.Duration, "notification-min-interval", o.MinInterval.Duration, "The min interval between the alerts of a reason for an object.")
+}
+
+// ApplyTo fills up notification config with options.
+func (o *NotificationOptions) ApplyTo(cfg *fireflyctrlmgrconfig.NotificationConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.WebhookURLFile = o.WebhookURLFile
+	cfg.Format = o.Format
+	cfg.ReconcileFailureThreshold = o.ReconcileFailureThreshold
+	cfg.MinInterval = o.MinInterval
+	return nil
+}
+
+// Validate checks validation of NotificationOptions.
+func (o *NotificationOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.WebhookURLFile != "" {
+		if _, err := notification.WebhookURLFromFile(o.WebhookURLFile); err != nil {
+			errs = append(errs, fmt.Errorf("notification-webhook-url-file is invalid: %v", err))
+		}
+	}
+	switch notification.Format(o.Format) {
+	case notification.FormatJSON, notification.FormatSlack:
+	default:
+		errs = append(errs, fmt.Errorf("notification-format must be one of %s and %s, got %q", notification.FormatJSON, notification.FormatSlack, o.Format))
+	}
+	if o.ReconcileFailureThreshold < 0 {
+		errs = append(errs, fmt.Errorf("notification-reconcile-failure-threshold must not be negative, got %d", o.ReconcileFailureThreshold))
+	}
+	if o.MinInterval.Duration < 0 {
+		errs = append(errs, fmt.Errorf("notification-min-interval must not be negative, got %s", o.MinInterval.Duration))
+	}
+	return errs
+}
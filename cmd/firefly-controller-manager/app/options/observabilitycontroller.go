@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+)
+
+// ObservabilityControllerOptions holds the ObservabilityController options.
+type ObservabilityControllerOptions struct {
+	*fireflyctrlmgrconfig.ObservabilityControllerConfiguration
+}
+
+// AddFlags adds flags related to ObservabilityController for controller manager to the specified FlagSet.
+func (o *ObservabilityControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentObservabilitySyncs, "concurrent-observability-syncs", o.ConcurrentObservabilitySyncs, "The number of karmada and clusterpedia objects whose dashboards and alerts are allowed to sync concurrently. Larger number = more responsive dashboards and alerts, but more CPU (and network) load")
+}
+
+// ApplyTo fills up ObservabilityController config with options.
+func (o *ObservabilityControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.ObservabilityControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentObservabilitySyncs = o.ConcurrentObservabilitySyncs
+	return nil
+}
+
+// Validate checks validation of ObservabilityControllerOptions.
+func (o *ObservabilityControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentObservabilitySyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-observability-syncs must be greater than 0, got %d", o.ConcurrentObservabilitySyncs))
+	}
+	return errs
+}
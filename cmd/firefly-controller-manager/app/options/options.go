@@ -25,10 +25,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	apiserveroptions "k8s.io/apiserver/pkg/server/options"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	clientset "k8s.io/client-go/kubernetes"
 	clientgokubescheme "k8s.io/client-go/kubernetes/scheme"
 	restclient "k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/record"
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/component-base/logs"
@@ -39,7 +39,12 @@ import (
 	netutils "k8s.io/utils/net"
 
 	fireflycontrollerconfig "github.com/carlory/firefly/cmd/firefly-controller-manager/app/config"
+	"github.com/carlory/firefly/pkg/clientbuilder"
 	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	"github.com/carlory/firefly/pkg/util/kms"
+	"github.com/carlory/firefly/pkg/util/leaderelection"
+	"github.com/carlory/firefly/pkg/util/notification"
 )
 
 const (
@@ -56,9 +61,44 @@ type FireflyControllerManagerOptions struct {
 	Authorization  *apiserveroptions.DelegatingAuthorizationOptions
 	Metrics        *metrics.Options
 	Logs           *logs.Options
+	Tracing        *TracingOptions
+
+	KarmadaController             *KarmadaControllerOptions
+	ClusterpediaController        *ClusterpediaControllerOptions
+	AddonController               *AddonControllerOptions
+	ClusterRegistrationController *ClusterRegistrationControllerOptions
+	ClusterAgentController        *ClusterAgentControllerOptions
+	KarmadaHealthController       *KarmadaHealthControllerOptions
+	EtcdMaintenanceController     *EtcdMaintenanceControllerOptions
+	ObservabilityController       *ObservabilityControllerOptions
+	OrphanController              *OrphanControllerOptions
+	DisasterRecoveryController    *DisasterRecoveryControllerOptions
+	SubmarinerController          *SubmarinerControllerOptions
+	SecretSyncController          *SecretSyncControllerOptions
+	Audit                         *AuditOptions
+	Notification                  *NotificationOptions
+	SecretsEncryption             *SecretsEncryptionOptions
+	Charts                        *ChartsOptions
 
 	Master     string
 	Kubeconfig string
+
+	// DryRun makes the controllers preview the reconciles instead of performing them.
+	DryRun bool
+
+	// StartupRetryTimeout is how long the manager retries to start while the apiserver is unreachable.
+	StartupRetryTimeout time.Duration
+	// StartupRetryBackoff is the interval before the first retry of the startup.
+	StartupRetryBackoff time.Duration
+
+	// LeaderElectionLabels are set on the leader election leases.
+	LeaderElectionLabels map[string]string
+	// LeaderElectionIdentitySuffix is appended to the holder identities of the leader election leases.
+	LeaderElectionIdentitySuffix string
+
+	// ControllerClientConnections overrides the QPS and Burst of the clients of the given
+	// controllers, in the form of <controller>=<qps>:<burst>[:<timeout>].
+	ControllerClientConnections map[string]string
 }
 
 // NewFireflyControllerManagerOptions creates a new FireflyControllerManagerOptions with a default config.
@@ -70,12 +110,64 @@ func NewFireflyControllerManagerOptions() (*FireflyControllerManagerOptions, err
 
 	s := FireflyControllerManagerOptions{
 		Generic: cmoptions.NewGenericControllerManagerConfigurationOptions(&componentConfig.Generic),
+		KarmadaController: &KarmadaControllerOptions{
+			KarmadaControllerConfiguration: &componentConfig.KarmadaController,
+		},
+		ClusterpediaController: &ClusterpediaControllerOptions{
+			ClusterpediaControllerConfiguration: &componentConfig.ClusterpediaController,
+		},
+		AddonController: &AddonControllerOptions{
+			AddonControllerConfiguration: &componentConfig.AddonController,
+		},
+		ClusterRegistrationController: &ClusterRegistrationControllerOptions{
+			ClusterRegistrationControllerConfiguration: &componentConfig.ClusterRegistrationController,
+		},
+		ClusterAgentController: &ClusterAgentControllerOptions{
+			ClusterAgentControllerConfiguration: &componentConfig.ClusterAgentController,
+		},
+		KarmadaHealthController: &KarmadaHealthControllerOptions{
+			KarmadaHealthControllerConfiguration: &componentConfig.KarmadaHealthController,
+		},
+		EtcdMaintenanceController: &EtcdMaintenanceControllerOptions{
+			EtcdMaintenanceControllerConfiguration: &componentConfig.EtcdMaintenanceController,
+		},
+		ObservabilityController: &ObservabilityControllerOptions{
+			ObservabilityControllerConfiguration: &componentConfig.ObservabilityController,
+		},
+		OrphanController: &OrphanControllerOptions{
+			OrphanControllerConfiguration: &componentConfig.OrphanController,
+		},
+		DisasterRecoveryController: &DisasterRecoveryControllerOptions{
+			DisasterRecoveryControllerConfiguration: &componentConfig.DisasterRecoveryController,
+		},
+		SubmarinerController: &SubmarinerControllerOptions{
+			SubmarinerControllerConfiguration: &componentConfig.SubmarinerController,
+		},
+		SecretSyncController: &SecretSyncControllerOptions{
+			SecretSyncControllerConfiguration: &componentConfig.SecretSyncController,
+		},
+		Audit: &AuditOptions{
+			AuditConfiguration: &componentConfig.Audit,
+		},
+		Notification: &NotificationOptions{
+			NotificationConfiguration: &componentConfig.Notification,
+		},
+		SecretsEncryption: &SecretsEncryptionOptions{
+			SecretsEncryptionConfiguration: &componentConfig.SecretsEncryption,
+		},
+		Charts: &ChartsOptions{
+			ChartsConfiguration: &componentConfig.Charts,
+		},
 
 		SecureServing:  apiserveroptions.NewSecureServingOptions().WithLoopback(),
 		Authentication: apiserveroptions.NewDelegatingAuthenticationOptions(),
 		Authorization:  apiserveroptions.NewDelegatingAuthorizationOptions(),
 		Metrics:        metrics.NewOptions(),
 		Logs:           logs.NewOptions(),
+		Tracing:        &TracingOptions{},
+
+		StartupRetryTimeout: componentConfig.StartupRetry.Timeout.Duration,
+		StartupRetryBackoff: componentConfig.StartupRetry.InitialBackoff.Duration,
 	}
 
 	// Set the PairName but leave certificate directory blank to generate in-memory by default
@@ -97,6 +189,69 @@ func NewDefaultComponentConfig() (fireflyctrlmgrconfig.FireflyControllerManagerC
 			MinResyncPeriod:         metav1.Duration{Duration: 12 * time.Hour},
 			ControllerStartInterval: metav1.Duration{Duration: 0 * time.Second},
 		},
+		KarmadaController: fireflyctrlmgrconfig.KarmadaControllerConfiguration{
+			ConcurrentKarmadaSyncs: 1,
+			Reconcile:              defaultReconcileConfiguration(),
+		},
+		ClusterpediaController: fireflyctrlmgrconfig.ClusterpediaControllerConfiguration{
+			ConcurrentClusterpediaSyncs: 1,
+			Reconcile:                   defaultReconcileConfiguration(),
+		},
+		AddonController: fireflyctrlmgrconfig.AddonControllerConfiguration{
+			ConcurrentAddonSyncs: 1,
+			Reconcile:            defaultReconcileConfiguration(),
+		},
+		ClusterRegistrationController: fireflyctrlmgrconfig.ClusterRegistrationControllerConfiguration{
+			ConcurrentClusterRegistrationSyncs: 1,
+			Reconcile:                          defaultReconcileConfiguration(),
+		},
+		ClusterAgentController: fireflyctrlmgrconfig.ClusterAgentControllerConfiguration{
+			ConcurrentClusterAgentSyncs: 1,
+		},
+		KarmadaHealthController: fireflyctrlmgrconfig.KarmadaHealthControllerConfiguration{
+			ConcurrentKarmadaHealthSyncs: 1,
+		},
+		EtcdMaintenanceController: fireflyctrlmgrconfig.EtcdMaintenanceControllerConfiguration{
+			ConcurrentEtcdMaintenanceSyncs: 1,
+			SpaceThresholdPercent:          80,
+			FragmentationThresholdPercent:  50,
+			MinRemediationInterval:         metav1.Duration{Duration: 10 * time.Minute},
+			CompactionRetentionRevisions:   1000,
+		},
+		ObservabilityController: fireflyctrlmgrconfig.ObservabilityControllerConfiguration{
+			ConcurrentObservabilitySyncs: 1,
+		},
+		OrphanController: fireflyctrlmgrconfig.OrphanControllerConfiguration{
+			ConcurrentOrphanSyncs: 1,
+		},
+		DisasterRecoveryController: fireflyctrlmgrconfig.DisasterRecoveryControllerConfiguration{
+			ConcurrentDisasterRecoverySyncs: 1,
+			VeleroNamespace:                 "velero",
+		},
+		SubmarinerController: fireflyctrlmgrconfig.SubmarinerControllerConfiguration{
+			ConcurrentSubmarinerSyncs: 1,
+			Reconcile:                 defaultReconcileConfiguration(),
+		},
+		SecretSyncController: fireflyctrlmgrconfig.SecretSyncControllerConfiguration{
+			ConcurrentSecretSyncSyncs: 1,
+			Reconcile:                 defaultReconcileConfiguration(),
+		},
+		Audit: fireflyctrlmgrconfig.AuditConfiguration{
+			MaxEvents: 500,
+		},
+		Notification: fireflyctrlmgrconfig.NotificationConfiguration{
+			Format:                    string(notification.FormatJSON),
+			ReconcileFailureThreshold: 5,
+			MinInterval:               metav1.Duration{Duration: 10 * time.Minute},
+		},
+		SecretsEncryption: fireflyctrlmgrconfig.SecretsEncryptionConfiguration{
+			KMSProvider: kms.KMSv1,
+			KMSTimeout:  metav1.Duration{Duration: 3 * time.Second},
+		},
+		StartupRetry: fireflyctrlmgrconfig.StartupRetryConfiguration{
+			Timeout:        metav1.Duration{Duration: 5 * time.Minute},
+			InitialBackoff: metav1.Duration{Duration: time.Second},
+		},
 	}
 	return internal, nil
 }
@@ -105,6 +260,22 @@ func NewDefaultComponentConfig() (fireflyctrlmgrconfig.FireflyControllerManagerC
 func (s *FireflyControllerManagerOptions) Flags(allControllers []string, disabledByDefaultControllers []string) cliflag.NamedFlagSets {
 	fss := cliflag.NamedFlagSets{}
 	s.Generic.AddFlags(&fss, allControllers, disabledByDefaultControllers)
+	s.KarmadaController.AddFlags(fss.FlagSet("karmada controller"))
+	s.ClusterpediaController.AddFlags(fss.FlagSet("clusterpedia controller"))
+	s.AddonController.AddFlags(fss.FlagSet("addon controller"))
+	s.ClusterRegistrationController.AddFlags(fss.FlagSet("clusterregistration controller"))
+	s.ClusterAgentController.AddFlags(fss.FlagSet("clusteragent controller"))
+	s.KarmadaHealthController.AddFlags(fss.FlagSet("karmadahealth controller"))
+	s.EtcdMaintenanceController.AddFlags(fss.FlagSet("etcdmaintenance controller"))
+	s.ObservabilityController.AddFlags(fss.FlagSet("observability controller"))
+	s.OrphanController.AddFlags(fss.FlagSet("orphan controller"))
+	s.DisasterRecoveryController.AddFlags(fss.FlagSet("disasterrecovery controller"))
+	s.SubmarinerController.AddFlags(fss.FlagSet("submariner controller"))
+	s.SecretSyncController.AddFlags(fss.FlagSet("secretsync controller"))
+	s.Audit.AddFlags(fss.FlagSet("audit"))
+	s.Notification.AddFlags(fss.FlagSet("notification"))
+	s.SecretsEncryption.AddFlags(fss.FlagSet("secrets encryption"))
+	s.Charts.AddFlags(fss.FlagSet("charts"))
 
 	s.SecureServing.AddFlags(fss.FlagSet("secure serving"))
 	s.Authentication.AddFlags(fss.FlagSet("authentication"))
@@ -112,10 +283,18 @@ func (s *FireflyControllerManagerOptions) Flags(allControllers []string, disable
 
 	s.Metrics.AddFlags(fss.FlagSet("metrics"))
 	logsapi.AddFlags(s.Logs, fss.FlagSet("logs"))
+	utilfeature.DefaultMutableFeatureGate.AddFlag(fss.FlagSet("feature gates"))
+	s.Tracing.AddFlags(fss.FlagSet("tracing"))
 
 	fs := fss.FlagSet("misc")
 	fs.StringVar(&s.Master, "master", s.Master, "The address of the Kubernetes API server (overrides any value in kubeconfig).")
-	fs.StringVar(&s.Kubeconfig, "kubeconfig", s.Kubeconfig, "Path to kubeconfig file with authorization and master location information.")
+	fs.StringVar(&s.Kubeconfig, "kubeconfig", s.Kubeconfig, "Path to kubeconfig file with authorization and master location information. If empty, the in-cluster config of the service account of the pod is used. Exec credential plugins and token files are supported, the credentials are reloaded when they rotate.")
+	fs.Var(cliflag.NewMapStringString(&s.ControllerClientConnections), "controller-client-connections", "A set of <controller>=<qps>:<burst>[:<timeout>] pairs which override --kube-api-qps and --kube-api-burst for the clients of the given controllers, and bound each of their requests by the timeout, e.g. firefly-karmada-controller=50:100:30s.")
+	fs.BoolVar(&s.DryRun, "dry-run", s.DryRun, "If true, the controllers only log the objects they would create, update or delete, after validating the mutations by server-side dry-run requests. A single object is previewed by annotating it with firefly.io/dry-run=true instead.")
+	fs.Var(cliflag.NewMapStringString(&s.LeaderElectionLabels), "leader-elect-labels", "A set of key=value pairs set as labels on the leader election leases, so that the leases of several firefly deployments sharing --leader-elect-resource-namespace can be told apart. Only supported by the leases resource lock.")
+	fs.StringVar(&s.LeaderElectionIdentitySuffix, "leader-elect-identity-suffix", s.LeaderElectionIdentitySuffix, "A suffix appended to the holder identities of the leader election leases, naming the deployment the holders belong to.")
+	fs.DurationVar(&s.StartupRetryTimeout, "startup-retry-timeout", s.StartupRetryTimeout, "How long the manager retries to build the context of the controllers while the apiserver is unreachable, before it exits. The configuration errors aren't retried. 0 means the manager exits on the first failure.")
+	fs.DurationVar(&s.StartupRetryBackoff, "startup-retry-backoff", s.StartupRetryBackoff, "The interval before the first retry of the startup, which is doubled after each retry up to 30s.")
 
 	return fss
 }
@@ -125,6 +304,62 @@ func (s *FireflyControllerManagerOptions) ApplyTo(c *fireflycontrollerconfig.Con
 	if err := s.Generic.ApplyTo(&c.ComponentConfig.Generic); err != nil {
 		return err
 	}
+	if err := s.KarmadaController.ApplyTo(&c.ComponentConfig.KarmadaController); err != nil {
+		return err
+	}
+	if err := s.ClusterpediaController.ApplyTo(&c.ComponentConfig.ClusterpediaController); err != nil {
+		return err
+	}
+	if err := s.AddonController.ApplyTo(&c.ComponentConfig.AddonController); err != nil {
+		return err
+	}
+	if err := s.ClusterRegistrationController.ApplyTo(&c.ComponentConfig.ClusterRegistrationController); err != nil {
+		return err
+	}
+	if err := s.ClusterAgentController.ApplyTo(&c.ComponentConfig.ClusterAgentController); err != nil {
+		return err
+	}
+	if err := s.KarmadaHealthController.ApplyTo(&c.ComponentConfig.KarmadaHealthController); err != nil {
+		return err
+	}
+	if err := s.EtcdMaintenanceController.ApplyTo(&c.ComponentConfig.EtcdMaintenanceController); err != nil {
+		return err
+	}
+	if err := s.ObservabilityController.ApplyTo(&c.ComponentConfig.ObservabilityController); err != nil {
+		return err
+	}
+	if err := s.OrphanController.ApplyTo(&c.ComponentConfig.OrphanController); err != nil {
+		return err
+	}
+	if err := s.DisasterRecoveryController.ApplyTo(&c.ComponentConfig.DisasterRecoveryController); err != nil {
+		return err
+	}
+	if err := s.SubmarinerController.ApplyTo(&c.ComponentConfig.SubmarinerController); err != nil {
+		return err
+	}
+	if err := s.SecretSyncController.ApplyTo(&c.ComponentConfig.SecretSyncController); err != nil {
+		return err
+	}
+	if err := s.Audit.ApplyTo(&c.ComponentConfig.Audit); err != nil {
+		return err
+	}
+	if err := s.Notification.ApplyTo(&c.ComponentConfig.Notification); err != nil {
+		return err
+	}
+	if err := s.SecretsEncryption.ApplyTo(&c.ComponentConfig.SecretsEncryption); err != nil {
+		return err
+	}
+	if err := s.Charts.ApplyTo(&c.ComponentConfig.Charts); err != nil {
+		return err
+	}
+	c.ComponentConfig.DryRun = s.DryRun
+	c.ComponentConfig.LeaderElectionLabels = s.LeaderElectionLabels
+	c.ComponentConfig.LeaderElectionIdentitySuffix = s.LeaderElectionIdentitySuffix
+	c.ComponentConfig.StartupRetry.Timeout = metav1.Duration{Duration: s.StartupRetryTimeout}
+	c.ComponentConfig.StartupRetry.InitialBackoff = metav1.Duration{Duration: s.StartupRetryBackoff}
+	if err := s.Tracing.ApplyTo(c); err != nil {
+		return err
+	}
 	if err := s.SecureServing.ApplyTo(&c.SecureServing, &c.LoopbackClientConfig); err != nil {
 		return err
 	}
@@ -142,6 +377,27 @@ func (s *FireflyControllerManagerOptions) ApplyTo(c *fireflycontrollerconfig.Con
 // Validate is used to validate the options and config before launching the controller manager
 func (s *FireflyControllerManagerOptions) Validate(allControllers []string, disabledByDefaultControllers []string) error {
 	var errs []error
+	if _, err := clientbuilder.ParseClientConnectionOverrides(s.ControllerClientConnections); err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, s.KarmadaController.Validate()...)
+	errs = append(errs, s.ClusterpediaController.Validate()...)
+	errs = append(errs, s.AddonController.Validate()...)
+	errs = append(errs, s.ClusterRegistrationController.Validate()...)
+	errs = append(errs, s.ClusterAgentController.Validate()...)
+	errs = append(errs, s.KarmadaHealthController.Validate()...)
+	errs = append(errs, s.EtcdMaintenanceController.Validate()...)
+	errs = append(errs, s.ObservabilityController.Validate()...)
+	errs = append(errs, s.OrphanController.Validate()...)
+	errs = append(errs, s.DisasterRecoveryController.Validate()...)
+	errs = append(errs, s.SubmarinerController.Validate()...)
+	errs = append(errs, s.SecretSyncController.Validate()...)
+	errs = append(errs, s.Audit.Validate()...)
+	errs = append(errs, s.Notification.Validate()...)
+	errs = append(errs, s.SecretsEncryption.Validate()...)
+	errs = append(errs, s.Charts.Validate()...)
+	errs = append(errs, s.Tracing.Validate()...)
+	errs = append(errs, leaderelection.ValidateLabels(s.Generic.LeaderElection.ResourceLock, s.LeaderElectionLabels)...)
 	return utilerrors.NewAggregate(errs)
 }
 
@@ -155,7 +411,12 @@ func (s FireflyControllerManagerOptions) Config(allControllers []string, disable
 		return nil, fmt.Errorf("error creating self-signed certificates: %v", err)
 	}
 
-	kubeconfig, err := clientcmd.BuildConfigFromFlags(s.Master, s.Kubeconfig)
+	kubeconfig, err := clientbuilder.LoadClientConfig(s.Master, s.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	// the rotated credentials of the kubeconfig are used without restarting.
+	kubeconfigReloader, err := clientbuilder.NewKubeconfigReloader(s.Master, s.Kubeconfig, kubeconfig)
 	if err != nil {
 		return nil, err
 	}
@@ -164,20 +425,31 @@ func (s FireflyControllerManagerOptions) Config(allControllers []string, disable
 	kubeconfig.ContentConfig.ContentType = s.Generic.ClientConnection.ContentType
 	kubeconfig.QPS = s.Generic.ClientConnection.QPS
 	kubeconfig.Burst = int(s.Generic.ClientConnection.Burst)
+	// the mutations sent with a dry-run context are dry-run, see --dry-run.
+	kubeconfig.Wrap(dryrun.WrapperFor())
 
 	client, err := clientset.NewForConfig(restclient.AddUserAgent(kubeconfig, FireflyControllerManagerUserAgent))
 	if err != nil {
 		return nil, err
 	}
 
+	clientConnectionOverrides, err := clientbuilder.ParseClientConnectionOverrides(s.ControllerClientConnections)
+	if err != nil {
+		return nil, err
+	}
+
 	eventBroadcaster := record.NewBroadcaster()
 	eventRecorder := eventBroadcaster.NewRecorder(clientgokubescheme.Scheme, v1.EventSource{Component: FireflyControllerManagerUserAgent})
 
 	c := &fireflycontrollerconfig.Config{
-		Client:           client,
-		Kubeconfig:       kubeconfig,
-		EventBroadcaster: eventBroadcaster,
-		EventRecorder:    eventRecorder,
+		Client:                    client,
+		Kubeconfig:                kubeconfig,
+		EventBroadcaster:          eventBroadcaster,
+		EventRecorder:             eventRecorder,
+		ClientConnectionOverrides: clientConnectionOverrides,
+	}
+	if kubeconfigReloader != nil {
+		c.KubeconfigReloaders = append(c.KubeconfigReloaders, kubeconfigReloader)
 	}
 	if err := s.ApplyTo(c); err != nil {
 		return nil, err
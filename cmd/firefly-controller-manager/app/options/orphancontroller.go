@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+)
+
+// OrphanControllerOptions holds the OrphanController options.
+type OrphanControllerOptions struct {
+	*fireflyctrlmgrconfig.OrphanControllerConfiguration
+}
+
+// AddFlags adds flags related to OrphanController for controller manager to the specified FlagSet.
+func (o *OrphanControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentOrphanSyncs, "concurrent-orphan-syncs", o.ConcurrentOrphanSyncs, "The number of firefly-managed resources that are allowed to be checked for orphans concurrently. Larger number = more responsive cleanup, but more CPU (and network) load")
+}
+
+// ApplyTo fills up OrphanController config with options.
+func (o *OrphanControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.OrphanControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentOrphanSyncs = o.ConcurrentOrphanSyncs
+	return nil
+}
+
+// Validate checks validation of OrphanControllerOptions.
+func (o *OrphanControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentOrphanSyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-orphan-syncs must be greater than 0, got %d", o.ConcurrentOrphanSyncs))
+	}
+	return errs
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+)
+
+// defaultReconcileConfiguration returns the default reconcile configuration of a controller, which resyncs
+// with the shared informers and backs off the same as the default controller rate limiter of client-go.
+func defaultReconcileConfiguration() fireflyctrlmgrconfig.ReconcileConfiguration {
+	return fireflyctrlmgrconfig.ReconcileConfiguration{
+		InitialBackoff: metav1.Duration{Duration: 5 * time.Millisecond},
+		MaxBackoff:     metav1.Duration{Duration: 1000 * time.Second},
+	}
+}
+
+// addReconcileFlags adds the flags of the resync period and the error backoff of the named controller
+// to the specified FlagSet.
+func addReconcileFlags(fs *pflag.FlagSet, controller string, cfg *fireflyctrlmgrconfig.ReconcileConfiguration) {
+	fs.DurationVar(&cfg.ResyncPeriod.Duration, controller+"-resync-period", cfg.ResyncPeriod.Duration, fmt.Sprintf("The period at which all %s objects are reconciled even if they're not changed. Zero means the resync period of the shared informers, derived from --min-resync-period", controller))
+	fs.DurationVar(&cfg.InitialBackoff.Duration, controller+"-initial-backoff", cfg.InitialBackoff.Duration, fmt.Sprintf("The delay before a %s object is retried after its first failed reconcile, which is doubled on each consecutive failure", controller))
+	fs.DurationVar(&cfg.MaxBackoff.Duration, controller+"-max-backoff", cfg.MaxBackoff.Duration, fmt.Sprintf("The maximum delay before a %s object is retried after a failed reconcile", controller))
+}
+
+// validateReconcile checks validation of the resync period and the error backoff of the named controller.
+func validateReconcile(controller string, cfg *fireflyctrlmgrconfig.ReconcileConfiguration) []error {
+	var errs []error
+	if cfg.ResyncPeriod.Duration < 0 {
+		errs = append(errs, fmt.Errorf("%s-resync-period must not be negative, got %v", controller, cfg.ResyncPeriod.Duration))
+	}
+	if cfg.InitialBackoff.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("%s-initial-backoff must be greater than 0, got %v", controller, cfg.InitialBackoff.Duration))
+	}
+	if cfg.MaxBackoff.Duration < cfg.InitialBackoff.Duration {
+		errs = append(errs, fmt.Errorf("%s-max-backoff must not be less than %s-initial-backoff, got %v", controller, controller, cfg.MaxBackoff.Duration))
+	}
+	return errs
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+	"github.com/carlory/firefly/pkg/util/kms"
+)
+
+// SecretsEncryptionOptions holds the secrets encryption options.
+type SecretsEncryptionOptions struct {
+	*fireflyctrlmgrconfig.SecretsEncryptionConfiguration
+}
+
+// AddFlags adds flags related to secrets encryption for controller manager to the specified FlagSet.
+func (o *SecretsEncryptionOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.StringVar(&o.KMSProvider, "secrets-encryption-kms-provider", o.KMSProvider, fmt.Sprintf("The KMS provider which encrypts the data encryption keys of the secrets generated by the controllers, one of %s.", strings.Join(kms.Providers(), ", ")))
+	fs.StringVar(&o.KMSEndpoint, "secrets-encryption-kms-endpoint", o.KMSEndpoint, "The endpoint of the KMS, e.g. unix:///var/run/kmsplugin/socket.sock for a Kubernetes KMS v1 plugin. If it is set, the private keys of the etcd CA and the front proxy CA, and the admin and read-only kubeconfigs published for the karmadas are envelope-encrypted with the KMS; fireflyctl get-kubeconfig decrypts the kubeconfigs with --kms-endpoint. The private key of the karmada CA, the certificates and keys of the components such as the etcd client key, the karmada-kubeconfig Secret and the database credentials of the clusterpedias are kept in plain text since the components read them from the mounted secrets and their environment.")
+	fs.DurationVar(&o.KMSTimeout.Duration, "secrets-encryption-kms-timeout", o.KMSTimeout.Duration, "The timeout of the calls to the KMS.")
+}
+
+// ApplyTo fills up secrets encryption config with options.
+func (o *SecretsEncryptionOptions) ApplyTo(cfg *fireflyctrlmgrconfig.SecretsEncryptionConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.KMSProvider = o.KMSProvider
+	cfg.KMSEndpoint = o.KMSEndpoint
+	cfg.KMSTimeout = o.KMSTimeout
+	return nil
+}
+
+// Validate checks validation of SecretsEncryptionOptions.
+func (o *SecretsEncryptionOptions) Validate() []error {
+	if o == nil || o.KMSEndpoint == "" {
+		return nil
+	}
+
+	var errs []error
+	known := false
+	for _, name := range kms.Providers() {
+		known = known || name == o.KMSProvider
+	}
+	if !known {
+		errs = append(errs, fmt.Errorf("secrets-encryption-kms-provider must be one of %s, got %q", strings.Join(kms.Providers(), ", "), o.KMSProvider))
+	}
+	if o.KMSTimeout.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("secrets-encryption-kms-timeout must be positive, got %s", o.KMSTimeout.Duration))
+	}
+	return errs
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+)
+
+// SecretSyncControllerOptions holds the SecretSyncController options.
+type SecretSyncControllerOptions struct {
+	*fireflyctrlmgrconfig.SecretSyncControllerConfiguration
+}
+
+// AddFlags adds flags related to SecretSyncController for controller manager to the specified FlagSet.
+func (o *SecretSyncControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentSecretSyncSyncs, "concurrent-secretsync-syncs", o.ConcurrentSecretSyncSyncs, "The number of secret sync objects that are allowed to sync concurrently. Larger number = more responsive secret syncs, but more CPU (and network) load")
+	addReconcileFlags(fs, "secretsync", &o.Reconcile)
+}
+
+// ApplyTo fills up SecretSyncController config with options.
+func (o *SecretSyncControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.SecretSyncControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentSecretSyncSyncs = o.ConcurrentSecretSyncSyncs
+	cfg.Reconcile = o.Reconcile
+	return nil
+}
+
+// Validate checks validation of SecretSyncControllerOptions.
+func (o *SecretSyncControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentSecretSyncSyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-secretsync-syncs must be greater than 0, got %d", o.ConcurrentSecretSyncSyncs))
+	}
+	errs = append(errs, validateReconcile("secretsync", &o.Reconcile)...)
+	return errs
+}
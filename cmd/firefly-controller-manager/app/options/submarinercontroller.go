@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+)
+
+// SubmarinerControllerOptions holds the SubmarinerController options.
+type SubmarinerControllerOptions struct {
+	*fireflyctrlmgrconfig.SubmarinerControllerConfiguration
+}
+
+// AddFlags adds flags related to SubmarinerController for controller manager to the specified FlagSet.
+func (o *SubmarinerControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentSubmarinerSyncs, "concurrent-submariner-syncs", o.ConcurrentSubmarinerSyncs, "The number of submariner objects that are allowed to sync concurrently. Larger number = more responsive submariners, but more CPU (and network) load")
+	addReconcileFlags(fs, "submariner", &o.Reconcile)
+}
+
+// ApplyTo fills up SubmarinerController config with options.
+func (o *SubmarinerControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.SubmarinerControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentSubmarinerSyncs = o.ConcurrentSubmarinerSyncs
+	cfg.Reconcile = o.Reconcile
+	return nil
+}
+
+// Validate checks validation of SubmarinerControllerOptions.
+func (o *SubmarinerControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentSubmarinerSyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-submariner-syncs must be greater than 0, got %d", o.ConcurrentSubmarinerSyncs))
+	}
+	errs = append(errs, validateReconcile("submariner", &o.Reconcile)...)
+	return errs
+}
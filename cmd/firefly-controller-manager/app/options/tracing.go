@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/semconv"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/component-base/tracing"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+
+	fireflycontrollerconfig "github.com/carlory/firefly/cmd/firefly-controller-manager/app/config"
+)
+
+// TracingOptions holds the OTLP tracing options.
+type TracingOptions struct {
+	// Endpoint is the address of the OTLP collector. Tracing is disabled if it is empty.
+	Endpoint               string
+	SamplingRatePerMillion int32
+}
+
+// AddFlags adds flags related to tracing for controller manager to the specified FlagSet.
+func (o *TracingOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.StringVar(&o.Endpoint, "tracing-endpoint", o.Endpoint, "The address of the OTLP gRPC collector which the spans of the reconciles are exported to, e.g. otel-collector.observability:4317. Tracing is disabled if it is empty.")
+	fs.Int32Var(&o.SamplingRatePerMillion, "tracing-sampling-rate-per-million", o.SamplingRatePerMillion, "The number of reconciles to trace per million. The requests sent outside of reconciles are sampled at the same rate.")
+}
+
+// TracingConfiguration returns the tracing configuration of the options, or nil if tracing is disabled.
+func (o *TracingOptions) TracingConfiguration() *tracingapi.TracingConfiguration {
+	if o == nil || o.Endpoint == "" {
+		return nil
+	}
+	endpoint, rate := o.Endpoint, o.SamplingRatePerMillion
+	return &tracingapi.TracingConfiguration{
+		Endpoint:               &endpoint,
+		SamplingRatePerMillion: &rate,
+	}
+}
+
+// ApplyTo fills up tracing config with options, and wraps the rest config of the
+// controller manager so that the requests of its clients are traced.
+func (o *TracingOptions) ApplyTo(c *fireflycontrollerconfig.Config) error {
+	tracingConfig := o.TracingConfiguration()
+	c.ComponentConfig.Tracing = tracingConfig
+	if tracingConfig == nil {
+		return nil
+	}
+
+	resourceOpts := []resource.Option{
+		resource.WithAttributes(semconv.ServiceNameKey.String(FireflyControllerManagerUserAgent)),
+	}
+	tp, err := tracing.NewProvider(context.Background(), tracingConfig, nil, resourceOpts)
+	if err != nil {
+		return fmt.Errorf("failed to create tracer provider: %v", err)
+	}
+	c.TracerProvider = tp
+	c.Kubeconfig.Wrap(tracing.WrapperFor(tp))
+	return nil
+}
+
+// Validate checks validation of TracingOptions.
+func (o *TracingOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, err := range tracingapi.ValidateTracingConfiguration(o.TracingConfiguration(), nil, field.NewPath("tracing")) {
+		errs = append(errs, err)
+	}
+	return errs
+}
@@ -17,11 +17,13 @@ limitations under the License.
 package config
 
 import (
+	oteltrace "go.opentelemetry.io/otel/trace"
 	apiserver "k8s.io/apiserver/pkg/server"
 	clientset "k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 
+	"github.com/carlory/firefly/pkg/clientbuilder"
 	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/karmada/controller/apis/config"
 )
 
@@ -47,8 +49,17 @@ type Config struct {
 	EstimatorNamespace string
 	KarmadaName        string
 
+	// ClientConnectionOverrides overrides the QPS and Burst of the clients of the given controllers.
+	ClientConnectionOverrides map[string]clientbuilder.ClientConnectionOverride
+
+	// KubeconfigReloaders reload the credentials of the kubeconfig files the clients are built from.
+	KubeconfigReloaders []*clientbuilder.KubeconfigReloader
+
 	EventBroadcaster record.EventBroadcaster
 	EventRecorder    record.EventRecorder
+
+	// TracerProvider creates the spans of the reconciles. It is nil if tracing is disabled.
+	TracerProvider oteltrace.TracerProvider
 }
 
 type completedConfig struct {
@@ -19,15 +19,13 @@ package app
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
-	karmadaversioned "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
-	karmadainformers "github.com/karmada-io/karmada/pkg/generated/informers/externalversions"
 	"github.com/spf13/cobra"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	"go.opentelemetry.io/otel"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/uuid"
@@ -35,15 +33,8 @@ import (
 	"k8s.io/apiserver/pkg/server/healthz"
 	"k8s.io/apiserver/pkg/server/mux"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
-	discovery "k8s.io/client-go/discovery"
-	cacheddiscovery "k8s.io/client-go/discovery/cached"
-	"k8s.io/client-go/dynamic/dynamicinformer"
-	"k8s.io/client-go/informers"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
-	"k8s.io/client-go/metadata"
-	"k8s.io/client-go/metadata/metadatainformer"
 	restclient "k8s.io/client-go/rest"
-	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	cliflag "k8s.io/component-base/cli/flag"
@@ -55,30 +46,31 @@ import (
 	"k8s.io/component-base/version"
 	"k8s.io/component-base/version/verflag"
 	genericcontrollermanager "k8s.io/controller-manager/app"
-	"k8s.io/controller-manager/controller"
 	controllerhealthz "k8s.io/controller-manager/pkg/healthz"
-	"k8s.io/controller-manager/pkg/informerfactory"
 	"k8s.io/controller-manager/pkg/leadermigration"
 	"k8s.io/klog/v2"
 
 	"github.com/carlory/firefly/cmd/firefly-karmada-manager/app/config"
 	"github.com/carlory/firefly/cmd/firefly-karmada-manager/app/options"
 	"github.com/carlory/firefly/pkg/clientbuilder"
-	fireflyversioned "github.com/carlory/firefly/pkg/generated/clientset/versioned"
-	fireflyinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions"
+	"github.com/carlory/firefly/pkg/features"
 	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/karmada/controller/apis/config"
-	karmadafireflyinformers "github.com/carlory/firefly/pkg/karmada/generated/informers/externalversions"
+	fireflyctrlmgrconfigscheme "github.com/carlory/firefly/pkg/karmada/controller/apis/config/scheme"
+	fireflyctrlmgrconfigv1alpha1 "github.com/carlory/firefly/pkg/karmada/controller/apis/config/v1alpha1"
+	"github.com/carlory/firefly/pkg/karmada/controllermanager"
+	"github.com/carlory/firefly/pkg/util/backoff"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	leaderelectionutil "github.com/carlory/firefly/pkg/util/leaderelection"
 )
 
 func init() {
 	utilruntime.Must(logsapi.AddFeatureGates(utilfeature.DefaultMutableFeatureGate))
+	utilruntime.Must(features.AddFeatureGates(utilfeature.DefaultMutableFeatureGate))
 }
 
 const (
-	// ControllerStartJitter is the Jitter used when starting controller managers
-	ControllerStartJitter = 1.0
-	// ConfigzName is the name used for register firefly-controller manager /configz, same with GroupName.
-	ConfigzName = "fireflycontrollermanager.config.firefly.io"
+	// ConfigzName is the name used for register firefly-karmada-manager /configz, same with GroupName.
+	ConfigzName = fireflyctrlmgrconfig.GroupName
 )
 
 // NewControllerManagerCommand creates a *cobra.Command object with default parameters
@@ -114,6 +106,13 @@ controller, and serviceaccounts controller.`,
 			}
 			cliflag.PrintFlags(cmd.Flags())
 
+			if err := s.Complete(cmd.Flags()); err != nil {
+				return err
+			}
+			if err := registerExternalControllers(s.ExternalControllers); err != nil {
+				return err
+			}
+
 			c, err := s.Config(KnownControllers(), ControllersDisabledByDefault.List())
 			if err != nil {
 				return err
@@ -149,10 +148,7 @@ controller, and serviceaccounts controller.`,
 // invoked; this is so that multiple controllers don't get into lock-step and all
 // hammer the apiserver with list requests simultaneously.
 func ResyncPeriod(c *config.CompletedConfig) func() time.Duration {
-	return func() time.Duration {
-		factor := rand.Float64() + 1
-		return time.Duration(float64(c.ComponentConfig.Generic.MinResyncPeriod.Nanoseconds()) * factor)
-	}
+	return controllermanager.ResyncPeriod(c.ComponentConfig.Generic.MinResyncPeriod.Duration)
 }
 
 // Run runs the FireflyControllerManagerOptions.
@@ -168,11 +164,27 @@ func Run(c *config.CompletedConfig, stopCh <-chan struct{}) error {
 	defer c.EventBroadcaster.Shutdown()
 
 	if cfgz, err := configz.New(ConfigzName); err == nil {
-		cfgz.Set(c.ComponentConfig)
+		// the effective configuration is exposed in the format of the --config file.
+		versioned := &fireflyctrlmgrconfigv1alpha1.FireflyKarmadaManagerConfiguration{}
+		if err := fireflyctrlmgrconfigscheme.Scheme.Convert(&c.ComponentConfig, versioned, nil); err != nil {
+			return err
+		}
+		versioned.SetGroupVersionKind(fireflyctrlmgrconfigv1alpha1.SchemeGroupVersion.WithKind("FireflyKarmadaManagerConfiguration"))
+		cfgz.Set(versioned)
 	} else {
 		klog.Errorf("unable to register configz: %v", err)
 	}
 
+	kubeconfigCtx, _ := wait.ContextForChannel(stopCh)
+	for _, reloader := range c.KubeconfigReloaders {
+		go reloader.Run(kubeconfigCtx)
+	}
+
+	// The controllers create the spans of their reconciles by the global tracer provider.
+	if c.TracerProvider != nil {
+		otel.SetTracerProvider(c.TracerProvider)
+	}
+
 	// Setup any healthz checks we will want to use.
 	var checks []healthz.HealthChecker
 	var electionChecker *leaderelection.HealthzAdaptor
@@ -182,11 +194,36 @@ func Run(c *config.CompletedConfig, stopCh <-chan struct{}) error {
 	}
 	healthzHandler := controllerhealthz.NewMutableHealthzHandler(checks...)
 
+	// controllersStarting is closed when this instance starts the controllers, and controllersReady
+	// is closed once they have been started and the caches of the shared informers have synced.
+	// The readyz check fails in between. A standby instance waiting for the leader lease is ready,
+	// otherwise a rolling update would never complete.
+	controllersStarting := make(chan struct{})
+	controllersReady := make(chan struct{})
+	var controllersStartingOnce, controllersReadyOnce sync.Once
+	if !c.ComponentConfig.Generic.LeaderElection.LeaderElect {
+		close(controllersStarting)
+	}
+	readyzCheck := healthz.NamedCheck("controllers", func(_ *http.Request) error {
+		select {
+		case <-controllersReady:
+			return nil
+		default:
+		}
+		select {
+		case <-controllersStarting:
+			return fmt.Errorf("controllers are not started or the informer caches are not synced yet")
+		default:
+			return nil
+		}
+	})
+
 	// Start the controller manager HTTP server
 	// unsecuredMux is the handler for these controller *after* authn/authz filters have been applied
 	var unsecuredMux *mux.PathRecorderMux
 	if c.SecureServing != nil {
 		unsecuredMux = genericcontrollermanager.NewBaseHandler(&c.ComponentConfig.Generic.Debugging, healthzHandler)
+		healthz.InstallReadyzHandler(unsecuredMux, readyzCheck)
 		handler := genericcontrollermanager.BuildHandlerChain(unsecuredMux, &c.Authorization, &c.Authentication)
 		// TODO: handle stoppedCh and listenerStoppedCh returned by c.SecureServing.Serve
 		if _, _, err := c.SecureServing.Serve(handler, 0, stopCh); err != nil {
@@ -194,27 +231,42 @@ func Run(c *config.CompletedConfig, stopCh <-chan struct{}) error {
 		}
 	}
 
-	karmadaClientBuilder, fireflyKubeClientBuilder := createClientBuilders(c)
+	rootKarmadaClientBuilder, karmadaClientBuilder, fireflyKubeClientBuilder := createClientBuilders(c)
+
+	preflightCtx, _ := wait.ContextForChannel(stopCh)
+	retry := c.ComponentConfig.StartupRetry
+	if err := backoff.Retry(preflightCtx, "running preflight checks", retry.Timeout.Duration, retry.InitialBackoff.Duration, func() error {
+		return RunPreflightChecks(preflightCtx, c, rootKarmadaClientBuilder, fireflyKubeClientBuilder)
+	}); err != nil {
+		return err
+	}
+	EnsureBulkClientsFlowSchema(preflightCtx, c, rootKarmadaClientBuilder)
 
 	run := func(ctx context.Context, initializersFunc ControllerInitializersFunc) {
-		controllerContext, err := CreateControllerContext(c, karmadaClientBuilder, fireflyKubeClientBuilder, ctx.Done())
+		controllersStartingOnce.Do(func() { close(controllersStarting) })
+
+		controllerContext, err := CreateControllerContext(c, rootKarmadaClientBuilder, karmadaClientBuilder, fireflyKubeClientBuilder, ctx.Done())
+		if err != nil && backoff.IsTransient(err) {
+			klog.Fatalf("error building controller context, the apiservers are still unreachable after %s: %v", c.ComponentConfig.StartupRetry.Timeout.Duration, err)
+		}
 		if err != nil {
 			klog.Fatalf("error building controller context: %v", err)
 		}
+		if c.ComponentConfig.DryRun {
+			klog.InfoS("Controllers are running in dry-run mode, nothing is mutated")
+			ctx = dryrun.WithDryRun(ctx)
+		}
 		controllerInitializers := initializersFunc()
-		if err := StartControllers(ctx, controllerContext, controllerInitializers, unsecuredMux, healthzHandler); err != nil {
+		if err := controllermanager.StartControllers(ctx, controllerContext, controllerInitializers, controllerDependencies, unsecuredMux, healthzHandler); err != nil {
 			klog.Fatalf("error starting controllers: %v", err)
 		}
 
-		controllerContext.KarmadaDynamicInformerFactory.Start(stopCh)
-		controllerContext.KarmadaKubeInformerFactory.Start(stopCh)
-		controllerContext.KarmadaInformerFactory.Start(stopCh)
-		controllerContext.KarmadaFireflyInformerFactory.Start(stopCh)
-		controllerContext.FireflyDynamicInformerFactory.Start(stopCh)
-		controllerContext.FireflyKubeInformerFactory.Start(stopCh)
-		controllerContext.FireflyInformerFactory.Start(stopCh)
-		controllerContext.ObjectOrMetadataInformerFactory.Start(stopCh)
-		close(controllerContext.InformersStarted)
+		controllerContext.StartInformers(stopCh)
+		go controllerContext.MemberClusterCache.Run(ctx)
+
+		if controllerContext.WaitForCacheSync(ctx.Done()) {
+			controllersReadyOnce.Do(func() { close(controllersReady) })
+		}
 
 		<-ctx.Done()
 	}
@@ -222,7 +274,9 @@ func Run(c *config.CompletedConfig, stopCh <-chan struct{}) error {
 	// No leader election, run directly
 	if !c.ComponentConfig.Generic.LeaderElection.LeaderElect {
 		ctx, _ := wait.ContextForChannel(stopCh)
-		run(ctx, NewControllerInitializers)
+		run(ctx, filterInitializersFunc(NewControllerInitializers, func(name string) bool {
+			return isControllerGroupRun(c, controllerGroupOf(c, name))
+		}))
 		return nil
 	}
 
@@ -233,58 +287,92 @@ func Run(c *config.CompletedConfig, stopCh <-chan struct{}) error {
 
 	// add a uniquifier so that two processes on the same host don't accidentally both become active
 	id = id + "_" + string(uuid.NewUUID())
+	// name the deployment of the holder, so that the holders of several deployments can be told apart
+	if suffix := c.ComponentConfig.LeaderElectionIdentitySuffix; suffix != "" {
+		id = id + "_" + suffix
+	}
+
+	// The controllers in none of the controller groups are run under the main lock.
+	if isControllerGroupRun(c, options.DefaultControllerGroup) {
+		inDefaultGroup := func(name string) bool {
+			return controllerGroupOf(c, name) == options.DefaultControllerGroup
+		}
 
-	// leaderMigrator will be non-nil if and only if Leader Migration is enabled.
-	var leaderMigrator *leadermigration.LeaderMigrator = nil
+		// leaderMigrator will be non-nil if and only if Leader Migration is enabled.
+		var leaderMigrator *leadermigration.LeaderMigrator = nil
 
-	// If leader migration is enabled, create the LeaderMigrator and prepare for migration
-	if leadermigration.Enabled(&c.ComponentConfig.Generic) {
-		klog.Infof("starting leader migration")
+		// If leader migration is enabled, create the LeaderMigrator and prepare for migration
+		if leadermigration.Enabled(&c.ComponentConfig.Generic) {
+			klog.Infof("starting leader migration")
+
+			leaderMigrator = leadermigration.NewLeaderMigrator(&c.ComponentConfig.Generic.LeaderMigration, "firefly-controller-manager")
+		}
+
+		// Start the main lock
+		go leaderElectAndRun(c, id, electionChecker,
+			c.ComponentConfig.Generic.LeaderElection.ResourceLock,
+			c.ComponentConfig.Generic.LeaderElection.ResourceName,
+			leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(ctx context.Context) {
+					initializersFunc := NewControllerInitializers
+					if leaderMigrator != nil {
+						// If leader migration is enabled, we should start only non-migrated controllers
+						//  for the main lock.
+						initializersFunc = createInitializersFunc(leaderMigrator.FilterFunc, leadermigration.ControllerNonMigrated)
+						klog.Info("leader migration: starting main controllers.")
+					}
+					run(ctx, filterInitializersFunc(initializersFunc, inDefaultGroup))
+				},
+				OnStoppedLeading: func() {
+					klog.ErrorS(nil, "leaderelection lost")
+					klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+				},
+			})
 
-		leaderMigrator = leadermigration.NewLeaderMigrator(&c.ComponentConfig.Generic.LeaderMigration, "firefly-controller-manager")
+		// If Leader Migration is enabled, proceed to attempt the migration lock.
+		if leaderMigrator != nil {
+			// Wait for Service Account Token Controller to start before acquiring the migration lock.
+			// At this point, the main lock must have already been acquired, or the KCM process already exited.
+			// We wait for the main lock before acquiring the migration lock to prevent the situation
+			//  where KCM instance A holds the main lock while KCM instance B holds the migration lock.
+			<-leaderMigrator.MigrationReady
+
+			// Start the migration lock.
+			go leaderElectAndRun(c, id, electionChecker,
+				c.ComponentConfig.Generic.LeaderMigration.ResourceLock,
+				c.ComponentConfig.Generic.LeaderMigration.LeaderName,
+				leaderelection.LeaderCallbacks{
+					OnStartedLeading: func(ctx context.Context) {
+						klog.Info("leader migration: starting migrated controllers.")
+						// DO NOT start saTokenController under migration lock
+						run(ctx, filterInitializersFunc(createInitializersFunc(leaderMigrator.FilterFunc, leadermigration.ControllerMigrated), inDefaultGroup))
+					},
+					OnStoppedLeading: func() {
+						klog.ErrorS(nil, "migration leaderelection lost")
+						klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+					},
+				})
+		}
 	}
 
-	// Start the main lock
-	go leaderElectAndRun(c, id, electionChecker,
-		c.ComponentConfig.Generic.LeaderElection.ResourceLock,
-		c.ComponentConfig.Generic.LeaderElection.ResourceName,
-		leaderelection.LeaderCallbacks{
-			OnStartedLeading: func(ctx context.Context) {
-				initializersFunc := NewControllerInitializers
-				if leaderMigrator != nil {
-					// If leader migration is enabled, we should start only non-migrated controllers
-					//  for the main lock.
-					initializersFunc = createInitializersFunc(leaderMigrator.FilterFunc, leadermigration.ControllerNonMigrated)
-					klog.Info("leader migration: starting main controllers.")
-				}
-				run(ctx, initializersFunc)
-			},
-			OnStoppedLeading: func() {
-				klog.ErrorS(nil, "leaderelection lost")
-				klog.FlushAndExit(klog.ExitFlushTimeout, 1)
-			},
-		})
-
-	// If Leader Migration is enabled, proceed to attempt the migration lock.
-	if leaderMigrator != nil {
-		// Wait for Service Account Token Controller to start before acquiring the migration lock.
-		// At this point, the main lock must have already been acquired, or the KCM process already exited.
-		// We wait for the main lock before acquiring the migration lock to prevent the situation
-		//  where KCM instance A holds the main lock while KCM instance B holds the migration lock.
-		<-leaderMigrator.MigrationReady
-
-		// Start the migration lock.
+	// Each controller group is run under its own lock, so the groups may be led by different replicas.
+	for _, group := range c.ComponentConfig.ControllerGroups {
+		if !isControllerGroupRun(c, group.Name) {
+			continue
+		}
+		groupName := group.Name
 		go leaderElectAndRun(c, id, electionChecker,
-			c.ComponentConfig.Generic.LeaderMigration.ResourceLock,
-			c.ComponentConfig.Generic.LeaderMigration.LeaderName,
+			c.ComponentConfig.Generic.LeaderElection.ResourceLock,
+			c.ComponentConfig.Generic.LeaderElection.ResourceName+"-"+groupName,
 			leaderelection.LeaderCallbacks{
 				OnStartedLeading: func(ctx context.Context) {
-					klog.Info("leader migration: starting migrated controllers.")
-					// DO NOT start saTokenController under migration lock
-					run(ctx, createInitializersFunc(leaderMigrator.FilterFunc, leadermigration.ControllerMigrated))
+					klog.InfoS("Starting the controllers of the controller group", "group", groupName)
+					run(ctx, filterInitializersFunc(NewControllerInitializers, func(name string) bool {
+						return controllerGroupOf(c, name) == groupName
+					}))
 				},
 				OnStoppedLeading: func() {
-					klog.ErrorS(nil, "migration leaderelection lost")
+					klog.ErrorS(nil, "leaderelection of the controller group lost", "group", groupName)
 					klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 				},
 			})
@@ -295,82 +383,10 @@ func Run(c *config.CompletedConfig, stopCh <-chan struct{}) error {
 }
 
 // ControllerContext defines the context object for controller
-type ControllerContext struct {
-	// KarmadaClientBuilder will provide a client for this controller to use
-	KarmadaClientBuilder clientbuilder.KarmadaControllerClientBuilder
-
-	// FireflyClientBuilder will provide a client for this controller to use
-	FireflyClientBuilder clientbuilder.FireflyControllerClientBuilder
-
-	// KarmadaKubeInformerFactory gives access to dynamic informers for the controller.
-	KarmadaDynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory
-
-	// KarmadaKubeInformerFactory gives access to kubernetes informers for the controller.
-	KarmadaKubeInformerFactory informers.SharedInformerFactory
-
-	// KarmadaFireflyInformerFactory gives access to karmada's firelfy informers for the controller.
-	KarmadaFireflyInformerFactory karmadafireflyinformers.SharedInformerFactory
-
-	// KarmadaInformerFactory gives access to firefly informers for the controller.
-	KarmadaInformerFactory karmadainformers.SharedInformerFactory
-
-	// FireflyDynamicInformerFactory gives access to dynamic informers for the controller.
-	FireflyDynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory
-
-	// FireflyKubeInformerFactory gives access to firefly informers for the controller.
-	FireflyKubeInformerFactory informers.SharedInformerFactory
-
-	// FireflyInformerFactory gives access to firefly informers for the controller.
-	FireflyInformerFactory fireflyinformers.SharedInformerFactory
-
-	// EstimatorNamespace is the namespace of scheduler-estimator
-	EstimatorNamespace string
-	// KarmadaName is the name of a firefly karmada object
-	KarmadaName string
-
-	// ObjectOrMetadataInformerFactory gives access to informers for typed resources
-	// and dynamic resources by their metadata. All generic controllers currently use
-	// object metadata - if a future controller needs access to the full object this
-	// would become GenericInformerFactory and take a dynamic client.
-	ObjectOrMetadataInformerFactory informerfactory.InformerFactory
+type ControllerContext = controllermanager.ControllerContext
 
-	// ComponentConfig provides access to init options for a given controller
-	ComponentConfig fireflyctrlmgrconfig.FireflyKarmadaManagerConfiguration
-
-	// DeferredDiscoveryRESTMapper is a RESTMapper that will defer
-	// initialization of the RESTMapper until the first mapping is
-	// requested.
-	RESTMapper *restmapper.DeferredDiscoveryRESTMapper
-
-	// AvailableResources is a map listing currently available resources
-	AvailableResources map[schema.GroupVersionResource]bool
-
-	// HostClusterAvailableResources is a map listing currently available resources on the host cluster.
-	HostClusterAvailableResources map[schema.GroupVersionResource]bool
-
-	// InformersStarted is closed after all of the controllers have been initialized and are running.  After this point it is safe,
-	// for an individual controller to start the shared informers. Before it is closed, they should not.
-	InformersStarted chan struct{}
-
-	// ResyncPeriod generates a duration each time it is invoked; this is so that
-	// multiple controllers don't get into lock-step and all hammer the apiserver
-	// with list requests simultaneously.
-	ResyncPeriod func() time.Duration
-}
-
-// IsControllerEnabled checks if the context's controllers enabled or not
-func (c ControllerContext) IsControllerEnabled(name string) bool {
-	return genericcontrollermanager.IsControllerEnabled(name, ControllersDisabledByDefault, c.ComponentConfig.Generic.Controllers)
-}
-
-// InitFunc is used to launch a particular controller. It returns a controller
-// that can optionally implement other interfaces so that the controller manager
-// can support the requested features.
-// The returned controller may be nil, which will be considered an anonymous controller
-// that requests no additional features from the controller manager.
-// Any error returned will cause the controller process to `Fatal`
-// The bool indicates whether the controller was enabled.
-type InitFunc func(ctx context.Context, controllerCtx ControllerContext) (controller controller.Interface, enabled bool, err error)
+// InitFunc is used to launch a particular controller.
+type InitFunc = controllermanager.InitFunc
 
 // ControllerInitializersFunc is used to create a collection of initializers.
 type ControllerInitializersFunc func() (initializers map[string]InitFunc)
@@ -382,213 +398,81 @@ func KnownControllers() []string {
 }
 
 // ControllersDisabledByDefault is the set of controllers which is disabled by default
-var ControllersDisabledByDefault = sets.NewString()
+var ControllersDisabledByDefault = controllermanager.ControllersDisabledByDefault
 
 // NewControllerInitializers is a public map of named controller groups (you can start more than one in an init func)
 // paired to their InitFunc.  This allows for structured downstream composition and subdivision.
+// The controllers added by RegisterController are included along with the built-in ones.
 func NewControllerInitializers() map[string]InitFunc {
-	controllers := map[string]InitFunc{}
-	controllers["estimator"] = startEstimatorController
-	controllers["node"] = startNodeController
-	controllers["foo"] = startFooController
-	controllers["kubean"] = startKubeanController
+	controllers := newBuiltinControllerInitializers()
+	for name, fn := range registeredControllerInitializers() {
+		controllers[name] = fn
+	}
 	return controllers
 }
 
-// GetKarmadaAvailableResources gets the map which contains all available resources of the karmada-apiserver
-// TODO: In general, any controller checking this needs to be dynamic so
-// users don't have to restart their controller manager if they change the apiserver.
-// Until we get there, the structure here needs to be exposed for the construction of a proper ControllerContext.
-func GetKarmadaAvailableResources(clientBuilder clientbuilder.KarmadaControllerClientBuilder) (map[schema.GroupVersionResource]bool, error) {
-	client := clientBuilder.ClientOrDie("controller-discovery")
-	discoveryClient := client.Discovery()
-	return GetAvailableResources(discoveryClient)
+// controllerDependencies are the dependencies of the built-in controllers, which are started once the
+// controllers they depend on are ready.
+var controllerDependencies = controllermanager.Dependencies{
+	// the estimators are deployed once the nodes of the member clusters are mirrored into karmada.
+	"estimator": {"node"},
 }
 
-// GetHostClusterAvailableResources gets the map which contains all available resources of the kube-apiserver
-// on the host cluster.
-// TODO: In general, any controller checking this needs to be dynamic so
-// users don't have to restart their controller manager if they change the apiserver.
-// Until we get there, the structure here needs to be exposed for the construction of a proper ControllerContext.
-func GetHostClusterAvailableResources(clientBuilder clientbuilder.FireflyControllerClientBuilder) (map[schema.GroupVersionResource]bool, error) {
-	client := clientBuilder.ClientOrDie("controller-discovery")
-	discoveryClient := client.Discovery()
-	return GetAvailableResources(discoveryClient)
-}
-
-// GetAvailableResources gets the map which contains all available resources of the apiserver
-// TODO: In general, any controller checking this needs to be dynamic so
-// users don't have to restart their controller manager if they change the apiserver.
-// Until we get there, the structure here needs to be exposed for the construction of a proper ControllerContext.
-func GetAvailableResources(client discovery.DiscoveryInterface) (map[schema.GroupVersionResource]bool, error) {
-	_, resourceMap, err := client.ServerGroupsAndResources()
-	if err != nil {
-		utilruntime.HandleError(fmt.Errorf("unable to get all supported resources from server: %v", err))
-	}
-	if len(resourceMap) == 0 {
-		return nil, fmt.Errorf("unable to get any supported resources from server")
-	}
-
-	allResources := map[schema.GroupVersionResource]bool{}
-	for _, apiResourceList := range resourceMap {
-		version, err := schema.ParseGroupVersion(apiResourceList.GroupVersion)
-		if err != nil {
-			return nil, err
-		}
-		for _, apiResource := range apiResourceList.APIResources {
-			allResources[version.WithResource(apiResource.Name)] = true
-		}
-	}
-
-	return allResources, nil
+// newBuiltinControllerInitializers returns the controllers shipped with the firefly-karmada-manager.
+func newBuiltinControllerInitializers() map[string]InitFunc {
+	controllers := map[string]InitFunc{}
+	controllers["estimator"] = startEstimatorController
+	controllers["node"] = startNodeController
+	controllers["kubean"] = startKubeanController
+	controllers["pediacluster"] = startPediaClusterController
+	controllers["registry"] = startRegistryController
+	return controllers
 }
 
 // CreateControllerContext creates a context struct containing references to resources needed by the
-// controllers such as the cloud provider and clientBuilder. rootClientBuilder is only used for
-// the shared-informers client and token controller.
-func CreateControllerContext(s *config.CompletedConfig, karmadaClientBuilder clientbuilder.KarmadaControllerClientBuilder, fireflyKubeClientBuilder clientbuilder.FireflyControllerClientBuilder, stop <-chan struct{}) (ControllerContext, error) {
-	karmadaKubeClient := karmadaClientBuilder.ClientOrDie("karmada-kube-shared-informers")
-	karmadaKubeSharedInformers := informers.NewSharedInformerFactory(karmadaKubeClient, ResyncPeriod(s)())
-
-	karmadaDynamicClient := karmadaClientBuilder.DynamicClientOrDie("karmada-dynamic-shared-informers")
-	karmadaDynamicSharedInformers := dynamicinformer.NewDynamicSharedInformerFactory(karmadaDynamicClient, ResyncPeriod(s)())
-
-	karmadaFireflyClient := karmadaClientBuilder.KarmadaFireflyClientOrDie("karmada-firefly-shared-informers")
-	karmadaFireflySharedInformers := karmadafireflyinformers.NewSharedInformerFactory(karmadaFireflyClient, ResyncPeriod(s)())
-
-	clientConfig := karmadaClientBuilder.ConfigOrDie("karmada-shared-informers")
-	karmadaClient := karmadaversioned.NewForConfigOrDie(clientConfig)
-	karmadaSharedInformers := karmadainformers.NewSharedInformerFactory(karmadaClient, ResyncPeriod(s)())
-
-	metadataClient := metadata.NewForConfigOrDie(karmadaClientBuilder.ConfigOrDie("firefly-metadata-informers"))
-	metadataInformers := metadatainformer.NewSharedInformerFactory(metadataClient, ResyncPeriod(s)())
-
-	// If apiserver is not running we should wait for some time and fail only then. This is particularly
-	// important when we start apiserver and controller manager at the same time.
-	if err := genericcontrollermanager.WaitForAPIServer(karmadaKubeClient, 10*time.Second); err != nil {
-		return ControllerContext{}, fmt.Errorf("failed to wait for apiserver being healthy: %v", err)
-	}
-
-	fireflyKubeClient := fireflyKubeClientBuilder.ClientOrDie("firefly-kube-shared-informers")
-	fireflyKubeSharedInformers := informers.NewSharedInformerFactory(fireflyKubeClient, ResyncPeriod(s)())
-
-	fireflyDynamicClient := fireflyKubeClientBuilder.DynamicClientOrDie("firefly-dynamic-shared-informers")
-	fireflyDynamicSharedInformers := dynamicinformer.NewDynamicSharedInformerFactory(fireflyDynamicClient, ResyncPeriod(s)())
-
-	fireflyClientConfig := fireflyKubeClientBuilder.ConfigOrDie("firefly-shared-informers")
-	fireflyClient := fireflyversioned.NewForConfigOrDie(fireflyClientConfig)
-	fireflySharedInformers := fireflyinformers.NewFilteredSharedInformerFactory(fireflyClient, ResyncPeriod(s)(), s.EstimatorNamespace, nil)
-
-	// If apiserver is not running we should wait for some time and fail only then. This is particularly
-	// important when we start apiserver and controller manager at the same time.
-	if err := genericcontrollermanager.WaitForAPIServer(fireflyKubeClient, 10*time.Second); err != nil {
-		return ControllerContext{}, fmt.Errorf("failed to wait for apiserver being healthy: %v", err)
-	}
-
-	// Use a discovery client capable of being refreshed.
-	discoveryClient := karmadaClientBuilder.DiscoveryClientOrDie("firelfy-controller-discovery")
-	cachedClient := cacheddiscovery.NewMemCacheClient(discoveryClient)
-	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedClient)
-	go wait.Until(func() {
-		restMapper.Reset()
-	}, 30*time.Second, stop)
-
-	availableResources, err := GetKarmadaAvailableResources(karmadaClientBuilder)
-	if err != nil {
-		return ControllerContext{}, err
-	}
-
-	hostClusterAvailableResources, err := GetHostClusterAvailableResources(fireflyKubeClientBuilder)
-	if err != nil {
-		return ControllerContext{}, err
-	}
-
-	ctx := ControllerContext{
-		KarmadaClientBuilder:            karmadaClientBuilder,
-		FireflyClientBuilder:            fireflyKubeClientBuilder,
-		KarmadaDynamicInformerFactory:   karmadaDynamicSharedInformers,
-		KarmadaKubeInformerFactory:      karmadaKubeSharedInformers,
-		KarmadaFireflyInformerFactory:   karmadaFireflySharedInformers,
-		KarmadaInformerFactory:          karmadaSharedInformers,
-		FireflyDynamicInformerFactory:   fireflyDynamicSharedInformers,
-		FireflyKubeInformerFactory:      fireflyKubeSharedInformers,
-		FireflyInformerFactory:          fireflySharedInformers,
-		ObjectOrMetadataInformerFactory: informerfactory.NewInformerFactory(karmadaKubeSharedInformers, metadataInformers),
-		ComponentConfig:                 s.ComponentConfig,
-		EstimatorNamespace:              s.EstimatorNamespace,
-		KarmadaName:                     s.KarmadaName,
-		RESTMapper:                      restMapper,
-		AvailableResources:              availableResources,
-		HostClusterAvailableResources:   hostClusterAvailableResources,
-		InformersStarted:                make(chan struct{}),
-		ResyncPeriod:                    ResyncPeriod(s),
-	}
-	return ctx, nil
-}
-
-// StartControllers starts a set of controllers with a specified ControllerContext
-func StartControllers(ctx context.Context, controllerCtx ControllerContext, controllers map[string]InitFunc,
-	unsecuredMux *mux.PathRecorderMux, healthzHandler *controllerhealthz.MutableHealthzHandler) error {
-	var controllerChecks []healthz.HealthChecker
-
-	for controllerName, initFn := range controllers {
-		if !controllerCtx.IsControllerEnabled(controllerName) {
-			klog.Warningf("%q is disabled", controllerName)
-			continue
-		}
-
-		time.Sleep(wait.Jitter(controllerCtx.ComponentConfig.Generic.ControllerStartInterval.Duration, ControllerStartJitter))
-
-		klog.V(1).Infof("Starting %q", controllerName)
-		ctrl, started, err := initFn(ctx, controllerCtx)
-		if err != nil {
-			klog.Errorf("Error starting %q", controllerName)
-			return err
-		}
-		if !started {
-			klog.Warningf("Skipping %q", controllerName)
-			continue
-		}
-		check := controllerhealthz.NamedPingChecker(controllerName)
-		if ctrl != nil {
-			// check if the controller supports and requests a debugHandler
-			// and it needs the unsecuredMux to mount the handler onto.
-			if debuggable, ok := ctrl.(controller.Debuggable); ok && unsecuredMux != nil {
-				if debugHandler := debuggable.DebuggingHandler(); debugHandler != nil {
-					basePath := "/debug/controllers/" + controllerName
-					unsecuredMux.UnlistedHandle(basePath, http.StripPrefix(basePath, debugHandler))
-					unsecuredMux.UnlistedHandlePrefix(basePath+"/", http.StripPrefix(basePath, debugHandler))
-				}
-			}
-			if healthCheckable, ok := ctrl.(controller.HealthCheckable); ok {
-				if realCheck := healthCheckable.HealthChecker(); realCheck != nil {
-					check = controllerhealthz.NamedHealthChecker(controllerName, realCheck)
-				}
-			}
-		}
-		controllerChecks = append(controllerChecks, check)
-
-		klog.Infof("Started %q", controllerName)
-	}
-
-	healthzHandler.AddHealthChecker(controllerChecks...)
-	return nil
+// controllers such as the client builders. rootKarmadaClientBuilder is only used for the shared-informers
+// and discovery clients of karmada. It's retried with a backoff according to StartupRetry while either
+// apiserver is unreachable.
+func CreateControllerContext(s *config.CompletedConfig, rootKarmadaClientBuilder, karmadaClientBuilder clientbuilder.KarmadaControllerClientBuilder, fireflyKubeClientBuilder clientbuilder.FireflyControllerClientBuilder, stop <-chan struct{}) (ControllerContext, error) {
+	ctx, cancel := wait.ContextForChannel(stop)
+	defer cancel()
+
+	var controllerContext ControllerContext
+	retry := s.ComponentConfig.StartupRetry
+	err := backoff.Retry(ctx, "building controller context", retry.Timeout.Duration, retry.InitialBackoff.Duration, func() (err error) {
+		controllerContext, err = controllermanager.NewControllerContext(controllermanager.ContextOptions{
+			ComponentConfig:          s.ComponentConfig,
+			RootKarmadaClientBuilder: rootKarmadaClientBuilder,
+			KarmadaClientBuilder:     karmadaClientBuilder,
+			FireflyClientBuilder:     fireflyKubeClientBuilder,
+			EstimatorNamespace:       s.EstimatorNamespace,
+			KarmadaName:              s.KarmadaName,
+		}, stop)
+		return err
+	})
+	return controllerContext, err
 }
 
-// createClientBuilders creates karmadaClientBuilder and fireflyKubeClientBuilder from the given configuration
-func createClientBuilders(c *config.CompletedConfig) (karmadaClientBuilder clientbuilder.KarmadaControllerClientBuilder, fireflyKubeClientBuilder clientbuilder.FireflyControllerClientBuilder) {
-	karmadaClientBuilder = clientbuilder.NewSimpleKarmadaControllerClientBuilder(c.KarmadaKubeconfig)
-
-	fireflyKubeClientBuilder = clientbuilder.NewSimpleFireflyControllerClientBuilder(c.FireflyKubeconfig)
-	return
+// createClientBuilders creates rootKarmadaClientBuilder, karmadaClientBuilder and fireflyKubeClientBuilder from the given configuration
+func createClientBuilders(c *config.CompletedConfig) (rootKarmadaClientBuilder, karmadaClientBuilder clientbuilder.KarmadaControllerClientBuilder, fireflyKubeClientBuilder clientbuilder.FireflyControllerClientBuilder) {
+	return controllermanager.NewClientBuilders(controllermanager.ClientBuilderOptions{
+		ComponentConfig:           c.ComponentConfig,
+		KarmadaKubeconfig:         c.KarmadaKubeconfig,
+		FireflyKubeconfig:         c.FireflyKubeconfig,
+		KarmadaCoreClient:         c.KarmadaKubeClient.CoreV1(),
+		UserAgent:                 options.FireflyKarmadaManagerUserAgent,
+		ClientConnectionOverrides: c.ClientConnectionOverrides,
+		TracerProvider:            c.TracerProvider,
+	})
 }
 
 // leaderElectAndRun runs the leader election, and runs the callbacks once the leader lease is acquired.
 // TODO: extract this function into staging/controller-manager
 func leaderElectAndRun(c *config.CompletedConfig, lockIdentity string, electionChecker *leaderelection.HealthzAdaptor, resourceLock string, leaseName string, callbacks leaderelection.LeaderCallbacks) {
-	rl, err := resourcelock.NewFromKubeconfig(resourceLock,
+	rl, err := leaderelectionutil.NewFromKubeconfig(resourceLock,
 		c.ComponentConfig.Generic.LeaderElection.ResourceNamespace,
 		leaseName,
+		c.ComponentConfig.LeaderElectionLabels,
 		resourcelock.ResourceLockConfig{
 			Identity:      lockIdentity,
 			EventRecorder: c.EventRecorder,
@@ -612,6 +496,42 @@ func leaderElectAndRun(c *config.CompletedConfig, lockIdentity string, electionC
 	panic("unreachable")
 }
 
+// controllerGroupOf returns the name of the controller group of the controller.
+func controllerGroupOf(c *config.CompletedConfig, controller string) string {
+	for _, group := range c.ComponentConfig.ControllerGroups {
+		for _, name := range group.Controllers {
+			if name == controller {
+				return group.Name
+			}
+		}
+	}
+	return options.DefaultControllerGroup
+}
+
+// isControllerGroupRun returns whether the controller group is run by this replica.
+func isControllerGroupRun(c *config.CompletedConfig, group string) bool {
+	for _, name := range c.ComponentConfig.LeaderElectControllerGroups {
+		if name == "*" || name == group {
+			return true
+		}
+	}
+	return false
+}
+
+// filterInitializersFunc creates a initializersFunc that returns the initializers of initializersFunc
+// whose controllers are accepted by filter.
+func filterInitializersFunc(initializersFunc ControllerInitializersFunc, filter func(name string) bool) ControllerInitializersFunc {
+	return func() map[string]InitFunc {
+		initializers := make(map[string]InitFunc)
+		for name, initFn := range initializersFunc() {
+			if filter(name) {
+				initializers[name] = initFn
+			}
+		}
+		return initializers
+	}
+}
+
 // createInitializersFunc creates a initializersFunc that returns all initializer
 //  with expected as the result after filtering through filterFunc.
 func createInitializersFunc(filterFunc leadermigration.FilterFunc, expected leadermigration.FilterResult) ControllerInitializersFunc {
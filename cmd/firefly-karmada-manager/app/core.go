@@ -19,15 +19,19 @@ package app
 import (
 	"context"
 	"fmt"
+	"net/http"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/dynamic"
 	"k8s.io/controller-manager/controller"
 
 	"github.com/carlory/firefly/pkg/karmada/controller/estimator"
-	"github.com/carlory/firefly/pkg/karmada/controller/foo"
 	"github.com/carlory/firefly/pkg/karmada/controller/kubean"
 	"github.com/carlory/firefly/pkg/karmada/controller/node"
+	"github.com/carlory/firefly/pkg/karmada/controller/pediacluster"
+	"github.com/carlory/firefly/pkg/karmada/controller/registry"
+	"github.com/carlory/firefly/pkg/util/debug"
+	informerutil "github.com/carlory/firefly/pkg/util/informer"
 )
 
 func startEstimatorController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
@@ -35,56 +39,118 @@ func startEstimatorController(ctx context.Context, controllerContext ControllerC
 		return nil, false, nil
 	}
 
+	clusterInformer := controllerContext.KarmadaInformerFactory.Cluster().V1alpha1().Clusters()
+	karmadaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas()
+	// the estimator controller only cares about the existence of the estimator deployments of the karmada.
+	deploymentInformer := controllerContext.FireflyKubeFilteredFactories.MetadataForController("estimator",
+		controllerContext.EstimatorNamespace,
+		func(options *metav1.ListOptions) {
+			options.LabelSelector = estimator.EstimatorSelector(controllerContext.KarmadaName).String()
+		},
+	).ForResource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"})
+	if err := informerutil.SetTransform(clusterInformer, karmadaInformer, deploymentInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the estimator controller informers: %v", err)
+	}
+
 	ctrl, err := estimator.NewEstimatorController(
 		controllerContext.KarmadaClientBuilder.ClientOrDie("firefly-estimator-controller"),
 		controllerContext.KarmadaClientBuilder.KarmadaClientOrDie("firefly-estimator-controller"),
-		controllerContext.KarmadaInformerFactory.Cluster().V1alpha1().Clusters(),
+		clusterInformer,
 		controllerContext.EstimatorNamespace,
 		controllerContext.KarmadaName,
 		controllerContext.FireflyClientBuilder.ClientOrDie("firefly-estimator-controller"),
-		controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas(),
+		controllerContext.FireflyClientBuilder.FireflyClientOrDie("firefly-estimator-controller"),
+		karmadaInformer,
+		deploymentInformer,
+		controllerContext.Expectations.For("estimator"),
+		controllerContext.ObjectResolver,
 	)
 	if err != nil {
 		return nil, true, fmt.Errorf("failed to start the estimator controller: %v", err)
 	}
-	go ctrl.Run(ctx, 1)
-	return nil, true, nil
+	go ctrl.Run(ctx, int(controllerContext.ComponentConfig.EstimatorController.ConcurrentEstimatorSyncs))
+	return ctrl, true, nil
 }
 
-func startNodeController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
-	ctrl, err := node.NewNodeController(
-		controllerContext.KarmadaClientBuilder.ClientOrDie("firefly-node-controller"),
-		controllerContext.FireflyKubeInformerFactory.Core().V1().Nodes(),
-		controllerContext.KarmadaKubeInformerFactory.Core().V1().Nodes(),
+func startPediaClusterController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	if !controllerContext.HostClusterAvailableResources[schema.GroupVersionResource{Group: "install.firefly.io", Version: "v1alpha1", Resource: "clusterpedias"}] {
+		return nil, false, nil
+	}
+
+	clusterInformer := controllerContext.KarmadaInformerFactory.Cluster().V1alpha1().Clusters()
+	secretInformer := controllerContext.KarmadaKubeInformerFactory.Core().V1().Secrets()
+	clusterpediaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Clusterpedias()
+	if err := informerutil.SetTransform(clusterInformer, secretInformer, clusterpediaInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the pediacluster controller informers: %v", err)
+	}
+
+	ctrl, err := pediacluster.NewPediaClusterController(
+		controllerContext.KarmadaClientBuilder.ClientOrDie("firefly-pediacluster-controller"),
+		controllerContext.KarmadaClientBuilder.DynamicClientOrDie("firefly-pediacluster-controller"),
+		clusterInformer,
+		secretInformer,
+		controllerContext.EstimatorNamespace,
+		controllerContext.KarmadaName,
+		clusterpediaInformer,
 	)
 	if err != nil {
-		return nil, true, fmt.Errorf("failed to start the node controller: %v", err)
+		return nil, true, fmt.Errorf("failed to start the pediacluster controller: %v", err)
 	}
-	go ctrl.Run(ctx, 1)
+	go ctrl.Run(ctx, int(controllerContext.ComponentConfig.PediaClusterController.ConcurrentPediaClusterSyncs))
 	return nil, true, nil
 }
 
-func startFooController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
-	clientConfig := controllerContext.KarmadaClientBuilder.ConfigOrDie("firefly-foo-controller")
-	dynamicClient := dynamic.NewForConfigOrDie(clientConfig)
-
-	ctrl, err := foo.NewFooController(
-		controllerContext.RESTMapper,
-		dynamicClient,
-		controllerContext.KarmadaClientBuilder.ClientOrDie("firefly-foo-controller"),
-		controllerContext.KarmadaClientBuilder.KarmadaClientOrDie("firefly-foo-controller"),
-		controllerContext.KarmadaClientBuilder.KarmadaFireflyClientOrDie("firefly-foo-controller"),
-		controllerContext.KarmadaFireflyInformerFactory.Toolkit().V1alpha1().Foos(),
-		controllerContext.KarmadaInformerFactory.Cluster().V1alpha1().Clusters(),
-		controllerContext.KarmadaInformerFactory.Work().V1alpha1().Works(),
+func startRegistryController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	if !controllerContext.HostClusterAvailableResources[schema.GroupVersionResource{Group: "install.firefly.io", Version: "v1alpha1", Resource: "karmadas"}] {
+		return nil, false, nil
+	}
+
+	overridePolicyInformer := controllerContext.KarmadaInformerFactory.Policy().V1alpha1().ClusterOverridePolicies()
+	karmadaInformer := controllerContext.FireflyInformerFactory.Install().V1alpha1().Karmadas()
+	if err := informerutil.SetTransform(overridePolicyInformer, karmadaInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the registry controller informers: %v", err)
+	}
+
+	ctrl, err := registry.NewRegistryController(
+		controllerContext.KarmadaClientBuilder.KarmadaClientOrDie("firefly-registry-controller"),
+		overridePolicyInformer,
+		controllerContext.EstimatorNamespace,
+		controllerContext.KarmadaName,
+		karmadaInformer,
 	)
 	if err != nil {
-		return nil, true, fmt.Errorf("failed to start the foo controller: %v", err)
+		return nil, true, fmt.Errorf("failed to start the registry controller: %v", err)
 	}
-	go ctrl.Run(ctx, 1)
+	// a single worker is enough, since the controller only syncs one policy.
+	go ctrl.Run(ctx)
 	return nil, true, nil
 }
 
+func startNodeController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	hostNodeInformer := controllerContext.FireflyKubeInformerFactory.Core().V1().Nodes()
+	nodeInformer := controllerContext.KarmadaMetadataInformerFactory.ForResource(schema.GroupVersionResource{Version: "v1", Resource: "nodes"})
+	clusterInformer := controllerContext.KarmadaInformerFactory.Cluster().V1alpha1().Clusters()
+	if err := informerutil.SetTransform(hostNodeInformer, nodeInformer, clusterInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the node controller informers: %v", err)
+	}
+
+	ctrl, err := node.NewNodeController(
+		controllerContext.KarmadaClientBuilder.ClientOrDie("firefly-node-controller"),
+		controllerContext.KarmadaClientBuilder.KarmadaClientOrDie("firefly-node-controller"),
+		controllerContext.MemberClusterCache,
+		hostNodeInformer,
+		nodeInformer,
+		clusterInformer,
+		controllerContext.ComponentConfig.NodeController.ExtendedResources,
+		controllerContext.ComponentConfig.NodeController.AcceleratorLabels,
+	)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to start the node controller: %v", err)
+	}
+	go ctrl.Run(ctx, int(controllerContext.ComponentConfig.NodeController.ConcurrentNodeSyncs))
+	return ctrl, true, nil
+}
+
 func startKubeanController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
 	gvrs := []schema.GroupVersionResource{
 		{Group: "kubean.io", Version: "v1alpha1", Resource: "clusteroperations"},
@@ -103,6 +169,12 @@ func startKubeanController(ctx context.Context, controllerContext ControllerCont
 
 	clusterInformers := controllerContext.KarmadaDynamicInformerFactory.ForResource(schema.GroupVersionResource{Group: "kubean.io", Version: "v1alpha1", Resource: "clusters"})
 	hostClusterInformers := controllerContext.FireflyDynamicInformerFactory.ForResource(schema.GroupVersionResource{Group: "kubean.io", Version: "v1alpha1", Resource: "clusters"})
+	configMapInformer := controllerContext.KarmadaKubeInformerFactory.Core().V1().ConfigMaps()
+	manifestInformers := controllerContext.KarmadaDynamicInformerFactory.ForResource(schema.GroupVersionResource{Group: "kubean.io", Version: "v1alpha1", Resource: "manifests"})
+	hostManifestInformers := controllerContext.FireflyDynamicInformerFactory.ForResource(schema.GroupVersionResource{Group: "kubean.io", Version: "v1alpha1", Resource: "manifests"})
+	if err := informerutil.SetTransform(clusterInformers, hostClusterInformers, configMapInformer, manifestInformers, hostManifestInformers); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the kubean controller informers: %v", err)
+	}
 
 	clusterctrl, err := kubean.NewClusterController(
 		controllerContext.EstimatorNamespace,
@@ -116,12 +188,12 @@ func startKubeanController(ctx context.Context, controllerContext ControllerCont
 		return nil, true, fmt.Errorf("failed to start the kubean cluster controller: %v", err)
 	}
 
-	go clusterctrl.Run(ctx, 1)
+	go clusterctrl.Run(ctx, int(controllerContext.ComponentConfig.KubeanController.ConcurrentKubeanSyncs))
 
 	clusterrefctl, err := kubean.NewClusterRefController(
 		controllerContext.KarmadaClientBuilder.ClientOrDie("kubean-clusterref-controller"),
 		clusterInformers,
-		controllerContext.KarmadaKubeInformerFactory.Core().V1().ConfigMaps(),
+		configMapInformer,
 		controllerContext.EstimatorNamespace,
 		controllerContext.FireflyClientBuilder.ClientOrDie("kubean-clusterref-controller"),
 	)
@@ -129,10 +201,7 @@ func startKubeanController(ctx context.Context, controllerContext ControllerCont
 		return nil, true, fmt.Errorf("failed to start the kubean clusterref controller: %v", err)
 	}
 
-	go clusterrefctl.Run(ctx, 1)
-
-	manifestInformers := controllerContext.KarmadaDynamicInformerFactory.ForResource(schema.GroupVersionResource{Group: "kubean.io", Version: "v1alpha1", Resource: "manifests"})
-	hostManifestInformers := controllerContext.FireflyDynamicInformerFactory.ForResource(schema.GroupVersionResource{Group: "kubean.io", Version: "v1alpha1", Resource: "manifests"})
+	go clusterrefctl.Run(ctx, int(controllerContext.ComponentConfig.KubeanController.ConcurrentKubeanSyncs))
 
 	manifestctrl, err := kubean.NewManifestController(
 		controllerContext.KarmadaClientBuilder.ClientOrDie("kubean-manifest-controller"),
@@ -144,7 +213,27 @@ func startKubeanController(ctx context.Context, controllerContext ControllerCont
 		return nil, true, fmt.Errorf("failed to start the kubean manifest controller: %v", err)
 	}
 
-	go manifestctrl.Run(ctx, 1)
+	go manifestctrl.Run(ctx, int(controllerContext.ComponentConfig.KubeanController.ConcurrentKubeanSyncs))
 
-	return nil, true, nil
+	return &kubeanControllers{
+		debuggingHandler: debug.Handler(map[string]*debug.Recorder{
+			"cluster":    clusterctrl.DebugRecorder(),
+			"clusterref": clusterrefctl.DebugRecorder(),
+			"manifest":   manifestctrl.DebugRecorder(),
+		}),
+	}, true, nil
+}
+
+// kubeanControllers exposes the debugging information of the kubean controllers,
+// which are started together by startKubeanController.
+type kubeanControllers struct {
+	debuggingHandler http.Handler
+}
+
+func (c *kubeanControllers) Name() string {
+	return "kubean"
+}
+
+func (c *kubeanControllers) DebuggingHandler() http.Handler {
+	return c.debuggingHandler
 }
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+
+	flowcontrolv1beta2 "k8s.io/api/flowcontrol/v1beta2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/carlory/firefly/cmd/firefly-karmada-manager/app/config"
+	"github.com/carlory/firefly/pkg/clientbuilder"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+)
+
+const (
+	// bulkClientsFlowSchemaName is the name of the FlowSchema which puts the requests of the bulk clients
+	// into the workload-low priority level of the karmada apiserver.
+	bulkClientsFlowSchemaName = "firefly-bulk-clients"
+	// bulkClientsPriorityLevel is one of the priority levels suggested by the API Priority and Fairness.
+	bulkClientsPriorityLevel = "workload-low"
+	// bulkClientsMatchingPrecedence makes the FlowSchema match before the suggested service-accounts
+	// FlowSchema, and after the ones of the leader elections and of the kube-controller-manager.
+	bulkClientsMatchingPrecedence = 1000
+)
+
+// EnsureBulkClientsFlowSchema creates the FlowSchema which matches the service accounts of the bulk clients,
+// or deletes it if there're no bulk clients. The requests can only be told apart by their users, so the
+// FlowSchema is only created if the controllers use their own service accounts.
+//
+// The FlowSchema only lowers the priority of the bulk clients, so a failure is logged instead of
+// preventing the controllers from starting.
+func EnsureBulkClientsFlowSchema(ctx context.Context, c *config.CompletedConfig, rootKarmadaClientBuilder clientbuilder.KarmadaControllerClientBuilder) {
+	client, err := rootKarmadaClientBuilder.Client("firefly-traffic-shaping")
+	if err != nil {
+		klog.ErrorS(err, "Failed to build the client to ensure the FlowSchema of the bulk clients")
+		return
+	}
+	bulkClients := sets.NewString(c.ComponentConfig.TrafficShaping.BulkClients...)
+	if !c.ComponentConfig.UseServiceAccountCredentials || bulkClients.Len() == 0 {
+		if err := deleteBulkClientsFlowSchema(ctx, client); err != nil {
+			klog.ErrorS(err, "Failed to delete the FlowSchema of the bulk clients", "flowSchema", bulkClientsFlowSchemaName)
+		}
+		return
+	}
+	if _, err := clientutil.CreateOrUpdateFlowSchema(ctx, client, bulkClientsFlowSchema(bulkClients.List())); err != nil {
+		klog.ErrorS(err, "Failed to ensure the FlowSchema of the bulk clients", "flowSchema", bulkClientsFlowSchemaName)
+		return
+	}
+	klog.V(2).InfoS("Ensured the FlowSchema of the bulk clients", "flowSchema", bulkClientsFlowSchemaName, "clients", bulkClients.List())
+}
+
+// bulkClientsFlowSchema returns the FlowSchema matching all the requests of the service accounts of the
+// given clients, which are created by the dynamic client builder in the kube-system namespace.
+func bulkClientsFlowSchema(bulkClients []string) *flowcontrolv1beta2.FlowSchema {
+	subjects := make([]flowcontrolv1beta2.Subject, 0, len(bulkClients))
+	for _, name := range bulkClients {
+		subjects = append(subjects, flowcontrolv1beta2.Subject{
+			Kind: flowcontrolv1beta2.SubjectKindServiceAccount,
+			ServiceAccount: &flowcontrolv1beta2.ServiceAccountSubject{
+				Namespace: metav1.NamespaceSystem,
+				Name:      name,
+			},
+		})
+	}
+	return &flowcontrolv1beta2.FlowSchema{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: bulkClientsFlowSchemaName,
+		},
+		Spec: flowcontrolv1beta2.FlowSchemaSpec{
+			PriorityLevelConfiguration: flowcontrolv1beta2.PriorityLevelConfigurationReference{
+				Name: bulkClientsPriorityLevel,
+			},
+			MatchingPrecedence: bulkClientsMatchingPrecedence,
+			DistinguisherMethod: &flowcontrolv1beta2.FlowDistinguisherMethod{
+				Type: flowcontrolv1beta2.FlowDistinguisherMethodByUserType,
+			},
+			Rules: []flowcontrolv1beta2.PolicyRulesWithSubjects{
+				{
+					Subjects: subjects,
+					ResourceRules: []flowcontrolv1beta2.ResourcePolicyRule{
+						{
+							Verbs:        []string{flowcontrolv1beta2.VerbAll},
+							APIGroups:    []string{flowcontrolv1beta2.APIGroupAll},
+							Resources:    []string{flowcontrolv1beta2.ResourceAll},
+							ClusterScope: true,
+							Namespaces:   []string{flowcontrolv1beta2.NamespaceEvery},
+						},
+					},
+					NonResourceRules: []flowcontrolv1beta2.NonResourcePolicyRule{
+						{
+							Verbs:           []string{flowcontrolv1beta2.VerbAll},
+							NonResourceURLs: []string{flowcontrolv1beta2.NonResourceAll},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// deleteBulkClientsFlowSchema deletes the FlowSchema of the bulk clients if it exists.
+func deleteBulkClientsFlowSchema(ctx context.Context, client clientset.Interface) error {
+	err := client.FlowcontrolV1beta2().FlowSchemas().Delete(ctx, bulkClientsFlowSchemaName, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "FlowSchema", Name: bulkClientsFlowSchemaName}, err)
+	return err
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/karmada/controller/apis/config"
+)
+
+// DefaultControllerGroup is the controller group of the controllers in none of the configured groups,
+// which is elected by the lease of the leader election resource name.
+const DefaultControllerGroup = "default"
+
+// validateControllerGroups checks that the groups are named as lease suffixes, each controller is in
+// at most one group, and the groups run by this replica exist.
+func validateControllerGroups(groups []fireflyctrlmgrconfig.ControllerGroup, leaderElectGroups []string, allControllers []string) []error {
+	var errs []error
+	known := sets.NewString(allControllers...)
+	names := sets.NewString(DefaultControllerGroup)
+	grouped := map[string]string{}
+	for _, group := range groups {
+		if group.Name == DefaultControllerGroup || group.Name == "*" {
+			errs = append(errs, fmt.Errorf("controller group name %q is reserved", group.Name))
+			continue
+		}
+		for _, msg := range validation.IsDNS1123Label(group.Name) {
+			errs = append(errs, fmt.Errorf("invalid controller group name %q: %s", group.Name, msg))
+		}
+		if names.Has(group.Name) {
+			errs = append(errs, fmt.Errorf("duplicate controller group %q", group.Name))
+		}
+		names.Insert(group.Name)
+		if len(group.Controllers) == 0 {
+			errs = append(errs, fmt.Errorf("controller group %q has no controllers", group.Name))
+		}
+		for _, controller := range group.Controllers {
+			if !known.Has(controller) {
+				errs = append(errs, fmt.Errorf("controller group %q contains the unknown controller %q", group.Name, controller))
+			}
+			if other, ok := grouped[controller]; ok {
+				errs = append(errs, fmt.Errorf("controller %q is in both controller groups %q and %q", controller, other, group.Name))
+			}
+			grouped[controller] = group.Name
+		}
+	}
+
+	if len(leaderElectGroups) == 0 {
+		errs = append(errs, fmt.Errorf("leader-elect-controller-groups must not be empty"))
+	}
+	for _, name := range leaderElectGroups {
+		if name != "*" && !names.Has(name) {
+			errs = append(errs, fmt.Errorf("leader-elect-controller-groups contains the unknown controller group %q", name))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/karmada/controller/apis/config"
+)
+
+// EstimatorControllerOptions holds the EstimatorController options.
+type EstimatorControllerOptions struct {
+	*fireflyctrlmgrconfig.EstimatorControllerConfiguration
+}
+
+// AddFlags adds flags related to EstimatorController for controller manager to the specified FlagSet.
+func (o *EstimatorControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentEstimatorSyncs, "concurrent-estimator-syncs", o.ConcurrentEstimatorSyncs, "The number of clusters whose estimators are allowed to sync concurrently. Larger number = more responsive estimators, but more CPU (and network) load")
+}
+
+// ApplyTo fills up EstimatorController config with options.
+func (o *EstimatorControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.EstimatorControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentEstimatorSyncs = o.ConcurrentEstimatorSyncs
+	return nil
+}
+
+// Validate checks validation of EstimatorControllerOptions.
+func (o *EstimatorControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentEstimatorSyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-estimator-syncs must be greater than 0, got %d", o.ConcurrentEstimatorSyncs))
+	}
+	return errs
+}
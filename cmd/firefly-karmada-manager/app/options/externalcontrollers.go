@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/karmada/controller/apis/config"
+)
+
+// ExternalControllerEnvPrefix is the prefix of the environment variables set by firefly for the external
+// controllers, which can't be overridden by their env.
+const ExternalControllerEnvPrefix = "FIREFLY_"
+
+// validateExternalControllers checks that the external controllers are named as the built-in controllers,
+// have a command, and don't set the environment variables reserved for firefly. The conflicts with the
+// built-in controllers are checked when they're registered.
+func validateExternalControllers(controllers []fireflyctrlmgrconfig.ExternalController) []error {
+	var errs []error
+	names := sets.NewString()
+	for _, controller := range controllers {
+		for _, msg := range validation.IsDNS1123Label(controller.Name) {
+			errs = append(errs, fmt.Errorf("invalid external controller name %q: %s", controller.Name, msg))
+		}
+		if names.Has(controller.Name) {
+			errs = append(errs, fmt.Errorf("duplicate external controller %q", controller.Name))
+		}
+		names.Insert(controller.Name)
+		if len(controller.Command) == 0 || controller.Command[0] == "" {
+			errs = append(errs, fmt.Errorf("external controller %q has no command", controller.Name))
+		}
+		for name := range controller.Env {
+			if strings.HasPrefix(name, ExternalControllerEnvPrefix) {
+				errs = append(errs, fmt.Errorf("env %s of external controller %q is reserved", name, controller.Name))
+			}
+		}
+	}
+	return errs
+}
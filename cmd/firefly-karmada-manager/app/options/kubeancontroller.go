@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/karmada/controller/apis/config"
+)
+
+// KubeanControllerOptions holds the KubeanController options.
+type KubeanControllerOptions struct {
+	*fireflyctrlmgrconfig.KubeanControllerConfiguration
+}
+
+// AddFlags adds flags related to KubeanController for controller manager to the specified FlagSet.
+func (o *KubeanControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentKubeanSyncs, "concurrent-kubean-syncs", o.ConcurrentKubeanSyncs, "The number of kubean objects of each kind that are allowed to sync concurrently. Larger number = more responsive kubean clusters and manifests, but more CPU (and network) load")
+}
+
+// ApplyTo fills up KubeanController config with options.
+func (o *KubeanControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.KubeanControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentKubeanSyncs = o.ConcurrentKubeanSyncs
+	return nil
+}
+
+// Validate checks validation of KubeanControllerOptions.
+func (o *KubeanControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentKubeanSyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-kubean-syncs must be greater than 0, got %d", o.ConcurrentKubeanSyncs))
+	}
+	return errs
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/karmada/controller/apis/config"
+)
+
+// NodeControllerOptions holds the NodeController options.
+type NodeControllerOptions struct {
+	*fireflyctrlmgrconfig.NodeControllerConfiguration
+}
+
+// AddFlags adds flags related to NodeController for controller manager to the specified FlagSet.
+func (o *NodeControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentNodeSyncs, "concurrent-node-syncs", o.ConcurrentNodeSyncs, "The number of nodes that are allowed to sync concurrently. Larger number = more responsive nodes, but more CPU (and network) load")
+	fs.StringSliceVar(&o.ExtendedResources, "extended-resources", o.ExtendedResources, "The extended resources of the nodes which are summarized for the member clusters along with cpu, memory and pods. An entry ending with '*' matches the resources prefixed with the rest of it, e.g. 'hugepages-*'.")
+	fs.StringSliceVar(&o.AcceleratorLabels, "accelerator-labels", o.AcceleratorLabels, "The keys of the node labels, e.g. the accelerator type, whose values are summarized for the member clusters.")
+}
+
+// ApplyTo fills up NodeController config with options.
+func (o *NodeControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.NodeControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentNodeSyncs = o.ConcurrentNodeSyncs
+	cfg.ExtendedResources = o.ExtendedResources
+	cfg.AcceleratorLabels = o.AcceleratorLabels
+	return nil
+}
+
+// Validate checks validation of NodeControllerOptions.
+func (o *NodeControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentNodeSyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-node-syncs must be greater than 0, got %d", o.ConcurrentNodeSyncs))
+	}
+	return errs
+}
@@ -22,25 +22,31 @@ import (
 	"os"
 	"time"
 
+	"github.com/spf13/pflag"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	apiserveroptions "k8s.io/apiserver/pkg/server/options"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	clientset "k8s.io/client-go/kubernetes"
 	clientgokubescheme "k8s.io/client-go/kubernetes/scheme"
 	restclient "k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/record"
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/component-base/logs"
 	logsapi "k8s.io/component-base/logs/api/v1"
 	"k8s.io/component-base/metrics"
-	"k8s.io/controller-manager/config"
 	cmoptions "k8s.io/controller-manager/options"
 	netutils "k8s.io/utils/net"
+	"k8s.io/utils/pointer"
 
 	fireflycontrollerconfig "github.com/carlory/firefly/cmd/firefly-karmada-manager/app/config"
-	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+	"github.com/carlory/firefly/pkg/clientbuilder"
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/karmada/controller/apis/config"
+	fireflyctrlmgrconfigscheme "github.com/carlory/firefly/pkg/karmada/controller/apis/config/scheme"
+	fireflyctrlmgrconfigv1alpha1 "github.com/carlory/firefly/pkg/karmada/controller/apis/config/v1alpha1"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	"github.com/carlory/firefly/pkg/util/leaderelection"
 )
 
 const (
@@ -57,12 +63,56 @@ type FireflyControllerManagerOptions struct {
 	Authorization  *apiserveroptions.DelegatingAuthorizationOptions
 	Metrics        *metrics.Options
 	Logs           *logs.Options
+	Tracing        *TracingOptions
+
+	EstimatorController    *EstimatorControllerOptions
+	NodeController         *NodeControllerOptions
+	KubeanController       *KubeanControllerOptions
+	PediaClusterController *PediaClusterControllerOptions
 
 	KarmadaMaster      string
 	KarmadaKubeconfig  string
 	FireflyKubeconfig  string
 	EstimatorNamespace string
 	KarmadaName        string
+
+	// DryRun makes the controllers preview the reconciles instead of performing them.
+	DryRun bool
+
+	// UseServiceAccountCredentials makes the controllers use their own service accounts of karmada.
+	UseServiceAccountCredentials bool
+
+	// RequestTimeout is the timeout of each request sent by the clients of the controllers.
+	RequestTimeout time.Duration
+	// BulkClients are the names of the clients sending bulk requests.
+	BulkClients []string
+
+	// StartupRetryTimeout is how long the manager retries to start while the apiservers are unreachable.
+	StartupRetryTimeout time.Duration
+	// StartupRetryBackoff is the interval before the first retry of the startup.
+	StartupRetryBackoff time.Duration
+
+	// ControllerGroups splits the controllers into groups elected by their own leases, it's only
+	// set by the --config file.
+	ControllerGroups []fireflyctrlmgrconfig.ControllerGroup
+	// LeaderElectControllerGroups is the list of the controller groups run by this replica.
+	LeaderElectControllerGroups []string
+
+	// LeaderElectionLabels are set on the leader election leases.
+	LeaderElectionLabels map[string]string
+	// LeaderElectionIdentitySuffix is appended to the holder identities of the leader election leases.
+	LeaderElectionIdentitySuffix string
+
+	// ExternalControllers are the controllers run as child processes of the manager, it's only set
+	// by the --config file.
+	ExternalControllers []fireflyctrlmgrconfig.ExternalController
+
+	// ConfigFile is the path to the FireflyKarmadaManagerConfiguration file.
+	ConfigFile string
+
+	// ControllerClientConnections overrides the QPS and Burst of the clients of the given
+	// controllers, in the form of <controller>=<qps>:<burst>[:<timeout>].
+	ControllerClientConnections map[string]string
 }
 
 // NewFireflyControllerManagerOptions creates a new FireflyControllerManagerOptions with a default config.
@@ -74,12 +124,29 @@ func NewFireflyControllerManagerOptions() (*FireflyControllerManagerOptions, err
 
 	s := FireflyControllerManagerOptions{
 		Generic: cmoptions.NewGenericControllerManagerConfigurationOptions(&componentConfig.Generic),
+		EstimatorController: &EstimatorControllerOptions{
+			EstimatorControllerConfiguration: &componentConfig.EstimatorController,
+		},
+		NodeController: &NodeControllerOptions{
+			NodeControllerConfiguration: &componentConfig.NodeController,
+		},
+		KubeanController: &KubeanControllerOptions{
+			KubeanControllerConfiguration: &componentConfig.KubeanController,
+		},
+		PediaClusterController: &PediaClusterControllerOptions{
+			PediaClusterControllerConfiguration: &componentConfig.PediaClusterController,
+		},
 
 		SecureServing:  apiserveroptions.NewSecureServingOptions().WithLoopback(),
 		Authentication: apiserveroptions.NewDelegatingAuthenticationOptions(),
 		Authorization:  apiserveroptions.NewDelegatingAuthorizationOptions(),
 		Metrics:        metrics.NewOptions(),
 		Logs:           logs.NewOptions(),
+		Tracing:        &TracingOptions{},
+
+		LeaderElectControllerGroups: componentConfig.LeaderElectControllerGroups,
+		StartupRetryTimeout:         componentConfig.StartupRetry.Timeout.Duration,
+		StartupRetryBackoff:         componentConfig.StartupRetry.InitialBackoff.Duration,
 	}
 
 	// Set the PairName but leave certificate directory blank to generate in-memory by default
@@ -87,28 +154,108 @@ func NewFireflyControllerManagerOptions() (*FireflyControllerManagerOptions, err
 	s.SecureServing.ServerCert.PairName = "firefly-karmada-manager"
 	// s.SecureServing.BindPort = 10257
 	s.SecureServing.BindPort = 10357
-
-	s.Generic.LeaderElection.ResourceName = "firefly-karmada-manager"
-	s.Generic.LeaderElection.ResourceNamespace = "firefly-system"
 	return &s, nil
 }
 
-func NewDefaultComponentConfig() (fireflyctrlmgrconfig.FireflyControllerManagerConfiguration, error) {
-	internal := fireflyctrlmgrconfig.FireflyControllerManagerConfiguration{
-		Generic: config.GenericControllerManagerConfiguration{
-			Address:                 "0.0.0.0",
-			Controllers:             []string{"*"},
-			MinResyncPeriod:         metav1.Duration{Duration: 12 * time.Hour},
-			ControllerStartInterval: metav1.Duration{Duration: 0 * time.Second},
-		},
+// NewDefaultComponentConfig returns the default configuration of the firefly-karmada-manager, which is
+// also the configuration of the fields omitted from the --config file.
+func NewDefaultComponentConfig() (fireflyctrlmgrconfig.FireflyKarmadaManagerConfiguration, error) {
+	versioned := fireflyctrlmgrconfigv1alpha1.FireflyKarmadaManagerConfiguration{}
+	fireflyctrlmgrconfigscheme.Scheme.Default(&versioned)
+
+	internal := fireflyctrlmgrconfig.FireflyKarmadaManagerConfiguration{}
+	if err := fireflyctrlmgrconfigscheme.Scheme.Convert(&versioned, &internal, nil); err != nil {
+		return internal, err
 	}
 	return internal, nil
 }
 
+// Complete loads the configuration file passed by --config into the options. The flags set on the
+// command line take precedence over the file.
+func (s *FireflyControllerManagerOptions) Complete(fs *pflag.FlagSet) error {
+	if s.ConfigFile == "" {
+		return nil
+	}
+	cfg, err := LoadConfigFromFile(s.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	// remember the values of the flags set on the command line, those overwritten by the file are set again.
+	values := map[*pflag.Flag]string{}
+	slices := map[*pflag.Flag][]string{}
+	fs.Visit(func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			slices[f] = sv.GetSlice()
+			return
+		}
+		values[f] = f.Value.String()
+	})
+
+	*s.Generic.GenericControllerManagerConfiguration = cfg.Generic
+	*s.EstimatorController.EstimatorControllerConfiguration = cfg.EstimatorController
+	*s.NodeController.NodeControllerConfiguration = cfg.NodeController
+	*s.KubeanController.KubeanControllerConfiguration = cfg.KubeanController
+	*s.PediaClusterController.PediaClusterControllerConfiguration = cfg.PediaClusterController
+	s.DryRun = cfg.DryRun
+	s.UseServiceAccountCredentials = cfg.UseServiceAccountCredentials
+	s.RequestTimeout = cfg.TrafficShaping.RequestTimeout.Duration
+	s.BulkClients = cfg.TrafficShaping.BulkClients
+	s.StartupRetryTimeout = cfg.StartupRetry.Timeout.Duration
+	s.StartupRetryBackoff = cfg.StartupRetry.InitialBackoff.Duration
+	s.ControllerGroups = cfg.ControllerGroups
+	s.LeaderElectControllerGroups = cfg.LeaderElectControllerGroups
+	s.LeaderElectionLabels = cfg.LeaderElectionLabels
+	s.LeaderElectionIdentitySuffix = cfg.LeaderElectionIdentitySuffix
+	s.ExternalControllers = cfg.ExternalControllers
+	s.Tracing.Endpoint, s.Tracing.SamplingRatePerMillion = "", 0
+	if cfg.Tracing != nil {
+		s.Tracing.Endpoint = pointer.StringDeref(cfg.Tracing.Endpoint, "")
+		s.Tracing.SamplingRatePerMillion = pointer.Int32Deref(cfg.Tracing.SamplingRatePerMillion, 0)
+	}
+
+	var errs []error
+	for f, value := range values {
+		if f.Value.String() != value {
+			if err := f.Value.Set(value); err != nil {
+				errs = append(errs, fmt.Errorf("invalid value %q for flag --%s: %v", value, f.Name, err))
+			}
+		}
+	}
+	for f, slice := range slices {
+		if err := f.Value.(pflag.SliceValue).Replace(slice); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value %q for flag --%s: %v", slice, f.Name, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// LoadConfigFromFile decodes the FireflyKarmadaManagerConfiguration from the given file and
+// defaults the omitted fields. Unknown and duplicate fields are rejected.
+func LoadConfigFromFile(file string) (*fireflyctrlmgrconfig.FireflyKarmadaManagerConfiguration, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	obj, gvk, err := fireflyctrlmgrconfigscheme.Codecs.UniversalDecoder().Decode(data, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", file, err)
+	}
+	cfg, ok := obj.(*fireflyctrlmgrconfig.FireflyKarmadaManagerConfiguration)
+	if !ok {
+		return nil, fmt.Errorf("couldn't decode %s as FireflyKarmadaManagerConfiguration, got %s", file, gvk)
+	}
+	return cfg, nil
+}
+
 // Flags returns flags for a specific APIServer by section name
 func (s *FireflyControllerManagerOptions) Flags(allControllers []string, disabledByDefaultControllers []string) cliflag.NamedFlagSets {
 	fss := cliflag.NamedFlagSets{}
 	s.Generic.AddFlags(&fss, allControllers, disabledByDefaultControllers)
+	s.EstimatorController.AddFlags(fss.FlagSet("estimator controller"))
+	s.NodeController.AddFlags(fss.FlagSet("node controller"))
+	s.KubeanController.AddFlags(fss.FlagSet("kubean controller"))
+	s.PediaClusterController.AddFlags(fss.FlagSet("pediacluster controller"))
 
 	s.SecureServing.AddFlags(fss.FlagSet("secure serving"))
 	s.Authentication.AddFlags(fss.FlagSet("authentication"))
@@ -116,13 +263,26 @@ func (s *FireflyControllerManagerOptions) Flags(allControllers []string, disable
 
 	s.Metrics.AddFlags(fss.FlagSet("metrics"))
 	logsapi.AddFlags(s.Logs, fss.FlagSet("logs"))
+	utilfeature.DefaultMutableFeatureGate.AddFlag(fss.FlagSet("feature gates"))
+	s.Tracing.AddFlags(fss.FlagSet("tracing"))
 
 	fs := fss.FlagSet("misc")
+	fs.StringVar(&s.ConfigFile, "config", s.ConfigFile, "The path to the FireflyKarmadaManagerConfiguration file of the apiVersion fireflykarmadamanager.config.firefly.io/v1alpha1. The flags set on the command line take precedence over the file.")
 	fs.StringVar(&s.KarmadaMaster, "karmada-master", s.KarmadaMaster, "The address of the karmada API server (overrides any value in karmada-kubeconfig).")
-	fs.StringVar(&s.KarmadaKubeconfig, "karmada-kubeconfig", s.KarmadaKubeconfig, "Path to karmada kubeconfig file with authorization and master location information.")
-	fs.StringVar(&s.FireflyKubeconfig, "firefly-kubeconfig", s.FireflyKubeconfig, "Path to firefly kubeconfig file with authorization and master location information.")
+	fs.StringVar(&s.KarmadaKubeconfig, "karmada-kubeconfig", s.KarmadaKubeconfig, "Path to karmada kubeconfig file with authorization and master location information. Exec credential plugins and token files are supported, the credentials are reloaded when they rotate.")
+	fs.StringVar(&s.FireflyKubeconfig, "firefly-kubeconfig", s.FireflyKubeconfig, "Path to firefly kubeconfig file with authorization and master location information. If empty, the in-cluster config of the service account of the pod is used.")
 	fs.StringVarP(&s.EstimatorNamespace, "estimator-namespace", "n", os.Getenv("ESTIMATOR_NAMESPACE"), "It represents the namespace which scheduler-estimator will be deployed. It should be the same as the namespace of a firefly karmada.")
-	fs.StringVar(&s.KarmadaName, "karmada-name", s.KarmadaName, "It represents the name of a firefly karmada object.")
+	fs.StringVar(&s.KarmadaName, "karmada-name", s.KarmadaName, "It represents the name of the firefly karmada object served by this manager. Each karmada is served by its own manager deployed in the namespace of the karmada.")
+	fs.Var(cliflag.NewMapStringString(&s.ControllerClientConnections), "controller-client-connections", "A set of <controller>=<qps>:<burst>[:<timeout>] pairs which override --kube-api-qps, --kube-api-burst and --controller-request-timeout for the clients of the given controllers, e.g. firefly-estimator-controller=50:100:30s.")
+	fs.DurationVar(&s.RequestTimeout, "controller-request-timeout", s.RequestTimeout, "The timeout of each request sent to the karmada apiserver by the clients of the controllers. The shared informers are not affected. 0 means no timeout.")
+	fs.StringSliceVar(&s.BulkClients, "bulk-clients", s.BulkClients, "A list of the clients sending bulk requests, e.g. firefly-node-controller. Their user agents are hinted with (bulk), and if --use-service-account-credentials is set, their requests are put into the workload-low priority level of the karmada apiserver by a FlowSchema.")
+	fs.DurationVar(&s.StartupRetryTimeout, "startup-retry-timeout", s.StartupRetryTimeout, "How long the manager retries to build the context of the controllers while the karmada or the firefly apiserver is unreachable, before it exits. The configuration errors aren't retried. 0 means the manager exits on the first failure.")
+	fs.DurationVar(&s.StartupRetryBackoff, "startup-retry-backoff", s.StartupRetryBackoff, "The interval before the first retry of the startup, which is doubled after each retry up to 30s.")
+	fs.BoolVar(&s.DryRun, "dry-run", s.DryRun, "If true, the controllers only log the objects they would create, update or delete, after validating the mutations by server-side dry-run requests. A single object is previewed by annotating it with firefly.io/dry-run=true instead.")
+	fs.BoolVar(&s.UseServiceAccountCredentials, "use-service-account-credentials", s.UseServiceAccountCredentials, "If true, each controller authenticates to the karmada apiserver with the token of its own service account in the kube-system namespace, which is created by the manager. The karmada kubeconfig is only used to request the tokens and by the shared informers.")
+	fs.StringSliceVar(&s.LeaderElectControllerGroups, "leader-elect-controller-groups", s.LeaderElectControllerGroups, "A list of the controller groups run by this replica, each of which is elected by its own lease. '*' runs all the groups, and 'default' is the group of the controllers in none of the controllerGroups of the --config file.")
+	fs.Var(cliflag.NewMapStringString(&s.LeaderElectionLabels), "leader-elect-labels", "A set of key=value pairs set as labels on the leader election leases, so that the leases of several firefly deployments sharing --leader-elect-resource-namespace can be told apart. Only supported by the leases resource lock.")
+	fs.StringVar(&s.LeaderElectionIdentitySuffix, "leader-elect-identity-suffix", s.LeaderElectionIdentitySuffix, "A suffix appended to the holder identities of the leader election leases, naming the deployment the holders belong to.")
 
 	return fss
 }
@@ -132,6 +292,32 @@ func (s *FireflyControllerManagerOptions) ApplyTo(c *fireflycontrollerconfig.Con
 	if err := s.Generic.ApplyTo(&c.ComponentConfig.Generic); err != nil {
 		return err
 	}
+	if err := s.EstimatorController.ApplyTo(&c.ComponentConfig.EstimatorController); err != nil {
+		return err
+	}
+	if err := s.NodeController.ApplyTo(&c.ComponentConfig.NodeController); err != nil {
+		return err
+	}
+	if err := s.KubeanController.ApplyTo(&c.ComponentConfig.KubeanController); err != nil {
+		return err
+	}
+	if err := s.PediaClusterController.ApplyTo(&c.ComponentConfig.PediaClusterController); err != nil {
+		return err
+	}
+	c.ComponentConfig.DryRun = s.DryRun
+	c.ComponentConfig.UseServiceAccountCredentials = s.UseServiceAccountCredentials
+	c.ComponentConfig.TrafficShaping.RequestTimeout = metav1.Duration{Duration: s.RequestTimeout}
+	c.ComponentConfig.TrafficShaping.BulkClients = s.BulkClients
+	c.ComponentConfig.StartupRetry.Timeout = metav1.Duration{Duration: s.StartupRetryTimeout}
+	c.ComponentConfig.StartupRetry.InitialBackoff = metav1.Duration{Duration: s.StartupRetryBackoff}
+	c.ComponentConfig.ControllerGroups = s.ControllerGroups
+	c.ComponentConfig.LeaderElectControllerGroups = s.LeaderElectControllerGroups
+	c.ComponentConfig.LeaderElectionLabels = s.LeaderElectionLabels
+	c.ComponentConfig.LeaderElectionIdentitySuffix = s.LeaderElectionIdentitySuffix
+	c.ComponentConfig.ExternalControllers = s.ExternalControllers
+	if err := s.Tracing.ApplyTo(c); err != nil {
+		return err
+	}
 	if err := s.SecureServing.ApplyTo(&c.SecureServing, &c.LoopbackClientConfig); err != nil {
 		return err
 	}
@@ -149,6 +335,20 @@ func (s *FireflyControllerManagerOptions) ApplyTo(c *fireflycontrollerconfig.Con
 // Validate is used to validate the options and config before launching the controller manager
 func (s *FireflyControllerManagerOptions) Validate(allControllers []string, disabledByDefaultControllers []string) error {
 	var errs []error
+	if _, err := clientbuilder.ParseClientConnectionOverrides(s.ControllerClientConnections); err != nil {
+		errs = append(errs, err)
+	}
+	if s.RequestTimeout < 0 {
+		errs = append(errs, fmt.Errorf("--controller-request-timeout must not be negative, got %v", s.RequestTimeout))
+	}
+	errs = append(errs, s.EstimatorController.Validate()...)
+	errs = append(errs, s.NodeController.Validate()...)
+	errs = append(errs, s.KubeanController.Validate()...)
+	errs = append(errs, s.PediaClusterController.Validate()...)
+	errs = append(errs, s.Tracing.Validate()...)
+	errs = append(errs, validateExternalControllers(s.ExternalControllers)...)
+	errs = append(errs, validateControllerGroups(s.ControllerGroups, s.LeaderElectControllerGroups, allControllers)...)
+	errs = append(errs, leaderelection.ValidateLabels(s.Generic.LeaderElection.ResourceLock, s.LeaderElectionLabels)...)
 	return utilerrors.NewAggregate(errs)
 }
 
@@ -162,7 +362,12 @@ func (s FireflyControllerManagerOptions) Config(allControllers []string, disable
 		return nil, fmt.Errorf("error creating self-signed certificates: %v", err)
 	}
 
-	karmadaKubeconfig, err := clientcmd.BuildConfigFromFlags(s.KarmadaMaster, s.KarmadaKubeconfig)
+	karmadaKubeconfig, err := clientbuilder.LoadClientConfig(s.KarmadaMaster, s.KarmadaKubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	// the rotated credentials of the kubeconfig are used without restarting.
+	karmadaKubeconfigReloader, err := clientbuilder.NewKubeconfigReloader(s.KarmadaMaster, s.KarmadaKubeconfig, karmadaKubeconfig)
 	if err != nil {
 		return nil, err
 	}
@@ -171,13 +376,19 @@ func (s FireflyControllerManagerOptions) Config(allControllers []string, disable
 	karmadaKubeconfig.ContentConfig.ContentType = s.Generic.ClientConnection.ContentType
 	karmadaKubeconfig.QPS = s.Generic.ClientConnection.QPS
 	karmadaKubeconfig.Burst = int(s.Generic.ClientConnection.Burst)
+	// the mutations sent with a dry-run context are dry-run, see --dry-run.
+	karmadaKubeconfig.Wrap(dryrun.WrapperFor())
 
 	karmadaKubeClient, err := clientset.NewForConfig(restclient.AddUserAgent(karmadaKubeconfig, FireflyKarmadaManagerUserAgent))
 	if err != nil {
 		return nil, err
 	}
 
-	fireflyKubeconfig, err := clientcmd.BuildConfigFromFlags("", s.FireflyKubeconfig)
+	fireflyKubeconfig, err := clientbuilder.LoadClientConfig("", s.FireflyKubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	fireflyKubeconfigReloader, err := clientbuilder.NewKubeconfigReloader("", s.FireflyKubeconfig, fireflyKubeconfig)
 	if err != nil {
 		return nil, err
 	}
@@ -186,23 +397,36 @@ func (s FireflyControllerManagerOptions) Config(allControllers []string, disable
 	fireflyKubeconfig.ContentConfig.ContentType = s.Generic.ClientConnection.ContentType
 	fireflyKubeconfig.QPS = s.Generic.ClientConnection.QPS
 	fireflyKubeconfig.Burst = int(s.Generic.ClientConnection.Burst)
+	// the mutations sent with a dry-run context are dry-run, see --dry-run.
+	fireflyKubeconfig.Wrap(dryrun.WrapperFor())
 	fireflyKubeClient, err := clientset.NewForConfig(restclient.AddUserAgent(fireflyKubeconfig, FireflyKarmadaManagerUserAgent))
 	if err != nil {
 		return nil, err
 	}
 
+	clientConnectionOverrides, err := clientbuilder.ParseClientConnectionOverrides(s.ControllerClientConnections)
+	if err != nil {
+		return nil, err
+	}
+
 	eventBroadcaster := record.NewBroadcaster()
 	eventRecorder := eventBroadcaster.NewRecorder(clientgokubescheme.Scheme, v1.EventSource{Component: FireflyKarmadaManagerUserAgent})
 
 	c := &fireflycontrollerconfig.Config{
-		KarmadaKubeClient:  karmadaKubeClient,
-		KarmadaKubeconfig:  karmadaKubeconfig,
-		FireflyKubeClient:  fireflyKubeClient,
-		FireflyKubeconfig:  fireflyKubeconfig,
-		EventBroadcaster:   eventBroadcaster,
-		EventRecorder:      eventRecorder,
-		EstimatorNamespace: s.EstimatorNamespace,
-		KarmadaName:        s.KarmadaName,
+		KarmadaKubeClient:         karmadaKubeClient,
+		KarmadaKubeconfig:         karmadaKubeconfig,
+		FireflyKubeClient:         fireflyKubeClient,
+		FireflyKubeconfig:         fireflyKubeconfig,
+		EventBroadcaster:          eventBroadcaster,
+		EventRecorder:             eventRecorder,
+		ClientConnectionOverrides: clientConnectionOverrides,
+		EstimatorNamespace:        s.EstimatorNamespace,
+		KarmadaName:               s.KarmadaName,
+	}
+	for _, reloader := range []*clientbuilder.KubeconfigReloader{karmadaKubeconfigReloader, fireflyKubeconfigReloader} {
+		if reloader != nil {
+			c.KubeconfigReloaders = append(c.KubeconfigReloaders, reloader)
+		}
 	}
 	if err := s.ApplyTo(c); err != nil {
 		return nil, err
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/karmada/controller/apis/config"
+)
+
+// PediaClusterControllerOptions holds the PediaClusterController options.
+type PediaClusterControllerOptions struct {
+	*fireflyctrlmgrconfig.PediaClusterControllerConfiguration
+}
+
+// AddFlags adds flags related to PediaClusterController for controller manager to the specified FlagSet.
+func (o *PediaClusterControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.Int32Var(&o.ConcurrentPediaClusterSyncs, "concurrent-pediacluster-syncs", o.ConcurrentPediaClusterSyncs, "The number of clusters whose pediaclusters are allowed to sync concurrently. Larger number = more responsive pediaclusters, but more CPU (and network) load")
+}
+
+// ApplyTo fills up PediaClusterController config with options.
+func (o *PediaClusterControllerOptions) ApplyTo(cfg *fireflyctrlmgrconfig.PediaClusterControllerConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	cfg.ConcurrentPediaClusterSyncs = o.ConcurrentPediaClusterSyncs
+	return nil
+}
+
+// Validate checks validation of PediaClusterControllerOptions.
+func (o *PediaClusterControllerOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.ConcurrentPediaClusterSyncs < 1 {
+		errs = append(errs, fmt.Errorf("concurrent-pediacluster-syncs must be greater than 0, got %d", o.ConcurrentPediaClusterSyncs))
+	}
+	return errs
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/controller-manager/controller"
+
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/karmada/controller/apis/config"
+	"github.com/carlory/firefly/pkg/karmada/controller/external"
+)
+
+var (
+	registeredControllersLock sync.RWMutex
+	// registeredControllers are the controllers added by RegisterController.
+	registeredControllers = map[string]InitFunc{}
+)
+
+// RegisterController adds a controller to the firefly-karmada-manager, so that downstream distributions
+// can ship their own controllers without forking this package. It's meant to be called from an init
+// function of the package of the controller, which is linked into a main package that runs
+// NewControllerManagerCommand, see example/sample-controller.
+//
+// The registered controller is started with the ControllerContext like the built-in ones, and can be
+// toggled by --controllers and joined to a controller group by its name. It's enabled by default unless
+// its name is added to ControllersDisabledByDefault. The permissions it needs are neither granted nor
+// verified by firefly, so they must be granted to the manager out of band.
+//
+// RegisterController panics if the name is empty or is already taken by another controller.
+func RegisterController(name string, fn InitFunc) {
+	if fn == nil {
+		panic(fmt.Sprintf("controller %q has no InitFunc", name))
+	}
+	if err := registerController(name, fn); err != nil {
+		panic(err.Error())
+	}
+}
+
+func registerController(name string, fn InitFunc) error {
+	if name == "" {
+		return fmt.Errorf("controller name must not be empty")
+	}
+	if _, ok := newBuiltinControllerInitializers()[name]; ok {
+		return fmt.Errorf("controller %q conflicts with a built-in controller", name)
+	}
+
+	registeredControllersLock.Lock()
+	defer registeredControllersLock.Unlock()
+	if _, ok := registeredControllers[name]; ok {
+		return fmt.Errorf("controller %q is registered twice", name)
+	}
+	registeredControllers[name] = fn
+	return nil
+}
+
+// registerExternalControllers registers the external controllers of the --config file, each of which
+// runs its command as a child process while the manager leads its controller group.
+func registerExternalControllers(controllers []fireflyctrlmgrconfig.ExternalController) error {
+	var errs []error
+	for _, ext := range controllers {
+		if err := registerController(ext.Name, startExternalController(ext)); err != nil {
+			errs = append(errs, fmt.Errorf("invalid external controller: %v", err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func startExternalController(ext fireflyctrlmgrconfig.ExternalController) InitFunc {
+	return func(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+		clientName := fmt.Sprintf("firefly-%s-controller", ext.Name)
+		// the configs are rebuilt by the client builders each time the process is restarted.
+		karmadaConfig := func() (*rest.Config, error) {
+			return controllerContext.KarmadaClientBuilder.Config(clientName)
+		}
+		hostConfig := func() (*rest.Config, error) {
+			return controllerContext.FireflyClientBuilder.Config(clientName)
+		}
+
+		ctrl := external.NewExternalController(
+			ext.Name,
+			ext.Command,
+			ext.Env,
+			karmadaConfig,
+			hostConfig,
+			controllerContext.EstimatorNamespace,
+			controllerContext.KarmadaName,
+			controllerContext.ComponentConfig.DryRun,
+		)
+		go ctrl.Run(ctx)
+		return ctrl, true, nil
+	}
+}
+
+// registeredControllerInitializers returns a copy of the controllers added by RegisterController.
+func registeredControllerInitializers() map[string]InitFunc {
+	registeredControllersLock.RLock()
+	defer registeredControllersLock.RUnlock()
+	controllers := make(map[string]InitFunc, len(registeredControllers))
+	for name, fn := range registeredControllers {
+		controllers[name] = fn
+	}
+	return controllers
+}
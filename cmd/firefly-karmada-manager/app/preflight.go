@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientset "k8s.io/client-go/kubernetes"
+	genericcontrollermanager "k8s.io/controller-manager/app"
+	"k8s.io/klog/v2"
+
+	"github.com/carlory/firefly/cmd/firefly-karmada-manager/app/config"
+	"github.com/carlory/firefly/pkg/clientbuilder"
+	"github.com/carlory/firefly/pkg/karmada/controller/rbac"
+	"github.com/carlory/firefly/pkg/util/backoff"
+)
+
+// requiredResource is a resource a controller can't start without.
+type requiredResource struct {
+	cluster rbac.Cluster
+	gvr     schema.GroupVersionResource
+}
+
+// requiredResources are the resources of the controllers which must be served before they're started.
+// The resources the controllers are skipped without, e.g. the kubean resources, aren't listed.
+var requiredResources = map[string][]requiredResource{
+	"estimator": {
+		{cluster: rbac.ClusterKarmada, gvr: schema.GroupVersionResource{Group: "cluster.karmada.io", Version: "v1alpha1", Resource: "clusters"}},
+	},
+	"pediacluster": {
+		{cluster: rbac.ClusterKarmada, gvr: schema.GroupVersionResource{Group: "cluster.karmada.io", Version: "v1alpha1", Resource: "clusters"}},
+	},
+	"registry": {
+		{cluster: rbac.ClusterKarmada, gvr: schema.GroupVersionResource{Group: "policy.karmada.io", Version: "v1alpha1", Resource: "clusteroverridepolicies"}},
+	},
+}
+
+// RunPreflightChecks verifies that both apiservers are reachable, and that they serve the resources
+// the enabled controllers of the controller groups run by this replica require and grant the permissions the controllers declare. All the failed
+// checks are reported in a single error, so that the controllers aren't started to fail one by one.
+//
+// The permissions of karmada aren't verified if the controllers use their own service accounts.
+// The error is marked as transient if either apiserver is unreachable, see backoff.Retry.
+func RunPreflightChecks(ctx context.Context, c *config.CompletedConfig, karmadaClientBuilder clientbuilder.KarmadaControllerClientBuilder, fireflyKubeClientBuilder clientbuilder.FireflyControllerClientBuilder) error {
+	clients := map[rbac.Cluster]clientset.Interface{
+		rbac.ClusterKarmada: karmadaClientBuilder.ClientOrDie("firefly-preflight"),
+		rbac.ClusterHost:    fireflyKubeClientBuilder.ClientOrDie("firefly-preflight"),
+	}
+	hosts := map[rbac.Cluster]string{
+		rbac.ClusterKarmada: c.KarmadaKubeconfig.Host,
+		rbac.ClusterHost:    c.FireflyKubeconfig.Host,
+	}
+
+	var failures []string
+	reachable := map[rbac.Cluster]bool{}
+	for _, cluster := range []rbac.Cluster{rbac.ClusterKarmada, rbac.ClusterHost} {
+		// the apiservers may be started at the same time as the manager.
+		if err := genericcontrollermanager.WaitForAPIServer(clients[cluster], 10*time.Second); err != nil {
+			failures = append(failures, fmt.Sprintf("the %s apiserver at %s is unreachable: %v", cluster, hosts[cluster], err))
+			continue
+		}
+		reachable[cluster] = true
+	}
+
+	var controllers []string
+	for name := range NewControllerInitializers() {
+		if genericcontrollermanager.IsControllerEnabled(name, ControllersDisabledByDefault, c.ComponentConfig.Generic.Controllers) &&
+			isControllerGroupRun(c, controllerGroupOf(c, name)) {
+			controllers = append(controllers, name)
+		}
+	}
+	sort.Strings(controllers)
+
+	served := map[rbac.Cluster]map[schema.GroupVersion]map[string]bool{}
+	for _, controller := range controllers {
+		for _, required := range requiredResources[controller] {
+			if !reachable[required.cluster] {
+				continue
+			}
+			ok, err := isServed(clients[required.cluster], served, required)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("failed to discover %s of the %s apiserver: %v", required.gvr.GroupVersion(), required.cluster, err))
+				continue
+			}
+			if !ok {
+				failures = append(failures, fmt.Sprintf("the %s controller requires %s which isn't served by the %s apiserver, is its CRD installed?", controller, qualifiedResource(required.gvr), required.cluster))
+			}
+		}
+
+		var karmadaClient, hostClient clientset.Interface
+		if reachable[rbac.ClusterKarmada] && !c.ComponentConfig.UseServiceAccountCredentials {
+			karmadaClient = clients[rbac.ClusterKarmada]
+		}
+		if reachable[rbac.ClusterHost] {
+			hostClient = clients[rbac.ClusterHost]
+		}
+		checker, err := rbac.NewPermissionChecker(controller, karmadaClient, hostClient)
+		if err != nil {
+			return err
+		}
+		if checker == nil {
+			continue
+		}
+		missing, err := checker.Review(ctx)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("failed to review the permissions of the %s controller: %v", controller, err))
+			continue
+		}
+		if len(missing) > 0 {
+			failures = append(failures, fmt.Sprintf("the %s controller is missing permissions: %s", controller, strings.Join(missing, ", ")))
+		}
+	}
+
+	if len(failures) > 0 {
+		err := fmt.Errorf("%d preflight checks failed:\n\t- %s", len(failures), strings.Join(failures, "\n\t- "))
+		if len(reachable) < len(clients) {
+			// the checks are run again once the apiservers are reachable.
+			return backoff.Transient(err)
+		}
+		return err
+	}
+	klog.InfoS("Preflight checks passed", "controllers", controllers)
+	return nil
+}
+
+// isServed returns whether the apiserver serves the resource. The resources of the group versions
+// already discovered are cached in served.
+func isServed(client clientset.Interface, served map[rbac.Cluster]map[schema.GroupVersion]map[string]bool, required requiredResource) (bool, error) {
+	gv := required.gvr.GroupVersion()
+	if served[required.cluster] == nil {
+		served[required.cluster] = map[schema.GroupVersion]map[string]bool{}
+	}
+	resources, ok := served[required.cluster][gv]
+	if !ok {
+		list, err := client.Discovery().ServerResourcesForGroupVersion(gv.String())
+		if err != nil && !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		resources = map[string]bool{}
+		if list != nil {
+			for _, resource := range list.APIResources {
+				resources[resource.Name] = true
+			}
+		}
+		served[required.cluster][gv] = resources
+	}
+	return resources[required.gvr.Resource], nil
+}
+
+func qualifiedResource(gvr schema.GroupVersionResource) string {
+	if gvr.Group == "" {
+		return gvr.Resource + "/" + gvr.Version
+	}
+	return gvr.Resource + "." + gvr.Group + "/" + gvr.Version
+}
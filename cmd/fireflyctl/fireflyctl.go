@@ -0,0 +1,35 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+
+	"k8s.io/component-base/cli"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/carlory/firefly/pkg/fireflyctl/cmd"
+	// install, the objects created by fireflyctl create are printed with their kinds.
+	_ "github.com/carlory/firefly/pkg/apis/install/install"
+)
+
+func main() {
+	command := cmd.NewFireflyctlCommand(os.Stdin, os.Stdout, os.Stderr)
+	if err := cli.RunNoErrOutput(command); err != nil {
+		cmdutil.CheckErr(err)
+	}
+}
@@ -23,6 +23,8 @@ import (
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
 	"github.com/carlory/firefly/pkg/fkactl/cmd"
+	// install, the manifests rendered by fkactl render are owned by the install objects.
+	_ "github.com/carlory/firefly/pkg/apis/install/install"
 )
 
 func main() {
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package deploy embeds the manifests which install firefly into a host cluster.
+package deploy
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/carlory/firefly/pkg/helm"
+)
+
+// Namespace is the namespace which the components of firefly are installed into.
+const Namespace = "firefly-system"
+
+// manifests holds the crds generated by hack/update-crdgen.sh and install.yaml.
+//
+//go:embed *.yaml
+var manifests embed.FS
+
+// Objects returns the objects which install firefly, including its namespace, in install order.
+func Objects() ([]*unstructured.Unstructured, error) {
+	namespace := &unstructured.Unstructured{}
+	namespace.SetAPIVersion("v1")
+	namespace.SetKind("Namespace")
+	namespace.SetName(Namespace)
+	objs := []*unstructured.Unstructured{namespace}
+
+	entries, err := manifests.ReadDir(".")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		data, err := manifests.ReadFile(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		decoded, err := decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %v", entry.Name(), err)
+		}
+		objs = append(objs, decoded...)
+	}
+	helm.SortByKind(objs)
+	return objs, nil
+}
+
+// decode decodes a multi-document yaml, skipping empty documents.
+func decode(data []byte) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		err := decoder.Decode(&obj.Object)
+		if err == io.EOF {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+}
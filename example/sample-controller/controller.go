@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	karmadaversioned "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	clusterinformers "github.com/karmada-io/karmada/pkg/generated/informers/externalversions/cluster/v1alpha1"
+	clusterlisters "github.com/karmada-io/karmada/pkg/generated/listers/cluster/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// maxRetries is the number of times a cluster will be retried before it is dropped out of the queue.
+	maxRetries = 15
+
+	// ReadyLabel is the label set on the clusters by the clusterready controller, whose value is whether
+	// the cluster is ready, so that the clusters can be selected by their readiness.
+	ReadyLabel = "sample.firefly.io/ready"
+)
+
+// NewClusterReadyController returns a new *ClusterReadyController.
+func NewClusterReadyController(karmadaClient karmadaversioned.Interface, clusterInformer clusterinformers.ClusterInformer) *ClusterReadyController {
+	ctrl := &ClusterReadyController{
+		karmadaClient:  karmadaClient,
+		clusterLister:  clusterInformer.Lister(),
+		clustersSynced: clusterInformer.Informer().HasSynced,
+		queue:          workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "clusterready"),
+	}
+	clusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.enqueue,
+		UpdateFunc: func(_, cur interface{}) { ctrl.enqueue(cur) },
+	})
+	return ctrl
+}
+
+// ClusterReadyController keeps the ReadyLabel of the member clusters in sync with their Ready condition.
+type ClusterReadyController struct {
+	karmadaClient  karmadaversioned.Interface
+	clusterLister  clusterlisters.ClusterLister
+	clustersSynced cache.InformerSynced
+	queue          workqueue.RateLimitingInterface
+}
+
+// Name returns the name of the controller.
+func (ctrl *ClusterReadyController) Name() string {
+	return "clusterready"
+}
+
+// Run will not return until ctx is done. workers determines how many clusters will be handled in parallel.
+func (ctrl *ClusterReadyController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer ctrl.queue.ShutDown()
+
+	klog.Infof("Starting clusterready controller")
+	defer klog.Infof("Shutting down clusterready controller")
+
+	if !cache.WaitForNamedCacheSync("clusterready", ctx.Done(), ctrl.clustersSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, ctrl.worker, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (ctrl *ClusterReadyController) worker(ctx context.Context) {
+	for ctrl.processNextWorkItem(ctx) {
+	}
+}
+
+func (ctrl *ClusterReadyController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := ctrl.queue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.queue.Done(key)
+
+	err := ctrl.syncCluster(ctx, key.(string))
+	if err == nil {
+		ctrl.queue.Forget(key)
+		return true
+	}
+	if ctrl.queue.NumRequeues(key) < maxRetries {
+		klog.V(2).InfoS("Error syncing cluster, retrying", "cluster", klog.KRef("", key.(string)), "err", err)
+		ctrl.queue.AddRateLimited(key)
+		return true
+	}
+	utilruntime.HandleError(err)
+	klog.V(2).InfoS("Dropping cluster out of the queue", "cluster", klog.KRef("", key.(string)), "err", err)
+	ctrl.queue.Forget(key)
+	return true
+}
+
+func (ctrl *ClusterReadyController) enqueue(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("couldn't get key for object %#v: %v", obj, err))
+		return
+	}
+	ctrl.queue.Add(key)
+}
+
+func (ctrl *ClusterReadyController) syncCluster(ctx context.Context, key string) error {
+	cluster, err := ctrl.clusterLister.Get(key)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !cluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	ready := strconv.FormatBool(meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1alpha1.ClusterConditionReady))
+	if cluster.Labels[ReadyLabel] == ready {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]string{ReadyLabel: ready},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	klog.V(2).InfoS("Updating readiness label of cluster", "cluster", klog.KObj(cluster), "ready", ready)
+	_, err = ctrl.karmadaClient.ClusterV1alpha1().Clusters().Patch(ctx, cluster.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
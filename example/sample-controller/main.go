@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// The sample-controller is an example of a downstream distribution of the firefly-karmada-manager,
+// which adds its own controller with app.RegisterController instead of forking the cmd package.
+// It's built like the firefly-karmada-manager and takes the same flags, e.g. --controllers=*,-clusterready
+// disables the sample controller.
+//
+// An out-of-tree controller lives in its own module which requires github.com/carlory/firefly, the
+// sample is kept in this module so that it's built along with the firefly-karmada-manager. The controller
+// only depends on pkg/karmada/controllermanager, the cmd package is only imported by the main package.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"k8s.io/component-base/cli"
+	_ "k8s.io/component-base/logs/json/register"          // for JSON log format registration
+	_ "k8s.io/component-base/metrics/prometheus/clientgo" // load all the prometheus client-go plugin
+	_ "k8s.io/component-base/metrics/prometheus/version"  // for version metric registration
+	"k8s.io/controller-manager/controller"
+
+	"github.com/carlory/firefly/cmd/firefly-karmada-manager/app"
+	"github.com/carlory/firefly/pkg/karmada/controllermanager"
+	informerutil "github.com/carlory/firefly/pkg/util/informer"
+)
+
+func init() {
+	app.RegisterController("clusterready", startClusterReadyController)
+}
+
+func startClusterReadyController(ctx context.Context, controllerContext controllermanager.ControllerContext) (controller.Interface, bool, error) {
+	clusterInformer := controllerContext.KarmadaInformerFactory.Cluster().V1alpha1().Clusters()
+	if err := informerutil.SetTransform(clusterInformer); err != nil {
+		return nil, true, fmt.Errorf("failed to set transform of the clusterready controller informers: %v", err)
+	}
+
+	ctrl := NewClusterReadyController(
+		controllerContext.KarmadaClientBuilder.KarmadaClientOrDie("sample-clusterready-controller"),
+		clusterInformer,
+	)
+	go ctrl.Run(ctx, 1)
+	return ctrl, true, nil
+}
+
+func main() {
+	command := app.NewControllerManagerCommand()
+	code := cli.Run(command)
+	os.Exit(code)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// applyconfiguration-gen runs the applyconfiguration-gen of the vendored code-generator with the
+// apply configurations of client-go for the kubernetes types embedded in the firefly types.
+//
+// The --external-applyconfigurations flag of code-generator v0.25 splits <package>.<typeName> on
+// every dot, so it rejects the packages of k8s.io, and replaces the default mappings of TypeMeta and
+// ObjectMeta once it's set. The mappings are added here instead, and the flag must not be set.
+package main
+
+import (
+	"flag"
+
+	"github.com/spf13/pflag"
+	"k8s.io/gengo/types"
+	"k8s.io/klog/v2"
+
+	generatorargs "k8s.io/code-generator/cmd/applyconfiguration-gen/args"
+	"k8s.io/code-generator/cmd/applyconfiguration-gen/generators"
+)
+
+// externalApplyConfigurations maps the packages of the embedded kubernetes types to the packages of
+// their apply configurations in client-go, and lists the types to map.
+var externalApplyConfigurations = map[string]struct {
+	applyConfigurationPackage string
+	types                     []string
+}{
+	"k8s.io/apimachinery/pkg/apis/meta/v1": {
+		applyConfigurationPackage: "k8s.io/client-go/applyconfigurations/meta/v1",
+		types:                     []string{"Condition", "LabelSelector", "LabelSelectorRequirement", "ManagedFieldsEntry", "OwnerReference"},
+	},
+	"k8s.io/api/core/v1": {
+		applyConfigurationPackage: "k8s.io/client-go/applyconfigurations/core/v1",
+		types: []string{
+			"EnvVar", "LimitRangeSpec", "LocalObjectReference", "PersistentVolumeClaimTemplate", "ResourceQuotaSpec",
+			"ResourceRequirements", "SecretKeySelector", "Toleration", "Volume", "VolumeMount",
+		},
+	},
+	"k8s.io/api/batch/v1": {
+		applyConfigurationPackage: "k8s.io/client-go/applyconfigurations/batch/v1",
+		types:                     []string{"JobTemplateSpec"},
+	},
+	"k8s.io/api/autoscaling/v2": {
+		applyConfigurationPackage: "k8s.io/client-go/applyconfigurations/autoscaling/v2",
+		types:                     []string{"HorizontalPodAutoscalerBehavior", "MetricSpec"},
+	},
+}
+
+func main() {
+	klog.InitFlags(nil)
+	genericArgs, customArgs := generatorargs.NewDefaults()
+	genericArgs.AddFlags(pflag.CommandLine)
+	if err := flag.Set("logtostderr", "true"); err != nil {
+		klog.Fatalf("Error: %v", err)
+	}
+	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	pflag.Parse()
+
+	for pkg, external := range externalApplyConfigurations {
+		for _, name := range external.types {
+			customArgs.ExternalApplyConfigurations[types.Name{Package: pkg, Name: name}] = external.applyConfigurationPackage
+		}
+	}
+	if err := generatorargs.Validate(genericArgs); err != nil {
+		klog.Fatalf("Error: %v", err)
+	}
+
+	if err := genericArgs.Execute(
+		generators.NameSystems(),
+		generators.DefaultNameSystem(),
+		generators.Packages,
+	); err != nil {
+		klog.Fatalf("Error: %v", err)
+	}
+}
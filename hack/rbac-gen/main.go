@@ -0,0 +1,216 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// rbac-gen generates the ClusterRoles of the controllers of the firefly-karmada-manager from the
+// +firefly:rbac markers in their packages. Each package under the root directory is a controller
+// named after the directory, and the markers declare the rules it needs on either cluster:
+//
+//	// +firefly:rbac:cluster=karmada,groups=cluster.karmada.io,resources=clusters,verbs=get;list;watch
+//	// +firefly:rbac:cluster=host,groups="",resources=services;secrets,verbs=get;create;update;delete
+//
+// The ClusterRoles of the karmada apiserver are written to karmada.yaml of the output directory,
+// and those of the host cluster to host.yaml.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	markerPrefix = "+firefly:rbac:"
+
+	// the labels and the name prefix must be kept in sync with pkg/karmada/controller/rbac, which
+	// can't be imported because it embeds the generated files.
+	controllerLabel = "rbac.firefly.io/controller"
+	clusterLabel    = "rbac.firefly.io/cluster"
+	namePrefix      = "system:firefly-karmada-manager:"
+)
+
+var clusters = []string{"karmada", "host"}
+
+// rule is a rule declared by a marker, the verbs of the rules of the same groups and resources are merged.
+type rule struct {
+	groups    []string
+	resources []string
+	verbs     sets.String
+}
+
+func main() {
+	root := flag.String("root", "./pkg/karmada/controller", "The directory whose packages are the controllers.")
+	output := flag.String("output-dir", "./pkg/karmada/controller/rbac", "The directory the ClusterRoles are written to.")
+	flag.Parse()
+
+	// rules are keyed by cluster, controller, groups and resources.
+	rules := map[string]map[string]map[string]*rule{}
+	for _, cluster := range clusters {
+		rules[cluster] = map[string]map[string]*rule{}
+	}
+
+	entries, err := os.ReadDir(*root)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		controller := entry.Name()
+		markers, err := parseMarkers(filepath.Join(*root, controller))
+		if err != nil {
+			klog.Fatal(err)
+		}
+		for _, marker := range markers {
+			cluster, r, err := parseMarker(marker)
+			if err != nil {
+				klog.Fatalf("invalid marker %q of the %s controller: %v", marker, controller, err)
+			}
+			if rules[cluster][controller] == nil {
+				rules[cluster][controller] = map[string]*rule{}
+			}
+			key := strings.Join(r.groups, ";") + "/" + strings.Join(r.resources, ";")
+			if existing, ok := rules[cluster][controller][key]; ok {
+				existing.verbs.Insert(r.verbs.UnsortedList()...)
+				continue
+			}
+			rules[cluster][controller][key] = r
+		}
+	}
+
+	for _, cluster := range clusters {
+		data, err := generate(cluster, rules[cluster])
+		if err != nil {
+			klog.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(*output, cluster+".yaml"), data, 0644); err != nil {
+			klog.Fatal(err)
+		}
+	}
+}
+
+// parseMarkers returns the markers in the comments of the go files of the directory.
+func parseMarkers(dir string) ([]string, error) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	var markers []string
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, group := range file.Comments {
+				for _, comment := range group.List {
+					text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+					if strings.HasPrefix(text, markerPrefix) {
+						markers = append(markers, strings.TrimPrefix(text, markerPrefix))
+					}
+				}
+			}
+		}
+	}
+	return markers, nil
+}
+
+// parseMarker parses the cluster and the rule of a marker like cluster=host,groups=apps,resources=deployments,verbs=get;list.
+func parseMarker(marker string) (string, *rule, error) {
+	var cluster string
+	r := &rule{}
+	for _, arg := range strings.Split(marker, ",") {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return "", nil, fmt.Errorf("expected key=value, got %q", arg)
+		}
+		var values []string
+		for _, v := range strings.Split(value, ";") {
+			values = append(values, strings.Trim(v, `"`))
+		}
+		switch key {
+		case "cluster":
+			cluster = value
+		case "groups":
+			r.groups = values
+		case "resources":
+			r.resources = values
+		case "verbs":
+			r.verbs = sets.NewString(values...)
+		default:
+			return "", nil, fmt.Errorf("unknown key %q", key)
+		}
+	}
+	if cluster != "karmada" && cluster != "host" {
+		return "", nil, fmt.Errorf("cluster must be karmada or host, got %q", cluster)
+	}
+	if len(r.groups) == 0 || len(r.resources) == 0 || r.verbs.Len() == 0 {
+		return "", nil, fmt.Errorf("groups, resources and verbs are required")
+	}
+	return cluster, r, nil
+}
+
+// generate returns the ClusterRoles of the controllers as a multi-document yaml, sorted by name.
+func generate(cluster string, rules map[string]map[string]*rule) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("# Code generated by rbac-gen. DO NOT EDIT.\n")
+
+	controllers := make([]string, 0, len(rules))
+	for controller := range rules {
+		controllers = append(controllers, controller)
+	}
+	sort.Strings(controllers)
+	for _, controller := range controllers {
+		keys := make([]string, 0, len(rules[controller]))
+		for key := range rules[controller] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		role := &rbacv1.ClusterRole{}
+		role.SetGroupVersionKind(rbacv1.SchemeGroupVersion.WithKind("ClusterRole"))
+		role.Name = namePrefix + controller
+		role.Labels = map[string]string{
+			controllerLabel: controller,
+			clusterLabel:    cluster,
+		}
+		for _, key := range keys {
+			r := rules[controller][key]
+			role.Rules = append(role.Rules, rbacv1.PolicyRule{
+				APIGroups: r.groups,
+				Resources: r.resources,
+				Verbs:     r.verbs.List(),
+			})
+		}
+
+		data, err := yaml.Marshal(role)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString("---\n")
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
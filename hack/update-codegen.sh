@@ -11,12 +11,32 @@ CODEGEN_PKG=${CODEGEN_PKG:-$(cd "${SCRIPT_ROOT}"; ls -d -1 ./vendor/k8s.io/code-
 # --output-base    because this script should also be able to run inside the vendor dir of
 #                  github.com/carlory/firefly. The output-base is needed for the generators to output into the vendor dir
 #                  instead of the $GOPATH directly. For normal projects this can be dropped.
-bash "${CODEGEN_PKG}"/generate-groups.sh "all" \
+bash "${CODEGEN_PKG}"/generate-groups.sh "deepcopy,lister,informer" \
  github.com/carlory/firefly/pkg/generated github.com/carlory/firefly/pkg/apis \
   "install:v1alpha1"\
   --output-base "$(dirname "${BASH_SOURCE[0]}")/../../../../" \
   --go-header-file "${SCRIPT_ROOT}"/hack/boilerplate/boilerplate.go.txt
 
+# generate-groups.sh of code-generator v0.25 doesn't generate the apply configurations, which the
+# clientset needs for its typed Apply and ApplyStatus methods. hack/applyconfiguration-gen runs the
+# vendored generator and maps the kubernetes types embedded in the firefly types to the apply
+# configurations of client-go.
+APPLYCONFIGURATION_GEN_BIN="$(mktemp -d)"
+trap 'rm -rf "${APPLYCONFIGURATION_GEN_BIN}"' EXIT
+(cd "${SCRIPT_ROOT}"; go build -mod=vendor -o "${APPLYCONFIGURATION_GEN_BIN}/applyconfiguration-gen" ./hack/applyconfiguration-gen)
+"${APPLYCONFIGURATION_GEN_BIN}/applyconfiguration-gen" \
+  --input-dirs github.com/carlory/firefly/pkg/apis/install/v1alpha1 \
+  --output-package github.com/carlory/firefly/pkg/generated/applyconfiguration \
+  --output-base "$(dirname "${BASH_SOURCE[0]}")/../../../../" \
+  --go-header-file "${SCRIPT_ROOT}"/hack/boilerplate/boilerplate.go.txt
+
+bash "${CODEGEN_PKG}"/generate-groups.sh "client" \
+ github.com/carlory/firefly/pkg/generated github.com/carlory/firefly/pkg/apis \
+  "install:v1alpha1"\
+  --output-base "$(dirname "${BASH_SOURCE[0]}")/../../../../" \
+  --go-header-file "${SCRIPT_ROOT}"/hack/boilerplate/boilerplate.go.txt \
+  --apply-configuration-package github.com/carlory/firefly/pkg/generated/applyconfiguration
+
 bash "${CODEGEN_PKG}"/generate-groups.sh "all" \
  github.com/carlory/firefly/pkg/karmada/generated github.com/carlory/firefly/pkg/karmada/apis \
   "toolkit:v1alpha1"\
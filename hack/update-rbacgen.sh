@@ -0,0 +1,11 @@
+#!/usr/bin/env bash
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+REPO_ROOT=$(dirname "${BASH_SOURCE[0]}")/..
+cd "${REPO_ROOT}"
+
+echo "Generating the ClusterRoles of the firefly-karmada-manager controllers with rbac-gen"
+go generate ./pkg/karmada/controller/rbac
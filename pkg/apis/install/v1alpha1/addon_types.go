@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Addon is a specification for an Addon resource.
+// An addon declares a set of components which should be installed into a hosted
+// karmada or its member clusters, so that a new component doesn't require a new
+// hardcoded controller in firefly.
+type Addon struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the Addon.
+	// +optional
+	Spec AddonSpec `json:"spec"`
+	// Most recently observed status of the Addon.
+	// +optional
+	Status AddonStatus `json:"status"`
+}
+
+// AddonSpec is the spec for an Addon resource
+type AddonSpec struct {
+	// Paused indicates that the reconciliation of the addon is paused. While it's paused,
+	// firefly doesn't create, update or delete any resources of the addon, but still
+	// updates its status, so that operators can debug or maintain it manually.
+	// Deleting the addon is still handled.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Target represents where the addon will be installed into.
+	Target AddonTarget `json:"target"`
+
+	// Manifests represents a bundle of Kubernetes resources to be installed.
+	// Manifests and Chart are mutually exclusive.
+	// +optional
+	Manifests []AddonManifest `json:"manifests,omitempty"`
+
+	// Chart represents a helm chart which will be rendered and installed.
+	// Manifests and Chart are mutually exclusive.
+	// +optional
+	Chart *ChartReference `json:"chart,omitempty"`
+
+	// Values holds the values used to render the chart.
+	// It's ignored if Chart is not set.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Values *runtime.RawExtension `json:"values,omitempty"`
+}
+
+// AddonTarget represents where the addon will be installed into.
+type AddonTarget struct {
+	// Karmada refers to a Karmada in the same namespace. Resources of the addon
+	// are installed into its karmada-apiserver.
+	Karmada corev1.LocalObjectReference `json:"karmada"`
+
+	// Clusters is a list of member clusters of the karmada above. If set, the
+	// resources of the addon are propagated to these clusters by a
+	// ClusterPropagationPolicy; otherwise they only live in the karmada control plane.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+}
+
+// AddonManifest represents a resource to be installed by the addon.
+type AddonManifest struct {
+	// +kubebuilder:pruning:PreserveUnknownFields
+	runtime.RawExtension `json:",inline"`
+}
+
+// AddonResource identifies a resource installed by the addon.
+type AddonResource struct {
+	// APIVersion is the group version of the resource.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind is the kind of the resource.
+	Kind string `json:"kind"`
+
+	// Namespace is the namespace of the resource.
+	// It's empty for cluster-scoped resources.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the resource.
+	Name string `json:"name"`
+}
+
+const (
+	// AddonConditionReady indicates whether all the resources of the addon are installed.
+	AddonConditionReady = "Ready"
+
+	// AddonConditionPaused indicates whether the reconciliation of the addon is paused.
+	AddonConditionPaused = "Paused"
+)
+
+// AddonStatus is the status for an Addon resource
+type AddonStatus struct {
+	// observedGeneration is the most recent generation observed for this Addon. It corresponds to the
+	// Addon's generation, which is updated on mutation by the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase summarizes the status of the addon. The conditions tell the details.
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+
+	// Resources is the list of resources which have been installed by the addon.
+	// +optional
+	Resources []AddonResource `json:"resources,omitempty"`
+
+	// Represents the latest available observations of an addon's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AddonList is a list of Addon resources
+type AddonList struct {
+	metav1.TypeMeta `json:",inline"`
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Addon `json:"items"`
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+)
+
+// Autoscaling describes how the replicas of a component are scaled by a HorizontalPodAutoscaler
+// of the host cluster. The host cluster must serve the autoscaling/v2 API.
+type Autoscaling struct {
+	// MinReplicas is the lower limit for the number of replicas. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit for the number of replicas. It can't be less than MinReplicas.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Metrics contains the specifications used to calculate the desired number of replicas.
+	// Defaults to an average CPU utilization of 80%, which requires the CPU requests of the component.
+	// Custom metrics, e.g. the request rate or the request latency of the apiserver, can be used
+	// if they're served by a metrics adapter of the host cluster, e.g. prometheus-adapter.
+	// More info: https://kubernetes.io/docs/tasks/run-application/horizontal-pod-autoscale/
+	// +optional
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+
+	// Behavior configures the scaling behavior in both up and down directions.
+	// If not set, the default behavior of the HorizontalPodAutoscaler is used.
+	// +optional
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// AvailabilityPolicy describes how the components which run more than one replica are kept
+// available while the host cluster is disrupted, e.g. its nodes are drained. A PodDisruptionBudget
+// is created for each of them, and their pods are spread across the topology domains of the host
+// cluster. The components running a single replica are left alone. It's ignored if Chart is set.
+type AvailabilityPolicy struct {
+	// Disabled indicates that neither PodDisruptionBudgets nor topology spread constraints are
+	// generated for the components.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// MaxUnavailable is the maximum number or percentage of the pods of a component which are allowed
+	// to be evicted at the same time. Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// TopologyKeys are the keys of the node labels whose values define the topology domains
+	// the pods of a component are spread across. Defaults to `kubernetes.io/hostname`.
+	// +optional
+	TopologyKeys []string `json:"topologyKeys,omitempty"`
+
+	// WhenUnsatisfiable indicates how to deal with a pod if it doesn't satisfy the spread
+	// constraints. Defaults to ScheduleAnyway.
+	// +kubebuilder:validation:Enum=DoNotSchedule;ScheduleAnyway
+	// +optional
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// ChartReference represents a reference to a helm chart.
+type ChartReference struct {
+	// Repository is where the chart is loaded from. It can be one of:
+	// - empty, the chart is bundled with firefly
+	// - https://<url>, a chart repository
+	// - http://<url>, a chart repository whose host is allowed by the --chart-insecure-hosts flag of firefly-controller-manager
+	// - oci://<registry>/<path>, an oci registry, the chart is at <path>/<name>
+	// +optional
+	Repository string `json:"repository,omitempty"`
+
+	// Name is the name of the chart.
+	Name string `json:"name"`
+
+	// Version is the version of the chart.
+	// If empty, the latest version will be used.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
@@ -63,6 +63,18 @@ func SetDefaults_Clusterpedia(obj *Clusterpedia) {
 		if local.ImageMeta.ImageTag == "" {
 			local.ImageMeta.ImageTag = "8"
 		}
+		if operator := local.Operator; operator != nil {
+			if operator.Instances == nil {
+				operator.Instances = utilpointer.Int32(3)
+			}
+			if operator.Routers == nil {
+				operator.Routers = utilpointer.Int32(1)
+			}
+		}
+	}
+
+	if provider := obj.Spec.ControlplaneProvider; provider != nil && provider.ClusterRegistration == "" {
+		provider.ClusterRegistration = ClusterRegistrationPolicy
 	}
 
 	apiServer := &obj.Spec.APIServer
@@ -79,4 +91,7 @@ func SetDefaults_Clusterpedia(obj *Clusterpedia) {
 	if synchro.Replicas == nil {
 		synchro.Replicas = utilpointer.Int32(1)
 	}
+	if sharding := synchro.Sharding; sharding != nil && sharding.Policy == "" {
+		sharding.Policy = ClusterSynchroShardingHash
+	}
 }
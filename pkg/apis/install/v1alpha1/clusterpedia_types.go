@@ -2,7 +2,9 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	clusterapi "github.com/clusterpedia-io/api/cluster/v1alpha2"
 )
@@ -10,6 +12,11 @@ import (
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:resource:path=clusterpedias
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.version`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Clusterpedia is a specification for a Clusterpedia resource
 type Clusterpedia struct {
@@ -29,6 +36,13 @@ type Clusterpedia struct {
 
 // ClusterpediaSpec is the spec for a Clusterpedia resource
 type ClusterpediaSpec struct {
+	// Paused indicates that the reconciliation of the clusterpedia is paused. While it's paused,
+	// firefly doesn't create, update or delete any resources of the clusterpedia, but still
+	// updates its status, so that operators can debug or maintain it manually.
+	// Deleting the clusterpedia is still handled.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
 	// ControlplaneProvider represents where the clusterpedia crds will be deployed on.
 	// If unset, means that the clusterpedia and its crds will be installed on the host
 	// cluster.
@@ -67,6 +81,105 @@ type ClusterpediaSpec struct {
 	// Note: the clusterpedia community doesn't support this field now. Please use component-specific feature gate settings.
 	// +optional
 	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// Chart represents a helm chart which the clusterpedia instance is installed from.
+	// If set, the chart is rendered and applied into the namespace of the clusterpedia
+	// instead of the built-in manifests of firefly. The container registry is passed to
+	// the chart as the value `global.imageRegistry`.
+	// +optional
+	Chart *ChartReference `json:"chart,omitempty"`
+
+	// ValuesOverride holds the values which override the values generated from the spec
+	// when rendering the chart. It's an escape hatch for the settings which are not
+	// supported by the spec. It's ignored if Chart is not set.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	ValuesOverride *runtime.RawExtension `json:"valuesOverride,omitempty"`
+
+	// Patches is a list of patches applied to the manifests generated by firefly
+	// or rendered from the chart, in order.
+	// +optional
+	Patches []Patch `json:"patches,omitempty"`
+
+	// Monitoring holds configuration for the monitoring of the components by Prometheus.
+	// +optional
+	Monitoring Monitoring `json:"monitoring,omitempty"`
+
+	// AvailabilityPolicy describes how the components running more than one replica are kept
+	// available while the host cluster is disrupted.
+	// +optional
+	AvailabilityPolicy AvailabilityPolicy `json:"availabilityPolicy,omitempty"`
+
+	// NetworkPolicy describes the hardening of the traffic between the components.
+	// +optional
+	NetworkPolicy NetworkPolicy `json:"networkPolicy,omitempty"`
+
+	// Access exposes the clusterpedia aggregated api to the users without access to the host cluster,
+	// e.g. the data teams querying the resources across the member clusters.
+	// +optional
+	Access *ClusterpediaAccess `json:"access,omitempty"`
+
+	// Retention bounds how long the objects deleted from the member clusters are kept in the built-in
+	// database. If empty, they are kept until they are deleted by the clusterpedia itself.
+	// +optional
+	Retention *ClusterpediaRetention `json:"retention,omitempty"`
+}
+
+// ClusterpediaRetention describes the cleanups of the objects deleted from the member clusters, which are
+// run against the built-in database by the Jobs of the CronJob `<database>-retention`.
+type ClusterpediaRetention struct {
+	// Resources are the resources the retention applies to, in the format of `resource.group`, e.g.
+	// `pods` or `deployments.apps`. If empty, it applies to all the resources.
+	// +optional
+	Resources []string `json:"resources,omitempty"`
+
+	// DeletedObjectRetention is how long an object is kept in the database after it's deleted from
+	// its member cluster.
+	DeletedObjectRetention metav1.Duration `json:"deletedObjectRetention"`
+
+	// Schedule is the schedule of the cleanups in the cron format. Defaults to "0 * * * *", that is hourly.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// ClusterpediaAccess describes how the clusterpedia aggregated api is accessed by the users. Once it's set,
+// the clusterpedia-apiserver serves a certificate signed by the karmada CA, which is valid for its service
+// and the addresses it's exposed at, so that the clients can verify it with the CA.
+type ClusterpediaAccess struct {
+	// ReadOnlyKubeconfig publishes a kubeconfig in the Secret <name>-clusterpedia-readonly-kubeconfig, whose
+	// user is only allowed to get, list and watch the resources of the clusterpedia aggregated api. Its server
+	// is the address the clusterpedia-apiserver is exposed at.
+	// +optional
+	ReadOnlyKubeconfig bool `json:"readOnlyKubeconfig,omitempty"`
+
+	// ServiceType determines how the clusterpedia-apiserver service is exposed.
+	// Defaults to ClusterIP.
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +optional
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// Ingress exposes the clusterpedia-apiserver through an Ingress. The TLS connections are passed
+	// through to the clusterpedia-apiserver, which is required by the client certificate authentication,
+	// e.g. the read-only kubeconfig. Passthrough is supported by the ingress-nginx controller with
+	// `--enable-ssl-passthrough`.
+	// +optional
+	Ingress *ClusterpediaIngress `json:"ingress,omitempty"`
+}
+
+// ClusterpediaIngress describes how the clusterpedia-apiserver is exposed through an Ingress.
+type ClusterpediaIngress struct {
+	// Hostname is the host name of the clusterpedia-apiserver.
+	// It's added to the SANs of the clusterpedia-apiserver certificate and used as the server address
+	// of the read-only kubeconfig.
+	Hostname string `json:"hostname"`
+
+	// IngressClassName is the name of the IngressClass used by the Ingress.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// Annotations are extra annotations added to the Ingress.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 // ControlplaneProvider represents where the clusterpedia crds will be deployed on.
@@ -80,11 +193,33 @@ type ClusterpediaControlplaneProvider struct {
 	// +optional
 	SyncResources []clusterapi.ClusterGroupResources `json:"syncResources,omitempty"`
 
+	// ClusterRegistration represents how the member clusters of the control plane are registered
+	// into clusterpedia as PediaClusters.
+	//
+	// Policy: firefly creates a ClusterImportPolicy and clusterpedia imports the member clusters by itself.
+	// Controller: the firefly-karmada-manager creates and removes a PediaCluster whenever a member cluster
+	// joins or leaves the control plane, with the credentials derived from the secret of the cluster.
+	// +kubebuilder:validation:Enum=Policy;Controller
+	// +kubebuilder:default=Policy
+	// +optional
+	ClusterRegistration ClusterRegistrationMode `json:"clusterRegistration,omitempty"`
+
 	// Karmada represents the karmada control plane.
 	// +optional
 	Karmada *ClusterpediaControlplaneProviderKarmada `json:"karmada,omitempty"`
 }
 
+// ClusterRegistrationMode represents how the member clusters of the control plane are registered into clusterpedia.
+type ClusterRegistrationMode string
+
+const (
+	// ClusterRegistrationPolicy means the member clusters are imported by a ClusterImportPolicy.
+	ClusterRegistrationPolicy ClusterRegistrationMode = "Policy"
+
+	// ClusterRegistrationController means the member clusters are registered by the firefly-karmada-manager.
+	ClusterRegistrationController ClusterRegistrationMode = "Controller"
+)
+
 // KarmadaControlplaneProviderKarmada represents the karmada controlplane provider
 type ClusterpediaControlplaneProviderKarmada struct {
 	corev1.LocalObjectReference `json:",inline"`
@@ -96,6 +231,36 @@ type ClusterpediaStorageComponent struct {
 	Postgres *Postgres `json:"postgres,omitempty"`
 	//MySQL holds settings to clusterpedia-storage-mysql component of the clusterpeida.
 	MySQL *MySQL `json:"mysql,omitempty"`
+
+	// CredentialsRotationPeriod is the period at which the password of the built-in database is
+	// rotated. If empty, it's only rotated when the firefly.io/rotate-database-credentials annotation
+	// of the clusterpedia is changed.
+	// +optional
+	CredentialsRotationPeriod *metav1.Duration `json:"credentialsRotationPeriod,omitempty"`
+
+	// Migration is the job which migrates the schema of the built-in database before the components
+	// are upgraded to a new spec.version. If empty, the schema is migrated by the components of the
+	// new version once they start.
+	// +optional
+	Migration *StorageMigration `json:"migration,omitempty"`
+}
+
+// StorageMigration describes the job which migrates the schema of the built-in database to a new
+// version of the clusterpedia. The job mounts the storage config at /etc/clusterpedia/storage, and
+// gets the password of the database by the DB_PASSWORD environment variable.
+type StorageMigration struct {
+	// ImageMeta allows to customize the image of the migration job. The repository defaults to
+	// spec.imageRepository, and the tag defaults to spec.version, so that the schema of the version
+	// being upgraded to is migrated.
+	ImageMeta `json:",inline"`
+
+	// Command is the entrypoint of the migration job.
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// Args are the arguments of the migration job.
+	// +optional
+	Args []string `json:"args,omitempty"`
 }
 
 // Postgres holds settings to clusterpedia-storage-postgres component of the clusterpeida.
@@ -111,6 +276,9 @@ type LocalPostgres struct {
 	// ImageMeta allows to customize the container used for postgres
 	// If empty, `docker.io/library/postgres:10` will be used by default.
 	ImageMeta `json:",inline"`
+
+	// PersistentStorage allows to keep the data of postgres in a PersistentVolumeClaim.
+	PersistentStorage `json:",inline"`
 }
 
 //MySQL holds settings to clusterpedia-storage-mysql component of the clusterpeida.
@@ -118,7 +286,7 @@ type MySQL struct {
 	// Local provides configuration knobs for configuring the built-in mysql instance
 	// Local and External are mutually exclusive
 	// +optional
-	Local *LocalPostgres `json:"local,omitempty"`
+	Local *LocalMySQL `json:"local,omitempty"`
 }
 
 // LocalMySQL describes that firefly should run a mysql cluster in a host cluster.
@@ -126,6 +294,49 @@ type LocalMySQL struct {
 	// ImageMeta allows to customize the container used for mysql
 	// If empty, `docker.io/library/mysql:8` will be used by default.
 	ImageMeta `json:",inline"`
+
+	// PersistentStorage allows to keep the data of mysql in a PersistentVolumeClaim.
+	PersistentStorage `json:",inline"`
+
+	// Operator delegates mysql to the MySQL Operator for Kubernetes, which must be installed on
+	// the host cluster. Instead of running a single mysql pod, firefly creates an InnoDBCluster,
+	// that is a group of mysql servers with automated failover behind MySQL Routers. The image is
+	// only used by the jobs which connect to mysql then, e.g. the credentials rotation.
+	// +optional
+	Operator *MySQLOperator `json:"operator,omitempty"`
+}
+
+// MySQLOperator describes the InnoDBCluster which is created for the MySQL Operator for Kubernetes.
+type MySQLOperator struct {
+	// Instances is the number of mysql servers of the InnoDBCluster. One of them is the primary,
+	// and the others are the replicas which are promoted on failover. Defaults to 3.
+	// +optional
+	Instances *int32 `json:"instances,omitempty"`
+
+	// Routers is the number of MySQL Routers which route the connections to the primary.
+	// Defaults to 1.
+	// +optional
+	Routers *int32 `json:"routers,omitempty"`
+
+	// Version is the version of the mysql servers. If empty, the default version of the operator is used.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// PersistentStorage describes the PersistentVolumeClaim which keeps the data of a built-in database.
+type PersistentStorage struct {
+	// Size is the requested size of the PersistentVolumeClaim. If empty, the data is kept by an
+	// emptyDir and lost once the pod is gone.
+	// The size can be increased later if the storage class allows volume expansion, but it can't
+	// be decreased.
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+
+	// StorageClassName is the name of the StorageClass of the PersistentVolumeClaim.
+	// If empty, the default storage class of the host cluster is used. It can't be changed
+	// once the PersistentVolumeClaim is created.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
 }
 
 // ClusterpediaAPIServerComponent holds settings to clusterpedia-apiserver component of the clusterpeida.
@@ -154,12 +365,18 @@ type ClusterpediaAPIServerComponent struct {
 	// +optional
 	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
 
+	// Logging configures the log verbosity and format of the component. They're passed to the
+	// component by the --v and --logging-format flags, unless the flags are set by extraArgs.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
 	// Compute Resources required by this component.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
-	// FeatureGates enabled by the user.
+	// FeatureGates enabled by the user. They are merged with spec.featureGates and passed to the
+	// component by the --feature-gates flag, unless the flag is set by extraArgs.
 	// +optional
 	FeatureGates map[string]bool `json:"featureGates,omitempty"`
 }
@@ -190,12 +407,18 @@ type ClusterpediaControllerManagerComponent struct {
 	// +optional
 	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
 
+	// Logging configures the log verbosity and format of the component. They're passed to the
+	// component by the --v and --logging-format flags, unless the flags are set by extraArgs.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
 	// Compute Resources required by this component.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
-	// FeatureGates enabled by the user.
+	// FeatureGates enabled by the user. They are merged with spec.featureGates and passed to the
+	// component by the --feature-gates flag, unless the flag is set by extraArgs.
 	// +optional
 	FeatureGates map[string]bool `json:"featureGates,omitempty"`
 }
@@ -226,17 +449,90 @@ type ClusterSynchroManagerComponent struct {
 	// +optional
 	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
 
+	// Logging configures the log verbosity and format of the component. They're passed to the
+	// component by the --v and --logging-format flags, unless the flags are set by extraArgs.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
 	// Compute Resources required by this component.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
-	// FeatureGates enabled by the user.
+	// FeatureGates enabled by the user. They are merged with spec.featureGates and passed to the
+	// component by the --feature-gates flag, unless the flag is set by extraArgs.
 	// More info: https://github.com/clusterpedia-io/clusterpedia/blob/main/pkg/synchromanager/features/features.go
 	// +optional
 	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// Sharding splits the synchronization of the clusters across multiple clustersynchro-managers, one
+	// Deployment per shard, for large fleets. Each of them has replicas pods and its own leader election.
+	// +optional
+	Sharding *ClusterSynchroSharding `json:"sharding,omitempty"`
+}
+
+// ClusterSynchroShardingPolicy is how the clusters are assigned to the shards of the clustersynchro-manager.
+type ClusterSynchroShardingPolicy string
+
+const (
+	// ClusterSynchroShardingHash assigns the clusters to the shards by the hash of their names.
+	ClusterSynchroShardingHash ClusterSynchroShardingPolicy = "Hash"
+	// ClusterSynchroShardingStatic assigns the clusters to the shards by spec.clusterSynchroManager.sharding.assignments,
+	// and the clusters not listed there by the hash of their names.
+	ClusterSynchroShardingStatic ClusterSynchroShardingPolicy = "Static"
+)
+
+// ClusterSynchroSharding describes the shards of the clustersynchro-manager.
+//
+// The clustersynchro-manager of the shard i is the Deployment clusterpedia-clustersynchro-manager-<i>. It's told
+// its identity by the SHARD_INDEX and SHARD_COUNT environment variables, which extraArgs may refer to as
+// $(SHARD_INDEX) and $(SHARD_COUNT). The PediaClusters registered by firefly, that is in the Controller cluster
+// registration mode, are labeled with their shards by install.firefly.io/clustersynchro-shard, so that the
+// clustersynchro-managers which support sharding only synchronize the clusters of their own shards.
+type ClusterSynchroSharding struct {
+	// Shards is the number of the shards.
+	// +kubebuilder:validation:Minimum=1
+	Shards int32 `json:"shards"`
+
+	// Policy is how the clusters are assigned to the shards. Defaults to Hash.
+	// +kubebuilder:validation:Enum=Hash;Static
+	// +optional
+	Policy ClusterSynchroShardingPolicy `json:"policy,omitempty"`
+
+	// Assignments are the clusters pinned to the shards by the Static policy.
+	// +listType=map
+	// +listMapKey=shard
+	// +optional
+	Assignments []ClusterSynchroShardAssignment `json:"assignments,omitempty"`
+}
+
+// ClusterSynchroShardAssignment pins clusters to a shard of the clustersynchro-manager.
+type ClusterSynchroShardAssignment struct {
+	// Shard is the index of the shard, from 0 to shards-1.
+	// +kubebuilder:validation:Minimum=0
+	Shard int32 `json:"shard"`
+
+	// Clusters are the names of the clusters synchronized by the shard.
+	Clusters []string `json:"clusters"`
 }
 
+const (
+	// ClusterpediaConditionReady indicates whether the latest generation of the clusterpedia is installed
+	// and healthy. It's summarized from the other conditions, its reason is the phase of the clusterpedia.
+	ClusterpediaConditionReady = "Ready"
+
+	// ClusterpediaConditionPaused indicates whether the reconciliation of the clusterpedia is paused.
+	ClusterpediaConditionPaused = "Paused"
+
+	// ClusterpediaConditionStorageResized indicates whether the PersistentVolumeClaim of the built-in
+	// database has the size requested by spec.storage.
+	ClusterpediaConditionStorageResized = "StorageResized"
+
+	// ClusterpediaConditionAPIServiceAvailable indicates whether the clusterpedia APIService is registered
+	// into the control plane and the aggregated clusterpedia api is discoverable through it.
+	ClusterpediaConditionAPIServiceAvailable = "APIServiceAvailable"
+)
+
 // ClusterpediaStatus is the status for a Clusterpedia resource
 type ClusterpediaStatus struct {
 	// observedGeneration is the most recent generation observed for this Clusterpedia. It corresponds to the
@@ -244,12 +540,70 @@ type ClusterpediaStatus struct {
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// Phase summarizes the status of the clusterpedia. The conditions tell the details.
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+
+	// Version is the version of the clusterpedia which has been installed successfully.
+	// It differs from spec.version while the clusterpedia is being upgraded.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// DatabaseCredentialsRotationTime is the time at which the password of the built-in database
+	// was rotated last time.
+	// +optional
+	DatabaseCredentialsRotationTime *metav1.Time `json:"databaseCredentialsRotationTime,omitempty"`
+
+	// Upgrade is the progress of the latest upgrade of the clusterpedia to a new spec.version.
+	// +optional
+	Upgrade *ClusterpediaUpgrade `json:"upgrade,omitempty"`
+
 	// Represents the latest available observations of a clusterpedia's current state.
 	// +patchMergeKey=type
 	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// ClusterpediaUpgradePhase is the phase of the upgrade of a clusterpedia.
+type ClusterpediaUpgradePhase string
+
+const (
+	// ClusterpediaUpgradeMigrating means that the schema of the storage is being migrated to the new version.
+	ClusterpediaUpgradeMigrating ClusterpediaUpgradePhase = "Migrating"
+	// ClusterpediaUpgradeRollingOut means that the components are being rolled out to the new version.
+	ClusterpediaUpgradeRollingOut ClusterpediaUpgradePhase = "RollingOut"
+	// ClusterpediaUpgradeCompleted means that all components are running the new version.
+	ClusterpediaUpgradeCompleted ClusterpediaUpgradePhase = "Completed"
+	// ClusterpediaUpgradeFailed means that the migration failed. The components keep running the old
+	// version until spec.version is changed.
+	ClusterpediaUpgradeFailed ClusterpediaUpgradePhase = "Failed"
+)
+
+// ClusterpediaUpgrade is the progress of the upgrade of a clusterpedia.
+type ClusterpediaUpgrade struct {
+	// FromVersion is the version which the clusterpedia is upgraded from.
+	FromVersion string `json:"fromVersion"`
+
+	// ToVersion is the version which the clusterpedia is upgraded to.
+	ToVersion string `json:"toVersion"`
+
+	// Phase is the phase of the upgrade.
+	Phase ClusterpediaUpgradePhase `json:"phase"`
+
+	// Message is a human readable message about the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is the time at which the upgrade was started.
+	StartTime metav1.Time `json:"startTime"`
+
+	// CompletionTime is the time at which the upgrade was completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // ClusterpediaList is a list of Clusterpedia resources
@@ -0,0 +1,196 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.agent.version`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClusterRegistration is a specification for a ClusterRegistration resource.
+// A cluster registration joins a member cluster into a hosted karmada, which is the
+// equivalent of `karmadactl join` in push mode or deploying karmada-agent in pull mode,
+// and unjoins the member cluster when it's deleted.
+type ClusterRegistration struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the ClusterRegistration.
+	// +optional
+	Spec ClusterRegistrationSpec `json:"spec"`
+	// Most recently observed status of the ClusterRegistration.
+	// +optional
+	Status ClusterRegistrationStatus `json:"status"`
+}
+
+// ClusterSyncMode describes the mode of synchronization between the karmada and a member cluster.
+// +kubebuilder:validation:Enum=Push;Pull
+type ClusterSyncMode string
+
+const (
+	// ClusterSyncModePush means the karmada control plane pushes the resources to the member cluster.
+	ClusterSyncModePush ClusterSyncMode = "Push"
+
+	// ClusterSyncModePull means the karmada-agent deployed in the member cluster pulls the
+	// resources from the karmada control plane.
+	ClusterSyncModePull ClusterSyncMode = "Pull"
+)
+
+// ClusterRegistrationSpec is the spec for a ClusterRegistration resource
+type ClusterRegistrationSpec struct {
+	// Karmada refers to a Karmada in the same namespace which the member cluster joins.
+	Karmada corev1.LocalObjectReference `json:"karmada"`
+
+	// ClusterName is the name of the Cluster object created in the karmada.
+	// Defaults to the name of the ClusterRegistration.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// KubeconfigSecret refers to a Secret in the same namespace whose `kubeconfig` field holds
+	// the kubeconfig of the member cluster. The kubeconfig must be allowed to manage namespaces,
+	// service accounts, RBAC and deployments of the member cluster.
+	KubeconfigSecret corev1.LocalObjectReference `json:"kubeconfigSecret"`
+
+	// SyncMode describes how the karmada synchronizes resources with the member cluster.
+	// Defaults to Push.
+	// +kubebuilder:default=Push
+	// +optional
+	SyncMode ClusterSyncMode `json:"syncMode,omitempty"`
+
+	// APIEndpoint is the address of the kube-apiserver of the member cluster which is
+	// accessed by the karmada. Defaults to the server of the kubeconfig.
+	// +optional
+	APIEndpoint string `json:"apiEndpoint,omitempty"`
+
+	// Agent holds settings to the karmada-agent deployed into the member cluster.
+	// It's ignored unless the sync mode is Pull. The agent is upgraded along with the karmada
+	// unless its image tag is set.
+	// +optional
+	Agent ClusterAgentComponent `json:"agent,omitempty"`
+}
+
+// ClusterAgentComponent holds settings to the karmada-agent component of a member cluster.
+type ClusterAgentComponent struct {
+	// ImageMeta allows to customize the image used for the karmada-agent component.
+	// The tag defaults to the installed version of the karmada.
+	ImageMeta `json:",inline"`
+
+	// Number of desired pods. This is a pointer to distinguish between explicit
+	// zero and not specified. Defaults to 1.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// ExtraArgs is an extra set of flags to pass to the karmada-agent component or
+	// override. A key in this map is the flag name as it appears on the command line except
+	// without leading dash(es).
+	// +optional
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+
+	// Compute Resources required by this component.
+	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+const (
+	// ClusterRegistrationConditionReady indicates whether the member cluster is joined and, in pull mode,
+	// its karmada-agent is ready. It's summarized from the other conditions, its reason is the phase of
+	// the registration.
+	ClusterRegistrationConditionReady = "Ready"
+
+	// ClusterRegistrationConditionJoined indicates whether the member cluster is joined into the karmada.
+	ClusterRegistrationConditionJoined = "Joined"
+
+	// ClusterRegistrationConditionAgentReady indicates whether all the replicas of karmada-agent in the
+	// member cluster are updated to the version of the karmada and available. It's only set in pull mode.
+	ClusterRegistrationConditionAgentReady = "AgentReady"
+)
+
+// ClusterRegistrationStatus is the status for a ClusterRegistration resource
+type ClusterRegistrationStatus struct {
+	// observedGeneration is the most recent generation observed for this ClusterRegistration. It corresponds to the
+	// ClusterRegistration's generation, which is updated on mutation by the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase summarizes the status of the registration. The conditions tell the details.
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+
+	// ClusterName is the name of the Cluster object which has been created in the karmada.
+	// It's used to unjoin the member cluster even if spec.clusterName has been changed.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// SyncMode is the sync mode with which the member cluster has been joined.
+	// +optional
+	SyncMode ClusterSyncMode `json:"syncMode,omitempty"`
+
+	// Agent is the most recently observed status of karmada-agent in the member cluster.
+	// It's only set in pull mode.
+	// +optional
+	Agent *ClusterAgentStatus `json:"agent,omitempty"`
+
+	// Represents the latest available observations of a cluster registration's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ClusterAgentStatus is the observed status of karmada-agent in a member cluster.
+type ClusterAgentStatus struct {
+	// Version is the image tag of karmada-agent which is deployed into the member cluster.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Replicas is the number of desired replicas of karmada-agent.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// UpdatedReplicas is the number of replicas of karmada-agent which run the desired version.
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+
+	// AvailableReplicas is the number of available replicas of karmada-agent.
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterRegistrationList is a list of ClusterRegistration resources
+type ClusterRegistrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ClusterRegistration `json:"items"`
+}
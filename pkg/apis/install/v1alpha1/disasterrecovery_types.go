@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DisasterRecovery describes the backups of a karmada taken by Velero, which must be installed on
+// the host cluster. The backups cover the namespace of the karmada, including the certificates and
+// the data volume of etcd, which should be set by spec.etcd.local.dataVolume. A snapshot of etcd is
+// saved into its data volume before each backup, so that the data can be recovered with `etcdctl
+// snapshot restore` if the volume snapshot isn't consistent. It's ignored if Chart is set.
+type DisasterRecovery struct {
+	// Schedule is the cron expression the backups are taken at, e.g. `0 */6 * * *`.
+	Schedule string `json:"schedule"`
+
+	// TTL is how long the backups are kept. Defaults to the TTL of Velero, which is 30 days.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// StorageLocation is the name of the Velero BackupStorageLocation the backups are stored in.
+	// Defaults to the default location of Velero.
+	// +optional
+	StorageLocation string `json:"storageLocation,omitempty"`
+
+	// VolumeSnapshotLocations are the names of the Velero VolumeSnapshotLocations the snapshots
+	// of the volumes are stored in. Defaults to the default locations of Velero.
+	// +optional
+	VolumeSnapshotLocations []string `json:"volumeSnapshotLocations,omitempty"`
+}
+
+// KarmadaRestorePhase is the phase of the restore of a karmada from a backup.
+type KarmadaRestorePhase string
+
+const (
+	// KarmadaRestoreScalingDown means that etcd and its data volume are being deleted, so that the
+	// volume can be restored. The karmada isn't reconciled until etcd is started again.
+	KarmadaRestoreScalingDown KarmadaRestorePhase = "ScalingDown"
+	// KarmadaRestoreRestoringVolumes means that Velero is restoring the volumes and the secrets.
+	KarmadaRestoreRestoringVolumes KarmadaRestorePhase = "RestoringVolumes"
+	// KarmadaRestoreStartingEtcd means that etcd is being started with the restored data.
+	KarmadaRestoreStartingEtcd KarmadaRestorePhase = "StartingEtcd"
+	// KarmadaRestoreRestartingAPIServers means that the apiservers are being restarted, so that
+	// their caches are rebuilt from the restored data.
+	KarmadaRestoreRestartingAPIServers KarmadaRestorePhase = "RestartingAPIServers"
+	// KarmadaRestoreCompleted means that the karmada has been restored.
+	KarmadaRestoreCompleted KarmadaRestorePhase = "Completed"
+	// KarmadaRestoreFailed means that the restore failed.
+	KarmadaRestoreFailed KarmadaRestorePhase = "Failed"
+)
+
+// KarmadaRestoreStatus describes the last restore of a karmada from a backup.
+type KarmadaRestoreStatus struct {
+	// BackupName is the name of the Velero Backup the karmada is restored from.
+	BackupName string `json:"backupName"`
+
+	// RestoreName is the name of the Velero Restore which restores the volumes and the secrets.
+	// +optional
+	RestoreName string `json:"restoreName,omitempty"`
+
+	// Phase is the phase of the restore.
+	Phase KarmadaRestorePhase `json:"phase"`
+
+	// Message is a human readable message indicating details about the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is the time at which the restore started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// LastTransitionTime is the time at which the restore entered its phase.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EstimatorService holds settings of the Services which expose the karmada-scheduler-estimators.
+type EstimatorService struct {
+	// Type determines how the estimators are exposed. Valid options are ClusterIP,
+	// NodePort and LoadBalancer. Defaults to ClusterIP.
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+
+	// Port is the port of the Services. The karmada-scheduler and the karmada-descheduler connect
+	// to all the estimators on this port, so it can't be overridden per cluster. Defaults to 10352.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// EstimatorGRPCTLS holds the TLS settings of the gRPC server of the karmada-scheduler-estimators.
+// The karmada-scheduler-estimator supports them since karmada v1.7, and the karmada-scheduler and
+// the karmada-descheduler need to be configured by their extraArgs to connect to it over TLS.
+type EstimatorGRPCTLS struct {
+	// SecretName is the name of the Secret in the karmada namespace which holds the serving
+	// certificate and key of the estimators in its tls.crt and tls.key, and the CA which
+	// verifies the client certificates in its ca.crt.
+	SecretName string `json:"secretName"`
+
+	// InsecureSkipClientVerify indicates whether the estimators skip verifying the client
+	// certificates, in which case the Secret doesn't need to hold a ca.crt.
+	// +optional
+	InsecureSkipClientVerify bool `json:"insecureSkipClientVerify,omitempty"`
+}
+
+// EstimatorClusterOverride overrides the settings of the karmada-scheduler-estimator of a member cluster.
+// The unset fields fall back to the settings of the karmada-scheduler-estimator component.
+type EstimatorClusterOverride struct {
+	// ClusterName is the name of the member cluster whose estimator is overridden.
+	ClusterName string `json:"clusterName"`
+
+	// Number of desired pods of the estimator.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// ServiceType determines how the estimator is exposed.
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +optional
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// ServerPort is the port the gRPC server of the estimator listens on. The Service of the
+	// estimator keeps exposing it on the port of the component Service.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	ServerPort int32 `json:"serverPort,omitempty"`
+
+	// GRPCTLS overrides the TLS settings of the gRPC server of the estimator.
+	// +optional
+	GRPCTLS *EstimatorGRPCTLS `json:"grpcTLS,omitempty"`
+
+	// ExtraArgs is an extra set of flags to pass to the estimator. They're merged with
+	// and take precedence over the extraArgs of the component.
+	// +optional
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+}
+
+// EstimatorFailover is the action taken when an estimator is persistently unhealthy.
+type EstimatorFailover string
+
+const (
+	// EstimatorFailoverNone only reports the unhealthy estimators.
+	EstimatorFailoverNone EstimatorFailover = "None"
+
+	// EstimatorFailoverRecreatePods deletes the pods of an unhealthy estimator every time it fails
+	// failureThreshold health checks in a row, so that they're recreated.
+	EstimatorFailoverRecreatePods EstimatorFailover = "RecreatePods"
+
+	// EstimatorFailoverFallback recreates the pods of an unhealthy estimator like RecreatePods. If the
+	// estimator is still unhealthy after its pods are recreated, its Service is removed, so that the
+	// karmada-scheduler falls back to the general estimation for the member cluster instead of waiting
+	// for the estimator. The Service is restored once the estimator is healthy again.
+	EstimatorFailoverFallback EstimatorFailover = "Fallback"
+)
+
+// EstimatorHealthCheck holds the settings of the health checks of the karmada-scheduler-estimators.
+// The health endpoint and the metrics of the ready pods of each estimator are scraped periodically.
+// An estimator is unhealthy if none of its pods is ready, a health endpoint fails, or all of the
+// estimating requests served since the last check have failed.
+type EstimatorHealthCheck struct {
+	// PeriodSeconds is how often the estimators are checked. Defaults to 30.
+	// +kubebuilder:validation:Minimum=5
+	// +optional
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+
+	// FailureThreshold is the number of checks in a row an estimator fails before it's considered
+	// persistently unhealthy and the failover is taken. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+
+	// Failover is the action taken when an estimator is persistently unhealthy, one of None,
+	// RecreatePods and Fallback. Defaults to Fallback.
+	// +kubebuilder:validation:Enum=None;RecreatePods;Fallback
+	// +optional
+	Failover EstimatorFailover `json:"failover,omitempty"`
+}
+
+// EstimatorStatus is the observed health of the karmada-scheduler-estimator of a member cluster.
+type EstimatorStatus struct {
+	// ClusterName is the name of the member cluster of the estimator.
+	ClusterName string `json:"clusterName"`
+
+	// Healthy indicates whether the estimator passed its last health check.
+	Healthy bool `json:"healthy"`
+
+	// ConsecutiveFailures is the number of health checks the estimator has failed in a row.
+	// +optional
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+
+	// FallenBack indicates that the Service of the estimator is removed by the Fallback failover,
+	// so that the karmada-scheduler estimates the replicas of the member cluster by the general
+	// estimation.
+	// +optional
+	FallenBack bool `json:"fallenBack,omitempty"`
+
+	// Reason is a brief CamelCase reason of the last health check result.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable message of the last health check result.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime is the last time the estimator turned healthy or unhealthy.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
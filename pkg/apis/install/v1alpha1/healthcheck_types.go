@@ -0,0 +1,30 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// HealthCheck holds configuration for the health probing of the control plane components.
+// The karmada-apiserver, the etcd and the karmada-webhook are always probed once the karmada
+// is installed, and their health is reported by the conditions of the karmada.
+type HealthCheck struct {
+	// AutoRemediation indicates whether firefly remediates the unhealthy components automatically.
+	// The pods of the crash-looping components are deleted, so that they're restarted right away
+	// instead of waiting for their growing back-off, and the Secret `karmada-kubeconfig` used by
+	// the components is re-rendered from the karmada certificates if it's missing or broken.
+	// Nothing is remediated while the karmada is paused.
+	// +optional
+	AutoRemediation bool `json:"autoRemediation,omitempty"`
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Hooks are the Jobs run at the phases of the lifecycle of the karmada, e.g. to migrate the
+// schema of a database before an upgrade or to run smoke tests after it. The hooks of a phase are
+// run in order, each once its predecessor is complete, and the phase is gated on them. They're
+// ignored if Chart is set.
+type Hooks struct {
+	// PreInstall hooks are run before the components of a new karmada are installed.
+	// +optional
+	PreInstall []Hook `json:"preInstall,omitempty"`
+
+	// PostInstall hooks are run after the components of a new karmada are installed, the karmada
+	// is reported as installed once they're complete.
+	// +optional
+	PostInstall []Hook `json:"postInstall,omitempty"`
+
+	// PreUpgrade hooks are run before the components are upgraded to spec.karmadaVersion.
+	// +optional
+	PreUpgrade []Hook `json:"preUpgrade,omitempty"`
+
+	// PostUpgrade hooks are run after the components are upgraded to spec.karmadaVersion, the
+	// karmada is reported as upgraded once they're complete.
+	// +optional
+	PostUpgrade []Hook `json:"postUpgrade,omitempty"`
+}
+
+// HookFailurePolicy describes how a failed hook is handled.
+type HookFailurePolicy string
+
+const (
+	// HookFailurePolicyFail holds the lifecycle phase back until the failed Job is deleted,
+	// which makes the hook run again.
+	HookFailurePolicyFail HookFailurePolicy = "Fail"
+	// HookFailurePolicyIgnore only reports the failure and goes on with the lifecycle phase.
+	HookFailurePolicyIgnore HookFailurePolicy = "Ignore"
+)
+
+// Hook is a Job run at a phase of the lifecycle of the karmada. Exactly one of Template and
+// JobRef must be set.
+type Hook struct {
+	// Name is the name of the hook, which is unique within its phase. The Job created from the
+	// template is named `<karmada name>-<phase>-<hook name>`, e.g. `karmada-pre-upgrade-migrate`.
+	Name string `json:"name"`
+
+	// Template is the template of the Job created for the hook. The Job is created again for each
+	// upgrade, and is owned by the karmada.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	// +optional
+	Template *batchv1.JobTemplateSpec `json:"template,omitempty"`
+
+	// JobRef references an existing Job in the namespace of the karmada, which is created by the
+	// user or a third party, e.g. a CD pipeline. The phase is gated on its completion.
+	// +optional
+	JobRef *corev1.LocalObjectReference `json:"jobRef,omitempty"`
+
+	// FailurePolicy describes how the failure of the hook is handled. Defaults to Fail.
+	// +kubebuilder:validation:Enum=Fail;Ignore
+	// +optional
+	FailurePolicy HookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// HookPhase is a phase of the lifecycle of the karmada at which hooks are run.
+type HookPhase string
+
+const (
+	HookPhasePreInstall  HookPhase = "pre-install"
+	HookPhasePostInstall HookPhase = "post-install"
+	HookPhasePreUpgrade  HookPhase = "pre-upgrade"
+	HookPhasePostUpgrade HookPhase = "post-upgrade"
+)
+
+// HookResult is the result of the run of a hook.
+type HookResult string
+
+const (
+	HookResultRunning   HookResult = "Running"
+	HookResultSucceeded HookResult = "Succeeded"
+	HookResultFailed    HookResult = "Failed"
+)
+
+// HookStatus describes the last run of a hook.
+type HookStatus struct {
+	// Phase is the lifecycle phase the hook is run at.
+	Phase HookPhase `json:"phase"`
+
+	// Name is the name of the hook.
+	Name string `json:"name"`
+
+	// Version is the version of the karmada the hook is run for, that is the installed or the
+	// upgraded version.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// JobName is the name of the Job of the hook.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+
+	// Result is the result of the run, one of Running, Succeeded or Failed.
+	// +optional
+	Result HookResult `json:"result,omitempty"`
+
+	// Message is a human readable message indicating details about the run.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime is the last time the result of the run changed.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
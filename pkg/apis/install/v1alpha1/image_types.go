@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ImagePolicy describes how the images of the components are resolved and verified before the
+// components are rolled out to them. It's ignored if Chart is set.
+type ImagePolicy struct {
+	// PinDigests indicates that the tags of the images are resolved to digests when the components are
+	// reconciled, and the components run the images by digest. A tag is resolved once, when it's first
+	// used, so that the components keep running the same images even if the tag is moved in the registry.
+	// The resolved digests are recorded in status.resolvedImages. Only the registries which allow anonymous
+	// pulls are supported.
+	// +optional
+	PinDigests bool `json:"pinDigests,omitempty"`
+
+	// Verification requires the images to be signed by cosign with one of the given keys. The signatures
+	// are verified when the digests are resolved, and the components are not rolled out to the images
+	// failing the verification. It implies PinDigests.
+	// +optional
+	Verification *ImageVerification `json:"verification,omitempty"`
+}
+
+// ImageVerification holds the keys the signatures of the images are verified with.
+type ImageVerification struct {
+	// PublicKeysSecretRef refers to a Secret in the namespace of the karmada whose values are the
+	// PEM-encoded public keys, e.g. the cosign.pub generated by `cosign generate-key-pair`.
+	// Keyless signatures are not supported.
+	PublicKeysSecretRef corev1.LocalObjectReference `json:"publicKeysSecretRef"`
+}
+
+// ResolvedImage is an image whose tag is resolved to a digest.
+type ResolvedImage struct {
+	// Image is the image with a tag, as it's generated from the spec.
+	Image string `json:"image"`
+
+	// Digest is the digest of the manifest the tag pointed to when it was resolved.
+	Digest string `json:"digest"`
+
+	// Verified indicates that the signature of the image has been verified.
+	// +optional
+	Verified bool `json:"verified,omitempty"`
+}
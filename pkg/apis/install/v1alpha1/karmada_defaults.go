@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	utilpointer "k8s.io/utils/pointer"
 )
@@ -53,12 +54,27 @@ func SetDefaults_Karmada(obj *Karmada) {
 	}
 
 	apiServer := &obj.Spec.APIServer
+	if apiServer.ServiceType == "" {
+		apiServer.ServiceType = corev1.ServiceTypeClusterIP
+	}
 	if apiServer.KubeAPIServer.Replicas == nil {
 		apiServer.KubeAPIServer.Replicas = utilpointer.Int32(1)
 	}
 	if apiServer.KarmadaAggregratedAPIServer.Replicas == nil {
 		apiServer.KarmadaAggregratedAPIServer.Replicas = utilpointer.Int32(1)
 	}
+	if apiServer.KarmadaSearch.Enable == nil {
+		apiServer.KarmadaSearch.Enable = utilpointer.Bool(false)
+	}
+	if apiServer.KarmadaSearch.Replicas == nil {
+		apiServer.KarmadaSearch.Replicas = utilpointer.Int32(1)
+	}
+	if apiServer.KarmadaMetricsAdapter.Enable == nil {
+		apiServer.KarmadaMetricsAdapter.Enable = utilpointer.Bool(false)
+	}
+	if apiServer.KarmadaMetricsAdapter.Replicas == nil {
+		apiServer.KarmadaMetricsAdapter.Replicas = utilpointer.Int32(1)
+	}
 
 	webhook := &obj.Spec.Webhook
 	if webhook.KarmadaWebhook.Replicas == nil {
@@ -66,6 +82,9 @@ func SetDefaults_Karmada(obj *Karmada) {
 	}
 
 	controllerManager := &obj.Spec.ControllerManager
+	if controllerManager.KubeControllerManager.Enable == nil {
+		controllerManager.KubeControllerManager.Enable = utilpointer.Bool(true)
+	}
 	if controllerManager.KubeControllerManager.Replicas == nil {
 		controllerManager.KubeControllerManager.Replicas = utilpointer.Int32(1)
 	}
@@ -75,9 +94,21 @@ func SetDefaults_Karmada(obj *Karmada) {
 	if controllerManager.KarmadaControllerManager.Replicas == nil {
 		controllerManager.KarmadaControllerManager.Replicas = utilpointer.Int32(1)
 	}
+	if controllerManager.FireflyKarmadaManager.Enable == nil {
+		controllerManager.FireflyKarmadaManager.Enable = utilpointer.Bool(true)
+	}
 	if controllerManager.FireflyKarmadaManager.Replicas == nil {
 		controllerManager.FireflyKarmadaManager.Replicas = utilpointer.Int32(1)
 	}
+	if controllerManager.MulticlusterCloudProvider.Enable == nil {
+		controllerManager.MulticlusterCloudProvider.Enable = utilpointer.Bool(false)
+	}
+	if controllerManager.MulticlusterCloudProvider.Provider == "" {
+		controllerManager.MulticlusterCloudProvider.Provider = "fake"
+	}
+	if controllerManager.MulticlusterCloudProvider.Replicas == nil {
+		controllerManager.MulticlusterCloudProvider.Replicas = utilpointer.Int32(1)
+	}
 
 	scheduler := &obj.Spec.Scheduler
 	if scheduler.KarmadaScheduler.Replicas == nil {
@@ -89,7 +120,36 @@ func SetDefaults_Karmada(obj *Karmada) {
 	if scheduler.KarmadaDescheduler.Replicas == nil {
 		scheduler.KarmadaDescheduler.Replicas = utilpointer.Int32(1)
 	}
+	if scheduler.KarmadaSchedulerEstimator.Enable == nil {
+		scheduler.KarmadaSchedulerEstimator.Enable = utilpointer.Bool(true)
+	}
 	if scheduler.KarmadaSchedulerEstimator.Replicas == nil {
 		scheduler.KarmadaSchedulerEstimator.Replicas = utilpointer.Int32(1)
 	}
+	if scheduler.KarmadaSchedulerEstimator.Service.Type == "" {
+		scheduler.KarmadaSchedulerEstimator.Service.Type = corev1.ServiceTypeClusterIP
+	}
+	if scheduler.KarmadaSchedulerEstimator.Service.Port == 0 {
+		scheduler.KarmadaSchedulerEstimator.Service.Port = 10352
+	}
+	if healthCheck := scheduler.KarmadaSchedulerEstimator.HealthCheck; healthCheck != nil {
+		if healthCheck.PeriodSeconds == 0 {
+			healthCheck.PeriodSeconds = 30
+		}
+		if healthCheck.FailureThreshold == 0 {
+			healthCheck.FailureThreshold = 3
+		}
+		if healthCheck.Failover == "" {
+			healthCheck.Failover = EstimatorFailoverFallback
+		}
+	}
+
+	if canary := obj.Spec.UpdateStrategy.Canary; canary != nil {
+		if canary.Replicas == nil {
+			canary.Replicas = utilpointer.Int32(1)
+		}
+		if canary.ProgressDeadlineSeconds == nil {
+			canary.ProgressDeadlineSeconds = utilpointer.Int32(300)
+		}
+	}
 }
@@ -18,11 +18,18 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.karmadaVersion`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Karmada is a specification for a Karmada resource
 type Karmada struct {
@@ -42,6 +49,13 @@ type Karmada struct {
 
 // KarmadaSpec is the spec for a Karmada resource
 type KarmadaSpec struct {
+	// Paused indicates that the reconciliation of the karmada is paused. While it's paused,
+	// firefly doesn't create, update or delete any resources of the karmada, but still
+	// updates its status, so that operators can debug or maintain it manually.
+	// Deleting the karmada is still handled.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
 	// Etcd holds configuration for etcd.
 	// +optional
 	Etcd Etcd `json:"etcd,omitempty"`
@@ -105,6 +119,10 @@ type KarmadaSpec struct {
 	// +optional
 	FireflyImageRepository string `json:"fireflyImageRepository,omitempty"`
 
+	// ImagePolicy describes how the images of the components are resolved and verified.
+	// +optional
+	ImagePolicy *ImagePolicy `json:"imagePolicy,omitempty"`
+
 	// FeatureGates enabled by the user.
 	// If you don't know that a feature gate should be applied to which components, you can
 	// use this field to enable or disable the feature gate for all the components of the karmada instance.
@@ -115,6 +133,215 @@ type KarmadaSpec struct {
 	// More info: https://github.com/karmada-io/karmada/blob/master/pkg/features/features.go
 	// +optional
 	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// Chart represents a helm chart which the karmada instance is installed from.
+	// If set, the chart is rendered and applied into the namespace of the karmada
+	// instead of the built-in manifests of firefly. The container registry is passed to
+	// the chart as the value `global.imageRegistry`.
+	// +optional
+	Chart *ChartReference `json:"chart,omitempty"`
+
+	// ValuesOverride holds the values which override the values generated from the spec
+	// when rendering the chart. It's an escape hatch for the settings which are not
+	// supported by the spec. It's ignored if Chart is not set.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	ValuesOverride *runtime.RawExtension `json:"valuesOverride,omitempty"`
+
+	// Patches is a list of patches applied to the manifests generated by firefly
+	// or rendered from the chart, in order.
+	// +optional
+	Patches []Patch `json:"patches,omitempty"`
+
+	// Kubeconfig contains settings to the kubeconfig Secrets published in the namespace of the
+	// karmada. The admin kubeconfig is always published into the Secret `<name>-admin-kubeconfig`.
+	// +optional
+	Kubeconfig KubeconfigSpec `json:"kubeconfig,omitempty"`
+
+	// Monitoring holds configuration for the monitoring of the components by Prometheus.
+	// +optional
+	Monitoring Monitoring `json:"monitoring,omitempty"`
+
+	// HealthCheck holds configuration for the health probing of the control plane components.
+	// +optional
+	HealthCheck HealthCheck `json:"healthCheck,omitempty"`
+
+	// AvailabilityPolicy describes how the components running more than one replica are kept
+	// available while the host cluster is disrupted.
+	// +optional
+	AvailabilityPolicy AvailabilityPolicy `json:"availabilityPolicy,omitempty"`
+
+	// NetworkPolicy describes the hardening of the traffic between the components.
+	// +optional
+	NetworkPolicy NetworkPolicy `json:"networkPolicy,omitempty"`
+
+	// DisasterRecovery describes the backups of the karmada taken by Velero.
+	// +optional
+	DisasterRecovery *DisasterRecovery `json:"disasterRecovery,omitempty"`
+
+	// UpdateStrategy describes how the changes of the components are rolled out.
+	// +optional
+	UpdateStrategy UpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// Hooks are the Jobs run at the phases of the lifecycle of the karmada, e.g. before it's upgraded.
+	// +optional
+	Hooks Hooks `json:"hooks,omitempty"`
+
+	// HostClusters spreads the apiservers of the karmada and the members of its local etcd across
+	// additional host clusters, e.g. in other regions, so that the karmada stays available if the host
+	// cluster is lost. It requires the MultiHostCluster feature gate of the firefly-controller-manager.
+	// +optional
+	HostClusters *KarmadaHostClusters `json:"hostClusters,omitempty"`
+
+	// Seed is the tenant structure seeded into the karmada-apiserver once the control plane is up, e.g. the
+	// namespaces of the teams along with their quotas and default propagation policies.
+	// +optional
+	Seed *KarmadaSeed `json:"seed,omitempty"`
+
+	// RegistryMirrors maps the regions of the member clusters to the registries nearest to them. The
+	// firefly-karmada-manager generates a ClusterOverridePolicy in the karmada-apiserver which replaces
+	// the registry of the images of the workloads propagated to the member clusters of a region with
+	// its mirror, so that they're pulled from the nearest registry.
+	// +listType=map
+	// +listMapKey=region
+	// +optional
+	RegistryMirrors []RegistryMirror `json:"registryMirrors,omitempty"`
+
+	// Verification is the smoke test suite run against the control plane once the karmada is installed
+	// or upgraded. If empty, no tests are run.
+	// +optional
+	Verification *Verification `json:"verification,omitempty"`
+}
+
+// RegistryMirror is the mirror registry of a region.
+type RegistryMirror struct {
+	// Region is the region of the member clusters, that is spec.region of their Clusters in the karmada.
+	Region string `json:"region"`
+
+	// Registry is the mirror registry, e.g. `registry.eu-west-1.example.com`, which replaces the registries
+	// of all the images of the workloads, so it's expected to mirror every registry they're pulled from.
+	Registry string `json:"registry"`
+}
+
+// KarmadaSeed describes the objects seeded into the karmada-apiserver of a karmada.
+//
+// The objects are applied with server-side apply whenever the seed of a namespace is changed, so that
+// the platform teams can pre-provision the tenants, and the tenants are free to change the other fields
+// afterwards. The namespaces removed from the seed are left in the karmada.
+type KarmadaSeed struct {
+	// Namespaces are the namespaces seeded into the karmada-apiserver.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Namespaces []KarmadaSeedNamespace `json:"namespaces,omitempty"`
+}
+
+// KarmadaSeedNamespace is a namespace seeded into the karmada-apiserver, whose ResourceQuota, LimitRange
+// and PropagationPolicy are all named `firefly-seed`.
+type KarmadaSeedNamespace struct {
+	// Name is the name of the namespace.
+	Name string `json:"name"`
+
+	// Labels are the labels set on the namespace.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// ResourceQuota is the spec of the ResourceQuota of the namespace. The ResourceQuota is only
+	// enforced if the ResourceQuota admission plugin of the karmada-apiserver is enabled.
+	// +optional
+	ResourceQuota *corev1.ResourceQuotaSpec `json:"resourceQuota,omitempty"`
+
+	// LimitRange is the spec of the LimitRange of the namespace.
+	// +optional
+	LimitRange *corev1.LimitRangeSpec `json:"limitRange,omitempty"`
+
+	// PropagationPolicy is the default PropagationPolicy of the namespace.
+	// +optional
+	PropagationPolicy *KarmadaSeedPropagationPolicy `json:"propagationPolicy,omitempty"`
+}
+
+// KarmadaSeedPropagationPolicy describes the default PropagationPolicy of a seeded namespace, which
+// propagates all the resources of the given kinds in the namespace to the given clusters.
+type KarmadaSeedPropagationPolicy struct {
+	// ResourceSelectors are the kinds of the resources which are propagated.
+	// +kubebuilder:validation:MinItems=1
+	ResourceSelectors []KarmadaSeedResourceSelector `json:"resourceSelectors"`
+
+	// Clusters are the member clusters the resources are propagated to. If empty, the resources are
+	// propagated to all the member clusters.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// PropagateDeps indicates that the dependencies of the resources, e.g. the ConfigMaps and the Secrets
+	// mounted by a Deployment, are propagated along with them.
+	// +optional
+	PropagateDeps bool `json:"propagateDeps,omitempty"`
+}
+
+// KarmadaSeedResourceSelector selects the resources of a kind.
+type KarmadaSeedResourceSelector struct {
+	// APIVersion is the group version of the resources, e.g. `apps/v1`.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind is the kind of the resources, e.g. `Deployment`.
+	Kind string `json:"kind"`
+}
+
+// KarmadaHostClusters describes the additional host clusters of a karmada.
+//
+// The karmada-apiserver, the karmada-aggregated-apiserver and the karmada-webhook are run in the namespace
+// of the karmada in each additional host cluster as well, along with copies of the Secrets and the ConfigMaps
+// they mount. The other components and the karmada-search stay in the host cluster of the karmada.
+//
+// If etcd is local, each additional host cluster runs a member of it as well, which joins the cluster as a
+// learner and is promoted once it has caught up with the leader. The members reach each other through the
+// LoadBalancer Service `etcd-external` of their host clusters, which only accepts peers and clients
+// authenticated by the etcd CA. The apiservers store data in the member of their host cluster, and in the
+// member of the host cluster of the karmada. Since etcd needs a majority of its voting members to be
+// available, an even number of voters tolerates no more failures than one less, so if the number of the
+// additional host clusters is odd, the member in the last one is kept as a non-voting learner, and the
+// apiservers in that host cluster store data in the member of the host cluster of the karmada only. Two
+// additional host clusters are needed for the karmada to survive the loss of any one host cluster, and the
+// EtcdVotersBalanced condition reports an even number of voters. The data volumes of the members are left in the host clusters removed from
+// spec.hostClusters, and a backup can't be restored while any member runs in an additional host cluster.
+type KarmadaHostClusters struct {
+	// Clusters are the additional host clusters.
+	// +listType=map
+	// +listMapKey=name
+	Clusters []KarmadaHostCluster `json:"clusters"`
+
+	// GlobalEndpoint is the host name which routes to the karmada-apiservers of all the host clusters,
+	// e.g. a global load balancer or a geo DNS record, optionally with a port which defaults to 5443. It's
+	// added to the SANs of the certificate of the karmada-apiserver, and it's the server of the published
+	// kubeconfigs unless spec.kubeconfig.server is set. The routing itself is managed outside of firefly.
+	// +optional
+	GlobalEndpoint string `json:"globalEndpoint,omitempty"`
+}
+
+// KarmadaHostCluster is an additional host cluster of a karmada.
+type KarmadaHostCluster struct {
+	// Name identifies the host cluster, e.g. its region.
+	Name string `json:"name"`
+
+	// KubeconfigSecretRef refers to the Secret in the namespace of the karmada whose `kubeconfig` key
+	// holds the kubeconfig of the host cluster. Its user must be allowed to manage the namespaces, and the
+	// deployments, the services, the secrets and the configmaps in the namespace of the karmada.
+	KubeconfigSecretRef corev1.LocalObjectReference `json:"kubeconfigSecretRef"`
+}
+
+// KubeconfigSpec contains settings to the kubeconfig Secrets published for users to access the karmada.
+// The kubeconfigs are kept up to date when the certificates or the endpoint of the karmada-apiserver change.
+type KubeconfigSpec struct {
+	// Server is the address of the karmada-apiserver written into the published kubeconfigs.
+	// If empty, the ControlPlaneEndpoint is used if it's set, otherwise the in-cluster address
+	// of the karmada-apiserver service is used.
+	// +optional
+	Server string `json:"server,omitempty"`
+
+	// ReadOnly indicates whether a read-only kubeconfig should be published into the Secret
+	// `<name>-readonly-kubeconfig` as well. Its user is bound to the `view` ClusterRole of the karmada.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
 }
 
 // Etcd contains elements describing Etcd configuration.
@@ -147,6 +374,91 @@ type LocalEtcd struct {
 	// PeerCertSANs sets extra Subject Alternative Names for the etcd peer signing cert.
 	// +optional
 	PeerCertSANs []string `json:"peerCertSANs,omitempty"`
+
+	// StorageSize is the size of the data volume of etcd, which overrides the storage requested by
+	// DataVolume. A ReadWriteOnce data volume is created if DataVolume is unset. The data volume of an
+	// installed etcd is expanded if its storage class allows it, but it's never shrunk.
+	// +optional
+	StorageSize *resource.Quantity `json:"storageSize,omitempty"`
+
+	// StorageClassName is the storage class of the data volume of etcd, which overrides the one of
+	// DataVolume. It can't be changed once etcd is installed.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// NodeSelector restricts etcd to the matching nodes, e.g. the nodes dedicated to etcd with fast disks.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations allow etcd to run on the tainted nodes, e.g. the nodes dedicated to etcd.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// QuotaBackendBytes is the space quota of the etcd database, etcd raises the NOSPACE alarm and only
+	// serves reads and deletes once the database exceeds it. Defaults to 2Gi, the default of etcd.
+	// +optional
+	QuotaBackendBytes *resource.Quantity `json:"quotaBackendBytes,omitempty"`
+
+	// Defragmentation schedules the online defragmentations of the etcd database, which reclaim the
+	// space freed by the compactions of the karmada-apiserver.
+	// +optional
+	Defragmentation *EtcdDefragmentation `json:"defragmentation,omitempty"`
+
+	// NetworkingMode is how etcd is addressed by its peers, one of Service and HostNetwork.
+	// Defaults to Service.
+	// +kubebuilder:validation:Enum=Service;HostNetwork
+	// +optional
+	NetworkingMode EtcdNetworkingMode `json:"networkingMode,omitempty"`
+}
+
+// EtcdNetworkingMode is how the local etcd is addressed by its peers.
+type EtcdNetworkingMode string
+
+const (
+	// EtcdNetworkingService addresses etcd by the DNS names of its pods under its headless service.
+	EtcdNetworkingService EtcdNetworkingMode = "Service"
+	// EtcdNetworkingHostNetwork runs etcd in the network of its node and addresses it by the IP of the
+	// node, for the host clusters which restrict the DNS resolution and the traffic of the pods across
+	// the nodes. Etcd binds the ports 2379, 2380 and 2381 of the node, so it must not run on the nodes
+	// running another etcd, e.g. the control plane nodes of a kubeadm cluster, which are excluded by
+	// NodeSelector. The NetworkPolicies of the karmada don't apply to etcd in this mode. The IP of the
+	// node is added to the SANs of the server certificate of etcd.
+	EtcdNetworkingHostNetwork EtcdNetworkingMode = "HostNetwork"
+)
+
+// EtcdDefragmentation describes the scheduled defragmentations of the etcd database.
+type EtcdDefragmentation struct {
+	// Schedule is the schedule of the defragmentations in the cron format, e.g. "0 3 * * 0". Each one is
+	// run by a Job of the CronJob `etcd-defrag`, and blocks the writes to etcd while it's running.
+	Schedule string `json:"schedule"`
+}
+
+// EtcdStatus is the observed state of the database of the local etcd.
+type EtcdStatus struct {
+	// DBSize is the size of the etcd database, including the space freed by the compactions which is
+	// only reclaimed by a defragmentation.
+	// +optional
+	DBSize *resource.Quantity `json:"dbSize,omitempty"`
+
+	// DBSizeInUse is the size of the etcd database actually in use.
+	// +optional
+	DBSizeInUse *resource.Quantity `json:"dbSizeInUse,omitempty"`
+
+	// Quota is the space quota of the etcd database.
+	// +optional
+	Quota *resource.Quantity `json:"quota,omitempty"`
+
+	// Alarms are the active alarms of etcd, e.g. NOSPACE.
+	// +optional
+	Alarms []string `json:"alarms,omitempty"`
+
+	// LastDefragmentationTime is the last time a scheduled defragmentation succeeded.
+	// +optional
+	LastDefragmentationTime *metav1.Time `json:"lastDefragmentationTime,omitempty"`
+
+	// Message is a human readable message telling why etcd couldn't be probed.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // ExternalEtcd describes an external etcd cluster.
@@ -187,6 +499,270 @@ type APIServerComponent struct {
 
 	// KarmadaAggregratedAPIServerComponent holds settings to karmada-aggregated-apiserver component of the karmada.
 	KarmadaAggregratedAPIServer KarmadaAggregratedAPIServerComponent `json:"karmadaAggregratedAPIServer,omitempty"`
+
+	// KarmadaSearch holds settings to karmada-search component of the karmada.
+	// +optional
+	KarmadaSearch KarmadaSearchComponent `json:"karmadaSearch,omitempty"`
+
+	// KarmadaMetricsAdapter holds settings to karmada-metrics-adapter component of the karmada.
+	// +optional
+	KarmadaMetricsAdapter KarmadaMetricsAdapterComponent `json:"karmadaMetricsAdapter,omitempty"`
+
+	// ServiceType determines how the karmada-apiserver service is exposed.
+	// Defaults to ClusterIP.
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +optional
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// Ingress exposes the karmada-apiserver through an Ingress.
+	// +optional
+	Ingress *APIServerIngress `json:"ingress,omitempty"`
+
+	// Gateway exposes the karmada-apiserver through a TLSRoute of the Gateway API.
+	// The TLS connections are passed through to the karmada-apiserver. If Ingress is set as well,
+	// the Ingress is only used if the Gateway API is not installed into the host cluster.
+	// +optional
+	Gateway *APIServerGateway `json:"gateway,omitempty"`
+
+	// OIDC configures the karmada-apiserver to authenticate the users by the ID tokens issued by
+	// an OpenID Connect provider, in addition to the client certificates.
+	// +optional
+	OIDC *APIServerOIDC `json:"oidc,omitempty"`
+
+	// ServiceAccountIssuer is the issuer of the service account tokens issued by the karmada-apiserver.
+	// Defaults to https://kubernetes.default.svc.<dnsDomain>.
+	// +optional
+	ServiceAccountIssuer string `json:"serviceAccountIssuer,omitempty"`
+
+	// APIAudiences are the audiences of the karmada-apiserver, which the service account tokens
+	// must be issued for. Defaults to the service account issuer.
+	// +optional
+	APIAudiences []string `json:"apiAudiences,omitempty"`
+
+	// Audit configures the auditing of the requests to the karmada-apiserver.
+	// If it's not set, the requests aren't audited.
+	// +optional
+	Audit *APIServerAudit `json:"audit,omitempty"`
+
+	// Encryption configures the encryption at rest of the resources stored by the karmada-apiserver.
+	// Once the resources are encrypted, unsetting it only stops encrypting the written resources,
+	// the keys are kept to read the encrypted ones.
+	// +optional
+	Encryption *APIServerEncryption `json:"encryption,omitempty"`
+
+	// CertSANs are the extra Subject Alternative Names, i.e. DNS names, IPs or wildcard DNS names like
+	// *.example.com, appended to the certificates of the karmada-apiserver and the karmada-webhook.
+	// The certificates are re-signed whenever they're changed, the removed names are dropped from them.
+	// +optional
+	CertSANs []string `json:"certSANs,omitempty"`
+}
+
+// APIServerIngress describes how the karmada-apiserver is exposed through an Ingress.
+// The hostname is added to the SANs of the karmada-apiserver certificate and used as the
+// server address of the published kubeconfigs.
+type APIServerIngress struct {
+	// Hostname is the host name of the karmada-apiserver.
+	Hostname string `json:"hostname"`
+
+	// IngressClassName is the name of the IngressClass used by the Ingress.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// TLSSecretName is the name of the Secret holding the certificate served by the ingress controller.
+	// If empty, the TLS connections are passed through to the karmada-apiserver, which is required
+	// by the client certificate authentication, e.g. the published kubeconfigs. Passthrough is
+	// supported by the ingress-nginx controller with `--enable-ssl-passthrough`.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// Annotations are extra annotations added to the Ingress.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// APIServerGateway describes how the karmada-apiserver is exposed through the Gateway API.
+type APIServerGateway struct {
+	// Hostname is the host name of the karmada-apiserver.
+	// It's added to the SANs of the karmada-apiserver certificate and used as the server
+	// address of the published kubeconfigs.
+	Hostname string `json:"hostname"`
+
+	// ParentRef refers to the Gateway which the TLSRoute is attached to. The Gateway must
+	// have a TLS listener in Passthrough mode.
+	ParentRef GatewayParentReference `json:"parentRef"`
+}
+
+// GatewayParentReference refers to a Gateway.
+type GatewayParentReference struct {
+	// Name is the name of the Gateway.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Gateway.
+	// If empty, the namespace of the karmada is used.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName is the name of the listener of the Gateway.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// APIServerOIDC describes how the karmada-apiserver authenticates the users by OpenID Connect.
+type APIServerOIDC struct {
+	// IssuerURL is the URL of the provider, which must use the https scheme.
+	IssuerURL string `json:"issuerURL"`
+
+	// ClientID is the client ID which the ID tokens must be issued for.
+	ClientID string `json:"clientID"`
+
+	// CASecretRef refers to a Secret in the namespace of the karmada whose `ca.crt` field holds the
+	// certificate authority which signed the serving certificate of the provider. If it's not set,
+	// the system roots of the karmada-apiserver are used.
+	// +optional
+	CASecretRef *corev1.LocalObjectReference `json:"caSecretRef,omitempty"`
+
+	// UsernameClaim is the claim of the ID tokens used as the user name. Defaults to sub.
+	// +optional
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+
+	// UsernamePrefix is prepended to the user names to prevent clashes with the existing names,
+	// e.g. the system: users. "-" disables the prefixing. If it's not set, the user names other
+	// than email are prefixed by the issuer URL.
+	// +optional
+	UsernamePrefix string `json:"usernamePrefix,omitempty"`
+
+	// GroupsClaim is the claim of the ID tokens used as the groups of the user.
+	// +optional
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+
+	// GroupsPrefix is prepended to the group names to prevent clashes with the existing names.
+	// +optional
+	GroupsPrefix string `json:"groupsPrefix,omitempty"`
+
+	// RequiredClaims are the claims which must be present in the ID tokens with the matching values.
+	// +optional
+	RequiredClaims map[string]string `json:"requiredClaims,omitempty"`
+
+	// SigningAlgs are the accepted signing algorithms of the ID tokens. Defaults to RS256.
+	// +optional
+	SigningAlgs []string `json:"signingAlgs,omitempty"`
+}
+
+// APIServerAudit describes how the karmada-apiserver audits the requests. Exactly one of policy and
+// policySecretRef must be set, and at least one of the backends.
+type APIServerAudit struct {
+	// Policy is the audit policy, that is an audit.k8s.io/v1 Policy in YAML, which is rendered into
+	// the karmada-apiserver-audit-policy configmap.
+	// +optional
+	Policy string `json:"policy,omitempty"`
+
+	// PolicySecretRef refers to a Secret in the namespace of the karmada whose `policy.yaml` field holds
+	// the audit policy. The karmada-apiserver isn't restarted when the Secret is changed.
+	// +optional
+	PolicySecretRef *corev1.LocalObjectReference `json:"policySecretRef,omitempty"`
+
+	// Log writes the audit events to a log file, which is streamed to the stdout of a sidecar container
+	// of the karmada-apiserver, so that they're collected along with the logs of the pod.
+	// +optional
+	Log *AuditLogBackend `json:"log,omitempty"`
+
+	// Webhook sends the audit events to a remote API.
+	// +optional
+	Webhook *AuditWebhookBackend `json:"webhook,omitempty"`
+}
+
+// AuditLogBackend describes the log backend of the audit.
+type AuditLogBackend struct {
+	// MaxAge is the maximum number of days to retain the rotated log files.
+	// +optional
+	MaxAge *int32 `json:"maxAge,omitempty"`
+
+	// MaxBackups is the maximum number of the rotated log files to retain.
+	// +optional
+	MaxBackups *int32 `json:"maxBackups,omitempty"`
+
+	// MaxSize is the maximum size in megabytes of the log file before it gets rotated.
+	// +optional
+	MaxSize *int32 `json:"maxSize,omitempty"`
+
+	// SidecarImage is the image of the sidecar container streaming the log file, which must
+	// provide the tail command. Defaults to docker.io/library/busybox:1.35.
+	// +optional
+	SidecarImage string `json:"sidecarImage,omitempty"`
+}
+
+// AuditWebhookBackend describes the webhook backend of the audit.
+type AuditWebhookBackend struct {
+	// KubeconfigSecretRef refers to a Secret in the namespace of the karmada whose `kubeconfig` field
+	// holds the kubeconfig of the remote API, that is its address and the credentials to access it.
+	KubeconfigSecretRef corev1.LocalObjectReference `json:"kubeconfigSecretRef"`
+
+	// Mode is the strategy of sending the audit events, one of batch, blocking and blocking-strict.
+	// Defaults to batch.
+	// +kubebuilder:validation:Enum=batch;blocking;blocking-strict
+	// +optional
+	Mode string `json:"mode,omitempty"`
+}
+
+// EncryptionProvider is a provider of the encryption at rest.
+type EncryptionProvider string
+
+const (
+	// EncryptionProviderAESCBC encrypts the resources by AES-CBC with a key generated by firefly.
+	EncryptionProviderAESCBC EncryptionProvider = "aescbc"
+	// EncryptionProviderSecretbox encrypts the resources by XSalsa20 and Poly1305 with a key generated by firefly.
+	EncryptionProviderSecretbox EncryptionProvider = "secretbox"
+	// EncryptionProviderKMS encrypts the resources by the envelope encryption of a KMS plugin.
+	EncryptionProviderKMS EncryptionProvider = "kms"
+)
+
+// APIServerEncryption describes how the karmada-apiserver encrypts the resources at rest. The
+// EncryptionConfiguration of the karmada-apiserver is generated into the karmada-encryption-config
+// Secret, along with the keys of the aescbc and secretbox providers.
+//
+// The key is rotated whenever the firefly.io/rotate-encryption-key annotation of the karmada is changed.
+// The new key is rolled out to the karmada-apiservers for decryption first, then for encryption, and
+// the old key is dropped once the resources are rewritten by a job.
+type APIServerEncryption struct {
+	// Resources are the resources encrypted at rest, e.g. secrets or widgets.example.com.
+	// Defaults to [secrets].
+	// +optional
+	Resources []string `json:"resources,omitempty"`
+
+	// Provider is the provider encrypting the resources, one of aescbc, secretbox and kms.
+	// Defaults to aescbc.
+	// +kubebuilder:validation:Enum=aescbc;secretbox;kms
+	// +optional
+	Provider EncryptionProvider `json:"provider,omitempty"`
+
+	// KMS configures the KMS plugin of the kms provider. The plugin must run along with the
+	// karmada-apiserver, e.g. as a sidecar added by the patches or extraVolumes, and serve at the
+	// endpoint. The keys of the kms provider are rotated by the KMS instead of firefly.
+	// +optional
+	KMS *EncryptionKMS `json:"kms,omitempty"`
+
+	// ReencryptImage is the image of the job which rewrites the resources with a rotated key,
+	// which must provide the kubectl command. Defaults to docker.io/bitnami/kubectl with the
+	// kubernetesVersion of the karmada.
+	// +optional
+	ReencryptImage string `json:"reencryptImage,omitempty"`
+}
+
+// EncryptionKMS describes the KMS plugin of the kms provider.
+type EncryptionKMS struct {
+	// Name is the name of the KMS plugin.
+	Name string `json:"name"`
+
+	// Endpoint is the listen address of the KMS plugin, e.g. unix:///var/run/kmsplugin/socket.sock.
+	Endpoint string `json:"endpoint"`
+
+	// CacheSize is the number of the data encryption keys cached in memory.
+	// +optional
+	CacheSize *int32 `json:"cacheSize,omitempty"`
+
+	// Timeout is the timeout of the calls to the KMS plugin. Defaults to 3s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 // KubeAPIServerComponent holds settings to kube-apiserver component of the kubernetes.
@@ -200,6 +776,11 @@ type KubeAPIServerComponent struct {
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// Autoscaling scales the kube-apiserver component by a HorizontalPodAutoscaler, so that it keeps up
+	// with the number of member clusters and the workload volume. Replicas is ignored if it's set.
+	// +optional
+	Autoscaling *Autoscaling `json:"autoscaling,omitempty"`
+
 	// ExtraArgs is an extra set of flags to pass to the kube-apiserver component or
 	// override. A key in this map is the flag name as it appears on the command line except
 	// without leading dash(es).
@@ -216,7 +797,17 @@ type KubeAPIServerComponent struct {
 	// +optional
 	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
 
+	// Logging configures the log verbosity and format of the component. They're passed to the
+	// component by the --v and --logging-format flags, unless the flags are set by extraArgs.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
+	// ComponentExtras holds the extra volumes, volume mounts and environment variables of the component.
+	ComponentExtras `json:",inline"`
+
 	// CertSANs sets extra Subject Alternative Names for the API Server signing cert.
+	// Deprecated: use spec.apiServer.certSANs instead. They're merged into it, so the removed names
+	// are dropped from the certificates the same way.
 	// +optional
 	CertSANs []string `json:"certSANs,omitempty"`
 
@@ -241,6 +832,11 @@ type KarmadaAggregratedAPIServerComponent struct {
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// Autoscaling scales the karmada-aggregated-apiserver component by a HorizontalPodAutoscaler, so that it keeps up
+	// with the number of member clusters and the workload volume. Replicas is ignored if it's set.
+	// +optional
+	Autoscaling *Autoscaling `json:"autoscaling,omitempty"`
+
 	// ExtraArgs is an extra set of flags to pass to the karmada-aggregated-apiserver component or
 	// override. A key in this map is the flag name as it appears on the command line except
 	// without leading dash(es).
@@ -257,6 +853,14 @@ type KarmadaAggregratedAPIServerComponent struct {
 	// +optional
 	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
 
+	// Logging configures the log verbosity and format of the component. They're passed to the
+	// component by the --v and --logging-format flags, unless the flags are set by extraArgs.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
+	// ComponentExtras holds the extra volumes, volume mounts and environment variables of the component.
+	ComponentExtras `json:",inline"`
+
 	// Compute Resources required by this component.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// +optional
@@ -295,6 +899,14 @@ type KarmadaWebhookComponent struct {
 	// +optional
 	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
 
+	// Logging configures the log verbosity and format of the component. They're passed to the
+	// component by the --v and --logging-format flags, unless the flags are set by extraArgs.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
+	// ComponentExtras holds the extra volumes, volume mounts and environment variables of the component.
+	ComponentExtras `json:",inline"`
+
 	// Compute Resources required by this component.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// +optional
@@ -311,12 +923,23 @@ type ControllerManagerComponent struct {
 
 	// FireflyKarmadaManager holds settings to firefly-karmada-manager component of the karmada.
 	FireflyKarmadaManager FireflyKarmadaManagerComponent `json:"fireflyKarmadaManager,omitempty"`
+
+	// MulticlusterCloudProvider holds settings to multicluster-cloud-provider component of the karmada.
+	// +optional
+	MulticlusterCloudProvider MulticlusterCloudProviderComponent `json:"multiclusterCloudProvider,omitempty"`
 }
 
 // KubeControllerManagerComponent holds settings to kube-controller-manager component of the kubernetes.
 // Karmada uses it to manage the lifecycle of the federated resources. An especial case is the garbage
 // collection of the orphan resources in your karmada.
 type KubeControllerManagerComponent struct {
+	// Enable indicates whether the kube-controller-manager conponent should be deployed. Without it, the
+	// namespaces of the karmada are not finalized and the orphan resources are not garbage collected.
+	// This is a pointer to distinguish between explicit zero and not specified.
+	// Defaults to true.
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
 	// ImageMeta allows to customize the image used for the karmada-scheduler component
 	ImageMeta `json:",inline"`
 
@@ -378,6 +1001,14 @@ type KubeControllerManagerComponent struct {
 	// +optional
 	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
 
+	// Logging configures the log verbosity and format of the component. They're passed to the
+	// component by the --v and --logging-format flags, unless the flags are set by extraArgs.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
+	// ComponentExtras holds the extra volumes, volume mounts and environment variables of the component.
+	ComponentExtras `json:",inline"`
+
 	// Compute Resources required by this component.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// +optional
@@ -430,6 +1061,14 @@ type KarmadaControllerManagerComponent struct {
 	// +optional
 	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
 
+	// Logging configures the log verbosity and format of the component. They're passed to the
+	// component by the --v and --logging-format flags, unless the flags are set by extraArgs.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
+	// ComponentExtras holds the extra volumes, volume mounts and environment variables of the component.
+	ComponentExtras `json:",inline"`
+
 	// Compute Resources required by this component.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// +optional
@@ -438,6 +1077,13 @@ type KarmadaControllerManagerComponent struct {
 
 // FireflyKarmadaManagerComponent holds settings to the firefly-karmada-manager component of the karmada.
 type FireflyKarmadaManagerComponent struct {
+	// Enable indicates whether the firefly-karmada-manager conponent should be deployed. Without it,
+	// the estimators of the member clusters are not deployed.
+	// This is a pointer to distinguish between explicit zero and not specified.
+	// Defaults to true.
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
 	// ImageMeta allows to customize the image used for the firefly-karmada-manager component
 	ImageMeta `json:",inline"`
 
@@ -456,6 +1102,178 @@ type FireflyKarmadaManagerComponent struct {
 	// +optional
 	Controllers []string `json:"controllers,omitempty"`
 
+	// ExtraArgs is an extra set of flags to pass to the firefly-karmada-manager component or override.
+	// A key in this map is the flag name as it appears on the command line except without
+	// leading dash(es).
+	//
+	// Incorrect settings on this feild maybe lead to the corresponding component in an unhealthy
+	// state. Before you do it, please confirm that you understand the risks of this configuration.
+	// +optional
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+
+	// Logging configures the log verbosity and format of the component. They're passed to the
+	// component by the --v and --logging-format flags, unless the flags are set by extraArgs.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
+	// ComponentExtras holds the extra volumes, volume mounts and environment variables of the component.
+	ComponentExtras `json:",inline"`
+
+	// Compute Resources required by this component.
+	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// MulticlusterCloudProviderComponent holds settings to the multicluster-cloud-provider component of
+// the karmada. It provisions the load balancers of the MultiClusterIngresses and MultiClusterServices
+// of the karmada through a cloud provider. The component accesses the karmada-apiserver with its own
+// client certificate, whose user is only granted the permissions it requires.
+type MulticlusterCloudProviderComponent struct {
+	// Enable indicates whether the multicluster-cloud-provider conponent should be deployed.
+	// This is a pointer to distinguish between explicit zero and not specified.
+	// Defaults to false.
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// Provider is the name of the cloud provider, which the component is built for. It's passed to
+	// the component by the --multicluster-provider flag. Defaults to "fake".
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// IngressClass is the ingress class of the MultiClusterIngresses handled by the component.
+	// Defaults to "karmada.io/<provider>".
+	// +optional
+	IngressClass string `json:"ingressClass,omitempty"`
+
+	// CloudConfig refers to the key of a secret in the namespace of the karmada, which holds the
+	// configuration of the cloud provider, e.g. its credentials. It's passed to the component by
+	// the --cloud-config flag.
+	// +optional
+	CloudConfig *corev1.SecretKeySelector `json:"cloudConfig,omitempty"`
+
+	// ImageMeta allows to customize the image used for the multicluster-cloud-provider component.
+	// The image is released separately from karmada, so the image repository defaults to
+	// docker.io/karmada, the image name to multicluster-provider-<provider> and the tag to latest.
+	ImageMeta `json:",inline"`
+
+	// Number of desired pods. This is a pointer to distinguish between explicit
+	// zero and not specified. Defaults to 1.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// ExtraArgs is an extra set of flags to pass to the multicluster-cloud-provider component or
+	// override. A key in this map is the flag name as it appears on the command line except
+	// without leading dash(es).
+	//
+	// Incorrect settings on this feild maybe lead to the corresponding component in an unhealthy
+	// state. Before you do it, please confirm that you understand the risks of this configuration.
+	//
+	// For supported flags, please see
+	// https://github.com/karmada-io/multicluster-cloud-provider/blob/main/options/options.go
+	// for details.
+	// +optional
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+
+	// Logging configures the log verbosity and format of the component. They're passed to the
+	// component by the --v and --logging-format flags, unless the flags are set by extraArgs.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
+	// ComponentExtras holds the extra volumes, volume mounts and environment variables of the component.
+	ComponentExtras `json:",inline"`
+
+	// Compute Resources required by this component.
+	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// KarmadaSearchComponent holds settings to karmada-search component of the karmada.
+type KarmadaSearchComponent struct {
+	// Enable indicates whether the karmada-search conponent should be deployed.
+	// This is a pointer to distinguish between explicit zero and not specified.
+	// Defaults to false.
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// ImageMeta allows to customize the image used for the karmada-search component
+	ImageMeta `json:",inline"`
+
+	// Number of desired pods. This is a pointer to distinguish between explicit
+	// zero and not specified. Defaults to 1.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// ExtraArgs is an extra set of flags to pass to the karmada-search component or override.
+	// A key in this map is the flag name as it appears on the command line except without
+	// leading dash(es).
+	//
+	// Note: This is a temporary solution to allow for the configuration of the karmada-search
+	// component. In the future, we will provide a more structured way to configure the component.
+	// Once that is done, this field will be discouraged to be used.
+	// Incorrect settings on this feild maybe lead to the corresponding component in an unhealthy
+	// state. Before you do it, please confirm that you understand the risks of this configuration.
+	//
+	// For supported flags, please see
+	// https://github.com/karmada-io/karmada/blob/master/cmd/karmada-search/app/options/options.go
+	// for details.
+	// +optional
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+
+	// Logging configures the log verbosity and format of the component. They're passed to the
+	// component by the --v and --logging-format flags, unless the flags are set by extraArgs.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
+	// ComponentExtras holds the extra volumes, volume mounts and environment variables of the component.
+	ComponentExtras `json:",inline"`
+
+	// Compute Resources required by this component.
+	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// KarmadaMetricsAdapterComponent holds settings to karmada-metrics-adapter component of the karmada.
+type KarmadaMetricsAdapterComponent struct {
+	// Enable indicates whether the FederatedHPA and the CronFederatedHPA are enabled for the karmada.
+	// They're enabled as a unit: the karmada-metrics-adapter component is deployed and registered as the
+	// metrics.k8s.io and custom.metrics.k8s.io APIServices, the metrics are readable by the view, edit
+	// and admin roles, and their controllers are enabled in the karmada-controller-manager. The metrics
+	// pipeline is verified end-to-end and reported by the HPAReady condition. The member clusters must
+	// serve the metrics, e.g. by the metrics-server.
+	// This is a pointer to distinguish between explicit zero and not specified.
+	// Defaults to false.
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// ImageMeta allows to customize the image used for the karmada-metrics-adapter component
+	ImageMeta `json:",inline"`
+
+	// Number of desired pods. This is a pointer to distinguish between explicit
+	// zero and not specified. Defaults to 1.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// ExtraArgs is an extra set of flags to pass to the karmada-metrics-adapter component or override.
+	// A key in this map is the flag name as it appears on the command line except without
+	// leading dash(es).
+	//
+	// For supported flags, please see
+	// https://github.com/karmada-io/karmada/blob/master/cmd/metrics-adapter/app/options/options.go
+	// for details.
+	// +optional
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+
+	// Logging configures the log verbosity and format of the component. They're passed to the
+	// component by the --v and --logging-format flags, unless the flags are set by extraArgs.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
+	// ComponentExtras holds the extra volumes, volume mounts and environment variables of the component.
+	ComponentExtras `json:",inline"`
+
 	// Compute Resources required by this component.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// +optional
@@ -503,15 +1321,71 @@ type KarmadaSchedulerComponent struct {
 	// +optional
 	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
 
+	// Config is the configuration of the karmada-scheduler, which is rendered into the
+	// karmada-scheduler-config configmap and passed to the component by the --config flag.
+	// If it's not set, the component runs with its default configuration.
+	// +optional
+	Config *KarmadaSchedulerConfiguration `json:"config,omitempty"`
+
+	// Logging configures the log verbosity and format of the component. They're passed to the
+	// component by the --v and --logging-format flags, unless the flags are set by extraArgs.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
+	// ComponentExtras holds the extra volumes, volume mounts and environment variables of the component.
+	ComponentExtras `json:",inline"`
+
 	// Compute Resources required by this component.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
+// KarmadaSchedulerConfiguration holds the configuration of the karmada-scheduler.
+type KarmadaSchedulerConfiguration struct {
+	// Plugins configures the plugins of the default profile, that is the profile of the
+	// default-scheduler. If it's not set, the default plugins are enabled.
+	// +optional
+	Plugins *SchedulerPlugins `json:"plugins,omitempty"`
+
+	// Profiles are the additional profiles of the karmada-scheduler. A profile schedules the
+	// resources whose schedulerName is the same as its name with its own plugins.
+	// +optional
+	Profiles []KarmadaSchedulerProfile `json:"profiles,omitempty"`
+
+	// EstimatorTimeout is the timeout of the calls to the karmada-scheduler-estimators.
+	// It only takes effect when the karmada-scheduler-estimator is enabled. Defaults to 3s.
+	// +optional
+	EstimatorTimeout *metav1.Duration `json:"estimatorTimeout,omitempty"`
+}
+
+// KarmadaSchedulerProfile is a profile of the karmada-scheduler.
+type KarmadaSchedulerProfile struct {
+	// SchedulerName is the name of the profile, which the resources set as their schedulerName
+	// to be scheduled by it. It must not be default-scheduler.
+	SchedulerName string `json:"schedulerName"`
+
+	// Plugins configures the plugins of the profile. If it's not set, the default plugins are enabled.
+	// +optional
+	Plugins *SchedulerPlugins `json:"plugins,omitempty"`
+}
+
+// SchedulerPlugins configures the plugins of a karmada-scheduler profile.
+type SchedulerPlugins struct {
+	// Enabled are the plugins enabled in addition to the default plugins.
+	// +optional
+	Enabled []string `json:"enabled,omitempty"`
+
+	// Disabled are the default plugins which are disabled. "*" disables all the default plugins,
+	// so that only the enabled ones are run.
+	// +optional
+	Disabled []string `json:"disabled,omitempty"`
+}
+
 // KarmadaDeschedulerComponent holds settings to karmada-descheduler conponent of the karmada.
 type KarmadaDeschedulerComponent struct {
 	// Enable indicates whether the karmada-descheduler conponent should be deployed.
+	// It only takes effect when the karmada-scheduler-estimator is enabled.
 	// This is a pointer to distinguish between explicit zero and not specified.
 	// Defaults to false.
 	// +optional
@@ -541,6 +1415,14 @@ type KarmadaDeschedulerComponent struct {
 	// +optional
 	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
 
+	// Logging configures the log verbosity and format of the component. They're passed to the
+	// component by the --v and --logging-format flags, unless the flags are set by extraArgs.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
+	// ComponentExtras holds the extra volumes, volume mounts and environment variables of the component.
+	ComponentExtras `json:",inline"`
+
 	// Compute Resources required by this component.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// +optional
@@ -549,6 +1431,14 @@ type KarmadaDeschedulerComponent struct {
 
 // KarmadaSchedulerEstimatorComponent holds settings to karmada-scheduler-estimator conponent of the karmada.
 type KarmadaSchedulerEstimatorComponent struct {
+	// Enable indicates whether the karmada-scheduler-estimator conponent should be deployed
+	// for member clusters. If it's disabled, the karmada-scheduler doesn't consult estimators
+	// when scheduling and the karmada-descheduler won't be deployed since it relies on them.
+	// This is a pointer to distinguish between explicit zero and not specified.
+	// Defaults to true.
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
 	// ImageMeta allows to customize the image used for the karmada-scheduler-estimator component
 	ImageMeta `json:",inline"`
 
@@ -573,10 +1463,64 @@ type KarmadaSchedulerEstimatorComponent struct {
 	// +optional
 	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
 
+	// Logging configures the log verbosity and format of the component. They're passed to the
+	// component by the --v and --logging-format flags, unless the flags are set by extraArgs.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
+	// ComponentExtras holds the extra volumes, volume mounts and environment variables of the component.
+	ComponentExtras `json:",inline"`
+
 	// Compute Resources required by this component.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Service holds settings of the Services which expose the estimators.
+	// +optional
+	Service EstimatorService `json:"service,omitempty"`
+
+	// GRPCTLS enables TLS for the gRPC servers of the estimators.
+	// +optional
+	GRPCTLS *EstimatorGRPCTLS `json:"grpcTLS,omitempty"`
+
+	// MutualTLS indicates whether the traffic between the estimators and the karmada-scheduler
+	// and the karmada-descheduler is secured by mTLS. Their certificates are issued by the karmada
+	// CA and rotated before they expire. The estimators whose grpcTLS is set keep using their own
+	// certificates, which have to be issued by the karmada CA then. It requires karmada v1.7 or later.
+	// +optional
+	MutualTLS bool `json:"mutualTLS,omitempty"`
+
+	// ClusterOverrides overrides the settings of the estimators of specific member clusters.
+	// +listType=map
+	// +listMapKey=clusterName
+	// +optional
+	ClusterOverrides []EstimatorClusterOverride `json:"clusterOverrides,omitempty"`
+
+	// HealthCheck enables the health checks of the estimators by the firefly-karmada-manager, whose
+	// results are reported in status.estimators. If it's not set, the estimators are only checked
+	// by their liveness probes.
+	// +optional
+	HealthCheck *EstimatorHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// ComponentExtras holds the extra settings of the container of a component, e.g. to mount the files
+// referred to by its extraArgs. They're added to the ones generated by firefly, so they must not
+// reuse the names of the generated volumes or the paths of the generated volume mounts.
+type ComponentExtras struct {
+	// ExtraVolumes are the extra volumes of the pods of the component.
+	// +optional
+	ExtraVolumes []corev1.Volume `json:"extraVolumes,omitempty"`
+
+	// ExtraVolumeMounts are the extra volume mounts of the container of the component, which
+	// may refer to the extraVolumes.
+	// +optional
+	ExtraVolumeMounts []corev1.VolumeMount `json:"extraVolumeMounts,omitempty"`
+
+	// Env is the extra environment variables of the container of the component. They override
+	// the generated environment variables of the same names.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
 }
 
 // ImageMeta allows to customize the image used for components.
@@ -597,6 +1541,73 @@ type ImageMeta struct {
 	ImageName string `json:"imageName,omitempty"`
 }
 
+const (
+	// KarmadaConditionReady indicates whether the latest generation of the karmada is installed and
+	// healthy. It's summarized from the other conditions, its reason is the phase of the karmada.
+	KarmadaConditionReady = "Ready"
+
+	// KarmadaConditionPaused indicates whether the reconciliation of the karmada is paused.
+	KarmadaConditionPaused = "Paused"
+
+	// KarmadaConditionAccepted indicates whether the karmada is installed in its namespace.
+	// Only one karmada is allowed per namespace, the others are rejected until it's deleted.
+	KarmadaConditionAccepted = "Accepted"
+
+	// KarmadaConditionSearchReady indicates whether the karmada-search component is ready
+	// and its APIService is registered into the karmada-apiserver.
+	KarmadaConditionSearchReady = "KarmadaSearchReady"
+
+	// KarmadaConditionHPAReady indicates whether the FederatedHPA is enabled and the metrics of the member
+	// clusters are served by the metrics APIServices of the karmada-apiserver.
+	KarmadaConditionHPAReady = "HPAReady"
+
+	// KarmadaConditionAPIServerHealthy indicates whether the karmada-apiserver reports itself ready by /readyz.
+	KarmadaConditionAPIServerHealthy = "KarmadaAPIServerHealthy"
+
+	// KarmadaConditionEtcdHealthy indicates whether the etcd of the karmada is healthy, as seen by
+	// the karmada-apiserver.
+	KarmadaConditionEtcdHealthy = "EtcdHealthy"
+
+	// KarmadaConditionWebhookHealthy indicates whether the karmada-webhook is reachable through its
+	// service with a serving certificate signed by the karmada CA.
+	KarmadaConditionWebhookHealthy = "KarmadaWebhookHealthy"
+
+	// KarmadaConditionRolloutPaused indicates whether the rollout of the changes of the components is
+	// paused according to spec.updateStrategy.
+	KarmadaConditionRolloutPaused = "RolloutPaused"
+
+	// KarmadaConditionHooksSucceeded indicates whether the hooks of the current lifecycle phase of the
+	// karmada have succeeded. The phase is held back while they're running or failed.
+	KarmadaConditionHooksSucceeded = "HooksSucceeded"
+
+	// KarmadaConditionUpgradePreflightPassed indicates whether the checks run before the karmada is
+	// upgraded have passed. The upgrade is refused while they're failing.
+	KarmadaConditionUpgradePreflightPassed = "UpgradePreflightPassed"
+
+	// KarmadaConditionEtcdDegraded indicates whether the local etcd of the karmada needs an intervention
+	// which can't be done automatically, e.g. its data in use is close to its space quota. The message
+	// tells what to do.
+	KarmadaConditionEtcdDegraded = "EtcdDegraded"
+
+	// KarmadaConditionEtcdVotersBalanced indicates whether the local etcd of the karmada, whose members are
+	// distributed across its host clusters, has an odd number of voting members. It's false if a member
+	// can't be demoted to a learner after a host cluster is removed, until a host cluster is added or removed.
+	KarmadaConditionEtcdVotersBalanced = "EtcdVotersBalanced"
+)
+
+// HostCapability describes whether an optional capability of the host cluster is available.
+type HostCapability struct {
+	// Name is the name of the capability, e.g. PodMonitors or GatewayAPI.
+	Name string `json:"name"`
+
+	// Available is true if all resources required by the capability are served by the host cluster.
+	Available bool `json:"available"`
+
+	// Message is a human readable message telling how the karmada is affected by the capability.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // KarmadaStatus is the status for a Karmada resource
 type KarmadaStatus struct {
 	// observedGeneration is the most recent generation observed for this Karmada. It corresponds to the
@@ -604,12 +1615,113 @@ type KarmadaStatus struct {
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// Phase summarizes the status of the karmada. The conditions tell the details.
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+
+	// KarmadaVersion is the version of the karmada which has been installed successfully.
+	// It differs from spec.karmadaVersion while the karmada is being upgraded.
+	// +optional
+	KarmadaVersion string `json:"karmadaVersion,omitempty"`
+
+	// EncryptionKeyRotationTime is the time at which the key of the encryption at rest was rotated last time.
+	// +optional
+	EncryptionKeyRotationTime *metav1.Time `json:"encryptionKeyRotationTime,omitempty"`
+
+	// ResolvedImages are the images of the components whose tags are resolved to digests
+	// according to spec.imagePolicy.
+	// +listType=map
+	// +listMapKey=image
+	// +optional
+	ResolvedImages []ResolvedImage `json:"resolvedImages,omitempty"`
+
+	// Restore describes the last restore of the karmada from a backup, which is requested by the
+	// firefly.io/restore-from-backup annotation.
+	// +optional
+	Restore *KarmadaRestoreStatus `json:"restore,omitempty"`
+
+	// Estimators are the health of the karmada-scheduler-estimators of the member clusters, which
+	// is reported if spec.scheduler.karmadaSchedulerEstimator.healthCheck is set.
+	// +listType=map
+	// +listMapKey=clusterName
+	// +optional
+	Estimators []EstimatorStatus `json:"estimators,omitempty"`
+
+	// Rollout describes the rollout of the changes of the components according to spec.updateStrategy.
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// Hooks are the results of the last runs of the hooks of spec.hooks.
+	// +listType=map
+	// +listMapKey=phase
+	// +listMapKey=name
+	// +optional
+	Hooks []HookStatus `json:"hooks,omitempty"`
+
+	// UpgradePreflight describes the checks run before the latest upgrade of the karmada.
+	// +optional
+	UpgradePreflight *UpgradePreflightStatus `json:"upgradePreflight,omitempty"`
+
+	// TestReport describes the last run of the smoke test suite of spec.verification.
+	// +optional
+	TestReport *TestReport `json:"testReport,omitempty"`
+
+	// Etcd is the observed state of the database of the local etcd, which is probed periodically once
+	// the karmada is installed.
+	// +optional
+	Etcd *EtcdStatus `json:"etcd,omitempty"`
+
+	// HostClusters are the observed states of the apiservers in the additional host clusters of
+	// spec.hostClusters. The host clusters removed from the spec are cleaned up through them.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	HostClusters []KarmadaHostClusterStatus `json:"hostClusters,omitempty"`
+
+	// HostCapabilities are the optional capabilities of the host cluster the karmada relies on,
+	// e.g. the Gateway API for spec.apiServer.gateway, and whether they're available.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	HostCapabilities []HostCapability `json:"hostCapabilities,omitempty"`
+
 	// Represents the latest available observations of a karmada's current state.
 	// +patchMergeKey=type
 	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// KarmadaHostClusterStatus is the observed state of the apiservers in an additional host cluster.
+type KarmadaHostClusterStatus struct {
+	// Name is the name of the host cluster in spec.hostClusters.
+	Name string `json:"name"`
+
+	// KubeconfigSecretName is the name of the Secret holding the kubeconfig of the host cluster,
+	// which is kept to clean the host cluster up once it's removed from the spec.
+	KubeconfigSecretName string `json:"kubeconfigSecretName"`
+
+	// Endpoints are the load balancer addresses of the karmada-apiserver in the host cluster, which
+	// should be routed to by spec.hostClusters.globalEndpoint. It's empty unless spec.apiServer.serviceType
+	// is LoadBalancer.
+	// +optional
+	Endpoints []string `json:"endpoints,omitempty"`
+
+	// ReadyReplicas is the number of the ready replicas of the karmada-apiserver in the host cluster.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// EtcdPeerURL is the peer URL of the etcd member in the host cluster, which is kept to remove the
+	// member once the host cluster is removed from the spec. It's empty if etcd is external.
+	// +optional
+	EtcdPeerURL string `json:"etcdPeerURL,omitempty"`
+
+	// Message describes why the apiservers failed to be reconciled in the host cluster, if they did.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // KarmadaList is a list of Karmada resources
@@ -0,0 +1,43 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// LogFormat is the format of the logs written by a component.
+// +kubebuilder:validation:Enum=text;json
+type LogFormat string
+
+const (
+	// LogFormatText writes the logs as klog text lines.
+	LogFormatText LogFormat = "text"
+	// LogFormatJSON writes the logs as structured json lines.
+	LogFormatJSON LogFormat = "json"
+)
+
+// Logging holds configuration for the logs of a component.
+type Logging struct {
+	// Verbosity is the number for the log level verbosity, passed to the component by the --v flag.
+	// If not set, the default verbosity of the component chosen by firefly is used.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Verbosity *int32 `json:"verbosity,omitempty"`
+
+	// Format is the log format, passed to the component by the --logging-format flag.
+	// The json format is only supported by the components built with the logging options
+	// of k8s.io/component-base. If not set, the component writes text logs.
+	// +optional
+	Format LogFormat `json:"format,omitempty"`
+}
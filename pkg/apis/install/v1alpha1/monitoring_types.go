@@ -0,0 +1,39 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// Monitoring holds configuration for the monitoring of the installed components by Prometheus.
+type Monitoring struct {
+	// Enabled indicates whether the metrics of the components are exposed to Prometheus.
+	// If the Prometheus Operator is installed into the host cluster, a PodMonitor is created
+	// for each component. Otherwise the pods of the components are annotated with the
+	// conventional `prometheus.io/scrape`, `prometheus.io/port` and `prometheus.io/path`
+	// annotations. Only the components which serve their metrics over plain http are monitored.
+	// It's ignored if Chart is set.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Labels are added to the PodMonitors, so that they're selected by the
+	// podMonitorSelector of a Prometheus.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Interval is the interval at which the metrics are scraped, e.g. 30s.
+	// If empty, the scrape interval of the Prometheus is used.
+	// +optional
+	Interval string `json:"interval,omitempty"`
+}
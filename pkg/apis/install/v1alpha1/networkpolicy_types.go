@@ -0,0 +1,27 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// NetworkPolicy describes the hardening of the traffic between the components. When it's enabled,
+// a NetworkPolicy is created for each stateful or internal component, which only admits the components
+// known to connect to it, e.g. etcd only admits the apiservers. It only takes effect if the network
+// plugin of the host cluster enforces NetworkPolicies. It's ignored if Chart is set.
+type NetworkPolicy struct {
+	// Enabled indicates that the NetworkPolicies are generated for the components.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// PatchType is the type of a patch.
+type PatchType string
+
+const (
+	// PatchTypeStrategicMerge is a strategic merge patch. For the kinds unknown to firefly,
+	// it falls back to a json merge patch.
+	PatchTypeStrategicMerge PatchType = "StrategicMerge"
+
+	// PatchTypeJSON6902 is a json patch defined by RFC 6902.
+	PatchTypeJSON6902 PatchType = "JSON6902"
+)
+
+// Patch represents a patch applied to the manifests generated by firefly before they're
+// created or updated, which is the same as the patches of kustomize. It allows users to
+// tweak any generated manifest, such as adding env vars, sidecars or args, without forking firefly.
+type Patch struct {
+	// Target selects the manifests to be patched.
+	Target PatchTarget `json:"target"`
+
+	// Type is the type of the patch.
+	// +kubebuilder:validation:Enum=StrategicMerge;JSON6902
+	// +kubebuilder:default=StrategicMerge
+	// +optional
+	Type PatchType `json:"type,omitempty"`
+
+	// Patch is the content of the patch in yaml or json.
+	// For StrategicMerge, it's a partial object, e.g.
+	//   spec:
+	//     template:
+	//       spec:
+	//         containers:
+	//         - name: kube-apiserver
+	//           env:
+	//           - name: FOO
+	//             value: bar
+	// For JSON6902, it's a list of operations, e.g.
+	//   - op: add
+	//     path: /spec/template/spec/containers/0/args/-
+	//     value: --v=4
+	Patch string `json:"patch"`
+}
+
+// PatchTarget selects the manifests to be patched.
+type PatchTarget struct {
+	// Group is the api group of the manifests. Empty means the core group.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// Version is the api version of the manifests.
+	// If empty, manifests of all the versions are selected.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Kind is the kind of the manifests.
+	Kind string `json:"kind"`
+
+	// Name is the name of the manifest.
+	// If empty, all the manifests of the kind are selected.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// Phase summarizes the status of an installation, e.g. a karmada or an addon, so that its state is told
+// at a glance by `kubectl get`. The conditions of the installation tell the details.
+type Phase string
+
+const (
+	// PhaseProgressing means the latest generation of the installation is being installed, or one of its
+	// conditions reports a problem.
+	PhaseProgressing Phase = "Progressing"
+	// PhaseReady means the latest generation of the installation is installed and healthy.
+	PhaseReady Phase = "Ready"
+	// PhasePaused means the reconciliation of the installation is paused.
+	PhasePaused Phase = "Paused"
+	// PhaseRejected means the installation is refused, e.g. another karmada is installed in its namespace.
+	PhaseRejected Phase = "Rejected"
+	// PhaseDeleting means the installation is being deleted.
+	PhaseDeleting Phase = "Deleting"
+)
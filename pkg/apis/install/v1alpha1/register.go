@@ -50,6 +50,14 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&KarmadaList{},
 		&Clusterpedia{},
 		&ClusterpediaList{},
+		&Addon{},
+		&AddonList{},
+		&ClusterRegistration{},
+		&ClusterRegistrationList{},
+		&Submariner{},
+		&SubmarinerList{},
+		&SecretSync{},
+		&SecretSyncList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -0,0 +1,146 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Karmada",type=string,JSONPath=`.spec.karmada.name`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SecretSync is a specification for a SecretSync resource.
+// A secret sync mirrors Secrets and ConfigMaps of the host cluster into a hosted karmada, and
+// optionally into its member clusters, e.g. the registry credentials and the CA bundles used by
+// the policies. The mirrors are updated whenever the sources are changed, so that the rotated
+// credentials are propagated, and they're deleted once they're not selected anymore.
+type SecretSync struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the SecretSync.
+	// +optional
+	Spec SecretSyncSpec `json:"spec"`
+	// Most recently observed status of the SecretSync.
+	// +optional
+	Status SecretSyncStatus `json:"status"`
+}
+
+// SecretSyncSpec is the spec for a SecretSync resource
+type SecretSyncSpec struct {
+	// Paused indicates that the reconciliation of the secret sync is paused. While it's paused,
+	// firefly doesn't create, update or delete any mirrors, but still updates its status.
+	// Deleting the secret sync is still handled.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Karmada refers to a Karmada in the same namespace, whose karmada-apiserver the Secrets and
+	// the ConfigMaps are mirrored into.
+	Karmada corev1.LocalObjectReference `json:"karmada"`
+
+	// Secrets are the names of the Secrets in the namespace of the secret sync to be mirrored.
+	// +optional
+	Secrets []string `json:"secrets,omitempty"`
+
+	// ConfigMaps are the names of the ConfigMaps in the namespace of the secret sync to be mirrored.
+	// +optional
+	ConfigMaps []string `json:"configMaps,omitempty"`
+
+	// Selector selects more Secrets and ConfigMaps in the namespace of the secret sync to be mirrored
+	// by their labels. The service account tokens are never mirrored.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// TargetNamespace is the namespace of the karmada the mirrors are created in, which is created if it
+	// doesn't exist. Defaults to the namespace of the secret sync.
+	// +optional
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+
+	// Clusters is a list of member clusters of the karmada. If set, the mirrors are propagated to these
+	// clusters by a PropagationPolicy; otherwise they only live in the karmada control plane.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+}
+
+// SecretSyncObject is an object mirrored by a secret sync.
+type SecretSyncObject struct {
+	// Kind is the kind of the object, either Secret or ConfigMap.
+	Kind string `json:"kind"`
+
+	// Name is the name of the object, which is the same in the host cluster and in the karmada.
+	Name string `json:"name"`
+
+	// Hash is the hash of the data of the object last mirrored. The mirror is only updated if it's changed.
+	Hash string `json:"hash"`
+}
+
+const (
+	// SecretSyncConditionReady indicates whether all the selected objects are mirrored.
+	SecretSyncConditionReady = "Ready"
+
+	// SecretSyncConditionPaused indicates whether the reconciliation of the secret sync is paused.
+	SecretSyncConditionPaused = "Paused"
+)
+
+// SecretSyncStatus is the status for a SecretSync resource
+type SecretSyncStatus struct {
+	// observedGeneration is the most recent generation observed for this SecretSync. It corresponds to the
+	// SecretSync's generation, which is updated on mutation by the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase summarizes the status of the secret sync. The conditions tell the details.
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+
+	// TargetNamespace is the namespace of the karmada the objects are mirrored into, which is kept to
+	// clean up the mirrors once spec.targetNamespace is changed.
+	// +optional
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+
+	// Objects are the objects which are mirrored into the karmada.
+	// +optional
+	Objects []SecretSyncObject `json:"objects,omitempty"`
+
+	// Represents the latest available observations of a secret sync's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SecretSyncList is a list of SecretSync resources
+type SecretSyncList struct {
+	metav1.TypeMeta `json:",inline"`
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ListMeta `json:"metadata"`
+
+	Items []SecretSync `json:"items"`
+}
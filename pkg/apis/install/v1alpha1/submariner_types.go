@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Submariner is a specification for a Submariner resource.
+// A submariner connects the networks of the member clusters of a hosted karmada with Submariner,
+// so that the pods and services of a member cluster are reachable from the other ones. The broker
+// is hosted by the karmada-apiserver, and the submariner-operator, which deploys the gateway and
+// the route agent, is propagated to the member clusters. The gateways run on the nodes labeled
+// with `submariner.io/gateway=true` in the member clusters.
+type Submariner struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the Submariner.
+	// +optional
+	Spec SubmarinerSpec `json:"spec"`
+	// Most recently observed status of the Submariner.
+	// +optional
+	Status SubmarinerStatus `json:"status"`
+}
+
+// SubmarinerSpec is the spec for a Submariner resource
+type SubmarinerSpec struct {
+	// Paused indicates that the reconciliation of the submariner is paused. While it's paused,
+	// firefly doesn't create, update or delete any resources of the submariner, but still
+	// updates its status. Deleting the submariner is still handled.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Karmada refers to a Karmada in the same namespace, whose karmada-apiserver hosts the broker.
+	Karmada corev1.LocalObjectReference `json:"karmada"`
+
+	// Clusters is the list of member clusters of the karmada above which are connected.
+	// +listType=map
+	// +listMapKey=name
+	Clusters []SubmarinerCluster `json:"clusters"`
+
+	// BrokerServer is the address of the karmada-apiserver, e.g. `karmada.example.com:5443`, which
+	// the member clusters access the broker with. If empty, the server of the admin kubeconfig of
+	// the karmada is used, see spec.kubeconfig.server of the karmada.
+	// +optional
+	BrokerServer string `json:"brokerServer,omitempty"`
+
+	// CableDriver is the driver of the tunnels between the gateways, one of libreswan, wireguard
+	// and vxlan. Defaults to libreswan.
+	// +kubebuilder:validation:Enum=libreswan;wireguard;vxlan
+	// +optional
+	CableDriver string `json:"cableDriver,omitempty"`
+
+	// NATTraversal indicates that the gateways are connected through their public addresses,
+	// which is required if the member clusters aren't in the same network.
+	// +optional
+	NATTraversal bool `json:"natTraversal,omitempty"`
+
+	// ServiceDiscovery indicates that Lighthouse is deployed, so that the services exported
+	// by a member cluster are resolved by the other ones.
+	// +optional
+	ServiceDiscovery bool `json:"serviceDiscovery,omitempty"`
+
+	// BrokerChart is the chart of the broker. Defaults to the submariner-k8s-broker chart
+	// of the Submariner chart repository.
+	// +optional
+	BrokerChart *ChartReference `json:"brokerChart,omitempty"`
+
+	// OperatorChart is the chart of the submariner-operator. Defaults to the submariner-operator
+	// chart of the Submariner chart repository.
+	// +optional
+	OperatorChart *ChartReference `json:"operatorChart,omitempty"`
+
+	// Values holds the values used to render the chart of the submariner-operator, which take
+	// precedence over the ones generated by firefly.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Values *runtime.RawExtension `json:"values,omitempty"`
+}
+
+// SubmarinerCluster describes a member cluster connected by a submariner.
+type SubmarinerCluster struct {
+	// Name is the name of the member cluster in the karmada.
+	Name string `json:"name"`
+
+	// ClusterCIDR is the CIDR of the pods of the member cluster. If empty, it's discovered by
+	// the submariner-operator.
+	// +optional
+	ClusterCIDR string `json:"clusterCIDR,omitempty"`
+
+	// ServiceCIDR is the CIDR of the services of the member cluster. If empty, it's discovered
+	// by the submariner-operator.
+	// +optional
+	ServiceCIDR string `json:"serviceCIDR,omitempty"`
+}
+
+const (
+	// SubmarinerConditionReady indicates whether the broker and the submariner-operator are installed.
+	SubmarinerConditionReady = "Ready"
+
+	// SubmarinerConditionPaused indicates whether the reconciliation of the submariner is paused.
+	SubmarinerConditionPaused = "Paused"
+)
+
+// SubmarinerStatus is the status for a Submariner resource
+type SubmarinerStatus struct {
+	// observedGeneration is the most recent generation observed for this Submariner. It corresponds to the
+	// Submariner's generation, which is updated on mutation by the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase summarizes the status of the submariner. The conditions tell the details.
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+
+	// Resources is the list of resources which have been installed into the karmada-apiserver
+	// by the submariner.
+	// +optional
+	Resources []AddonResource `json:"resources,omitempty"`
+
+	// Represents the latest available observations of a submariner's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SubmarinerList is a list of Submariner resources
+type SubmarinerList struct {
+	metav1.TypeMeta `json:",inline"`
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Submariner `json:"items"`
+}
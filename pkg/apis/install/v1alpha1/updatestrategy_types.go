@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// UpdateStrategy describes how the changes of the pod templates of the components, e.g. of their images
+// or configurations, are rolled out. It applies to the components deployed as Deployments, and is
+// ignored if Chart is set. It's only available to karmadas, the changes of the components of a
+// clusterpedia are rolled out by the rolling update of their deployments right away.
+type UpdateStrategy struct {
+	// MaxUnavailable is the maximum number or percentage of the pods of a component which can be
+	// unavailable during its rolling update. Defaults to the default of Deployments, that is 25%.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// Canary, if set, rolls out the changes of a component to canary replicas first. The component
+	// is only updated once the canary replicas are available, otherwise the rollout is paused.
+	// +optional
+	Canary *CanaryStrategy `json:"canary,omitempty"`
+
+	// PauseAfter are the components after whose update the rollout is paused, e.g. karmada-apiserver.
+	// While the rollout is paused, the changes of the other components are held until it's resumed
+	// by changing the firefly.io/resume-rollout annotation of the karmada, e.g. setting it to the
+	// current time.
+	// +listType=set
+	// +optional
+	PauseAfter []string `json:"pauseAfter,omitempty"`
+}
+
+// CanaryStrategy describes the canary replicas a change of a component is rolled out to first.
+// They run alongside the pods of the component and serve its traffic as well.
+type CanaryStrategy struct {
+	// Replicas is the number of the canary replicas. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// ProgressDeadlineSeconds is the maximum time in seconds for the canary replicas to become
+	// available. If they don't, the canary fails and the rollout is paused. Defaults to 300.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+}
+
+const (
+	// RolloutPausedAfterComponent means the rollout is paused after the update of a component
+	// listed in spec.updateStrategy.pauseAfter.
+	RolloutPausedAfterComponent = "PausedAfterComponent"
+	// RolloutCanaryFailed means the rollout is paused because the canary replicas of a component
+	// didn't become available in time.
+	RolloutCanaryFailed = "CanaryFailed"
+)
+
+// RolloutStatus describes the rollout of the changes of the components of a karmada.
+type RolloutStatus struct {
+	// Paused indicates that the rollout is paused, the changes of the components which are not
+	// updated yet are held until it's resumed.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Component is the component which paused the rollout.
+	// +optional
+	Component string `json:"component,omitempty"`
+
+	// Reason is the reason why the rollout is paused, either PausedAfterComponent or CanaryFailed.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable message indicating details about the pause.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ResumedBy is the value of the firefly.io/resume-rollout annotation which resumed the rollout
+	// the last time.
+	// +optional
+	ResumedBy string `json:"resumedBy,omitempty"`
+
+	// LastTransitionTime is the last time the rollout was paused or resumed.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Canaries are the components whose canary deployments exist. A canary is removed once the change
+	// of its component is rolled out or reverted.
+	// +optional
+	// +listType=set
+	Canaries []string `json:"canaries,omitempty"`
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// UpgradePreflightCheckName is the name of a check run before the karmada is upgraded.
+type UpgradePreflightCheckName string
+
+const (
+	// UpgradePreflightCheckVersionSkew verifies that the upgrade neither downgrades the karmada nor
+	// skips a minor version.
+	UpgradePreflightCheckVersionSkew UpgradePreflightCheckName = "VersionSkew"
+	// UpgradePreflightCheckEtcdHealth verifies that the etcd of the karmada is healthy, as seen by the
+	// karmada-apiserver.
+	UpgradePreflightCheckEtcdHealth UpgradePreflightCheckName = "EtcdHealth"
+	// UpgradePreflightCheckEtcdSpace verifies that the database of the local etcd has room for the
+	// objects rewritten by the upgrade.
+	UpgradePreflightCheckEtcdSpace UpgradePreflightCheckName = "EtcdSpace"
+	// UpgradePreflightCheckStoredVersions verifies that no object is stored in a version of a CRD which
+	// is no longer served by the upgraded karmada.
+	UpgradePreflightCheckStoredVersions UpgradePreflightCheckName = "StoredVersions"
+	// UpgradePreflightCheckAgentVersionSkew verifies that the karmada-agents of the member clusters in
+	// pull mode stay within one minor version of the upgraded karmada.
+	UpgradePreflightCheckAgentVersionSkew UpgradePreflightCheckName = "AgentVersionSkew"
+)
+
+// UpgradePreflightCheck is the result of a check run before the karmada is upgraded.
+type UpgradePreflightCheck struct {
+	// Name is the name of the check.
+	Name UpgradePreflightCheckName `json:"name"`
+
+	// Passed is true if the upgrade is safe as far as the check is concerned.
+	Passed bool `json:"passed"`
+
+	// Message is a human readable message indicating details about the check.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// UpgradePreflightStatus describes the checks run before the karmada is upgraded from
+// status.karmadaVersion to spec.karmadaVersion. The upgrade is refused until they pass, unless it's
+// forced by the firefly.io/force-upgrade annotation.
+type UpgradePreflightStatus struct {
+	// FromVersion is the installed version of the karmada.
+	FromVersion string `json:"fromVersion"`
+
+	// ToVersion is the version the karmada is upgraded to.
+	ToVersion string `json:"toVersion"`
+
+	// Passed is true if all the checks passed or the upgrade is forced.
+	Passed bool `json:"passed"`
+
+	// Forced is true if the upgrade is forced by the firefly.io/force-upgrade annotation in spite of
+	// the failed checks.
+	// +optional
+	Forced bool `json:"forced,omitempty"`
+
+	// Checks are the results of the checks.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Checks []UpgradePreflightCheck `json:"checks,omitempty"`
+}
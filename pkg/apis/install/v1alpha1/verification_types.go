@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Verification is the built-in smoke test suite run against the control plane once a karmada is
+// installed or upgraded. The tests create a namespace in the karmada-apiserver, propagate a Deployment
+// to the member clusters and query the clusterpedia which uses the karmada as its control plane. The
+// suite is run once per installed version, and its results are reported in status.testReport, a failed
+// test doesn't roll the karmada back.
+type Verification struct {
+	// Clusters are the member clusters the Deployment of the propagation test is propagated to. If
+	// empty, it's propagated to all the member clusters, and the test is skipped if there's none.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// Timeout is how long the tests wait for the objects they create, e.g. for the Deployment to be
+	// applied to the member clusters, before they fail. Defaults to 5m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// TestResult is the result of the smoke test suite or of a test of it.
+type TestResult string
+
+const (
+	TestResultRunning TestResult = "Running"
+	TestResultPassed  TestResult = "Passed"
+	TestResultFailed  TestResult = "Failed"
+	TestResultSkipped TestResult = "Skipped"
+)
+
+// TestReport describes the last run of the smoke test suite of spec.verification.
+type TestReport struct {
+	// Version is the version of the karmada the suite is run against.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Result is the result of the suite, one of Running, Passed or Failed.
+	// +optional
+	Result TestResult `json:"result,omitempty"`
+
+	// StartTime is the time the suite was started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the suite was completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Tests are the results of the tests of the suite.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Tests []TestCaseStatus `json:"tests,omitempty"`
+}
+
+// TestCaseStatus describes the result of a test of the smoke test suite.
+type TestCaseStatus struct {
+	// Name is the name of the test, one of namespace, propagation or clusterpedia.
+	Name string `json:"name"`
+
+	// Result is the result of the test, one of Running, Passed, Failed or Skipped.
+	// +optional
+	Result TestResult `json:"result,omitempty"`
+
+	// Message is a human readable message indicating details about the result.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
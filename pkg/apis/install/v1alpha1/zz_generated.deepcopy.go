@@ -23,221 +23,240 @@ package v1alpha1
 
 import (
 	v1alpha2 "github.com/clusterpedia-io/api/cluster/v1alpha2"
-	corev1 "k8s.io/api/core/v1"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *APIServerComponent) DeepCopyInto(out *APIServerComponent) {
+func (in *APIServerAudit) DeepCopyInto(out *APIServerAudit) {
 	*out = *in
-	in.KubeAPIServer.DeepCopyInto(&out.KubeAPIServer)
-	in.KarmadaAggregratedAPIServer.DeepCopyInto(&out.KarmadaAggregratedAPIServer)
+	if in.PolicySecretRef != nil {
+		in, out := &in.PolicySecretRef, &out.PolicySecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Log != nil {
+		in, out := &in.Log, &out.Log
+		*out = new(AuditLogBackend)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(AuditWebhookBackend)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerComponent.
-func (in *APIServerComponent) DeepCopy() *APIServerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerAudit.
+func (in *APIServerAudit) DeepCopy() *APIServerAudit {
 	if in == nil {
 		return nil
 	}
-	out := new(APIServerComponent)
+	out := new(APIServerAudit)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterSynchroManagerComponent) DeepCopyInto(out *ClusterSynchroManagerComponent) {
+func (in *APIServerComponent) DeepCopyInto(out *APIServerComponent) {
 	*out = *in
-	out.ImageMeta = in.ImageMeta
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
+	in.KubeAPIServer.DeepCopyInto(&out.KubeAPIServer)
+	in.KarmadaAggregratedAPIServer.DeepCopyInto(&out.KarmadaAggregratedAPIServer)
+	in.KarmadaSearch.DeepCopyInto(&out.KarmadaSearch)
+	in.KarmadaMetricsAdapter.DeepCopyInto(&out.KarmadaMetricsAdapter)
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(APIServerIngress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(APIServerGateway)
 		**out = **in
 	}
-	if in.ExtraArgs != nil {
-		in, out := &in.ExtraArgs, &out.ExtraArgs
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(APIServerOIDC)
+		(*in).DeepCopyInto(*out)
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.FeatureGates != nil {
-		in, out := &in.FeatureGates, &out.FeatureGates
-		*out = make(map[string]bool, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.APIAudiences != nil {
+		in, out := &in.APIAudiences, &out.APIAudiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(APIServerAudit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(APIServerEncryption)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertSANs != nil {
+		in, out := &in.CertSANs, &out.CertSANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSynchroManagerComponent.
-func (in *ClusterSynchroManagerComponent) DeepCopy() *ClusterSynchroManagerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerComponent.
+func (in *APIServerComponent) DeepCopy() *APIServerComponent {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterSynchroManagerComponent)
+	out := new(APIServerComponent)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Clusterpedia) DeepCopyInto(out *Clusterpedia) {
+func (in *APIServerEncryption) DeepCopyInto(out *APIServerEncryption) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KMS != nil {
+		in, out := &in.KMS, &out.KMS
+		*out = new(EncryptionKMS)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Clusterpedia.
-func (in *Clusterpedia) DeepCopy() *Clusterpedia {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerEncryption.
+func (in *APIServerEncryption) DeepCopy() *APIServerEncryption {
 	if in == nil {
 		return nil
 	}
-	out := new(Clusterpedia)
+	out := new(APIServerEncryption)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Clusterpedia) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterpediaAPIServerComponent) DeepCopyInto(out *ClusterpediaAPIServerComponent) {
+func (in *APIServerGateway) DeepCopyInto(out *APIServerGateway) {
 	*out = *in
-	out.ImageMeta = in.ImageMeta
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
-		**out = **in
-	}
-	if in.ExtraArgs != nil {
-		in, out := &in.ExtraArgs, &out.ExtraArgs
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.FeatureGates != nil {
-		in, out := &in.FeatureGates, &out.FeatureGates
-		*out = make(map[string]bool, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	out.ParentRef = in.ParentRef
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaAPIServerComponent.
-func (in *ClusterpediaAPIServerComponent) DeepCopy() *ClusterpediaAPIServerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerGateway.
+func (in *APIServerGateway) DeepCopy() *APIServerGateway {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterpediaAPIServerComponent)
+	out := new(APIServerGateway)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterpediaControllerManagerComponent) DeepCopyInto(out *ClusterpediaControllerManagerComponent) {
+func (in *APIServerIngress) DeepCopyInto(out *APIServerIngress) {
 	*out = *in
-	out.ImageMeta = in.ImageMeta
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
 		**out = **in
 	}
-	if in.ExtraArgs != nil {
-		in, out := &in.ExtraArgs, &out.ExtraArgs
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.FeatureGates != nil {
-		in, out := &in.FeatureGates, &out.FeatureGates
-		*out = make(map[string]bool, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaControllerManagerComponent.
-func (in *ClusterpediaControllerManagerComponent) DeepCopy() *ClusterpediaControllerManagerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerIngress.
+func (in *APIServerIngress) DeepCopy() *APIServerIngress {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterpediaControllerManagerComponent)
+	out := new(APIServerIngress)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterpediaControlplaneProvider) DeepCopyInto(out *ClusterpediaControlplaneProvider) {
+func (in *APIServerOIDC) DeepCopyInto(out *APIServerOIDC) {
 	*out = *in
-	if in.SyncResources != nil {
-		in, out := &in.SyncResources, &out.SyncResources
-		*out = make([]v1alpha2.ClusterGroupResources, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.RequiredClaims != nil {
+		in, out := &in.RequiredClaims, &out.RequiredClaims
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
-	if in.Karmada != nil {
-		in, out := &in.Karmada, &out.Karmada
-		*out = new(ClusterpediaControlplaneProviderKarmada)
-		**out = **in
+	if in.SigningAlgs != nil {
+		in, out := &in.SigningAlgs, &out.SigningAlgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaControlplaneProvider.
-func (in *ClusterpediaControlplaneProvider) DeepCopy() *ClusterpediaControlplaneProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerOIDC.
+func (in *APIServerOIDC) DeepCopy() *APIServerOIDC {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterpediaControlplaneProvider)
+	out := new(APIServerOIDC)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterpediaControlplaneProviderKarmada) DeepCopyInto(out *ClusterpediaControlplaneProviderKarmada) {
+func (in *Addon) DeepCopyInto(out *Addon) {
 	*out = *in
-	out.LocalObjectReference = in.LocalObjectReference
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaControlplaneProviderKarmada.
-func (in *ClusterpediaControlplaneProviderKarmada) DeepCopy() *ClusterpediaControlplaneProviderKarmada {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Addon.
+func (in *Addon) DeepCopy() *Addon {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterpediaControlplaneProviderKarmada)
+	out := new(Addon)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Addon) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterpediaList) DeepCopyInto(out *ClusterpediaList) {
+func (in *AddonList) DeepCopyInto(out *AddonList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Clusterpedia, len(*in))
+		*out = make([]Addon, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -245,18 +264,18 @@ func (in *ClusterpediaList) DeepCopyInto(out *ClusterpediaList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaList.
-func (in *ClusterpediaList) DeepCopy() *ClusterpediaList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonList.
+func (in *AddonList) DeepCopy() *AddonList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterpediaList)
+	out := new(AddonList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterpediaList) DeepCopyObject() runtime.Object {
+func (in *AddonList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -264,241 +283,273 @@ func (in *ClusterpediaList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterpediaSpec) DeepCopyInto(out *ClusterpediaSpec) {
+func (in *AddonManifest) DeepCopyInto(out *AddonManifest) {
 	*out = *in
-	if in.ControlplaneProvider != nil {
-		in, out := &in.ControlplaneProvider, &out.ControlplaneProvider
-		*out = new(ClusterpediaControlplaneProvider)
-		(*in).DeepCopyInto(*out)
-	}
-	in.Storage.DeepCopyInto(&out.Storage)
-	in.APIServer.DeepCopyInto(&out.APIServer)
-	in.ControllerManager.DeepCopyInto(&out.ControllerManager)
-	in.ClusterpediaSynchroManager.DeepCopyInto(&out.ClusterpediaSynchroManager)
-	if in.FeatureGates != nil {
-		in, out := &in.FeatureGates, &out.FeatureGates
-		*out = make(map[string]bool, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	in.RawExtension.DeepCopyInto(&out.RawExtension)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonManifest.
+func (in *AddonManifest) DeepCopy() *AddonManifest {
+	if in == nil {
+		return nil
 	}
+	out := new(AddonManifest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonResource) DeepCopyInto(out *AddonResource) {
+	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaSpec.
-func (in *ClusterpediaSpec) DeepCopy() *ClusterpediaSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonResource.
+func (in *AddonResource) DeepCopy() *AddonResource {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterpediaSpec)
+	out := new(AddonResource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterpediaStatus) DeepCopyInto(out *ClusterpediaStatus) {
+func (in *AddonSpec) DeepCopyInto(out *AddonSpec) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+	in.Target.DeepCopyInto(&out.Target)
+	if in.Manifests != nil {
+		in, out := &in.Manifests, &out.Manifests
+		*out = make([]AddonManifest, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Chart != nil {
+		in, out := &in.Chart, &out.Chart
+		*out = new(ChartReference)
+		**out = **in
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaStatus.
-func (in *ClusterpediaStatus) DeepCopy() *ClusterpediaStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonSpec.
+func (in *AddonSpec) DeepCopy() *AddonSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterpediaStatus)
+	out := new(AddonSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterpediaStorageComponent) DeepCopyInto(out *ClusterpediaStorageComponent) {
+func (in *AddonStatus) DeepCopyInto(out *AddonStatus) {
 	*out = *in
-	if in.Postgres != nil {
-		in, out := &in.Postgres, &out.Postgres
-		*out = new(Postgres)
-		(*in).DeepCopyInto(*out)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]AddonResource, len(*in))
+		copy(*out, *in)
 	}
-	if in.MySQL != nil {
-		in, out := &in.MySQL, &out.MySQL
-		*out = new(MySQL)
-		(*in).DeepCopyInto(*out)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaStorageComponent.
-func (in *ClusterpediaStorageComponent) DeepCopy() *ClusterpediaStorageComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonStatus.
+func (in *AddonStatus) DeepCopy() *AddonStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterpediaStorageComponent)
+	out := new(AddonStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ControllerManagerComponent) DeepCopyInto(out *ControllerManagerComponent) {
+func (in *AddonTarget) DeepCopyInto(out *AddonTarget) {
 	*out = *in
-	in.KubeControllerManager.DeepCopyInto(&out.KubeControllerManager)
-	in.KarmadaControllerManager.DeepCopyInto(&out.KarmadaControllerManager)
-	in.FireflyKarmadaManager.DeepCopyInto(&out.FireflyKarmadaManager)
+	out.Karmada = in.Karmada
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerManagerComponent.
-func (in *ControllerManagerComponent) DeepCopy() *ControllerManagerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonTarget.
+func (in *AddonTarget) DeepCopy() *AddonTarget {
 	if in == nil {
 		return nil
 	}
-	out := new(ControllerManagerComponent)
+	out := new(AddonTarget)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Etcd) DeepCopyInto(out *Etcd) {
+func (in *AuditLogBackend) DeepCopyInto(out *AuditLogBackend) {
 	*out = *in
-	if in.Local != nil {
-		in, out := &in.Local, &out.Local
-		*out = new(LocalEtcd)
-		(*in).DeepCopyInto(*out)
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(int32)
+		**out = **in
 	}
-	if in.External != nil {
-		in, out := &in.External, &out.External
-		*out = new(ExternalEtcd)
-		(*in).DeepCopyInto(*out)
+	if in.MaxBackups != nil {
+		in, out := &in.MaxBackups, &out.MaxBackups
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		*out = new(int32)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Etcd.
-func (in *Etcd) DeepCopy() *Etcd {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLogBackend.
+func (in *AuditLogBackend) DeepCopy() *AuditLogBackend {
 	if in == nil {
 		return nil
 	}
-	out := new(Etcd)
+	out := new(AuditLogBackend)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExternalEtcd) DeepCopyInto(out *ExternalEtcd) {
+func (in *AuditWebhookBackend) DeepCopyInto(out *AuditWebhookBackend) {
 	*out = *in
-	if in.Endpoints != nil {
-		in, out := &in.Endpoints, &out.Endpoints
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out.KubeconfigSecretRef = in.KubeconfigSecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditWebhookBackend.
+func (in *AuditWebhookBackend) DeepCopy() *AuditWebhookBackend {
+	if in == nil {
+		return nil
 	}
-	if in.CAData != nil {
-		in, out := &in.CAData, &out.CAData
-		*out = make([]byte, len(*in))
-		copy(*out, *in)
+	out := new(AuditWebhookBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Autoscaling) DeepCopyInto(out *Autoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
 	}
-	if in.CertData != nil {
-		in, out := &in.CertData, &out.CertData
-		*out = make([]byte, len(*in))
-		copy(*out, *in)
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]v2.MetricSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.KeyData != nil {
-		in, out := &in.KeyData, &out.KeyData
-		*out = make([]byte, len(*in))
-		copy(*out, *in)
+	if in.Behavior != nil {
+		in, out := &in.Behavior, &out.Behavior
+		*out = new(v2.HorizontalPodAutoscalerBehavior)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalEtcd.
-func (in *ExternalEtcd) DeepCopy() *ExternalEtcd {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Autoscaling.
+func (in *Autoscaling) DeepCopy() *Autoscaling {
 	if in == nil {
 		return nil
 	}
-	out := new(ExternalEtcd)
+	out := new(Autoscaling)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FireflyKarmadaManagerComponent) DeepCopyInto(out *FireflyKarmadaManagerComponent) {
+func (in *AvailabilityPolicy) DeepCopyInto(out *AvailabilityPolicy) {
 	*out = *in
-	out.ImageMeta = in.ImageMeta
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
 		**out = **in
 	}
-	if in.Controllers != nil {
-		in, out := &in.Controllers, &out.Controllers
+	if in.TopologyKeys != nil {
+		in, out := &in.TopologyKeys, &out.TopologyKeys
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FireflyKarmadaManagerComponent.
-func (in *FireflyKarmadaManagerComponent) DeepCopy() *FireflyKarmadaManagerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AvailabilityPolicy.
+func (in *AvailabilityPolicy) DeepCopy() *AvailabilityPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(FireflyKarmadaManagerComponent)
+	out := new(AvailabilityPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageMeta) DeepCopyInto(out *ImageMeta) {
+func (in *CanaryStrategy) DeepCopyInto(out *CanaryStrategy) {
 	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMeta.
-func (in *ImageMeta) DeepCopy() *ImageMeta {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStrategy.
+func (in *CanaryStrategy) DeepCopy() *CanaryStrategy {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageMeta)
+	out := new(CanaryStrategy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Karmada) DeepCopyInto(out *Karmada) {
+func (in *ChartReference) DeepCopyInto(out *ChartReference) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Karmada.
-func (in *Karmada) DeepCopy() *Karmada {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartReference.
+func (in *ChartReference) DeepCopy() *ChartReference {
 	if in == nil {
 		return nil
 	}
-	out := new(Karmada)
+	out := new(ChartReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Karmada) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KarmadaAggregratedAPIServerComponent) DeepCopyInto(out *KarmadaAggregratedAPIServerComponent) {
+func (in *ClusterAgentComponent) DeepCopyInto(out *ClusterAgentComponent) {
 	*out = *in
 	out.ImageMeta = in.ImageMeta
 	if in.Replicas != nil {
@@ -517,94 +568,68 @@ func (in *KarmadaAggregratedAPIServerComponent) DeepCopyInto(out *KarmadaAggregr
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaAggregratedAPIServerComponent.
-func (in *KarmadaAggregratedAPIServerComponent) DeepCopy() *KarmadaAggregratedAPIServerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAgentComponent.
+func (in *ClusterAgentComponent) DeepCopy() *ClusterAgentComponent {
 	if in == nil {
 		return nil
 	}
-	out := new(KarmadaAggregratedAPIServerComponent)
+	out := new(ClusterAgentComponent)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KarmadaControllerManagerComponent) DeepCopyInto(out *KarmadaControllerManagerComponent) {
+func (in *ClusterAgentStatus) DeepCopyInto(out *ClusterAgentStatus) {
 	*out = *in
-	out.ImageMeta = in.ImageMeta
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
-		**out = **in
-	}
-	if in.Controllers != nil {
-		in, out := &in.Controllers, &out.Controllers
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.ExtraArgs != nil {
-		in, out := &in.ExtraArgs, &out.ExtraArgs
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	in.Resources.DeepCopyInto(&out.Resources)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaControllerManagerComponent.
-func (in *KarmadaControllerManagerComponent) DeepCopy() *KarmadaControllerManagerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAgentStatus.
+func (in *ClusterAgentStatus) DeepCopy() *ClusterAgentStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(KarmadaControllerManagerComponent)
+	out := new(ClusterAgentStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KarmadaDeschedulerComponent) DeepCopyInto(out *KarmadaDeschedulerComponent) {
+func (in *ClusterRegistration) DeepCopyInto(out *ClusterRegistration) {
 	*out = *in
-	if in.Enable != nil {
-		in, out := &in.Enable, &out.Enable
-		*out = new(bool)
-		**out = **in
-	}
-	out.ImageMeta = in.ImageMeta
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
-		**out = **in
-	}
-	if in.ExtraArgs != nil {
-		in, out := &in.ExtraArgs, &out.ExtraArgs
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	in.Resources.DeepCopyInto(&out.Resources)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaDeschedulerComponent.
-func (in *KarmadaDeschedulerComponent) DeepCopy() *KarmadaDeschedulerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistration.
+func (in *ClusterRegistration) DeepCopy() *ClusterRegistration {
 	if in == nil {
 		return nil
 	}
-	out := new(KarmadaDeschedulerComponent)
+	out := new(ClusterRegistration)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterRegistration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KarmadaList) DeepCopyInto(out *KarmadaList) {
+func (in *ClusterRegistrationList) DeepCopyInto(out *ClusterRegistrationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Karmada, len(*in))
+		*out = make([]ClusterRegistration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -612,18 +637,18 @@ func (in *KarmadaList) DeepCopyInto(out *KarmadaList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaList.
-func (in *KarmadaList) DeepCopy() *KarmadaList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistrationList.
+func (in *ClusterRegistrationList) DeepCopy() *ClusterRegistrationList {
 	if in == nil {
 		return nil
 	}
-	out := new(KarmadaList)
+	out := new(ClusterRegistrationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KarmadaList) DeepCopyObject() runtime.Object {
+func (in *ClusterRegistrationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -631,37 +656,54 @@ func (in *KarmadaList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KarmadaSchedulerComponent) DeepCopyInto(out *KarmadaSchedulerComponent) {
+func (in *ClusterRegistrationSpec) DeepCopyInto(out *ClusterRegistrationSpec) {
 	*out = *in
-	out.ImageMeta = in.ImageMeta
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
+	out.Karmada = in.Karmada
+	out.KubeconfigSecret = in.KubeconfigSecret
+	in.Agent.DeepCopyInto(&out.Agent)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistrationSpec.
+func (in *ClusterRegistrationSpec) DeepCopy() *ClusterRegistrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRegistrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRegistrationStatus) DeepCopyInto(out *ClusterRegistrationStatus) {
+	*out = *in
+	if in.Agent != nil {
+		in, out := &in.Agent, &out.Agent
+		*out = new(ClusterAgentStatus)
 		**out = **in
 	}
-	if in.ExtraArgs != nil {
-		in, out := &in.ExtraArgs, &out.ExtraArgs
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaSchedulerComponent.
-func (in *KarmadaSchedulerComponent) DeepCopy() *KarmadaSchedulerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistrationStatus.
+func (in *ClusterRegistrationStatus) DeepCopy() *ClusterRegistrationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(KarmadaSchedulerComponent)
+	out := new(ClusterRegistrationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KarmadaSchedulerEstimatorComponent) DeepCopyInto(out *KarmadaSchedulerEstimatorComponent) {
+func (in *ClusterSynchroManagerComponent) DeepCopyInto(out *ClusterSynchroManagerComponent) {
 	*out = *in
 	out.ImageMeta = in.ImageMeta
 	if in.Replicas != nil {
@@ -676,55 +718,64 @@ func (in *KarmadaSchedulerEstimatorComponent) DeepCopyInto(out *KarmadaScheduler
 			(*out)[key] = val
 		}
 	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Sharding != nil {
+		in, out := &in.Sharding, &out.Sharding
+		*out = new(ClusterSynchroSharding)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaSchedulerEstimatorComponent.
-func (in *KarmadaSchedulerEstimatorComponent) DeepCopy() *KarmadaSchedulerEstimatorComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSynchroManagerComponent.
+func (in *ClusterSynchroManagerComponent) DeepCopy() *ClusterSynchroManagerComponent {
 	if in == nil {
 		return nil
 	}
-	out := new(KarmadaSchedulerEstimatorComponent)
+	out := new(ClusterSynchroManagerComponent)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KarmadaSpec) DeepCopyInto(out *KarmadaSpec) {
+func (in *ClusterSynchroShardAssignment) DeepCopyInto(out *ClusterSynchroShardAssignment) {
 	*out = *in
-	in.Etcd.DeepCopyInto(&out.Etcd)
-	out.Networking = in.Networking
-	in.APIServer.DeepCopyInto(&out.APIServer)
-	in.Webhook.DeepCopyInto(&out.Webhook)
-	in.ControllerManager.DeepCopyInto(&out.ControllerManager)
-	in.Scheduler.DeepCopyInto(&out.Scheduler)
-	if in.FeatureGates != nil {
-		in, out := &in.FeatureGates, &out.FeatureGates
-		*out = make(map[string]bool, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaSpec.
-func (in *KarmadaSpec) DeepCopy() *KarmadaSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSynchroShardAssignment.
+func (in *ClusterSynchroShardAssignment) DeepCopy() *ClusterSynchroShardAssignment {
 	if in == nil {
 		return nil
 	}
-	out := new(KarmadaSpec)
+	out := new(ClusterSynchroShardAssignment)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KarmadaStatus) DeepCopyInto(out *KarmadaStatus) {
+func (in *ClusterSynchroSharding) DeepCopyInto(out *ClusterSynchroSharding) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+	if in.Assignments != nil {
+		in, out := &in.Assignments, &out.Assignments
+		*out = make([]ClusterSynchroShardAssignment, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -732,48 +783,46 @@ func (in *KarmadaStatus) DeepCopyInto(out *KarmadaStatus) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaStatus.
-func (in *KarmadaStatus) DeepCopy() *KarmadaStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSynchroSharding.
+func (in *ClusterSynchroSharding) DeepCopy() *ClusterSynchroSharding {
 	if in == nil {
 		return nil
 	}
-	out := new(KarmadaStatus)
+	out := new(ClusterSynchroSharding)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KarmadaWebhookComponent) DeepCopyInto(out *KarmadaWebhookComponent) {
+func (in *Clusterpedia) DeepCopyInto(out *Clusterpedia) {
 	*out = *in
-	out.ImageMeta = in.ImageMeta
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
-		**out = **in
-	}
-	if in.ExtraArgs != nil {
-		in, out := &in.ExtraArgs, &out.ExtraArgs
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	in.Resources.DeepCopyInto(&out.Resources)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaWebhookComponent.
-func (in *KarmadaWebhookComponent) DeepCopy() *KarmadaWebhookComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Clusterpedia.
+func (in *Clusterpedia) DeepCopy() *Clusterpedia {
 	if in == nil {
 		return nil
 	}
-	out := new(KarmadaWebhookComponent)
+	out := new(Clusterpedia)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Clusterpedia) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeAPIServerComponent) DeepCopyInto(out *KubeAPIServerComponent) {
+func (in *ClusterpediaAPIServerComponent) DeepCopyInto(out *ClusterpediaAPIServerComponent) {
 	*out = *in
 	out.ImageMeta = in.ImageMeta
 	if in.Replicas != nil {
@@ -788,10 +837,10 @@ func (in *KubeAPIServerComponent) DeepCopyInto(out *KubeAPIServerComponent) {
 			(*out)[key] = val
 		}
 	}
-	if in.CertSANs != nil {
-		in, out := &in.CertSANs, &out.CertSANs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
 	if in.FeatureGates != nil {
@@ -804,30 +853,46 @@ func (in *KubeAPIServerComponent) DeepCopyInto(out *KubeAPIServerComponent) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeAPIServerComponent.
-func (in *KubeAPIServerComponent) DeepCopy() *KubeAPIServerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaAPIServerComponent.
+func (in *ClusterpediaAPIServerComponent) DeepCopy() *ClusterpediaAPIServerComponent {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeAPIServerComponent)
+	out := new(ClusterpediaAPIServerComponent)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeControllerManagerComponent) DeepCopyInto(out *KubeControllerManagerComponent) {
+func (in *ClusterpediaAccess) DeepCopyInto(out *ClusterpediaAccess) {
 	*out = *in
-	out.ImageMeta = in.ImageMeta
-	if in.Replicas != nil {
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(ClusterpediaIngress)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaAccess.
+func (in *ClusterpediaAccess) DeepCopy() *ClusterpediaAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterpediaAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterpediaControllerManagerComponent) DeepCopyInto(out *ClusterpediaControllerManagerComponent) {
+	*out = *in
+	out.ImageMeta = in.ImageMeta
+	if in.Replicas != nil {
 		in, out := &in.Replicas, &out.Replicas
 		*out = new(int32)
 		**out = **in
 	}
-	if in.Controllers != nil {
-		in, out := &in.Controllers, &out.Controllers
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 	if in.ExtraArgs != nil {
 		in, out := &in.ExtraArgs, &out.ExtraArgs
 		*out = make(map[string]string, len(*in))
@@ -835,6 +900,11 @@ func (in *KubeControllerManagerComponent) DeepCopyInto(out *KubeControllerManage
 			(*out)[key] = val
 		}
 	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
 	if in.FeatureGates != nil {
 		in, out := &in.FeatureGates, &out.FeatureGates
@@ -846,155 +916,2691 @@ func (in *KubeControllerManagerComponent) DeepCopyInto(out *KubeControllerManage
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeControllerManagerComponent.
-func (in *KubeControllerManagerComponent) DeepCopy() *KubeControllerManagerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaControllerManagerComponent.
+func (in *ClusterpediaControllerManagerComponent) DeepCopy() *ClusterpediaControllerManagerComponent {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeControllerManagerComponent)
+	out := new(ClusterpediaControllerManagerComponent)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalEtcd) DeepCopyInto(out *LocalEtcd) {
+func (in *ClusterpediaControlplaneProvider) DeepCopyInto(out *ClusterpediaControlplaneProvider) {
 	*out = *in
-	out.ImageMeta = in.ImageMeta
-	if in.DataVolume != nil {
-		in, out := &in.DataVolume, &out.DataVolume
-		*out = new(corev1.PersistentVolumeClaimTemplate)
-		(*in).DeepCopyInto(*out)
+	if in.SyncResources != nil {
+		in, out := &in.SyncResources, &out.SyncResources
+		*out = make([]v1alpha2.ClusterGroupResources, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.ServerCertSANs != nil {
-		in, out := &in.ServerCertSANs, &out.ServerCertSANs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Karmada != nil {
+		in, out := &in.Karmada, &out.Karmada
+		*out = new(ClusterpediaControlplaneProviderKarmada)
+		**out = **in
 	}
-	if in.PeerCertSANs != nil {
-		in, out := &in.PeerCertSANs, &out.PeerCertSANs
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaControlplaneProvider.
+func (in *ClusterpediaControlplaneProvider) DeepCopy() *ClusterpediaControlplaneProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterpediaControlplaneProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterpediaControlplaneProviderKarmada) DeepCopyInto(out *ClusterpediaControlplaneProviderKarmada) {
+	*out = *in
+	out.LocalObjectReference = in.LocalObjectReference
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaControlplaneProviderKarmada.
+func (in *ClusterpediaControlplaneProviderKarmada) DeepCopy() *ClusterpediaControlplaneProviderKarmada {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterpediaControlplaneProviderKarmada)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterpediaIngress) DeepCopyInto(out *ClusterpediaIngress) {
+	*out = *in
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaIngress.
+func (in *ClusterpediaIngress) DeepCopy() *ClusterpediaIngress {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterpediaIngress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterpediaList) DeepCopyInto(out *ClusterpediaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Clusterpedia, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaList.
+func (in *ClusterpediaList) DeepCopy() *ClusterpediaList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterpediaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterpediaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterpediaRetention) DeepCopyInto(out *ClusterpediaRetention) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	out.DeletedObjectRetention = in.DeletedObjectRetention
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalEtcd.
-func (in *LocalEtcd) DeepCopy() *LocalEtcd {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaRetention.
+func (in *ClusterpediaRetention) DeepCopy() *ClusterpediaRetention {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalEtcd)
+	out := new(ClusterpediaRetention)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalMySQL) DeepCopyInto(out *LocalMySQL) {
+func (in *ClusterpediaSpec) DeepCopyInto(out *ClusterpediaSpec) {
 	*out = *in
-	out.ImageMeta = in.ImageMeta
+	if in.ControlplaneProvider != nil {
+		in, out := &in.ControlplaneProvider, &out.ControlplaneProvider
+		*out = new(ClusterpediaControlplaneProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Storage.DeepCopyInto(&out.Storage)
+	in.APIServer.DeepCopyInto(&out.APIServer)
+	in.ControllerManager.DeepCopyInto(&out.ControllerManager)
+	in.ClusterpediaSynchroManager.DeepCopyInto(&out.ClusterpediaSynchroManager)
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Chart != nil {
+		in, out := &in.Chart, &out.Chart
+		*out = new(ChartReference)
+		**out = **in
+	}
+	if in.ValuesOverride != nil {
+		in, out := &in.ValuesOverride, &out.ValuesOverride
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]Patch, len(*in))
+		copy(*out, *in)
+	}
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	in.AvailabilityPolicy.DeepCopyInto(&out.AvailabilityPolicy)
+	out.NetworkPolicy = in.NetworkPolicy
+	if in.Access != nil {
+		in, out := &in.Access, &out.Access
+		*out = new(ClusterpediaAccess)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(ClusterpediaRetention)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalMySQL.
-func (in *LocalMySQL) DeepCopy() *LocalMySQL {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaSpec.
+func (in *ClusterpediaSpec) DeepCopy() *ClusterpediaSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalMySQL)
+	out := new(ClusterpediaSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalPostgres) DeepCopyInto(out *LocalPostgres) {
+func (in *ClusterpediaStatus) DeepCopyInto(out *ClusterpediaStatus) {
 	*out = *in
-	out.ImageMeta = in.ImageMeta
+	if in.DatabaseCredentialsRotationTime != nil {
+		in, out := &in.DatabaseCredentialsRotationTime, &out.DatabaseCredentialsRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(ClusterpediaUpgrade)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalPostgres.
-func (in *LocalPostgres) DeepCopy() *LocalPostgres {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaStatus.
+func (in *ClusterpediaStatus) DeepCopy() *ClusterpediaStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalPostgres)
+	out := new(ClusterpediaStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MySQL) DeepCopyInto(out *MySQL) {
+func (in *ClusterpediaStorageComponent) DeepCopyInto(out *ClusterpediaStorageComponent) {
 	*out = *in
-	if in.Local != nil {
-		in, out := &in.Local, &out.Local
-		*out = new(LocalPostgres)
+	if in.Postgres != nil {
+		in, out := &in.Postgres, &out.Postgres
+		*out = new(Postgres)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MySQL != nil {
+		in, out := &in.MySQL, &out.MySQL
+		*out = new(MySQL)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialsRotationPeriod != nil {
+		in, out := &in.CredentialsRotationPeriod, &out.CredentialsRotationPeriod
+		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(StorageMigration)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MySQL.
-func (in *MySQL) DeepCopy() *MySQL {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaStorageComponent.
+func (in *ClusterpediaStorageComponent) DeepCopy() *ClusterpediaStorageComponent {
 	if in == nil {
 		return nil
 	}
-	out := new(MySQL)
+	out := new(ClusterpediaStorageComponent)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Networking) DeepCopyInto(out *Networking) {
+func (in *ClusterpediaUpgrade) DeepCopyInto(out *ClusterpediaUpgrade) {
 	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Networking.
-func (in *Networking) DeepCopy() *Networking {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterpediaUpgrade.
+func (in *ClusterpediaUpgrade) DeepCopy() *ClusterpediaUpgrade {
 	if in == nil {
 		return nil
 	}
-	out := new(Networking)
+	out := new(ClusterpediaUpgrade)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Postgres) DeepCopyInto(out *Postgres) {
+func (in *ComponentExtras) DeepCopyInto(out *ComponentExtras) {
 	*out = *in
-	if in.Local != nil {
-		in, out := &in.Local, &out.Local
-		*out = new(LocalPostgres)
-		**out = **in
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Postgres.
-func (in *Postgres) DeepCopy() *Postgres {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentExtras.
+func (in *ComponentExtras) DeepCopy() *ComponentExtras {
 	if in == nil {
 		return nil
 	}
-	out := new(Postgres)
+	out := new(ComponentExtras)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SchedulerComponent) DeepCopyInto(out *SchedulerComponent) {
+func (in *ControllerManagerComponent) DeepCopyInto(out *ControllerManagerComponent) {
 	*out = *in
-	in.KarmadaScheduler.DeepCopyInto(&out.KarmadaScheduler)
-	in.KarmadaDescheduler.DeepCopyInto(&out.KarmadaDescheduler)
-	in.KarmadaSchedulerEstimator.DeepCopyInto(&out.KarmadaSchedulerEstimator)
+	in.KubeControllerManager.DeepCopyInto(&out.KubeControllerManager)
+	in.KarmadaControllerManager.DeepCopyInto(&out.KarmadaControllerManager)
+	in.FireflyKarmadaManager.DeepCopyInto(&out.FireflyKarmadaManager)
+	in.MulticlusterCloudProvider.DeepCopyInto(&out.MulticlusterCloudProvider)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerComponent.
-func (in *SchedulerComponent) DeepCopy() *SchedulerComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerManagerComponent.
+func (in *ControllerManagerComponent) DeepCopy() *ControllerManagerComponent {
 	if in == nil {
 		return nil
 	}
-	out := new(SchedulerComponent)
+	out := new(ControllerManagerComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecovery) DeepCopyInto(out *DisasterRecovery) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.VolumeSnapshotLocations != nil {
+		in, out := &in.VolumeSnapshotLocations, &out.VolumeSnapshotLocations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecovery.
+func (in *DisasterRecovery) DeepCopy() *DisasterRecovery {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionKMS) DeepCopyInto(out *EncryptionKMS) {
+	*out = *in
+	if in.CacheSize != nil {
+		in, out := &in.CacheSize, &out.CacheSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionKMS.
+func (in *EncryptionKMS) DeepCopy() *EncryptionKMS {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptionKMS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EstimatorClusterOverride) DeepCopyInto(out *EstimatorClusterOverride) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.GRPCTLS != nil {
+		in, out := &in.GRPCTLS, &out.GRPCTLS
+		*out = new(EstimatorGRPCTLS)
+		**out = **in
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstimatorClusterOverride.
+func (in *EstimatorClusterOverride) DeepCopy() *EstimatorClusterOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(EstimatorClusterOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EstimatorGRPCTLS) DeepCopyInto(out *EstimatorGRPCTLS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstimatorGRPCTLS.
+func (in *EstimatorGRPCTLS) DeepCopy() *EstimatorGRPCTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(EstimatorGRPCTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EstimatorHealthCheck) DeepCopyInto(out *EstimatorHealthCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstimatorHealthCheck.
+func (in *EstimatorHealthCheck) DeepCopy() *EstimatorHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(EstimatorHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EstimatorService) DeepCopyInto(out *EstimatorService) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstimatorService.
+func (in *EstimatorService) DeepCopy() *EstimatorService {
+	if in == nil {
+		return nil
+	}
+	out := new(EstimatorService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EstimatorStatus) DeepCopyInto(out *EstimatorStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstimatorStatus.
+func (in *EstimatorStatus) DeepCopy() *EstimatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EstimatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Etcd) DeepCopyInto(out *Etcd) {
+	*out = *in
+	if in.Local != nil {
+		in, out := &in.Local, &out.Local
+		*out = new(LocalEtcd)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalEtcd)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Etcd.
+func (in *Etcd) DeepCopy() *Etcd {
+	if in == nil {
+		return nil
+	}
+	out := new(Etcd)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdDefragmentation) DeepCopyInto(out *EtcdDefragmentation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdDefragmentation.
+func (in *EtcdDefragmentation) DeepCopy() *EtcdDefragmentation {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdDefragmentation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdStatus) DeepCopyInto(out *EtcdStatus) {
+	*out = *in
+	if in.DBSize != nil {
+		in, out := &in.DBSize, &out.DBSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.DBSizeInUse != nil {
+		in, out := &in.DBSizeInUse, &out.DBSizeInUse
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Alarms != nil {
+		in, out := &in.Alarms, &out.Alarms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastDefragmentationTime != nil {
+		in, out := &in.LastDefragmentationTime, &out.LastDefragmentationTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdStatus.
+func (in *EtcdStatus) DeepCopy() *EtcdStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalEtcd) DeepCopyInto(out *ExternalEtcd) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CAData != nil {
+		in, out := &in.CAData, &out.CAData
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.CertData != nil {
+		in, out := &in.CertData, &out.CertData
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.KeyData != nil {
+		in, out := &in.KeyData, &out.KeyData
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalEtcd.
+func (in *ExternalEtcd) DeepCopy() *ExternalEtcd {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalEtcd)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FireflyKarmadaManagerComponent) DeepCopyInto(out *FireflyKarmadaManagerComponent) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	out.ImageMeta = in.ImageMeta
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Controllers != nil {
+		in, out := &in.Controllers, &out.Controllers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ComponentExtras.DeepCopyInto(&out.ComponentExtras)
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FireflyKarmadaManagerComponent.
+func (in *FireflyKarmadaManagerComponent) DeepCopy() *FireflyKarmadaManagerComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(FireflyKarmadaManagerComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayParentReference) DeepCopyInto(out *GatewayParentReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayParentReference.
+func (in *GatewayParentReference) DeepCopy() *GatewayParentReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayParentReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheck.
+func (in *HealthCheck) DeepCopy() *HealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Hook) DeepCopyInto(out *Hook) {
+	*out = *in
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(batchv1.JobTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JobRef != nil {
+		in, out := &in.JobRef, &out.JobRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hook.
+func (in *Hook) DeepCopy() *Hook {
+	if in == nil {
+		return nil
+	}
+	out := new(Hook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HookStatus) DeepCopyInto(out *HookStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HookStatus.
+func (in *HookStatus) DeepCopy() *HookStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HookStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Hooks) DeepCopyInto(out *Hooks) {
+	*out = *in
+	if in.PreInstall != nil {
+		in, out := &in.PreInstall, &out.PreInstall
+		*out = make([]Hook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostInstall != nil {
+		in, out := &in.PostInstall, &out.PostInstall
+		*out = make([]Hook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreUpgrade != nil {
+		in, out := &in.PreUpgrade, &out.PreUpgrade
+		*out = make([]Hook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostUpgrade != nil {
+		in, out := &in.PostUpgrade, &out.PostUpgrade
+		*out = make([]Hook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hooks.
+func (in *Hooks) DeepCopy() *Hooks {
+	if in == nil {
+		return nil
+	}
+	out := new(Hooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostCapability) DeepCopyInto(out *HostCapability) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostCapability.
+func (in *HostCapability) DeepCopy() *HostCapability {
+	if in == nil {
+		return nil
+	}
+	out := new(HostCapability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageMeta) DeepCopyInto(out *ImageMeta) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMeta.
+func (in *ImageMeta) DeepCopy() *ImageMeta {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageMeta)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePolicy) DeepCopyInto(out *ImagePolicy) {
+	*out = *in
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(ImageVerification)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePolicy.
+func (in *ImagePolicy) DeepCopy() *ImagePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageVerification) DeepCopyInto(out *ImageVerification) {
+	*out = *in
+	out.PublicKeysSecretRef = in.PublicKeysSecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVerification.
+func (in *ImageVerification) DeepCopy() *ImageVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Karmada) DeepCopyInto(out *Karmada) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Karmada.
+func (in *Karmada) DeepCopy() *Karmada {
+	if in == nil {
+		return nil
+	}
+	out := new(Karmada)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Karmada) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaAggregratedAPIServerComponent) DeepCopyInto(out *KarmadaAggregratedAPIServerComponent) {
+	*out = *in
+	out.ImageMeta = in.ImageMeta
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(Autoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ComponentExtras.DeepCopyInto(&out.ComponentExtras)
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaAggregratedAPIServerComponent.
+func (in *KarmadaAggregratedAPIServerComponent) DeepCopy() *KarmadaAggregratedAPIServerComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaAggregratedAPIServerComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaControllerManagerComponent) DeepCopyInto(out *KarmadaControllerManagerComponent) {
+	*out = *in
+	out.ImageMeta = in.ImageMeta
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Controllers != nil {
+		in, out := &in.Controllers, &out.Controllers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ComponentExtras.DeepCopyInto(&out.ComponentExtras)
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaControllerManagerComponent.
+func (in *KarmadaControllerManagerComponent) DeepCopy() *KarmadaControllerManagerComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaControllerManagerComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaDeschedulerComponent) DeepCopyInto(out *KarmadaDeschedulerComponent) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	out.ImageMeta = in.ImageMeta
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ComponentExtras.DeepCopyInto(&out.ComponentExtras)
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaDeschedulerComponent.
+func (in *KarmadaDeschedulerComponent) DeepCopy() *KarmadaDeschedulerComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaDeschedulerComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaHostCluster) DeepCopyInto(out *KarmadaHostCluster) {
+	*out = *in
+	out.KubeconfigSecretRef = in.KubeconfigSecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaHostCluster.
+func (in *KarmadaHostCluster) DeepCopy() *KarmadaHostCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaHostCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaHostClusterStatus) DeepCopyInto(out *KarmadaHostClusterStatus) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaHostClusterStatus.
+func (in *KarmadaHostClusterStatus) DeepCopy() *KarmadaHostClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaHostClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaHostClusters) DeepCopyInto(out *KarmadaHostClusters) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]KarmadaHostCluster, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaHostClusters.
+func (in *KarmadaHostClusters) DeepCopy() *KarmadaHostClusters {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaHostClusters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaList) DeepCopyInto(out *KarmadaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Karmada, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaList.
+func (in *KarmadaList) DeepCopy() *KarmadaList {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KarmadaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaMetricsAdapterComponent) DeepCopyInto(out *KarmadaMetricsAdapterComponent) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	out.ImageMeta = in.ImageMeta
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ComponentExtras.DeepCopyInto(&out.ComponentExtras)
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaMetricsAdapterComponent.
+func (in *KarmadaMetricsAdapterComponent) DeepCopy() *KarmadaMetricsAdapterComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaMetricsAdapterComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaRestoreStatus) DeepCopyInto(out *KarmadaRestoreStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaRestoreStatus.
+func (in *KarmadaRestoreStatus) DeepCopy() *KarmadaRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaSchedulerComponent) DeepCopyInto(out *KarmadaSchedulerComponent) {
+	*out = *in
+	out.ImageMeta = in.ImageMeta
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(KarmadaSchedulerConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ComponentExtras.DeepCopyInto(&out.ComponentExtras)
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaSchedulerComponent.
+func (in *KarmadaSchedulerComponent) DeepCopy() *KarmadaSchedulerComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaSchedulerComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaSchedulerConfiguration) DeepCopyInto(out *KarmadaSchedulerConfiguration) {
+	*out = *in
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(SchedulerPlugins)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]KarmadaSchedulerProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EstimatorTimeout != nil {
+		in, out := &in.EstimatorTimeout, &out.EstimatorTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaSchedulerConfiguration.
+func (in *KarmadaSchedulerConfiguration) DeepCopy() *KarmadaSchedulerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaSchedulerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaSchedulerEstimatorComponent) DeepCopyInto(out *KarmadaSchedulerEstimatorComponent) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	out.ImageMeta = in.ImageMeta
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ComponentExtras.DeepCopyInto(&out.ComponentExtras)
+	in.Resources.DeepCopyInto(&out.Resources)
+	out.Service = in.Service
+	if in.GRPCTLS != nil {
+		in, out := &in.GRPCTLS, &out.GRPCTLS
+		*out = new(EstimatorGRPCTLS)
+		**out = **in
+	}
+	if in.ClusterOverrides != nil {
+		in, out := &in.ClusterOverrides, &out.ClusterOverrides
+		*out = make([]EstimatorClusterOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(EstimatorHealthCheck)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaSchedulerEstimatorComponent.
+func (in *KarmadaSchedulerEstimatorComponent) DeepCopy() *KarmadaSchedulerEstimatorComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaSchedulerEstimatorComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaSchedulerProfile) DeepCopyInto(out *KarmadaSchedulerProfile) {
+	*out = *in
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(SchedulerPlugins)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaSchedulerProfile.
+func (in *KarmadaSchedulerProfile) DeepCopy() *KarmadaSchedulerProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaSchedulerProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaSearchComponent) DeepCopyInto(out *KarmadaSearchComponent) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	out.ImageMeta = in.ImageMeta
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ComponentExtras.DeepCopyInto(&out.ComponentExtras)
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaSearchComponent.
+func (in *KarmadaSearchComponent) DeepCopy() *KarmadaSearchComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaSearchComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaSeed) DeepCopyInto(out *KarmadaSeed) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]KarmadaSeedNamespace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaSeed.
+func (in *KarmadaSeed) DeepCopy() *KarmadaSeed {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaSeed)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaSeedNamespace) DeepCopyInto(out *KarmadaSeedNamespace) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ResourceQuota != nil {
+		in, out := &in.ResourceQuota, &out.ResourceQuota
+		*out = new(v1.ResourceQuotaSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LimitRange != nil {
+		in, out := &in.LimitRange, &out.LimitRange
+		*out = new(v1.LimitRangeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PropagationPolicy != nil {
+		in, out := &in.PropagationPolicy, &out.PropagationPolicy
+		*out = new(KarmadaSeedPropagationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaSeedNamespace.
+func (in *KarmadaSeedNamespace) DeepCopy() *KarmadaSeedNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaSeedNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaSeedPropagationPolicy) DeepCopyInto(out *KarmadaSeedPropagationPolicy) {
+	*out = *in
+	if in.ResourceSelectors != nil {
+		in, out := &in.ResourceSelectors, &out.ResourceSelectors
+		*out = make([]KarmadaSeedResourceSelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaSeedPropagationPolicy.
+func (in *KarmadaSeedPropagationPolicy) DeepCopy() *KarmadaSeedPropagationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaSeedPropagationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaSeedResourceSelector) DeepCopyInto(out *KarmadaSeedResourceSelector) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaSeedResourceSelector.
+func (in *KarmadaSeedResourceSelector) DeepCopy() *KarmadaSeedResourceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaSeedResourceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaSpec) DeepCopyInto(out *KarmadaSpec) {
+	*out = *in
+	in.Etcd.DeepCopyInto(&out.Etcd)
+	out.Networking = in.Networking
+	in.APIServer.DeepCopyInto(&out.APIServer)
+	in.Webhook.DeepCopyInto(&out.Webhook)
+	in.ControllerManager.DeepCopyInto(&out.ControllerManager)
+	in.Scheduler.DeepCopyInto(&out.Scheduler)
+	if in.ImagePolicy != nil {
+		in, out := &in.ImagePolicy, &out.ImagePolicy
+		*out = new(ImagePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Chart != nil {
+		in, out := &in.Chart, &out.Chart
+		*out = new(ChartReference)
+		**out = **in
+	}
+	if in.ValuesOverride != nil {
+		in, out := &in.ValuesOverride, &out.ValuesOverride
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]Patch, len(*in))
+		copy(*out, *in)
+	}
+	out.Kubeconfig = in.Kubeconfig
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	out.HealthCheck = in.HealthCheck
+	in.AvailabilityPolicy.DeepCopyInto(&out.AvailabilityPolicy)
+	out.NetworkPolicy = in.NetworkPolicy
+	if in.DisasterRecovery != nil {
+		in, out := &in.DisasterRecovery, &out.DisasterRecovery
+		*out = new(DisasterRecovery)
+		(*in).DeepCopyInto(*out)
+	}
+	in.UpdateStrategy.DeepCopyInto(&out.UpdateStrategy)
+	in.Hooks.DeepCopyInto(&out.Hooks)
+	if in.HostClusters != nil {
+		in, out := &in.HostClusters, &out.HostClusters
+		*out = new(KarmadaHostClusters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Seed != nil {
+		in, out := &in.Seed, &out.Seed
+		*out = new(KarmadaSeed)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make([]RegistryMirror, len(*in))
+		copy(*out, *in)
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(Verification)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaSpec.
+func (in *KarmadaSpec) DeepCopy() *KarmadaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaStatus) DeepCopyInto(out *KarmadaStatus) {
+	*out = *in
+	if in.EncryptionKeyRotationTime != nil {
+		in, out := &in.EncryptionKeyRotationTime, &out.EncryptionKeyRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ResolvedImages != nil {
+		in, out := &in.ResolvedImages, &out.ResolvedImages
+		*out = make([]ResolvedImage, len(*in))
+		copy(*out, *in)
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(KarmadaRestoreStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Estimators != nil {
+		in, out := &in.Estimators, &out.Estimators
+		*out = make([]EstimatorStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]HookStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpgradePreflight != nil {
+		in, out := &in.UpgradePreflight, &out.UpgradePreflight
+		*out = new(UpgradePreflightStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TestReport != nil {
+		in, out := &in.TestReport, &out.TestReport
+		*out = new(TestReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Etcd != nil {
+		in, out := &in.Etcd, &out.Etcd
+		*out = new(EtcdStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostClusters != nil {
+		in, out := &in.HostClusters, &out.HostClusters
+		*out = make([]KarmadaHostClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HostCapabilities != nil {
+		in, out := &in.HostCapabilities, &out.HostCapabilities
+		*out = make([]HostCapability, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaStatus.
+func (in *KarmadaStatus) DeepCopy() *KarmadaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarmadaWebhookComponent) DeepCopyInto(out *KarmadaWebhookComponent) {
+	*out = *in
+	out.ImageMeta = in.ImageMeta
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ComponentExtras.DeepCopyInto(&out.ComponentExtras)
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarmadaWebhookComponent.
+func (in *KarmadaWebhookComponent) DeepCopy() *KarmadaWebhookComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(KarmadaWebhookComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeAPIServerComponent) DeepCopyInto(out *KubeAPIServerComponent) {
+	*out = *in
+	out.ImageMeta = in.ImageMeta
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(Autoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ComponentExtras.DeepCopyInto(&out.ComponentExtras)
+	if in.CertSANs != nil {
+		in, out := &in.CertSANs, &out.CertSANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeAPIServerComponent.
+func (in *KubeAPIServerComponent) DeepCopy() *KubeAPIServerComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeAPIServerComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeControllerManagerComponent) DeepCopyInto(out *KubeControllerManagerComponent) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	out.ImageMeta = in.ImageMeta
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Controllers != nil {
+		in, out := &in.Controllers, &out.Controllers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ComponentExtras.DeepCopyInto(&out.ComponentExtras)
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeControllerManagerComponent.
+func (in *KubeControllerManagerComponent) DeepCopy() *KubeControllerManagerComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeControllerManagerComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeconfigSpec) DeepCopyInto(out *KubeconfigSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeconfigSpec.
+func (in *KubeconfigSpec) DeepCopy() *KubeconfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeconfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalEtcd) DeepCopyInto(out *LocalEtcd) {
+	*out = *in
+	out.ImageMeta = in.ImageMeta
+	if in.DataVolume != nil {
+		in, out := &in.DataVolume, &out.DataVolume
+		*out = new(v1.PersistentVolumeClaimTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServerCertSANs != nil {
+		in, out := &in.ServerCertSANs, &out.ServerCertSANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PeerCertSANs != nil {
+		in, out := &in.PeerCertSANs, &out.PeerCertSANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StorageSize != nil {
+		in, out := &in.StorageSize, &out.StorageSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QuotaBackendBytes != nil {
+		in, out := &in.QuotaBackendBytes, &out.QuotaBackendBytes
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Defragmentation != nil {
+		in, out := &in.Defragmentation, &out.Defragmentation
+		*out = new(EtcdDefragmentation)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalEtcd.
+func (in *LocalEtcd) DeepCopy() *LocalEtcd {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalEtcd)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalMySQL) DeepCopyInto(out *LocalMySQL) {
+	*out = *in
+	out.ImageMeta = in.ImageMeta
+	in.PersistentStorage.DeepCopyInto(&out.PersistentStorage)
+	if in.Operator != nil {
+		in, out := &in.Operator, &out.Operator
+		*out = new(MySQLOperator)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalMySQL.
+func (in *LocalMySQL) DeepCopy() *LocalMySQL {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalMySQL)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalPostgres) DeepCopyInto(out *LocalPostgres) {
+	*out = *in
+	out.ImageMeta = in.ImageMeta
+	in.PersistentStorage.DeepCopyInto(&out.PersistentStorage)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalPostgres.
+func (in *LocalPostgres) DeepCopy() *LocalPostgres {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalPostgres)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Logging) DeepCopyInto(out *Logging) {
+	*out = *in
+	if in.Verbosity != nil {
+		in, out := &in.Verbosity, &out.Verbosity
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Logging.
+func (in *Logging) DeepCopy() *Logging {
+	if in == nil {
+		return nil
+	}
+	out := new(Logging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Monitoring) DeepCopyInto(out *Monitoring) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Monitoring.
+func (in *Monitoring) DeepCopy() *Monitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(Monitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MulticlusterCloudProviderComponent) DeepCopyInto(out *MulticlusterCloudProviderComponent) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CloudConfig != nil {
+		in, out := &in.CloudConfig, &out.CloudConfig
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	out.ImageMeta = in.ImageMeta
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ComponentExtras.DeepCopyInto(&out.ComponentExtras)
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MulticlusterCloudProviderComponent.
+func (in *MulticlusterCloudProviderComponent) DeepCopy() *MulticlusterCloudProviderComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(MulticlusterCloudProviderComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MySQL) DeepCopyInto(out *MySQL) {
+	*out = *in
+	if in.Local != nil {
+		in, out := &in.Local, &out.Local
+		*out = new(LocalMySQL)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MySQL.
+func (in *MySQL) DeepCopy() *MySQL {
+	if in == nil {
+		return nil
+	}
+	out := new(MySQL)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MySQLOperator) DeepCopyInto(out *MySQLOperator) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Routers != nil {
+		in, out := &in.Routers, &out.Routers
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MySQLOperator.
+func (in *MySQLOperator) DeepCopy() *MySQLOperator {
+	if in == nil {
+		return nil
+	}
+	out := new(MySQLOperator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicy) DeepCopyInto(out *NetworkPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicy.
+func (in *NetworkPolicy) DeepCopy() *NetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Networking) DeepCopyInto(out *Networking) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Networking.
+func (in *Networking) DeepCopy() *Networking {
+	if in == nil {
+		return nil
+	}
+	out := new(Networking)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Patch) DeepCopyInto(out *Patch) {
+	*out = *in
+	out.Target = in.Target
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Patch.
+func (in *Patch) DeepCopy() *Patch {
+	if in == nil {
+		return nil
+	}
+	out := new(Patch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchTarget) DeepCopyInto(out *PatchTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchTarget.
+func (in *PatchTarget) DeepCopy() *PatchTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(PatchTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentStorage) DeepCopyInto(out *PersistentStorage) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentStorage.
+func (in *PersistentStorage) DeepCopy() *PersistentStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Postgres) DeepCopyInto(out *Postgres) {
+	*out = *in
+	if in.Local != nil {
+		in, out := &in.Local, &out.Local
+		*out = new(LocalPostgres)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Postgres.
+func (in *Postgres) DeepCopy() *Postgres {
+	if in == nil {
+		return nil
+	}
+	out := new(Postgres)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryMirror) DeepCopyInto(out *RegistryMirror) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryMirror.
+func (in *RegistryMirror) DeepCopy() *RegistryMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedImage) DeepCopyInto(out *ResolvedImage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedImage.
+func (in *ResolvedImage) DeepCopy() *ResolvedImage {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Canaries != nil {
+		in, out := &in.Canaries, &out.Canaries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerComponent) DeepCopyInto(out *SchedulerComponent) {
+	*out = *in
+	in.KarmadaScheduler.DeepCopyInto(&out.KarmadaScheduler)
+	in.KarmadaDescheduler.DeepCopyInto(&out.KarmadaDescheduler)
+	in.KarmadaSchedulerEstimator.DeepCopyInto(&out.KarmadaSchedulerEstimator)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerComponent.
+func (in *SchedulerComponent) DeepCopy() *SchedulerComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerPlugins) DeepCopyInto(out *SchedulerPlugins) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerPlugins.
+func (in *SchedulerPlugins) DeepCopy() *SchedulerPlugins {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerPlugins)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSync) DeepCopyInto(out *SecretSync) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSync.
+func (in *SecretSync) DeepCopy() *SecretSync {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretSync) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSyncList) DeepCopyInto(out *SecretSyncList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecretSync, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSyncList.
+func (in *SecretSyncList) DeepCopy() *SecretSyncList {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSyncList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretSyncList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSyncObject) DeepCopyInto(out *SecretSyncObject) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSyncObject.
+func (in *SecretSyncObject) DeepCopy() *SecretSyncObject {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSyncObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSyncSpec) DeepCopyInto(out *SecretSyncSpec) {
+	*out = *in
+	out.Karmada = in.Karmada
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSyncSpec.
+func (in *SecretSyncSpec) DeepCopy() *SecretSyncSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSyncSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSyncStatus) DeepCopyInto(out *SecretSyncStatus) {
+	*out = *in
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]SecretSyncObject, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSyncStatus.
+func (in *SecretSyncStatus) DeepCopy() *SecretSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageMigration) DeepCopyInto(out *StorageMigration) {
+	*out = *in
+	out.ImageMeta = in.ImageMeta
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageMigration.
+func (in *StorageMigration) DeepCopy() *StorageMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Submariner) DeepCopyInto(out *Submariner) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Submariner.
+func (in *Submariner) DeepCopy() *Submariner {
+	if in == nil {
+		return nil
+	}
+	out := new(Submariner)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Submariner) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubmarinerCluster) DeepCopyInto(out *SubmarinerCluster) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubmarinerCluster.
+func (in *SubmarinerCluster) DeepCopy() *SubmarinerCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(SubmarinerCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubmarinerList) DeepCopyInto(out *SubmarinerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Submariner, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubmarinerList.
+func (in *SubmarinerList) DeepCopy() *SubmarinerList {
+	if in == nil {
+		return nil
+	}
+	out := new(SubmarinerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubmarinerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubmarinerSpec) DeepCopyInto(out *SubmarinerSpec) {
+	*out = *in
+	out.Karmada = in.Karmada
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]SubmarinerCluster, len(*in))
+		copy(*out, *in)
+	}
+	if in.BrokerChart != nil {
+		in, out := &in.BrokerChart, &out.BrokerChart
+		*out = new(ChartReference)
+		**out = **in
+	}
+	if in.OperatorChart != nil {
+		in, out := &in.OperatorChart, &out.OperatorChart
+		*out = new(ChartReference)
+		**out = **in
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubmarinerSpec.
+func (in *SubmarinerSpec) DeepCopy() *SubmarinerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SubmarinerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubmarinerStatus) DeepCopyInto(out *SubmarinerStatus) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]AddonResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubmarinerStatus.
+func (in *SubmarinerStatus) DeepCopy() *SubmarinerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SubmarinerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestCaseStatus) DeepCopyInto(out *TestCaseStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestCaseStatus.
+func (in *TestCaseStatus) DeepCopy() *TestCaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TestCaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestReport) DeepCopyInto(out *TestReport) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Tests != nil {
+		in, out := &in.Tests, &out.Tests
+		*out = make([]TestCaseStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestReport.
+func (in *TestReport) DeepCopy() *TestReport {
+	if in == nil {
+		return nil
+	}
+	out := new(TestReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateStrategy) DeepCopyInto(out *UpdateStrategy) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PauseAfter != nil {
+		in, out := &in.PauseAfter, &out.PauseAfter
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateStrategy.
+func (in *UpdateStrategy) DeepCopy() *UpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePreflightCheck) DeepCopyInto(out *UpgradePreflightCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePreflightCheck.
+func (in *UpgradePreflightCheck) DeepCopy() *UpgradePreflightCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePreflightCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePreflightStatus) DeepCopyInto(out *UpgradePreflightStatus) {
+	*out = *in
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]UpgradePreflightCheck, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePreflightStatus.
+func (in *UpgradePreflightStatus) DeepCopy() *UpgradePreflightStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePreflightStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Verification) DeepCopyInto(out *Verification) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Verification.
+func (in *Verification) DeepCopy() *Verification {
+	if in == nil {
+		return nil
+	}
+	out := new(Verification)
 	in.DeepCopyInto(out)
 	return out
 }
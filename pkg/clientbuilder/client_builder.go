@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientbuilder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/discovery"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/controller-manager/pkg/clientbuilder"
+	"k8s.io/klog/v2"
+)
+
+// ClientConnectionOverride overrides the QPS and Burst of the clients built for a controller.
+type ClientConnectionOverride struct {
+	// QPS controls the number of queries per second allowed for the clients.
+	QPS float32
+	// Burst allows extra queries to accumulate when the clients are exceeding their rate.
+	Burst int
+	// Timeout is the timeout of each request of the clients, it's not overridden if it's 0.
+	Timeout time.Duration
+}
+
+// ParseClientConnectionOverrides parses overrides in the form of <name>=<qps>:<burst>[:<timeout>],
+// the keys of the given map are the names passed to the client builders.
+func ParseClientConnectionOverrides(overrides map[string]string) (map[string]ClientConnectionOverride, error) {
+	result := make(map[string]ClientConnectionOverride, len(overrides))
+	for name, value := range overrides {
+		qpsValue, burstValue, ok := strings.Cut(value, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid client connection override %s=%s, expected <qps>:<burst>[:<timeout>]", name, value)
+		}
+		burstValue, timeoutValue, hasTimeout := strings.Cut(burstValue, ":")
+		qps, err := strconv.ParseFloat(qpsValue, 32)
+		if err != nil || qps <= 0 {
+			return nil, fmt.Errorf("invalid qps %q of client connection override %s, it must be a positive number", qpsValue, name)
+		}
+		burst, err := strconv.Atoi(burstValue)
+		if err != nil || burst <= 0 {
+			return nil, fmt.Errorf("invalid burst %q of client connection override %s, it must be a positive integer", burstValue, name)
+		}
+		var timeout time.Duration
+		if hasTimeout {
+			timeout, err = time.ParseDuration(timeoutValue)
+			if err != nil || timeout <= 0 {
+				return nil, fmt.Errorf("invalid timeout %q of client connection override %s, it must be a positive duration", timeoutValue, name)
+			}
+		}
+		result[name] = ClientConnectionOverride{QPS: float32(qps), Burst: burst, Timeout: timeout}
+	}
+	return result, nil
+}
+
+// make sure that SimpleControllerClientBuilder implements ControllerClientBuilder
+var _ clientbuilder.ControllerClientBuilder = SimpleControllerClientBuilder{}
+
+// SimpleControllerClientBuilder returns a fixed client with different user agents of the
+// form <UserAgent>/<name>. The QPS and Burst of the clients can be overridden per name.
+type SimpleControllerClientBuilder struct {
+	// ClientConfig is a skeleton config to clone and use as the basis for each controller client
+	ClientConfig *restclient.Config
+
+	// UserAgent is the prefix of the user agents, usually it's the name of the component.
+	// If empty, the default kubernetes user agent is used.
+	UserAgent string
+
+	// Overrides holds the QPS and Burst of the clients keyed by the names passed to the builder.
+	// The clients whose names are not listed use the QPS and Burst of the ClientConfig.
+	Overrides map[string]ClientConnectionOverride
+
+	// TrafficShaping shapes the requests of the clients, e.g. their timeouts.
+	TrafficShaping TrafficShaping
+}
+
+// Config returns a client config for a fixed client
+func (b SimpleControllerClientBuilder) Config(name string) (*restclient.Config, error) {
+	clientConfig := restclient.CopyConfig(b.ClientConfig)
+	if b.UserAgent != "" {
+		clientConfig.UserAgent = fmt.Sprintf("%s/%s", b.UserAgent, name)
+	} else {
+		restclient.AddUserAgent(clientConfig, name)
+	}
+	if override, ok := b.Overrides[name]; ok {
+		clientConfig.QPS = override.QPS
+		clientConfig.Burst = override.Burst
+	}
+	b.TrafficShaping.apply(clientConfig, name, b.Overrides)
+	return clientConfig, nil
+}
+
+// ConfigOrDie returns a client config if no error from previous config func.
+// If it gets an error getting the client, it will log the error and kill the process it's running in.
+func (b SimpleControllerClientBuilder) ConfigOrDie(name string) *restclient.Config {
+	clientConfig, err := b.Config(name)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return clientConfig
+}
+
+// Client returns a clientset.Interface built from the ClientBuilder
+func (b SimpleControllerClientBuilder) Client(name string) (clientset.Interface, error) {
+	clientConfig, err := b.Config(name)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.NewForConfig(clientConfig)
+}
+
+// ClientOrDie returns a clientset.interface built from the ClientBuilder with no error.
+// If it gets an error getting the client, it will log the error and kill the process it's running in.
+func (b SimpleControllerClientBuilder) ClientOrDie(name string) clientset.Interface {
+	client, err := b.Client(name)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return client
+}
+
+// DiscoveryClient returns a discovery.DiscoveryInterface built from the ClientBuilder
+// Discovery is special because it will artificially pump the burst quite high to handle the many discovery requests.
+func (b SimpleControllerClientBuilder) DiscoveryClient(name string) (discovery.DiscoveryInterface, error) {
+	clientConfig, err := b.Config(name)
+	if err != nil {
+		return nil, err
+	}
+	// Discovery makes a lot of requests infrequently.  This allows the burst to succeed and refill to happen
+	// in just a few seconds.
+	clientConfig.Burst = 200
+	clientConfig.QPS = 20
+	return clientset.NewForConfig(clientConfig)
+}
+
+// DiscoveryClientOrDie returns a discovery.DiscoveryInterface built from the ClientBuilder with no error.
+// Discovery is special because it will artificially pump the burst quite high to handle the many discovery requests.
+// If it gets an error getting the client, it will log the error and kill the process it's running in.
+func (b SimpleControllerClientBuilder) DiscoveryClientOrDie(name string) discovery.DiscoveryInterface {
+	client, err := b.DiscoveryClient(name)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return client
+}
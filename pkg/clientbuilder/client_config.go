@@ -0,0 +1,52 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientbuilder
+
+import (
+	"fmt"
+
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+)
+
+// LoadClientConfig loads the client config the clients are built from. The master overrides the
+// server of the kubeconfig file. If no kubeconfig file is given, the in-cluster config of the
+// service account of the pod is used instead, whose server is also overridden by the master.
+//
+// The credentials are never cached for the lifetime of the process. They're rotated by client-go
+// if they're read from a token file, e.g. a projected service account token, or issued by an exec
+// credential plugin, e.g. the IAM authenticator of a cloud provider. The plugin is run without
+// stdin, so it mustn't be interactive.
+func LoadClientConfig(master, kubeconfigPath string) (*restclient.Config, error) {
+	if kubeconfigPath == "" {
+		config, err := restclient.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("no kubeconfig is given and the in-cluster config can't be loaded: %v", err)
+		}
+		if master != "" {
+			config.Host = master
+		}
+		klog.V(2).InfoS("No kubeconfig is given, using the in-cluster config", "server", config.Host)
+		return config, nil
+	}
+
+	loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath}
+	overrides := &clientcmd.ConfigOverrides{ClusterInfo: clientcmdapi.Cluster{Server: master}}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+}
@@ -37,18 +37,21 @@ type FireflyControllerClientBuilder interface {
 // make sure that SimpleFireflyControllerClientBuilder implements FireflyControllerClientBuilder
 var _ FireflyControllerClientBuilder = SimpleFireflyControllerClientBuilder{}
 
-// NewSimpleFireflyControllerClientBuilder creates a SimpleFireflyControllerClientBuilder
-func NewSimpleFireflyControllerClientBuilder(config *restclient.Config) SimpleFireflyControllerClientBuilder {
+// NewSimpleFireflyControllerClientBuilder creates a SimpleFireflyControllerClientBuilder. The user agents of the
+// clients are <userAgent>/<name>, and their QPS and Burst can be overridden per name.
+func NewSimpleFireflyControllerClientBuilder(config *restclient.Config, userAgent string, overrides map[string]ClientConnectionOverride) SimpleFireflyControllerClientBuilder {
 	return SimpleFireflyControllerClientBuilder{
-		clientbuilder.SimpleControllerClientBuilder{
+		SimpleControllerClientBuilder{
 			ClientConfig: config,
+			UserAgent:    userAgent,
+			Overrides:    overrides,
 		},
 	}
 }
 
 // SimpleFireflyControllerClientBuilder returns a fixed client with different user agents
 type SimpleFireflyControllerClientBuilder struct {
-	clientbuilder.SimpleControllerClientBuilder
+	SimpleControllerClientBuilder
 }
 
 // DynamicClient returns a dynamic.Interface built from the ClientBuilder
@@ -40,18 +40,21 @@ type KarmadaControllerClientBuilder interface {
 // make sure that SimpleKarmadaControllerClientBuilder implements KarmadaControllerClientBuilder
 var _ KarmadaControllerClientBuilder = SimpleKarmadaControllerClientBuilder{}
 
-// NewSimpleKarmadaControllerClientBuilder creates a SimpleKarmadaControllerClientBuilder
-func NewSimpleKarmadaControllerClientBuilder(config *restclient.Config) SimpleKarmadaControllerClientBuilder {
+// NewSimpleKarmadaControllerClientBuilder creates a SimpleKarmadaControllerClientBuilder. The user agents of the
+// clients are <userAgent>/<name>, and their QPS and Burst can be overridden per name.
+func NewSimpleKarmadaControllerClientBuilder(config *restclient.Config, userAgent string, overrides map[string]ClientConnectionOverride) SimpleKarmadaControllerClientBuilder {
 	return SimpleKarmadaControllerClientBuilder{
-		clientbuilder.SimpleControllerClientBuilder{
+		SimpleControllerClientBuilder{
 			ClientConfig: config,
+			UserAgent:    userAgent,
+			Overrides:    overrides,
 		},
 	}
 }
 
 // SimpleKarmadaControllerClientBuilder returns a fixed client with different user agents
 type SimpleKarmadaControllerClientBuilder struct {
-	clientbuilder.SimpleControllerClientBuilder
+	SimpleControllerClientBuilder
 }
 
 // DynamicClient returns a dynamic.Interface built from the ClientBuilder
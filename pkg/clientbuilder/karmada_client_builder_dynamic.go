@@ -0,0 +1,194 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientbuilder
+
+import (
+	"fmt"
+
+	karmadaversioned "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/controller-manager/pkg/clientbuilder"
+	"k8s.io/klog/v2"
+
+	fireflyclient "github.com/carlory/firefly/pkg/karmada/generated/clientset/versioned"
+)
+
+// make sure that DynamicKarmadaControllerClientBuilder implements KarmadaControllerClientBuilder
+var _ KarmadaControllerClientBuilder = &DynamicKarmadaControllerClientBuilder{}
+
+// NewDynamicKarmadaControllerClientBuilder creates a DynamicKarmadaControllerClientBuilder. Each name passed
+// to the builder gets its own service account in the given namespace of the karmada apiserver, which is
+// created by the coreClient if it doesn't exist. The clients authenticate with the tokens requested for
+// the service accounts, which are refreshed before they expire, so the access of every controller can be
+// audited, restricted by RBAC and revoked on its own. The config mustn't carry any credentials, see
+// restclient.AnonymousClientConfig. The user agents of the clients are <userAgent>/<name>, and their QPS
+// and Burst can be overridden per name.
+func NewDynamicKarmadaControllerClientBuilder(config *restclient.Config, coreClient v1core.CoreV1Interface, namespace, userAgent string, overrides map[string]ClientConnectionOverride) *DynamicKarmadaControllerClientBuilder {
+	return &DynamicKarmadaControllerClientBuilder{
+		tokenClientBuilder: clientbuilder.NewDynamicClientBuilder(config, coreClient, namespace),
+		UserAgent:          userAgent,
+		Overrides:          overrides,
+	}
+}
+
+// DynamicKarmadaControllerClientBuilder returns clients authenticated as a service account per name
+type DynamicKarmadaControllerClientBuilder struct {
+	// tokenClientBuilder provisions the service accounts and caches the token sources of them.
+	tokenClientBuilder clientbuilder.ControllerClientBuilder
+
+	// UserAgent is the prefix of the user agents, usually it's the name of the component.
+	// If empty, the user agents are set by the tokenClientBuilder.
+	UserAgent string
+
+	// Overrides holds the QPS and Burst of the clients keyed by the names passed to the builder.
+	Overrides map[string]ClientConnectionOverride
+
+	// TrafficShaping shapes the requests of the clients, e.g. their timeouts.
+	TrafficShaping TrafficShaping
+}
+
+// Config returns a client config authenticated as the service account of the given name
+func (b *DynamicKarmadaControllerClientBuilder) Config(name string) (*restclient.Config, error) {
+	clientConfig, err := b.tokenClientBuilder.Config(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to provision the service account of %s: %v", name, err)
+	}
+	if b.UserAgent != "" {
+		clientConfig.UserAgent = fmt.Sprintf("%s/%s", b.UserAgent, name)
+	}
+	if override, ok := b.Overrides[name]; ok {
+		clientConfig.QPS = override.QPS
+		clientConfig.Burst = override.Burst
+	}
+	b.TrafficShaping.apply(clientConfig, name, b.Overrides)
+	return clientConfig, nil
+}
+
+// ConfigOrDie returns a client config if no error from previous config func.
+// If it gets an error getting the client, it will log the error and kill the process it's running in.
+func (b *DynamicKarmadaControllerClientBuilder) ConfigOrDie(name string) *restclient.Config {
+	clientConfig, err := b.Config(name)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return clientConfig
+}
+
+// Client returns a clientset.Interface built from the ClientBuilder
+func (b *DynamicKarmadaControllerClientBuilder) Client(name string) (clientset.Interface, error) {
+	clientConfig, err := b.Config(name)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.NewForConfig(clientConfig)
+}
+
+// ClientOrDie returns a clientset.interface built from the ClientBuilder with no error.
+// If it gets an error getting the client, it will log the error and kill the process it's running in.
+func (b *DynamicKarmadaControllerClientBuilder) ClientOrDie(name string) clientset.Interface {
+	client, err := b.Client(name)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return client
+}
+
+// DiscoveryClient returns a discovery.DiscoveryInterface built from the ClientBuilder
+// Discovery is special because it will artificially pump the burst quite high to handle the many discovery requests.
+func (b *DynamicKarmadaControllerClientBuilder) DiscoveryClient(name string) (discovery.DiscoveryInterface, error) {
+	clientConfig, err := b.Config(name)
+	if err != nil {
+		return nil, err
+	}
+	// Discovery makes a lot of requests infrequently.  This allows the burst to succeed and refill to happen
+	// in just a few seconds.
+	clientConfig.Burst = 200
+	clientConfig.QPS = 20
+	return clientset.NewForConfig(clientConfig)
+}
+
+// DiscoveryClientOrDie returns a discovery.DiscoveryInterface built from the ClientBuilder with no error.
+// Discovery is special because it will artificially pump the burst quite high to handle the many discovery requests.
+// If it gets an error getting the client, it will log the error and kill the process it's running in.
+func (b *DynamicKarmadaControllerClientBuilder) DiscoveryClientOrDie(name string) discovery.DiscoveryInterface {
+	client, err := b.DiscoveryClient(name)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return client
+}
+
+// DynamicClient returns a dynamic.Interface built from the ClientBuilder
+func (b *DynamicKarmadaControllerClientBuilder) DynamicClient(name string) (dynamic.Interface, error) {
+	clientConfig, err := b.Config(name)
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(clientConfig)
+}
+
+// DynamicClientOrDie returns a dynamic.Interface built from the ClientBuilder with no error.
+// If it gets an error getting the client, it will log the error and kill the process it's running in.
+func (b *DynamicKarmadaControllerClientBuilder) DynamicClientOrDie(name string) dynamic.Interface {
+	client, err := b.DynamicClient(name)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return client
+}
+
+// KarmadaClient returns a karmadaversioned.Interface built from the ClientBuilder
+func (b *DynamicKarmadaControllerClientBuilder) KarmadaClient(name string) (karmadaversioned.Interface, error) {
+	clientConfig, err := b.Config(name)
+	if err != nil {
+		return nil, err
+	}
+	return karmadaversioned.NewForConfig(clientConfig)
+}
+
+// KarmadaClientOrDie returns a karmadaversioned.interface built from the ClientBuilder with no error.
+// If it gets an error getting the client, it will log the error and kill the process it's running in.
+func (b *DynamicKarmadaControllerClientBuilder) KarmadaClientOrDie(name string) karmadaversioned.Interface {
+	client, err := b.KarmadaClient(name)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return client
+}
+
+// KarmadaFireflyClient returns a fireflyclient.Interface built from the ClientBuilder
+func (b *DynamicKarmadaControllerClientBuilder) KarmadaFireflyClient(name string) (fireflyclient.Interface, error) {
+	clientConfig, err := b.Config(name)
+	if err != nil {
+		return nil, err
+	}
+	return fireflyclient.NewForConfig(clientConfig)
+}
+
+// KarmadaFireflyClientOrDie returns a fireflyclient.interface built from the ClientBuilder with no error.
+// If it gets an error getting the client, it will log the error and kill the process it's running in.
+func (b *DynamicKarmadaControllerClientBuilder) KarmadaFireflyClientOrDie(name string) fireflyclient.Interface {
+	client, err := b.KarmadaFireflyClient(name)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return client
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientbuilder
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// KubeconfigReloader watches a kubeconfig file and reloads its credentials whenever its content
+// changes, e.g. the client certificate is rotated. The clients built from the wrapped config send
+// their requests with the reloaded credentials, so they needn't be rebuilt.
+//
+// Only the credentials and the certificate authority are reloaded. The clients keep talking to the
+// server loaded at startup, a change of the server requires a restart.
+type KubeconfigReloader struct {
+	master string
+	path   string
+	host   string
+
+	lock    sync.RWMutex
+	content []byte
+	// transport sends the requests with the reloaded credentials. It's nil until the file is changed,
+	// the transport built from the config at startup is used in the meantime.
+	transport http.RoundTripper
+}
+
+// NewKubeconfigReloader creates a reloader of the kubeconfig file the config is loaded from with
+// LoadClientConfig, and wraps the transport of the config. It returns nil if the config isn't
+// loaded from a file, that is the in-cluster config whose token is reloaded by client-go.
+func NewKubeconfigReloader(master, kubeconfigPath string, config *restclient.Config) (*KubeconfigReloader, error) {
+	if kubeconfigPath == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(kubeconfigPath)
+	if err != nil {
+		return nil, err
+	}
+	r := &KubeconfigReloader{
+		master:  master,
+		path:    kubeconfigPath,
+		host:    config.Host,
+		content: content,
+	}
+	config.Wrap(r.wrapTransport)
+	return r, nil
+}
+
+func (r *KubeconfigReloader) wrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &reloadingRoundTripper{reloader: r, initial: rt}
+}
+
+// Run watches the directory of the kubeconfig file until the context is done. The directory is
+// watched instead of the file, because a mounted secret is updated by replacing a symlink.
+func (r *KubeconfigReloader) Run(ctx context.Context) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		klog.ErrorS(err, "Failed to watch the kubeconfig, it won't be reloaded", "path", r.path)
+		return
+	}
+	defer watcher.Close()
+	if err := watcher.Add(filepath.Dir(r.path)); err != nil {
+		klog.ErrorS(err, "Failed to watch the kubeconfig, it won't be reloaded", "path", r.path)
+		return
+	}
+
+	klog.InfoS("Watching the kubeconfig for changes", "path", r.path)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if err := r.reload(); err != nil {
+				klog.ErrorS(err, "Failed to reload the kubeconfig", "path", r.path)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			klog.ErrorS(err, "Error watching the kubeconfig", "path", r.path)
+		}
+	}
+}
+
+// reload rebuilds the transport from the kubeconfig file if its content is changed.
+func (r *KubeconfigReloader) reload() error {
+	content, err := os.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+	r.lock.RLock()
+	unchanged := bytes.Equal(content, r.content)
+	r.lock.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	config, err := LoadClientConfig(r.master, r.path)
+	if err != nil {
+		return err
+	}
+	if config.Host != r.host {
+		klog.InfoS("The server of the kubeconfig is changed, restart to connect to it", "path", r.path, "server", config.Host)
+	}
+	// the user agent is set by the clients.
+	config.UserAgent = ""
+	transport, err := restclient.TransportFor(config)
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	previous := r.transport
+	r.content = content
+	r.transport = transport
+	r.lock.Unlock()
+
+	if previous != nil {
+		utilnet.CloseIdleConnectionsFor(previous)
+	}
+	klog.InfoS("Reloaded the kubeconfig", "path", r.path)
+	return nil
+}
+
+func (r *KubeconfigReloader) current() http.RoundTripper {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.transport
+}
+
+// reloadingRoundTripper sends the requests by the transport of the reloaded kubeconfig once it's
+// reloaded, otherwise by the initial transport.
+type reloadingRoundTripper struct {
+	reloader *KubeconfigReloader
+	initial  http.RoundTripper
+}
+
+var _ utilnet.RoundTripperWrapper = &reloadingRoundTripper{}
+
+func (rt *reloadingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := rt.reloader.current()
+	if transport == nil {
+		return rt.initial.RoundTrip(req)
+	}
+	// the credentials loaded at startup are replaced with the reloaded ones.
+	req = utilnet.CloneRequest(req)
+	req.Header.Del("Authorization")
+	return transport.RoundTrip(req)
+}
+
+func (rt *reloadingRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return rt.initial
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientbuilder
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// MemberClusterClientBuilder allows you to get clients and configs for the member clusters of the karmada,
+// which reach the clusters through the cluster proxy of the karmada-aggregated-apiserver, i.e.
+// /apis/cluster.karmada.io/v1alpha1/clusters/<cluster>/proxy, instead of their own kubeconfigs.
+// The requests are authorized by the karmada, so that the clients need the get permission of clusters/proxy.
+type MemberClusterClientBuilder interface {
+	Config(cluster, name string) (*restclient.Config, error)
+	ConfigOrDie(cluster, name string) *restclient.Config
+	Client(cluster, name string) (clientset.Interface, error)
+	ClientOrDie(cluster, name string) clientset.Interface
+	DynamicClient(cluster, name string) (dynamic.Interface, error)
+	DynamicClientOrDie(cluster, name string) dynamic.Interface
+}
+
+// make sure that SimpleMemberClusterClientBuilder implements MemberClusterClientBuilder
+var _ MemberClusterClientBuilder = SimpleMemberClusterClientBuilder{}
+
+// NewSimpleMemberClusterClientBuilder creates a SimpleMemberClusterClientBuilder which proxies the clients of
+// the member clusters through the karmada-apiserver reached by the given builder.
+func NewSimpleMemberClusterClientBuilder(karmadaClientBuilder KarmadaControllerClientBuilder) SimpleMemberClusterClientBuilder {
+	return SimpleMemberClusterClientBuilder{KarmadaClientBuilder: karmadaClientBuilder}
+}
+
+// SimpleMemberClusterClientBuilder returns the clients of the member clusters, which share the credentials,
+// user agents and overridden QPS and Burst of the clients of the karmada-apiserver with the same names.
+type SimpleMemberClusterClientBuilder struct {
+	// KarmadaClientBuilder builds the configs of the karmada-apiserver the proxied configs are derived from.
+	KarmadaClientBuilder KarmadaControllerClientBuilder
+}
+
+// Config returns a client config which reaches the member cluster through the cluster proxy
+func (b SimpleMemberClusterClientBuilder) Config(cluster, name string) (*restclient.Config, error) {
+	if cluster == "" {
+		return nil, fmt.Errorf("the name of the member cluster is required")
+	}
+	clientConfig, err := b.KarmadaClientBuilder.Config(name)
+	if err != nil {
+		return nil, err
+	}
+	clientConfig.Host = strings.TrimSuffix(clientConfig.Host, "/") + ClusterProxyPath(cluster)
+	return clientConfig, nil
+}
+
+// ConfigOrDie returns a client config of the member cluster if no error from previous config func.
+// If it gets an error getting the client, it will log the error and kill the process it's running in.
+func (b SimpleMemberClusterClientBuilder) ConfigOrDie(cluster, name string) *restclient.Config {
+	clientConfig, err := b.Config(cluster, name)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return clientConfig
+}
+
+// Client returns a clientset.Interface of the member cluster built from the ClientBuilder
+func (b SimpleMemberClusterClientBuilder) Client(cluster, name string) (clientset.Interface, error) {
+	clientConfig, err := b.Config(cluster, name)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.NewForConfig(clientConfig)
+}
+
+// ClientOrDie returns a clientset.interface of the member cluster built from the ClientBuilder with no error.
+// If it gets an error getting the client, it will log the error and kill the process it's running in.
+func (b SimpleMemberClusterClientBuilder) ClientOrDie(cluster, name string) clientset.Interface {
+	client, err := b.Client(cluster, name)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return client
+}
+
+// DynamicClient returns a dynamic.Interface of the member cluster built from the ClientBuilder
+func (b SimpleMemberClusterClientBuilder) DynamicClient(cluster, name string) (dynamic.Interface, error) {
+	clientConfig, err := b.Config(cluster, name)
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(clientConfig)
+}
+
+// DynamicClientOrDie returns a dynamic.Interface of the member cluster built from the ClientBuilder with no error.
+// If it gets an error getting the client, it will log the error and kill the process it's running in.
+func (b SimpleMemberClusterClientBuilder) DynamicClientOrDie(cluster, name string) dynamic.Interface {
+	client, err := b.DynamicClient(cluster, name)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return client
+}
+
+// ClusterProxyPath returns the path of the cluster proxy of the member cluster served by the
+// karmada-aggregated-apiserver.
+func ClusterProxyPath(cluster string) string {
+	return "/apis/cluster.karmada.io/v1alpha1/clusters/" + cluster + "/proxy"
+}
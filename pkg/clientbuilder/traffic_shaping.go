@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientbuilder
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	restclient "k8s.io/client-go/rest"
+)
+
+// BulkUserAgentHint is appended to the user agents of the bulk clients, e.g.
+// firefly-karmada-manager/firefly-node-controller (bulk), so that their requests are told apart
+// from the requests of the interactive users in the audit logs of the apiserver.
+const BulkUserAgentHint = "bulk"
+
+// TrafficShaping shapes the requests sent by the clients of the controllers, so that the bulk operations
+// of the controllers don't starve the interactive users of the apiserver.
+type TrafficShaping struct {
+	// RequestTimeout is the timeout of each request, which bounds the context of the request and is passed
+	// to the apiserver as its timeout parameter. 0 means no timeout. It's overridden per name by the
+	// Timeout of the ClientConnectionOverride. It must not be set for the clients of the informers, whose
+	// watches would be closed by the timeout.
+	RequestTimeout time.Duration
+	// BulkClients are the names of the clients sending bulk requests, whose user agents are hinted with
+	// BulkUserAgentHint.
+	BulkClients sets.String
+}
+
+// apply shapes the requests of the client of the given name built from the config.
+func (t TrafficShaping) apply(clientConfig *restclient.Config, name string, overrides map[string]ClientConnectionOverride) {
+	if t.RequestTimeout > 0 {
+		clientConfig.Timeout = t.RequestTimeout
+	}
+	if override, ok := overrides[name]; ok && override.Timeout > 0 {
+		clientConfig.Timeout = override.Timeout
+	}
+	if t.BulkClients.Has(name) {
+		clientConfig.UserAgent += " (" + BulkUserAgentHint + ")"
+	}
+}
@@ -24,12 +24,18 @@ const (
 
 	// KarmadaSystemNamespace defines the leader selection namespace for karmada components
 	KarmadaSystemNamespace = "karmada-system"
+	// KarmadaClusterNamespace defines the namespace in which karmada stores the secrets of the member clusters
+	KarmadaClusterNamespace = "karmada-cluster"
 	// KarmadaComponentEtcd defines the name of the built-in etcd cluster component
 	KarmadaComponentEtcd = "etcd"
 	// KarmadaComponentKubeAPIServer defines the name of the karmada-apiserver component
 	KarmadaComponentKubeAPIServer = "karmada-apiserver"
 	// KarmadaComponentAggregratedAPIServer defines the name of the karmada-aggregated-apiserver component
 	KarmadaComponentAggregratedAPIServer = "karmada-aggregated-apiserver"
+	// KarmadaComponentSearch defines the name of the karmada-search component
+	KarmadaComponentSearch = "karmada-search"
+	// KarmadaComponentMetricsAdapter defines the name of the karmada-metrics-adapter component
+	KarmadaComponentMetricsAdapter = "karmada-metrics-adapter"
 	// KarmadaComponentKubeControllerManager defines the name of the karmada-kube-controller-manager component
 	KarmadaComponentKubeControllerManager = "karmada-kube-controller-manager"
 	// KarmadaComponentScheduler defines the name of the karmada-scheduler component
@@ -42,6 +48,10 @@ const (
 	KarmadaComponentWebhook = "karmada-webhook"
 	// KarmadaComponentSchedulerEstimator defines the name of the karmada-scheduler-estimator component
 	KarmadaComponentSchedulerEstimator = "karmada-scheduler-estimator"
+	// KarmadaComponentAgent defines the name of the karmada-agent component deployed into a member cluster in pull mode
+	KarmadaComponentAgent = "karmada-agent"
+	// KarmadaComponentMulticlusterCloudProvider defines the name of the multicluster-cloud-provider component
+	KarmadaComponentMulticlusterCloudProvider = "multicluster-cloud-provider"
 	// FireflyComponentKarmadaManager defines the name of the karmada-karmada-manager component
 	FireflyComponentKarmadaManager = "firefly-karmada-manager"
 
@@ -59,4 +69,114 @@ const (
 	ClusterpediaComponentInternalStoragePostgres = "clusterpedia-internalstorage-postgres"
 	// ClusterpediaComponentInternalStorageMySQL defines the name of the clusterpedia-internalstorage-mysql component
 	ClusterpediaComponentInternalStorageMySQL = "clusterpedia-internalstorage-mysql"
+
+	// KarmadaInstanceLabel is the label set on all host cluster resources managed for a karmada,
+	// its value is the name of the karmada object.
+	KarmadaInstanceLabel = "install.firefly.io/karmada"
+	// ManagedByLabel is the label set on all host cluster resources managed for an install object, so that
+	// the orphaned ones can be found and cleaned up. Its value is always ManagedByFirefly.
+	ManagedByLabel = "app.firefly.io/managed-by"
+	// ManagedByFirefly is the value of ManagedByLabel.
+	ManagedByFirefly = "firefly"
+	// ComponentLabel is the label set on the host cluster resources of a component which is managed
+	// by firefly-karmada-manager, its value is the name of the component.
+	ComponentLabel = "firefly.io/component"
+	// ComponentEstimator is the value of ComponentLabel of the scheduler estimators, which is also
+	// set on their pods.
+	ComponentEstimator = "estimator"
+	// ClusterLabel is the label set on the host cluster resources created for a member cluster,
+	// its value is the name of the member cluster.
+	ClusterLabel = "firefly.io/cluster"
+	// ClusterRegistrationLabel is the label set on all resources created for a cluster registration, both
+	// in the karmada and in the member cluster, its value is the name of the cluster registration.
+	ClusterRegistrationLabel = "clusterregistration.install.firefly.io/name"
+	// ClusterSynchroShardLabel is the label set on the PediaClusters registered by firefly and on the
+	// clustersynchro-managers of a sharded clusterpedia, its value is the index of the shard.
+	ClusterSynchroShardLabel = "install.firefly.io/clustersynchro-shard"
+
+	// DryRunAnnotation is the annotation which makes the controllers only preview the reconciles of
+	// the annotated object if its value is "true", that is the mutations are sent as dry-run requests.
+	DryRunAnnotation = "firefly.io/dry-run"
+
+	// RotateDatabaseCredentialsAnnotation is the annotation which makes the clusterpedia controller
+	// rotate the password of the built-in database whenever its value is changed, e.g. set to the
+	// current time.
+	RotateDatabaseCredentialsAnnotation = "firefly.io/rotate-database-credentials"
+	// RotateEncryptionKeyAnnotation is the annotation which makes the karmada controller rotate the key of
+	// the encryption at rest of the karmada-apiserver whenever its value is changed, e.g. set to the current time.
+	RotateEncryptionKeyAnnotation = "firefly.io/rotate-encryption-key"
+	// RestoreFromBackupAnnotation is the annotation which makes the disasterrecovery controller restore
+	// the karmada from the Velero Backup named by its value. It's removed once the restore is started,
+	// whose progress is reported by the status of the karmada.
+	RestoreFromBackupAnnotation = "firefly.io/restore-from-backup"
+	// EncryptionConfigHashAnnotation is the annotation set on the pod template of the karmada-apiserver whose
+	// resources are encrypted at rest, its value is the hash of its EncryptionConfiguration. It lets the
+	// karmada-apiserver be restarted with the changed configuration, which it only loads at startup.
+	EncryptionConfigHashAnnotation = "firefly.io/encryption-config-hash"
+	// DatabaseCredentialsRotatedAtAnnotation is the annotation set on the pod templates of the
+	// components which connect to the built-in database, so that they are restarted with the
+	// rotated password.
+	DatabaseCredentialsRotatedAtAnnotation = "firefly.io/database-credentials-rotated-at"
+	// DeploymentSpecHashAnnotation is the annotation set on the deployments of the components, its
+	// value is the hash of the desired deployment except the args of its containers. It lets a
+	// change of only the args, e.g. the log verbosity, be patched into the deployment.
+	DeploymentSpecHashAnnotation = "firefly.io/deployment-spec-hash"
+	// LastAppliedConfigurationAnnotation is the annotation set on the objects created or patched by
+	// the CreateOrPatch helper, its value is the object applied last time. It lets the fields dropped
+	// from the desired object be removed, while the fields set by others, e.g. defaults, are kept.
+	LastAppliedConfigurationAnnotation = "firefly.io/last-applied-configuration"
+	// PodTemplateHashAnnotation is the annotation set on the deployments of the components, its value is the
+	// hash of their desired pod template. It lets the karmada controller tell a change to be rolled out
+	// according to spec.updateStrategy, e.g. to canary replicas first.
+	PodTemplateHashAnnotation = "firefly.io/pod-template-hash"
+	// ResumeRolloutAnnotation is the annotation which makes the karmada controller resume the paused rollout
+	// of the changes of the components whenever its value is changed, e.g. set to the current time.
+	ResumeRolloutAnnotation = "firefly.io/resume-rollout"
+	// CanaryLabel is the label set on the canary deployments of the components and their pods, its value is "true".
+	CanaryLabel = "firefly.io/canary"
+	// HookVersionAnnotation is the annotation set on the Jobs of the hooks of spec.hooks of the karmada, its value
+	// is the version of the karmada the hook is run for. The Job is created again for another version.
+	HookVersionAnnotation = "firefly.io/hook-version"
+	// ForceUpgradeAnnotation is the annotation which makes the karmada controller upgrade the karmada in spite
+	// of the failed upgrade preflight checks, its value must be the version the karmada is upgraded to, so
+	// that a later upgrade isn't forced by accident.
+	ForceUpgradeAnnotation = "firefly.io/force-upgrade"
+	// AdoptExistingResourcesAnnotation is the annotation which makes the controllers take ownership of the
+	// pre-existing deployments and services of the components of the annotated object if its value is "true",
+	// e.g. the ones of a manual install. Otherwise such resources are left untouched and reported as conflicts.
+	// An adopted deployment whose selector differs from the desired one is deleted along with its pods and
+	// created again, since the selector is immutable, so the component is down until the new pods are ready.
+	AdoptExistingResourcesAnnotation = "firefly.io/adopt-existing-resources"
+	// GRPCCertSerialAnnotation is the annotation set on the pod templates of the karmada-scheduler-estimators
+	// whose certificates are issued by firefly, its value is the serial number of the serving certificate.
+	// It lets the estimators be restarted with the rotated certificate, which they only load at startup.
+	GRPCCertSerialAnnotation = "firefly.io/grpc-cert-serial"
+	// SchedulerConfigHashAnnotation is the annotation set on the pod template of the karmada-scheduler whose
+	// configuration is set by the karmada, its value is the hash of the configuration. It lets the scheduler
+	// be restarted with the changed configuration, which it only loads at startup.
+	SchedulerConfigHashAnnotation = "firefly.io/scheduler-config-hash"
+	// AuditPolicyHashAnnotation is the annotation set on the pod template of the karmada-apiserver whose audit
+	// policy is set inline by the karmada, its value is the hash of the policy. It lets the karmada-apiserver
+	// be restarted with the changed policy, which it only loads at startup.
+	AuditPolicyHashAnnotation = "firefly.io/audit-policy-hash"
+	// ClusterCapacityAnnotation is the annotation set on the member clusters in the karmada by the node
+	// controller, its value is a JSON object of the total capacity of the cpu, memory, pods and allowed extended
+	// resources of the nodes of the cluster, e.g. {"cpu":"16","memory":"64Gi","nvidia.com/gpu":"4","pods":"220"}.
+	ClusterCapacityAnnotation = "firefly.io/resource-capacity"
+	// ClusterAllocatableAnnotation is the annotation set on the member clusters in the karmada by the node
+	// controller, its value is a JSON object of the total allocatable resources of the ready and schedulable
+	// nodes of the cluster, in the same format as ClusterCapacityAnnotation.
+	ClusterAllocatableAnnotation = "firefly.io/resource-allocatable"
+	// ClusterAcceleratorLabelsAnnotation is the annotation set on the member clusters in the karmada by the node
+	// controller, its value is a JSON object of the number of the nodes of the cluster by the values of the
+	// accelerator labels, e.g. {"nvidia.com/gpu.product":{"NVIDIA-A100-SXM4-40GB":2,"Tesla-T4":3}}.
+	ClusterAcceleratorLabelsAnnotation = "firefly.io/accelerator-labels"
+	// CertSANsAnnotation is the annotation set on the karmada-cert Secret, its value is the comma separated
+	// spec.apiServer.certSANs of the karmada which the certificate of the karmada-apiserver is signed for.
+	// It lets the SANs removed from the karmada be dropped from the certificate.
+	CertSANsAnnotation = "firefly.io/cert-sans"
+
+	// EstimatorClientCertSecret is the name of the Secret holding the client certificate which the
+	// karmada-scheduler and the karmada-descheduler use to connect to the karmada-scheduler-estimators.
+	EstimatorClientCertSecret = "karmada-scheduler-estimator-client-cert"
 )
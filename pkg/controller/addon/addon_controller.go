@@ -0,0 +1,472 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/prometheus/ratelimiter"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	fireflyclient "github.com/carlory/firefly/pkg/generated/clientset/versioned"
+	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
+	installlisters "github.com/carlory/firefly/pkg/generated/listers/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/helm"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/coalesce"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	informerutil "github.com/carlory/firefly/pkg/util/informer"
+	"github.com/carlory/firefly/pkg/util/notification"
+	phaseutil "github.com/carlory/firefly/pkg/util/phase"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
+)
+
+const (
+	// maxRetries is the number of times an addon will be retried before it is dropped out of the queue.
+	// With the current rate-limiter in use (5ms*2^(maxRetries-1)) the following numbers represent the
+	// sequence of delays between successive queuings of an addon.
+	//
+	// 5ms, 10ms, 20ms, 40ms, 80ms, 160ms, 320ms, 640ms, 1.3s, 2.6s, 5.1s, 10.2s, 20.4s, 41s, 82s
+	maxRetries = 15
+
+	// name of the addon controller finalizer
+	AddonControllerFinalizerName = "addon.install.firefly.io/finalizer"
+)
+
+// NewAddonController returns a new *Controller.
+func NewAddonController(
+	client clientset.Interface,
+	fireflyClient fireflyclient.Interface,
+	addonInformer installinformers.AddonInformer,
+	karmadaInformer installinformers.KarmadaInformer,
+	resyncPeriod time.Duration,
+	rateLimiter workqueue.RateLimiter) (*AddonController, error) {
+	broadcaster := record.NewBroadcaster()
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "addon-controller"})
+
+	if client != nil && client.CoreV1().RESTClient().GetRateLimiter() != nil {
+		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("addon_controller", client.CoreV1().RESTClient().GetRateLimiter())
+	}
+
+	ctrl := &AddonController{
+		client:           client,
+		fireflyClient:    fireflyClient,
+		addonsLister:     addonInformer.Lister(),
+		addonsSynced:     addonInformer.Informer().HasSynced,
+		karmadasLister:   karmadaInformer.Lister(),
+		karmadasSynced:   karmadaInformer.Informer().HasSynced,
+		queue:            coalesce.NewQueue(workqueue.NewNamedRateLimitingQueue(rateLimiter, "addon"), coalesce.DefaultWindow, coalesce.DefaultInterval),
+		workerLoopPeriod: time.Second,
+		eventBroadcaster: broadcaster,
+		eventRecorder:    recorder,
+		chartFetcher:     helm.NewFetcher(),
+	}
+
+	informerutil.AddEventHandler(addonInformer.Informer(), cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.addAddon,
+		UpdateFunc: ctrl.updateAddon,
+		DeleteFunc: ctrl.deleteAddon,
+	}, resyncPeriod)
+
+	// Addons are requeued when their target karmada is created or changed,
+	// so that an addon waiting for its karmada is installed as soon as possible.
+	karmadaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.addKarmada,
+		UpdateFunc: ctrl.updateKarmada,
+	})
+
+	return ctrl, nil
+}
+
+type AddonController struct {
+	client           clientset.Interface
+	fireflyClient    fireflyclient.Interface
+	eventBroadcaster record.EventBroadcaster
+	eventRecorder    record.EventRecorder
+
+	addonsLister installlisters.AddonLister
+	addonsSynced cache.InformerSynced
+
+	karmadasLister installlisters.KarmadaLister
+	karmadasSynced cache.InformerSynced
+
+	// chartFetcher loads the charts referenced by spec.chart.
+	chartFetcher *helm.Fetcher
+
+	// Addons that need to be updated. A channel is inappropriate here,
+	// because it allows an addon to be inserted multiple times and be
+	// processed more than necessary.
+	queue *coalesce.Queue
+
+	// workerLoopPeriod is the time between worker runs. The workers process the queue of addon changes.
+	workerLoopPeriod time.Duration
+}
+
+// Run will not return until stopCh is closed. workers determines how many
+// addons will be handled in parallel.
+func (ctrl *AddonController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+
+	// Start events processing pipeline.
+	ctrl.eventBroadcaster.StartStructuredLogging(0)
+	ctrl.eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: ctrl.client.CoreV1().Events("")})
+	defer ctrl.eventBroadcaster.Shutdown()
+
+	defer ctrl.queue.ShutDown()
+
+	klog.Infof("Starting addon controller")
+	defer klog.Infof("Shutting down addon controller")
+
+	if !cache.WaitForNamedCacheSync("addon", ctx.Done(), ctrl.addonsSynced, ctrl.karmadasSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, ctrl.worker, ctrl.workerLoopPeriod)
+	}
+	<-ctx.Done()
+}
+
+// worker runs a worker thread that just dequeues items, processes them, and
+// marks them done. You may run as many of these in parallel as you wish; the
+// workqueue guarantees that they will not end up processing the same addon
+// at the same time.
+func (ctrl *AddonController) worker(ctx context.Context) {
+	for ctrl.processNextWorkItem(ctx) {
+	}
+}
+
+func (ctrl *AddonController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := ctrl.queue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.queue.Done(key)
+
+	ctx, span := tracingutil.StartReconcile(ctx, "addon", key.(string))
+	err := ctrl.syncAddon(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.handleErr(err, key)
+
+	return true
+}
+
+func (ctrl *AddonController) addAddon(obj interface{}) {
+	addon := obj.(*installv1alpha1.Addon)
+	klog.V(4).InfoS("Adding addon", "addon", klog.KObj(addon))
+	ctrl.enqueue(addon)
+}
+
+func (ctrl *AddonController) updateAddon(old, cur interface{}) {
+	oldAddon := old.(*installv1alpha1.Addon)
+	curAddon := cur.(*installv1alpha1.Addon)
+	klog.V(4).InfoS("Updating addon", "addon", klog.KObj(oldAddon))
+	ctrl.enqueue(curAddon)
+}
+
+func (ctrl *AddonController) deleteAddon(obj interface{}) {
+	addon, ok := obj.(*installv1alpha1.Addon)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("couldn't get object from tombstone %#v", obj))
+			return
+		}
+		addon, ok = tombstone.Obj.(*installv1alpha1.Addon)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("tombstone contained object that is not an Addon %#v", obj))
+			return
+		}
+	}
+	klog.V(4).InfoS("Deleting addon", "addon", klog.KObj(addon))
+	ctrl.enqueue(addon)
+}
+
+func (ctrl *AddonController) addKarmada(obj interface{}) {
+	karmada := obj.(*installv1alpha1.Karmada)
+	ctrl.enqueueAddonsForKarmada(karmada)
+}
+
+func (ctrl *AddonController) updateKarmada(old, cur interface{}) {
+	curKarmada := cur.(*installv1alpha1.Karmada)
+	ctrl.enqueueAddonsForKarmada(curKarmada)
+}
+
+// enqueueAddonsForKarmada enqueues all addons which target the given karmada.
+func (ctrl *AddonController) enqueueAddonsForKarmada(karmada *installv1alpha1.Karmada) {
+	addons, err := ctrl.addonsLister.Addons(karmada.Namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, addon := range addons {
+		if addon.Spec.Target.Karmada.Name == karmada.Name {
+			ctrl.enqueueCoalesced(addon)
+		}
+	}
+}
+
+func (ctrl *AddonController) enqueue(addon *installv1alpha1.Addon) {
+	key, err := cache.MetaNamespaceKeyFunc(addon)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.Add(key)
+}
+
+// enqueueCoalesced enqueues the addon after the bursts of events of its secondary objects have settled.
+func (ctrl *AddonController) enqueueCoalesced(addon *installv1alpha1.Addon) {
+	key, err := cache.MetaNamespaceKeyFunc(addon)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.AddCoalesced(key)
+}
+
+func (ctrl *AddonController) handleErr(err error, key interface{}) {
+	if err == nil || errors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+		ctrl.queue.Forget(key)
+		return
+	}
+
+	ns, name, keyErr := cache.SplitMetaNamespaceKey(key.(string))
+	if keyErr != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+	}
+
+	notification.ReconcileFailed("addon", audit.ObjectReference{Kind: "Addon", Namespace: ns, Name: name}, ctrl.queue.NumRequeues(key)+1, err)
+	if ctrl.queue.NumRequeues(key) < maxRetries {
+		klog.V(2).InfoS("Error syncing addon, retrying", "addon", klog.KRef(ns, name), "err", err)
+		ctrl.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	klog.V(2).InfoS("Dropping addon out of the queue", "addon", klog.KRef(ns, name), "err", err)
+	ctrl.queue.Forget(key)
+}
+
+func (ctrl *AddonController) syncAddon(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+		return err
+	}
+
+	startTime := time.Now()
+	klog.V(4).InfoS("Started syncing addon", "addon", klog.KRef(namespace, name), "startTime", startTime)
+	defer func() {
+		klog.V(4).InfoS("Finished syncing addon", "addon", klog.KRef(namespace, name), "duration", time.Since(startTime))
+	}()
+
+	addon, err := ctrl.addonsLister.Addons(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		klog.V(2).InfoS("Addon has been deleted", "addon", klog.KRef(namespace, name))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Deep-copy otherwise we are mutating our cache.
+	addon = addon.DeepCopy()
+	ctx = audit.WithTrigger(ctx, addon)
+	ctx = dryrun.ForObject(ctx, addon)
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if addon.DeletionTimestamp.IsZero() {
+		// The object is not being deleted, so if it does not have our finalizer,
+		// then lets add the finalizer and update the object. This is equivalent
+		// registering our finalizer.
+		if !controllerutil.ContainsFinalizer(addon, AddonControllerFinalizerName) {
+			controllerutil.AddFinalizer(addon, AddonControllerFinalizerName)
+			addon, err = ctrl.fireflyClient.InstallV1alpha1().Addons(addon.Namespace).Update(ctx, addon, metav1.UpdateOptions{})
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		// The object is being deleted
+		if controllerutil.ContainsFinalizer(addon, AddonControllerFinalizerName) {
+			// our finalizer is present, so lets handle any external dependency
+			if err := ctrl.deleteUnableGCResources(ctx, addon); err != nil {
+				// if fail to delete the external dependency here, return with error
+				// so that it can be retried
+				return err
+			}
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(addon, AddonControllerFinalizerName)
+			_, err := ctrl.fireflyClient.InstallV1alpha1().Addons(addon.Namespace).Update(ctx, addon, metav1.UpdateOptions{})
+			if err != nil {
+				return err
+			}
+			// Stop reconciliation as the item is being deleted
+			return nil
+		}
+	}
+
+	if addon.Spec.Paused {
+		klog.V(2).InfoS("Addon is paused, skip syncing", "addon", klog.KObj(addon))
+		return ctrl.updatePausedCondition(ctx, addon)
+	}
+	if err := ctrl.updatePausedCondition(ctx, addon); err != nil {
+		return err
+	}
+
+	klog.InfoS("Syncing addon", "addon", klog.KObj(addon))
+
+	karmadaName := addon.Spec.Target.Karmada.Name
+	karmada, err := ctrl.karmadasLister.Karmadas(addon.Namespace).Get(karmadaName)
+	if errors.IsNotFound(err) {
+		// The addon will be requeued once the karmada is created.
+		return ctrl.updateStatus(ctx, addon, addon.Status.Resources, metav1.ConditionFalse, "KarmadaNotFound",
+			fmt.Sprintf("karmada %s not found", karmadaName))
+	}
+	if err != nil {
+		return err
+	}
+	if !karmada.DeletionTimestamp.IsZero() {
+		return ctrl.updateStatus(ctx, addon, addon.Status.Resources, metav1.ConditionFalse, "KarmadaTerminating",
+			fmt.Sprintf("karmada %s is terminating", karmadaName))
+	}
+
+	resources, err := ctrl.EnsureAddon(ctx, addon)
+	if err != nil {
+		ctrl.eventRecorder.Eventf(addon, corev1.EventTypeWarning, "InstallFailed", "Failed to install addon: %v", err)
+		if updateErr := ctrl.updateStatus(ctx, addon, resources, metav1.ConditionFalse, "InstallFailed", err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "Failed to update addon status", "addon", klog.KObj(addon))
+		}
+		return err
+	}
+	if !meta.IsStatusConditionTrue(addon.Status.Conditions, installv1alpha1.AddonConditionReady) {
+		ctrl.eventRecorder.Eventf(addon, corev1.EventTypeNormal, "Installed", "Installed %d resources of the addon", len(resources))
+	}
+	return ctrl.updateStatus(ctx, addon, resources, metav1.ConditionTrue, "Installed", "all resources of the addon are installed")
+}
+
+// EnsureAddon installs the resources of the addon into the target karmada and propagates
+// them to the target clusters. It returns the resources which are owned by the addon now.
+func (ctrl *AddonController) EnsureAddon(ctx context.Context, addon *installv1alpha1.Addon) ([]installv1alpha1.AddonResource, error) {
+	objs, err := ctrl.RenderManifests(ctx, addon)
+	if err != nil {
+		return addon.Status.Resources, err
+	}
+
+	tc, err := ctrl.newTargetClient(addon)
+	if err != nil {
+		return addon.Status.Resources, err
+	}
+
+	resources, err := tc.applyObjects(ctx, addon, objs)
+	if err != nil {
+		// keep track of the resources which might have been created, so that they can be pruned later.
+		return mergeResources(addon.Status.Resources, resources), err
+	}
+
+	if err := tc.pruneResources(ctx, addon.Status.Resources, resources); err != nil {
+		return mergeResources(addon.Status.Resources, resources), err
+	}
+
+	if err := tc.ensurePropagationPolicy(ctx, addon, resources); err != nil {
+		return resources, err
+	}
+	return resources, nil
+}
+
+func (ctrl *AddonController) deleteUnableGCResources(ctx context.Context, addon *installv1alpha1.Addon) error {
+	karmada, err := ctrl.karmadasLister.Karmadas(addon.Namespace).Get(addon.Spec.Target.Karmada.Name)
+	if errors.IsNotFound(err) || (err == nil && !karmada.DeletionTimestamp.IsZero()) {
+		// The whole control plane is gone or going away, nothing to clean up.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	tc, err := ctrl.newTargetClient(addon)
+	if err != nil {
+		return err
+	}
+	if err := tc.removePropagationPolicy(ctx, addon); err != nil {
+		return err
+	}
+	return tc.pruneResources(ctx, addon.Status.Resources, nil)
+}
+
+// updatePausedCondition sets the Paused condition of the addon if it's paused, otherwise removes it.
+// The status is updated only if it's changed.
+func (ctrl *AddonController) updatePausedCondition(ctx context.Context, addon *installv1alpha1.Addon) error {
+	oldStatus := addon.Status.DeepCopy()
+	if addon.Spec.Paused {
+		addon.Status.ObservedGeneration = addon.Generation
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:               installv1alpha1.AddonConditionPaused,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: addon.Generation,
+			Reason:             "Paused",
+			Message:            "the reconciliation of the addon is paused",
+		})
+	} else {
+		meta.RemoveStatusCondition(&addon.Status.Conditions, installv1alpha1.AddonConditionPaused)
+	}
+	phaseutil.SetAddonPhase(addon)
+	if equality.Semantic.DeepEqual(oldStatus, &addon.Status) {
+		return nil
+	}
+	updated, err := ctrl.fireflyClient.InstallV1alpha1().Addons(addon.Namespace).UpdateStatus(ctx, addon, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	addon.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
+func (ctrl *AddonController) updateStatus(ctx context.Context, addon *installv1alpha1.Addon, resources []installv1alpha1.AddonResource, status metav1.ConditionStatus, reason, message string) error {
+	addon.Status.ObservedGeneration = addon.Generation
+	addon.Status.Resources = resources
+	meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+		Type:               installv1alpha1.AddonConditionReady,
+		Status:             status,
+		ObservedGeneration: addon.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	phaseutil.SetAddonPhase(addon)
+	_, err := ctrl.fireflyClient.InstallV1alpha1().Addons(addon.Namespace).UpdateStatus(ctx, addon, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/helm"
+	chartutil "github.com/carlory/firefly/pkg/util/chart"
+)
+
+// RenderManifests returns the objects which should be installed by the addon.
+func (ctrl *AddonController) RenderManifests(ctx context.Context, addon *installv1alpha1.Addon) ([]*unstructured.Unstructured, error) {
+	if addon.Spec.Chart != nil && len(addon.Spec.Manifests) > 0 {
+		return nil, fmt.Errorf("manifests and chart are mutually exclusive")
+	}
+	if addon.Spec.Chart != nil {
+		values, err := chartutil.ParseValues(addon.Spec.Values)
+		if err != nil {
+			return nil, err
+		}
+		release := helm.ReleaseOptions{Name: addon.Name, Namespace: metav1.NamespaceDefault}
+		return chartutil.Render(ctx, ctrl.chartFetcher, addon.Spec.Chart, release, values)
+	}
+
+	objs := make([]*unstructured.Unstructured, 0, len(addon.Spec.Manifests))
+	for i, manifest := range addon.Spec.Manifests {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(manifest.Raw); err != nil {
+			return nil, fmt.Errorf("failed to decode manifests[%d]: %v", i, err)
+		}
+		if obj.GetName() == "" {
+			return nil, fmt.Errorf("manifests[%d]: name is required", i)
+		}
+		objs = append(objs, obj)
+	}
+	helm.SortByKind(objs)
+	return objs, nil
+}
+
+// mergeResources returns the union of the given resource lists.
+func mergeResources(a, b []installv1alpha1.AddonResource) []installv1alpha1.AddonResource {
+	seen := make(map[installv1alpha1.AddonResource]bool, len(a)+len(b))
+	var merged []installv1alpha1.AddonResource
+	for _, list := range [][]installv1alpha1.AddonResource{a, b} {
+		for _, r := range list {
+			if seen[r] {
+				continue
+			}
+			seen[r] = true
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
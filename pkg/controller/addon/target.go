@@ -0,0 +1,197 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	karmadaversioned "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/util/apply"
+	"github.com/carlory/firefly/pkg/util/audit"
+	utilresource "github.com/carlory/firefly/pkg/util/resource"
+)
+
+const (
+	// the user-agent name is used when talking to karmada-apiserver
+	userAgentName = "addon-controller"
+
+	// fieldManager is the field manager used to apply the resources of an addon.
+	fieldManager = "firefly-addon-controller"
+
+	// AddonLabel is the label set on all resources installed by an addon.
+	// Its value is the name of the addon.
+	AddonLabel = "addon.install.firefly.io/name"
+
+	// karmadaKubeconfigSecretName is the name of the secret which holds the kubeconfig of karmada-apiserver.
+	karmadaKubeconfigSecretName = "karmada-kubeconfig"
+)
+
+// targetClient talks to the karmada-apiserver targeted by an addon.
+type targetClient struct {
+	applier       *apply.Applier
+	karmadaClient karmadaversioned.Interface
+}
+
+func (ctrl *AddonController) newTargetClient(addon *installv1alpha1.Addon) (*targetClient, error) {
+	clientConfig, err := utilresource.GetClientConfigFromKubeConfigSecret(ctrl.client, addon.Namespace, karmadaKubeconfigSecretName, userAgentName)
+	if err != nil {
+		return nil, err
+	}
+	applier, err := apply.NewApplier(clientConfig, fieldManager)
+	if err != nil {
+		return nil, err
+	}
+	karmadaClient, err := karmadaversioned.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &targetClient{
+		applier:       applier,
+		karmadaClient: karmadaClient,
+	}, nil
+}
+
+// applyObjects applies the objects with server-side apply and returns the applied resources.
+func (c *targetClient) applyObjects(ctx context.Context, addon *installv1alpha1.Addon, objs []*unstructured.Unstructured) ([]installv1alpha1.AddonResource, error) {
+	var resources []installv1alpha1.AddonResource
+	for _, obj := range objs {
+		obj = obj.DeepCopy()
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[AddonLabel] = addon.Name
+		obj.SetLabels(labels)
+
+		if _, err := c.applier.Apply(ctx, obj); err != nil {
+			return resources, err
+		}
+		resources = append(resources, installv1alpha1.AddonResource{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+		})
+	}
+	return resources, nil
+}
+
+// pruneResources deletes the resources in old which are not in current.
+func (c *targetClient) pruneResources(ctx context.Context, old, current []installv1alpha1.AddonResource) error {
+	keep := make(map[installv1alpha1.AddonResource]bool, len(current))
+	for _, r := range current {
+		keep[r] = true
+	}
+
+	// delete in reverse install order, so that namespaces and crds are deleted last.
+	for i := len(old) - 1; i >= 0; i-- {
+		r := old[i]
+		if keep[r] {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(r.APIVersion)
+		obj.SetKind(r.Kind)
+		obj.SetNamespace(r.Namespace)
+		obj.SetName(r.Name)
+		if err := c.applier.Delete(ctx, obj); err != nil {
+			return err
+		}
+		klog.V(2).InfoS("Pruned addon resource", "kind", r.Kind, "object", klog.KObj(obj))
+	}
+	return nil
+}
+
+// propagationPolicyName returns the name of the ClusterPropagationPolicy of the addon.
+func propagationPolicyName(addon *installv1alpha1.Addon) string {
+	return fmt.Sprintf("firefly-addon-%s", addon.Name)
+}
+
+// ensurePropagationPolicy propagates the resources of the addon to its target clusters.
+func (c *targetClient) ensurePropagationPolicy(ctx context.Context, addon *installv1alpha1.Addon, resources []installv1alpha1.AddonResource) error {
+	if len(addon.Spec.Target.Clusters) == 0 || len(resources) == 0 {
+		return c.removePropagationPolicy(ctx, addon)
+	}
+
+	selectors := make([]policyv1alpha1.ResourceSelector, 0, len(resources))
+	for _, r := range resources {
+		if gv, err := schema.ParseGroupVersion(r.APIVersion); err == nil && gv.Group == "apiextensions.k8s.io" {
+			// crds are installed into the member clusters by the users or other addons.
+			continue
+		}
+		selectors = append(selectors, policyv1alpha1.ResourceSelector{
+			APIVersion: r.APIVersion,
+			Kind:       r.Kind,
+			Namespace:  r.Namespace,
+			Name:       r.Name,
+		})
+	}
+
+	policy := &policyv1alpha1.ClusterPropagationPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   propagationPolicyName(addon),
+			Labels: map[string]string{AddonLabel: addon.Name},
+		},
+		Spec: policyv1alpha1.PropagationSpec{
+			ResourceSelectors: selectors,
+			Placement: policyv1alpha1.Placement{
+				ClusterAffinity: &policyv1alpha1.ClusterAffinity{
+					ClusterNames: addon.Spec.Target.Clusters,
+				},
+			},
+		},
+	}
+
+	policies := c.karmadaClient.PolicyV1alpha1().ClusterPropagationPolicies()
+	existing, err := policies.Get(ctx, policy.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = policies.Create(ctx, policy, metav1.CreateOptions{})
+		audit.RecordResult(ctx, audit.Create, policy, err)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	old := existing.DeepCopy()
+	existing.Labels = policy.Labels
+	existing.Spec = policy.Spec
+	updated, err := policies.Update(ctx, existing, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	audit.RecordUpdate(ctx, old, updated)
+	return nil
+}
+
+// removePropagationPolicy removes the ClusterPropagationPolicy of the addon.
+func (c *targetClient) removePropagationPolicy(ctx context.Context, addon *installv1alpha1.Addon) error {
+	err := c.karmadaClient.PolicyV1alpha1().ClusterPropagationPolicies().Delete(ctx, propagationPolicyName(addon), metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "ClusterPropagationPolicy", Name: propagationPolicyName(addon)}, err)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ package config
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
 	cmconfig "k8s.io/controller-manager/config"
 )
 
@@ -27,4 +28,240 @@ type FireflyControllerManagerConfiguration struct {
 
 	// Generic holds configuration for a generic controller-manager
 	Generic cmconfig.GenericControllerManagerConfiguration
+
+	// Tracing holds the OTLP tracing configuration of the controllers. Tracing is disabled if it is nil.
+	Tracing *tracingapi.TracingConfiguration
+
+	// DryRun makes the controllers send their mutations as server-side dry-run requests, so that
+	// the objects they would create, update or delete are only validated and audited.
+	DryRun bool
+
+	// LeaderElectionLabels are set on the leader election leases, so that the leases of several
+	// firefly deployments sharing the lock namespace can be told apart.
+	LeaderElectionLabels map[string]string
+	// LeaderElectionIdentitySuffix is appended to the holder identities of the leader election
+	// leases to name the deployment the holders belong to.
+	LeaderElectionIdentitySuffix string
+
+	// StartupRetry describes how the manager retries to start while the apiserver is unreachable.
+	StartupRetry StartupRetryConfiguration
+
+	// KarmadaController holds configuration for KarmadaController related features.
+	KarmadaController KarmadaControllerConfiguration
+	// ClusterpediaController holds configuration for ClusterpediaController related features.
+	ClusterpediaController ClusterpediaControllerConfiguration
+	// AddonController holds configuration for AddonController related features.
+	AddonController AddonControllerConfiguration
+	// ClusterRegistrationController holds configuration for ClusterRegistrationController related features.
+	ClusterRegistrationController ClusterRegistrationControllerConfiguration
+	// ClusterAgentController holds configuration for ClusterAgentController related features.
+	ClusterAgentController ClusterAgentControllerConfiguration
+	// KarmadaHealthController holds configuration for KarmadaHealthController related features.
+	KarmadaHealthController KarmadaHealthControllerConfiguration
+	// EtcdMaintenanceController holds configuration for EtcdMaintenanceController related features.
+	EtcdMaintenanceController EtcdMaintenanceControllerConfiguration
+	// ObservabilityController holds configuration for ObservabilityController related features.
+	ObservabilityController ObservabilityControllerConfiguration
+	// OrphanController holds configuration for OrphanController related features.
+	OrphanController OrphanControllerConfiguration
+	// DisasterRecoveryController holds configuration for DisasterRecoveryController related features.
+	DisasterRecoveryController DisasterRecoveryControllerConfiguration
+	// SubmarinerController holds configuration for SubmarinerController related features.
+	SubmarinerController SubmarinerControllerConfiguration
+	// SecretSyncController holds configuration for SecretSyncController related features.
+	SecretSyncController SecretSyncControllerConfiguration
+
+	// Audit holds configuration for the audit of the mutations performed by the controllers.
+	Audit AuditConfiguration
+	// Notification holds configuration for the alerts pushed to external endpoints.
+	Notification NotificationConfiguration
+	// SecretsEncryption holds configuration for the encryption of the secrets generated by the controllers.
+	SecretsEncryption SecretsEncryptionConfiguration
+	// Charts holds configuration for the charts loaded by the controllers.
+	Charts ChartsConfiguration
+}
+
+// StartupRetryConfiguration contains elements describing how the manager retries to build the context of
+// the controllers while the apiserver is unreachable, e.g. when they're started at the same time. The
+// configuration errors aren't retried.
+type StartupRetryConfiguration struct {
+	// Timeout is how long the manager retries before it exits. 0 means it isn't retried.
+	Timeout metav1.Duration
+	// InitialBackoff is the interval before the first retry, which is doubled after each retry up to 30s.
+	InitialBackoff metav1.Duration
+}
+
+// KarmadaControllerConfiguration contains elements describing KarmadaController.
+type KarmadaControllerConfiguration struct {
+	// ConcurrentKarmadaSyncs is the number of karmada objects that are allowed to sync
+	// concurrently. Larger number = more responsive karmadas, but more CPU (and network) load.
+	ConcurrentKarmadaSyncs int32
+	// Reconcile holds the resync period and the error backoff of the controller.
+	Reconcile ReconcileConfiguration
+}
+
+// ClusterpediaControllerConfiguration contains elements describing ClusterpediaController.
+type ClusterpediaControllerConfiguration struct {
+	// ConcurrentClusterpediaSyncs is the number of clusterpedia objects that are allowed to sync
+	// concurrently. Larger number = more responsive clusterpedias, but more CPU (and network) load.
+	ConcurrentClusterpediaSyncs int32
+	// Reconcile holds the resync period and the error backoff of the controller.
+	Reconcile ReconcileConfiguration
+}
+
+// AddonControllerConfiguration contains elements describing AddonController.
+type AddonControllerConfiguration struct {
+	// ConcurrentAddonSyncs is the number of addon objects that are allowed to sync
+	// concurrently. Larger number = more responsive addons, but more CPU (and network) load.
+	ConcurrentAddonSyncs int32
+	// Reconcile holds the resync period and the error backoff of the controller.
+	Reconcile ReconcileConfiguration
+}
+
+// ClusterRegistrationControllerConfiguration contains elements describing ClusterRegistrationController.
+type ClusterRegistrationControllerConfiguration struct {
+	// ConcurrentClusterRegistrationSyncs is the number of cluster registration objects that are allowed to sync
+	// concurrently. Larger number = more responsive cluster registrations, but more CPU (and network) load.
+	ConcurrentClusterRegistrationSyncs int32
+	// Reconcile holds the resync period and the error backoff of the controller.
+	Reconcile ReconcileConfiguration
+}
+
+// ClusterAgentControllerConfiguration contains elements describing ClusterAgentController.
+type ClusterAgentControllerConfiguration struct {
+	// ConcurrentClusterAgentSyncs is the number of cluster registration objects in pull mode whose agents are
+	// allowed to sync concurrently. Larger number = more responsive cluster agents, but more CPU (and network) load.
+	ConcurrentClusterAgentSyncs int32
+}
+
+// KarmadaHealthControllerConfiguration contains elements describing KarmadaHealthController.
+type KarmadaHealthControllerConfiguration struct {
+	// ConcurrentKarmadaHealthSyncs is the number of karmada objects whose components are allowed to be probed
+	// concurrently. Larger number = more responsive health conditions, but more CPU (and network) load.
+	ConcurrentKarmadaHealthSyncs int32
+}
+
+// EtcdMaintenanceControllerConfiguration contains elements describing EtcdMaintenanceController.
+type EtcdMaintenanceControllerConfiguration struct {
+	// ConcurrentEtcdMaintenanceSyncs is the number of karmada objects whose etcd is allowed to be maintained
+	// concurrently. Larger number = more responsive maintenance, but more CPU (and network) load.
+	ConcurrentEtcdMaintenanceSyncs int32
+	// SpaceThresholdPercent is the percentage of the space quota of etcd which, once the database exceeds
+	// it, makes the controller compact and defragment the database.
+	SpaceThresholdPercent int32
+	// FragmentationThresholdPercent is the percentage of the database of etcd not in use which, once it's
+	// exceeded, makes the controller defragment the database.
+	FragmentationThresholdPercent int32
+	// MinRemediationInterval is the minimum interval between two remediations of the same etcd, since a
+	// defragmentation blocks the writes to etcd while it's running.
+	MinRemediationInterval metav1.Duration
+	// CompactionRetentionRevisions is the number of the latest revisions of etcd kept by a compaction, so
+	// that the watchers of the karmada-apiserver which are slightly behind don't have to relist.
+	CompactionRetentionRevisions int64
+}
+
+// ObservabilityControllerConfiguration contains elements describing ObservabilityController.
+type ObservabilityControllerConfiguration struct {
+	// ConcurrentObservabilitySyncs is the number of karmada and clusterpedia objects whose dashboards
+	// and alerts are allowed to sync concurrently. Larger number = more responsive dashboards and alerts,
+	// but more CPU (and network) load.
+	ConcurrentObservabilitySyncs int32
+}
+
+// OrphanControllerConfiguration contains elements describing OrphanController.
+type OrphanControllerConfiguration struct {
+	// ConcurrentOrphanSyncs is the number of firefly-managed resources that are allowed to be checked
+	// concurrently. Larger number = more responsive cleanup, but more CPU (and network) load.
+	ConcurrentOrphanSyncs int32
+}
+
+// ReconcileConfiguration contains elements describing how often a controller reconciles its objects.
+type ReconcileConfiguration struct {
+	// ResyncPeriod is the period at which all objects of the controller are reconciled even if they're
+	// not changed. Zero means the resync period of the shared informers, derived from MinResyncPeriod.
+	ResyncPeriod metav1.Duration
+	// InitialBackoff is the delay before an object is retried after its first failed reconcile, which is
+	// doubled on each consecutive failure.
+	InitialBackoff metav1.Duration
+	// MaxBackoff is the maximum delay before an object is retried after a failed reconcile.
+	MaxBackoff metav1.Duration
+}
+
+// AuditConfiguration contains elements describing the audit of the mutations performed by the controllers.
+// The audit events are always logged, and kept by a configmap in addition if it's configured.
+type AuditConfiguration struct {
+	// ConfigMap is the namespace/name of the configmap which keeps the latest audit events.
+	ConfigMap string
+	// MaxEvents is the number of the latest audit events kept by the configmap.
+	MaxEvents int32
+}
+
+// NotificationConfiguration contains elements describing the alerts pushed to external endpoints when an
+// installation degrades or a controller keeps failing to reconcile an object. The alerts are always logged,
+// and posted to a webhook in addition if it's configured.
+type NotificationConfiguration struct {
+	// WebhookURLFile is the path of the file holding the url of the webhook the alerts are posted to, e.g.
+	// a key of a mounted Secret. The url itself is a secret, so it's kept out of the configuration.
+	WebhookURLFile string
+	// Format is the format of the body posted to the webhook, json or slack.
+	Format string
+	// ReconcileFailureThreshold is the number of failed reconciles of an object in a row which is alerted.
+	// 0 disables the alerts of the failed reconciles.
+	ReconcileFailureThreshold int32
+	// MinInterval is the min interval between the alerts of a reason for an object.
+	MinInterval metav1.Duration
+}
+
+// SecretsEncryptionConfiguration contains elements describing the envelope encryption of the sensitive
+// keys of the secrets generated by the controllers with an external KMS. Only the keys which the components
+// don't read are encrypted: the private keys of the etcd CA and the front proxy CA, which only the controllers
+// sign certificates with, and the admin and read-only kubeconfigs published for the karmadas. The private
+// key of the karmada CA, the certificates and the private keys of the components, e.g. the etcd client key,
+// the karmada-kubeconfig Secret and the database credentials of the clusterpedias are kept in plain text,
+// since the components, which can't reach the KMS, read them from the mounted secrets and their environment.
+// The secrets aren't encrypted if the endpoint isn't set.
+type SecretsEncryptionConfiguration struct {
+	// KMSProvider is the name of the KMS provider, e.g. kmsv1 for a Kubernetes KMS v1 plugin.
+	KMSProvider string
+	// KMSEndpoint is the endpoint of the KMS, e.g. the unix socket of the KMS plugin.
+	KMSEndpoint string
+	// KMSTimeout is the timeout of the calls to the KMS.
+	KMSTimeout metav1.Duration
+}
+
+// ChartsConfiguration contains elements describing where the charts referenced by the objects, e.g.
+// spec.chart of a karmada, may be loaded from.
+type ChartsConfiguration struct {
+	// InsecureHosts are the hosts which the charts may be loaded from over plain http, e.g. a chart
+	// repository in the host cluster. The charts are only loaded over https from the other hosts.
+	InsecureHosts []string
+}
+
+// DisasterRecoveryControllerConfiguration contains elements describing DisasterRecoveryController.
+type DisasterRecoveryControllerConfiguration struct {
+	// ConcurrentDisasterRecoverySyncs is the number of karmada objects whose backups and restores are
+	// allowed to sync concurrently. Larger number = more responsive backups and restores, but more CPU
+	// (and network) load.
+	ConcurrentDisasterRecoverySyncs int32
+	// VeleroNamespace is the namespace Velero is installed in, where the Velero Schedules and Restores
+	// of the karmadas are created.
+	VeleroNamespace string
+}
+
+// SubmarinerControllerConfiguration contains elements describing SubmarinerController.
+type SubmarinerControllerConfiguration struct {
+	// ConcurrentSubmarinerSyncs is the number of submariner objects that are allowed to sync
+	// concurrently. Larger number = more responsive submariners, but more CPU (and network) load.
+	ConcurrentSubmarinerSyncs int32
+	// Reconcile holds the resync period and the error backoff of the controller.
+	Reconcile ReconcileConfiguration
+}
+
+// SecretSyncControllerConfiguration contains elements describing SecretSyncController.
+type SecretSyncControllerConfiguration struct {
+	// ConcurrentSecretSyncSyncs is the number of secret sync objects that are allowed to sync
+	// concurrently. Larger number = more responsive secret syncs, but more CPU (and network) load.
+	ConcurrentSecretSyncSyncs int32
+	// Reconcile holds the resync period and the error backoff of the controller.
+	Reconcile ReconcileConfiguration
 }
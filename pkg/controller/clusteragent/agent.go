@@ -0,0 +1,260 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusteragent
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	karmadacontroller "github.com/carlory/firefly/pkg/controller/karmada"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/kms"
+	maputil "github.com/carlory/firefly/pkg/util/map"
+)
+
+const (
+	// agentServiceAccountName is the name of the service account of karmada-agent in the member cluster.
+	agentServiceAccountName = "karmada-agent-sa"
+
+	// agentKubeconfigSecretName is the name of the secret which holds the kubeconfig of karmada-apiserver
+	// in the member cluster.
+	agentKubeconfigSecretName = "karmada-kubeconfig"
+)
+
+// ensureAgent deploys karmada-agent into the member cluster, which registers the member cluster into
+// the karmada and pulls the resources from it. The agent accesses the karmada-apiserver with the admin
+// kubeconfig of the karmada, whose server is reachable from outside of the host cluster.
+func (ctrl *ClusterAgentController) ensureAgent(ctx context.Context, cr *installv1alpha1.ClusterRegistration, karmada *installv1alpha1.Karmada,
+	memberClient kubernetes.Interface, apiEndpoint string) error {
+	kubeconfigSecret, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, karmadacontroller.AdminKubeconfigSecretName(karmada), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	kubeconfig, err := kms.SecretData(ctx, kubeconfigSecret, "kubeconfig")
+	if err != nil {
+		return err
+	}
+	if len(kubeconfig) == 0 {
+		return fmt.Errorf("the secret %s doesn't contain the kubeconfig field in the namespace %s", kubeconfigSecret.Name, kubeconfigSecret.Namespace)
+	}
+
+	labels := map[string]string{constants.ClusterRegistrationLabel: cr.Name}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: constants.KarmadaSystemNamespace, Labels: labels}}
+	result, err := clientutil.CreateOrUpdateNamespace(ctx, memberClient, ns)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, cr, ns, result)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      agentKubeconfigSecretName,
+			Namespace: constants.KarmadaSystemNamespace,
+			Labels:    labels,
+		},
+		Data: map[string][]byte{"kubeconfig": kubeconfig},
+	}
+	result, err = clientutil.CreateOrUpdateSecret(ctx, memberClient, secret)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, cr, secret, result)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureAgentRBAC(ctx, memberClient, labels); err != nil {
+		return err
+	}
+
+	deployment := agentDeployment(cr, karmada, apiEndpoint)
+	result, err = clientutil.CreateOrUpdateDeployment(ctx, memberClient, deployment)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, cr, deployment, result)
+	return err
+}
+
+// ensureAgentRBAC creates the service account of karmada-agent, and grants all permissions of the
+// member cluster to it like `karmadactl register` does.
+func ensureAgentRBAC(ctx context.Context, memberClient kubernetes.Interface, labels map[string]string) error {
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: agentServiceAccountName, Namespace: constants.KarmadaSystemNamespace, Labels: labels},
+	}
+	if _, err := clientutil.CreateOrUpdateServiceAccount(ctx, memberClient, sa); err != nil {
+		return err
+	}
+
+	clusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.KarmadaComponentAgent, Labels: labels},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+			{NonResourceURLs: []string{"*"}, Verbs: []string{"get"}},
+		},
+	}
+	if _, err := clientutil.CreateOrUpdateClusterRole(ctx, memberClient, clusterRole); err != nil {
+		return err
+	}
+
+	crb := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.KarmadaComponentAgent, Labels: labels},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      agentServiceAccountName,
+				Namespace: constants.KarmadaSystemNamespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind:     "ClusterRole",
+			Name:     constants.KarmadaComponentAgent,
+			APIGroup: "rbac.authorization.k8s.io",
+		},
+	}
+	_, err := clientutil.CreateOrUpdateClusterRoleBinding(ctx, memberClient, crb)
+	return err
+}
+
+// agentVersion returns the version of karmada-agent. It follows the installed version of the karmada
+// rather than spec.karmadaVersion, so that the agents are upgraded after the control plane.
+func agentVersion(cr *installv1alpha1.ClusterRegistration, karmada *installv1alpha1.Karmada) string {
+	if cr.Spec.Agent.ImageTag != "" {
+		return cr.Spec.Agent.ImageTag
+	}
+	return karmada.Status.KarmadaVersion
+}
+
+// agentDeployment returns the karmada-agent deployment of the member cluster.
+func agentDeployment(cr *installv1alpha1.ClusterRegistration, karmada *installv1alpha1.Karmada, apiEndpoint string) *appsv1.Deployment {
+	componentName := constants.KarmadaComponentAgent
+	agent := cr.Spec.Agent
+
+	repository := karmada.Spec.ImageRepository
+	if agent.ImageRepository != "" {
+		repository = agent.ImageRepository
+	}
+
+	imageName := constants.KarmadaComponentAgent
+	if agent.ImageName != "" {
+		imageName = agent.ImageName
+	}
+
+	defaultArgs := map[string]string{
+		"karmada-kubeconfig":              "/etc/kubeconfig",
+		"cluster-name":                    cr.Status.ClusterName,
+		"cluster-api-endpoint":            apiEndpoint,
+		"cluster-status-update-frequency": "10s",
+		"bind-address":                    "0.0.0.0",
+		"secure-port":                     "10357",
+		"v":                               "4",
+	}
+	computedArgs := maputil.MergeStringMaps(defaultArgs, agent.ExtraArgs)
+	args := maputil.ConvertToCommandOrArgs(computedArgs)
+
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      componentName,
+			Namespace: constants.KarmadaSystemNamespace,
+			Labels:    map[string]string{constants.ClusterRegistrationLabel: cr.Name},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": componentName},
+			},
+			Replicas: agent.Replicas,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": componentName},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: agentServiceAccountName,
+					Containers: []corev1.Container{
+						{
+							Name:            componentName,
+							Image:           util.ComponentImageName(repository, imageName, agentVersion(cr, karmada)),
+							ImagePullPolicy: "IfNotPresent",
+							Command:         []string{"/bin/karmada-agent"},
+							Args:            args,
+							Resources:       agent.Resources,
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "kubeconfig",
+									MountPath: "/etc/kubeconfig",
+									SubPath:   "kubeconfig",
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "kubeconfig",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: agentKubeconfigSecretName,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// RemoveAgent deletes karmada-agent and its credentials from the member cluster. The karmada-system
+// namespace is kept, since it may be shared with other karmada components, e.g. the scheduler estimator.
+func RemoveAgent(ctx context.Context, memberClient kubernetes.Interface) error {
+	namespace := constants.KarmadaSystemNamespace
+	err := memberClient.AppsV1().Deployments(namespace).Delete(ctx, constants.KarmadaComponentAgent, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Deployment", Namespace: namespace, Name: constants.KarmadaComponentAgent}, err)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	err = memberClient.RbacV1().ClusterRoleBindings().Delete(ctx, constants.KarmadaComponentAgent, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "ClusterRoleBinding", Name: constants.KarmadaComponentAgent}, err)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	err = memberClient.RbacV1().ClusterRoles().Delete(ctx, constants.KarmadaComponentAgent, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "ClusterRole", Name: constants.KarmadaComponentAgent}, err)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	err = memberClient.CoreV1().ServiceAccounts(namespace).Delete(ctx, agentServiceAccountName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "ServiceAccount", Namespace: namespace, Name: agentServiceAccountName}, err)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	err = memberClient.CoreV1().Secrets(namespace).Delete(ctx, agentKubeconfigSecretName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Secret", Namespace: namespace, Name: agentKubeconfigSecretName}, err)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
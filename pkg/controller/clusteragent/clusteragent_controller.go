@@ -0,0 +1,430 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusteragent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/prometheus/ratelimiter"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	fireflyclient "github.com/carlory/firefly/pkg/generated/clientset/versioned"
+	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
+	installlisters "github.com/carlory/firefly/pkg/generated/listers/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/coalesce"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	"github.com/carlory/firefly/pkg/util/notification"
+	phaseutil "github.com/carlory/firefly/pkg/util/phase"
+	utilresource "github.com/carlory/firefly/pkg/util/resource"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
+)
+
+const (
+	// maxRetries is the number of times a cluster registration will be retried before it is dropped out of the queue.
+	// With the current rate-limiter in use (5ms*2^(maxRetries-1)) the following numbers represent the
+	// sequence of delays between successive queuings of a cluster registration.
+	//
+	// 5ms, 10ms, 20ms, 40ms, 80ms, 160ms, 320ms, 640ms, 1.3s, 2.6s, 5.1s, 10.2s, 20.4s, 41s, 82s
+	maxRetries = 15
+
+	// the user-agent name is used when talking to the member clusters
+	userAgentName = "clusteragent-controller"
+
+	// agentStatusCheckPeriod is how often the status of karmada-agent is checked. The member clusters
+	// can't be watched, so the cluster registrations in pull mode are requeued periodically.
+	agentStatusCheckPeriod = 30 * time.Second
+)
+
+// NewClusterAgentController returns a new *Controller.
+func NewClusterAgentController(
+	client clientset.Interface,
+	fireflyClient fireflyclient.Interface,
+	clusterRegistrationInformer installinformers.ClusterRegistrationInformer,
+	karmadaInformer installinformers.KarmadaInformer) (*ClusterAgentController, error) {
+	broadcaster := record.NewBroadcaster()
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "clusteragent-controller"})
+
+	if client != nil && client.CoreV1().RESTClient().GetRateLimiter() != nil {
+		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("clusteragent_controller", client.CoreV1().RESTClient().GetRateLimiter())
+	}
+
+	ctrl := &ClusterAgentController{
+		client:                     client,
+		fireflyClient:              fireflyClient,
+		clusterRegistrationsLister: clusterRegistrationInformer.Lister(),
+		clusterRegistrationsSynced: clusterRegistrationInformer.Informer().HasSynced,
+		karmadasLister:             karmadaInformer.Lister(),
+		karmadasSynced:             karmadaInformer.Informer().HasSynced,
+		queue:                      coalesce.NewQueue(workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "clusteragent"), coalesce.DefaultWindow, coalesce.DefaultInterval),
+		workerLoopPeriod:           time.Second,
+		eventBroadcaster:           broadcaster,
+		eventRecorder:              recorder,
+	}
+
+	// The agents are removed by the cluster registration controller when the member clusters
+	// are unjoined, so deletions are not handled here.
+	clusterRegistrationInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.addClusterRegistration,
+		UpdateFunc: ctrl.updateClusterRegistration,
+	})
+
+	// The agents are upgraded along with their karmada.
+	karmadaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.addKarmada,
+		UpdateFunc: ctrl.updateKarmada,
+	})
+
+	return ctrl, nil
+}
+
+// ClusterAgentController installs, upgrades and monitors karmada-agent in the member clusters
+// which are joined in pull mode.
+type ClusterAgentController struct {
+	client           clientset.Interface
+	fireflyClient    fireflyclient.Interface
+	eventBroadcaster record.EventBroadcaster
+	eventRecorder    record.EventRecorder
+
+	clusterRegistrationsLister installlisters.ClusterRegistrationLister
+	clusterRegistrationsSynced cache.InformerSynced
+
+	karmadasLister installlisters.KarmadaLister
+	karmadasSynced cache.InformerSynced
+
+	// Cluster registrations that need to be updated. A channel is inappropriate here,
+	// because it allows a cluster registration to be inserted multiple times and be
+	// processed more than necessary.
+	queue *coalesce.Queue
+
+	// workerLoopPeriod is the time between worker runs. The workers process the queue of cluster registration changes.
+	workerLoopPeriod time.Duration
+}
+
+// Run will not return until stopCh is closed. workers determines how many
+// cluster registrations will be handled in parallel.
+func (ctrl *ClusterAgentController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+
+	// Start events processing pipeline.
+	ctrl.eventBroadcaster.StartStructuredLogging(0)
+	ctrl.eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: ctrl.client.CoreV1().Events("")})
+	defer ctrl.eventBroadcaster.Shutdown()
+
+	defer ctrl.queue.ShutDown()
+
+	klog.Infof("Starting cluster agent controller")
+	defer klog.Infof("Shutting down cluster agent controller")
+
+	if !cache.WaitForNamedCacheSync("clusteragent", ctx.Done(), ctrl.clusterRegistrationsSynced, ctrl.karmadasSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, ctrl.worker, ctrl.workerLoopPeriod)
+	}
+	<-ctx.Done()
+}
+
+// worker runs a worker thread that just dequeues items, processes them, and
+// marks them done. You may run as many of these in parallel as you wish; the
+// workqueue guarantees that they will not end up processing the same cluster
+// registration at the same time.
+func (ctrl *ClusterAgentController) worker(ctx context.Context) {
+	for ctrl.processNextWorkItem(ctx) {
+	}
+}
+
+func (ctrl *ClusterAgentController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := ctrl.queue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.queue.Done(key)
+
+	ctx, span := tracingutil.StartReconcile(ctx, "clusteragent", key.(string))
+	err := ctrl.syncClusterAgent(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.handleErr(err, key)
+
+	return true
+}
+
+func (ctrl *ClusterAgentController) addClusterRegistration(obj interface{}) {
+	cr := obj.(*installv1alpha1.ClusterRegistration)
+	klog.V(4).InfoS("Adding cluster registration", "clusterregistration", klog.KObj(cr))
+	ctrl.enqueue(cr)
+}
+
+func (ctrl *ClusterAgentController) updateClusterRegistration(old, cur interface{}) {
+	oldCR := old.(*installv1alpha1.ClusterRegistration)
+	curCR := cur.(*installv1alpha1.ClusterRegistration)
+	klog.V(4).InfoS("Updating cluster registration", "clusterregistration", klog.KObj(oldCR))
+	ctrl.enqueue(curCR)
+}
+
+func (ctrl *ClusterAgentController) addKarmada(obj interface{}) {
+	karmada := obj.(*installv1alpha1.Karmada)
+	ctrl.enqueueClusterRegistrationsForKarmada(karmada)
+}
+
+func (ctrl *ClusterAgentController) updateKarmada(old, cur interface{}) {
+	oldKarmada := old.(*installv1alpha1.Karmada)
+	curKarmada := cur.(*installv1alpha1.Karmada)
+	if oldKarmada.Status.KarmadaVersion == curKarmada.Status.KarmadaVersion && oldKarmada.Spec.ImageRepository == curKarmada.Spec.ImageRepository {
+		return
+	}
+	ctrl.enqueueClusterRegistrationsForKarmada(curKarmada)
+}
+
+// enqueueClusterRegistrationsForKarmada enqueues all cluster registrations which join the given karmada.
+func (ctrl *ClusterAgentController) enqueueClusterRegistrationsForKarmada(karmada *installv1alpha1.Karmada) {
+	crs, err := ctrl.clusterRegistrationsLister.ClusterRegistrations(karmada.Namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, cr := range crs {
+		if cr.Spec.Karmada.Name == karmada.Name {
+			ctrl.enqueueCoalesced(cr)
+		}
+	}
+}
+
+func (ctrl *ClusterAgentController) enqueue(cr *installv1alpha1.ClusterRegistration) {
+	key, err := cache.MetaNamespaceKeyFunc(cr)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.Add(key)
+}
+
+// enqueueCoalesced enqueues the cluster registration after the bursts of events of its secondary objects have settled.
+func (ctrl *ClusterAgentController) enqueueCoalesced(cr *installv1alpha1.ClusterRegistration) {
+	key, err := cache.MetaNamespaceKeyFunc(cr)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.AddCoalesced(key)
+}
+
+func (ctrl *ClusterAgentController) handleErr(err error, key interface{}) {
+	if err == nil || errors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+		ctrl.queue.Forget(key)
+		return
+	}
+
+	ns, name, keyErr := cache.SplitMetaNamespaceKey(key.(string))
+	if keyErr != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+	}
+
+	notification.ReconcileFailed("clusteragent", audit.ObjectReference{Kind: "ClusterRegistration", Namespace: ns, Name: name}, ctrl.queue.NumRequeues(key)+1, err)
+	if ctrl.queue.NumRequeues(key) < maxRetries {
+		klog.V(2).InfoS("Error syncing cluster agent, retrying", "clusterregistration", klog.KRef(ns, name), "err", err)
+		ctrl.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	klog.V(2).InfoS("Dropping cluster agent out of the queue", "clusterregistration", klog.KRef(ns, name), "err", err)
+	ctrl.queue.Forget(key)
+}
+
+func (ctrl *ClusterAgentController) syncClusterAgent(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+		return err
+	}
+
+	startTime := time.Now()
+	klog.V(4).InfoS("Started syncing cluster agent", "clusterregistration", klog.KRef(namespace, name), "startTime", startTime)
+	defer func() {
+		klog.V(4).InfoS("Finished syncing cluster agent", "clusterregistration", klog.KRef(namespace, name), "duration", time.Since(startTime))
+	}()
+
+	cr, err := ctrl.clusterRegistrationsLister.ClusterRegistrations(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		klog.V(2).InfoS("Cluster registration has been deleted", "clusterregistration", klog.KRef(namespace, name))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !cr.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	// Deep-copy otherwise we are mutating our cache.
+	cr = cr.DeepCopy()
+	ctx = audit.WithTrigger(ctx, cr)
+	ctx = dryrun.ForObject(ctx, cr)
+
+	if cr.Spec.SyncMode != installv1alpha1.ClusterSyncModePull {
+		return ctrl.clearAgentStatus(ctx, cr)
+	}
+
+	// The agent is deployed after the member cluster is joined in pull mode, so that it doesn't race
+	// with the cluster registration controller which removes the agent while unjoining.
+	joined := meta.FindStatusCondition(cr.Status.Conditions, installv1alpha1.ClusterRegistrationConditionJoined)
+	if joined == nil || joined.Status != metav1.ConditionTrue || joined.ObservedGeneration != cr.Generation || cr.Status.SyncMode != installv1alpha1.ClusterSyncModePull {
+		klog.V(4).InfoS("Cluster is not joined in pull mode yet, skip syncing its agent", "clusterregistration", klog.KObj(cr))
+		return nil
+	}
+
+	karmada, err := ctrl.karmadasLister.Karmadas(cr.Namespace).Get(cr.Spec.Karmada.Name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !karmada.DeletionTimestamp.IsZero() || karmada.Status.KarmadaVersion == "" {
+		return nil
+	}
+
+	klog.InfoS("Syncing cluster agent", "clusterregistration", klog.KObj(cr))
+
+	memberConfig, err := utilresource.GetClientConfigFromKubeConfigSecret(ctrl.client, cr.Namespace, cr.Spec.KubeconfigSecret.Name, userAgentName)
+	if err != nil {
+		return err
+	}
+	memberClient, err := clientset.NewForConfig(memberConfig)
+	if err != nil {
+		return err
+	}
+
+	desiredVersion := agentVersion(cr, karmada)
+	if cr.Status.Agent != nil && cr.Status.Agent.Version != "" && cr.Status.Agent.Version != desiredVersion {
+		ctrl.eventRecorder.Eventf(cr, corev1.EventTypeNormal, "UpgradingAgent", "Upgrading karmada-agent from %s to %s", cr.Status.Agent.Version, desiredVersion)
+	}
+
+	apiEndpoint := cr.Spec.APIEndpoint
+	if apiEndpoint == "" {
+		apiEndpoint = memberConfig.Host
+	}
+	if err := ctrl.ensureAgent(ctx, cr, karmada, memberClient, apiEndpoint); err != nil {
+		ctrl.eventRecorder.Eventf(cr, corev1.EventTypeWarning, "AgentInstallFailed", "Failed to install karmada-agent: %v", err)
+		if updateErr := ctrl.updateAgentStatus(ctx, cr, cr.Status.Agent, metav1.ConditionFalse, "InstallFailed", err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "Failed to update cluster registration status", "clusterregistration", klog.KObj(cr))
+		}
+		return err
+	}
+
+	if err := ctrl.reportAgentStatus(ctx, cr, memberClient); err != nil {
+		return err
+	}
+	// The member cluster can't be watched, so check the agent again later.
+	ctrl.queue.AddAfter(key, agentStatusCheckPeriod)
+	return nil
+}
+
+// reportAgentStatus reports the status of the karmada-agent deployment into the cluster registration.
+func (ctrl *ClusterAgentController) reportAgentStatus(ctx context.Context, cr *installv1alpha1.ClusterRegistration, memberClient clientset.Interface) error {
+	deployment, err := memberClient.AppsV1().Deployments(constants.KarmadaSystemNamespace).Get(ctx, constants.KarmadaComponentAgent, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return ctrl.updateAgentStatus(ctx, cr, nil, metav1.ConditionFalse, "AgentNotFound", "karmada-agent is not found in the member cluster")
+	}
+	if err != nil {
+		return ctrl.updateAgentStatus(ctx, cr, cr.Status.Agent, metav1.ConditionUnknown, "MemberClusterUnreachable", err.Error())
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	agentStatus := &installv1alpha1.ClusterAgentStatus{
+		Replicas:          replicas,
+		UpdatedReplicas:   deployment.Status.UpdatedReplicas,
+		AvailableReplicas: deployment.Status.AvailableReplicas,
+	}
+	for _, c := range deployment.Spec.Template.Spec.Containers {
+		if c.Name == constants.KarmadaComponentAgent {
+			agentStatus.Version = c.Image[strings.LastIndex(c.Image, ":")+1:]
+		}
+	}
+
+	rolledOut := deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas && deployment.Status.Replicas == replicas
+	switch {
+	case rolledOut && deployment.Status.AvailableReplicas == replicas:
+		return ctrl.updateAgentStatus(ctx, cr, agentStatus, metav1.ConditionTrue, "AgentReady",
+			fmt.Sprintf("karmada-agent %s is available", agentStatus.Version))
+	case !rolledOut:
+		return ctrl.updateAgentStatus(ctx, cr, agentStatus, metav1.ConditionFalse, "AgentProgressing",
+			fmt.Sprintf("%d of %d replicas of karmada-agent are updated to %s", deployment.Status.UpdatedReplicas, replicas, agentStatus.Version))
+	default:
+		return ctrl.updateAgentStatus(ctx, cr, agentStatus, metav1.ConditionFalse, "AgentUnavailable",
+			fmt.Sprintf("%d of %d replicas of karmada-agent are available", deployment.Status.AvailableReplicas, replicas))
+	}
+}
+
+// updateAgentStatus sets the agent status and the AgentReady condition of the cluster registration.
+// The status is updated only if it's changed.
+func (ctrl *ClusterAgentController) updateAgentStatus(ctx context.Context, cr *installv1alpha1.ClusterRegistration, agentStatus *installv1alpha1.ClusterAgentStatus,
+	status metav1.ConditionStatus, reason, message string) error {
+	oldStatus := cr.Status.DeepCopy()
+	cr.Status.Agent = agentStatus
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               installv1alpha1.ClusterRegistrationConditionAgentReady,
+		Status:             status,
+		ObservedGeneration: cr.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	phaseutil.SetClusterRegistrationPhase(cr)
+	if equality.Semantic.DeepEqual(oldStatus, &cr.Status) {
+		return nil
+	}
+	_, err := ctrl.fireflyClient.InstallV1alpha1().ClusterRegistrations(cr.Namespace).UpdateStatus(ctx, cr, metav1.UpdateOptions{})
+	return err
+}
+
+// clearAgentStatus removes the agent status and the AgentReady condition of a cluster registration
+// which isn't in pull mode.
+func (ctrl *ClusterAgentController) clearAgentStatus(ctx context.Context, cr *installv1alpha1.ClusterRegistration) error {
+	if cr.Status.Agent == nil && meta.FindStatusCondition(cr.Status.Conditions, installv1alpha1.ClusterRegistrationConditionAgentReady) == nil {
+		return nil
+	}
+	cr.Status.Agent = nil
+	meta.RemoveStatusCondition(&cr.Status.Conditions, installv1alpha1.ClusterRegistrationConditionAgentReady)
+	phaseutil.SetClusterRegistrationPhase(cr)
+	_, err := ctrl.fireflyClient.InstallV1alpha1().ClusterRegistrations(cr.Namespace).UpdateStatus(ctx, cr, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpedia
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/availability"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+// ensureDeployment creates or updates the deployment of a component. The pods of the component are spread
+// and protected by a PodDisruptionBudget according to the availability policy of the clusterpedia if it
+// runs more than one replica. Unlike the components of a karmada, the changes of its pod template are rolled
+// out right away, as a clusterpedia has no update strategy to hold them, e.g. behind canary replicas.
+func (ctrl *ClusterpediaController) ensureDeployment(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia, deployment *appsv1.Deployment) error {
+	maxReplicas := availability.MaxReplicas(deployment.Spec.Replicas, nil)
+	availability.SpreadPods(clusterpedia.Spec.AvailabilityPolicy, &deployment.Spec.Template, deployment.Spec.Selector, maxReplicas)
+
+	result, err := clientutil.CreateOrUpdateDeployment(ctx, ctrl.client, deployment)
+	ctrl.recordOperationResult(ctx, clusterpedia, deployment, result)
+	if err != nil {
+		return err
+	}
+	return ctrl.ensurePodDisruptionBudget(ctx, clusterpedia, deployment, maxReplicas)
+}
+
+// ensurePodDisruptionBudget creates or updates the PodDisruptionBudget of the deployment if the availability
+// policy of the clusterpedia applies to it, otherwise deletes it.
+func (ctrl *ClusterpediaController) ensurePodDisruptionBudget(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia, deployment *appsv1.Deployment, maxReplicas int32) error {
+	pdb := availability.PodDisruptionBudget(clusterpedia.Spec.AvailabilityPolicy, deployment.Namespace, deployment.Name, deployment.Labels, deployment.Spec.Selector, maxReplicas)
+	if pdb == nil {
+		err := ctrl.client.PolicyV1().PodDisruptionBudgets(deployment.Namespace).Delete(ctx, deployment.Name, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "PodDisruptionBudget", Namespace: deployment.Namespace, Name: deployment.Name}, err)
+		return client.IgnoreNotFound(err)
+	}
+
+	controllerutil.SetOwnerReference(clusterpedia, pdb, scheme.Scheme)
+	if err := patchutil.Apply(pdb, clusterpedia.Spec.Patches); err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdatePodDisruptionBudget(ctx, ctrl.client, pdb)
+	ctrl.recordOperationResult(ctx, clusterpedia, pdb, result)
+	return err
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpedia
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/helm"
+	chartutil "github.com/carlory/firefly/pkg/util/chart"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+// EnsureChart installs the clusterpedia from spec.chart instead of the built-in manifests.
+func (ctrl *ClusterpediaController) EnsureChart(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	objs, err := renderChart(ctx, ctrl.chartFetcher, clusterpedia)
+	if err != nil {
+		return err
+	}
+	return chartutil.Install(ctx, ctrl.client, ctrl.applier, clusterpedia, installv1alpha1.SchemeGroupVersion.WithKind("Clusterpedia"), objs)
+}
+
+// RemoveChart removes the objects installed from spec.chart which can't be garbage collected, as recorded
+// by the inventory of the chart. A chart installed before the inventory was recorded is rendered again, and
+// its objects are left behind if it can't be rendered anymore, rather than blocking the deletion forever.
+func (ctrl *ClusterpediaController) RemoveChart(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	if removed, err := chartutil.Uninstall(ctx, ctrl.client, ctrl.applier, clusterpedia, installv1alpha1.SchemeGroupVersion.WithKind("Clusterpedia")); err != nil || removed {
+		return err
+	}
+	objs, err := renderChart(ctx, ctrl.chartFetcher, clusterpedia)
+	if err != nil {
+		klog.ErrorS(err, "Failed to render the chart, leaving the objects installed from it behind", "clusterpedia", klog.KObj(clusterpedia))
+		return nil
+	}
+	return chartutil.DeleteUnowned(ctx, ctrl.applier, clusterpedia, objs)
+}
+
+// renderChart renders spec.chart with the values generated from the spec and spec.valuesOverride,
+// then applies spec.patches to the output.
+func renderChart(ctx context.Context, fetcher *helm.Fetcher, clusterpedia *installv1alpha1.Clusterpedia) ([]*unstructured.Unstructured, error) {
+	override, err := chartutil.ParseValues(clusterpedia.Spec.ValuesOverride)
+	if err != nil {
+		return nil, err
+	}
+	release := helm.ReleaseOptions{Name: clusterpedia.Name, Namespace: clusterpedia.Namespace}
+	objs, err := chartutil.Render(ctx, fetcher, clusterpedia.Spec.Chart, release, chartutil.ImageRegistryValues(clusterpedia.Spec.ImageRepository), override)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objs {
+		if err := patchutil.Apply(obj, clusterpedia.Spec.Patches); err != nil {
+			return nil, err
+		}
+	}
+	return objs, nil
+}
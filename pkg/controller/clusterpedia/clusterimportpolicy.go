@@ -29,12 +29,13 @@ import (
 	"k8s.io/client-go/dynamic"
 
 	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/util/audit"
 )
 
 var gvr = schema.GroupVersionResource{Group: "policy.clusterpedia.io", Version: "v1alpha1", Resource: "clusterimportpolicies"}
 
-func (ctrl *ClusterpediaController) EnsureClusterImportPolicy(clusterpedia *installv1alpha1.Clusterpedia) error {
-	exists, err := ctrl.IsControllPlaneProviderExists(clusterpedia)
+func (ctrl *ClusterpediaController) EnsureClusterImportPolicy(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	exists, err := ctrl.IsControllPlaneProviderExists(ctx, clusterpedia)
 	if err != nil {
 		return err
 	}
@@ -55,14 +56,25 @@ func (ctrl *ClusterpediaController) EnsureClusterImportPolicy(clusterpedia *inst
 		return fmt.Errorf("unsupported controlplane provider")
 	}
 
-	client, err := ctrl.GetControlplaneDynamicClientFromProvider(clusterpedia)
+	client, err := ctrl.GetControlplaneDynamicClientFromProvider(ctx, clusterpedia)
 	if err != nil {
 		return err
 	}
-	return ctrl.applyPolicy(client, policy)
+
+	// the member clusters are registered by the firefly-karmada-manager, so the policy
+	// should be removed, otherwise both of them manage the same PediaClusters.
+	if provider.ClusterRegistration == installv1alpha1.ClusterRegistrationController {
+		err := client.Resource(gvr).Delete(ctx, policy.Name, metav1.DeleteOptions{})
+		audit.RecordResult(ctx, audit.Delete, policy, err)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+	return ctrl.applyPolicy(ctx, client, policy)
 }
 
-func (ctrl *ClusterpediaController) applyPolicy(client dynamic.Interface, policy *policyapi.ClusterImportPolicy) error {
+func (ctrl *ClusterpediaController) applyPolicy(ctx context.Context, client dynamic.Interface, policy *policyapi.ClusterImportPolicy) error {
 	data, _ := json.Marshal(policy)
 	obj := &unstructured.Unstructured{}
 	err := json.Unmarshal(data, obj)
@@ -70,20 +82,22 @@ func (ctrl *ClusterpediaController) applyPolicy(client dynamic.Interface, policy
 		return err
 	}
 
-	_, err = client.Resource(gvr).Create(context.TODO(), obj, metav1.CreateOptions{})
+	_, err = client.Resource(gvr).Create(ctx, obj, metav1.CreateOptions{})
+	audit.RecordResult(ctx, audit.Create, obj, err)
 	if err != nil {
 		if !errors.IsAlreadyExists(err) {
 			return err
 		}
-		old, err := client.Resource(gvr).Get(context.TODO(), obj.GetName(), metav1.GetOptions{})
+		old, err := client.Resource(gvr).Get(ctx, obj.GetName(), metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
 		obj.SetResourceVersion(old.GetResourceVersion())
-		_, err = client.Resource(gvr).Update(context.TODO(), obj, metav1.UpdateOptions{})
+		updated, err := client.Resource(gvr).Update(ctx, obj, metav1.UpdateOptions{})
 		if err != nil {
 			return err
 		}
+		audit.RecordUpdate(ctx, old, updated)
 	}
 	return nil
 }
@@ -19,6 +19,7 @@ package clusterpedia
 import (
 	"context"
 	"fmt"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -34,14 +35,26 @@ import (
 	"github.com/carlory/firefly/pkg/constants"
 	"github.com/carlory/firefly/pkg/scheme"
 	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
 	clientutil "github.com/carlory/firefly/pkg/util/client"
 	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
 	utilresource "github.com/carlory/firefly/pkg/util/resource"
 )
 
+const (
+	// clusterpediaAPIServiceName is the name of the APIService registering the clusterpedia-apiserver
+	// into the control plane.
+	clusterpediaAPIServiceName = "v1beta1.clusterpedia.io"
+
+	// apiServiceCheckInterval is the interval at which a clusterpedia is requeued while its APIService
+	// isn't available.
+	apiServiceCheckInterval = 15 * time.Second
+)
+
 // EnsureAPIServer ensures the clusterpedia-apiserver component.
-func (ctrl *ClusterpediaController) EnsureAPIServer(clusterpedia *installv1alpha1.Clusterpedia) error {
-	hasProvider, err := ctrl.IsControllPlaneProviderExists(clusterpedia)
+func (ctrl *ClusterpediaController) EnsureAPIServer(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	hasProvider, err := ctrl.IsControllPlaneProviderExists(ctx, clusterpedia)
 	if err != nil {
 		return err
 	}
@@ -50,21 +63,39 @@ func (ctrl *ClusterpediaController) EnsureAPIServer(clusterpedia *installv1alpha
 		return fmt.Errorf("unsupported without provider")
 	}
 
-	if err := ctrl.EnsureAPIServerService(clusterpedia); err != nil {
+	if err := ctrl.EnsureAPIServerService(ctx, clusterpedia); err != nil {
+		return err
+	}
+	if err := ctrl.EnsureAPIServerAccess(ctx, clusterpedia); err != nil {
 		return err
 	}
-	if err := ctrl.EnsureAPIServerDeployment(clusterpedia); err != nil {
+	if err := ctrl.EnsureAPIServerDeployment(ctx, clusterpedia); err != nil {
 		return err
 	}
-	if err := ctrl.EnsureClusterpediaAPIService(clusterpedia); err != nil {
+	if err := ctrl.EnsureClusterpediaAPIService(ctx, clusterpedia); err != nil {
 		return err
 	}
 	return nil
 }
 
 // EnsureAPIServerService ensures the clusterpedia-apiserver service exists.
-func (ctrl *ClusterpediaController) EnsureAPIServerService(clusterpedia *installv1alpha1.Clusterpedia) error {
+func (ctrl *ClusterpediaController) EnsureAPIServerService(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	svc, err := apiServerService(clusterpedia)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateService(ctx, ctrl.client, svc)
+	ctrl.recordOperationResult(ctx, clusterpedia, svc, result)
+	return err
+}
+
+// apiServerService returns the clusterpedia-apiserver service of the clusterpedia.
+func apiServerService(clusterpedia *installv1alpha1.Clusterpedia) (*corev1.Service, error) {
 	componentName := constants.ClusterpediaComponentAPIServer
+	serviceType := corev1.ServiceTypeClusterIP
+	if access := clusterpedia.Spec.Access; access != nil && access.ServiceType != "" {
+		serviceType = access.ServiceType
+	}
 	svc := &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
@@ -75,7 +106,7 @@ func (ctrl *ClusterpediaController) EnsureAPIServerService(clusterpedia *install
 			Namespace: clusterpedia.Namespace,
 		},
 		Spec: corev1.ServiceSpec{
-			Type:     corev1.ServiceTypeClusterIP,
+			Type:     serviceType,
 			Selector: map[string]string{"app": componentName},
 			Ports: []corev1.ServicePort{
 				{
@@ -91,11 +122,27 @@ func (ctrl *ClusterpediaController) EnsureAPIServerService(clusterpedia *install
 		},
 	}
 	controllerutil.SetOwnerReference(clusterpedia, svc, scheme.Scheme)
-	return clientutil.CreateOrUpdateService(ctrl.client, svc)
+	if err := patchutil.Apply(svc, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return svc, nil
 }
 
 // EnsureAPIServerDeployment ensures the clusterpedia-apiserver deployment exists.
-func (ctrl *ClusterpediaController) EnsureAPIServerDeployment(clusterpedia *installv1alpha1.Clusterpedia) error {
+func (ctrl *ClusterpediaController) EnsureAPIServerDeployment(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(ctx, clusterpedia)
+	if err != nil {
+		return err
+	}
+	deployment, err := apiServerDeployment(clusterpedia, kubeconfigSecretName)
+	if err != nil {
+		return err
+	}
+	return ctrl.ensureDeployment(ctx, clusterpedia, deployment)
+}
+
+// apiServerDeployment returns the clusterpedia-apiserver deployment of the clusterpedia.
+func apiServerDeployment(clusterpedia *installv1alpha1.Clusterpedia, kubeconfigSecretName string) (*appsv1.Deployment, error) {
 	componentName := constants.ClusterpediaComponentAPIServer
 	server := clusterpedia.Spec.APIServer
 	repository := clusterpedia.Spec.ImageRepository
@@ -119,21 +166,16 @@ func (ctrl *ClusterpediaController) EnsureAPIServerDeployment(clusterpedia *inst
 		"storage-config":            "/etc/clusterpedia/storage/internalstorage-config.yaml",
 		"v":                         "3",
 	}
-	featureGates := maputil.MergeBoolMaps(clusterpedia.Spec.FeatureGates, server.FeatureGates)
-	for feature, enabled := range featureGates {
-		if defaultArgs["feature-gates"] == "" {
-			defaultArgs["feature-gates"] = fmt.Sprintf("%s=%t", feature, enabled)
-		} else {
-			defaultArgs["feature-gates"] = fmt.Sprintf("%s,%s=%t", defaultArgs["feature-gates"], feature, enabled)
-		}
+	if featureGates := maputil.MergeBoolMaps(clusterpedia.Spec.FeatureGates, server.FeatureGates); len(featureGates) > 0 {
+		defaultArgs["feature-gates"] = maputil.ConvertToFeatureGates(featureGates)
 	}
-	computedArgs := maputil.MergeStringMaps(defaultArgs, server.ExtraArgs)
-	args := maputil.ConvertToCommandOrArgs(computedArgs)
-
-	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(clusterpedia)
-	if err != nil {
-		return err
+	if clusterpedia.Spec.Access != nil {
+		// serve with the certificate issued for the addresses it's exposed at instead of the self-signed one.
+		defaultArgs["tls-cert-file"] = apiServerCertMountPath + "/tls.crt"
+		defaultArgs["tls-private-key-file"] = apiServerCertMountPath + "/tls.key"
 	}
+	computedArgs := maputil.MergeStringMaps(defaultArgs, util.LoggingArgs(server.Logging), server.ExtraArgs)
+	args := maputil.ConvertToCommandOrArgs(computedArgs)
 
 	deployment := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
@@ -151,7 +193,8 @@ func (ctrl *ClusterpediaController) EnsureAPIServerDeployment(clusterpedia *inst
 			Replicas: server.Replicas,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{"app": componentName},
+					Labels:      map[string]string{"app": componentName},
+					Annotations: databaseCredentialsAnnotations(clusterpedia),
 				},
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{
@@ -247,12 +290,31 @@ func (ctrl *ClusterpediaController) EnsureAPIServerDeployment(clusterpedia *inst
 			},
 		},
 	}
+	if clusterpedia.Spec.Access != nil {
+		podSpec := &deployment.Spec.Template.Spec
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      "apiserver-cert",
+			MountPath: apiServerCertMountPath,
+			ReadOnly:  true,
+		})
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "apiserver-cert",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: apiServerCertSecretName(),
+				},
+			},
+		})
+	}
 	controllerutil.SetOwnerReference(clusterpedia, deployment, scheme.Scheme)
-	return clientutil.CreateOrUpdateDeployment(ctrl.client, deployment)
+	if err := patchutil.Apply(deployment, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return deployment, nil
 }
 
-func (ctrl *ClusterpediaController) EnsureClusterpediaAPIService(clusterpedia *installv1alpha1.Clusterpedia) error {
-	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(clusterpedia)
+func (ctrl *ClusterpediaController) EnsureClusterpediaAPIService(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(ctx, clusterpedia)
 	if err != nil {
 		return err
 	}
@@ -283,7 +345,10 @@ func (ctrl *ClusterpediaController) EnsureClusterpediaAPIService(clusterpedia *i
 			ExternalName: fmt.Sprintf("%s.%s.svc", constants.ClusterpediaComponentAPIServer, clusterpedia.Namespace),
 		},
 	}
-	if err = clientutil.CreateOrUpdateService(kubeClient, svc); err != nil {
+	if err := patchutil.Apply(svc, clusterpedia.Spec.Patches); err != nil {
+		return err
+	}
+	if _, err = clientutil.CreateOrUpdateService(ctx, kubeClient, svc); err != nil {
 		return err
 	}
 
@@ -293,7 +358,7 @@ func (ctrl *ClusterpediaController) EnsureClusterpediaAPIService(clusterpedia *i
 			Kind:       "APIService",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: "v1beta1.clusterpedia.io",
+			Name: clusterpediaAPIServiceName,
 		},
 		Spec: apiregistrationv1.APIServiceSpec{
 			InsecureSkipTLSVerify: true,
@@ -307,11 +372,16 @@ func (ctrl *ClusterpediaController) EnsureClusterpediaAPIService(clusterpedia *i
 			VersionPriority: 100,
 		},
 	}
-	return clientutil.CreateOrUpdateAPIService(aaClient, apisvc)
+	if err := patchutil.Apply(apisvc, clusterpedia.Spec.Patches); err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateAPIService(ctx, aaClient, apisvc)
+	ctrl.recordOperationResult(ctx, clusterpedia, apisvc, result)
+	return err
 }
 
-func (ctrl *ClusterpediaController) RemoveClusterpediaAPIService(clusterpedia *installv1alpha1.Clusterpedia) error {
-	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(clusterpedia)
+func (ctrl *ClusterpediaController) RemoveClusterpediaAPIService(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(ctx, clusterpedia)
 	if err != nil {
 		return err
 	}
@@ -328,14 +398,97 @@ func (ctrl *ClusterpediaController) RemoveClusterpediaAPIService(clusterpedia *i
 		return err
 	}
 
-	err = kubeClient.CoreV1().Services(constants.ClusterpediaSystemNamespace).Delete(context.TODO(), constants.ClusterpediaComponentAPIServer, metav1.DeleteOptions{})
+	err = kubeClient.CoreV1().Services(constants.ClusterpediaSystemNamespace).Delete(ctx, constants.ClusterpediaComponentAPIServer, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Service", Namespace: constants.ClusterpediaSystemNamespace, Name: constants.ClusterpediaComponentAPIServer}, err)
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
 
-	err = aaClient.ApiregistrationV1().APIServices().Delete(context.TODO(), "v1beta1.clusterpedia.io", metav1.DeleteOptions{})
+	err = aaClient.ApiregistrationV1().APIServices().Delete(ctx, clusterpediaAPIServiceName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "APIService", Name: clusterpediaAPIServiceName}, err)
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
 	return nil
 }
+
+// EnsureAPIServiceAvailable verifies that the clusterpedia APIService is available in the control plane
+// and the aggregated clusterpedia api can be discovered through it, and reports the result by the
+// APIServiceAvailable condition. The clusterpedia is requeued until the APIService is available.
+func (ctrl *ClusterpediaController) EnsureAPIServiceAvailable(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	condition, err := ctrl.apiServiceAvailableCondition(ctx, clusterpedia)
+	if err != nil {
+		return err
+	}
+	if condition.Status != metav1.ConditionTrue {
+		ctrl.enqueueAfter(clusterpedia, apiServiceCheckInterval)
+	}
+	return ctrl.updateClusterpediaCondition(ctx, clusterpedia, installv1alpha1.ClusterpediaConditionAPIServiceAvailable, condition)
+}
+
+// apiServiceAvailableCondition returns the APIServiceAvailable condition of the clusterpedia.
+func (ctrl *ClusterpediaController) apiServiceAvailableCondition(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) (*metav1.Condition, error) {
+	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(ctx, clusterpedia)
+	if err != nil {
+		return nil, err
+	}
+	clientConfig, err := utilresource.GetClientConfigFromKubeConfigSecret(ctrl.client, clusterpedia.Namespace, kubeconfigSecretName, userAgentName)
+	if err != nil {
+		return nil, err
+	}
+	aaClient, err := aggregator.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	apisvc, err := aaClient.ApiregistrationV1().APIServices().Get(ctx, clusterpediaAPIServiceName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return &metav1.Condition{
+			Status:  metav1.ConditionFalse,
+			Reason:  "NotRegistered",
+			Message: fmt.Sprintf("the APIService %s isn't registered", clusterpediaAPIServiceName),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	available := false
+	message := "the APIService has no Available condition"
+	for _, cond := range apisvc.Status.Conditions {
+		if cond.Type == apiregistrationv1.Available {
+			available = cond.Status == apiregistrationv1.ConditionTrue
+			message = cond.Message
+			break
+		}
+	}
+	if !available {
+		return &metav1.Condition{
+			Status:  metav1.ConditionFalse,
+			Reason:  "Unavailable",
+			Message: fmt.Sprintf("the APIService %s isn't available: %s", clusterpediaAPIServiceName, message),
+		}, nil
+	}
+
+	groupVersion := apisvc.Spec.Group + "/" + apisvc.Spec.Version
+	resources, err := aaClient.Discovery().ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		return &metav1.Condition{
+			Status:  metav1.ConditionFalse,
+			Reason:  "DiscoveryFailed",
+			Message: fmt.Sprintf("failed to discover %s: %v", groupVersion, err),
+		}, nil
+	}
+	if len(resources.APIResources) == 0 {
+		return &metav1.Condition{
+			Status:  metav1.ConditionFalse,
+			Reason:  "DiscoveryFailed",
+			Message: fmt.Sprintf("no resources are served by %s", groupVersion),
+		}, nil
+	}
+	return &metav1.Condition{
+		Status:  metav1.ConditionTrue,
+		Reason:  "Available",
+		Message: fmt.Sprintf("%s is served by the clusterpedia-apiserver", groupVersion),
+	}, nil
+}
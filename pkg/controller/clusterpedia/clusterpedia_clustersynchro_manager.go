@@ -17,23 +17,30 @@ limitations under the License.
 package clusterpedia
 
 import (
+	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilpointer "k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
 	"github.com/carlory/firefly/pkg/constants"
 	"github.com/carlory/firefly/pkg/scheme"
 	"github.com/carlory/firefly/pkg/util"
-	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/audit"
 	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
 )
 
-func (ctrl *ClusterpediaController) EnsureClusterSynchroManager(clusterpedia *installv1alpha1.Clusterpedia) error {
-	hasProvider, err := ctrl.IsControllPlaneProviderExists(clusterpedia)
+func (ctrl *ClusterpediaController) EnsureClusterSynchroManager(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	hasProvider, err := ctrl.IsControllPlaneProviderExists(ctx, clusterpedia)
 	if err != nil {
 		return err
 	}
@@ -42,10 +49,101 @@ func (ctrl *ClusterpediaController) EnsureClusterSynchroManager(clusterpedia *in
 		return fmt.Errorf("unsupported without provider")
 	}
 
-	return ctrl.EnsureClusterSynchroManagerDeployment(clusterpedia)
+	return ctrl.EnsureClusterSynchroManagerDeployment(ctx, clusterpedia)
 }
 
-func (ctrl *ClusterpediaController) EnsureClusterSynchroManagerDeployment(clusterpedia *installv1alpha1.Clusterpedia) error {
+func (ctrl *ClusterpediaController) EnsureClusterSynchroManagerDeployment(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(ctx, clusterpedia)
+	if err != nil {
+		return err
+	}
+	deployments, err := clusterSynchroManagerDeployments(clusterpedia, kubeconfigSecretName)
+	if err != nil {
+		return err
+	}
+	for _, deployment := range deployments {
+		if err := ctrl.ensureDeployment(ctx, clusterpedia, deployment); err != nil {
+			return err
+		}
+	}
+	return ctrl.removeStaleClusterSynchroManagers(ctx, clusterpedia)
+}
+
+// removeStaleClusterSynchroManagers deletes the deployments of the clustersynchro-manager, and their
+// PodDisruptionBudgets, which are left by the previous sharding of the clusterpedia, e.g. the shards
+// beyond the current number of shards.
+func (ctrl *ClusterpediaController) removeStaleClusterSynchroManagers(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	deployments, err := ctrl.client.AppsV1().Deployments(clusterpedia.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	componentName := constants.ClusterpediaComponentClusterSynchroManager
+	names := sets.NewString(clusterSynchroManagerDeploymentNames(clusterpedia)...)
+	for _, deployment := range deployments.Items {
+		// the deployment of an unsharded clustersynchro-manager isn't labeled, so they're matched by names.
+		if deployment.Name != componentName && !strings.HasPrefix(deployment.Name, componentName+"-") {
+			continue
+		}
+		if names.Has(deployment.Name) || !metav1.IsControlledBy(&deployment, clusterpedia) {
+			continue
+		}
+		err := ctrl.client.AppsV1().Deployments(deployment.Namespace).Delete(ctx, deployment.Name, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Deployment", Namespace: deployment.Namespace, Name: deployment.Name}, err)
+		if err := client.IgnoreNotFound(err); err != nil {
+			return err
+		}
+		err = ctrl.client.PolicyV1().PodDisruptionBudgets(deployment.Namespace).Delete(ctx, deployment.Name, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "PodDisruptionBudget", Namespace: deployment.Namespace, Name: deployment.Name}, err)
+		if err := client.IgnoreNotFound(err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clusterSynchroManagerDeploymentNames returns the names of the deployments of the clustersynchro-manager,
+// which has one deployment per shard if it's sharded.
+func clusterSynchroManagerDeploymentNames(clusterpedia *installv1alpha1.Clusterpedia) []string {
+	sharding := clusterpedia.Spec.ClusterpediaSynchroManager.Sharding
+	if sharding == nil {
+		return []string{constants.ClusterpediaComponentClusterSynchroManager}
+	}
+	names := make([]string, 0, sharding.Shards)
+	for shard := int32(0); shard < sharding.Shards; shard++ {
+		names = append(names, clusterSynchroManagerShardName(shard))
+	}
+	return names
+}
+
+// clusterSynchroManagerShardName returns the name of the deployment of a shard of the clustersynchro-manager.
+func clusterSynchroManagerShardName(shard int32) string {
+	return fmt.Sprintf("%s-%d", constants.ClusterpediaComponentClusterSynchroManager, shard)
+}
+
+// clusterSynchroManagerDeployments returns the clusterpedia-clustersynchro-manager deployments of the clusterpedia.
+func clusterSynchroManagerDeployments(clusterpedia *installv1alpha1.Clusterpedia, kubeconfigSecretName string) ([]*appsv1.Deployment, error) {
+	sharding := clusterpedia.Spec.ClusterpediaSynchroManager.Sharding
+	if sharding == nil {
+		deployment, err := clusterSynchroManagerDeployment(clusterpedia, kubeconfigSecretName, nil)
+		if err != nil {
+			return nil, err
+		}
+		return []*appsv1.Deployment{deployment}, nil
+	}
+	deployments := make([]*appsv1.Deployment, 0, sharding.Shards)
+	for shard := int32(0); shard < sharding.Shards; shard++ {
+		deployment, err := clusterSynchroManagerDeployment(clusterpedia, kubeconfigSecretName, utilpointer.Int32(shard))
+		if err != nil {
+			return nil, err
+		}
+		deployments = append(deployments, deployment)
+	}
+	return deployments, nil
+}
+
+// clusterSynchroManagerDeployment returns the clusterpedia-clustersynchro-manager deployment of the clusterpedia,
+// or the deployment of the given shard if it's sharded.
+func clusterSynchroManagerDeployment(clusterpedia *installv1alpha1.Clusterpedia, kubeconfigSecretName string, shard *int32) (*appsv1.Deployment, error) {
 	componentName := constants.ClusterpediaComponentClusterSynchroManager
 	manager := clusterpedia.Spec.ClusterpediaSynchroManager
 	repository := clusterpedia.Spec.ImageRepository
@@ -63,21 +161,37 @@ func (ctrl *ClusterpediaController) EnsureClusterSynchroManagerDeployment(cluste
 		"storage-config":                  "/etc/clusterpedia/storage/internalstorage-config.yaml",
 		"v":                               "4",
 	}
-	featureGates := maputil.MergeBoolMaps(clusterpedia.Spec.FeatureGates, manager.FeatureGates)
-	for feature, enabled := range featureGates {
-		if defaultArgs["feature-gates"] == "" {
-			defaultArgs["feature-gates"] = fmt.Sprintf("%s=%t", feature, enabled)
-		} else {
-			defaultArgs["feature-gates"] = fmt.Sprintf("%s,%s=%t", defaultArgs["feature-gates"], feature, enabled)
-		}
+	if featureGates := maputil.MergeBoolMaps(clusterpedia.Spec.FeatureGates, manager.FeatureGates); len(featureGates) > 0 {
+		defaultArgs["feature-gates"] = maputil.ConvertToFeatureGates(featureGates)
 	}
-	computedArgs := maputil.MergeStringMaps(defaultArgs, manager.ExtraArgs)
-	args := maputil.ConvertToCommandOrArgs(computedArgs)
 
-	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(clusterpedia)
-	if err != nil {
-		return err
+	name := componentName
+	var shardLabels map[string]string
+	env := []corev1.EnvVar{
+		{
+			Name: "DB_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: GenerateDatabaseSecretName(clusterpedia),
+					},
+					Key: "password",
+				},
+			},
+		},
+	}
+	if shard != nil {
+		// the shards are elected separately, so that each of them has its own leader.
+		name = clusterSynchroManagerShardName(*shard)
+		shardLabels = map[string]string{constants.ClusterSynchroShardLabel: strconv.Itoa(int(*shard))}
+		defaultArgs["leader-elect-resource-name"] = name
+		env = append(env,
+			corev1.EnvVar{Name: "SHARD_INDEX", Value: strconv.Itoa(int(*shard))},
+			corev1.EnvVar{Name: "SHARD_COUNT", Value: strconv.Itoa(int(manager.Sharding.Shards))},
+		)
 	}
+	computedArgs := maputil.MergeStringMaps(defaultArgs, util.LoggingArgs(manager.Logging), manager.ExtraArgs)
+	args := maputil.ConvertToCommandOrArgs(computedArgs)
 
 	deployment := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
@@ -85,17 +199,19 @@ func (ctrl *ClusterpediaController) EnsureClusterSynchroManagerDeployment(cluste
 			Kind:       "Deployment",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      componentName,
+			Name:      name,
 			Namespace: clusterpedia.Namespace,
+			Labels:    shardLabels,
 		},
 		Spec: appsv1.DeploymentSpec{
 			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{"app": componentName},
+				MatchLabels: maputil.MergeStringMaps(map[string]string{"app": componentName}, shardLabels),
 			},
 			Replicas: manager.Replicas,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{"app": componentName},
+					Labels:      maputil.MergeStringMaps(map[string]string{"app": componentName}, shardLabels),
+					Annotations: databaseCredentialsAnnotations(clusterpedia),
 				},
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{
@@ -105,20 +221,8 @@ func (ctrl *ClusterpediaController) EnsureClusterSynchroManagerDeployment(cluste
 							ImagePullPolicy: "IfNotPresent",
 							Command:         []string{"/usr/local/bin/clustersynchro-manager"},
 							Args:            args,
-							Env: []corev1.EnvVar{
-								{
-									Name: "DB_PASSWORD",
-									ValueFrom: &corev1.EnvVarSource{
-										SecretKeyRef: &corev1.SecretKeySelector{
-											LocalObjectReference: corev1.LocalObjectReference{
-												Name: GenerateDatabaseSecretName(clusterpedia),
-											},
-											Key: "password",
-										},
-									},
-								},
-							},
-							Resources: manager.Resources,
+							Env:             env,
+							Resources:       manager.Resources,
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      "internalstorage-config",
@@ -159,5 +263,8 @@ func (ctrl *ClusterpediaController) EnsureClusterSynchroManagerDeployment(cluste
 		},
 	}
 	controllerutil.SetOwnerReference(clusterpedia, deployment, scheme.Scheme)
-	return clientutil.CreateOrUpdateDeployment(ctrl.client, deployment)
+	if err := patchutil.Apply(deployment, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return deployment, nil
 }
@@ -19,6 +19,7 @@ package clusterpedia
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -28,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	clientset "k8s.io/client-go/kubernetes"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -40,7 +42,19 @@ import (
 	fireflyclient "github.com/carlory/firefly/pkg/generated/clientset/versioned"
 	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
 	installlisters "github.com/carlory/firefly/pkg/generated/listers/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/helm"
 	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/adoption"
+	"github.com/carlory/firefly/pkg/util/apply"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/coalesce"
+	"github.com/carlory/firefly/pkg/util/debug"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	informerutil "github.com/carlory/firefly/pkg/util/informer"
+	"github.com/carlory/firefly/pkg/util/notification"
+	phaseutil "github.com/carlory/firefly/pkg/util/phase"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
 )
 
 const (
@@ -59,7 +73,12 @@ const (
 func NewClusterpediaController(
 	client clientset.Interface,
 	fireflyClient fireflyclient.Interface,
-	clusterpediaInformer installinformers.ClusterpediaInformer) (*ClusterpediaController, error) {
+	clusterpediaInformer installinformers.ClusterpediaInformer,
+	restConfig *rest.Config,
+	podMonitorsAvailable bool,
+	innoDBClustersAvailable bool,
+	resyncPeriod time.Duration,
+	rateLimiter workqueue.RateLimiter) (*ClusterpediaController, error) {
 	broadcaster := record.NewBroadcaster()
 	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "clusterpedia-controller"})
 
@@ -67,22 +86,34 @@ func NewClusterpediaController(
 		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("clusterpedia_controller", client.CoreV1().RESTClient().GetRateLimiter())
 	}
 
+	applier, err := apply.NewApplier(restConfig, "firefly-clusterpedia-controller")
+	if err != nil {
+		return nil, err
+	}
+
+	queue := debug.NewQueue(workqueue.NewNamedRateLimitingQueue(rateLimiter, "clusterpedia"))
 	ctrl := &ClusterpediaController{
 		client:              client,
 		fireflyClient:       fireflyClient,
 		clusterpediasLister: clusterpediaInformer.Lister(),
 		clusterpediasSynced: clusterpediaInformer.Informer().HasSynced,
-		queue:               workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "clusterpedia"),
+		queue:               coalesce.NewQueue(queue, coalesce.DefaultWindow, coalesce.DefaultInterval),
+		debugRecorder:       debug.NewRecorder(queue),
 		workerLoopPeriod:    time.Second,
 		eventBroadcaster:    broadcaster,
 		eventRecorder:       recorder,
+		applier:             applier,
+		chartFetcher:        helm.NewFetcher(),
+
+		podMonitorsAvailable:    podMonitorsAvailable,
+		innoDBClustersAvailable: innoDBClustersAvailable,
 	}
 
-	clusterpediaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	informerutil.AddEventHandler(clusterpediaInformer.Informer(), cache.ResourceEventHandlerFuncs{
 		AddFunc:    ctrl.addClusterpedia,
 		UpdateFunc: ctrl.updateClusterpedia,
 		DeleteFunc: ctrl.deleteClusterpedia,
-	})
+	}, resyncPeriod)
 
 	return ctrl, nil
 }
@@ -96,17 +127,41 @@ type ClusterpediaController struct {
 	clusterpediasLister installlisters.ClusterpediaLister
 	clusterpediasSynced cache.InformerSynced
 
+	// applier applies the objects rendered from charts.
+	applier *apply.Applier
+	// chartFetcher loads the charts referenced by spec.chart.
+	chartFetcher *helm.Fetcher
+	// podMonitorsAvailable is true if the Prometheus Operator is installed into the host cluster,
+	// so that the monitored components are scraped by PodMonitors.
+	podMonitorsAvailable bool
+	// innoDBClustersAvailable is true if the MySQL Operator for Kubernetes is installed into the host
+	// cluster, so that mysql can be delegated to it.
+	innoDBClustersAvailable bool
+
 	// Clusterpedia that need to be updated. A channel is inappropriate here,
 	// because it allows services with lots of pods to be serviced much
 	// more often than services with few pods; it also would cause a
 	// service that's inserted multiple times to be processed more than
 	// necessary.
-	queue workqueue.RateLimitingInterface
+	queue *coalesce.Queue
+	// debugRecorder records the reconcile results and desired states for the debugging handler.
+	debugRecorder *debug.Recorder
 
 	// workerLoopPeriod is the time between worker runs. The workers process the queue of service and pod changes.
 	workerLoopPeriod time.Duration
 }
 
+// Name returns the name of the controller.
+func (ctrl *ClusterpediaController) Name() string {
+	return "clusterpedia"
+}
+
+// DebuggingHandler returns a handler which exposes the queue, the last reconcile results
+// and the changes of the desired states of the controller.
+func (ctrl *ClusterpediaController) DebuggingHandler() http.Handler {
+	return ctrl.debugRecorder
+}
+
 // Run will not return until stopCh is closed. workers determines how many
 // clusterpedia will be handled in parallel.
 func (ctrl *ClusterpediaController) Run(ctx context.Context, workers int) {
@@ -148,7 +203,11 @@ func (ctrl *ClusterpediaController) processNextWorkItem(ctx context.Context) boo
 	}
 	defer ctrl.queue.Done(key)
 
+	startTime := time.Now()
+	ctx, span := tracingutil.StartReconcile(ctx, "clusterpedia", key.(string))
 	err := ctrl.syncClusterpedia(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.debugRecorder.ObserveReconcile(key.(string), startTime, err)
 	ctrl.handleErr(err, key)
 
 	return true
@@ -164,6 +223,11 @@ func (ctrl *ClusterpediaController) updateClusterpedia(old, cur interface{}) {
 	oldClusterpedia := old.(*installv1alpha1.Clusterpedia)
 	curClusterpedia := cur.(*installv1alpha1.Clusterpedia)
 	klog.V(4).InfoS("Updating clusterpedia", "clusterpedia", klog.KObj(oldClusterpedia))
+	// Status-only updates are coalesced, the changes of the spec or the metadata are synced at once.
+	if !coalesce.Changed(oldClusterpedia, curClusterpedia) {
+		ctrl.enqueueCoalesced(curClusterpedia)
+		return
+	}
 	ctrl.enqueue(curClusterpedia)
 }
 
@@ -194,6 +258,17 @@ func (ctrl *ClusterpediaController) enqueue(clusterpedia *installv1alpha1.Cluste
 	ctrl.queue.Add(key)
 }
 
+// enqueueCoalesced enqueues the clusterpedia after the bursts of its status updates, which are written by
+// several controllers, have settled.
+func (ctrl *ClusterpediaController) enqueueCoalesced(clusterpedia *installv1alpha1.Clusterpedia) {
+	key, err := cache.MetaNamespaceKeyFunc(clusterpedia)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.AddCoalesced(key)
+}
+
 func (ctrl *ClusterpediaController) handleErr(err error, key interface{}) {
 	if err == nil || errors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
 		ctrl.queue.Forget(key)
@@ -205,6 +280,7 @@ func (ctrl *ClusterpediaController) handleErr(err error, key interface{}) {
 		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
 	}
 
+	notification.ReconcileFailed("clusterpedia", audit.ObjectReference{Kind: "Clusterpedia", Namespace: ns, Name: name}, ctrl.queue.NumRequeues(key)+1, err)
 	if ctrl.queue.NumRequeues(key) < maxRetries {
 		klog.V(2).InfoS("Error syncing clusterpedia, retrying", "clusterpedia", klog.KRef(ns, name), "err", err)
 		ctrl.queue.AddRateLimited(key)
@@ -232,6 +308,7 @@ func (ctrl *ClusterpediaController) syncClusterpedia(ctx context.Context, key st
 	clusterpedia, err := ctrl.clusterpediasLister.Clusterpedias(namespace).Get(name)
 	if errors.IsNotFound(err) {
 		klog.V(2).InfoS("Clusterpedia has been deleted", "clusterpedia", klog.KRef(namespace, name))
+		ctrl.debugRecorder.Forget(key)
 		return nil
 	}
 	if err != nil {
@@ -241,6 +318,9 @@ func (ctrl *ClusterpediaController) syncClusterpedia(ctx context.Context, key st
 	// Deep-copy otherwise we are mutating our cache.
 	// TODO: Deep-copy only when needed.
 	clusterpedia = clusterpedia.DeepCopy()
+	ctx = audit.WithTrigger(ctx, clusterpedia)
+	ctx = dryrun.ForObject(ctx, clusterpedia)
+	ctx = adoption.ForObject(ctx, clusterpedia)
 
 	// examine DeletionTimestamp to determine if object is under deletion
 	if clusterpedia.DeletionTimestamp.IsZero() {
@@ -258,7 +338,7 @@ func (ctrl *ClusterpediaController) syncClusterpedia(ctx context.Context, key st
 		// The object is being deleted
 		if controllerutil.ContainsFinalizer(clusterpedia, ClusterpediaControllerFinalizerName) {
 			// our finalizer is present, so lets handle any external dependency
-			if err := ctrl.deleteUnableGCResources(clusterpedia); err != nil {
+			if err := ctrl.deleteUnableGCResources(ctx, clusterpedia); err != nil {
 				// if fail to delete the external dependency here, return with error
 				// so that it can be retried
 				return err
@@ -275,62 +355,165 @@ func (ctrl *ClusterpediaController) syncClusterpedia(ctx context.Context, key st
 		}
 	}
 
+	if clusterpedia.Spec.Paused {
+		klog.V(2).InfoS("Clusterpedia is paused, skip syncing", "clusterpedia", klog.KObj(clusterpedia))
+		return ctrl.updateClusterpediaCondition(ctx, clusterpedia, installv1alpha1.ClusterpediaConditionPaused, &metav1.Condition{
+			Status:  metav1.ConditionTrue,
+			Reason:  "Paused",
+			Message: "the reconciliation of the clusterpedia is paused",
+		})
+	}
+	if err := ctrl.updateClusterpediaCondition(ctx, clusterpedia, installv1alpha1.ClusterpediaConditionPaused, nil); err != nil {
+		return err
+	}
+
 	klog.InfoS("Syncing clusterpedia", "clusterpedia", klog.KObj(clusterpedia))
 
-	if err := ctrl.EnsureNamespace(clusterpedia); err != nil {
-		return err
+	if clusterpedia.Spec.Chart != nil {
+		if err := ctrl.EnsureChart(ctx, clusterpedia); err != nil {
+			return ctrl.reconcileFailed(ctx, clusterpedia, "ChartFailed", err)
+		}
+		return nil
 	}
 
-	if err := ctrl.EnsureClusterpediaCRDs(clusterpedia); err != nil {
-		return err
+	if err := ctrl.EnsureNamespace(ctx, clusterpedia); err != nil {
+		return ctrl.reconcileFailed(ctx, clusterpedia, "NamespaceFailed", err)
 	}
 
-	if err := ctrl.EnsureInternalStorage(clusterpedia); err != nil {
-		return err
+	if err := ctrl.EnsureClusterpediaCRDs(ctx, clusterpedia); err != nil {
+		return ctrl.reconcileFailed(ctx, clusterpedia, "CRDsFailed", err)
 	}
 
-	if err := ctrl.EnsureAPIServer(clusterpedia); err != nil {
-		return err
+	if err := ctrl.EnsureInternalStorage(ctx, clusterpedia); err != nil {
+		return ctrl.reconcileFailed(ctx, clusterpedia, "InternalStorageFailed", err)
+	}
+
+	if err := ctrl.EnsureStorageRetention(ctx, clusterpedia); err != nil {
+		return ctrl.reconcileFailed(ctx, clusterpedia, "StorageRetentionFailed", err)
+	}
+
+	if err := ctrl.EnsureNetworkPolicy(ctx, clusterpedia); err != nil {
+		return ctrl.reconcileFailed(ctx, clusterpedia, "NetworkPolicyFailed", err)
+	}
+
+	// the components are rolled out to a new version once the schema of the storage is migrated.
+	rollOut, err := ctrl.EnsureUpgrade(ctx, clusterpedia)
+	if err != nil {
+		return ctrl.reconcileFailed(ctx, clusterpedia, "UpgradeFailed", err)
+	}
+	if !rollOut {
+		return nil
 	}
 
-	if err := ctrl.EnsureControllerManager(clusterpedia); err != nil {
+	// the components only depend on the storage, so that a slow one, e.g. waiting for its image to be
+	// pulled, doesn't hold back the others.
+	err = ctrl.reconcileConcurrently(ctx, clusterpedia,
+		func(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+			if err := ctrl.EnsureAPIServer(ctx, clusterpedia); err != nil {
+				return ctrl.reconcileFailed(ctx, clusterpedia, "APIServerFailed", err)
+			}
+			return nil
+		},
+		func(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+			if err := ctrl.EnsureControllerManager(ctx, clusterpedia); err != nil {
+				return ctrl.reconcileFailed(ctx, clusterpedia, "ControllerManagerFailed", err)
+			}
+			return nil
+		},
+		func(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+			if err := ctrl.EnsureClusterSynchroManager(ctx, clusterpedia); err != nil {
+				return ctrl.reconcileFailed(ctx, clusterpedia, "ClusterSynchroManagerFailed", err)
+			}
+			return nil
+		},
+		func(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+			if err := ctrl.EnsureClusterImportPolicy(ctx, clusterpedia); err != nil {
+				return ctrl.reconcileFailed(ctx, clusterpedia, "ClusterImportPolicyFailed", err)
+			}
+			return nil
+		},
+		func(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+			if err := ctrl.EnsureMonitoring(ctx, clusterpedia); err != nil {
+				return ctrl.reconcileFailed(ctx, clusterpedia, "MonitoringFailed", err)
+			}
+			return nil
+		},
+	)
+	if err != nil {
 		return err
 	}
 
-	if err := ctrl.EnsureClusterSynchroManager(clusterpedia); err != nil {
+	// pods being Running doesn't mean the search is usable, so the aggregated api is verified.
+	if err := ctrl.EnsureAPIServiceAvailable(ctx, clusterpedia); err != nil {
+		return ctrl.reconcileFailed(ctx, clusterpedia, "APIServiceFailed", err)
+	}
+
+	if completed, err := ctrl.completeUpgrade(ctx, clusterpedia); err != nil || !completed {
 		return err
 	}
+	return ctrl.updateInstalledVersion(ctx, clusterpedia)
+}
+
+// reconcileFailed emits a warning event on the clusterpedia for the failed step of the reconciliation
+// and returns the error.
+func (ctrl *ClusterpediaController) reconcileFailed(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia, reason string, err error) error {
+	ctrl.eventRecorder.Eventf(clusterpedia, corev1.EventTypeWarning, reason, "Failed to reconcile clusterpedia: %v", err)
+	return err
+}
+
+// updateInstalledVersion records the version and the generation of the clusterpedia once all of its
+// components are reconciled.
+func (ctrl *ClusterpediaController) updateInstalledVersion(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	installed := clusterpedia.Status.Version
+	if installed == clusterpedia.Spec.Version && clusterpedia.Status.ObservedGeneration == clusterpedia.Generation {
+		return nil
+	}
 
-	if err := ctrl.EnsureClusterImportPolicy(clusterpedia); err != nil {
+	clusterpedia.Status.Version = clusterpedia.Spec.Version
+	clusterpedia.Status.ObservedGeneration = clusterpedia.Generation
+	phaseutil.SetClusterpediaPhase(clusterpedia)
+	updated, err := ctrl.fireflyClient.InstallV1alpha1().Clusterpedias(clusterpedia.Namespace).UpdateStatus(ctx, clusterpedia, metav1.UpdateOptions{})
+	if err != nil {
 		return err
 	}
+	clusterpedia.ResourceVersion = updated.ResourceVersion
 
+	switch installed {
+	case clusterpedia.Spec.Version:
+		// only the generation is recorded.
+	case "":
+		ctrl.eventRecorder.Eventf(clusterpedia, corev1.EventTypeNormal, "InstallCompleted", "Installed clusterpedia %s", clusterpedia.Spec.Version)
+	default:
+		ctrl.eventRecorder.Eventf(clusterpedia, corev1.EventTypeNormal, "UpgradeCompleted", "Upgraded clusterpedia from %s to %s", installed, clusterpedia.Spec.Version)
+	}
 	return nil
 }
 
-func (ctrl *ClusterpediaController) EnsureNamespace(clusterpedia *installv1alpha1.Clusterpedia) error {
-	kubeClient, err := ctrl.GetControlplaneClient(clusterpedia)
+func (ctrl *ClusterpediaController) EnsureNamespace(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	kubeClient, err := ctrl.GetControlplaneClient(ctx, clusterpedia)
 	if err != nil {
 		return err
 	}
 
 	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: clusterpedia.Namespace}}
-	hasProvider, err := ctrl.IsControllPlaneProviderExists(clusterpedia)
+	hasProvider, err := ctrl.IsControllPlaneProviderExists(ctx, clusterpedia)
 	if err != nil {
 		return err
 	}
 	if hasProvider {
 		ns.Name = constants.ClusterpediaSystemNamespace
 	}
-	_, err = kubeClient.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
-		return err
-	}
-	return nil
+	result, err := clientutil.CreateOrUpdateNamespace(ctx, kubeClient, ns)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, clusterpedia, ns, result)
+	return err
 }
 
-func (ctrl *ClusterpediaController) deleteUnableGCResources(clusterpedia *installv1alpha1.Clusterpedia) error {
-	_, err := ctrl.IsControllPlaneProviderExists(clusterpedia)
+func (ctrl *ClusterpediaController) deleteUnableGCResources(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	if clusterpedia.Spec.Chart != nil {
+		return ctrl.RemoveChart(ctx, clusterpedia)
+	}
+
+	_, err := ctrl.IsControllPlaneProviderExists(ctx, clusterpedia)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return nil
@@ -338,19 +521,19 @@ func (ctrl *ClusterpediaController) deleteUnableGCResources(clusterpedia *instal
 		return err
 	}
 
-	err = ctrl.RemoveClusterpediaCRDs(clusterpedia)
+	err = ctrl.RemoveClusterpediaCRDs(ctx, clusterpedia)
 	if err != nil {
 		return err
 	}
-	err = ctrl.RemoveClusterpediaAPIService(clusterpedia)
+	err = ctrl.RemoveClusterpediaAPIService(ctx, clusterpedia)
 	if err != nil {
 		return err
 	}
-	return ctrl.removeNamespaceFromControlplaneProvider(clusterpedia)
+	return ctrl.removeNamespaceFromControlplaneProvider(ctx, clusterpedia)
 }
 
-func (ctrl *ClusterpediaController) removeNamespaceFromControlplaneProvider(clusterpedia *installv1alpha1.Clusterpedia) error {
-	hasProvider, err := ctrl.IsControllPlaneProviderExists(clusterpedia)
+func (ctrl *ClusterpediaController) removeNamespaceFromControlplaneProvider(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	hasProvider, err := ctrl.IsControllPlaneProviderExists(ctx, clusterpedia)
 	if err != nil {
 		return err
 	}
@@ -360,12 +543,13 @@ func (ctrl *ClusterpediaController) removeNamespaceFromControlplaneProvider(clus
 		return nil
 	}
 
-	kubeClient, err := ctrl.GetControlplaneClientFromProvider(clusterpedia)
+	kubeClient, err := ctrl.GetControlplaneClientFromProvider(ctx, clusterpedia)
 	if err != nil {
 		return err
 	}
 
-	err = kubeClient.CoreV1().Namespaces().Delete(context.TODO(), constants.ClusterpediaSystemNamespace, metav1.DeleteOptions{})
+	err = kubeClient.CoreV1().Namespaces().Delete(ctx, constants.ClusterpediaSystemNamespace, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Namespace", Name: constants.ClusterpediaSystemNamespace}, err)
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
@@ -17,18 +17,19 @@ limitations under the License.
 package clusterpedia
 
 import (
+	"context"
 	"fmt"
 
 	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
 )
 
-func (ctrl *ClusterpediaController) EnsureInternalStorage(clusterpedia *installv1alpha1.Clusterpedia) error {
+func (ctrl *ClusterpediaController) EnsureInternalStorage(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
 	storage := clusterpedia.Spec.Storage
 	switch {
 	case storage.Postgres != nil && storage.Postgres.Local != nil:
-		return ctrl.EnsurePostgres(clusterpedia)
+		return ctrl.EnsurePostgres(ctx, clusterpedia)
 	case storage.MySQL != nil && storage.MySQL.Local != nil:
-		return ctrl.EnsureMySQL(clusterpedia)
+		return ctrl.EnsureMySQL(ctx, clusterpedia)
 	}
 	return fmt.Errorf("unknown storage type")
 }
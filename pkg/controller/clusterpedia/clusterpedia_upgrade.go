@@ -0,0 +1,303 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpedia
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilpointer "k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+	phaseutil "github.com/carlory/firefly/pkg/util/phase"
+)
+
+const (
+	// storageMigrationJobName is the name of the job which migrates the schema of the storage.
+	storageMigrationJobName = "clusterpedia-storage-migration"
+	// storageMigrationVersionAnnotation is the annotation of the migration job whose value is the
+	// version which the schema is migrated to.
+	storageMigrationVersionAnnotation = "install.firefly.io/clusterpedia-version"
+
+	// upgradeCheckInterval is the interval at which a clusterpedia is requeued while it's being
+	// upgraded, since the migration job and the deployments are not watched.
+	upgradeCheckInterval = 10 * time.Second
+)
+
+// upgradedDeployments returns the deployments which are rolled out to the new version by an upgrade.
+func upgradedDeployments(clusterpedia *installv1alpha1.Clusterpedia) []string {
+	return append([]string{
+		constants.ClusterpediaComponentAPIServer,
+		constants.ClusterpediaComponentControllerManager,
+	}, clusterSynchroManagerDeploymentNames(clusterpedia)...)
+}
+
+// storageMigrationJob returns the job which migrates the schema of the storage to spec.version.
+func storageMigrationJob(clusterpedia *installv1alpha1.Clusterpedia) (*batchv1.Job, error) {
+	migration := clusterpedia.Spec.Storage.Migration
+	repository := clusterpedia.Spec.ImageRepository
+	tag := clusterpedia.Spec.Version
+	if migration.ImageRepository != "" {
+		repository = migration.ImageRepository
+	}
+	if migration.ImageTag != "" {
+		tag = migration.ImageTag
+	}
+
+	job := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      storageMigrationJobName,
+			Namespace: clusterpedia.Namespace,
+			Labels:    map[string]string{"app": storageMigrationJobName},
+			Annotations: map[string]string{
+				storageMigrationVersionAnnotation: clusterpedia.Spec.Version,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: utilpointer.Int32(3),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": storageMigrationJobName},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:            "migration",
+							Image:           util.ComponentImageName(repository, migration.ImageName, tag),
+							ImagePullPolicy: "IfNotPresent",
+							Command:         migration.Command,
+							Args:            migration.Args,
+							Env: []corev1.EnvVar{
+								{
+									Name: "DB_PASSWORD",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: GenerateDatabaseSecretName(clusterpedia),
+											},
+											Key: databasePasswordKey,
+										},
+									},
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "internalstorage-config",
+									MountPath: "/etc/clusterpedia/storage",
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "internalstorage-config",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: GenerateDatabaseConfigMapName(clusterpedia),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	controllerutil.SetOwnerReference(clusterpedia, job, scheme.Scheme)
+	if err := patchutil.Apply(job, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// EnsureUpgrade drives the upgrade of the clusterpedia to spec.version, and records its phase in
+// status.upgrade. It returns whether the components can be rolled out to spec.version, that is the
+// clusterpedia isn't being upgraded or the schema of the storage has been migrated.
+func (ctrl *ClusterpediaController) EnsureUpgrade(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) (bool, error) {
+	installed := clusterpedia.Status.Version
+	if installed == "" || installed == clusterpedia.Spec.Version {
+		// an upgrade which is reverted before it's completed is dropped.
+		if upgrade := clusterpedia.Status.Upgrade; upgrade != nil && upgrade.Phase != installv1alpha1.ClusterpediaUpgradeCompleted {
+			if err := ctrl.deleteStorageMigrationJob(ctx, clusterpedia); err != nil {
+				return false, err
+			}
+			clusterpedia.Status.Upgrade = nil
+			if err := ctrl.updateStatus(ctx, clusterpedia); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	upgrade := clusterpedia.Status.Upgrade
+	if upgrade == nil || upgrade.FromVersion != installed || upgrade.ToVersion != clusterpedia.Spec.Version {
+		if err := ctrl.deleteStorageMigrationJob(ctx, clusterpedia); err != nil {
+			return false, err
+		}
+		ctrl.eventRecorder.Eventf(clusterpedia, corev1.EventTypeNormal, "UpgradeStarted", "Upgrading clusterpedia from %s to %s", installed, clusterpedia.Spec.Version)
+		clusterpedia.Status.Upgrade = &installv1alpha1.ClusterpediaUpgrade{
+			FromVersion: installed,
+			ToVersion:   clusterpedia.Spec.Version,
+			Phase:       installv1alpha1.ClusterpediaUpgradeMigrating,
+			Message:     "migrating the schema of the storage",
+			StartTime:   metav1.Now(),
+		}
+		if err := ctrl.updateStatus(ctx, clusterpedia); err != nil {
+			return false, err
+		}
+		upgrade = clusterpedia.Status.Upgrade
+	}
+
+	switch upgrade.Phase {
+	case installv1alpha1.ClusterpediaUpgradeFailed:
+		return false, nil
+	case installv1alpha1.ClusterpediaUpgradeMigrating:
+		migrated, err := ctrl.ensureStorageMigration(ctx, clusterpedia)
+		if err != nil {
+			ctrl.eventRecorder.Eventf(clusterpedia, corev1.EventTypeWarning, "UpgradeFailed", "Failed to upgrade clusterpedia to %s: %v", clusterpedia.Spec.Version, err)
+			return false, ctrl.updateUpgradePhase(ctx, clusterpedia, installv1alpha1.ClusterpediaUpgradeFailed, err.Error())
+		}
+		if !migrated {
+			ctrl.enqueueAfter(clusterpedia, upgradeCheckInterval)
+			return false, nil
+		}
+		return true, ctrl.updateUpgradePhase(ctx, clusterpedia, installv1alpha1.ClusterpediaUpgradeRollingOut, "rolling out the components")
+	}
+	return true, nil
+}
+
+// ensureStorageMigration runs the migration job of spec.version and returns whether it's complete.
+// An error is returned if the job failed. There is nothing to wait for if no migration is configured.
+func (ctrl *ClusterpediaController) ensureStorageMigration(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) (bool, error) {
+	if clusterpedia.Spec.Storage.Migration == nil {
+		return true, nil
+	}
+
+	job, err := storageMigrationJob(clusterpedia)
+	if err != nil {
+		return false, err
+	}
+	got, err := ctrl.client.BatchV1().Jobs(job.Namespace).Get(ctx, job.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err := ctrl.client.BatchV1().Jobs(job.Namespace).Create(ctx, job, metav1.CreateOptions{}); err != nil {
+			return false, err
+		}
+		audit.Record(ctx, audit.Create, job, nil)
+		ctrl.recordOperationResult(ctx, clusterpedia, job, clientutil.OperationResultCreated)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case got.Annotations[storageMigrationVersionAnnotation] != clusterpedia.Spec.Version:
+		// the job is left by another upgrade, it's recreated for this one.
+		return false, ctrl.deleteJob(ctx, got)
+	case jobConditionTrue(got, batchv1.JobComplete):
+		return true, nil
+	case jobConditionTrue(got, batchv1.JobFailed):
+		return false, fmt.Errorf("the storage migration job %s failed, see its logs for details", got.Name)
+	}
+	return false, nil
+}
+
+// completeUpgrade returns whether the components of the clusterpedia have been rolled out to
+// spec.version, and marks the upgrade completed if so.
+func (ctrl *ClusterpediaController) completeUpgrade(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) (bool, error) {
+	upgrade := clusterpedia.Status.Upgrade
+	if upgrade == nil || upgrade.Phase != installv1alpha1.ClusterpediaUpgradeRollingOut {
+		return true, nil
+	}
+
+	for _, name := range upgradedDeployments(clusterpedia) {
+		deployment, err := ctrl.client.AppsV1().Deployments(clusterpedia.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if !deploymentRolledOut(deployment) {
+			ctrl.enqueueAfter(clusterpedia, upgradeCheckInterval)
+			return false, nil
+		}
+	}
+
+	if err := ctrl.deleteStorageMigrationJob(ctx, clusterpedia); err != nil {
+		return false, err
+	}
+	now := metav1.Now()
+	upgrade.Phase = installv1alpha1.ClusterpediaUpgradeCompleted
+	upgrade.Message = ""
+	upgrade.CompletionTime = &now
+	return true, nil
+}
+
+// deploymentRolledOut returns whether all replicas of the deployment are updated and available.
+func deploymentRolledOut(deployment *appsv1.Deployment) bool {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false
+	}
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+func (ctrl *ClusterpediaController) deleteStorageMigrationJob(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: storageMigrationJobName, Namespace: clusterpedia.Namespace}}
+	return ctrl.deleteJob(ctx, job)
+}
+
+// updateUpgradePhase records the phase of the upgrade of the clusterpedia.
+func (ctrl *ClusterpediaController) updateUpgradePhase(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia, phase installv1alpha1.ClusterpediaUpgradePhase, message string) error {
+	clusterpedia.Status.Upgrade.Phase = phase
+	clusterpedia.Status.Upgrade.Message = message
+	return ctrl.updateStatus(ctx, clusterpedia)
+}
+
+// updateStatus updates the status of the clusterpedia.
+func (ctrl *ClusterpediaController) updateStatus(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	phaseutil.SetClusterpediaPhase(clusterpedia)
+	updated, err := ctrl.fireflyClient.InstallV1alpha1().Clusterpedias(clusterpedia.Namespace).UpdateStatus(ctx, clusterpedia, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	clusterpedia.ResourceVersion = updated.ResourceVersion
+	return nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package clusterpedia
 
 import (
+	"context"
 	"fmt"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -28,12 +29,12 @@ import (
 	"github.com/carlory/firefly/pkg/constants"
 	"github.com/carlory/firefly/pkg/scheme"
 	"github.com/carlory/firefly/pkg/util"
-	clientutil "github.com/carlory/firefly/pkg/util/client"
 	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
 )
 
-func (ctrl *ClusterpediaController) EnsureControllerManager(clusterpedia *installv1alpha1.Clusterpedia) error {
-	hasProvider, err := ctrl.IsControllPlaneProviderExists(clusterpedia)
+func (ctrl *ClusterpediaController) EnsureControllerManager(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	hasProvider, err := ctrl.IsControllPlaneProviderExists(ctx, clusterpedia)
 	if err != nil {
 		return err
 	}
@@ -42,10 +43,24 @@ func (ctrl *ClusterpediaController) EnsureControllerManager(clusterpedia *instal
 		return fmt.Errorf("unsupported without provider")
 	}
 
-	return ctrl.EnsureControllerManagerDeployment(clusterpedia)
+	return ctrl.EnsureControllerManagerDeployment(ctx, clusterpedia)
 }
 
-func (ctrl *ClusterpediaController) EnsureControllerManagerDeployment(clusterpedia *installv1alpha1.Clusterpedia) error {
+func (ctrl *ClusterpediaController) EnsureControllerManagerDeployment(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(ctx, clusterpedia)
+	if err != nil {
+		return err
+	}
+	deployment, err := controllerManagerDeployment(clusterpedia, kubeconfigSecretName)
+	if err != nil {
+		return err
+	}
+	ctrl.annotateForScraping(clusterpedia, constants.ClusterpediaComponentControllerManager, &deployment.Spec.Template)
+	return ctrl.ensureDeployment(ctx, clusterpedia, deployment)
+}
+
+// controllerManagerDeployment returns the clusterpedia-controller-manager deployment of the clusterpedia.
+func controllerManagerDeployment(clusterpedia *installv1alpha1.Clusterpedia, kubeconfigSecretName string) (*appsv1.Deployment, error) {
 	componentName := constants.ClusterpediaComponentControllerManager
 	manager := clusterpedia.Spec.ControllerManager
 	repository := clusterpedia.Spec.ImageRepository
@@ -62,22 +77,12 @@ func (ctrl *ClusterpediaController) EnsureControllerManagerDeployment(clusterped
 		"leader-elect-resource-namespace": constants.ClusterpediaSystemNamespace,
 		"v":                               "4",
 	}
-	featureGates := maputil.MergeBoolMaps(clusterpedia.Spec.FeatureGates, manager.FeatureGates)
-	for feature, enabled := range featureGates {
-		if defaultArgs["feature-gates"] == "" {
-			defaultArgs["feature-gates"] = fmt.Sprintf("%s=%t", feature, enabled)
-		} else {
-			defaultArgs["feature-gates"] = fmt.Sprintf("%s,%s=%t", defaultArgs["feature-gates"], feature, enabled)
-		}
+	if featureGates := maputil.MergeBoolMaps(clusterpedia.Spec.FeatureGates, manager.FeatureGates); len(featureGates) > 0 {
+		defaultArgs["feature-gates"] = maputil.ConvertToFeatureGates(featureGates)
 	}
-	computedArgs := maputil.MergeStringMaps(defaultArgs, manager.ExtraArgs)
+	computedArgs := maputil.MergeStringMaps(defaultArgs, util.LoggingArgs(manager.Logging), manager.ExtraArgs)
 	args := maputil.ConvertToCommandOrArgs(computedArgs)
 
-	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(clusterpedia)
-	if err != nil {
-		return err
-	}
-
 	deployment := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "apps/v1",
@@ -130,5 +135,8 @@ func (ctrl *ClusterpediaController) EnsureControllerManagerDeployment(clusterped
 		},
 	}
 	controllerutil.SetOwnerReference(clusterpedia, deployment, scheme.Scheme)
-	return clientutil.CreateOrUpdateDeployment(ctrl.client, deployment)
+	if err := patchutil.Apply(deployment, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return deployment, nil
 }
@@ -0,0 +1,42 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpedia
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+)
+
+// reconcileStep reconciles a part of the clusterpedia, e.g. a component.
+type reconcileStep func(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error
+
+// reconcileConcurrently runs the independent steps concurrently and waits for them. Each of the steps
+// works on its own copy of the clusterpedia, so the steps must not update its status. The first error
+// cancels the context of the other steps and is returned.
+func (ctrl *ClusterpediaController) reconcileConcurrently(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia, steps ...reconcileStep) error {
+	g, ctx := errgroup.WithContext(ctx)
+	for _, step := range steps {
+		step, stepClusterpedia := step, clusterpedia.DeepCopy()
+		g.Go(func() error {
+			return step(ctx, stepClusterpedia)
+		})
+	}
+	return g.Wait()
+}
@@ -17,12 +17,15 @@ limitations under the License.
 package clusterpedia
 
 import (
+	"context"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/cli-runtime/pkg/resource"
 
 	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/dryrun"
 	utilresource "github.com/carlory/firefly/pkg/util/resource"
 )
 
@@ -31,8 +34,8 @@ const (
 	userAgentName = "clusterpedia-controller"
 )
 
-func (ctrl *ClusterpediaController) EnsureClusterpediaCRDs(clusterpedia *installv1alpha1.Clusterpedia) error {
-	builder, err := ctrl.NewResourceBuilder(clusterpedia)
+func (ctrl *ClusterpediaController) EnsureClusterpediaCRDs(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	builder, err := ctrl.NewResourceBuilder(ctx, clusterpedia)
 	if err != nil {
 		return err
 	}
@@ -45,7 +48,8 @@ func (ctrl *ClusterpediaController) EnsureClusterpediaCRDs(clusterpedia *install
 		if err != nil {
 			return err
 		}
-		_, err1 := resource.NewHelper(info.Client, info.Mapping).Create(info.Namespace, true, info.Object)
+		_, err1 := resource.NewHelper(info.Client, info.Mapping).DryRun(dryrun.Enabled(ctx)).Create(info.Namespace, true, info.Object)
+		audit.RecordResult(ctx, audit.Create, info.Object, err1)
 		if err1 != nil {
 			if !errors.IsAlreadyExists(err1) {
 				return err1
@@ -55,8 +59,8 @@ func (ctrl *ClusterpediaController) EnsureClusterpediaCRDs(clusterpedia *install
 	})
 }
 
-func (ctrl *ClusterpediaController) RemoveClusterpediaCRDs(clusterpedia *installv1alpha1.Clusterpedia) error {
-	builder, err := ctrl.NewResourceBuilder(clusterpedia)
+func (ctrl *ClusterpediaController) RemoveClusterpediaCRDs(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	builder, err := ctrl.NewResourceBuilder(ctx, clusterpedia)
 	if err != nil {
 		return err
 	}
@@ -69,7 +73,8 @@ func (ctrl *ClusterpediaController) RemoveClusterpediaCRDs(clusterpedia *install
 		if err != nil {
 			return err
 		}
-		_, err1 := resource.NewHelper(info.Client, info.Mapping).Delete(info.Namespace, info.Name)
+		_, err1 := resource.NewHelper(info.Client, info.Mapping).DryRun(dryrun.Enabled(ctx)).Delete(info.Namespace, info.Name)
+		audit.RecordResult(ctx, audit.Delete, info.Object, err1)
 		if err1 != nil {
 			if !errors.IsNotFound(err1) {
 				return err1
@@ -79,8 +84,8 @@ func (ctrl *ClusterpediaController) RemoveClusterpediaCRDs(clusterpedia *install
 	})
 }
 
-func (ctrl *ClusterpediaController) NewResourceBuilder(clusterpedia *installv1alpha1.Clusterpedia) (*resource.Builder, error) {
-	hasProvider, err := ctrl.IsControllPlaneProviderExists(clusterpedia)
+func (ctrl *ClusterpediaController) NewResourceBuilder(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) (*resource.Builder, error) {
+	hasProvider, err := ctrl.IsControllPlaneProviderExists(ctx, clusterpedia)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +94,7 @@ func (ctrl *ClusterpediaController) NewResourceBuilder(clusterpedia *installv1al
 		return nil, fmt.Errorf("unsupported without provider")
 	}
 
-	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(clusterpedia)
+	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(ctx, clusterpedia)
 	if err != nil {
 		return nil, err
 	}
@@ -20,23 +20,29 @@ import (
 	"context"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 
 	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	phaseutil "github.com/carlory/firefly/pkg/util/phase"
 	utilresource "github.com/carlory/firefly/pkg/util/resource"
 )
 
 // IsControllPlaneProviderExists check if the given provider exists.
-func (ctrl *ClusterpediaController) IsControllPlaneProviderExists(clusterpedia *installv1alpha1.Clusterpedia) (bool, error) {
+func (ctrl *ClusterpediaController) IsControllPlaneProviderExists(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) (bool, error) {
 	provider := clusterpedia.Spec.ControlplaneProvider
 	if provider == nil {
 		return false, nil
 	}
 	if provider.Karmada != nil {
 		karmadaName := provider.Karmada.Name
-		karmada, err := ctrl.fireflyClient.InstallV1alpha1().Karmadas(clusterpedia.Namespace).Get(context.TODO(), karmadaName, metav1.GetOptions{})
+		karmada, err := ctrl.fireflyClient.InstallV1alpha1().Karmadas(clusterpedia.Namespace).Get(ctx, karmadaName, metav1.GetOptions{})
 		if err != nil {
 			return false, err
 		}
@@ -49,8 +55,8 @@ func (ctrl *ClusterpediaController) IsControllPlaneProviderExists(clusterpedia *
 }
 
 // GetControlplaneClient returns the client of the controlplane.
-func (ctrl *ClusterpediaController) GetControlplaneClient(clusterpedia *installv1alpha1.Clusterpedia) (kubernetes.Interface, error) {
-	hasProvider, err := ctrl.IsControllPlaneProviderExists(clusterpedia)
+func (ctrl *ClusterpediaController) GetControlplaneClient(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) (kubernetes.Interface, error) {
+	hasProvider, err := ctrl.IsControllPlaneProviderExists(ctx, clusterpedia)
 	if err != nil {
 		return nil, err
 	}
@@ -58,11 +64,11 @@ func (ctrl *ClusterpediaController) GetControlplaneClient(clusterpedia *installv
 	if !hasProvider {
 		return ctrl.client, nil
 	}
-	return ctrl.GetControlplaneClientFromProvider(clusterpedia)
+	return ctrl.GetControlplaneClientFromProvider(ctx, clusterpedia)
 }
 
-func (ctrl *ClusterpediaController) GetControlplaneDynamicClientFromProvider(clusterpedia *installv1alpha1.Clusterpedia) (dynamic.Interface, error) {
-	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(clusterpedia)
+func (ctrl *ClusterpediaController) GetControlplaneDynamicClientFromProvider(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) (dynamic.Interface, error) {
+	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(ctx, clusterpedia)
 	if err != nil {
 		return nil, err
 	}
@@ -74,8 +80,8 @@ func (ctrl *ClusterpediaController) GetControlplaneDynamicClientFromProvider(clu
 }
 
 // GetControlplaneClientFromProvider returns the client of the controlplane according to a provider.
-func (ctrl *ClusterpediaController) GetControlplaneClientFromProvider(clusterpedia *installv1alpha1.Clusterpedia) (kubernetes.Interface, error) {
-	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(clusterpedia)
+func (ctrl *ClusterpediaController) GetControlplaneClientFromProvider(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) (kubernetes.Interface, error) {
+	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(ctx, clusterpedia)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +93,11 @@ func (ctrl *ClusterpediaController) GetControlplaneClientFromProvider(clusterped
 }
 
 // KubeConfigSecretNameFromProvider returns the name of a kubeconfig secret according to the given provider.
-func (ctrl *ClusterpediaController) KubeConfigSecretNameFromProvider(clusterpedia *installv1alpha1.Clusterpedia) (string, error) {
+func (ctrl *ClusterpediaController) KubeConfigSecretNameFromProvider(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) (string, error) {
+	return kubeConfigSecretNameFromProvider(clusterpedia)
+}
+
+func kubeConfigSecretNameFromProvider(clusterpedia *installv1alpha1.Clusterpedia) (string, error) {
 	provider := clusterpedia.Spec.ControlplaneProvider
 	if provider == nil {
 		return "", fmt.Errorf("no provider found")
@@ -97,3 +107,35 @@ func (ctrl *ClusterpediaController) KubeConfigSecretNameFromProvider(clusterpedi
 	}
 	return "", nil
 }
+
+// updateClusterpediaCondition sets the condition of the given type of the clusterpedia to the given
+// one, or removes it if the given one is nil. The status is updated only if it's changed.
+func (ctrl *ClusterpediaController) updateClusterpediaCondition(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia, conditionType string, condition *metav1.Condition) error {
+	oldStatus := clusterpedia.Status.DeepCopy()
+	if condition == nil {
+		meta.RemoveStatusCondition(&clusterpedia.Status.Conditions, conditionType)
+	} else {
+		condition.Type = conditionType
+		condition.ObservedGeneration = clusterpedia.Generation
+		meta.SetStatusCondition(&clusterpedia.Status.Conditions, *condition)
+	}
+	phaseutil.SetClusterpediaPhase(clusterpedia)
+	if equality.Semantic.DeepEqual(oldStatus, &clusterpedia.Status) {
+		return nil
+	}
+	updated, err := ctrl.fireflyClient.InstallV1alpha1().Clusterpedias(clusterpedia.Namespace).UpdateStatus(ctx, clusterpedia, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	clusterpedia.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
+// recordOperationResult emits the event of the create or update of the component of the clusterpedia,
+// and records the desired state of the component for the debugging handler.
+func (ctrl *ClusterpediaController) recordOperationResult(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia, obj runtime.Object, result clientutil.OperationResult) {
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, clusterpedia, obj, result)
+	if key, err := cache.MetaNamespaceKeyFunc(clusterpedia); err == nil {
+		ctrl.debugRecorder.ObserveDesiredState(key, obj)
+	}
+}
@@ -0,0 +1,347 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpedia
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	utilpointer "k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+const (
+	// databasePasswordKey is the key of the database secret which holds the current password.
+	databasePasswordKey = "password"
+	// databaseNewPasswordKey is the key of the database secret which holds the password being rolled
+	// out by a rotation. It's removed once the password of the database user is changed.
+	databaseNewPasswordKey = "new-password"
+
+	// databasePasswordLength is the length of the generated passwords.
+	databasePasswordLength = 32
+	// databasePasswordAlphabet is the alphabet of the generated passwords. Symbols are left out,
+	// so that the passwords can be embedded in SQL statements and shell commands without escaping.
+	databasePasswordAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	// credentialsRotationCheckInterval is the interval at which a clusterpedia is requeued while the
+	// credentials of its database are being rotated, since the rotation jobs are not watched.
+	credentialsRotationCheckInterval = 10 * time.Second
+)
+
+// generateDatabasePassword returns a random password for the built-in database.
+func generateDatabasePassword() (string, error) {
+	password := make([]byte, databasePasswordLength)
+	max := big.NewInt(int64(len(databasePasswordAlphabet)))
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		password[i] = databasePasswordAlphabet[n.Int64()]
+	}
+	return string(password), nil
+}
+
+// databaseSecret returns the secret which holds the password of the built-in database of the clusterpedia.
+func databaseSecret(clusterpedia *installv1alpha1.Clusterpedia, password string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateDatabaseSecretName(clusterpedia),
+			Namespace: clusterpedia.Namespace,
+		},
+		Data: map[string][]byte{
+			databasePasswordKey: []byte(password),
+		},
+	}
+	controllerutil.SetOwnerReference(clusterpedia, secret, scheme.Scheme)
+	if err := patchutil.Apply(secret, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// databaseCredentialsAnnotations returns the annotations of the pod templates of the components which
+// connect to the built-in database, so that they are rolled once the password is rotated.
+func databaseCredentialsAnnotations(clusterpedia *installv1alpha1.Clusterpedia) map[string]string {
+	rotated := clusterpedia.Status.DatabaseCredentialsRotationTime
+	if rotated == nil {
+		return nil
+	}
+	return map[string]string{
+		constants.DatabaseCredentialsRotatedAtAnnotation: rotated.UTC().Format(time.RFC3339),
+	}
+}
+
+// credentialsRotationJobName returns the name of the job which rotates the password of the database component.
+func credentialsRotationJobName(component string) string {
+	return component + "-rotate-credentials"
+}
+
+// credentialsRotationJob returns the job which changes the password of the database user by the script.
+// The script connects to the database with $OLD_PASSWORD and changes the password to $NEW_PASSWORD. It
+// should succeed if the password has been changed already, so that a retried job is harmless.
+func credentialsRotationJob(clusterpedia *installv1alpha1.Clusterpedia, component string, image installv1alpha1.ImageMeta, script string) (*batchv1.Job, error) {
+	secretName := GenerateDatabaseSecretName(clusterpedia)
+	secretKeyRef := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		}
+	}
+
+	job := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      credentialsRotationJobName(component),
+			Namespace: clusterpedia.Namespace,
+			Labels: map[string]string{
+				"app": credentialsRotationJobName(component),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: utilpointer.Int32(3),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app": credentialsRotationJobName(component),
+					},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:            "rotate-credentials",
+							Image:           util.ComponentImageName(image.ImageRepository, image.ImageName, image.ImageTag),
+							ImagePullPolicy: "IfNotPresent",
+							Command:         []string{"/bin/sh", "-c", script},
+							Env: []corev1.EnvVar{
+								{Name: "DATABASE_HOST", Value: component},
+								{Name: "OLD_PASSWORD", ValueFrom: secretKeyRef(databasePasswordKey)},
+								{Name: "NEW_PASSWORD", ValueFrom: secretKeyRef(databaseNewPasswordKey)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	controllerutil.SetOwnerReference(clusterpedia, job, scheme.Scheme)
+	if err := patchutil.Apply(job, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// EnsureDatabaseCredentials ensures the secret which holds the password of the database component
+// exists, with a random password generated at install time, and rotates the password if it's requested
+// by the firefly.io/rotate-database-credentials annotation or due by spec.storage.credentialsRotationPeriod.
+//
+// A rotation stages the new password in the secret, changes the password of the database user by
+// a job, and then replaces the password in the secret. The components which connect to the database
+// are rolled after that, since they are reconciled after the database.
+func (ctrl *ClusterpediaController) EnsureDatabaseCredentials(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia, component string, image installv1alpha1.ImageMeta, rotationScript string) error {
+	name := GenerateDatabaseSecretName(clusterpedia)
+	got, err := ctrl.client.CoreV1().Secrets(clusterpedia.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		password, err := generateDatabasePassword()
+		if err != nil {
+			return err
+		}
+		secret, err := databaseSecret(clusterpedia, password)
+		if err != nil {
+			return err
+		}
+		// the rotation requested before the install is fulfilled by the generated password.
+		if trigger := clusterpedia.Annotations[constants.RotateDatabaseCredentialsAnnotation]; trigger != "" {
+			metav1.SetMetaDataAnnotation(&secret.ObjectMeta, constants.RotateDatabaseCredentialsAnnotation, trigger)
+		}
+		if _, err := ctrl.client.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		audit.Record(ctx, audit.Create, secret, nil)
+		ctrl.recordOperationResult(ctx, clusterpedia, secret, clientutil.OperationResultCreated)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	secret := got.DeepCopy()
+	if _, ok := secret.Data[databaseNewPasswordKey]; ok {
+		return ctrl.completeCredentialsRotation(ctx, clusterpedia, secret, component, image, rotationScript)
+	}
+
+	due, remaining := credentialsRotationDue(clusterpedia, secret)
+	if !due {
+		if remaining > 0 {
+			ctrl.enqueueAfter(clusterpedia, remaining)
+		}
+		return nil
+	}
+
+	password, err := generateDatabasePassword()
+	if err != nil {
+		return err
+	}
+	secret.Data[databaseNewPasswordKey] = []byte(password)
+	if trigger := clusterpedia.Annotations[constants.RotateDatabaseCredentialsAnnotation]; trigger != "" {
+		metav1.SetMetaDataAnnotation(&secret.ObjectMeta, constants.RotateDatabaseCredentialsAnnotation, trigger)
+	}
+	updated, err := ctrl.client.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	audit.RecordUpdate(ctx, got, updated)
+	ctrl.eventRecorder.Eventf(clusterpedia, corev1.EventTypeNormal, "CredentialsRotationStarted", "Rotating the password of %s", component)
+	return ctrl.completeCredentialsRotation(ctx, clusterpedia, updated, component, image, rotationScript)
+}
+
+// credentialsRotationDue returns whether the password held by the secret should be rotated. If it's
+// not due yet but rotated periodically, the time remaining until the next rotation is returned as well.
+func credentialsRotationDue(clusterpedia *installv1alpha1.Clusterpedia, secret *corev1.Secret) (bool, time.Duration) {
+	if trigger := clusterpedia.Annotations[constants.RotateDatabaseCredentialsAnnotation]; trigger != "" &&
+		trigger != secret.Annotations[constants.RotateDatabaseCredentialsAnnotation] {
+		return true, 0
+	}
+
+	period := clusterpedia.Spec.Storage.CredentialsRotationPeriod
+	if period == nil || period.Duration <= 0 {
+		return false, 0
+	}
+	last := secret.CreationTimestamp.Time
+	if rotated := clusterpedia.Status.DatabaseCredentialsRotationTime; rotated != nil && rotated.Time.After(last) {
+		last = rotated.Time
+	}
+	remaining := time.Until(last.Add(period.Duration))
+	return remaining <= 0, remaining
+}
+
+// completeCredentialsRotation runs the job which changes the password of the database user to the
+// staged one, and replaces the password held by the secret once the job is complete.
+func (ctrl *ClusterpediaController) completeCredentialsRotation(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia, secret *corev1.Secret, component string, image installv1alpha1.ImageMeta, rotationScript string) error {
+	job, err := credentialsRotationJob(clusterpedia, component, image, rotationScript)
+	if err != nil {
+		return err
+	}
+	got, err := ctrl.client.BatchV1().Jobs(job.Namespace).Get(ctx, job.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err := ctrl.client.BatchV1().Jobs(job.Namespace).Create(ctx, job, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		audit.Record(ctx, audit.Create, job, nil)
+		ctrl.recordOperationResult(ctx, clusterpedia, job, clientutil.OperationResultCreated)
+		ctrl.enqueueAfter(clusterpedia, credentialsRotationCheckInterval)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case jobConditionTrue(got, batchv1.JobComplete):
+		old := secret.DeepCopy()
+		secret.Data[databasePasswordKey] = secret.Data[databaseNewPasswordKey]
+		delete(secret.Data, databaseNewPasswordKey)
+		updated, err := ctrl.client.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		audit.RecordUpdate(ctx, old, updated)
+
+		now := metav1.Now()
+		clusterpedia.Status.DatabaseCredentialsRotationTime = &now
+		if err := ctrl.updateStatus(ctx, clusterpedia); err != nil {
+			return err
+		}
+
+		if err := ctrl.deleteJob(ctx, got); err != nil {
+			return err
+		}
+		ctrl.eventRecorder.Eventf(clusterpedia, corev1.EventTypeNormal, "CredentialsRotated", "Rotated the password of %s", component)
+		return nil
+	case jobConditionTrue(got, batchv1.JobFailed):
+		// drop the staged password, so that the rotation is started over.
+		old := secret.DeepCopy()
+		delete(secret.Data, databaseNewPasswordKey)
+		updated, err := ctrl.client.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		audit.RecordUpdate(ctx, old, updated)
+		if err := ctrl.deleteJob(ctx, got); err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to rotate the password of %s, see the logs of job %s", component, got.Name)
+	}
+
+	ctrl.enqueueAfter(clusterpedia, credentialsRotationCheckInterval)
+	return nil
+}
+
+// deleteJob deletes the job along with its pods.
+func (ctrl *ClusterpediaController) deleteJob(ctx context.Context, job *batchv1.Job) error {
+	policy := metav1.DeletePropagationBackground
+	err := ctrl.client.BatchV1().Jobs(job.Namespace).Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: &policy})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	audit.RecordResult(ctx, audit.Delete, job, err)
+	return err
+}
+
+// enqueueAfter requeues the clusterpedia after the given duration.
+func (ctrl *ClusterpediaController) enqueueAfter(clusterpedia *installv1alpha1.Clusterpedia, duration time.Duration) {
+	key, err := cache.MetaNamespaceKeyFunc(clusterpedia)
+	if err != nil {
+		return
+	}
+	ctrl.queue.AddAfter(key, duration)
+}
+
+func jobConditionTrue(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
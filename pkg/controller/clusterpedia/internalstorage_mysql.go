@@ -17,6 +17,7 @@ limitations under the License.
 package clusterpedia
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/MakeNowJust/heredoc"
@@ -30,23 +31,46 @@ import (
 	"github.com/carlory/firefly/pkg/constants"
 	"github.com/carlory/firefly/pkg/scheme"
 	clientutil "github.com/carlory/firefly/pkg/util/client"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
 )
 
-func (ctrl *ClusterpediaController) EnsureMySQL(clusterpedia *installv1alpha1.Clusterpedia) error {
-	if err := ctrl.EnsureMySQLService(clusterpedia); err != nil {
+// mysqlRotationScript changes the password of the root user of the clusterpedia-internalstorage-mysql.
+const mysqlRotationScript = `mysql -h "$DATABASE_HOST" -uroot -p"$OLD_PASSWORD" -e "ALTER USER 'root'@'%' IDENTIFIED BY '$NEW_PASSWORD'; ALTER USER 'root'@'localhost' IDENTIFIED BY '$NEW_PASSWORD'" ||
+mysql -h "$DATABASE_HOST" -uroot -p"$NEW_PASSWORD" -e "SELECT 1"`
+
+func (ctrl *ClusterpediaController) EnsureMySQL(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	if clusterpedia.Spec.Storage.MySQL.Local.Operator != nil {
+		return ctrl.EnsureInnoDBCluster(ctx, clusterpedia)
+	}
+	if err := ctrl.EnsureMySQLService(ctx, clusterpedia); err != nil {
+		return err
+	}
+	local := clusterpedia.Spec.Storage.MySQL.Local
+	if err := ctrl.EnsureDatabaseCredentials(ctx, clusterpedia, constants.ClusterpediaComponentInternalStorageMySQL, local.ImageMeta, mysqlRotationScript); err != nil {
 		return err
 	}
-	if err := ctrl.EnsureMySQLSecret(clusterpedia); err != nil {
+	if err := ctrl.EnsureMySQLConfigMap(ctx, clusterpedia); err != nil {
 		return err
 	}
-	if err := ctrl.EnsureMySQLConfigMap(clusterpedia); err != nil {
+	if err := ctrl.EnsureDataVolume(ctx, clusterpedia, constants.ClusterpediaComponentInternalStorageMySQL, local.PersistentStorage); err != nil {
 		return err
 	}
-	return ctrl.EnsureMySQLDeployment(clusterpedia)
+	return ctrl.EnsureMySQLDeployment(ctx, clusterpedia)
 }
 
 // EnsureMySQLService ensures the clusterpedia-internalstorage-mysql service exists.
-func (ctrl *ClusterpediaController) EnsureMySQLService(clusterpedia *installv1alpha1.Clusterpedia) error {
+func (ctrl *ClusterpediaController) EnsureMySQLService(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	svc, err := mysqlService(clusterpedia)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateService(ctx, ctrl.client, svc)
+	ctrl.recordOperationResult(ctx, clusterpedia, svc, result)
+	return err
+}
+
+// mysqlService returns the clusterpedia-internalstorage-mysql service of the clusterpedia.
+func mysqlService(clusterpedia *installv1alpha1.Clusterpedia) (*corev1.Service, error) {
 	componentName := constants.ClusterpediaComponentInternalStorageMySQL
 	svc := &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -77,35 +101,30 @@ func (ctrl *ClusterpediaController) EnsureMySQLService(clusterpedia *installv1al
 		},
 	}
 	controllerutil.SetOwnerReference(clusterpedia, svc, scheme.Scheme)
-	return clientutil.CreateOrUpdateService(ctrl.client, svc)
+	if err := patchutil.Apply(svc, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return svc, nil
 }
 
-// EnsureMySQLSecret ensures the clusterpedia-internalstorage-mysql secret exists.
-func (ctrl *ClusterpediaController) EnsureMySQLSecret(clusterpedia *installv1alpha1.Clusterpedia) error {
-	secret := &corev1.Secret{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: "v1",
-			Kind:       "Secret",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      GenerateDatabaseSecretName(clusterpedia),
-			Namespace: clusterpedia.Namespace,
-		},
-		Data: map[string][]byte{
-			"password": []byte("dangerous0"),
-		},
+// EnsureMySQLConfigMap ensures the clusterpedia-internalstorage-mysql configmap exists.
+func (ctrl *ClusterpediaController) EnsureMySQLConfigMap(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	cm, err := mysqlConfigMap(clusterpedia)
+	if err != nil {
+		return err
 	}
-	controllerutil.SetOwnerReference(clusterpedia, secret, scheme.Scheme)
-	return clientutil.CreateOrUpdateSecret(ctrl.client, secret)
+	result, err := clientutil.CreateOrUpdateConfigMap(ctx, ctrl.client, cm)
+	ctrl.recordOperationResult(ctx, clusterpedia, cm, result)
+	return err
 }
 
-// EnsureMySQLConfigMap ensures the clusterpedia-internalstorage-mysql configmap exists.
-func (ctrl *ClusterpediaController) EnsureMySQLConfigMap(clusterpedia *installv1alpha1.Clusterpedia) error {
+// mysqlConfigMap returns the clusterpedia-internalstorage-mysql configmap of the clusterpedia.
+func mysqlConfigMap(clusterpedia *installv1alpha1.Clusterpedia) (*corev1.ConfigMap, error) {
 	svcName := constants.ClusterpediaComponentInternalStorageMySQL
 	cm := &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
-			Kind:       "Secret",
+			Kind:       "ConfigMap",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      GenerateDatabaseConfigMapName(clusterpedia),
@@ -121,11 +140,25 @@ func (ctrl *ClusterpediaController) EnsureMySQLConfigMap(clusterpedia *installv1
 		},
 	}
 	controllerutil.SetOwnerReference(clusterpedia, cm, scheme.Scheme)
-	return clientutil.CreateOrUpdateConfigMap(ctrl.client, cm)
+	if err := patchutil.Apply(cm, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return cm, nil
 }
 
 // EnsureMySQLDeployment ensures the clusterpedia-internalstorage-mysql deployment exists.
-func (ctrl *ClusterpediaController) EnsureMySQLDeployment(clusterpedia *installv1alpha1.Clusterpedia) error {
+func (ctrl *ClusterpediaController) EnsureMySQLDeployment(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	deployment, err := mysqlDeployment(clusterpedia)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateDeployment(ctx, ctrl.client, deployment)
+	ctrl.recordOperationResult(ctx, clusterpedia, deployment, result)
+	return err
+}
+
+// mysqlDeployment returns the clusterpedia-internalstorage-mysql deployment of the clusterpedia.
+func mysqlDeployment(clusterpedia *installv1alpha1.Clusterpedia) (*appsv1.Deployment, error) {
 	componentName := constants.ClusterpediaComponentInternalStorageMySQL
 	image := clusterpedia.Spec.Storage.MySQL.Local
 
@@ -139,6 +172,7 @@ func (ctrl *ClusterpediaController) EnsureMySQLDeployment(clusterpedia *installv
 			Namespace: clusterpedia.Namespace,
 		},
 		Spec: appsv1.DeploymentSpec{
+			Strategy: dataVolumeStrategy(image.PersistentStorage),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
 					"app":                                  componentName,
@@ -194,10 +228,8 @@ func (ctrl *ClusterpediaController) EnsureMySQLDeployment(clusterpedia *installv
 					},
 					Volumes: []corev1.Volume{
 						{
-							Name: "data",
-							VolumeSource: corev1.VolumeSource{
-								EmptyDir: &corev1.EmptyDirVolumeSource{},
-							},
+							Name:         "data",
+							VolumeSource: dataVolumeSource(componentName, image.PersistentStorage),
 						},
 					},
 				},
@@ -205,5 +237,8 @@ func (ctrl *ClusterpediaController) EnsureMySQLDeployment(clusterpedia *installv
 		},
 	}
 	controllerutil.SetOwnerReference(clusterpedia, deployment, scheme.Scheme)
-	return clientutil.CreateOrUpdateDeployment(ctrl.client, deployment)
+	if err := patchutil.Apply(deployment, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return deployment, nil
 }
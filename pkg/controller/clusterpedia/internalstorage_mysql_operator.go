@@ -0,0 +1,209 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpedia
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/audit"
+	chartutil "github.com/carlory/firefly/pkg/util/chart"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+var (
+	// InnoDBClusterGVK is the kind of the InnoDBClusters of the MySQL Operator for Kubernetes.
+	InnoDBClusterGVK = schema.GroupVersionKind{Group: "mysql.oracle.com", Version: "v2", Kind: "InnoDBCluster"}
+	// InnoDBClusterGVR is the resource of the InnoDBClusters of the MySQL Operator for Kubernetes.
+	InnoDBClusterGVR = schema.GroupVersionResource{Group: "mysql.oracle.com", Version: "v2", Resource: "innodbclusters"}
+)
+
+// mysqlOperatorSecretName returns the name of the secret which holds the root account that the
+// MySQL Operator for Kubernetes creates when the InnoDBCluster is bootstrapped.
+func mysqlOperatorSecretName() string {
+	return constants.ClusterpediaComponentInternalStorageMySQL + "-root"
+}
+
+// mysqlOperatorSecret returns the secret of the root account of the InnoDBCluster.
+func mysqlOperatorSecret(clusterpedia *installv1alpha1.Clusterpedia, password string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mysqlOperatorSecretName(),
+			Namespace: clusterpedia.Namespace,
+		},
+		StringData: map[string]string{
+			"rootUser":     "root",
+			"rootHost":     "%",
+			"rootPassword": password,
+		},
+	}
+	controllerutil.SetOwnerReference(clusterpedia, secret, scheme.Scheme)
+	if err := patchutil.Apply(secret, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// innoDBCluster returns the InnoDBCluster of the clusterpedia. The operator exposes it by the
+// clusterpedia-internalstorage-mysql service, whose port 3306 is routed to the primary.
+func innoDBCluster(clusterpedia *installv1alpha1.Clusterpedia) (*unstructured.Unstructured, error) {
+	local := clusterpedia.Spec.Storage.MySQL.Local
+	operator := local.Operator
+
+	spec := map[string]interface{}{
+		"secretName":       mysqlOperatorSecretName(),
+		"tlsUseSelfSigned": true,
+	}
+	if operator.Instances != nil {
+		spec["instances"] = int64(*operator.Instances)
+	}
+	if operator.Routers != nil {
+		spec["router"] = map[string]interface{}{"instances": int64(*operator.Routers)}
+	}
+	if operator.Version != "" {
+		spec["version"] = operator.Version
+	}
+	if local.Size != nil {
+		claim := map[string]interface{}{
+			"accessModes": []interface{}{string(corev1.ReadWriteOnce)},
+			"resources": map[string]interface{}{
+				"requests": map[string]interface{}{"storage": local.Size.String()},
+			},
+		}
+		if local.StorageClassName != nil {
+			claim["storageClassName"] = *local.StorageClassName
+		}
+		spec["datadirVolumeClaimTemplate"] = claim
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetGroupVersionKind(InnoDBClusterGVK)
+	obj.SetNamespace(clusterpedia.Namespace)
+	obj.SetName(constants.ClusterpediaComponentInternalStorageMySQL)
+	if err := patchutil.Apply(obj, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// EnsureInnoDBCluster delegates the mysql of the clusterpedia to the MySQL Operator for Kubernetes.
+func (ctrl *ClusterpediaController) EnsureInnoDBCluster(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	if !ctrl.innoDBClustersAvailable {
+		return fmt.Errorf("the MySQL Operator for Kubernetes is not installed on the host cluster, %s is not found", InnoDBClusterGVR.GroupResource())
+	}
+
+	// the single mysql pod is replaced by the InnoDBCluster, whose service has the same name.
+	if err := ctrl.removeSingleMySQL(ctx, clusterpedia); err != nil {
+		return err
+	}
+
+	local := clusterpedia.Spec.Storage.MySQL.Local
+	if err := ctrl.EnsureDatabaseCredentials(ctx, clusterpedia, constants.ClusterpediaComponentInternalStorageMySQL, local.ImageMeta, mysqlRotationScript); err != nil {
+		return err
+	}
+	if err := ctrl.ensureMySQLOperatorSecret(ctx, clusterpedia); err != nil {
+		return err
+	}
+	if err := ctrl.EnsureMySQLConfigMap(ctx, clusterpedia); err != nil {
+		return err
+	}
+
+	cluster, err := innoDBCluster(clusterpedia)
+	if err != nil {
+		return err
+	}
+	return chartutil.Apply(ctx, ctrl.applier, clusterpedia, installv1alpha1.SchemeGroupVersion.WithKind("Clusterpedia"), []*unstructured.Unstructured{cluster})
+}
+
+// ensureMySQLOperatorSecret creates the secret of the root account of the InnoDBCluster with the
+// password of the database secret. The operator only reads it when the InnoDBCluster is bootstrapped,
+// so it's never updated, and the password is rotated by the credentials rotation.
+func (ctrl *ClusterpediaController) ensureMySQLOperatorSecret(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	_, err := ctrl.client.CoreV1().Secrets(clusterpedia.Namespace).Get(ctx, mysqlOperatorSecretName(), metav1.GetOptions{})
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	dbSecret, err := ctrl.client.CoreV1().Secrets(clusterpedia.Namespace).Get(ctx, GenerateDatabaseSecretName(clusterpedia), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	secret, err := mysqlOperatorSecret(clusterpedia, string(dbSecret.Data[databasePasswordKey]))
+	if err != nil {
+		return err
+	}
+	if _, err := ctrl.client.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.Create, secret, nil)
+	ctrl.recordOperationResult(ctx, clusterpedia, secret, clientutil.OperationResultCreated)
+	return nil
+}
+
+// removeSingleMySQL deletes the deployment and the service of the single mysql pod, if any.
+func (ctrl *ClusterpediaController) removeSingleMySQL(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	name := constants.ClusterpediaComponentInternalStorageMySQL
+	err := ctrl.client.AppsV1().Deployments(clusterpedia.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	audit.RecordResult(ctx, audit.Delete, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: clusterpedia.Namespace}}, err)
+
+	// the service created by the operator is kept.
+	svc, err := ctrl.client.CoreV1().Services(clusterpedia.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !ownedBy(svc, clusterpedia) {
+		return nil
+	}
+	err = ctrl.client.CoreV1().Services(clusterpedia.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	audit.RecordResult(ctx, audit.Delete, svc, err)
+	return err
+}
+
+// ownedBy returns whether the object has an owner reference to the owner.
+func ownedBy(obj, owner metav1.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return false
+}
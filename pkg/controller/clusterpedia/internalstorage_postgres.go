@@ -17,6 +17,7 @@ limitations under the License.
 package clusterpedia
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/MakeNowJust/heredoc"
@@ -30,23 +31,43 @@ import (
 	"github.com/carlory/firefly/pkg/constants"
 	"github.com/carlory/firefly/pkg/scheme"
 	clientutil "github.com/carlory/firefly/pkg/util/client"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
 )
 
-func (ctrl *ClusterpediaController) EnsurePostgres(clusterpedia *installv1alpha1.Clusterpedia) error {
-	if err := ctrl.EnsurePostgresService(clusterpedia); err != nil {
+// postgresRotationScript changes the password of the postgres user of the clusterpedia-internalstorage-postgres.
+const postgresRotationScript = `PGPASSWORD="$OLD_PASSWORD" psql -h "$DATABASE_HOST" -U postgres -d clusterpedia -v ON_ERROR_STOP=1 -c "ALTER USER postgres WITH PASSWORD '$NEW_PASSWORD'" ||
+PGPASSWORD="$NEW_PASSWORD" psql -h "$DATABASE_HOST" -U postgres -d clusterpedia -v ON_ERROR_STOP=1 -c "SELECT 1"`
+
+func (ctrl *ClusterpediaController) EnsurePostgres(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	if err := ctrl.EnsurePostgresService(ctx, clusterpedia); err != nil {
+		return err
+	}
+	local := clusterpedia.Spec.Storage.Postgres.Local
+	if err := ctrl.EnsureDatabaseCredentials(ctx, clusterpedia, constants.ClusterpediaComponentInternalStoragePostgres, local.ImageMeta, postgresRotationScript); err != nil {
 		return err
 	}
-	if err := ctrl.EnsurePostgresSecret(clusterpedia); err != nil {
+	if err := ctrl.EnsurePostgresConfigMap(ctx, clusterpedia); err != nil {
 		return err
 	}
-	if err := ctrl.EnsurePostgresConfigMap(clusterpedia); err != nil {
+	if err := ctrl.EnsureDataVolume(ctx, clusterpedia, constants.ClusterpediaComponentInternalStoragePostgres, local.PersistentStorage); err != nil {
 		return err
 	}
-	return ctrl.EnsurePostgresDeployment(clusterpedia)
+	return ctrl.EnsurePostgresDeployment(ctx, clusterpedia)
 }
 
 // EnsurePostgresService ensures the clusterpedia-internalstorage-postgres service exists.
-func (ctrl *ClusterpediaController) EnsurePostgresService(clusterpedia *installv1alpha1.Clusterpedia) error {
+func (ctrl *ClusterpediaController) EnsurePostgresService(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	svc, err := postgresService(clusterpedia)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateService(ctx, ctrl.client, svc)
+	ctrl.recordOperationResult(ctx, clusterpedia, svc, result)
+	return err
+}
+
+// postgresService returns the clusterpedia-internalstorage-postgres service of the clusterpedia.
+func postgresService(clusterpedia *installv1alpha1.Clusterpedia) (*corev1.Service, error) {
 	componentName := constants.ClusterpediaComponentInternalStoragePostgres
 	svc := &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -77,35 +98,30 @@ func (ctrl *ClusterpediaController) EnsurePostgresService(clusterpedia *installv
 		},
 	}
 	controllerutil.SetOwnerReference(clusterpedia, svc, scheme.Scheme)
-	return clientutil.CreateOrUpdateService(ctrl.client, svc)
+	if err := patchutil.Apply(svc, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return svc, nil
 }
 
-// EnsurePostgresSecret ensures the clusterpedia-internalstorage-postgres secret exists.
-func (ctrl *ClusterpediaController) EnsurePostgresSecret(clusterpedia *installv1alpha1.Clusterpedia) error {
-	secret := &corev1.Secret{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: "v1",
-			Kind:       "Secret",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      GenerateDatabaseSecretName(clusterpedia),
-			Namespace: clusterpedia.Namespace,
-		},
-		Data: map[string][]byte{
-			"password": []byte("dangerous0"),
-		},
+// EnsurePostgresConfigMap ensures the clusterpedia-internalstorage-postgres configmap exists.
+func (ctrl *ClusterpediaController) EnsurePostgresConfigMap(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	cm, err := postgresConfigMap(clusterpedia)
+	if err != nil {
+		return err
 	}
-	controllerutil.SetOwnerReference(clusterpedia, secret, scheme.Scheme)
-	return clientutil.CreateOrUpdateSecret(ctrl.client, secret)
+	result, err := clientutil.CreateOrUpdateConfigMap(ctx, ctrl.client, cm)
+	ctrl.recordOperationResult(ctx, clusterpedia, cm, result)
+	return err
 }
 
-// EnsurePostgresConfigMap ensures the clusterpedia-internalstorage-postgres configmap exists.
-func (ctrl *ClusterpediaController) EnsurePostgresConfigMap(clusterpedia *installv1alpha1.Clusterpedia) error {
+// postgresConfigMap returns the clusterpedia-internalstorage-postgres configmap of the clusterpedia.
+func postgresConfigMap(clusterpedia *installv1alpha1.Clusterpedia) (*corev1.ConfigMap, error) {
 	svcName := constants.ClusterpediaComponentInternalStoragePostgres
 	cm := &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
-			Kind:       "Secret",
+			Kind:       "ConfigMap",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      GenerateDatabaseConfigMapName(clusterpedia),
@@ -121,11 +137,25 @@ func (ctrl *ClusterpediaController) EnsurePostgresConfigMap(clusterpedia *instal
 		},
 	}
 	controllerutil.SetOwnerReference(clusterpedia, cm, scheme.Scheme)
-	return clientutil.CreateOrUpdateConfigMap(ctrl.client, cm)
+	if err := patchutil.Apply(cm, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return cm, nil
 }
 
 // EnsurePostgresDeployment ensures the clusterpedia-internalstorage-postgres deployment exists.
-func (ctrl *ClusterpediaController) EnsurePostgresDeployment(clusterpedia *installv1alpha1.Clusterpedia) error {
+func (ctrl *ClusterpediaController) EnsurePostgresDeployment(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	deployment, err := postgresDeployment(clusterpedia)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateDeployment(ctx, ctrl.client, deployment)
+	ctrl.recordOperationResult(ctx, clusterpedia, deployment, result)
+	return err
+}
+
+// postgresDeployment returns the clusterpedia-internalstorage-postgres deployment of the clusterpedia.
+func postgresDeployment(clusterpedia *installv1alpha1.Clusterpedia) (*appsv1.Deployment, error) {
 	componentName := constants.ClusterpediaComponentInternalStoragePostgres
 	image := clusterpedia.Spec.Storage.Postgres.Local
 
@@ -139,6 +169,7 @@ func (ctrl *ClusterpediaController) EnsurePostgresDeployment(clusterpedia *insta
 			Namespace: clusterpedia.Namespace,
 		},
 		Spec: appsv1.DeploymentSpec{
+			Strategy: dataVolumeStrategy(image.PersistentStorage),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
 					"app":                                  componentName,
@@ -191,10 +222,8 @@ func (ctrl *ClusterpediaController) EnsurePostgresDeployment(clusterpedia *insta
 					},
 					Volumes: []corev1.Volume{
 						{
-							Name: "data",
-							VolumeSource: corev1.VolumeSource{
-								EmptyDir: &corev1.EmptyDirVolumeSource{},
-							},
+							Name:         "data",
+							VolumeSource: dataVolumeSource(componentName, image.PersistentStorage),
 						},
 					},
 				},
@@ -202,5 +231,8 @@ func (ctrl *ClusterpediaController) EnsurePostgresDeployment(clusterpedia *insta
 		},
 	}
 	controllerutil.SetOwnerReference(clusterpedia, deployment, scheme.Scheme)
-	return clientutil.CreateOrUpdateDeployment(ctrl.client, deployment)
+	if err := patchutil.Apply(deployment, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return deployment, nil
 }
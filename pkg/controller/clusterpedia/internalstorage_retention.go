@@ -0,0 +1,201 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpedia
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	utilpointer "k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+const (
+	// defaultRetentionSchedule is the schedule of the cleanups if spec.retention.schedule is empty.
+	defaultRetentionSchedule = "0 * * * *"
+
+	// postgresRetentionScript runs the cleanup statement against the clusterpedia-internalstorage-postgres.
+	postgresRetentionScript = `PGPASSWORD="$DB_PASSWORD" psql -h "$DATABASE_HOST" -U postgres -d clusterpedia -v ON_ERROR_STOP=1 -c "$RETENTION_SQL"`
+
+	// mysqlRetentionScript runs the cleanup statement against the clusterpedia-internalstorage-mysql.
+	mysqlRetentionScript = `mysql -h "$DATABASE_HOST" -uroot -p"$DB_PASSWORD" clusterpedia -e "$RETENTION_SQL"`
+)
+
+// retentionCronJobName returns the name of the CronJob which cleans up the database component.
+func retentionCronJobName(component string) string {
+	return component + "-retention"
+}
+
+// EnsureStorageRetention applies the CronJob which deletes the objects deleted from the member clusters
+// from the built-in database once they are older than spec.retention.deletedObjectRetention, otherwise
+// deletes it.
+func (ctrl *ClusterpediaController) EnsureStorageRetention(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	if clusterpedia.Spec.Retention == nil {
+		for _, component := range []string{constants.ClusterpediaComponentInternalStoragePostgres, constants.ClusterpediaComponentInternalStorageMySQL} {
+			name := retentionCronJobName(component)
+			err := ctrl.client.BatchV1().CronJobs(clusterpedia.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+			audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "CronJob", Namespace: clusterpedia.Namespace, Name: name}, err)
+			if err := client.IgnoreNotFound(err); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cronJob, err := storageRetentionCronJob(clusterpedia)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateCronJob(ctx, ctrl.client, cronJob)
+	ctrl.recordOperationResult(ctx, clusterpedia, cronJob, result)
+	return err
+}
+
+// storageRetentionCronJob returns the CronJob cleaning up the built-in database of the clusterpedia,
+// which must have spec.retention set.
+func storageRetentionCronJob(clusterpedia *installv1alpha1.Clusterpedia) (*batchv1.CronJob, error) {
+	storage := clusterpedia.Spec.Storage
+	var component, script string
+	var image installv1alpha1.ImageMeta
+	switch {
+	case storage.Postgres != nil && storage.Postgres.Local != nil:
+		component, script, image = constants.ClusterpediaComponentInternalStoragePostgres, postgresRetentionScript, storage.Postgres.Local.ImageMeta
+	case storage.MySQL != nil && storage.MySQL.Local != nil:
+		component, script, image = constants.ClusterpediaComponentInternalStorageMySQL, mysqlRetentionScript, storage.MySQL.Local.ImageMeta
+	default:
+		return nil, fmt.Errorf("unknown storage type")
+	}
+
+	sql, err := retentionSQL(clusterpedia.Spec.Retention, component == constants.ClusterpediaComponentInternalStorageMySQL)
+	if err != nil {
+		return nil, err
+	}
+	return retentionCronJob(clusterpedia, component, image, script, sql)
+}
+
+// retentionSQL returns the statement deleting the objects of the resources which have been deleted from
+// the member clusters for longer than the retention. The resources are validated before they are quoted
+// into the statement, so that it can't be injected by the spec.
+func retentionSQL(retention *installv1alpha1.ClusterpediaRetention, mysql bool) (string, error) {
+	seconds := int64(retention.DeletedObjectRetention.Seconds())
+	if seconds <= 0 {
+		return "", fmt.Errorf("invalid spec.retention.deletedObjectRetention %q: must be positive", retention.DeletedObjectRetention.Duration)
+	}
+
+	// group is a reserved word of both databases.
+	group, expired := `"group"`, fmt.Sprintf("deleted_at < NOW() - INTERVAL '%d seconds'", seconds)
+	if mysql {
+		group, expired = "`group`", fmt.Sprintf("deleted_at < NOW() - INTERVAL %d SECOND", seconds)
+	}
+
+	var resources []string
+	for _, resource := range retention.Resources {
+		if errs := validation.IsDNS1123Subdomain(resource); len(errs) > 0 {
+			return "", fmt.Errorf("invalid resource %q of spec.retention.resources: %s", resource, strings.Join(errs, ", "))
+		}
+		gr := schema.ParseGroupResource(resource)
+		resources = append(resources, fmt.Sprintf("(resource = '%s' AND %s = '%s')", gr.Resource, group, gr.Group))
+	}
+
+	sql := "DELETE FROM resources WHERE deleted_at IS NOT NULL AND " + expired
+	if len(resources) > 0 {
+		sql += " AND (" + strings.Join(resources, " OR ") + ")"
+	}
+	return sql, nil
+}
+
+// retentionCronJob returns the CronJob which runs the cleanup statement against the database component
+// by the script, which connects to the database with $DB_PASSWORD and runs $RETENTION_SQL.
+func retentionCronJob(clusterpedia *installv1alpha1.Clusterpedia, component string, image installv1alpha1.ImageMeta, script, sql string) (*batchv1.CronJob, error) {
+	name := retentionCronJobName(component)
+	schedule := clusterpedia.Spec.Retention.Schedule
+	if schedule == "" {
+		schedule = defaultRetentionSchedule
+	}
+
+	cronJob := &batchv1.CronJob{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "CronJob",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: clusterpedia.Namespace,
+			Labels:    map[string]string{"app": name},
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          schedule,
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: utilpointer.Int32(2),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{"app": name},
+						},
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyOnFailure,
+							Containers: []corev1.Container{
+								{
+									Name:            "retention",
+									Image:           util.ComponentImageName(image.ImageRepository, image.ImageName, image.ImageTag),
+									ImagePullPolicy: "IfNotPresent",
+									Command:         []string{"/bin/sh", "-c", script},
+									Env: []corev1.EnvVar{
+										{Name: "DATABASE_HOST", Value: component},
+										{Name: "RETENTION_SQL", Value: sql},
+										{
+											Name: "DB_PASSWORD",
+											ValueFrom: &corev1.EnvVarSource{
+												SecretKeyRef: &corev1.SecretKeySelector{
+													LocalObjectReference: corev1.LocalObjectReference{
+														Name: GenerateDatabaseSecretName(clusterpedia),
+													},
+													Key: databasePasswordKey,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	controllerutil.SetOwnerReference(clusterpedia, cronJob, scheme.Scheme)
+	if err := patchutil.Apply(cronJob, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return cronJob, nil
+}
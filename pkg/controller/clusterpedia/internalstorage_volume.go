@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpedia
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+// dataVolumeResizeCheckInterval is the interval at which a clusterpedia is requeued while the
+// PersistentVolumeClaim of its database is being expanded, since the claims are not watched.
+const dataVolumeResizeCheckInterval = 30 * time.Second
+
+// dataVolumeName returns the name of the PersistentVolumeClaim which keeps the data of the database component.
+func dataVolumeName(component string) string {
+	return component + "-data"
+}
+
+// dataVolumeClaim returns the PersistentVolumeClaim which keeps the data of the database component.
+// It returns nil if the data is kept by an emptyDir.
+func dataVolumeClaim(clusterpedia *installv1alpha1.Clusterpedia, component string, storage installv1alpha1.PersistentStorage) (*corev1.PersistentVolumeClaim, error) {
+	if storage.Size == nil {
+		return nil, nil
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "PersistentVolumeClaim",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dataVolumeName(component),
+			Namespace: clusterpedia.Namespace,
+			Labels: map[string]string{
+				"app": component,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: storage.StorageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: *storage.Size,
+				},
+			},
+		},
+	}
+	controllerutil.SetOwnerReference(clusterpedia, pvc, scheme.Scheme)
+	if err := patchutil.Apply(pvc, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return pvc, nil
+}
+
+// dataVolumeSource returns the source of the volume which keeps the data of the database component.
+func dataVolumeSource(component string, storage installv1alpha1.PersistentStorage) corev1.VolumeSource {
+	if storage.Size == nil {
+		return corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+	}
+	return corev1.VolumeSource{
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: dataVolumeName(component),
+		},
+	}
+}
+
+// EnsureDataVolume ensures the PersistentVolumeClaim of the database component exists, and expands
+// it online if the requested size is increased. The progress of the expansion is reported by the
+// StorageResized condition of the clusterpedia.
+func (ctrl *ClusterpediaController) EnsureDataVolume(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia, component string, storage installv1alpha1.PersistentStorage) error {
+	pvc, err := dataVolumeClaim(clusterpedia, component, storage)
+	if err != nil {
+		return err
+	}
+	if pvc == nil {
+		return ctrl.updateClusterpediaCondition(ctx, clusterpedia, installv1alpha1.ClusterpediaConditionStorageResized, nil)
+	}
+
+	got, err := ctrl.client.CoreV1().PersistentVolumeClaims(pvc.Namespace).Get(ctx, pvc.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = ctrl.client.CoreV1().PersistentVolumeClaims(pvc.Namespace).Create(ctx, pvc, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+		audit.Record(ctx, audit.Create, pvc, nil)
+		ctrl.recordOperationResult(ctx, clusterpedia, pvc, clientutil.OperationResultCreated)
+		return ctrl.updateClusterpediaCondition(ctx, clusterpedia, installv1alpha1.ClusterpediaConditionStorageResized, &metav1.Condition{
+			Status:  metav1.ConditionTrue,
+			Reason:  "Resized",
+			Message: fmt.Sprintf("the storage is provisioned with %s", storage.Size),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	condition, err := ctrl.resizeDataVolume(ctx, clusterpedia, got, *storage.Size)
+	if err != nil {
+		return err
+	}
+	if condition.Reason == "Resizing" {
+		ctrl.enqueueAfter(clusterpedia, dataVolumeResizeCheckInterval)
+	}
+	return ctrl.updateClusterpediaCondition(ctx, clusterpedia, installv1alpha1.ClusterpediaConditionStorageResized, condition)
+}
+
+// resizeDataVolume expands the PersistentVolumeClaim to the requested size if it's larger and the
+// storage class allows volume expansion. It returns the StorageResized condition of the claim.
+func (ctrl *ClusterpediaController) resizeDataVolume(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia, pvc *corev1.PersistentVolumeClaim, size resource.Quantity) (*metav1.Condition, error) {
+	current := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	switch size.Cmp(current) {
+	case -1:
+		return &metav1.Condition{
+			Status:  metav1.ConditionFalse,
+			Reason:  "ShrinkNotSupported",
+			Message: fmt.Sprintf("the storage can't be shrunk from %s to %s", current.String(), size.String()),
+		}, nil
+	case 1:
+		className := ""
+		if pvc.Spec.StorageClassName != nil {
+			className = *pvc.Spec.StorageClassName
+		}
+		if className == "" {
+			return &metav1.Condition{
+				Status:  metav1.ConditionFalse,
+				Reason:  "ExpansionNotSupported",
+				Message: fmt.Sprintf("the storage can't be expanded to %s, because it has no storage class", size.String()),
+			}, nil
+		}
+		class, err := ctrl.client.StorageV1().StorageClasses().Get(ctx, className, metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		if class == nil || class.AllowVolumeExpansion == nil || !*class.AllowVolumeExpansion {
+			return &metav1.Condition{
+				Status:  metav1.ConditionFalse,
+				Reason:  "ExpansionNotSupported",
+				Message: fmt.Sprintf("the storage can't be expanded to %s, because storage class %s doesn't allow volume expansion", size.String(), className),
+			}, nil
+		}
+
+		expanded := pvc.DeepCopy()
+		if expanded.Spec.Resources.Requests == nil {
+			expanded.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		expanded.Spec.Resources.Requests[corev1.ResourceStorage] = size
+		updated, err := ctrl.client.CoreV1().PersistentVolumeClaims(pvc.Namespace).Update(ctx, expanded, metav1.UpdateOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if audit.RecordUpdate(ctx, pvc, updated) {
+			ctrl.recordOperationResult(ctx, clusterpedia, updated, clientutil.OperationResultUpdated)
+		}
+		pvc = updated
+	}
+
+	capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+	if ok && capacity.Cmp(size) < 0 {
+		return &metav1.Condition{
+			Status:  metav1.ConditionFalse,
+			Reason:  "Resizing",
+			Message: fmt.Sprintf("the storage is being expanded from %s to %s", capacity.String(), size.String()),
+		}, nil
+	}
+	return &metav1.Condition{
+		Status:  metav1.ConditionTrue,
+		Reason:  "Resized",
+		Message: fmt.Sprintf("the storage is provisioned with %s", size.String()),
+	}, nil
+}
+
+// dataVolumeStrategy returns the strategy of the deployment of the database component. The old pod
+// is deleted before the new one is created if the data is kept by a ReadWriteOnce claim.
+func dataVolumeStrategy(storage installv1alpha1.PersistentStorage) appsv1.DeploymentStrategy {
+	if storage.Size == nil {
+		return appsv1.DeploymentStrategy{}
+	}
+	return appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+}
@@ -15,3 +15,423 @@ limitations under the License.
 */
 
 package clusterpedia
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+	"k8s.io/klog/v2"
+	netutils "k8s.io/utils/net"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/certs"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+	utilresource "github.com/carlory/firefly/pkg/util/resource"
+)
+
+const (
+	// readOnlyUserName is the user of the read-only kubeconfig of the clusterpedia.
+	readOnlyUserName = "firefly:clusterpedia-readonly"
+
+	// certRenewBefore is how long before the expiration the certificates issued for the access to the
+	// clusterpedia-apiserver are renewed.
+	certRenewBefore = 30 * 24 * time.Hour
+
+	// apiServerCertMountPath is the directory the serving certificate of the clusterpedia-apiserver is mounted at.
+	apiServerCertMountPath = "/etc/clusterpedia/pki"
+)
+
+// ReadOnlyKubeconfigSecretName returns the name of the Secret holding the read-only kubeconfig of the clusterpedia.
+func ReadOnlyKubeconfigSecretName(clusterpedia *installv1alpha1.Clusterpedia) string {
+	return util.ComponentName("clusterpedia-readonly-kubeconfig", clusterpedia.Name)
+}
+
+// apiServerCertSecretName returns the name of the Secret holding the serving certificate of the clusterpedia-apiserver.
+func apiServerCertSecretName() string {
+	return fmt.Sprintf("%s-cert", constants.ClusterpediaComponentAPIServer)
+}
+
+// EnsureAPIServerAccess exposes the clusterpedia-apiserver through the Ingress if required, issues its serving
+// certificate for the addresses it's exposed at, and publishes the read-only kubeconfig if required. The
+// resources are deleted once spec.access is unset.
+func (ctrl *ClusterpediaController) EnsureAPIServerAccess(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	if err := ctrl.EnsureAPIServerIngress(ctx, clusterpedia); err != nil {
+		return err
+	}
+
+	access := clusterpedia.Spec.Access
+	if access == nil {
+		for _, name := range []string{apiServerCertSecretName(), ReadOnlyKubeconfigSecretName(clusterpedia)} {
+			err := ctrl.client.CoreV1().Secrets(clusterpedia.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+			audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Secret", Namespace: clusterpedia.Namespace, Name: name}, err)
+			if err := client.IgnoreNotFound(err); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// the certificates are signed by the karmada CA, which the clusterpedia-apiserver trusts the client
+	// certificates of through the delegated authentication.
+	certSecret, err := ctrl.client.CoreV1().Secrets(clusterpedia.Namespace).Get(ctx, "karmada-cert", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	caCert, caKey, err := certs.ParseCA(certSecret.Data["ca.crt"], certSecret.Data["ca.key"])
+	if err != nil {
+		return fmt.Errorf("invalid karmada CA: %v", err)
+	}
+
+	addresses, err := ctrl.apiServerExternalAddresses(ctx, clusterpedia)
+	if err != nil {
+		return err
+	}
+	if err := ctrl.EnsureAPIServerCert(ctx, clusterpedia, certSecret.Data["ca.crt"], caCert, caKey, addresses); err != nil {
+		return err
+	}
+
+	if !access.ReadOnlyKubeconfig {
+		name := ReadOnlyKubeconfigSecretName(clusterpedia)
+		err := ctrl.client.CoreV1().Secrets(clusterpedia.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Secret", Namespace: clusterpedia.Namespace, Name: name}, err)
+		return client.IgnoreNotFound(err)
+	}
+	if len(addresses) == 0 && access.ServiceType == corev1.ServiceTypeLoadBalancer {
+		// the kubeconfig is published at the next resync once the load balancer is provisioned.
+		klog.V(4).InfoS("Waiting for the load balancer to be provisioned", "clusterpedia", klog.KObj(clusterpedia))
+		return nil
+	}
+	server := fmt.Sprintf("https://%s.%s.svc:443", constants.ClusterpediaComponentAPIServer, clusterpedia.Namespace)
+	if len(addresses) > 0 {
+		server = fmt.Sprintf("https://%s", addresses[0])
+		if access.Ingress == nil {
+			server = fmt.Sprintf("https://%s", net.JoinHostPort(addresses[0], "443"))
+		}
+	}
+	return ctrl.EnsureReadOnlyKubeconfigSecret(ctx, clusterpedia, certSecret.Data["ca.crt"], caCert, caKey, server)
+}
+
+// apiServerExternalAddresses returns the addresses the clusterpedia-apiserver is exposed at, the hostname
+// of the Ingress or the addresses of the load balancer, which are empty until it's provisioned.
+func (ctrl *ClusterpediaController) apiServerExternalAddresses(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) ([]string, error) {
+	access := clusterpedia.Spec.Access
+	if access.Ingress != nil {
+		return []string{access.Ingress.Hostname}, nil
+	}
+	if access.ServiceType != corev1.ServiceTypeLoadBalancer {
+		return nil, nil
+	}
+	svc, err := ctrl.client.CoreV1().Services(clusterpedia.Namespace).Get(ctx, constants.ClusterpediaComponentAPIServer, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var addresses []string
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			addresses = append(addresses, ingress.IP)
+		}
+		if ingress.Hostname != "" {
+			addresses = append(addresses, ingress.Hostname)
+		}
+	}
+	return addresses, nil
+}
+
+// EnsureAPIServerCert issues the serving certificate of the clusterpedia-apiserver with the karmada CA for
+// its service and the given addresses. The certificate is reused until it's going to expire, the CA is
+// rotated or it doesn't cover the hosts. The clusterpedia-apiserver reloads it from the mounted Secret.
+func (ctrl *ClusterpediaController) EnsureAPIServerCert(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia, caData []byte, caCert *x509.Certificate, caKey crypto.Signer, addresses []string) error {
+	componentName := constants.ClusterpediaComponentAPIServer
+	hosts := append([]string{
+		componentName,
+		fmt.Sprintf("%s.%s", componentName, clusterpedia.Namespace),
+		fmt.Sprintf("%s.%s.svc", componentName, clusterpedia.Namespace),
+	}, addresses...)
+
+	secretName := apiServerCertSecretName()
+	secret, err := ctrl.client.CoreV1().Secrets(clusterpedia.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err == nil && reusableCert(secret.Data["tls.crt"], secret.Data["ca.crt"], caData, caCert, hosts) {
+		return nil
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	var altNames certutil.AltNames
+	for _, host := range hosts {
+		if ip := netutils.ParseIPSloppy(host); ip != nil {
+			altNames.IPs = append(altNames.IPs, ip)
+		} else {
+			altNames.DNSNames = append(altNames.DNSNames, host)
+		}
+	}
+	certData, keyData, err := newCert(caCert, caKey, componentName, altNames)
+	if err != nil {
+		return err
+	}
+
+	secret = &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: clusterpedia.Namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"tls.crt": certData,
+			"tls.key": keyData,
+			"ca.crt":  caData,
+		},
+	}
+	controllerutil.SetOwnerReference(clusterpedia, secret, scheme.Scheme)
+	result, err := clientutil.CreateOrUpdateSecret(ctx, ctrl.client, secret)
+	ctrl.recordOperationResult(ctx, clusterpedia, secret, result)
+	if err != nil {
+		return err
+	}
+	ctrl.eventRecorder.Eventf(clusterpedia, corev1.EventTypeNormal, "CertificateRotated", "Issued a new serving certificate for %s", componentName)
+	return nil
+}
+
+// EnsureReadOnlyKubeconfigSecret publishes a kubeconfig whose user is only allowed to read the resources of the
+// clusterpedia aggregated api. The client certificate is reused until it's going to expire or the CA is rotated.
+func (ctrl *ClusterpediaController) EnsureReadOnlyKubeconfigSecret(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia, caData []byte, caCert *x509.Certificate, caKey crypto.Signer, server string) error {
+	if err := ctrl.EnsureReadOnlyRBAC(ctx, clusterpedia); err != nil {
+		return err
+	}
+
+	secretName := ReadOnlyKubeconfigSecretName(clusterpedia)
+	var certData, keyData []byte
+	if secret, err := ctrl.client.CoreV1().Secrets(clusterpedia.Namespace).Get(ctx, secretName, metav1.GetOptions{}); err == nil {
+		if config, err := clientcmd.Load(secret.Data["kubeconfig"]); err == nil {
+			if authInfo, ok := config.AuthInfos[readOnlyUserName]; ok && reusableCert(authInfo.ClientCertificateData, caData, caData, caCert, nil) {
+				certData, keyData = authInfo.ClientCertificateData, authInfo.ClientKeyData
+			}
+		}
+	}
+	if certData == nil {
+		var err error
+		certData, keyData, err = newCert(caCert, caKey, readOnlyUserName, certutil.AltNames{})
+		if err != nil {
+			return err
+		}
+		ctrl.eventRecorder.Event(clusterpedia, corev1.EventTypeNormal, "CertificateRotated", "Issued a new client certificate for the read-only kubeconfig")
+	}
+
+	config := certs.CreateWithCerts(server, readOnlyUserName, "clusterpedia", caData, keyData, certData)
+	configBytes, err := clientcmd.Write(*config)
+	if err != nil {
+		return fmt.Errorf("failure while serializing read-only kubeconfig. %v", err)
+	}
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: clusterpedia.Namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{"kubeconfig": configBytes},
+	}
+	controllerutil.SetOwnerReference(clusterpedia, secret, scheme.Scheme)
+	result, err := clientutil.CreateOrUpdateSecret(ctx, ctrl.client, secret)
+	ctrl.recordOperationResult(ctx, clusterpedia, secret, result)
+	return err
+}
+
+// EnsureReadOnlyRBAC allows the user of the read-only kubeconfig to get, list and watch the resources of the
+// clusterpedia aggregated api in the control plane, which authorizes the requests to the clusterpedia-apiserver.
+func (ctrl *ClusterpediaController) EnsureReadOnlyRBAC(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	kubeconfigSecretName, err := ctrl.KubeConfigSecretNameFromProvider(ctx, clusterpedia)
+	if err != nil {
+		return err
+	}
+	clientConfig, err := utilresource.GetClientConfigFromKubeConfigSecret(ctrl.client, clusterpedia.Namespace, kubeconfigSecretName, userAgentName)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	clusterRole := &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "ClusterRole",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: readOnlyUserName,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"clusterpedia.io"},
+				Resources: []string{"*"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+		},
+	}
+	result, err := clientutil.CreateOrUpdateClusterRole(ctx, kubeClient, clusterRole)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, clusterpedia, clusterRole, result)
+	if err != nil {
+		return err
+	}
+
+	crb := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: readOnlyUserName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:     rbacv1.UserKind,
+				Name:     readOnlyUserName,
+				APIGroup: rbacv1.GroupName,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind:     "ClusterRole",
+			Name:     readOnlyUserName,
+			APIGroup: rbacv1.GroupName,
+		},
+	}
+	result, err = clientutil.CreateOrUpdateClusterRoleBinding(ctx, kubeClient, crb)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, clusterpedia, crb, result)
+	return err
+}
+
+// EnsureAPIServerIngress exposes the clusterpedia-apiserver through an Ingress if spec.access.ingress is set,
+// otherwise the Ingress is deleted.
+func (ctrl *ClusterpediaController) EnsureAPIServerIngress(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	componentName := constants.ClusterpediaComponentAPIServer
+	if clusterpedia.Spec.Access == nil || clusterpedia.Spec.Access.Ingress == nil {
+		err := ctrl.client.NetworkingV1().Ingresses(clusterpedia.Namespace).Delete(ctx, componentName, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Ingress", Namespace: clusterpedia.Namespace, Name: componentName}, err)
+		return client.IgnoreNotFound(err)
+	}
+	ingress, err := apiServerIngress(clusterpedia)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateIngress(ctx, ctrl.client, ingress)
+	ctrl.recordOperationResult(ctx, clusterpedia, ingress, result)
+	return err
+}
+
+// apiServerIngress returns the ingress which exposes the clusterpedia-apiserver of the clusterpedia,
+// which must have spec.access.ingress set.
+func apiServerIngress(clusterpedia *installv1alpha1.Clusterpedia) (*networkingv1.Ingress, error) {
+	componentName := constants.ClusterpediaComponentAPIServer
+	spec := clusterpedia.Spec.Access.Ingress
+
+	defaultAnnotations := map[string]string{
+		"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS",
+		"nginx.ingress.kubernetes.io/ssl-passthrough":  "true",
+	}
+	pathType := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.k8s.io/v1",
+			Kind:       "Ingress",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        componentName,
+			Namespace:   clusterpedia.Namespace,
+			Annotations: maputil.MergeStringMaps(defaultAnnotations, spec.Annotations),
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: spec.IngressClassName,
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: spec.Hostname,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: componentName,
+											Port: networkingv1.ServiceBackendPort{Number: 443},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	controllerutil.SetOwnerReference(clusterpedia, ingress, scheme.Scheme)
+	if err := patchutil.Apply(ingress, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return ingress, nil
+}
+
+// newCert issues a certificate of the common name and the SANs signed by the CA, which is valid for a year.
+func newCert(caCert *x509.Certificate, caKey crypto.Signer, commonName string, altNames certutil.AltNames) ([]byte, []byte, error) {
+	notAfter := time.Now().Add(certs.Duration365d).UTC()
+	certCfg := certs.NewCertConfig(commonName, []string{}, altNames, &notAfter)
+	cert, key, err := certs.NewCertAndKey(caCert, caKey, certCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyData, err := keyutil.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return certs.EncodeCertPEM(cert), keyData, nil
+}
+
+// reusableCert returns whether the certificate is still valid for the hosts, and is issued by the current
+// CA, that is the CA it was issued with is the current one.
+func reusableCert(certData, issuedCAData, caData []byte, caCert *x509.Certificate, hosts []string) bool {
+	if !bytes.Equal(issuedCAData, caData) {
+		return false
+	}
+	parsed, err := certutil.ParseCertsPEM(certData)
+	if err != nil {
+		return false
+	}
+	if time.Now().Add(certRenewBefore).After(parsed[0].NotAfter) {
+		return false
+	}
+	if err := parsed[0].CheckSignatureFrom(caCert); err != nil {
+		return false
+	}
+	for _, host := range hosts {
+		if err := parsed[0].VerifyHostname(host); err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpedia
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/helm"
+	"github.com/carlory/firefly/pkg/util/manifest"
+)
+
+// RenderManifests returns the objects which the controller applies to the host cluster for the
+// clusterpedia, without applying them. The fetcher loads spec.chart if it's set.
+//
+// The objects applied to the controlplane of the provider, that is the crds, the APIService and
+// the ClusterImportPolicy, are not part of the manifests.
+func RenderManifests(ctx context.Context, fetcher *helm.Fetcher, clusterpedia *installv1alpha1.Clusterpedia) ([]runtime.Object, error) {
+	var bundle manifest.Bundle
+	if clusterpedia.Spec.Chart != nil {
+		objs, err := renderChart(ctx, fetcher, clusterpedia)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			bundle.Add(obj, nil)
+		}
+		return bundle.Objects()
+	}
+
+	// the password is generated at install time, so the rendered one is only an example.
+	password, err := generateDatabasePassword()
+	if err != nil {
+		return nil, err
+	}
+	storage := clusterpedia.Spec.Storage
+	switch {
+	case storage.Postgres != nil && storage.Postgres.Local != nil:
+		bundle.Add(postgresService(clusterpedia))
+		bundle.Add(databaseSecret(clusterpedia, password))
+		bundle.Add(postgresConfigMap(clusterpedia))
+		if storage.Postgres.Local.Size != nil {
+			bundle.Add(dataVolumeClaim(clusterpedia, constants.ClusterpediaComponentInternalStoragePostgres, storage.Postgres.Local.PersistentStorage))
+		}
+		bundle.Add(postgresDeployment(clusterpedia))
+	case storage.MySQL != nil && storage.MySQL.Local != nil && storage.MySQL.Local.Operator != nil:
+		bundle.Add(databaseSecret(clusterpedia, password))
+		bundle.Add(mysqlOperatorSecret(clusterpedia, password))
+		bundle.Add(mysqlConfigMap(clusterpedia))
+		bundle.Add(innoDBCluster(clusterpedia))
+	case storage.MySQL != nil && storage.MySQL.Local != nil:
+		bundle.Add(mysqlService(clusterpedia))
+		bundle.Add(databaseSecret(clusterpedia, password))
+		bundle.Add(mysqlConfigMap(clusterpedia))
+		if storage.MySQL.Local.Size != nil {
+			bundle.Add(dataVolumeClaim(clusterpedia, constants.ClusterpediaComponentInternalStorageMySQL, storage.MySQL.Local.PersistentStorage))
+		}
+		bundle.Add(mysqlDeployment(clusterpedia))
+	default:
+		return nil, fmt.Errorf("unknown storage type")
+	}
+	if clusterpedia.Spec.Retention != nil {
+		bundle.Add(storageRetentionCronJob(clusterpedia))
+	}
+
+	kubeconfigSecretName, err := kubeConfigSecretNameFromProvider(clusterpedia)
+	if err != nil {
+		return nil, err
+	}
+	bundle.Add(apiServerService(clusterpedia))
+	bundle.Add(apiServerDeployment(clusterpedia, kubeconfigSecretName))
+	bundle.Add(controllerManagerDeployment(clusterpedia, kubeconfigSecretName))
+	synchroManagers, err := clusterSynchroManagerDeployments(clusterpedia, kubeconfigSecretName)
+	if err != nil {
+		return nil, err
+	}
+	for _, deployment := range synchroManagers {
+		bundle.Add(deployment, nil)
+	}
+	for _, obj := range podMonitors(clusterpedia) {
+		bundle.Add(obj, nil)
+	}
+	if clusterpedia.Spec.NetworkPolicy.Enabled {
+		policy, err := networkPolicy(clusterpedia)
+		if err != nil {
+			return nil, err
+		}
+		if policy != nil {
+			bundle.Add(policy, nil)
+		}
+	}
+	return bundle.Objects()
+}
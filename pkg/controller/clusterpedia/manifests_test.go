@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpedia
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// testClusterpedia returns a defaulted clusterpedia storing into the internal postgres.
+func testClusterpedia() *installv1alpha1.Clusterpedia {
+	clusterpedia := &installv1alpha1.Clusterpedia{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "clusterpedia-test", UID: "3c2b1a09-8f7e-4d6c-b5a4-9e8d7c6b5a4f"},
+	}
+	installv1alpha1.SetDefaults_Clusterpedia(clusterpedia)
+	return clusterpedia
+}
+
+// TestManifests compares the objects rendered for the components of a clusterpedia with the golden files
+// in testdata. Run the test with -update to update them after an intended change.
+func TestManifests(t *testing.T) {
+	clusterpedia := testClusterpedia()
+	kubeconfigSecretName := "test-kubeconfig"
+	manifests := map[string]func() (runtime.Object, error){
+		"apiserver-service":    func() (runtime.Object, error) { return apiServerService(clusterpedia) },
+		"apiserver-deployment": func() (runtime.Object, error) { return apiServerDeployment(clusterpedia, kubeconfigSecretName) },
+		"clustersynchro-manager-deployment": func() (runtime.Object, error) {
+			return clusterSynchroManagerDeployment(clusterpedia, kubeconfigSecretName, nil)
+		},
+		"controller-manager-deployment": func() (runtime.Object, error) {
+			return controllerManagerDeployment(clusterpedia, kubeconfigSecretName)
+		},
+		"postgres-service":    func() (runtime.Object, error) { return postgresService(clusterpedia) },
+		"postgres-configmap":  func() (runtime.Object, error) { return postgresConfigMap(clusterpedia) },
+		"postgres-deployment": func() (runtime.Object, error) { return postgresDeployment(clusterpedia) },
+		"network-policy":      func() (runtime.Object, error) { return networkPolicy(clusterpedia) },
+	}
+	for name, render := range manifests {
+		t.Run(name, func(t *testing.T) {
+			obj, err := render()
+			if err != nil {
+				t.Fatalf("failed to render %s: %v", name, err)
+			}
+			compareGolden(t, name, obj)
+		})
+	}
+}
+
+// compareGolden compares the YAML of the object with the golden file of the name, or updates the
+// golden file with it if the test is run with -update.
+func compareGolden(t *testing.T, name string, obj runtime.Object) {
+	t.Helper()
+	got, err := yaml.Marshal(obj)
+	if err != nil {
+		t.Fatalf("failed to marshal %s: %v", name, err)
+	}
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("failed to update %s: %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s, run the test with -update to create it: %v", path, err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s differs from %s, run the test with -update if the change is intended:\n%s", name, path, got)
+	}
+}
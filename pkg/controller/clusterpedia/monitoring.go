@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpedia
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	chartutil "github.com/carlory/firefly/pkg/util/chart"
+	"github.com/carlory/firefly/pkg/util/monitoring"
+)
+
+// metricsEndpoints are the metrics endpoints of the components which serve their metrics
+// over plain http. The clusterpedia-apiserver only serves them over authenticated https.
+var metricsEndpoints = []monitoring.Endpoint{
+	{Component: constants.ClusterpediaComponentControllerManager, Port: 8080, Path: "/metrics"},
+}
+
+// podMonitors returns the PodMonitors of the components of the clusterpedia if it's monitored.
+func podMonitors(clusterpedia *installv1alpha1.Clusterpedia) []*unstructured.Unstructured {
+	if !clusterpedia.Spec.Monitoring.Enabled {
+		return nil
+	}
+	objs := make([]*unstructured.Unstructured, 0, len(metricsEndpoints))
+	for _, endpoint := range metricsEndpoints {
+		objs = append(objs, monitoring.PodMonitor(clusterpedia.Namespace, endpoint, clusterpedia.Spec.Monitoring))
+	}
+	return objs
+}
+
+// EnsureMonitoring creates a PodMonitor for each component of the clusterpedia if it's monitored
+// and the Prometheus Operator is installed, and deletes them once it's no longer monitored.
+func (ctrl *ClusterpediaController) EnsureMonitoring(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	if !ctrl.podMonitorsAvailable {
+		return nil
+	}
+	if clusterpedia.Spec.Monitoring.Enabled {
+		return chartutil.Apply(ctx, ctrl.applier, clusterpedia, installv1alpha1.SchemeGroupVersion.WithKind("Clusterpedia"), podMonitors(clusterpedia))
+	}
+	for _, endpoint := range metricsEndpoints {
+		obj := monitoring.PodMonitor(clusterpedia.Namespace, endpoint, clusterpedia.Spec.Monitoring)
+		if err := ctrl.applier.Delete(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// annotateForScraping adds the scrape annotations to the pod template of the component if the
+// clusterpedia is monitored but the Prometheus Operator is not installed.
+func (ctrl *ClusterpediaController) annotateForScraping(clusterpedia *installv1alpha1.Clusterpedia, component string, template *corev1.PodTemplateSpec) {
+	if ctrl.podMonitorsAvailable || !clusterpedia.Spec.Monitoring.Enabled {
+		return
+	}
+	for _, endpoint := range metricsEndpoints {
+		if endpoint.Component == component {
+			monitoring.Annotate(template, endpoint)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpedia
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/networkpolicy"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+// storageComponents are the internal storage components whose traffic is restricted by a NetworkPolicy.
+var storageComponents = []string{
+	constants.ClusterpediaComponentInternalStoragePostgres,
+	constants.ClusterpediaComponentInternalStorageMySQL,
+}
+
+// storageComponent returns the internal storage component of the clusterpedia, or an empty string
+// if the storage isn't deployed by the controller. The clusters of the MySQL operator are managed by
+// the operator, so they're left alone.
+func storageComponent(clusterpedia *installv1alpha1.Clusterpedia) string {
+	storage := clusterpedia.Spec.Storage
+	switch {
+	case storage.Postgres != nil && storage.Postgres.Local != nil:
+		return constants.ClusterpediaComponentInternalStoragePostgres
+	case storage.MySQL != nil && storage.MySQL.Local != nil && storage.MySQL.Local.Operator == nil:
+		return constants.ClusterpediaComponentInternalStorageMySQL
+	}
+	return ""
+}
+
+// networkPolicy returns the NetworkPolicy of the internal storage of the clusterpedia, which only
+// admits the components storing data in it and the jobs maintaining it, or nil if there's none.
+func networkPolicy(clusterpedia *installv1alpha1.Clusterpedia) (*networkingv1.NetworkPolicy, error) {
+	component := storageComponent(clusterpedia)
+	if component == "" {
+		return nil, nil
+	}
+
+	var from []map[string]string
+	for _, app := range []string{
+		constants.ClusterpediaComponentAPIServer,
+		constants.ClusterpediaComponentClusterSynchroManager,
+		credentialsRotationJobName(component),
+		retentionCronJobName(component),
+		storageMigrationJobName,
+	} {
+		from = append(from, map[string]string{"app": app})
+	}
+	policy := networkpolicy.NetworkPolicy(clusterpedia.Namespace, component, map[string]string{"app": component},
+		networkpolicy.Rule{From: from})
+	controllerutil.SetOwnerReference(clusterpedia, policy, scheme.Scheme)
+	if err := patchutil.Apply(policy, clusterpedia.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// EnsureNetworkPolicy creates or updates the NetworkPolicy of the internal storage of the clusterpedia
+// if it's enabled, and deletes the NetworkPolicies which are no longer desired.
+func (ctrl *ClusterpediaController) EnsureNetworkPolicy(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia) error {
+	var desired string
+	if clusterpedia.Spec.NetworkPolicy.Enabled {
+		policy, err := networkPolicy(clusterpedia)
+		if err != nil {
+			return err
+		}
+		if policy != nil {
+			result, err := clientutil.CreateOrUpdateNetworkPolicy(ctx, ctrl.client, policy)
+			ctrl.recordOperationResult(ctx, clusterpedia, policy, result)
+			if err != nil {
+				return err
+			}
+			desired = policy.Name
+		}
+	}
+
+	for _, name := range storageComponents {
+		if name == desired {
+			continue
+		}
+		err := ctrl.client.NetworkingV1().NetworkPolicies(clusterpedia.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "NetworkPolicy", Namespace: clusterpedia.Namespace, Name: name}, err)
+		if err := client.IgnoreNotFound(err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
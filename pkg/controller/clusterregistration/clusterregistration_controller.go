@@ -0,0 +1,408 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterregistration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/prometheus/ratelimiter"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	fireflyclient "github.com/carlory/firefly/pkg/generated/clientset/versioned"
+	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
+	installlisters "github.com/carlory/firefly/pkg/generated/listers/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/coalesce"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	informerutil "github.com/carlory/firefly/pkg/util/informer"
+	"github.com/carlory/firefly/pkg/util/notification"
+	phaseutil "github.com/carlory/firefly/pkg/util/phase"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
+)
+
+const (
+	// maxRetries is the number of times a cluster registration will be retried before it is dropped out of the queue.
+	// With the current rate-limiter in use (5ms*2^(maxRetries-1)) the following numbers represent the
+	// sequence of delays between successive queuings of a cluster registration.
+	//
+	// 5ms, 10ms, 20ms, 40ms, 80ms, 160ms, 320ms, 640ms, 1.3s, 2.6s, 5.1s, 10.2s, 20.4s, 41s, 82s
+	maxRetries = 15
+
+	// name of the cluster registration controller finalizer
+	ClusterRegistrationControllerFinalizerName = "clusterregistration.install.firefly.io/finalizer"
+)
+
+// NewClusterRegistrationController returns a new *Controller.
+func NewClusterRegistrationController(
+	client clientset.Interface,
+	fireflyClient fireflyclient.Interface,
+	clusterRegistrationInformer installinformers.ClusterRegistrationInformer,
+	karmadaInformer installinformers.KarmadaInformer,
+	resyncPeriod time.Duration,
+	rateLimiter workqueue.RateLimiter) (*ClusterRegistrationController, error) {
+	broadcaster := record.NewBroadcaster()
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "clusterregistration-controller"})
+
+	if client != nil && client.CoreV1().RESTClient().GetRateLimiter() != nil {
+		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("clusterregistration_controller", client.CoreV1().RESTClient().GetRateLimiter())
+	}
+
+	ctrl := &ClusterRegistrationController{
+		client:                     client,
+		fireflyClient:              fireflyClient,
+		clusterRegistrationsLister: clusterRegistrationInformer.Lister(),
+		clusterRegistrationsSynced: clusterRegistrationInformer.Informer().HasSynced,
+		karmadasLister:             karmadaInformer.Lister(),
+		karmadasSynced:             karmadaInformer.Informer().HasSynced,
+		queue:                      coalesce.NewQueue(workqueue.NewNamedRateLimitingQueue(rateLimiter, "clusterregistration"), coalesce.DefaultWindow, coalesce.DefaultInterval),
+		workerLoopPeriod:           time.Second,
+		eventBroadcaster:           broadcaster,
+		eventRecorder:              recorder,
+	}
+
+	informerutil.AddEventHandler(clusterRegistrationInformer.Informer(), cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.addClusterRegistration,
+		UpdateFunc: ctrl.updateClusterRegistration,
+		DeleteFunc: ctrl.deleteClusterRegistration,
+	}, resyncPeriod)
+
+	// Cluster registrations are requeued when their karmada is created or changed,
+	// so that a member cluster waiting for its karmada is joined as soon as possible.
+	karmadaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.addKarmada,
+		UpdateFunc: ctrl.updateKarmada,
+	})
+
+	return ctrl, nil
+}
+
+type ClusterRegistrationController struct {
+	client           clientset.Interface
+	fireflyClient    fireflyclient.Interface
+	eventBroadcaster record.EventBroadcaster
+	eventRecorder    record.EventRecorder
+
+	clusterRegistrationsLister installlisters.ClusterRegistrationLister
+	clusterRegistrationsSynced cache.InformerSynced
+
+	karmadasLister installlisters.KarmadaLister
+	karmadasSynced cache.InformerSynced
+
+	// Cluster registrations that need to be updated. A channel is inappropriate here,
+	// because it allows a cluster registration to be inserted multiple times and be
+	// processed more than necessary.
+	queue *coalesce.Queue
+
+	// workerLoopPeriod is the time between worker runs. The workers process the queue of cluster registration changes.
+	workerLoopPeriod time.Duration
+}
+
+// Run will not return until stopCh is closed. workers determines how many
+// cluster registrations will be handled in parallel.
+func (ctrl *ClusterRegistrationController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+
+	// Start events processing pipeline.
+	ctrl.eventBroadcaster.StartStructuredLogging(0)
+	ctrl.eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: ctrl.client.CoreV1().Events("")})
+	defer ctrl.eventBroadcaster.Shutdown()
+
+	defer ctrl.queue.ShutDown()
+
+	klog.Infof("Starting cluster registration controller")
+	defer klog.Infof("Shutting down cluster registration controller")
+
+	if !cache.WaitForNamedCacheSync("clusterregistration", ctx.Done(), ctrl.clusterRegistrationsSynced, ctrl.karmadasSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, ctrl.worker, ctrl.workerLoopPeriod)
+	}
+	<-ctx.Done()
+}
+
+// worker runs a worker thread that just dequeues items, processes them, and
+// marks them done. You may run as many of these in parallel as you wish; the
+// workqueue guarantees that they will not end up processing the same cluster
+// registration at the same time.
+func (ctrl *ClusterRegistrationController) worker(ctx context.Context) {
+	for ctrl.processNextWorkItem(ctx) {
+	}
+}
+
+func (ctrl *ClusterRegistrationController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := ctrl.queue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.queue.Done(key)
+
+	ctx, span := tracingutil.StartReconcile(ctx, "clusterregistration", key.(string))
+	err := ctrl.syncClusterRegistration(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.handleErr(err, key)
+
+	return true
+}
+
+func (ctrl *ClusterRegistrationController) addClusterRegistration(obj interface{}) {
+	cr := obj.(*installv1alpha1.ClusterRegistration)
+	klog.V(4).InfoS("Adding cluster registration", "clusterregistration", klog.KObj(cr))
+	ctrl.enqueue(cr)
+}
+
+func (ctrl *ClusterRegistrationController) updateClusterRegistration(old, cur interface{}) {
+	oldCR := old.(*installv1alpha1.ClusterRegistration)
+	curCR := cur.(*installv1alpha1.ClusterRegistration)
+	klog.V(4).InfoS("Updating cluster registration", "clusterregistration", klog.KObj(oldCR))
+	ctrl.enqueue(curCR)
+}
+
+func (ctrl *ClusterRegistrationController) deleteClusterRegistration(obj interface{}) {
+	cr, ok := obj.(*installv1alpha1.ClusterRegistration)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("couldn't get object from tombstone %#v", obj))
+			return
+		}
+		cr, ok = tombstone.Obj.(*installv1alpha1.ClusterRegistration)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("tombstone contained object that is not a ClusterRegistration %#v", obj))
+			return
+		}
+	}
+	klog.V(4).InfoS("Deleting cluster registration", "clusterregistration", klog.KObj(cr))
+	ctrl.enqueue(cr)
+}
+
+func (ctrl *ClusterRegistrationController) addKarmada(obj interface{}) {
+	karmada := obj.(*installv1alpha1.Karmada)
+	ctrl.enqueueClusterRegistrationsForKarmada(karmada)
+}
+
+func (ctrl *ClusterRegistrationController) updateKarmada(old, cur interface{}) {
+	curKarmada := cur.(*installv1alpha1.Karmada)
+	ctrl.enqueueClusterRegistrationsForKarmada(curKarmada)
+}
+
+// enqueueClusterRegistrationsForKarmada enqueues all cluster registrations which join the given karmada.
+func (ctrl *ClusterRegistrationController) enqueueClusterRegistrationsForKarmada(karmada *installv1alpha1.Karmada) {
+	crs, err := ctrl.clusterRegistrationsLister.ClusterRegistrations(karmada.Namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, cr := range crs {
+		if cr.Spec.Karmada.Name == karmada.Name {
+			ctrl.enqueueCoalesced(cr)
+		}
+	}
+}
+
+func (ctrl *ClusterRegistrationController) enqueue(cr *installv1alpha1.ClusterRegistration) {
+	key, err := cache.MetaNamespaceKeyFunc(cr)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.Add(key)
+}
+
+// enqueueCoalesced enqueues the cluster registration after the bursts of events of its secondary objects have settled.
+func (ctrl *ClusterRegistrationController) enqueueCoalesced(cr *installv1alpha1.ClusterRegistration) {
+	key, err := cache.MetaNamespaceKeyFunc(cr)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.AddCoalesced(key)
+}
+
+func (ctrl *ClusterRegistrationController) handleErr(err error, key interface{}) {
+	if err == nil || errors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+		ctrl.queue.Forget(key)
+		return
+	}
+
+	ns, name, keyErr := cache.SplitMetaNamespaceKey(key.(string))
+	if keyErr != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+	}
+
+	notification.ReconcileFailed("clusterregistration", audit.ObjectReference{Kind: "ClusterRegistration", Namespace: ns, Name: name}, ctrl.queue.NumRequeues(key)+1, err)
+	if ctrl.queue.NumRequeues(key) < maxRetries {
+		klog.V(2).InfoS("Error syncing cluster registration, retrying", "clusterregistration", klog.KRef(ns, name), "err", err)
+		ctrl.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	klog.V(2).InfoS("Dropping cluster registration out of the queue", "clusterregistration", klog.KRef(ns, name), "err", err)
+	ctrl.queue.Forget(key)
+}
+
+func (ctrl *ClusterRegistrationController) syncClusterRegistration(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+		return err
+	}
+
+	startTime := time.Now()
+	klog.V(4).InfoS("Started syncing cluster registration", "clusterregistration", klog.KRef(namespace, name), "startTime", startTime)
+	defer func() {
+		klog.V(4).InfoS("Finished syncing cluster registration", "clusterregistration", klog.KRef(namespace, name), "duration", time.Since(startTime))
+	}()
+
+	cr, err := ctrl.clusterRegistrationsLister.ClusterRegistrations(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		klog.V(2).InfoS("Cluster registration has been deleted", "clusterregistration", klog.KRef(namespace, name))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Deep-copy otherwise we are mutating our cache.
+	cr = cr.DeepCopy()
+	ctx = audit.WithTrigger(ctx, cr)
+	ctx = dryrun.ForObject(ctx, cr)
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if cr.DeletionTimestamp.IsZero() {
+		// The object is not being deleted, so if it does not have our finalizer,
+		// then lets add the finalizer and update the object. This is equivalent
+		// registering our finalizer.
+		if !controllerutil.ContainsFinalizer(cr, ClusterRegistrationControllerFinalizerName) {
+			controllerutil.AddFinalizer(cr, ClusterRegistrationControllerFinalizerName)
+			cr, err = ctrl.fireflyClient.InstallV1alpha1().ClusterRegistrations(cr.Namespace).Update(ctx, cr, metav1.UpdateOptions{})
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		// The object is being deleted
+		if controllerutil.ContainsFinalizer(cr, ClusterRegistrationControllerFinalizerName) {
+			// our finalizer is present, so lets unjoin the member cluster
+			if err := ctrl.unjoinCluster(ctx, cr, cr.Status.ClusterName, cr.Status.SyncMode); err != nil {
+				// if fail to unjoin the member cluster here, return with error
+				// so that it can be retried
+				return err
+			}
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(cr, ClusterRegistrationControllerFinalizerName)
+			_, err := ctrl.fireflyClient.InstallV1alpha1().ClusterRegistrations(cr.Namespace).Update(ctx, cr, metav1.UpdateOptions{})
+			if err != nil {
+				return err
+			}
+			// Stop reconciliation as the item is being deleted
+			return nil
+		}
+	}
+
+	klog.InfoS("Syncing cluster registration", "clusterregistration", klog.KObj(cr))
+
+	karmadaName := cr.Spec.Karmada.Name
+	karmada, err := ctrl.karmadasLister.Karmadas(cr.Namespace).Get(karmadaName)
+	if errors.IsNotFound(err) {
+		// The cluster registration will be requeued once the karmada is created.
+		return ctrl.updateStatus(ctx, cr, metav1.ConditionFalse, "KarmadaNotFound", fmt.Sprintf("karmada %s not found", karmadaName))
+	}
+	if err != nil {
+		return err
+	}
+	if !karmada.DeletionTimestamp.IsZero() {
+		return ctrl.updateStatus(ctx, cr, metav1.ConditionFalse, "KarmadaTerminating", fmt.Sprintf("karmada %s is terminating", karmadaName))
+	}
+	if karmada.Status.KarmadaVersion == "" {
+		// The cluster registration will be requeued once the karmada is installed.
+		return ctrl.updateStatus(ctx, cr, metav1.ConditionFalse, "KarmadaNotInstalled", fmt.Sprintf("karmada %s is not installed yet", karmadaName))
+	}
+
+	clusterName, syncMode := desiredClusterName(cr), desiredSyncMode(cr)
+	if cr.Status.ClusterName != "" && (cr.Status.ClusterName != clusterName || cr.Status.SyncMode != syncMode) {
+		// The member cluster is joined with another name or sync mode, unjoin it first.
+		if err := ctrl.unjoinCluster(ctx, cr, cr.Status.ClusterName, cr.Status.SyncMode); err != nil {
+			return err
+		}
+		ctrl.eventRecorder.Eventf(cr, corev1.EventTypeNormal, "Unjoined", "Unjoined cluster %s in %s mode", cr.Status.ClusterName, cr.Status.SyncMode)
+		cr.Status.ClusterName, cr.Status.SyncMode = "", ""
+	}
+
+	if err := ctrl.joinCluster(ctx, cr, clusterName, syncMode); err != nil {
+		ctrl.eventRecorder.Eventf(cr, corev1.EventTypeWarning, "JoinFailed", "Failed to join cluster %s: %v", clusterName, err)
+		if updateErr := ctrl.updateStatus(ctx, cr, metav1.ConditionFalse, "JoinFailed", err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "Failed to update cluster registration status", "clusterregistration", klog.KObj(cr))
+		}
+		return err
+	}
+	if !meta.IsStatusConditionTrue(cr.Status.Conditions, installv1alpha1.ClusterRegistrationConditionJoined) {
+		ctrl.eventRecorder.Eventf(cr, corev1.EventTypeNormal, "Joined", "Joined cluster %s in %s mode", clusterName, syncMode)
+	}
+	cr.Status.ClusterName, cr.Status.SyncMode = clusterName, syncMode
+	return ctrl.updateStatus(ctx, cr, metav1.ConditionTrue, "Joined", fmt.Sprintf("cluster %s is joined in %s mode", clusterName, syncMode))
+}
+
+// desiredClusterName returns the name of the Cluster object of the member cluster.
+func desiredClusterName(cr *installv1alpha1.ClusterRegistration) string {
+	if cr.Spec.ClusterName != "" {
+		return cr.Spec.ClusterName
+	}
+	return cr.Name
+}
+
+// desiredSyncMode returns the sync mode with which the member cluster is joined.
+func desiredSyncMode(cr *installv1alpha1.ClusterRegistration) installv1alpha1.ClusterSyncMode {
+	if cr.Spec.SyncMode != "" {
+		return cr.Spec.SyncMode
+	}
+	return installv1alpha1.ClusterSyncModePush
+}
+
+func (ctrl *ClusterRegistrationController) updateStatus(ctx context.Context, cr *installv1alpha1.ClusterRegistration, status metav1.ConditionStatus, reason, message string) error {
+	cr.Status.ObservedGeneration = cr.Generation
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               installv1alpha1.ClusterRegistrationConditionJoined,
+		Status:             status,
+		ObservedGeneration: cr.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	phaseutil.SetClusterRegistrationPhase(cr)
+	_, err := ctrl.fireflyClient.InstallV1alpha1().ClusterRegistrations(cr.Namespace).UpdateStatus(ctx, cr, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterregistration
+
+import (
+	"context"
+	"fmt"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	karmadaversioned "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/controller/clusteragent"
+	"github.com/carlory/firefly/pkg/util/audit"
+	utilresource "github.com/carlory/firefly/pkg/util/resource"
+)
+
+const (
+	// the user-agent name is used when talking to karmada-apiserver and the member clusters
+	userAgentName = "clusterregistration-controller"
+
+	// karmadaKubeconfigSecretName is the name of the secret which holds the kubeconfig of karmada-apiserver.
+	karmadaKubeconfigSecretName = "karmada-kubeconfig"
+)
+
+// memberClient talks to the member cluster of a cluster registration.
+type memberClient struct {
+	config *rest.Config
+	client kubernetes.Interface
+}
+
+func (ctrl *ClusterRegistrationController) newMemberClient(cr *installv1alpha1.ClusterRegistration) (*memberClient, error) {
+	config, err := utilresource.GetClientConfigFromKubeConfigSecret(ctrl.client, cr.Namespace, cr.Spec.KubeconfigSecret.Name, userAgentName)
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &memberClient{config: config, client: client}, nil
+}
+
+func (ctrl *ClusterRegistrationController) newKarmadaClients(cr *installv1alpha1.ClusterRegistration) (kubernetes.Interface, karmadaversioned.Interface, error) {
+	config, err := utilresource.GetClientConfigFromKubeConfigSecret(ctrl.client, cr.Namespace, karmadaKubeconfigSecretName, userAgentName)
+	if err != nil {
+		return nil, nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	karmadaClient, err := karmadaversioned.NewForConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return kubeClient, karmadaClient, nil
+}
+
+// joinCluster performs the equivalent of `karmadactl join` in push mode, and creates or updates the Cluster
+// object in the karmada. In pull mode, karmada-agent is deployed into the member cluster by the cluster agent
+// controller once the member cluster is joined.
+func (ctrl *ClusterRegistrationController) joinCluster(ctx context.Context, cr *installv1alpha1.ClusterRegistration, clusterName string, syncMode installv1alpha1.ClusterSyncMode) error {
+	member, err := ctrl.newMemberClient(cr)
+	if err != nil {
+		return err
+	}
+	karmadaKubeClient, karmadaClient, err := ctrl.newKarmadaClients(cr)
+	if err != nil {
+		return err
+	}
+
+	apiEndpoint := cr.Spec.APIEndpoint
+	if apiEndpoint == "" {
+		apiEndpoint = member.config.Host
+	}
+	cluster := &clusterv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   clusterName,
+			Labels: map[string]string{constants.ClusterRegistrationLabel: cr.Name},
+		},
+		Spec: clusterv1alpha1.ClusterSpec{
+			SyncMode:                    clusterv1alpha1.ClusterSyncMode(syncMode),
+			APIEndpoint:                 apiEndpoint,
+			InsecureSkipTLSVerification: member.config.Insecure,
+		},
+	}
+
+	if syncMode != installv1alpha1.ClusterSyncModePull {
+		secretRef, impersonatorSecretRef, err := ctrl.ensurePushCredentials(ctx, cr, member, karmadaKubeClient, clusterName)
+		if err != nil {
+			return err
+		}
+		cluster.Spec.SecretRef = secretRef
+		cluster.Spec.ImpersonatorSecretRef = impersonatorSecretRef
+	}
+	return ensureCluster(ctx, karmadaClient, cluster)
+}
+
+// ensureCluster creates or updates the Cluster object. The status and the fields of the spec which are
+// not managed by firefly, e.g. the taints, are kept.
+func ensureCluster(ctx context.Context, karmadaClient karmadaversioned.Interface, cluster *clusterv1alpha1.Cluster) error {
+	clusters := karmadaClient.ClusterV1alpha1().Clusters()
+	existing, err := clusters.Get(ctx, cluster.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = clusters.Create(ctx, cluster, metav1.CreateOptions{})
+		audit.RecordResult(ctx, audit.Create, cluster, err)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if owner := existing.Labels[constants.ClusterRegistrationLabel]; owner != cluster.Labels[constants.ClusterRegistrationLabel] {
+		return fmt.Errorf("cluster %s already exists in the karmada and isn't joined by this cluster registration", cluster.Name)
+	}
+
+	old := existing.DeepCopy()
+	existing.Spec.SyncMode = cluster.Spec.SyncMode
+	existing.Spec.APIEndpoint = cluster.Spec.APIEndpoint
+	existing.Spec.InsecureSkipTLSVerification = cluster.Spec.InsecureSkipTLSVerification
+	existing.Spec.SecretRef = cluster.Spec.SecretRef
+	existing.Spec.ImpersonatorSecretRef = cluster.Spec.ImpersonatorSecretRef
+	updated, err := clusters.Update(ctx, existing, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	audit.RecordUpdate(ctx, old, updated)
+	return nil
+}
+
+// unjoinCluster deletes the Cluster object from the karmada, and then removes everything created for the
+// member cluster when it's joined with the given name and sync mode. Nothing is done if it's never joined.
+func (ctrl *ClusterRegistrationController) unjoinCluster(ctx context.Context, cr *installv1alpha1.ClusterRegistration, clusterName string, syncMode installv1alpha1.ClusterSyncMode) error {
+	if clusterName == "" {
+		return nil
+	}
+
+	karmada, err := ctrl.karmadasLister.Karmadas(cr.Namespace).Get(cr.Spec.Karmada.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	// The karmada side is skipped if the whole control plane is gone or going away.
+	if err == nil && karmada.DeletionTimestamp.IsZero() {
+		karmadaKubeClient, karmadaClient, err := ctrl.newKarmadaClients(cr)
+		if err != nil {
+			return err
+		}
+		if err := deleteCluster(ctx, karmadaClient, cr, clusterName); err != nil {
+			return err
+		}
+		if syncMode != installv1alpha1.ClusterSyncModePull {
+			if err := removePushSecrets(ctx, karmadaKubeClient, clusterName); err != nil {
+				return err
+			}
+		}
+	}
+
+	member, err := ctrl.newMemberClient(cr)
+	if errors.IsNotFound(err) {
+		klog.V(2).InfoS("Kubeconfig secret of the member cluster is gone, skip cleaning up the member cluster", "clusterregistration", klog.KObj(cr), "cluster", clusterName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if syncMode == installv1alpha1.ClusterSyncModePull {
+		return clusteragent.RemoveAgent(ctx, member.client)
+	}
+	return removePushCredentials(ctx, member, clusterName)
+}
+
+// deleteCluster deletes the Cluster object and waits for it to be gone, so that the karmada has removed
+// the works and the execution namespace of the member cluster before its credentials are revoked.
+func deleteCluster(ctx context.Context, karmadaClient karmadaversioned.Interface, cr *installv1alpha1.ClusterRegistration, clusterName string) error {
+	clusters := karmadaClient.ClusterV1alpha1().Clusters()
+	existing, err := clusters.Get(ctx, clusterName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.Labels[constants.ClusterRegistrationLabel] != cr.Name {
+		// The cluster has been taken over by someone else, leave it alone.
+		return nil
+	}
+	if existing.DeletionTimestamp.IsZero() {
+		err := clusters.Delete(ctx, clusterName, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Cluster", Name: clusterName}, err)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("waiting for cluster %s to be removed from the karmada", clusterName)
+}
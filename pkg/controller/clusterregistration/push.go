@@ -0,0 +1,250 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterregistration
+
+import (
+	"context"
+	"fmt"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+)
+
+const (
+	// impersonatorName is the name of the service account, cluster role and cluster role binding
+	// used by the karmada to impersonate the users of the karmada in the member cluster.
+	impersonatorName = "karmada-impersonator"
+)
+
+// controllerManagerName returns the name of the service account, cluster role and cluster role binding
+// used by the karmada controllers to access the member cluster, which is the same as `karmadactl join`.
+func controllerManagerName(clusterName string) string {
+	return fmt.Sprintf("karmada-%s", clusterName)
+}
+
+// controllerManagerClusterRoleName returns the name of the cluster role and cluster role binding of the
+// service account used by the karmada controllers.
+func controllerManagerClusterRoleName(clusterName string) string {
+	return fmt.Sprintf("karmada-controller-manager:%s", controllerManagerName(clusterName))
+}
+
+// tokenSecretName returns the name of the service account token secret of the given service account.
+func tokenSecretName(serviceAccountName string) string {
+	return fmt.Sprintf("%s-token", serviceAccountName)
+}
+
+// ensurePushCredentials creates the service accounts used by the karmada in the member cluster, and
+// stores their tokens into the karmada. It returns the references to the secrets of the Cluster object.
+func (ctrl *ClusterRegistrationController) ensurePushCredentials(ctx context.Context, cr *installv1alpha1.ClusterRegistration, member *memberClient,
+	karmadaKubeClient kubernetes.Interface, clusterName string) (*clusterv1alpha1.LocalSecretReference, *clusterv1alpha1.LocalSecretReference, error) {
+	if err := ensureNamespace(ctx, member.client, cr, constants.KarmadaClusterNamespace); err != nil {
+		return nil, nil, err
+	}
+
+	saName := controllerManagerName(clusterName)
+	controllerManagerRules := []rbacv1.PolicyRule{
+		{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+		{NonResourceURLs: []string{"*"}, Verbs: []string{"get"}},
+	}
+	if err := ensureServiceAccountWithClusterRole(ctx, member.client, cr, constants.KarmadaClusterNamespace, saName, controllerManagerClusterRoleName(clusterName), controllerManagerRules); err != nil {
+		return nil, nil, err
+	}
+	impersonatorRules := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"users", "groups", "serviceaccounts"}, Verbs: []string{"impersonate"}},
+	}
+	if err := ensureServiceAccountWithClusterRole(ctx, member.client, cr, constants.KarmadaClusterNamespace, impersonatorName, impersonatorName, impersonatorRules); err != nil {
+		return nil, nil, err
+	}
+
+	token, caBundle, err := serviceAccountToken(ctx, member.client, cr, constants.KarmadaClusterNamespace, saName)
+	if err != nil {
+		return nil, nil, err
+	}
+	impersonatorToken, _, err := serviceAccountToken(ctx, member.client, cr, constants.KarmadaClusterNamespace, impersonatorName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(member.config.CAData) > 0 {
+		// the member cluster is accessed with the CA of the kubeconfig, which may differ from the CA of the tokens.
+		caBundle = member.config.CAData
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterName,
+			Namespace: constants.KarmadaClusterNamespace,
+			Labels:    map[string]string{constants.ClusterRegistrationLabel: cr.Name},
+		},
+		Data: map[string][]byte{
+			clusterv1alpha1.SecretCADataKey: caBundle,
+			clusterv1alpha1.SecretTokenKey:  token,
+		},
+	}
+	result, err := clientutil.CreateOrUpdateSecret(ctx, karmadaKubeClient, secret)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, cr, secret, result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	impersonatorSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      impersonatorSecretName(clusterName),
+			Namespace: constants.KarmadaClusterNamespace,
+			Labels:    map[string]string{constants.ClusterRegistrationLabel: cr.Name},
+		},
+		Data: map[string][]byte{
+			clusterv1alpha1.SecretTokenKey: impersonatorToken,
+		},
+	}
+	result, err = clientutil.CreateOrUpdateSecret(ctx, karmadaKubeClient, impersonatorSecret)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, cr, impersonatorSecret, result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &clusterv1alpha1.LocalSecretReference{Namespace: secret.Namespace, Name: secret.Name},
+		&clusterv1alpha1.LocalSecretReference{Namespace: impersonatorSecret.Namespace, Name: impersonatorSecret.Name}, nil
+}
+
+// impersonatorSecretName returns the name of the secret holding the impersonator token of the member cluster in the karmada.
+func impersonatorSecretName(clusterName string) string {
+	return fmt.Sprintf("%s-impersonator", clusterName)
+}
+
+// ensureNamespace creates the namespace, or patches its labels if it exists.
+func ensureNamespace(ctx context.Context, client kubernetes.Interface, cr *installv1alpha1.ClusterRegistration, name string) error {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{constants.ClusterRegistrationLabel: cr.Name},
+		},
+	}
+	_, err := clientutil.CreateOrUpdateNamespace(ctx, client, ns)
+	return err
+}
+
+// ensureServiceAccountWithClusterRole creates the service account, and grants the rules to it by a cluster
+// role and a cluster role binding of the given name. The rules are updated if they're changed.
+func ensureServiceAccountWithClusterRole(ctx context.Context, client kubernetes.Interface, cr *installv1alpha1.ClusterRegistration,
+	namespace, saName, clusterRoleName string, rules []rbacv1.PolicyRule) error {
+	labels := map[string]string{constants.ClusterRegistrationLabel: cr.Name}
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: saName, Namespace: namespace, Labels: labels},
+	}
+	if _, err := clientutil.CreateOrUpdateServiceAccount(ctx, client, sa); err != nil {
+		return err
+	}
+
+	clusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterRoleName, Labels: labels},
+		Rules:      rules,
+	}
+	if _, err := clientutil.CreateOrUpdateClusterRole(ctx, client, clusterRole); err != nil {
+		return err
+	}
+
+	crb := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterRoleName, Labels: labels},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      saName,
+				Namespace: namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind:     "ClusterRole",
+			Name:     clusterRoleName,
+			APIGroup: "rbac.authorization.k8s.io",
+		},
+	}
+	_, err := clientutil.CreateOrUpdateClusterRoleBinding(ctx, client, crb)
+	return err
+}
+
+// serviceAccountToken returns the token and the CA of the service account. Token secrets are not created
+// automatically since kubernetes 1.24, so the secret is created explicitly and the token controller
+// of the member cluster populates it. An error is returned until it's populated.
+func serviceAccountToken(ctx context.Context, client kubernetes.Interface, cr *installv1alpha1.ClusterRegistration, namespace, saName string) ([]byte, []byte, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        tokenSecretName(saName),
+			Namespace:   namespace,
+			Labels:      map[string]string{constants.ClusterRegistrationLabel: cr.Name},
+			Annotations: map[string]string{corev1.ServiceAccountNameKey: saName},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}
+	if _, err := clientutil.CreateOrUpdateSecret(ctx, client, secret); err != nil {
+		return nil, nil, err
+	}
+
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, tokenSecretName(saName), metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	token := secret.Data[corev1.ServiceAccountTokenKey]
+	if len(token) == 0 {
+		return nil, nil, fmt.Errorf("waiting for the token of service account %s/%s to be populated", namespace, saName)
+	}
+	return token, secret.Data[corev1.ServiceAccountRootCAKey], nil
+}
+
+// removePushSecrets deletes the secrets holding the tokens of the member cluster from the karmada.
+func removePushSecrets(ctx context.Context, karmadaKubeClient kubernetes.Interface, clusterName string) error {
+	for _, name := range []string{clusterName, impersonatorSecretName(clusterName)} {
+		err := karmadaKubeClient.CoreV1().Secrets(constants.KarmadaClusterNamespace).Delete(ctx, name, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Secret", Namespace: constants.KarmadaClusterNamespace, Name: name}, err)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// removePushCredentials revokes the access of the karmada to the member cluster, which is the same
+// as `karmadactl unjoin`. The namespace is deleted at last, along with the service accounts and tokens.
+func removePushCredentials(ctx context.Context, member *memberClient, clusterName string) error {
+	for _, name := range []string{controllerManagerClusterRoleName(clusterName), impersonatorName} {
+		err := member.client.RbacV1().ClusterRoleBindings().Delete(ctx, name, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "ClusterRoleBinding", Name: name}, err)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		err = member.client.RbacV1().ClusterRoles().Delete(ctx, name, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "ClusterRole", Name: name}, err)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	err := member.client.CoreV1().Namespaces().Delete(ctx, constants.KarmadaClusterNamespace, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Namespace", Name: constants.KarmadaClusterNamespace}, err)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
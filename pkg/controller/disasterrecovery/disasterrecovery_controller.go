@@ -0,0 +1,290 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package disasterrecovery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/prometheus/ratelimiter"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	fireflyclient "github.com/carlory/firefly/pkg/generated/clientset/versioned"
+	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
+	installlisters "github.com/carlory/firefly/pkg/generated/listers/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/apply"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	"github.com/carlory/firefly/pkg/util/notification"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
+)
+
+const (
+	// maxRetries is the number of times a karmada will be retried before it is dropped out of the queue.
+	// With the current rate-limiter in use (5ms*2^(maxRetries-1)) the following numbers represent the
+	// sequence of delays between successive queuings of a karmada.
+	//
+	// 5ms, 10ms, 20ms, 40ms, 80ms, 160ms, 320ms, 640ms, 1.3s, 2.6s, 5.1s, 10.2s, 20.4s, 41s, 82s
+	maxRetries = 15
+
+	// restorePollPeriod is how often the progress of a restore is checked.
+	restorePollPeriod = 10 * time.Second
+)
+
+// NewDisasterRecoveryController returns a new *Controller.
+func NewDisasterRecoveryController(
+	client clientset.Interface,
+	fireflyClient fireflyclient.Interface,
+	karmadaInformer installinformers.KarmadaInformer,
+	restConfig *rest.Config,
+	veleroNamespace string) (*DisasterRecoveryController, error) {
+	broadcaster := record.NewBroadcaster()
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "disasterrecovery-controller"})
+
+	if client != nil && client.CoreV1().RESTClient().GetRateLimiter() != nil {
+		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("disasterrecovery_controller", client.CoreV1().RESTClient().GetRateLimiter())
+	}
+
+	applier, err := apply.NewApplier(restConfig, "firefly-disasterrecovery-controller")
+	if err != nil {
+		return nil, err
+	}
+
+	ctrl := &DisasterRecoveryController{
+		client:           client,
+		fireflyClient:    fireflyClient,
+		applier:          applier,
+		veleroNamespace:  veleroNamespace,
+		karmadasLister:   karmadaInformer.Lister(),
+		karmadasSynced:   karmadaInformer.Informer().HasSynced,
+		queue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "disasterrecovery"),
+		workerLoopPeriod: time.Second,
+		eventBroadcaster: broadcaster,
+		eventRecorder:    recorder,
+	}
+
+	karmadaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.addKarmada,
+		UpdateFunc: ctrl.updateKarmada,
+		DeleteFunc: ctrl.deleteKarmada,
+	})
+
+	return ctrl, nil
+}
+
+// DisasterRecoveryController schedules the Velero backups of the karmadas which enable the disaster
+// recovery, and restores a karmada from a backup when it's annotated with constants.RestoreFromBackupAnnotation.
+type DisasterRecoveryController struct {
+	client           clientset.Interface
+	fireflyClient    fireflyclient.Interface
+	eventBroadcaster record.EventBroadcaster
+	eventRecorder    record.EventRecorder
+
+	// applier applies the Velero Schedules and Restores.
+	applier *apply.Applier
+	// veleroNamespace is the namespace Velero is installed in, where its Schedules and Restores live.
+	veleroNamespace string
+
+	karmadasLister installlisters.KarmadaLister
+	karmadasSynced cache.InformerSynced
+
+	// Karmadas that need to be synced. A channel is inappropriate here,
+	// because it allows a karmada to be inserted multiple times and be
+	// processed more than necessary.
+	queue workqueue.RateLimitingInterface
+
+	// workerLoopPeriod is the time between worker runs. The workers process the queue of karmada changes.
+	workerLoopPeriod time.Duration
+}
+
+// Run will not return until stopCh is closed. workers determines how many
+// karmadas will be handled in parallel.
+func (ctrl *DisasterRecoveryController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+
+	// Start events processing pipeline.
+	ctrl.eventBroadcaster.StartStructuredLogging(0)
+	ctrl.eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: ctrl.client.CoreV1().Events("")})
+	defer ctrl.eventBroadcaster.Shutdown()
+
+	defer ctrl.queue.ShutDown()
+
+	klog.Infof("Starting disaster recovery controller")
+	defer klog.Infof("Shutting down disaster recovery controller")
+
+	if !cache.WaitForNamedCacheSync("disasterrecovery", ctx.Done(), ctrl.karmadasSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, ctrl.worker, ctrl.workerLoopPeriod)
+	}
+	<-ctx.Done()
+}
+
+// worker runs a worker thread that just dequeues items, processes them, and
+// marks them done. You may run as many of these in parallel as you wish; the
+// workqueue guarantees that they will not end up processing the same karmada
+// at the same time.
+func (ctrl *DisasterRecoveryController) worker(ctx context.Context) {
+	for ctrl.processNextWorkItem(ctx) {
+	}
+}
+
+func (ctrl *DisasterRecoveryController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := ctrl.queue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.queue.Done(key)
+
+	ctx, span := tracingutil.StartReconcile(ctx, "disasterrecovery", key.(string))
+	err := ctrl.syncKarmada(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.handleErr(err, key)
+
+	return true
+}
+
+func (ctrl *DisasterRecoveryController) addKarmada(obj interface{}) {
+	karmada := obj.(*installv1alpha1.Karmada)
+	klog.V(4).InfoS("Adding karmada", "karmada", klog.KObj(karmada))
+	ctrl.enqueue(karmada)
+}
+
+// updateKarmada enqueues the karmada when its spec is changed or a restore is requested. The progress
+// of a restore is picked up by polling.
+func (ctrl *DisasterRecoveryController) updateKarmada(old, cur interface{}) {
+	oldKarmada := old.(*installv1alpha1.Karmada)
+	curKarmada := cur.(*installv1alpha1.Karmada)
+	if oldKarmada.Generation == curKarmada.Generation &&
+		oldKarmada.Annotations[constants.RestoreFromBackupAnnotation] == curKarmada.Annotations[constants.RestoreFromBackupAnnotation] {
+		return
+	}
+	klog.V(4).InfoS("Updating karmada", "karmada", klog.KObj(oldKarmada))
+	ctrl.enqueue(curKarmada)
+}
+
+func (ctrl *DisasterRecoveryController) deleteKarmada(obj interface{}) {
+	karmada, ok := obj.(*installv1alpha1.Karmada)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("couldn't get object from tombstone %#v", obj))
+			return
+		}
+		karmada, ok = tombstone.Obj.(*installv1alpha1.Karmada)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("tombstone contained object that is not a Karmada %#v", obj))
+			return
+		}
+	}
+	klog.V(4).InfoS("Deleting karmada", "karmada", klog.KObj(karmada))
+	ctrl.enqueue(karmada)
+}
+
+func (ctrl *DisasterRecoveryController) enqueue(karmada *installv1alpha1.Karmada) {
+	key, err := cache.MetaNamespaceKeyFunc(karmada)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.Add(key)
+}
+
+func (ctrl *DisasterRecoveryController) handleErr(err error, key interface{}) {
+	if err == nil || errors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+		ctrl.queue.Forget(key)
+		return
+	}
+
+	ns, name, keyErr := cache.SplitMetaNamespaceKey(key.(string))
+	if keyErr != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+	}
+
+	notification.ReconcileFailed("disasterrecovery", audit.ObjectReference{Kind: "Karmada", Namespace: ns, Name: name}, ctrl.queue.NumRequeues(key)+1, err)
+	if ctrl.queue.NumRequeues(key) < maxRetries {
+		klog.V(2).InfoS("Error syncing disaster recovery of karmada, retrying", "karmada", klog.KRef(ns, name), "err", err)
+		ctrl.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	klog.V(2).InfoS("Dropping karmada out of the queue", "karmada", klog.KRef(ns, name), "err", err)
+	ctrl.queue.Forget(key)
+}
+
+func (ctrl *DisasterRecoveryController) syncKarmada(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+		return err
+	}
+
+	startTime := time.Now()
+	klog.V(4).InfoS("Started syncing disaster recovery of karmada", "karmada", klog.KRef(namespace, name), "startTime", startTime)
+	defer func() {
+		klog.V(4).InfoS("Finished syncing disaster recovery of karmada", "karmada", klog.KRef(namespace, name), "duration", time.Since(startTime))
+	}()
+
+	karmada, err := ctrl.karmadasLister.Karmadas(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		klog.V(2).InfoS("Karmada has been deleted", "karmada", klog.KRef(namespace, name))
+		// the schedule lives in the namespace of Velero, so it isn't garbage collected with the karmada.
+		return ctrl.applier.Delete(ctx, ctrl.schedule(namespace, name))
+	}
+	if err != nil {
+		return err
+	}
+	if !karmada.DeletionTimestamp.IsZero() || karmada.Spec.Chart != nil {
+		return ctrl.applier.Delete(ctx, ctrl.schedule(namespace, name))
+	}
+	if karmada.Spec.Paused {
+		klog.V(2).InfoS("Karmada is paused, skip syncing disaster recovery", "karmada", klog.KObj(karmada))
+		return nil
+	}
+
+	// Deep-copy otherwise we are mutating our cache.
+	karmada = karmada.DeepCopy()
+	ctx = audit.WithTrigger(ctx, karmada)
+	ctx = dryrun.ForObject(ctx, karmada)
+
+	if err := ctrl.EnsureSchedule(ctx, karmada); err != nil {
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "BackupScheduleFailed", "Failed to schedule the backups: %v", err)
+		return err
+	}
+	if dryrun.Enabled(ctx) {
+		// a restore can't be previewed, since each phase waits for the mutations of the previous one.
+		return nil
+	}
+	return ctrl.syncRestore(ctx, key, karmada)
+}
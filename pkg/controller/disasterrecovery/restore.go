@@ -0,0 +1,318 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package disasterrecovery
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/util/audit"
+	phaseutil "github.com/carlory/firefly/pkg/util/phase"
+)
+
+// restartedAPIServers are the components which cache the data of etcd, so they're restarted once
+// etcd is started with the restored data.
+var restartedAPIServers = []string{
+	constants.KarmadaComponentKubeAPIServer,
+	constants.KarmadaComponentAggregratedAPIServer,
+	constants.KarmadaComponentSearch,
+}
+
+// restoreStep runs a phase of the restore, it returns the phase the restore transitions to, which is
+// the current phase if the restore has to wait, and a message about it.
+type restoreStep func(ctx context.Context, karmada *installv1alpha1.Karmada) (installv1alpha1.KarmadaRestorePhase, string, error)
+
+// syncRestore starts a restore of the karmada if it's requested by constants.RestoreFromBackupAnnotation,
+// and advances the restore in progress. The restore is sequenced as follows:
+//
+//  1. etcd and its data volume are deleted, during which the karmada controller stops reconciling the karmada.
+//  2. the volumes and the secrets of the namespace of the karmada are restored by a Velero Restore.
+//  3. the karmada controller recreates etcd, which picks up the restored data volume.
+//  4. the apiservers are restarted, so that they don't serve the data cached before the restore.
+func (ctrl *DisasterRecoveryController) syncRestore(ctx context.Context, key string, karmada *installv1alpha1.Karmada) error {
+	backup := karmada.Annotations[constants.RestoreFromBackupAnnotation]
+	restore := karmada.Status.Restore
+	if restore == nil || finished(restore) {
+		if backup == "" {
+			return nil
+		}
+		now := metav1.Now()
+		karmada.Status.Restore = &installv1alpha1.KarmadaRestoreStatus{
+			BackupName:         backup,
+			RestoreName:        fmt.Sprintf("firefly-%s-%s-%s", karmada.Namespace, karmada.Name, now.Format("20060102150405")),
+			Phase:              installv1alpha1.KarmadaRestoreScalingDown,
+			StartTime:          &now,
+			LastTransitionTime: &now,
+		}
+		if err := ctrl.updateStatus(ctx, karmada); err != nil {
+			return err
+		}
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "RestoreStarted", "Restoring from the backup %s", backup)
+		ctrl.queue.Add(key)
+		return ctrl.removeRestoreAnnotation(ctx, karmada)
+	}
+	if backup == restore.BackupName {
+		// the annotation is left over if it failed to be removed once the restore was started.
+		if err := ctrl.removeRestoreAnnotation(ctx, karmada); err != nil {
+			return err
+		}
+	}
+
+	var step restoreStep
+	switch restore.Phase {
+	case installv1alpha1.KarmadaRestoreScalingDown:
+		step = ctrl.scaleDownEtcd
+	case installv1alpha1.KarmadaRestoreRestoringVolumes:
+		step = ctrl.restoreVolumes
+	case installv1alpha1.KarmadaRestoreStartingEtcd:
+		step = ctrl.startEtcd
+	case installv1alpha1.KarmadaRestoreRestartingAPIServers:
+		step = ctrl.restartAPIServers
+	default:
+		return fmt.Errorf("unknown restore phase %q", restore.Phase)
+	}
+	phase, message, err := step(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	if phase == restore.Phase {
+		klog.V(4).InfoS("Waiting for the restore of karmada", "karmada", klog.KObj(karmada), "phase", phase, "message", message)
+		ctrl.queue.AddAfter(key, restorePollPeriod)
+		return nil
+	}
+
+	now := metav1.Now()
+	restore.Phase = phase
+	restore.Message = message
+	restore.LastTransitionTime = &now
+	if err := ctrl.updateStatus(ctx, karmada); err != nil {
+		return err
+	}
+	switch phase {
+	case installv1alpha1.KarmadaRestoreCompleted:
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "RestoreCompleted", "Restored from the backup %s", restore.BackupName)
+	case installv1alpha1.KarmadaRestoreFailed:
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "RestoreFailed", "Failed to restore from the backup %s: %s", restore.BackupName, message)
+	default:
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "Restoring", "Restoring from the backup %s: %s", restore.BackupName, phase)
+		ctrl.queue.Add(key)
+	}
+	return nil
+}
+
+// scaleDownEtcd deletes etcd and its data volume, so that the volume can be restored by Velero, which
+// doesn't overwrite existing volumes.
+func (ctrl *DisasterRecoveryController) scaleDownEtcd(ctx context.Context, karmada *installv1alpha1.Karmada) (installv1alpha1.KarmadaRestorePhase, string, error) {
+	if karmada.Spec.Etcd.External != nil {
+		return installv1alpha1.KarmadaRestoreRestoringVolumes, "", nil
+	}
+
+	namespace := karmada.Namespace
+	sts, err := ctrl.client.AppsV1().StatefulSets(namespace).Get(ctx, constants.KarmadaComponentEtcd, metav1.GetOptions{})
+	if client.IgnoreNotFound(err) != nil {
+		return "", "", err
+	}
+	if err == nil {
+		if sts.DeletionTimestamp.IsZero() {
+			err := ctrl.client.AppsV1().StatefulSets(namespace).Delete(ctx, sts.Name, metav1.DeleteOptions{})
+			audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "StatefulSet", Namespace: namespace, Name: sts.Name}, err)
+			if client.IgnoreNotFound(err) != nil {
+				return "", "", err
+			}
+		}
+		return installv1alpha1.KarmadaRestoreScalingDown, "waiting for the etcd statefulset to be deleted", nil
+	}
+
+	// the claims created from the volume claim templates are labeled with the selector of the statefulset.
+	selector := labels.SelectorFromSet(labels.Set{"app": constants.KarmadaComponentEtcd}).String()
+	pods, err := ctrl.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return "", "", err
+	}
+	if len(pods.Items) > 0 {
+		return installv1alpha1.KarmadaRestoreScalingDown, "waiting for the etcd pods to be deleted", nil
+	}
+	claims, err := ctrl.client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return "", "", err
+	}
+	if len(claims.Items) > 0 {
+		for _, claim := range claims.Items {
+			if !claim.DeletionTimestamp.IsZero() {
+				continue
+			}
+			err := ctrl.client.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, claim.Name, metav1.DeleteOptions{})
+			audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "PersistentVolumeClaim", Namespace: namespace, Name: claim.Name}, err)
+			if client.IgnoreNotFound(err) != nil {
+				return "", "", err
+			}
+		}
+		return installv1alpha1.KarmadaRestoreScalingDown, "waiting for the etcd data volume to be deleted", nil
+	}
+	return installv1alpha1.KarmadaRestoreRestoringVolumes, "", nil
+}
+
+// restoreVolumes restores the volumes and the secrets of the namespace of the karmada by a Velero Restore.
+// The secrets are overwritten, since the restored data of etcd must be served with the certificates it
+// was backed up with.
+func (ctrl *DisasterRecoveryController) restoreVolumes(ctx context.Context, karmada *installv1alpha1.Karmada) (installv1alpha1.KarmadaRestorePhase, string, error) {
+	restore := ctrl.veleroObject(restoreGVK, karmada.Status.Restore.RestoreName, karmada.Namespace, karmada.Name)
+	got, err := ctrl.applier.Get(ctx, restore)
+	if errors.IsNotFound(err) {
+		restore.Object["spec"] = map[string]interface{}{
+			"backupName":             karmada.Status.Restore.BackupName,
+			"includedNamespaces":     []interface{}{karmada.Namespace},
+			"includedResources":      []interface{}{"persistentvolumeclaims", "persistentvolumes", "secrets"},
+			"restorePVs":             true,
+			"existingResourcePolicy": "update",
+		}
+		got, err = ctrl.applier.Apply(ctx, restore)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	phase, _, _ := unstructured.NestedString(got.Object, "status", "phase")
+	switch phase {
+	case "Completed":
+		return installv1alpha1.KarmadaRestoreStartingEtcd, "", nil
+	case "PartiallyFailed", "Failed", "FailedValidation":
+		message := fmt.Sprintf("the Velero Restore %s is %s", restore.GetName(), phase)
+		if reason, _, _ := unstructured.NestedString(got.Object, "status", "failureReason"); reason != "" {
+			message = fmt.Sprintf("%s: %s", message, reason)
+		}
+		if errs, _, _ := unstructured.NestedStringSlice(got.Object, "status", "validationErrors"); len(errs) > 0 {
+			message = fmt.Sprintf("%s: %v", message, errs)
+		}
+		return installv1alpha1.KarmadaRestoreFailed, message, nil
+	}
+	return installv1alpha1.KarmadaRestoreRestoringVolumes, fmt.Sprintf("waiting for the Velero Restore %s to complete", restore.GetName()), nil
+}
+
+// startEtcd waits for etcd to be recreated by the karmada controller with the restored data volume.
+func (ctrl *DisasterRecoveryController) startEtcd(ctx context.Context, karmada *installv1alpha1.Karmada) (installv1alpha1.KarmadaRestorePhase, string, error) {
+	if karmada.Spec.Etcd.External != nil {
+		return installv1alpha1.KarmadaRestoreRestartingAPIServers, "", nil
+	}
+	sts, err := ctrl.client.AppsV1().StatefulSets(karmada.Namespace).Get(ctx, constants.KarmadaComponentEtcd, metav1.GetOptions{})
+	if client.IgnoreNotFound(err) != nil {
+		return "", "", err
+	}
+	if err != nil || !statefulSetReady(sts) {
+		return installv1alpha1.KarmadaRestoreStartingEtcd, "waiting for etcd to be ready", nil
+	}
+	return installv1alpha1.KarmadaRestoreRestartingAPIServers, "", nil
+}
+
+// restartAPIServers deletes the pods of the apiservers started before the restore entered the phase,
+// and waits for the apiservers to be ready again.
+func (ctrl *DisasterRecoveryController) restartAPIServers(ctx context.Context, karmada *installv1alpha1.Karmada) (installv1alpha1.KarmadaRestorePhase, string, error) {
+	namespace := karmada.Namespace
+	restartedAt := karmada.Status.Restore.LastTransitionTime
+	for _, component := range restartedAPIServers {
+		deployment, err := ctrl.client.AppsV1().Deployments(namespace).Get(ctx, component, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			// the component is disabled.
+			continue
+		}
+		if err != nil {
+			return "", "", err
+		}
+
+		selector := labels.SelectorFromSet(labels.Set{"app": component}).String()
+		pods, err := ctrl.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return "", "", err
+		}
+		stale := 0
+		for _, pod := range pods.Items {
+			if restartedAt == nil || !pod.CreationTimestamp.Before(restartedAt) {
+				continue
+			}
+			stale++
+			if !pod.DeletionTimestamp.IsZero() {
+				continue
+			}
+			err := ctrl.client.CoreV1().Pods(namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+			audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Pod", Namespace: namespace, Name: pod.Name}, err)
+			if client.IgnoreNotFound(err) != nil {
+				return "", "", err
+			}
+		}
+		if stale > 0 || !deploymentReady(deployment) {
+			return installv1alpha1.KarmadaRestoreRestartingAPIServers, fmt.Sprintf("waiting for %s to be restarted", component), nil
+		}
+	}
+	return installv1alpha1.KarmadaRestoreCompleted, "", nil
+}
+
+// updateStatus updates the status of the karmada, and keeps its resource version up to date.
+func (ctrl *DisasterRecoveryController) updateStatus(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	phaseutil.SetKarmadaPhase(karmada)
+	updated, err := ctrl.fireflyClient.InstallV1alpha1().Karmadas(karmada.Namespace).UpdateStatus(ctx, karmada, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	karmada.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
+// removeRestoreAnnotation removes constants.RestoreFromBackupAnnotation from the karmada.
+func (ctrl *DisasterRecoveryController) removeRestoreAnnotation(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if _, ok := karmada.Annotations[constants.RestoreFromBackupAnnotation]; !ok {
+		return nil
+	}
+	delete(karmada.Annotations, constants.RestoreFromBackupAnnotation)
+	updated, err := ctrl.fireflyClient.InstallV1alpha1().Karmadas(karmada.Namespace).Update(ctx, karmada, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	karmada.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
+// finished returns whether the restore has completed or failed.
+func finished(restore *installv1alpha1.KarmadaRestoreStatus) bool {
+	return restore.Phase == installv1alpha1.KarmadaRestoreCompleted || restore.Phase == installv1alpha1.KarmadaRestoreFailed
+}
+
+func statefulSetReady(sts *appsv1.StatefulSet) bool {
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	return sts.Status.ObservedGeneration >= sts.Generation && sts.Status.ReadyReplicas >= replicas
+}
+
+func deploymentReady(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation && deployment.Status.ReadyReplicas >= replicas
+}
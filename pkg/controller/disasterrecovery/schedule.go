@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package disasterrecovery
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+)
+
+var (
+	// ScheduleGVR is the resource of the Schedules of Velero.
+	ScheduleGVR = schema.GroupVersionResource{Group: "velero.io", Version: "v1", Resource: "schedules"}
+
+	scheduleGVK = schema.GroupVersionKind{Group: "velero.io", Version: "v1", Kind: "Schedule"}
+	restoreGVK  = schema.GroupVersionKind{Group: "velero.io", Version: "v1", Kind: "Restore"}
+)
+
+// karmadaNamespaceLabel is the label set on the Velero objects created for a karmada, its value is
+// the namespace of the karmada. The objects can't be owned by the karmada, since they live in the
+// namespace of Velero.
+const karmadaNamespaceLabel = "install.firefly.io/karmada-namespace"
+
+// etcdSnapshotCommand saves a snapshot of etcd into its data volume before each backup.
+var etcdSnapshotCommand = []interface{}{
+	"etcdctl",
+	"--endpoints=https://127.0.0.1:2379",
+	"--cacert=/etc/etcd/pki/etcd-ca.crt",
+	"--cert=/etc/etcd/pki/etcd-server.crt",
+	"--key=/etc/etcd/pki/etcd-server.key",
+	"snapshot",
+	"save",
+	"/var/lib/etcd/snapshot.db",
+}
+
+// EnsureSchedule applies the Velero Schedule of the backups of the karmada if the disaster recovery
+// is enabled, otherwise deletes it.
+func (ctrl *DisasterRecoveryController) EnsureSchedule(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	dr := karmada.Spec.DisasterRecovery
+	if dr == nil {
+		return ctrl.applier.Delete(ctx, ctrl.schedule(karmada.Namespace, karmada.Name))
+	}
+	if local := karmada.Spec.Etcd.Local; karmada.Spec.Etcd.External == nil && (local == nil || (local.DataVolume == nil && local.StorageSize == nil)) {
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "EtcdDataVolumeMissing", "The data of etcd isn't backed up, since etcd has no data volume")
+	}
+
+	template := map[string]interface{}{
+		"includedNamespaces": []interface{}{karmada.Namespace},
+		"snapshotVolumes":    true,
+	}
+	if dr.TTL != nil {
+		template["ttl"] = dr.TTL.Duration.String()
+	}
+	if dr.StorageLocation != "" {
+		template["storageLocation"] = dr.StorageLocation
+	}
+	if len(dr.VolumeSnapshotLocations) > 0 {
+		locations := make([]interface{}, 0, len(dr.VolumeSnapshotLocations))
+		for _, location := range dr.VolumeSnapshotLocations {
+			locations = append(locations, location)
+		}
+		template["volumeSnapshotLocations"] = locations
+	}
+	if karmada.Spec.Etcd.External == nil {
+		template["hooks"] = map[string]interface{}{
+			"resources": []interface{}{
+				map[string]interface{}{
+					"name":               "etcd-snapshot",
+					"includedNamespaces": []interface{}{karmada.Namespace},
+					"labelSelector": map[string]interface{}{
+						"matchLabels": map[string]interface{}{"app": constants.KarmadaComponentEtcd},
+					},
+					"pre": []interface{}{
+						map[string]interface{}{
+							"exec": map[string]interface{}{
+								"container": constants.KarmadaComponentEtcd,
+								"command":   etcdSnapshotCommand,
+								"onError":   "Fail",
+								"timeout":   "5m",
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	schedule := ctrl.schedule(karmada.Namespace, karmada.Name)
+	schedule.Object["spec"] = map[string]interface{}{
+		"schedule": dr.Schedule,
+		"template": template,
+	}
+	_, err := ctrl.applier.Apply(ctx, schedule)
+	return err
+}
+
+// schedule returns the Velero Schedule of the backups of the given karmada without its spec.
+func (ctrl *DisasterRecoveryController) schedule(namespace, name string) *unstructured.Unstructured {
+	return ctrl.veleroObject(scheduleGVK, fmt.Sprintf("firefly-%s-%s", namespace, name), namespace, name)
+}
+
+// veleroObject returns an object of Velero created for the given karmada.
+func (ctrl *DisasterRecoveryController) veleroObject(gvk schema.GroupVersionKind, objName, namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(ctrl.veleroNamespace)
+	obj.SetName(objName)
+	obj.SetLabels(map[string]string{
+		constants.KarmadaInstanceLabel: name,
+		karmadaNamespaceLabel:          namespace,
+	})
+	return obj
+}
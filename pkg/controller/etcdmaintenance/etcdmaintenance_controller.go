@@ -0,0 +1,321 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdmaintenance
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	metav1apply "k8s.io/client-go/applyconfigurations/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/prometheus/ratelimiter"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	installapplyconfig "github.com/carlory/firefly/pkg/generated/applyconfiguration/install/v1alpha1"
+	fireflyclient "github.com/carlory/firefly/pkg/generated/clientset/versioned"
+	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
+	installlisters "github.com/carlory/firefly/pkg/generated/listers/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	"github.com/carlory/firefly/pkg/util/notification"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
+)
+
+const (
+	// maxRetries is the number of times a karmada will be retried before it is dropped out of the queue.
+	// With the current rate-limiter in use (5ms*2^(maxRetries-1)) the following numbers represent the
+	// sequence of delays between successive queuings of a karmada.
+	//
+	// 5ms, 10ms, 20ms, 40ms, 80ms, 160ms, 320ms, 640ms, 1.3s, 2.6s, 5.1s, 10.2s, 20.4s, 41s, 82s
+	maxRetries = 15
+
+	// maintenanceCheckPeriod is how often the etcd of an installed karmada is checked.
+	maintenanceCheckPeriod = time.Minute
+
+	// fieldManager is the field manager of the EtcdDegraded condition of the karmadas.
+	fieldManager = "firefly-etcdmaintenance-controller"
+)
+
+// NewEtcdMaintenanceController returns a new *Controller.
+func NewEtcdMaintenanceController(
+	client clientset.Interface,
+	fireflyClient fireflyclient.Interface,
+	karmadaInformer installinformers.KarmadaInformer,
+	spaceThresholdPercent int32,
+	fragmentationThresholdPercent int32,
+	minRemediationInterval time.Duration,
+	compactionRetentionRevisions int64) (*EtcdMaintenanceController, error) {
+	broadcaster := record.NewBroadcaster()
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "etcdmaintenance-controller"})
+
+	if client != nil && client.CoreV1().RESTClient().GetRateLimiter() != nil {
+		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("etcdmaintenance_controller", client.CoreV1().RESTClient().GetRateLimiter())
+	}
+
+	ctrl := &EtcdMaintenanceController{
+		client:                        client,
+		fireflyClient:                 fireflyClient,
+		karmadasLister:                karmadaInformer.Lister(),
+		karmadasSynced:                karmadaInformer.Informer().HasSynced,
+		queue:                         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "etcdmaintenance"),
+		workerLoopPeriod:              time.Second,
+		eventBroadcaster:              broadcaster,
+		eventRecorder:                 recorder,
+		spaceThresholdPercent:         int64(spaceThresholdPercent),
+		fragmentationThresholdPercent: int64(fragmentationThresholdPercent),
+		minRemediationInterval:        minRemediationInterval,
+		compactionRetentionRevisions:  compactionRetentionRevisions,
+		lastRemediations:              map[string]time.Time{},
+	}
+
+	karmadaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.addKarmada,
+		UpdateFunc: ctrl.updateKarmada,
+	})
+
+	return ctrl, nil
+}
+
+// EtcdMaintenanceController checks the database of the local etcd of the installed karmadas periodically.
+// It compacts and defragments the database when it's running out of space or fragmented, disarms the
+// NOSPACE alarm once the space is reclaimed, and reports by the EtcdDegraded condition of the karmadas
+// what to do when the space can't be reclaimed automatically.
+type EtcdMaintenanceController struct {
+	client           clientset.Interface
+	fireflyClient    fireflyclient.Interface
+	eventBroadcaster record.EventBroadcaster
+	eventRecorder    record.EventRecorder
+
+	karmadasLister installlisters.KarmadaLister
+	karmadasSynced cache.InformerSynced
+
+	// Karmadas whose etcd needs to be checked. A channel is inappropriate here,
+	// because it allows a karmada to be inserted multiple times and be
+	// processed more than necessary.
+	queue workqueue.RateLimitingInterface
+
+	// workerLoopPeriod is the time between worker runs. The workers process the queue of karmada changes.
+	workerLoopPeriod time.Duration
+
+	// spaceThresholdPercent is the percentage of the space quota which, once the database exceeds it,
+	// makes the database compacted and defragmented.
+	spaceThresholdPercent int64
+	// fragmentationThresholdPercent is the percentage of the database not in use which, once it's
+	// exceeded, makes the database defragmented.
+	fragmentationThresholdPercent int64
+	// minRemediationInterval is the minimum interval between two remediations of the same etcd.
+	minRemediationInterval time.Duration
+	// compactionRetentionRevisions is the number of the latest revisions kept by a compaction.
+	compactionRetentionRevisions int64
+
+	// lastRemediations are the last times the etcd of the karmadas were remediated, keyed by the
+	// karmadas.
+	lock             sync.Mutex
+	lastRemediations map[string]time.Time
+}
+
+// Run will not return until stopCh is closed. workers determines how many
+// karmadas will be handled in parallel.
+func (ctrl *EtcdMaintenanceController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+
+	// Start events processing pipeline.
+	ctrl.eventBroadcaster.StartStructuredLogging(0)
+	ctrl.eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: ctrl.client.CoreV1().Events("")})
+	defer ctrl.eventBroadcaster.Shutdown()
+
+	defer ctrl.queue.ShutDown()
+
+	klog.Infof("Starting etcd maintenance controller")
+	defer klog.Infof("Shutting down etcd maintenance controller")
+
+	if !cache.WaitForNamedCacheSync("etcdmaintenance", ctx.Done(), ctrl.karmadasSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, ctrl.worker, ctrl.workerLoopPeriod)
+	}
+	<-ctx.Done()
+}
+
+// worker runs a worker thread that just dequeues items, processes them, and
+// marks them done. You may run as many of these in parallel as you wish; the
+// workqueue guarantees that they will not end up processing the same karmada
+// at the same time.
+func (ctrl *EtcdMaintenanceController) worker(ctx context.Context) {
+	for ctrl.processNextWorkItem(ctx) {
+	}
+}
+
+func (ctrl *EtcdMaintenanceController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := ctrl.queue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.queue.Done(key)
+
+	ctx, span := tracingutil.StartReconcile(ctx, "etcdmaintenance", key.(string))
+	err := ctrl.syncEtcdMaintenance(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.handleErr(err, key)
+
+	return true
+}
+
+func (ctrl *EtcdMaintenanceController) addKarmada(obj interface{}) {
+	karmada := obj.(*installv1alpha1.Karmada)
+	klog.V(4).InfoS("Adding karmada", "karmada", klog.KObj(karmada))
+	ctrl.enqueue(karmada)
+}
+
+// updateKarmada enqueues the karmada when its spec is changed or it's installed. The other updates,
+// e.g. the condition reported by this controller, are picked up by the periodic checks.
+func (ctrl *EtcdMaintenanceController) updateKarmada(old, cur interface{}) {
+	oldKarmada := old.(*installv1alpha1.Karmada)
+	curKarmada := cur.(*installv1alpha1.Karmada)
+	if oldKarmada.Generation == curKarmada.Generation && oldKarmada.Status.KarmadaVersion == curKarmada.Status.KarmadaVersion {
+		return
+	}
+	klog.V(4).InfoS("Updating karmada", "karmada", klog.KObj(oldKarmada))
+	ctrl.enqueue(curKarmada)
+}
+
+func (ctrl *EtcdMaintenanceController) enqueue(karmada *installv1alpha1.Karmada) {
+	key, err := cache.MetaNamespaceKeyFunc(karmada)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.Add(key)
+}
+
+func (ctrl *EtcdMaintenanceController) handleErr(err error, key interface{}) {
+	if err == nil || errors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+		ctrl.queue.Forget(key)
+		return
+	}
+
+	ns, name, keyErr := cache.SplitMetaNamespaceKey(key.(string))
+	if keyErr != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+	}
+
+	notification.ReconcileFailed("etcdmaintenance", audit.ObjectReference{Kind: "Karmada", Namespace: ns, Name: name}, ctrl.queue.NumRequeues(key)+1, err)
+	if ctrl.queue.NumRequeues(key) < maxRetries {
+		klog.V(2).InfoS("Error syncing etcd maintenance, retrying", "karmada", klog.KRef(ns, name), "err", err)
+		ctrl.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	klog.V(2).InfoS("Dropping etcd maintenance out of the queue", "karmada", klog.KRef(ns, name), "err", err)
+	ctrl.queue.Forget(key)
+	// keep checking the etcd even though the status of the karmada can't be updated for now.
+	ctrl.queue.AddAfter(key, maintenanceCheckPeriod)
+}
+
+func (ctrl *EtcdMaintenanceController) syncEtcdMaintenance(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+		return err
+	}
+
+	startTime := time.Now()
+	klog.V(4).InfoS("Started syncing etcd maintenance", "karmada", klog.KRef(namespace, name), "startTime", startTime)
+	defer func() {
+		klog.V(4).InfoS("Finished syncing etcd maintenance", "karmada", klog.KRef(namespace, name), "duration", time.Since(startTime))
+	}()
+
+	karmada, err := ctrl.karmadasLister.Karmadas(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		klog.V(2).InfoS("Karmada has been deleted", "karmada", klog.KRef(namespace, name))
+		ctrl.lock.Lock()
+		delete(ctrl.lastRemediations, key)
+		ctrl.lock.Unlock()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !karmada.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	// Only the local etcd installed by firefly is maintained, an external etcd is maintained by its owner.
+	if karmada.Status.KarmadaVersion == "" || karmada.Spec.Etcd.External != nil {
+		klog.V(4).InfoS("Karmada has no local etcd installed, skip maintaining", "karmada", klog.KObj(karmada))
+		return nil
+	}
+
+	// Deep-copy otherwise we are mutating our cache.
+	karmada = karmada.DeepCopy()
+	ctx = audit.WithTrigger(ctx, karmada)
+	ctx = dryrun.ForObject(ctx, karmada)
+
+	condition := ctrl.maintain(ctx, key, karmada)
+	if err := ctrl.updateCondition(ctx, karmada, condition); err != nil {
+		return err
+	}
+
+	ctrl.queue.AddAfter(key, maintenanceCheckPeriod)
+	return nil
+}
+
+// updateCondition sets the EtcdDegraded condition of the karmada. The condition is applied on its own
+// only if it's changed, so that it neither conflicts with nor overwrites the rest of the status, which
+// is owned by the karmada controller. The phase of the karmada is summarized by the karmada controller
+// once it's notified of the change.
+func (ctrl *EtcdMaintenanceController) updateCondition(ctx context.Context, karmada *installv1alpha1.Karmada, condition metav1.Condition) error {
+	condition.ObservedGeneration = karmada.Generation
+	old := meta.FindStatusCondition(karmada.Status.Conditions, condition.Type)
+	if old != nil && old.Status == condition.Status && old.Reason == condition.Reason &&
+		old.Message == condition.Message && old.ObservedGeneration == condition.ObservedGeneration {
+		return nil
+	}
+	if condition.Status == metav1.ConditionTrue && (old == nil || old.Status != metav1.ConditionTrue || old.Reason != condition.Reason) {
+		ctrl.eventRecorder.Event(karmada, corev1.EventTypeWarning, "EtcdDegraded", condition.Message)
+	}
+	// the last transition time is kept unless the status of the condition is changed.
+	meta.SetStatusCondition(&karmada.Status.Conditions, condition)
+	condition = *meta.FindStatusCondition(karmada.Status.Conditions, condition.Type)
+
+	status := installapplyconfig.KarmadaStatus().WithConditions(metav1apply.Condition().
+		WithType(condition.Type).
+		WithStatus(condition.Status).
+		WithObservedGeneration(condition.ObservedGeneration).
+		WithLastTransitionTime(condition.LastTransitionTime).
+		WithReason(condition.Reason).
+		WithMessage(condition.Message))
+	_, err := ctrl.fireflyClient.InstallV1alpha1().Karmadas(karmada.Namespace).ApplyStatus(ctx,
+		installapplyconfig.Karmada(karmada.Name, karmada.Namespace).WithStatus(status),
+		metav1.ApplyOptions{FieldManager: fieldManager, Force: true})
+	return err
+}
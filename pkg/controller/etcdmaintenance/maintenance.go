@@ -0,0 +1,203 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdmaintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	karmadacontroller "github.com/carlory/firefly/pkg/controller/karmada"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+)
+
+const (
+	// etcdRequestTimeout bounds the requests to etcd, except the compactions and the defragmentations.
+	etcdRequestTimeout = 10 * time.Second
+	// etcdMaintenanceTimeout bounds the compactions and the defragmentations, which take a while for a
+	// large database.
+	etcdMaintenanceTimeout = 5 * time.Minute
+
+	// minReclaimableBytes is the minimum space a defragmentation must reclaim to be run because of the
+	// fragmentation of the database, so that a small database isn't defragmented over and over.
+	minReclaimableBytes = 64 << 20
+)
+
+// maintain checks the database of the local etcd of the karmada, compacts and defragments it and
+// disarms the NOSPACE alarm if it's needed, and returns the EtcdDegraded condition of the karmada.
+func (ctrl *EtcdMaintenanceController) maintain(ctx context.Context, key string, karmada *installv1alpha1.Karmada) metav1.Condition {
+	condition := metav1.Condition{Type: installv1alpha1.KarmadaConditionEtcdDegraded}
+	endpoint := karmadacontroller.LocalEtcdEndpoint(karmada)
+	client, err := karmadacontroller.NewLocalEtcdClient(ctx, ctrl.client, karmada)
+	if err != nil {
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionUnknown, "EtcdUnreachable", err.Error()
+		return condition
+	}
+	defer client.Close()
+
+	db, alarms, err := inspect(ctx, client, endpoint)
+	if err != nil {
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionUnknown, "EtcdUnreachable", err.Error()
+		return condition
+	}
+	if hasAlarm(alarms, pb.AlarmType_CORRUPT) {
+		condition.Status, condition.Reason = metav1.ConditionTrue, "DataCorrupted"
+		condition.Message = "etcd raised the CORRUPT alarm, its data can't be trusted: restore the karmada from a backup taken before the corruption"
+		return condition
+	}
+
+	quota := karmadacontroller.EtcdQuotaBytes(karmada)
+	noSpace := hasAlarm(alarms, pb.AlarmType_NOSPACE)
+	spaceExceeded := db.DbSize*100 >= quota*ctrl.spaceThresholdPercent
+	reclaimable := db.DbSize - db.DbSizeInUse
+	fragmented := reclaimable >= minReclaimableBytes && reclaimable*100 >= db.DbSize*ctrl.fragmentationThresholdPercent
+	if (noSpace || spaceExceeded || fragmented) && ctrl.remediationAllowed(ctx, key, karmada) {
+		// The history is only compacted when the space is running out, the compactions of the
+		// karmada-apiserver are enough otherwise.
+		db, err = ctrl.remediate(ctx, karmada, client, endpoint, db, noSpace || spaceExceeded)
+		if err != nil {
+			condition.Status, condition.Reason, condition.Message = metav1.ConditionTrue, "RemediationFailed", err.Error()
+			return condition
+		}
+		if noSpace && db.DbSize < quota {
+			if err := ctrl.disarmNoSpace(ctx, karmada, client, alarms); err != nil {
+				condition.Status, condition.Reason, condition.Message = metav1.ConditionTrue, "RemediationFailed", err.Error()
+				return condition
+			}
+			noSpace = false
+		}
+	}
+
+	switch {
+	case db.DbSizeInUse*100 >= quota*ctrl.spaceThresholdPercent:
+		condition.Status, condition.Reason = metav1.ConditionTrue, "SpaceExhausted"
+		condition.Message = fmt.Sprintf("the data in use of etcd takes %d%% of its space quota %s, which can't be reclaimed by compactions and defragmentations: "+
+			"raise spec.etcd.local.quotaBackendBytes along with spec.etcd.local.storageSize, or delete the objects no longer needed from the karmada",
+			db.DbSizeInUse*100/quota, resource.NewQuantity(quota, resource.BinarySI))
+	case noSpace:
+		condition.Status, condition.Reason = metav1.ConditionTrue, "NoSpace"
+		condition.Message = fmt.Sprintf("etcd refuses the writes because of the NOSPACE alarm, which is disarmed once its database is defragmented below its space quota %s",
+			resource.NewQuantity(quota, resource.BinarySI))
+	default:
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionFalse, "EnoughSpace", "etcd has enough space"
+	}
+	return condition
+}
+
+// remediationAllowed returns whether the etcd of the karmada can be remediated now. The remediations
+// are skipped while the karmada is paused or reconciled in dry-run, and are run at most once per the
+// minimum remediation interval.
+func (ctrl *EtcdMaintenanceController) remediationAllowed(ctx context.Context, key string, karmada *installv1alpha1.Karmada) bool {
+	if karmada.Spec.Paused || dryrun.Enabled(ctx) {
+		return false
+	}
+	ctrl.lock.Lock()
+	defer ctrl.lock.Unlock()
+	if last, ok := ctrl.lastRemediations[key]; ok && time.Since(last) < ctrl.minRemediationInterval {
+		klog.V(4).InfoS("Etcd was remediated recently, skip remediating", "karmada", klog.KObj(karmada), "lastRemediation", last)
+		return false
+	}
+	ctrl.lastRemediations[key] = time.Now()
+	return true
+}
+
+// remediate compacts the history of etcd if compact is true, keeping its latest revisions within the
+// retention window, then defragments its database to reclaim the space freed. It returns the state of
+// etcd afterwards.
+func (ctrl *EtcdMaintenanceController) remediate(ctx context.Context, karmada *installv1alpha1.Karmada, client *clientv3.Client, endpoint string, db *clientv3.StatusResponse, compact bool) (*clientv3.StatusResponse, error) {
+	if revision := db.Header.Revision - ctrl.compactionRetentionRevisions; compact && revision > 0 {
+		// Only the watchers of the karmada-apiserver behind the retention window relist their resources.
+		compactCtx, cancel := context.WithTimeout(ctx, etcdMaintenanceTimeout)
+		_, err := client.Compact(compactCtx, revision, clientv3.WithCompactPhysical())
+		cancel()
+		if err != nil && !errors.Is(err, rpctypes.ErrCompacted) {
+			return nil, fmt.Errorf("failed to compact etcd: %v", err)
+		}
+		if err == nil {
+			ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "EtcdCompacted", "Compacted etcd up to the revision %d, keeping the latest %d revisions",
+				revision, ctrl.compactionRetentionRevisions)
+		}
+	}
+
+	defragCtx, cancel := context.WithTimeout(ctx, etcdMaintenanceTimeout)
+	_, err := client.Defragment(defragCtx, endpoint)
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to defragment etcd: %v", err)
+	}
+
+	statusCtx, cancel := context.WithTimeout(ctx, etcdRequestTimeout)
+	defer cancel()
+	defragmented, err := client.Status(statusCtx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the status of etcd: %v", err)
+	}
+	ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "EtcdDefragmented", "Defragmented the database of etcd from %s to %s",
+		resource.NewQuantity(db.DbSize, resource.BinarySI), resource.NewQuantity(defragmented.DbSize, resource.BinarySI))
+	return defragmented, nil
+}
+
+// disarmNoSpace disarms the NOSPACE alarms of etcd, so that etcd accepts the writes again.
+func (ctrl *EtcdMaintenanceController) disarmNoSpace(ctx context.Context, karmada *installv1alpha1.Karmada, client *clientv3.Client, alarms []*pb.AlarmMember) error {
+	ctx, cancel := context.WithTimeout(ctx, etcdRequestTimeout)
+	defer cancel()
+	for _, alarm := range alarms {
+		if alarm.Alarm != pb.AlarmType_NOSPACE {
+			continue
+		}
+		if _, err := client.AlarmDisarm(ctx, (*clientv3.AlarmMember)(alarm)); err != nil {
+			return fmt.Errorf("failed to disarm the NOSPACE alarm of etcd: %v", err)
+		}
+	}
+	ctrl.eventRecorder.Event(karmada, corev1.EventTypeNormal, "EtcdAlarmDisarmed", "Disarmed the NOSPACE alarm of etcd")
+	return nil
+}
+
+// inspect returns the status and the active alarms of etcd.
+func inspect(ctx context.Context, client *clientv3.Client, endpoint string) (*clientv3.StatusResponse, []*pb.AlarmMember, error) {
+	ctx, cancel := context.WithTimeout(ctx, etcdRequestTimeout)
+	defer cancel()
+	status, err := client.Status(ctx, endpoint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the status of etcd: %v", err)
+	}
+	alarms, err := client.AlarmList(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list the alarms of etcd: %v", err)
+	}
+	return status, alarms.Alarms, nil
+}
+
+// hasAlarm returns whether the alarm of the given type is active.
+func hasAlarm(alarms []*pb.AlarmMember, alarmType pb.AlarmType) bool {
+	for _, alarm := range alarms {
+		if alarm.Alarm == alarmType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+// defaultTargetCPUUtilization is the average CPU utilization targeted by the autoscaled
+// components if no metrics are specified.
+const defaultTargetCPUUtilization = 80
+
+// preserveAutoscaledReplicas keeps the current replicas of an autoscaled deployment, so that the replicas
+// chosen by its HorizontalPodAutoscaler are not reset on every reconciliation. A new deployment starts
+// with the min replicas.
+func (ctrl *KarmadaController) preserveAutoscaledReplicas(ctx context.Context, deployment *appsv1.Deployment, autoscaling *installv1alpha1.Autoscaling) error {
+	if autoscaling == nil {
+		return nil
+	}
+
+	deployment.Spec.Replicas = minReplicas(autoscaling)
+	current, err := ctrl.client.AppsV1().Deployments(deployment.Namespace).Get(ctx, deployment.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if current.Spec.Replicas != nil {
+		deployment.Spec.Replicas = current.Spec.Replicas
+	}
+	return nil
+}
+
+// EnsureAutoscaling creates or updates the HorizontalPodAutoscaler of the component if it's autoscaled,
+// otherwise deletes it.
+func (ctrl *KarmadaController) EnsureAutoscaling(ctx context.Context, karmada *installv1alpha1.Karmada, component string, autoscaling *installv1alpha1.Autoscaling) error {
+	if autoscaling == nil {
+		err := ctrl.client.AutoscalingV2().HorizontalPodAutoscalers(karmada.Namespace).Delete(ctx, component, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "HorizontalPodAutoscaler", Namespace: karmada.Namespace, Name: component}, err)
+		return client.IgnoreNotFound(err)
+	}
+
+	hpa, err := horizontalPodAutoscaler(karmada, component, autoscaling)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateHorizontalPodAutoscaler(ctx, ctrl.client, hpa)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, hpa, result)
+	return err
+}
+
+// horizontalPodAutoscaler returns the HorizontalPodAutoscaler which scales the deployment of the component.
+func horizontalPodAutoscaler(karmada *installv1alpha1.Karmada, component string, autoscaling *installv1alpha1.Autoscaling) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	min := minReplicas(autoscaling)
+	if *min > autoscaling.MaxReplicas {
+		return nil, fmt.Errorf("the min replicas %d of %s is greater than its max replicas %d", *min, component, autoscaling.MaxReplicas)
+	}
+
+	metrics := autoscaling.Metrics
+	if len(metrics) == 0 {
+		metrics = []autoscalingv2.MetricSpec{
+			{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name: corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{
+						Type:               autoscalingv2.UtilizationMetricType,
+						AverageUtilization: pointer.Int32(defaultTargetCPUUtilization),
+					},
+				},
+			},
+		}
+	}
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "autoscaling/v2",
+			Kind:       "HorizontalPodAutoscaler",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      component,
+			Namespace: karmada.Namespace,
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       component,
+			},
+			MinReplicas: min,
+			MaxReplicas: autoscaling.MaxReplicas,
+			Metrics:     metrics,
+			Behavior:    autoscaling.Behavior,
+		},
+	}
+	util.SetKarmadaInstanceLabel(hpa, karmada.Name)
+	controllerutil.SetOwnerReference(karmada, hpa, scheme.Scheme)
+	if err := patchutil.Apply(hpa, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return hpa, nil
+}
+
+// minReplicas returns the min replicas of the autoscaled component.
+func minReplicas(autoscaling *installv1alpha1.Autoscaling) *int32 {
+	if autoscaling.MinReplicas != nil {
+		return pointer.Int32(*autoscaling.MinReplicas)
+	}
+	return pointer.Int32(1)
+}
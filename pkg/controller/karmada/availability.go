@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/availability"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+// ensureDeployment creates or updates the deployment of a component along with its HorizontalPodAutoscaler
+// if it's autoscaled. The pods of the component are spread and protected by a PodDisruptionBudget according
+// to the availability policy of the karmada if it may run more than one replica. The changes of its pod
+// template are rolled out according to the update strategy of the karmada.
+func (ctrl *KarmadaController) ensureDeployment(ctx context.Context, karmada *installv1alpha1.Karmada, deployment *appsv1.Deployment, autoscaling *installv1alpha1.Autoscaling) error {
+	if err := ctrl.preserveAutoscaledReplicas(ctx, deployment, autoscaling); err != nil {
+		return err
+	}
+	if err := ctrl.pinImages(ctx, karmada, &deployment.Spec.Template.Spec); err != nil {
+		return err
+	}
+	selector := excludeCanaryPods(deployment.Spec.Selector)
+	deployment.Spec.Selector = selector
+	maxReplicas := availability.MaxReplicas(deployment.Spec.Replicas, autoscaling)
+	availability.SpreadPods(karmada.Spec.AvailabilityPolicy, &deployment.Spec.Template, deployment.Spec.Selector, maxReplicas)
+	if rollout, err := ctrl.rolloutDeployment(ctx, karmada, deployment); err != nil || !rollout {
+		return err
+	}
+
+	result, err := clientutil.CreateOrUpdateDeployment(ctx, ctrl.client, deployment)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, deployment, result)
+	if err != nil {
+		return err
+	}
+	if err := ctrl.EnsurePodDisruptionBudget(ctx, karmada, deployment.Name, deployment.Labels, selector, maxReplicas); err != nil {
+		return err
+	}
+	return ctrl.EnsureAutoscaling(ctx, karmada, deployment.Name, autoscaling)
+}
+
+// EnsurePodDisruptionBudget creates or updates the PodDisruptionBudget of the pods selected by the selector,
+// e.g. the ones of a component or of its canary, if the availability policy of the karmada applies to them,
+// otherwise deletes it.
+func (ctrl *KarmadaController) EnsurePodDisruptionBudget(ctx context.Context, karmada *installv1alpha1.Karmada, name string, labels map[string]string, selector *metav1.LabelSelector, maxReplicas int32) error {
+	pdb := availability.PodDisruptionBudget(karmada.Spec.AvailabilityPolicy, karmada.Namespace, name, labels, selector, maxReplicas)
+	if pdb == nil {
+		return ctrl.RemovePodDisruptionBudget(ctx, karmada, name)
+	}
+
+	controllerutil.SetOwnerReference(karmada, pdb, scheme.Scheme)
+	if err := patchutil.Apply(pdb, karmada.Spec.Patches); err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdatePodDisruptionBudget(ctx, ctrl.client, pdb)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, pdb, result)
+	return err
+}
+
+// RemovePodDisruptionBudget deletes the PodDisruptionBudget of the component, if any.
+func (ctrl *KarmadaController) RemovePodDisruptionBudget(ctx context.Context, karmada *installv1alpha1.Karmada, component string) error {
+	err := ctrl.client.PolicyV1().PodDisruptionBudgets(karmada.Namespace).Delete(ctx, component, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "PodDisruptionBudget", Namespace: karmada.Namespace, Name: component}, err)
+	return client.IgnoreNotFound(err)
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/util/apply"
+)
+
+const (
+	// BootstrapVersionLabel is the label of the bootstrapped objects whose value is the karmada version
+	// they're applied for. The karmada-system namespace is labeled last, so that its label tells the
+	// version whose bootstrap has completed.
+	BootstrapVersionLabel = "install.firefly.io/bootstrap-version"
+
+	// bootstrapFieldManager is the field manager of the bootstrapped objects.
+	bootstrapFieldManager = "firefly-karmada-controller"
+)
+
+// EnsureKarmadaBootstrap applies the CRDs of spec.karmadaVersion, the system namespaces and the
+// default RBAC into the karmada-apiserver. The objects are applied when the karmada is installed
+// and every time it's upgraded, so that the CRDs of the new version replace the old ones.
+func (ctrl *KarmadaController) EnsureKarmadaBootstrap(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	client, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	version := karmada.Spec.KarmadaVersion
+	ns, err := client.CoreV1().Namespaces().Get(ctx, constants.KarmadaSystemNamespace, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && ns.Labels[BootstrapVersionLabel] == version {
+		return nil
+	}
+
+	crds, err := ctrl.crdFetcher.Fetch(ctx, version)
+	if err != nil {
+		return err
+	}
+	systemObjs, err := karmadaSystemObjects()
+	if err != nil {
+		return err
+	}
+
+	applier, err := apply.NewApplier(clientConfig, bootstrapFieldManager)
+	if err != nil {
+		return err
+	}
+	// the karmada-system namespace comes last, see BootstrapVersionLabel.
+	for _, obj := range append(crds, systemObjs...) {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[BootstrapVersionLabel] = version
+		obj.SetLabels(labels)
+		if _, err := applier.Apply(ctx, obj); err != nil {
+			return err
+		}
+	}
+	ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "Bootstrapped", "Applied %d CRDs and the system resources of karmada %s", len(crds), version)
+	return nil
+}
+
+// karmadaAPIGroups are the api groups served by karmada, which the default ClusterRoles grant access to.
+var karmadaAPIGroups = []string{
+	"cluster.karmada.io",
+	"config.karmada.io",
+	"networking.karmada.io",
+	"policy.karmada.io",
+	"work.karmada.io",
+	"multicluster.x-k8s.io",
+}
+
+// karmadaSystemObjects returns the system namespaces and the default RBAC of karmada, that are
+//   - cluster-proxy-admin, which allows the admin to access the member clusters through the
+//     proxy of the clusters.
+//   - karmada-view and karmada-edit, which aggregate the karmada api groups into the view, edit
+//     and admin ClusterRoles, e.g. the one bound to the read-only kubeconfig.
+//
+// The karmada-system namespace is the last object.
+func karmadaSystemObjects() ([]*unstructured.Unstructured, error) {
+	objs := []runtime.Object{
+		&corev1.Namespace{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: constants.KarmadaClusterNamespace},
+		},
+		&rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-proxy-admin"},
+			Rules: []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{"cluster.karmada.io"},
+					Resources: []string{"clusters/proxy"},
+					Verbs:     []string{"*"},
+				},
+			},
+		},
+		&rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-proxy-admin"},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "ClusterRole",
+				Name:     "cluster-proxy-admin",
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					APIGroup: "rbac.authorization.k8s.io",
+					Kind:     "User",
+					Name:     "system:admin",
+				},
+			},
+		},
+		&rbacv1.ClusterRole{
+			TypeMeta: metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "karmada-view",
+				Labels: map[string]string{
+					"rbac.authorization.k8s.io/aggregate-to-view":  "true",
+					"rbac.authorization.k8s.io/aggregate-to-edit":  "true",
+					"rbac.authorization.k8s.io/aggregate-to-admin": "true",
+				},
+			},
+			Rules: []rbacv1.PolicyRule{
+				{
+					APIGroups: karmadaAPIGroups,
+					Resources: []string{"*"},
+					Verbs:     []string{"get", "list", "watch"},
+				},
+			},
+		},
+		&rbacv1.ClusterRole{
+			TypeMeta: metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "karmada-edit",
+				Labels: map[string]string{
+					"rbac.authorization.k8s.io/aggregate-to-edit":  "true",
+					"rbac.authorization.k8s.io/aggregate-to-admin": "true",
+				},
+			},
+			Rules: []rbacv1.PolicyRule{
+				{
+					APIGroups: karmadaAPIGroups,
+					Resources: []string{"*"},
+					Verbs:     []string{"create", "update", "patch", "delete", "deletecollection"},
+				},
+			},
+		},
+		&corev1.Namespace{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: constants.KarmadaSystemNamespace},
+		},
+	}
+
+	return unstructuredObjects(objs...)
+}
+
+// unstructuredObjects converts the typed objects to be applied into the karmada-apiserver.
+func unstructuredObjects(objs ...runtime.Object) ([]*unstructured.Unstructured, error) {
+	var unstructuredObjs []*unstructured.Unstructured
+	for _, obj := range objs {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %T to unstructured: %v", obj, err)
+		}
+		u := &unstructured.Unstructured{Object: content}
+		// the applied configuration only holds the fields which are set.
+		unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+		unstructuredObjs = append(unstructuredObjs, u)
+	}
+	return unstructuredObjs, nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/util/capabilities"
+)
+
+// EnsureHostCapabilities reports the optional capabilities of the host cluster the karmada relies on,
+// and how the karmada is installed according to them, in the status of the karmada.
+func (ctrl *KarmadaController) EnsureHostCapabilities(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	reported := []installv1alpha1.HostCapability{ctrl.hostCapability(capabilities.PodMonitors,
+		"the monitored components are scraped by PodMonitors",
+		"the monitored components are annotated for scraping")}
+	if karmada.Spec.APIServer.Gateway != nil {
+		fallback := "the karmada-apiserver is not exposed"
+		if karmada.Spec.APIServer.Ingress != nil {
+			fallback = "the karmada-apiserver is exposed through the Ingress"
+		}
+		reported = append(reported, ctrl.hostCapability(capabilities.GatewayAPI,
+			"the karmada-apiserver is exposed through a TLSRoute", fallback))
+	}
+	if karmada.Spec.APIServer.Ingress != nil && !ctrl.exposedThroughGateway(karmada) {
+		reported = append(reported, ctrl.hostCapability(capabilities.Ingresses,
+			"the karmada-apiserver is exposed through the Ingress", "the karmada-apiserver is not exposed"))
+	}
+
+	return ctrl.updateKarmadaStatus(ctx, karmada, func(status *installv1alpha1.KarmadaStatus) {
+		status.HostCapabilities = reported
+	})
+}
+
+// hostCapability returns the status of the capability, whose message tells the effect of the
+// capability on the karmada and why it is or isn't available.
+func (ctrl *KarmadaController) hostCapability(capability capabilities.Capability, available, unavailable string) installv1alpha1.HostCapability {
+	status := installv1alpha1.HostCapability{
+		Name:      string(capability),
+		Available: ctrl.hostCapabilities.Has(capability),
+		Message:   unavailable,
+	}
+	if status.Available {
+		status.Message = available
+	}
+	status.Message += " since " + ctrl.hostCapabilities.Reason(capability)
+	return status
+}
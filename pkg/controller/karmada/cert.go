@@ -18,15 +18,19 @@ package karmada
 
 import (
 	"context"
+	"crypto"
+	"crypto/x509"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/apimachinery/pkg/util/sets"
 	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
 	"k8s.io/klog/v2"
 	netutils "k8s.io/utils/net"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -35,7 +39,9 @@ import (
 	"github.com/carlory/firefly/pkg/constants"
 	"github.com/carlory/firefly/pkg/scheme"
 	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
 	"github.com/carlory/firefly/pkg/util/certs"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
 )
 
 var certList = []string{
@@ -49,7 +55,28 @@ var certList = []string{
 	"front-proxy-client",
 }
 
-func (ctrl *KarmadaController) genCerts(karmada *installv1alpha1.Karmada, karmadaAPIServerIP []net.IP) error {
+// certSecretNames returns the names of the Secrets holding the certificates generated by genCerts.
+func certSecretNames() []string {
+	return []string{"karmada-kubeconfig", fmt.Sprintf("%s-cert", constants.KarmadaComponentEtcd), "karmada-cert"}
+}
+
+// genCerts generates the CA and the certificates of the karmada, and creates the Secrets holding them
+// which don't exist yet. The existing ones are kept, since they're renewed by others, so nothing is
+// generated if all of them exist.
+func (ctrl *KarmadaController) genCerts(ctx context.Context, karmada *installv1alpha1.Karmada, karmadaAPIServerIP []net.IP) error {
+	missing := sets.NewString()
+	for _, name := range certSecretNames() {
+		_, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			missing.Insert(name)
+		} else if err != nil {
+			return err
+		}
+	}
+	if missing.Len() == 0 {
+		return nil
+	}
+
 	notAfter := time.Now().Add(certs.Duration365d).UTC()
 
 	var etcdServerCertDNS = []string{
@@ -69,32 +96,22 @@ func (ctrl *KarmadaController) genCerts(karmada *installv1alpha1.Karmada, karmad
 	etcdServerCertConfig := certs.NewCertConfig("karmada-etcd-server", []string{}, etcdServerAltNames, &notAfter)
 	etcdClientCertCfg := certs.NewCertConfig("karmada-etcd-client", []string{}, certutil.AltNames{}, &notAfter)
 
-	var karmadaDNS = []string{
-		"localhost",
-		"kubernetes",
-		"kubernetes.default",
-		"kubernetes.default.svc",
-		constants.KarmadaComponentKubeAPIServer,
-		constants.KarmadaComponentWebhook,
-		constants.KarmadaComponentAggregratedAPIServer,
-		fmt.Sprintf("%s.%s.svc", constants.KarmadaComponentKubeAPIServer, karmada.Namespace),
-		fmt.Sprintf("%s.%s.svc.%s", constants.KarmadaComponentKubeAPIServer, karmada.Namespace, karmada.Spec.Networking.DNSDomain),
-		fmt.Sprintf("%s.%s.svc.%s", constants.KarmadaComponentWebhook, karmada.Namespace, karmada.Spec.Networking.DNSDomain),
-		fmt.Sprintf("%s.%s.svc", constants.KarmadaComponentWebhook, karmada.Namespace),
-		fmt.Sprintf("%s.%s.svc.%s", constants.KarmadaComponentAggregratedAPIServer, karmada.Namespace, karmada.Spec.Networking.DNSDomain),
-		fmt.Sprintf("*.%s.svc.%s", karmada.Namespace, karmada.Spec.Networking.DNSDomain),
-		fmt.Sprintf("*.%s.svc", karmada.Namespace),
-	}
+	karmadaDNS := karmadaAPIServerDNSNames(karmada)
 
 	karmadaIPs := []net.IP{}
-	karmadaIPs = append(
-		karmadaIPs,
-		netutils.ParseIPSloppy("127.0.0.1"),
-		netutils.ParseIPSloppy("10.254.0.1"),
-	)
+	for _, ip := range karmadaAPIServerIPs() {
+		karmadaIPs = append(karmadaIPs, netutils.ParseIPSloppy(ip))
+	}
 	if len(karmadaAPIServerIP) > 0 {
 		karmadaIPs = append(karmadaIPs, karmadaAPIServerIP...)
 	}
+	for _, san := range apiServerCertSANs(karmada) {
+		if ip := netutils.ParseIPSloppy(san); ip != nil {
+			karmadaIPs = append(karmadaIPs, ip)
+		} else {
+			karmadaDNS = append(karmadaDNS, san)
+		}
+	}
 
 	internetIP, err := util.InternetIP()
 	if err != nil {
@@ -118,17 +135,8 @@ func (ctrl *KarmadaController) genCerts(karmada *installv1alpha1.Karmada, karmad
 	}
 
 	// Create kubeconfig Secret
-	karmadaServerURL := fmt.Sprintf("https://%s.%s.svc.%s:%v", constants.KarmadaComponentKubeAPIServer, karmada.Namespace, karmada.Spec.Networking.DNSDomain, 5443)
-	config := certs.CreateWithCerts(karmadaServerURL, "karmada-admin", "karmada-admin", data["ca.crt"], data["karmada.key"], data["karmada.crt"])
-	configBytes, err := clientcmd.Write(*config)
+	kubeConfigSecret, err := KarmadaKubeconfigSecret(karmada, data["ca.crt"], data["karmada.key"], data["karmada.crt"])
 	if err != nil {
-		return fmt.Errorf("failure while serializing admin kubeConfig. %v", err)
-	}
-
-	kubeConfigSecret := SecretFromSpec(karmada.Namespace, "karmada-kubeconfig", corev1.SecretTypeOpaque, map[string]string{"kubeconfig": string(configBytes)})
-	controllerutil.SetOwnerReference(karmada, kubeConfigSecret, scheme.Scheme)
-	_, err = ctrl.client.CoreV1().Secrets(karmada.Namespace).Create(context.TODO(), kubeConfigSecret, metav1.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
 		return err
 	}
 
@@ -140,9 +148,9 @@ func (ctrl *KarmadaController) genCerts(karmada *installv1alpha1.Karmada, karmad
 		"etcd-server.key": string(data["etcd-server.key"]),
 	}
 	etcdSecret := SecretFromSpec(karmada.Namespace, fmt.Sprintf("%s-cert", constants.KarmadaComponentEtcd), corev1.SecretTypeOpaque, etcdCert)
+	util.SetKarmadaInstanceLabel(etcdSecret, karmada.Name)
 	controllerutil.SetOwnerReference(karmada, etcdSecret, scheme.Scheme)
-	_, err = ctrl.client.CoreV1().Secrets(karmada.Namespace).Create(context.TODO(), etcdSecret, metav1.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
+	if err := encryptSecret(ctx, karmada, etcdSecret); err != nil {
 		return err
 	}
 
@@ -152,24 +160,195 @@ func (ctrl *KarmadaController) genCerts(karmada *installv1alpha1.Karmada, karmad
 		karmadaCert[fmt.Sprintf("%s.key", v)] = string(data[fmt.Sprintf("%s.key", v)])
 	}
 	karmadaSecret := SecretFromSpec(karmada.Namespace, "karmada-cert", corev1.SecretTypeOpaque, karmadaCert)
+	if sans := apiServerCertSANs(karmada); len(sans) > 0 {
+		karmadaSecret.Annotations = map[string]string{constants.CertSANsAnnotation: strings.Join(sans, ",")}
+	}
+	util.SetKarmadaInstanceLabel(karmadaSecret, karmada.Name)
 	controllerutil.SetOwnerReference(karmada, karmadaSecret, scheme.Scheme)
-	_, err = ctrl.client.CoreV1().Secrets(karmada.Namespace).Create(context.TODO(), karmadaSecret, metav1.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
+	if err := encryptSecret(ctx, karmada, karmadaSecret); err != nil {
 		return err
 	}
-	karmadaWebhookCert := map[string]string{
-		"tls.crt": string(data["karmada.crt"]),
-		"tls.key": string(data["karmada.key"]),
+
+	for _, secret := range []*corev1.Secret{kubeConfigSecret, etcdSecret, karmadaSecret} {
+		if !missing.Has(secret.Name) {
+			continue
+		}
+		result, err := clientutil.CreateOrUpdateSecret(ctx, ctrl.client, secret)
+		clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, secret, result)
+		if err != nil {
+			return err
+		}
+	}
+	if missing.Has(karmadaSecret.Name) {
+		ctrl.eventRecorder.Event(karmada, corev1.EventTypeNormal, "CertificatesGenerated", "Generated the CA and certificates of karmada")
+	}
+	return nil
+}
+
+// karmadaAPIServerDNSNames returns the DNS names the certificate of the karmada-apiserver is always signed for.
+func karmadaAPIServerDNSNames(karmada *installv1alpha1.Karmada) []string {
+	return []string{
+		"localhost",
+		"kubernetes",
+		"kubernetes.default",
+		"kubernetes.default.svc",
+		constants.KarmadaComponentKubeAPIServer,
+		constants.KarmadaComponentWebhook,
+		constants.KarmadaComponentAggregratedAPIServer,
+		fmt.Sprintf("%s.%s.svc", constants.KarmadaComponentKubeAPIServer, karmada.Namespace),
+		fmt.Sprintf("%s.%s.svc.%s", constants.KarmadaComponentKubeAPIServer, karmada.Namespace, karmada.Spec.Networking.DNSDomain),
+		fmt.Sprintf("%s.%s.svc.%s", constants.KarmadaComponentWebhook, karmada.Namespace, karmada.Spec.Networking.DNSDomain),
+		fmt.Sprintf("%s.%s.svc", constants.KarmadaComponentWebhook, karmada.Namespace),
+		fmt.Sprintf("%s.%s.svc.%s", constants.KarmadaComponentAggregratedAPIServer, karmada.Namespace, karmada.Spec.Networking.DNSDomain),
+		fmt.Sprintf("*.%s.svc.%s", karmada.Namespace, karmada.Spec.Networking.DNSDomain),
+		fmt.Sprintf("*.%s.svc", karmada.Namespace),
 	}
-	karmadaWebhookSecret := SecretFromSpec(karmada.Namespace, fmt.Sprintf("%s-cert", constants.KarmadaComponentWebhook), corev1.SecretTypeOpaque, karmadaWebhookCert)
-	controllerutil.SetOwnerReference(karmada, karmadaWebhookSecret, scheme.Scheme)
-	_, err = ctrl.client.CoreV1().Secrets(karmada.Namespace).Create(context.TODO(), karmadaWebhookSecret, metav1.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
+}
+
+// karmadaAPIServerIPs returns the IPs the certificate of the karmada-apiserver is always signed for.
+func karmadaAPIServerIPs() []string {
+	return []string{"127.0.0.1", "10.254.0.1"}
+}
+
+// apiServerCertSANs returns the normalized custom SANs of the certificate of the karmada-apiserver, which are
+// spec.apiServer.certSANs along with the deprecated spec.apiServer.kubeAPIServer.certSANs.
+func apiServerCertSANs(karmada *installv1alpha1.Karmada) []string {
+	return normalizedCertSANs(append(append([]string(nil), karmada.Spec.APIServer.CertSANs...), karmada.Spec.APIServer.KubeAPIServer.CertSANs...))
+}
+
+// resignCertForHosts re-signs the certificate with the CA if some of the given hosts are not in its SANs,
+// the new certificate keeps the subject, the SANs and the expiry of the old one. It returns nil data if
+// the certificate already has all of the hosts.
+func resignCertForHosts(certData []byte, caCert *x509.Certificate, caKey crypto.Signer, hosts []string) ([]byte, []byte, error) {
+	return resignCertWithSANs(certData, caCert, caKey, hosts, nil)
+}
+
+// resignCertWithSANs re-signs the certificate with the CA if the added hosts are not in its SANs or the
+// removed ones are, the new certificate keeps the subject, the other SANs and the expiry of the old one.
+// It returns nil data if the SANs of the certificate are unchanged.
+func resignCertWithSANs(certData []byte, caCert *x509.Certificate, caKey crypto.Signer, added, removed []string) ([]byte, []byte, error) {
+	parsed, err := certutil.ParseCertsPEM(certData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the certificate: %v", err)
+	}
+	cert := parsed[0]
+
+	current := certSANs(cert)
+	desired := sets.NewString(current.List()...)
+	desired.Delete(removed...)
+	desired.Insert(normalizedCertSANs(added)...)
+	if desired.Equal(current) {
+		return nil, nil, nil
+	}
+
+	notAfter := cert.NotAfter
+	certCfg := certs.NewCertConfig(cert.Subject.CommonName, cert.Subject.Organization, altNamesForHosts(desired.List()), &notAfter)
+	newCert, newKey, err := certs.NewCertAndKey(caCert, caKey, certCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyData, err := keyutil.MarshalPrivateKeyToPEM(newKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return certs.EncodeCertPEM(newCert), keyData, nil
+}
+
+// altNamesForHosts returns the SANs of the given hosts, which are either IPs or DNS names.
+func altNamesForHosts(hosts []string) certutil.AltNames {
+	var altNames certutil.AltNames
+	for _, host := range hosts {
+		if ip := netutils.ParseIPSloppy(host); ip != nil {
+			altNames.IPs = append(altNames.IPs, ip)
+		} else if host != "" {
+			altNames.DNSNames = append(altNames.DNSNames, host)
+		}
+	}
+	certs.RemoveDuplicateAltNames(&altNames)
+	return altNames
+}
+
+// certSANs returns the DNS names and the IPs of the SANs of the certificate.
+func certSANs(cert *x509.Certificate) sets.String {
+	sans := sets.NewString(cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans.Insert(ip.String())
+	}
+	return sans
+}
+
+// normalizedCertSANs returns the sorted and deduplicated SANs, with the IPs in their canonical form so that
+// they're comparable with the ones of a certificate.
+func normalizedCertSANs(sans []string) []string {
+	normalized := sets.NewString()
+	for _, san := range sans {
+		if ip := netutils.ParseIPSloppy(san); ip != nil {
+			normalized.Insert(ip.String())
+		} else if san != "" {
+			normalized.Insert(san)
+		}
+	}
+	return normalized.List()
+}
+
+// EnsureAPIServerCertSANs re-signs the certificate of the karmada-apiserver with the karmada CA if its custom
+// SANs, see apiServerCertSANs, are changed since it was signed, the removed SANs are dropped from it unless
+// it's always signed for them. The karmada-apiserver reloads it automatically.
+func (ctrl *KarmadaController) EnsureAPIServerCertSANs(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	certSecret, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, "karmada-cert", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	desired := apiServerCertSANs(karmada)
+	var signed []string
+	if value := certSecret.Annotations[constants.CertSANsAnnotation]; value != "" {
+		signed = strings.Split(value, ",")
+	}
+	if sets.NewString(signed...).Equal(sets.NewString(desired...)) {
+		return nil
+	}
+
+	caCert, caKey, err := parseCA(certSecret)
+	if err != nil {
+		return err
+	}
+	// the DNS names and the IPs the certificate is always signed for are kept even if they were custom SANs.
+	removed := sets.NewString(signed...).Difference(sets.NewString(desired...)).
+		Delete(karmadaAPIServerDNSNames(karmada)...).Delete(karmadaAPIServerIPs()...).List()
+	certData, keyData, err := resignCertWithSANs(certSecret.Data["apiserver.crt"], caCert, caKey, desired, removed)
+	if err != nil {
 		return err
 	}
+
+	if certData != nil {
+		certSecret.Data["apiserver.crt"] = certData
+		certSecret.Data["apiserver.key"] = keyData
+	}
+	if len(desired) > 0 {
+		metav1.SetMetaDataAnnotation(&certSecret.ObjectMeta, constants.CertSANsAnnotation, strings.Join(desired, ","))
+	} else {
+		delete(certSecret.Annotations, constants.CertSANsAnnotation)
+	}
+	_, err = ctrl.client.CoreV1().Secrets(karmada.Namespace).Update(ctx, certSecret, metav1.UpdateOptions{})
+	audit.RecordResult(ctx, audit.Update, certSecret, err)
+	if err != nil {
+		return err
+	}
+	if certData != nil {
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "CertificateRotated", "Re-signed the certificate of %s for the SANs %v", constants.KarmadaComponentKubeAPIServer, desired)
+	}
 	return nil
 }
 
+// parseCA parses the karmada CA held by the karmada-cert Secret.
+func parseCA(certSecret *corev1.Secret) (*x509.Certificate, crypto.Signer, error) {
+	caCert, caKey, err := certs.ParseCA(certSecret.Data["ca.crt"], certSecret.Data["ca.key"])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid karmada CA: %v", err)
+	}
+	return caCert, caKey, nil
+}
+
 func SecretFromSpec(namespace, name string, secretType corev1.SecretType, data map[string]string) *corev1.Secret {
 	return &corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
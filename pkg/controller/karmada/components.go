@@ -0,0 +1,43 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+)
+
+// optionalComponents maps the optional components of a karmada to whether they're enabled by its spec.
+var optionalComponents = map[string]func(*installv1alpha1.Karmada) bool{
+	constants.KarmadaComponentDescheduler:               karmadaDeschedulerEnabled,
+	constants.KarmadaComponentSearch:                    karmadaSearchEnabled,
+	constants.KarmadaComponentMetricsAdapter:            karmadaMetricsAdapterEnabled,
+	constants.KarmadaComponentKubeControllerManager:     kubeControllerManagerEnabled,
+	constants.FireflyComponentKarmadaManager:            fireflyKarmadaManagerEnabled,
+	constants.KarmadaComponentMulticlusterCloudProvider: multiclusterCloudProviderEnabled,
+}
+
+// ComponentDisabled returns whether the component is optional and disabled by the spec of the karmada,
+// so that the host cluster resources named after it, e.g. its deployment and service, are not desired.
+// The components of a karmada installed from a chart are decided by the chart, so none is disabled.
+func ComponentDisabled(karmada *installv1alpha1.Karmada, component string) bool {
+	if karmada.Spec.Chart != nil {
+		return false
+	}
+	enabled, ok := optionalComponents[component]
+	return ok && !enabled(karmada)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	phaseutil "github.com/carlory/firefly/pkg/util/phase"
+)
+
+// reconcileStep reconciles a part of the karmada, e.g. a component or a group of components.
+type reconcileStep func(ctx context.Context, karmada *installv1alpha1.Karmada) error
+
+// sharedKarmada is the karmada whose steps are reconciled concurrently. Each of the steps works on
+// its own copy of the karmada, while the updates of the status are serialized and applied to the
+// shared karmada, so that they neither race nor conflict with each other.
+type sharedKarmada struct {
+	lock    sync.Mutex
+	karmada *installv1alpha1.Karmada
+	// rollout is the rollout status of the karmada when its reconciliation started, the components
+	// decide whether their changes are held back by it.
+	rollout *installv1alpha1.RolloutStatus
+	// held is true if the changes of any component are held back by the update strategy.
+	held bool
+}
+
+type sharedKarmadaKey struct{}
+
+// withSharedKarmada returns a context in which the steps reconciling the karmada share its status.
+func withSharedKarmada(ctx context.Context, karmada *installv1alpha1.Karmada) context.Context {
+	return context.WithValue(ctx, sharedKarmadaKey{}, &sharedKarmada{karmada: karmada, rollout: karmada.Status.Rollout.DeepCopy()})
+}
+
+// holdRollout records that the changes of a component are held back, e.g. until its canary is available.
+func holdRollout(ctx context.Context) {
+	if shared, ok := ctx.Value(sharedKarmadaKey{}).(*sharedKarmada); ok {
+		shared.lock.Lock()
+		shared.held = true
+		shared.lock.Unlock()
+	}
+}
+
+// rolloutHeld returns true if the changes of any component are held back.
+func rolloutHeld(ctx context.Context) bool {
+	shared, ok := ctx.Value(sharedKarmadaKey{}).(*sharedKarmada)
+	if !ok {
+		return false
+	}
+	shared.lock.Lock()
+	defer shared.lock.Unlock()
+	return shared.held
+}
+
+// reconcileConcurrently runs the independent steps concurrently and waits for them. The first error
+// cancels the context of the other steps and is returned. The steps may reconcile their own
+// independent steps concurrently as well.
+func (ctrl *KarmadaController) reconcileConcurrently(ctx context.Context, karmada *installv1alpha1.Karmada, steps ...reconcileStep) error {
+	shared, nested := ctx.Value(sharedKarmadaKey{}).(*sharedKarmada)
+	if !nested {
+		ctx = withSharedKarmada(ctx, karmada)
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	for _, step := range steps {
+		step, stepKarmada := step, karmada.DeepCopy()
+		g.Go(func() error {
+			return step(ctx, stepKarmada)
+		})
+	}
+	err := g.Wait()
+
+	if nested && shared.karmada != karmada {
+		// the status may have been updated by the steps, pick it up for the following steps.
+		shared.lock.Lock()
+		karmada.Status = *shared.karmada.Status.DeepCopy()
+		karmada.ResourceVersion = shared.karmada.ResourceVersion
+		shared.lock.Unlock()
+	}
+	return err
+}
+
+// updateKarmadaStatus applies mutate to the status of the karmada and updates the status if it's changed.
+// If the karmada is reconciled concurrently, the update is serialized with the ones of the other steps.
+func (ctrl *KarmadaController) updateKarmadaStatus(ctx context.Context, karmada *installv1alpha1.Karmada, mutate func(status *installv1alpha1.KarmadaStatus)) error {
+	target := karmada
+	if shared, ok := ctx.Value(sharedKarmadaKey{}).(*sharedKarmada); ok {
+		shared.lock.Lock()
+		defer shared.lock.Unlock()
+		target = shared.karmada
+	}
+
+	oldStatus := target.Status.DeepCopy()
+	mutate(&target.Status)
+	phaseutil.SetKarmadaPhase(target)
+	if !equality.Semantic.DeepEqual(oldStatus, &target.Status) {
+		updated, err := ctrl.fireflyClient.InstallV1alpha1().Karmadas(target.Namespace).UpdateStatus(ctx, target, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		target.ResourceVersion = updated.ResourceVersion
+	}
+
+	if target != karmada {
+		karmada.Status = *target.Status.DeepCopy()
+		karmada.ResourceVersion = target.ResourceVersion
+	}
+	return nil
+}
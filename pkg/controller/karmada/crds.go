@@ -17,32 +17,193 @@ limitations under the License.
 package karmada
 
 import (
-	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/cli-runtime/pkg/resource"
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
-	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
-	utilresource "github.com/carlory/firefly/pkg/util/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
 )
 
-func (ctrl *KarmadaController) EnsureKarmadaCRDs(karmada *installv1alpha1.Karmada) error {
-	clientConfig, err := ctrl.GenerateClientConfig(karmada)
+const (
+	// karmadaCRDsURL is the url of the CRD bundle of a karmada release. It's downloaded if the
+	// CRDs of the version aren't bundled with firefly.
+	karmadaCRDsURL = "https://github.com/karmada-io/karmada/releases/download/%s/crds.tar.gz"
+
+	// maxCRDsSize is the max size of a downloaded CRD bundle.
+	maxCRDsSize = 32 << 20
+)
+
+// bundledCRDs holds the CRDs of the karmada versions shipped with firefly, in crds/<version>/*.yaml.
+//
+//go:embed crds
+var bundledCRDs embed.FS
+
+// crdFetcher loads the CRDs of a karmada version from the bundled ones, or downloads them from
+// the karmada release. Downloaded CRDs are cached in memory.
+type crdFetcher struct {
+	httpClient *http.Client
+
+	lock  sync.Mutex
+	cache map[string][]*unstructured.Unstructured
+}
+
+// newCRDFetcher returns a new *crdFetcher.
+func newCRDFetcher() *crdFetcher {
+	return &crdFetcher{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		cache:      map[string][]*unstructured.Unstructured{},
+	}
+}
+
+// Fetch returns the CRDs of the karmada version. The returned objects are copies, so they
+// may be mutated by the caller.
+func (f *crdFetcher) Fetch(ctx context.Context, version string) ([]*unstructured.Unstructured, error) {
+	f.lock.Lock()
+	crds, ok := f.cache[version]
+	f.lock.Unlock()
+	if !ok {
+		var err error
+		crds, err = bundledKarmadaCRDs(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			crds, err = f.download(ctx, version)
+		}
+		if err != nil {
+			return nil, err
+		}
+		f.lock.Lock()
+		f.cache[version] = crds
+		f.lock.Unlock()
+	}
+
+	copied := make([]*unstructured.Unstructured, 0, len(crds))
+	for _, crd := range crds {
+		copied = append(copied, crd.DeepCopy())
+	}
+	return copied, nil
+}
+
+// bundledKarmadaCRDs returns the bundled CRDs of the karmada version. fs.ErrNotExist is returned
+// if the version isn't bundled.
+func bundledKarmadaCRDs(version string) ([]*unstructured.Unstructured, error) {
+	dir := path.Join("crds", version)
+	entries, err := bundledCRDs.ReadDir(dir)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	result := utilresource.NewBuilder(clientConfig).
-		Unstructured().
-		FilenameParam(false, &resource.FilenameOptions{Recursive: false, Filenames: []string{"./pkg/controller/karmada/crds"}}).
-		Flatten().Do()
-	return result.Visit(func(info *resource.Info, err error) error {
+	var crds []*unstructured.Unstructured
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+		data, err := bundledCRDs.ReadFile(path.Join(dir, entry.Name()))
 		if err != nil {
-			return err
+			return nil, err
 		}
-		_, err1 := resource.NewHelper(info.Client, info.Mapping).Create(info.Namespace, true, info.Object)
-		if err1 != nil {
-			if !errors.IsAlreadyExists(err1) {
-				return err1
+		objs, err := decodeCRDs(entry.Name(), data)
+		if err != nil {
+			return nil, err
+		}
+		crds = append(crds, objs...)
+	}
+	return crds, nil
+}
+
+// download downloads the CRD bundle of the karmada release. Only the CRDs in crds/bases are
+// used, the kustomize patches of the bundle which configure the conversion webhooks are not.
+func (f *crdFetcher) download(ctx context.Context, version string) ([]*unstructured.Unstructured, error) {
+	url := fmt.Sprintf(karmadaCRDsURL, version)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the CRDs of karmada %s: %v", version, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download the CRDs of karmada %s from %s: %s", version, url, resp.Status)
+	}
+
+	gz, err := gzip.NewReader(io.LimitReader(resp.Body, maxCRDsSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CRDs of karmada %s: %v", version, err)
+	}
+	defer gz.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CRDs of karmada %s: %v", version, err)
+		}
+		name := path.Clean(header.Name)
+		// the CRDs are in crds/bases, or its subdirectories per api group since karmada v1.5.
+		if header.Typeflag != tar.TypeReg || !strings.Contains("/"+name, "/bases/") || !strings.HasSuffix(name, ".yaml") {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CRDs of karmada %s: %v", version, err)
+		}
+		files[name] = data
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no CRDs are found in %s", url)
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var crds []*unstructured.Unstructured
+	for _, name := range names {
+		objs, err := decodeCRDs(name, files[name])
+		if err != nil {
+			return nil, err
+		}
+		crds = append(crds, objs...)
+	}
+	return crds, nil
+}
+
+// decodeCRDs decodes the CustomResourceDefinitions of the yaml file.
+func decodeCRDs(name string, data []byte) ([]*unstructured.Unstructured, error) {
+	var crds []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if err == io.EOF {
+				break
 			}
+			return nil, fmt.Errorf("failed to decode %s: %v", name, err)
 		}
-		return nil
-	})
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetKind() != "CustomResourceDefinition" {
+			return nil, fmt.Errorf("%s contains a %s, only CustomResourceDefinitions are expected", name, obj.GetKind())
+		}
+		crds = append(crds, obj)
+	}
+	return crds, nil
 }
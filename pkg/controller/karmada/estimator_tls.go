@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+)
+
+// estimatorClientTLSMountPath is the path where the client certificate of the estimators is mounted.
+const estimatorClientTLSMountPath = "/etc/estimator-client-tls"
+
+// estimatorMutualTLS returns whether the karmada-scheduler and the karmada-descheduler connect to the
+// estimators with mTLS.
+func estimatorMutualTLS(karmada *installv1alpha1.Karmada) bool {
+	estimator := karmada.Spec.Scheduler.KarmadaSchedulerEstimator
+	return pointer.BoolDeref(estimator.Enable, true) && estimator.MutualTLS
+}
+
+// setEstimatorClientTLSArgs sets the flags by which the component connects to the estimators with the
+// client certificate issued by the estimator controller, if mTLS is enabled.
+func setEstimatorClientTLSArgs(karmada *installv1alpha1.Karmada, args map[string]string) {
+	if !estimatorMutualTLS(karmada) {
+		return
+	}
+	args["scheduler-estimator-ca-file"] = estimatorClientTLSMountPath + "/ca.crt"
+	args["scheduler-estimator-cert-file"] = estimatorClientTLSMountPath + "/tls.crt"
+	args["scheduler-estimator-key-file"] = estimatorClientTLSMountPath + "/tls.key"
+}
+
+// mountEstimatorClientTLS mounts the client certificate of the estimators into the containers of the pod,
+// if mTLS is enabled. The Secret is optional since it's only issued once a member cluster is synced by the
+// estimator controller; the certificate is read whenever an estimator is connected, so the rotated one is
+// picked up without restarts.
+func mountEstimatorClientTLS(karmada *installv1alpha1.Karmada, podSpec *corev1.PodSpec) {
+	if !estimatorMutualTLS(karmada) {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "estimator-client-tls",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: constants.EstimatorClientCertSecret,
+				Optional:   pointer.Bool(true),
+			},
+		},
+	})
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      "estimator-client-tls",
+			MountPath: estimatorClientTLSMountPath,
+			ReadOnly:  true,
+		})
+	}
+}
@@ -17,32 +17,70 @@ limitations under the License.
 package karmada
 
 import (
+	"context"
 	"fmt"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
 	"github.com/carlory/firefly/pkg/constants"
 	"github.com/carlory/firefly/pkg/scheme"
 	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/certs"
 	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/kms"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
 )
 
-func (ctrl *KarmadaController) EnsureEtcd(karmada *installv1alpha1.Karmada) error {
-	if err := ctrl.EnsureEtcdService(karmada); err != nil {
+const (
+	// etcdDataVolumeName is the name of the volume claim template of the data of etcd.
+	etcdDataVolumeName = "etcd-data"
+	// etcdDataDir is the data directory of etcd.
+	etcdDataDir = "/var/lib/etcd"
+	// etcdDefaultQuotaBytes is the default space quota of the etcd database, which is the default of etcd.
+	etcdDefaultQuotaBytes = 2 << 30
+	// etcdExternalServiceName is the name of the Service exposing etcd to the additional host clusters.
+	etcdExternalServiceName = "etcd-external"
+)
+
+func (ctrl *KarmadaController) EnsureEtcd(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if err := ctrl.EnsureEtcdService(ctx, karmada); err != nil {
+		return err
+	}
+	if err := ctrl.EnsureEtcdExternalService(ctx, karmada); err != nil {
+		return err
+	}
+	if err := ctrl.EnsureEtcdStatefulSet(ctx, karmada); err != nil {
 		return err
 	}
-	if err := ctrl.EnsureEtcdStatefulSet(karmada); err != nil {
+	if err := ctrl.EnsureEtcdCertSANs(ctx, karmada); err != nil {
+		return err
+	}
+	if err := ctrl.EnsureEtcdDefragmentation(ctx, karmada); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (ctrl *KarmadaController) EnsureEtcdService(karmada *installv1alpha1.Karmada) error {
+func (ctrl *KarmadaController) EnsureEtcdService(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	svc, err := etcdService(karmada)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateService(ctx, ctrl.client, svc)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, svc, result)
+	return err
+}
+
+// etcdService returns the etcd service of the karmada.
+func etcdService(karmada *installv1alpha1.Karmada) (*corev1.Service, error) {
 	etcdName := constants.KarmadaComponentEtcd
 	svc := &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -79,29 +117,172 @@ func (ctrl *KarmadaController) EnsureEtcdService(karmada *installv1alpha1.Karmad
 			},
 		},
 	}
+	util.SetKarmadaInstanceLabel(svc, karmada.Name)
 	controllerutil.SetOwnerReference(karmada, svc, scheme.Scheme)
-	return clientutil.CreateOrUpdateService(ctrl.client, svc)
+	if err := patchutil.Apply(svc, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return svc, nil
 }
 
-func (ctrl *KarmadaController) EnsureEtcdStatefulSet(karmada *installv1alpha1.Karmada) error {
-	etcdName := constants.KarmadaComponentEtcd
-	etcd := karmada.Spec.Etcd.Local
-	repository := karmada.Spec.ImageRepository
-	if karmada.Spec.KubeImageRepository != "" {
-		repository = karmada.Spec.KubeImageRepository
+// EnsureEtcdExternalService exposes the local etcd to the apiservers and the etcd members in the additional
+// host clusters through a LoadBalancer Service while its members are distributed, otherwise deletes the Service.
+func (ctrl *KarmadaController) EnsureEtcdExternalService(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if !etcdDistributed(karmada) {
+		err := ctrl.client.CoreV1().Services(karmada.Namespace).Delete(ctx, etcdExternalServiceName, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Service", Namespace: karmada.Namespace, Name: etcdExternalServiceName}, err)
+		return client.IgnoreNotFound(err)
 	}
+	svc, err := etcdExternalService(karmada)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateService(ctx, ctrl.client, svc)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, svc, result)
+	return err
+}
 
-	tag := "3.4.13-0"
-	imageName := "etcd"
-	if etcd != nil {
-		if etcd.ImageRepository != "" {
-			repository = etcd.ImageRepository
+// etcdExternalService returns the LoadBalancer Service which exposes the client and the peer ports of an
+// etcd member. The clients and the peers are still authenticated by the etcd CA.
+func etcdExternalService(karmada *installv1alpha1.Karmada) (*corev1.Service, error) {
+	svc := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      etcdExternalServiceName,
+			Namespace: karmada.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": constants.KarmadaComponentEtcd},
+			Type:     corev1.ServiceTypeLoadBalancer,
+			Ports: []corev1.ServicePort{
+				{
+					Name:     "client",
+					Protocol: corev1.ProtocolTCP,
+					Port:     2379,
+					TargetPort: intstr.IntOrString{
+						Type:   intstr.Int,
+						IntVal: 2379,
+					},
+				},
+				{
+					Name:     "server",
+					Protocol: corev1.ProtocolTCP,
+					Port:     2380,
+					TargetPort: intstr.IntOrString{
+						Type:   intstr.Int,
+						IntVal: 2380,
+					},
+				},
+			},
+		},
+	}
+	util.SetKarmadaInstanceLabel(svc, karmada.Name)
+	controllerutil.SetOwnerReference(karmada, svc, scheme.Scheme)
+	if err := patchutil.Apply(svc, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// etcdExternalAddresses returns the load balancer addresses of the etcd-external Service.
+func (ctrl *KarmadaController) etcdExternalAddresses(ctx context.Context, karmada *installv1alpha1.Karmada) ([]string, error) {
+	svc, err := ctrl.client.CoreV1().Services(karmada.Namespace).Get(ctx, etcdExternalServiceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return loadBalancerAddresses(svc), nil
+}
+
+func (ctrl *KarmadaController) EnsureEtcdStatefulSet(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	sts, err := etcdStatefulSet(karmada)
+	if err != nil {
+		return err
+	}
+	ctrl.annotateForScraping(karmada, constants.KarmadaComponentEtcd, &sts.Spec.Template)
+
+	// the volume claim templates of a statefulset are immutable, so an installed etcd keeps its data volume.
+	got, err := ctrl.client.AppsV1().StatefulSets(sts.Namespace).Get(ctx, sts.Name, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		if len(got.Spec.VolumeClaimTemplates) != len(sts.Spec.VolumeClaimTemplates) {
+			ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "EtcdDataVolumeImmutable", "The data volume of etcd can't be changed once etcd is installed")
 		}
-		if etcd.ImageName != "" {
-			imageName = etcd.ImageName
+		setEtcdDataVolume(sts, got.Spec.VolumeClaimTemplates)
+		if err := ctrl.expandEtcdDataVolume(ctx, karmada); err != nil {
+			return err
 		}
-		if etcd.ImageTag != "" {
-			tag = etcd.ImageTag
+	}
+	if err := ctrl.pinImages(ctx, karmada, &sts.Spec.Template.Spec); err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateStatefulSet(ctx, ctrl.client, sts)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, sts, result)
+	return err
+}
+
+// etcdStatefulSet returns the etcd statefulset of the karmada.
+func etcdStatefulSet(karmada *installv1alpha1.Karmada) (*appsv1.StatefulSet, error) {
+	etcdName := constants.KarmadaComponentEtcd
+	etcd := karmada.Spec.Etcd.Local
+
+	peerScheme := "http"
+	distributed := etcdDistributed(karmada)
+	if distributed {
+		peerScheme = "https"
+	}
+	listenPeerURL := peerScheme + "://0.0.0.0:2380"
+	peerURL := fmt.Sprintf("%s://%s-0.%s.%s.svc:2380", peerScheme, etcdName, etcdName, karmada.Namespace)
+	hostNetwork := etcdHostNetwork(karmada)
+	if hostNetwork {
+		// the peers are addressed by the IP of the node, and the peer port isn't bound on the other
+		// interfaces of the node since the peer traffic isn't encrypted unless the members are distributed.
+		listenPeerURL = peerScheme + "://$(POD_IP):2380"
+		peerURL = peerScheme + "://$(POD_IP):2380"
+	}
+
+	command := []string{
+		"/usr/local/bin/etcd",
+		"--name",
+		localEtcdMemberName,
+		"--listen-peer-urls",
+		listenPeerURL,
+		"--listen-client-urls",
+		"https://0.0.0.0:2379",
+		"--advertise-client-urls",
+		fmt.Sprintf("https://%s.%s.svc:2379", etcdName, karmada.Namespace),
+		"--initial-cluster",
+		localEtcdMemberName + "=" + peerURL,
+		"--initial-cluster-state",
+		"new",
+		"--cert-file=/etc/etcd/pki/etcd-server.crt",
+		"--client-cert-auth=true",
+		"--key-file=/etc/etcd/pki/etcd-server.key",
+		"--trusted-ca-file=/etc/etcd/pki/etcd-ca.crt",
+		"--data-dir=" + etcdDataDir,
+	}
+	if monitored(karmada, etcdName) {
+		command = append(command, fmt.Sprintf("--listen-metrics-urls=http://0.0.0.0:%d", etcdMetricsPort))
+	}
+	if etcd != nil && etcd.QuotaBackendBytes != nil {
+		command = append(command, fmt.Sprintf("--quota-backend-bytes=%d", etcd.QuotaBackendBytes.Value()))
+	}
+	if distributed {
+		// the peers in the additional host clusters come through the load balancers, so the peer traffic
+		// is encrypted and the peers are authenticated by the etcd CA. The peer URL advertised here only
+		// bootstraps etcd, the member is reached at the one of its load balancer afterwards.
+		command = append(command,
+			"--peer-cert-file=/etc/etcd/pki/etcd-server.crt",
+			"--peer-key-file=/etc/etcd/pki/etcd-server.key",
+			"--peer-trusted-ca-file=/etc/etcd/pki/etcd-ca.crt",
+			"--peer-client-cert-auth=true",
+		)
+		if !hostNetwork {
+			command = append(command, "--initial-advertise-peer-urls", peerURL)
 		}
 	}
 
@@ -128,28 +309,9 @@ func (ctrl *KarmadaController) EnsureEtcdStatefulSet(karmada *installv1alpha1.Ka
 					Containers: []corev1.Container{
 						{
 							Name:            "etcd",
-							Image:           util.ComponentImageName(repository, imageName, tag),
+							Image:           etcdImage(karmada),
 							ImagePullPolicy: "IfNotPresent",
-							Command: []string{
-								"/usr/local/bin/etcd",
-								"--name",
-								"etcd0",
-								"--listen-peer-urls",
-								"http://0.0.0.0:2380",
-								"--listen-client-urls",
-								"https://0.0.0.0:2379",
-								"--advertise-client-urls",
-								fmt.Sprintf("https://%s.%s.svc:2379", etcdName, karmada.Namespace),
-								"--initial-cluster",
-								fmt.Sprintf("etcd0=http://%s-0.%s.%s.svc:2380", etcdName, etcdName, karmada.Namespace),
-								"--initial-cluster-state",
-								"new",
-								"--cert-file=/etc/etcd/pki/etcd-server.crt",
-								"--client-cert-auth=true",
-								"--key-file=/etc/etcd/pki/etcd-server.key",
-								"--trusted-ca-file=/etc/etcd/pki/etcd-ca.crt",
-								"--data-dir=/var/lib/etcd",
-							},
+							Command:         command,
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      "etcd-certs",
@@ -172,6 +334,234 @@ func (ctrl *KarmadaController) EnsureEtcdStatefulSet(karmada *installv1alpha1.Ka
 			},
 		},
 	}
+	if etcd != nil {
+		sts.Spec.Template.Spec.NodeSelector = etcd.NodeSelector
+		sts.Spec.Template.Spec.Tolerations = etcd.Tolerations
+	}
+	if hostNetwork {
+		podSpec := &sts.Spec.Template.Spec
+		podSpec.HostNetwork = true
+		podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+		podSpec.Containers[0].Command = append(podSpec.Containers[0].Command, "--initial-advertise-peer-urls", peerURL)
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, corev1.EnvVar{
+			Name: "POD_IP",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+			},
+		})
+	}
+	if claim := etcdDataVolumeClaim(etcd); claim != nil {
+		setEtcdDataVolume(sts, []corev1.PersistentVolumeClaim{*claim})
+	}
+	util.SetKarmadaInstanceLabel(sts, karmada.Name)
 	controllerutil.SetOwnerReference(karmada, sts, scheme.Scheme)
-	return clientutil.CreateOrUpdateStatefulSet(ctrl.client, sts)
+	if err := patchutil.Apply(sts, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return sts, nil
+}
+
+// etcdHostNetwork returns whether the local etcd runs in the network of its node.
+func etcdHostNetwork(karmada *installv1alpha1.Karmada) bool {
+	etcd := karmada.Spec.Etcd.Local
+	return etcd != nil && etcd.NetworkingMode == installv1alpha1.EtcdNetworkingHostNetwork
+}
+
+// EnsureEtcdCertSANs re-signs the server certificate of etcd with the etcd CA if spec.etcd.local.serverCertSANs,
+// the IP of the node of etcd in the HostNetwork mode, or the addresses of the etcd-external Service, are not
+// in its SANs.
+func (ctrl *KarmadaController) EnsureEtcdCertSANs(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	etcd := karmada.Spec.Etcd.Local
+	if etcd == nil {
+		return nil
+	}
+	hosts := append([]string(nil), etcd.ServerCertSANs...)
+	if etcdHostNetwork(karmada) {
+		pod, err := ctrl.client.CoreV1().Pods(karmada.Namespace).Get(ctx, fmt.Sprintf("%s-0", constants.KarmadaComponentEtcd), metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		// the pod may still run in its own network until it's recreated by the rolling update.
+		if err == nil && pod.Spec.HostNetwork && pod.Status.HostIP != "" {
+			hosts = append(hosts, pod.Status.HostIP)
+		}
+	}
+	if etcdDistributed(karmada) {
+		addresses, err := ctrl.etcdExternalAddresses(ctx, karmada)
+		if err != nil {
+			return err
+		}
+		if len(addresses) == 0 {
+			return fmt.Errorf("waiting for the load balancer of %s to be provisioned", etcdExternalServiceName)
+		}
+		hosts = append(hosts, addresses...)
+	}
+	return ctrl.ensureEtcdServerCertHosts(ctx, karmada, hosts)
+}
+
+// ensureEtcdServerCertHosts re-signs the server certificate of etcd with the etcd CA if the hosts are not
+// in its SANs. The certificate is shared by all the etcd members, which reload it automatically.
+func (ctrl *KarmadaController) ensureEtcdServerCertHosts(ctx context.Context, karmada *installv1alpha1.Karmada, hosts []string) error {
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s-cert", constants.KarmadaComponentEtcd)
+	certSecret, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	caKeyData, err := kms.SecretData(ctx, certSecret, "etcd-ca.key")
+	if err != nil {
+		return err
+	}
+	caCert, caKey, err := certs.ParseCA(certSecret.Data["etcd-ca.crt"], caKeyData)
+	if err != nil {
+		return fmt.Errorf("invalid etcd CA: %v", err)
+	}
+	certData, keyData, err := resignCertForHosts(certSecret.Data["etcd-server.crt"], caCert, caKey, hosts)
+	if err != nil || certData == nil {
+		return err
+	}
+
+	certSecret.Data["etcd-server.crt"] = certData
+	certSecret.Data["etcd-server.key"] = keyData
+	_, err = ctrl.client.CoreV1().Secrets(karmada.Namespace).Update(ctx, certSecret, metav1.UpdateOptions{})
+	audit.RecordResult(ctx, audit.Update, certSecret, err)
+	if err != nil {
+		return err
+	}
+	ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "CertificateRotated", "Re-signed the certificate of %s for hosts %v", constants.KarmadaComponentEtcd, hosts)
+	return nil
+}
+
+// etcdImage returns the image of etcd, which is also used by the Jobs running etcdctl.
+func etcdImage(karmada *installv1alpha1.Karmada) string {
+	etcd := karmada.Spec.Etcd.Local
+	repository := karmada.Spec.ImageRepository
+	if karmada.Spec.KubeImageRepository != "" {
+		repository = karmada.Spec.KubeImageRepository
+	}
+
+	tag := "3.4.13-0"
+	imageName := "etcd"
+	if etcd != nil {
+		if etcd.ImageRepository != "" {
+			repository = etcd.ImageRepository
+		}
+		if etcd.ImageName != "" {
+			imageName = etcd.ImageName
+		}
+		if etcd.ImageTag != "" {
+			tag = etcd.ImageTag
+		}
+	}
+	return util.ComponentImageName(repository, imageName, tag)
+}
+
+// etcdDataVolumeClaim returns the volume claim template of the data volume of etcd built from DataVolume,
+// StorageSize and StorageClassName, or nil if etcd keeps its data in the container.
+func etcdDataVolumeClaim(etcd *installv1alpha1.LocalEtcd) *corev1.PersistentVolumeClaim {
+	if etcd == nil || (etcd.DataVolume == nil && etcd.StorageSize == nil) {
+		return nil
+	}
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: etcdDataVolumeName,
+		},
+	}
+	if etcd.DataVolume != nil {
+		claim.Labels = etcd.DataVolume.Labels
+		claim.Annotations = etcd.DataVolume.Annotations
+		claim.Spec = *etcd.DataVolume.Spec.DeepCopy()
+	}
+	if len(claim.Spec.AccessModes) == 0 {
+		claim.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	}
+	if etcd.StorageSize != nil {
+		if claim.Spec.Resources.Requests == nil {
+			claim.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		claim.Spec.Resources.Requests[corev1.ResourceStorage] = *etcd.StorageSize
+	}
+	if etcd.StorageClassName != nil {
+		claim.Spec.StorageClassName = etcd.StorageClassName
+	}
+	return claim
+}
+
+// EtcdQuotaBytes returns the space quota of the database of the local etcd.
+func EtcdQuotaBytes(karmada *installv1alpha1.Karmada) int64 {
+	if etcd := karmada.Spec.Etcd.Local; etcd != nil && etcd.QuotaBackendBytes != nil {
+		return etcd.QuotaBackendBytes.Value()
+	}
+	return etcdDefaultQuotaBytes
+}
+
+// expandEtcdDataVolume expands the data volume of the installed etcd to spec.etcd.local.storageSize. The
+// volume can't be shrunk, and it's only expanded if its storage class allows the expansion.
+func (ctrl *KarmadaController) expandEtcdDataVolume(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	etcd := karmada.Spec.Etcd.Local
+	if etcd == nil || etcd.StorageSize == nil {
+		return nil
+	}
+	name := fmt.Sprintf("%s-%s-0", etcdDataVolumeName, constants.KarmadaComponentEtcd)
+	pvc, err := ctrl.client.CoreV1().PersistentVolumeClaims(karmada.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	current := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	switch etcd.StorageSize.Cmp(current) {
+	case 0:
+		return nil
+	case -1:
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "EtcdDataVolumeShrinkUnsupported", "The data volume of etcd can't be shrunk from %s to %s", current.String(), etcd.StorageSize.String())
+		return nil
+	}
+
+	expanded := pvc.DeepCopy()
+	expanded.Spec.Resources.Requests[corev1.ResourceStorage] = *etcd.StorageSize
+	updated, err := ctrl.client.CoreV1().PersistentVolumeClaims(karmada.Namespace).Update(ctx, expanded, metav1.UpdateOptions{})
+	if errors.IsForbidden(err) || errors.IsInvalid(err) {
+		// the storage class doesn't allow the expansion, which is reported instead of failing the reconciliation.
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "EtcdDataVolumeExpansionFailed", "Failed to expand the data volume of etcd to %s: %v", etcd.StorageSize.String(), err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if audit.RecordUpdate(ctx, pvc, updated) {
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "EtcdDataVolumeExpanding", "Expanding the data volume of etcd from %s to %s", current.String(), etcd.StorageSize.String())
+	}
+	return nil
+}
+
+// setEtcdDataVolume sets the volume claim templates of the etcd statefulset, and mounts the data volume
+// to the data directory of etcd if there's one. Otherwise the data is kept by the container.
+func setEtcdDataVolume(sts *appsv1.StatefulSet, claims []corev1.PersistentVolumeClaim) {
+	sts.Spec.VolumeClaimTemplates = claims
+	container := &sts.Spec.Template.Spec.Containers[0]
+	mounts := container.VolumeMounts[:0]
+	for _, mount := range container.VolumeMounts {
+		if mount.Name != etcdDataVolumeName {
+			mounts = append(mounts, mount)
+		}
+	}
+	for _, claim := range claims {
+		if claim.Name == etcdDataVolumeName {
+			mounts = append(mounts, corev1.VolumeMount{Name: etcdDataVolumeName, MountPath: etcdDataDir})
+		}
+	}
+	container.VolumeMounts = mounts
+}
+
+// restoring returns whether the data volume of etcd is being restored from a backup, during which
+// the karmada isn't reconciled.
+func restoring(karmada *installv1alpha1.Karmada) bool {
+	restore := karmada.Status.Restore
+	return restore != nil && (restore.Phase == installv1alpha1.KarmadaRestoreScalingDown || restore.Phase == installv1alpha1.KarmadaRestoreRestoringVolumes)
 }
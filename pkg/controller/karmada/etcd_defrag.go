@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+// EtcdDefragCronJobName is the name of the CronJob running the scheduled defragmentations of etcd.
+const EtcdDefragCronJobName = "etcd-defrag"
+
+// etcdDefragmentationEnabled returns whether the defragmentations of the local etcd are scheduled.
+func etcdDefragmentationEnabled(karmada *installv1alpha1.Karmada) bool {
+	etcd := karmada.Spec.Etcd.Local
+	return karmada.Spec.Etcd.External == nil && etcd != nil && etcd.Defragmentation != nil
+}
+
+// EnsureEtcdDefragmentation applies the CronJob which defragments the database of the local etcd on
+// spec.etcd.local.defragmentation.schedule, otherwise deletes it.
+func (ctrl *KarmadaController) EnsureEtcdDefragmentation(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if !etcdDefragmentationEnabled(karmada) {
+		err := ctrl.client.BatchV1().CronJobs(karmada.Namespace).Delete(ctx, EtcdDefragCronJobName, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "CronJob", Namespace: karmada.Namespace, Name: EtcdDefragCronJobName}, err)
+		return client.IgnoreNotFound(err)
+	}
+
+	cronJob, err := etcdDefragCronJob(karmada)
+	if err != nil {
+		return err
+	}
+	if err := ctrl.pinImages(ctx, karmada, &cronJob.Spec.JobTemplate.Spec.Template.Spec); err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateCronJob(ctx, ctrl.client, cronJob)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, cronJob, result)
+	return err
+}
+
+// etcdDefragCronJob returns the CronJob defragmenting the database of the local etcd. The defragmentation
+// is followed by disarming the alarms of etcd, so that etcd accepts writes again once the space freed
+// by the compactions is reclaimed after it raised the NOSPACE alarm.
+func etcdDefragCronJob(karmada *installv1alpha1.Karmada) (*batchv1.CronJob, error) {
+	etcdctl := func(args ...string) []string {
+		return append([]string{
+			"etcdctl",
+			fmt.Sprintf("--endpoints=https://%s.%s.svc:2379", constants.KarmadaComponentEtcd, karmada.Namespace),
+			"--cacert=/etc/kubernetes/pki/etcd-ca.crt",
+			"--cert=/etc/kubernetes/pki/etcd-client.crt",
+			"--key=/etc/kubernetes/pki/etcd-client.key",
+		}, args...)
+	}
+	container := func(name string, command []string) corev1.Container {
+		return corev1.Container{
+			Name:            name,
+			Image:           etcdImage(karmada),
+			ImagePullPolicy: "IfNotPresent",
+			Command:         command,
+			Env:             []corev1.EnvVar{{Name: "ETCDCTL_API", Value: "3"}},
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      "k8s-certs",
+					MountPath: "/etc/kubernetes/pki",
+					ReadOnly:  true,
+				},
+			},
+		}
+	}
+
+	var backoffLimit int32 = 2
+	cronJob := &batchv1.CronJob{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "CronJob",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      EtcdDefragCronJobName,
+			Namespace: karmada.Namespace,
+			Labels:    map[string]string{"app": EtcdDefragCronJobName},
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          karmada.Spec.Etcd.Local.Defragmentation.Schedule,
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{"app": EtcdDefragCronJobName},
+						},
+						Spec: corev1.PodSpec{
+							RestartPolicy:  corev1.RestartPolicyOnFailure,
+							InitContainers: []corev1.Container{container("defrag", etcdctl("defrag", "--command-timeout=5m"))},
+							Containers:     []corev1.Container{container("disarm-alarms", etcdctl("alarm", "disarm"))},
+							Volumes: []corev1.Volume{
+								{
+									Name: "k8s-certs",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{
+											SecretName: "karmada-cert",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	util.SetKarmadaInstanceLabel(cronJob, karmada.Name)
+	controllerutil.SetOwnerReference(karmada, cronJob, scheme.Scheme)
+	if err := patchutil.Apply(cronJob, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return cronJob, nil
+}
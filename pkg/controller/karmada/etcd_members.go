@@ -0,0 +1,479 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/util"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+)
+
+const (
+	// localEtcdMemberName is the name of the etcd member in the host cluster of the karmada.
+	localEtcdMemberName = "etcd0"
+	// etcdRequestTimeout bounds the connection and the requests to the local etcd.
+	etcdRequestTimeout = 10 * time.Second
+)
+
+// etcdDistributed returns whether the members of the local etcd of the karmada are distributed across
+// its host clusters. They're distributed as long as any member is left in an additional host cluster,
+// so that the local member keeps reaching it until it's removed.
+func etcdDistributed(karmada *installv1alpha1.Karmada) bool {
+	if karmada.Spec.Etcd.Local == nil {
+		return false
+	}
+	if multiHostClusterEnabled(karmada) {
+		return true
+	}
+	for _, status := range karmada.Status.HostClusters {
+		if status.EtcdPeerURL != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// LocalEtcdEndpoint returns the client endpoint of the etcd member in the host cluster of the karmada.
+func LocalEtcdEndpoint(karmada *installv1alpha1.Karmada) string {
+	return fmt.Sprintf("https://%s.%s.svc:2379", constants.KarmadaComponentEtcd, karmada.Namespace)
+}
+
+// NewLocalEtcdClient returns a client of the etcd member in the host cluster of the karmada, which is
+// authenticated by the etcd client certificate of the karmada.
+func NewLocalEtcdClient(ctx context.Context, client kubernetes.Interface, karmada *installv1alpha1.Karmada) (*clientv3.Client, error) {
+	certSecret, err := client.CoreV1().Secrets(karmada.Namespace).Get(ctx, "karmada-cert", metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the etcd client certificate: %v", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(certSecret.Data["etcd-ca.crt"]) {
+		return nil, fmt.Errorf("failed to parse the etcd CA")
+	}
+	cert, err := tls.X509KeyPair(certSecret.Data["etcd-client.crt"], certSecret.Data["etcd-client.key"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the etcd client certificate: %v", err)
+	}
+	etcdClient, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{LocalEtcdEndpoint(karmada)},
+		DialTimeout: etcdRequestTimeout,
+		Context:     ctx,
+		Logger:      zap.NewNop(),
+		TLS: &tls.Config{
+			RootCAs:      roots,
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to etcd: %v", err)
+	}
+	return etcdClient, nil
+}
+
+// ensureLocalEtcdPeerURL makes the etcd member in the host cluster of the karmada advertise the peer URL
+// of its load balancer, through which the members in the additional host clusters reach it.
+func (ctrl *KarmadaController) ensureLocalEtcdPeerURL(ctx context.Context, karmada *installv1alpha1.Karmada, etcdClient *clientv3.Client) error {
+	addresses, err := ctrl.etcdExternalAddresses(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	if len(addresses) == 0 {
+		return fmt.Errorf("waiting for the load balancer of %s to be provisioned", etcdExternalServiceName)
+	}
+	peerURL := etcdPeerURL(addresses[0])
+
+	ctx, cancel := context.WithTimeout(ctx, etcdRequestTimeout)
+	defer cancel()
+	members, err := etcdClient.MemberList(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the etcd members: %v", err)
+	}
+	for _, member := range members.Members {
+		if member.Name != localEtcdMemberName {
+			continue
+		}
+		if len(member.PeerURLs) == 1 && member.PeerURLs[0] == peerURL {
+			return nil
+		}
+		if dryrun.Enabled(ctx) {
+			klog.V(2).InfoS("Skipped updating the peer URL of the etcd member in dry-run", "karmada", klog.KObj(karmada), "member", member.Name, "peerURL", peerURL)
+			return nil
+		}
+		if _, err := etcdClient.MemberUpdate(ctx, member.ID, []string{peerURL}); err != nil {
+			return fmt.Errorf("failed to update the peer URL of the etcd member %s: %v", member.Name, err)
+		}
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "EtcdMemberUpdated", "Updated the peer URL of the etcd member %s to %s", member.Name, peerURL)
+		return nil
+	}
+	return fmt.Errorf("the etcd member %s is not found", localEtcdMemberName)
+}
+
+// ensureHostClusterEtcd runs an etcd member in the host cluster of the status, and records its peer URL into
+// it. The member joins etcd as a learner and is promoted once it has caught up with the leader, so that the
+// quorum isn't affected while it's catching up, unless it's kept as a learner by etcdVoter. It returns the
+// etcd servers of the apiservers in the host cluster, which are the local etcd member if it votes and the one
+// in the host cluster of the karmada, or empty if etcd is external.
+func (ctrl *KarmadaController) ensureHostClusterEtcd(ctx context.Context, karmada *installv1alpha1.Karmada, hostClient kubernetes.Interface, status *installv1alpha1.KarmadaHostClusterStatus) (string, error) {
+	if karmada.Spec.Etcd.Local == nil {
+		return "", nil
+	}
+	localAddresses, err := ctrl.etcdExternalAddresses(ctx, karmada)
+	if err != nil {
+		return "", err
+	}
+	if len(localAddresses) == 0 {
+		return "", fmt.Errorf("waiting for the load balancer of %s to be provisioned", etcdExternalServiceName)
+	}
+	remoteEtcdServer := fmt.Sprintf("https://%s:2379", localAddresses[0])
+	etcdServers := fmt.Sprintf("%s,%s", LocalEtcdEndpoint(karmada), remoteEtcdServer)
+
+	for _, render := range []func(*installv1alpha1.Karmada) (*corev1.Service, error){
+		etcdService,
+		etcdExternalService,
+	} {
+		svc, err := render(karmada)
+		if err != nil {
+			return "", err
+		}
+		svc.OwnerReferences = nil
+		if _, err := clientutil.CreateOrUpdateService(ctx, hostClient, svc); err != nil {
+			return "", err
+		}
+	}
+	svc, err := hostClient.CoreV1().Services(karmada.Namespace).Get(ctx, etcdExternalServiceName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	addresses := loadBalancerAddresses(svc)
+	if len(addresses) == 0 {
+		return "", fmt.Errorf("waiting for the load balancer of %s to be provisioned", etcdExternalServiceName)
+	}
+	// the server certificate is shared by the members, so it's valid for the load balancers of all of them.
+	if err := ctrl.ensureEtcdServerCertHosts(ctx, karmada, addresses); err != nil {
+		return "", err
+	}
+
+	etcdClient, err := NewLocalEtcdClient(ctx, ctrl.client, karmada)
+	if err != nil {
+		return "", err
+	}
+	defer etcdClient.Close()
+	if err := ctrl.ensureLocalEtcdPeerURL(ctx, karmada, etcdClient); err != nil {
+		return "", err
+	}
+
+	name := hostClusterEtcdMemberName(status.Name)
+	peerURL := etcdPeerURL(addresses[0])
+	member, members, err := ctrl.ensureHostClusterEtcdMember(ctx, karmada, etcdClient, name, status.EtcdPeerURL, peerURL)
+	if err != nil || member == nil {
+		return "", err
+	}
+	status.EtcdPeerURL = peerURL
+
+	local, err := ctrl.client.AppsV1().StatefulSets(karmada.Namespace).Get(ctx, constants.KarmadaComponentEtcd, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	initialCluster := etcdInitialCluster(members, member.ID, name)
+	got, err := hostClient.AppsV1().StatefulSets(karmada.Namespace).Get(ctx, constants.KarmadaComponentEtcd, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", err
+	}
+	if err == nil && len(got.Spec.Template.Spec.Containers) > 0 {
+		// the initial cluster only bootstraps the member, so it's kept rather than restarting the member
+		// whenever the other members change.
+		if current := commandFlag(got.Spec.Template.Spec.Containers[0].Command, "--initial-cluster"); current != "" {
+			initialCluster = current
+		}
+	}
+	sts := hostClusterEtcdStatefulSet(karmada, local, name, peerURL, fmt.Sprintf("https://%s:2379", addresses[0]), initialCluster)
+	if err := ctrl.copyHostClusterVolumes(ctx, karmada, hostClient, []corev1.PodSpec{sts.Spec.Template.Spec}); err != nil {
+		return "", err
+	}
+	if _, err := clientutil.CreateOrUpdateStatefulSet(ctx, hostClient, sts); err != nil {
+		return "", err
+	}
+
+	if !member.IsLearner {
+		return etcdServers, nil
+	}
+	if member.Name == "" {
+		return "", fmt.Errorf("waiting for the etcd member %s to start", name)
+	}
+	if !etcdVoter(karmada, status.Name) {
+		// a learner doesn't serve the requests of the apiservers.
+		return remoteEtcdServer, nil
+	}
+	promoteCtx, cancel := context.WithTimeout(ctx, etcdRequestTimeout)
+	defer cancel()
+	if _, err := etcdClient.MemberPromote(promoteCtx, member.ID); err != nil {
+		if errors.Is(err, rpctypes.ErrMemberLearnerNotReady) {
+			return "", fmt.Errorf("waiting for the etcd member %s to catch up with the leader", name)
+		}
+		return "", fmt.Errorf("failed to promote the etcd member %s: %v", name, err)
+	}
+	ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "EtcdMemberPromoted", "Promoted the etcd member %s in host cluster %s", name, status.Name)
+	return etcdServers, nil
+}
+
+// etcdVoter returns whether the etcd member in the additional host cluster is promoted to a voting member.
+// etcd needs a majority of its voters to be available, so an even number of them tolerates no more failures
+// than one less: if the number of the additional host clusters is odd, the member in the last one is kept as
+// a learner, e.g. the lone member of a single additional host cluster.
+func etcdVoter(karmada *installv1alpha1.Karmada, hostCluster string) bool {
+	clusters := karmada.Spec.HostClusters.Clusters
+	return len(clusters)%2 == 0 || clusters[len(clusters)-1].Name != hostCluster
+}
+
+// etcdVotersCondition returns the EtcdVotersBalanced condition of the karmada from the members of its local
+// etcd. A voter can't be demoted, so the number of voters is even if a host cluster is removed from an even
+// number of them, until another host cluster is added or removed.
+func (ctrl *KarmadaController) etcdVotersCondition(ctx context.Context, karmada *installv1alpha1.Karmada) (metav1.Condition, error) {
+	condition := metav1.Condition{Status: metav1.ConditionUnknown, Reason: "EtcdUnreachable"}
+	etcdClient, err := NewLocalEtcdClient(ctx, ctrl.client, karmada)
+	if err != nil {
+		condition.Message = err.Error()
+		return condition, err
+	}
+	defer etcdClient.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, etcdRequestTimeout)
+	defer cancel()
+	list, err := etcdClient.MemberList(ctx)
+	if err != nil {
+		err = fmt.Errorf("failed to list the etcd members: %v", err)
+		condition.Message = err.Error()
+		return condition, err
+	}
+	var voters, learners []string
+	for _, member := range list.Members {
+		name := member.Name
+		if name == "" && len(member.PeerURLs) > 0 {
+			// the member isn't started yet.
+			name = member.PeerURLs[0]
+		}
+		if member.IsLearner {
+			learners = append(learners, name)
+		} else {
+			voters = append(voters, name)
+		}
+	}
+	switch {
+	case len(voters)%2 == 0:
+		condition.Status, condition.Reason = metav1.ConditionFalse, "EvenVoters"
+		condition.Message = fmt.Sprintf("etcd has %d voting members %s, the loss of half of them makes it lose its quorum: add or remove a host cluster",
+			len(voters), strings.Join(voters, ", "))
+	case len(learners) > 0:
+		condition.Status, condition.Reason = metav1.ConditionTrue, "OddVotersWithLearners"
+		condition.Message = fmt.Sprintf("etcd has %d voting members, the members %s are non-voting learners",
+			len(voters), strings.Join(learners, ", "))
+	default:
+		condition.Status, condition.Reason = metav1.ConditionTrue, "OddVoters"
+		condition.Message = fmt.Sprintf("etcd has %d voting members", len(voters))
+	}
+	return condition, nil
+}
+
+// ensureHostClusterEtcdMember adds the etcd member of a host cluster as a learner unless it's a member already.
+// The member is found by its last recorded peer URL, which is updated if the address of its load balancer
+// changed. It returns the member along with all the members, or a nil member in dry-run if it's not added.
+func (ctrl *KarmadaController) ensureHostClusterEtcdMember(ctx context.Context, karmada *installv1alpha1.Karmada, etcdClient *clientv3.Client, name, lastPeerURL, peerURL string) (*etcdserverpb.Member, []*etcdserverpb.Member, error) {
+	ctx, cancel := context.WithTimeout(ctx, etcdRequestTimeout)
+	defer cancel()
+	list, err := etcdClient.MemberList(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list the etcd members: %v", err)
+	}
+	member := findEtcdMember(list.Members, peerURL)
+	if member == nil && lastPeerURL != "" {
+		member = findEtcdMember(list.Members, lastPeerURL)
+	}
+
+	switch {
+	case member != nil && (len(member.PeerURLs) != 1 || member.PeerURLs[0] != peerURL):
+		if dryrun.Enabled(ctx) {
+			klog.V(2).InfoS("Skipped updating the peer URL of the etcd member in dry-run", "karmada", klog.KObj(karmada), "member", name, "peerURL", peerURL)
+			return member, list.Members, nil
+		}
+		if _, err := etcdClient.MemberUpdate(ctx, member.ID, []string{peerURL}); err != nil {
+			return nil, nil, fmt.Errorf("failed to update the peer URL of the etcd member %s: %v", name, err)
+		}
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "EtcdMemberUpdated", "Updated the peer URL of the etcd member %s to %s", name, peerURL)
+		member.PeerURLs = []string{peerURL}
+	case member == nil:
+		if dryrun.Enabled(ctx) {
+			klog.V(2).InfoS("Skipped adding the etcd member in dry-run", "karmada", klog.KObj(karmada), "member", name, "peerURL", peerURL)
+			return nil, nil, nil
+		}
+		added, err := etcdClient.MemberAddAsLearner(ctx, []string{peerURL})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to add the etcd member %s: %v", name, err)
+		}
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "EtcdMemberAdded", "Added the etcd member %s at %s as a learner", name, peerURL)
+		return added.Member, added.Members, nil
+	}
+	return member, list.Members, nil
+}
+
+// removeHostClusterEtcdMember removes the etcd member with the peer URL, if any, before its host cluster is
+// cleaned up.
+func (ctrl *KarmadaController) removeHostClusterEtcdMember(ctx context.Context, karmada *installv1alpha1.Karmada, status installv1alpha1.KarmadaHostClusterStatus) error {
+	if status.EtcdPeerURL == "" || karmada.Spec.Etcd.Local == nil {
+		return nil
+	}
+	etcdClient, err := NewLocalEtcdClient(ctx, ctrl.client, karmada)
+	if err != nil {
+		return err
+	}
+	defer etcdClient.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, etcdRequestTimeout)
+	defer cancel()
+	list, err := etcdClient.MemberList(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the etcd members: %v", err)
+	}
+	member := findEtcdMember(list.Members, status.EtcdPeerURL)
+	if member == nil {
+		return nil
+	}
+	if dryrun.Enabled(ctx) {
+		klog.V(2).InfoS("Skipped removing the etcd member in dry-run", "karmada", klog.KObj(karmada), "peerURL", status.EtcdPeerURL)
+		return nil
+	}
+	if _, err := etcdClient.MemberRemove(ctx, member.ID); err != nil && !errors.Is(err, rpctypes.ErrMemberNotFound) {
+		return fmt.Errorf("failed to remove the etcd member at %s: %v", status.EtcdPeerURL, err)
+	}
+	ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "EtcdMemberRemoved", "Removed the etcd member at %s of host cluster %s", status.EtcdPeerURL, status.Name)
+	return nil
+}
+
+// hostClusterEtcdMemberName returns the name of the etcd member in the host cluster.
+func hostClusterEtcdMemberName(hostCluster string) string {
+	return fmt.Sprintf("%s-%s", constants.KarmadaComponentEtcd, hostCluster)
+}
+
+// etcdPeerURL returns the peer URL of the etcd member behind the load balancer with the address.
+func etcdPeerURL(address string) string {
+	return fmt.Sprintf("https://%s:2380", address)
+}
+
+// findEtcdMember returns the etcd member with the peer URL, or nil if there's none.
+func findEtcdMember(members []*etcdserverpb.Member, peerURL string) *etcdserverpb.Member {
+	for _, member := range members {
+		for _, url := range member.PeerURLs {
+			if url == peerURL {
+				return member
+			}
+		}
+	}
+	return nil
+}
+
+// etcdInitialCluster returns the initial cluster bootstrapping the member with the id and the name, which
+// has no name yet if it's not started. The other members which are not started are left out.
+func etcdInitialCluster(members []*etcdserverpb.Member, id uint64, name string) string {
+	var peers []string
+	for _, member := range members {
+		memberName := member.Name
+		if member.ID == id {
+			memberName = name
+		}
+		if memberName == "" {
+			continue
+		}
+		for _, url := range member.PeerURLs {
+			peers = append(peers, fmt.Sprintf("%s=%s", memberName, url))
+		}
+	}
+	return strings.Join(peers, ",")
+}
+
+// hostClusterEtcdStatefulSet returns the copy of the local etcd statefulset for the member in an additional
+// host cluster, which isn't owned by the karmada and joins the existing members of etcd. The member is reached
+// through the load balancer of its host cluster rather than the network of its node.
+func hostClusterEtcdStatefulSet(karmada *installv1alpha1.Karmada, local *appsv1.StatefulSet, name, peerURL, clientURL, initialCluster string) *appsv1.StatefulSet {
+	sts := &appsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "StatefulSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        local.Name,
+			Namespace:   local.Namespace,
+			Labels:      local.Labels,
+			Annotations: local.Annotations,
+		},
+		Spec: *local.Spec.DeepCopy(),
+	}
+	podSpec := &sts.Spec.Template.Spec
+	podSpec.HostNetwork = false
+	podSpec.DNSPolicy = corev1.DNSClusterFirst
+	container := &podSpec.Containers[0]
+	command := container.Command
+	command = setCommandFlag(command, "--name", name)
+	command = setCommandFlag(command, "--listen-peer-urls", "https://0.0.0.0:2380")
+	command = setCommandFlag(command, "--advertise-client-urls", clientURL)
+	command = setCommandFlag(command, "--initial-advertise-peer-urls", peerURL)
+	command = setCommandFlag(command, "--initial-cluster", initialCluster)
+	command = setCommandFlag(command, "--initial-cluster-state", "existing")
+	container.Command = command
+	util.SetKarmadaInstanceLabel(sts, karmada.Name)
+	return sts
+}
+
+// commandFlag returns the value of the flag given as a separate argument in the command.
+func commandFlag(command []string, flag string) string {
+	for i := 0; i+1 < len(command); i++ {
+		if command[i] == flag {
+			return command[i+1]
+		}
+	}
+	return ""
+}
+
+// setCommandFlag sets the value of the flag given as a separate argument in the command, or appends it.
+func setCommandFlag(command []string, flag, value string) []string {
+	for i := 0; i+1 < len(command); i++ {
+		if command[i] == flag {
+			command[i+1] = value
+			return command
+		}
+	}
+	return append(command, flag, value)
+}
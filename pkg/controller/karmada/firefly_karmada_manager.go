@@ -26,52 +26,120 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
 	"github.com/carlory/firefly/pkg/constants"
 	"github.com/carlory/firefly/pkg/scheme"
 	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/dryrun"
 	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
 	utilresource "github.com/carlory/firefly/pkg/util/resource"
 )
 
-func (ctrl *KarmadaController) EnsureFireflyKarmadaManager(karmada *installv1alpha1.Karmada) error {
-	if err := ctrl.EnsureFireflyKarmadaManagerServiceAccount(karmada); err != nil {
+func (ctrl *KarmadaController) EnsureFireflyKarmadaManager(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if !fireflyKarmadaManagerEnabled(karmada) {
+		return ctrl.RemoveFireflyKarmadaManager(ctx, karmada)
+	}
+	if err := ctrl.EnsureFireflyKarmadaManagerServiceAccount(ctx, karmada); err != nil {
 		return err
 	}
-	if err := ctrl.EnsureFireflyKarmadaManagerClusterRoleBinding(karmada); err != nil {
+	if err := ctrl.EnsureFireflyKarmadaManagerClusterRoleBinding(ctx, karmada); err != nil {
 		return err
 	}
-	if err := ctrl.EnsureFireflyKarmadaManagerRoleBinding(karmada); err != nil {
+	if err := ctrl.EnsureFireflyKarmadaManagerRoleBinding(ctx, karmada); err != nil {
 		return err
 	}
-	if err := ctrl.EnsureFireflyKarmadaManagerDeployment(karmada); err != nil {
+	if err := ctrl.EnsureFireflyKarmadaManagerDeployment(ctx, karmada); err != nil {
 		return err
 	}
-	return ctrl.EnsureFireflyKarmadaManagerCRDs(karmada)
+	return ctrl.EnsureFireflyKarmadaManagerCRDs(ctx, karmada)
+}
+
+// fireflyKarmadaManagerEnabled returns whether the firefly-karmada-manager is installed for the karmada.
+func fireflyKarmadaManagerEnabled(karmada *installv1alpha1.Karmada) bool {
+	return pointer.BoolDeref(karmada.Spec.ControllerManager.FireflyKarmadaManager.Enable, true)
 }
 
-func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerServiceAccount(karmada *installv1alpha1.Karmada) error {
+// RemoveFireflyKarmadaManager deletes the deployment of the firefly-karmada-manager and its permissions.
+// The CRDs it installed to the karmada-apiserver are kept, since they may still have objects.
+func (ctrl *KarmadaController) RemoveFireflyKarmadaManager(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	componentName := constants.FireflyComponentKarmadaManager
+	err := ctrl.client.AppsV1().Deployments(karmada.Namespace).Delete(ctx, componentName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Deployment", Namespace: karmada.Namespace, Name: componentName}, err)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	err = ctrl.client.RbacV1().RoleBindings(karmada.Namespace).Delete(ctx, componentName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "RoleBinding", Namespace: karmada.Namespace, Name: componentName}, err)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	clusterRoleBindingName := fireflyKarmadaManagerClusterRoleBindingName(karmada)
+	err = ctrl.client.RbacV1().ClusterRoleBindings().Delete(ctx, clusterRoleBindingName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "ClusterRoleBinding", Name: clusterRoleBindingName}, err)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	err = ctrl.client.CoreV1().ServiceAccounts(karmada.Namespace).Delete(ctx, componentName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "ServiceAccount", Namespace: karmada.Namespace, Name: componentName}, err)
+	return client.IgnoreNotFound(err)
+}
+
+func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerServiceAccount(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	sa, err := fireflyKarmadaManagerServiceAccount(karmada)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateServiceAccount(ctx, ctrl.client, sa)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, sa, result)
+	return err
+}
+
+// fireflyKarmadaManagerServiceAccount returns the firefly-karmada-manager service account of the karmada.
+func fireflyKarmadaManagerServiceAccount(karmada *installv1alpha1.Karmada) (*corev1.ServiceAccount, error) {
 	sa := &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      constants.FireflyComponentKarmadaManager,
 			Namespace: karmada.Namespace,
 		},
 	}
+	util.SetKarmadaInstanceLabel(sa, karmada.Name)
 	controllerutil.SetOwnerReference(karmada, sa, scheme.Scheme)
-	_, err := ctrl.client.CoreV1().ServiceAccounts(karmada.Namespace).Create(context.TODO(), sa, metav1.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
+	if err := patchutil.Apply(sa, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return sa, nil
+}
+
+// fireflyKarmadaManagerClusterRoleBindingName returns the name of the ClusterRoleBinding of the firefly-karmada-manager.
+// It's cluster scoped, so it's named after the namespace of the karmada.
+func fireflyKarmadaManagerClusterRoleBindingName(karmada *installv1alpha1.Karmada) string {
+	return fmt.Sprintf("%s-%s", constants.FireflyComponentKarmadaManager, karmada.Namespace)
+}
+
+func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerClusterRoleBinding(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	crb, err := fireflyKarmadaManagerClusterRoleBinding(karmada)
+	if err != nil {
 		return err
 	}
-	return nil
+	result, err := clientutil.CreateOrUpdateClusterRoleBinding(ctx, ctrl.client, crb)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, crb, result)
+	return err
 }
 
-func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerClusterRoleBinding(karmada *installv1alpha1.Karmada) error {
+// fireflyKarmadaManagerClusterRoleBinding returns the firefly-karmada-manager cluster role binding of the karmada.
+func fireflyKarmadaManagerClusterRoleBinding(karmada *installv1alpha1.Karmada) (*rbacv1.ClusterRoleBinding, error) {
 	crb := &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: fmt.Sprintf("%s-%s", constants.FireflyComponentKarmadaManager, karmada.Namespace),
+			Name: fireflyKarmadaManagerClusterRoleBindingName(karmada),
 		},
 		Subjects: []rbacv1.Subject{
 			{
@@ -86,15 +154,26 @@ func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerClusterRoleBinding(kar
 			APIGroup: "rbac.authorization.k8s.io",
 		},
 	}
+	util.SetKarmadaInstanceLabel(crb, karmada.Name)
 	controllerutil.SetOwnerReference(karmada, crb, scheme.Scheme)
-	_, err := ctrl.client.RbacV1().ClusterRoleBindings().Create(context.TODO(), crb, metav1.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
+	if err := patchutil.Apply(crb, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return crb, nil
+}
+
+func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerRoleBinding(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	rb, err := fireflyKarmadaManagerRoleBinding(karmada)
+	if err != nil {
 		return err
 	}
-	return nil
+	result, err := clientutil.CreateOrUpdateRoleBinding(ctx, ctrl.client, rb)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, rb, result)
+	return err
 }
 
-func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerRoleBinding(karmada *installv1alpha1.Karmada) error {
+// fireflyKarmadaManagerRoleBinding returns the firefly-karmada-manager role binding of the karmada.
+func fireflyKarmadaManagerRoleBinding(karmada *installv1alpha1.Karmada) (*rbacv1.RoleBinding, error) {
 	rb := &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      constants.FireflyComponentKarmadaManager,
@@ -113,15 +192,29 @@ func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerRoleBinding(karmada *i
 			APIGroup: "rbac.authorization.k8s.io",
 		},
 	}
+	util.SetKarmadaInstanceLabel(rb, karmada.Name)
 	controllerutil.SetOwnerReference(karmada, rb, scheme.Scheme)
-	_, err := ctrl.client.RbacV1().RoleBindings(karmada.Namespace).Create(context.TODO(), rb, metav1.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
+	if err := patchutil.Apply(rb, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return rb, nil
+}
+
+func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerDeployment(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	deployment, err := fireflyKarmadaManagerDeployment(karmada)
+	if err != nil {
+		return err
+	}
+	if err := ctrl.pinImages(ctx, karmada, &deployment.Spec.Template.Spec); err != nil {
 		return err
 	}
-	return nil
+	result, err := clientutil.CreateOrUpdateDeployment(ctx, ctrl.client, deployment)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, deployment, result)
+	return err
 }
 
-func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerDeployment(karmada *installv1alpha1.Karmada) error {
+// fireflyKarmadaManagerDeployment returns the firefly-karmada-manager deployment of the karmada.
+func fireflyKarmadaManagerDeployment(karmada *installv1alpha1.Karmada) (*appsv1.Deployment, error) {
 	componentName := constants.FireflyComponentKarmadaManager
 	fkm := karmada.Spec.ControllerManager.FireflyKarmadaManager
 	repository := karmada.Spec.ImageRepository
@@ -154,7 +247,8 @@ func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerDeployment(karmada *in
 		defaultArgs["controllers"] = strings.Join(fkm.Controllers, ",")
 	}
 
-	args := maputil.ConvertToCommandOrArgs(defaultArgs)
+	computedArgs := maputil.MergeStringMaps(defaultArgs, util.LoggingArgs(fkm.Logging), fkm.ExtraArgs)
+	args := maputil.ConvertToCommandOrArgs(computedArgs)
 
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -198,6 +292,17 @@ func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerDeployment(karmada *in
 									MountPath: "/etc/karmada",
 								},
 							},
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path:   "/readyz",
+										Port:   intstr.FromInt(10357),
+										Scheme: corev1.URISchemeHTTPS,
+									},
+								},
+								PeriodSeconds:  10,
+								TimeoutSeconds: 5,
+							},
 						},
 					},
 				},
@@ -205,16 +310,18 @@ func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerDeployment(karmada *in
 		},
 	}
 
+	util.ApplyComponentExtras(&deployment.Spec.Template.Spec, fkm.ComponentExtras)
+	util.SetKarmadaInstanceLabel(deployment, karmada.Name)
+
 	controllerutil.SetOwnerReference(karmada, deployment, scheme.Scheme)
-	_, err := ctrl.client.AppsV1().Deployments(karmada.Namespace).Create(context.TODO(), deployment, metav1.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
-		return err
+	if err := patchutil.Apply(deployment, karmada.Spec.Patches); err != nil {
+		return nil, err
 	}
-	return nil
+	return deployment, nil
 }
 
-func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerCRDs(karmada *installv1alpha1.Karmada) error {
-	clientConfig, err := ctrl.GenerateClientConfig(karmada)
+func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerCRDs(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
 	if err != nil {
 		return err
 	}
@@ -226,7 +333,8 @@ func (ctrl *KarmadaController) EnsureFireflyKarmadaManagerCRDs(karmada *installv
 		if err != nil {
 			return err
 		}
-		_, err1 := resource.NewHelper(info.Client, info.Mapping).Create(info.Namespace, true, info.Object)
+		_, err1 := resource.NewHelper(info.Client, info.Mapping).DryRun(dryrun.Enabled(ctx)).Create(info.Namespace, true, info.Object)
+		audit.RecordResult(ctx, audit.Create, info.Object, err1)
 		if err1 != nil {
 			if !errors.IsAlreadyExists(err1) {
 				return err1
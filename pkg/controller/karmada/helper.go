@@ -17,6 +17,10 @@ limitations under the License.
 package karmada
 
 import (
+	"context"
+	"hash/fnv"
+	"strconv"
+
 	restclient "k8s.io/client-go/rest"
 
 	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
@@ -28,7 +32,15 @@ const (
 	userAgentName = "karmada-controller"
 )
 
-func (ctrl *KarmadaController) GenerateClientConfig(karmada *installv1alpha1.Karmada) (*restclient.Config, error) {
+func (ctrl *KarmadaController) GenerateClientConfig(ctx context.Context, karmada *installv1alpha1.Karmada) (*restclient.Config, error) {
 	secretName := "karmada-kubeconfig"
 	return utilresource.GetClientConfigFromKubeConfigSecret(ctrl.client, karmada.Namespace, secretName, userAgentName)
 }
+
+// contentHash returns the hash of the content of a configuration file, which is set on the pod
+// templates of the components loading the file, so that they're restarted when it's changed.
+func contentHash(content string) string {
+	hasher := fnv.New32a()
+	hasher.Write([]byte(content))
+	return strconv.FormatUint(uint64(hasher.Sum32()), 16)
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+// hookCheckInterval is the interval the Jobs of the running or failed hooks are checked at.
+const hookCheckInterval = 10 * time.Second
+
+// hookPhase returns the phase of the lifecycle of the karmada the pre or the post hooks are run at,
+// along with the hooks. It returns an empty phase if the installed version is the desired one.
+func hookPhase(karmada *installv1alpha1.Karmada, post bool) (installv1alpha1.HookPhase, []installv1alpha1.Hook) {
+	hooks := karmada.Spec.Hooks
+	switch installed := karmada.Status.KarmadaVersion; {
+	case installed == "" && post:
+		return installv1alpha1.HookPhasePostInstall, hooks.PostInstall
+	case installed == "":
+		return installv1alpha1.HookPhasePreInstall, hooks.PreInstall
+	case installed != karmada.Spec.KarmadaVersion && post:
+		return installv1alpha1.HookPhasePostUpgrade, hooks.PostUpgrade
+	case installed != karmada.Spec.KarmadaVersion:
+		return installv1alpha1.HookPhasePreUpgrade, hooks.PreUpgrade
+	}
+	return "", nil
+}
+
+// EnsureHooks runs the pre or the post hooks of the current lifecycle phase of the karmada one after
+// another, and returns whether all of them are complete. The phase is held back otherwise, and the
+// karmada is requeued until the running hook is complete or the failed one is run again.
+func (ctrl *KarmadaController) EnsureHooks(ctx context.Context, karmada *installv1alpha1.Karmada, post bool) (bool, error) {
+	phase, hooks := hookPhase(karmada, post)
+	if len(hooks) == 0 {
+		return true, nil
+	}
+
+	version := karmada.Spec.KarmadaVersion
+	for i := range hooks {
+		hook := &hooks[i]
+		if last := hookStatus(karmada, phase, hook.Name); last != nil && last.Version == version &&
+			(last.Result == installv1alpha1.HookResultSucceeded || last.Result == installv1alpha1.HookResultFailed && hook.FailurePolicy == installv1alpha1.HookFailurePolicyIgnore) {
+			continue
+		}
+
+		result, message, jobName, err := ctrl.runHook(ctx, karmada, phase, hook)
+		if err != nil {
+			return false, err
+		}
+		if err := ctrl.recordHookResult(ctx, karmada, phase, hook.Name, jobName, result, message); err != nil {
+			return false, err
+		}
+
+		switch {
+		case result == installv1alpha1.HookResultSucceeded:
+			continue
+		case result == installv1alpha1.HookResultFailed && hook.FailurePolicy == installv1alpha1.HookFailurePolicyIgnore:
+			klog.V(2).InfoS("Ignoring the failed hook", "karmada", klog.KObj(karmada), "phase", phase, "hook", hook.Name)
+			continue
+		case result == installv1alpha1.HookResultFailed:
+			ctrl.enqueueAfter(karmada, hookCheckInterval)
+			return false, ctrl.updateKarmadaCondition(ctx, karmada, installv1alpha1.KarmadaConditionHooksSucceeded, &metav1.Condition{
+				Status:  metav1.ConditionFalse,
+				Reason:  "HookFailed",
+				Message: fmt.Sprintf("the %s hook %s failed, delete the job %s to run it again: %s", phase, hook.Name, jobName, message),
+			})
+		default:
+			ctrl.enqueueAfter(karmada, hookCheckInterval)
+			return false, ctrl.updateKarmadaCondition(ctx, karmada, installv1alpha1.KarmadaConditionHooksSucceeded, &metav1.Condition{
+				Status:  metav1.ConditionFalse,
+				Reason:  "HookRunning",
+				Message: fmt.Sprintf("waiting for the %s hook %s to complete", phase, hook.Name),
+			})
+		}
+	}
+
+	return true, ctrl.updateKarmadaCondition(ctx, karmada, installv1alpha1.KarmadaConditionHooksSucceeded, &metav1.Condition{
+		Status:  metav1.ConditionTrue,
+		Reason:  "HooksSucceeded",
+		Message: fmt.Sprintf("the %s hooks have succeeded", phase),
+	})
+}
+
+// runHook creates the Job of the hook from its template unless it exists, and returns the result of
+// the Job. The Job left by a run for another version is deleted first.
+func (ctrl *KarmadaController) runHook(ctx context.Context, karmada *installv1alpha1.Karmada, phase installv1alpha1.HookPhase, hook *installv1alpha1.Hook) (installv1alpha1.HookResult, string, string, error) {
+	var job *batchv1.Job
+	switch {
+	case hook.Template != nil:
+		var err error
+		job, err = hookJob(karmada, phase, hook)
+		if err != nil {
+			return "", "", "", err
+		}
+	case hook.JobRef != nil:
+		job = &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: hook.JobRef.Name, Namespace: karmada.Namespace}}
+	default:
+		return installv1alpha1.HookResultFailed, "neither template nor jobRef is set", "", nil
+	}
+
+	got, err := ctrl.client.BatchV1().Jobs(job.Namespace).Get(ctx, job.Name, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return "", "", "", err
+	}
+	if err == nil && hook.Template != nil && got.Annotations[constants.HookVersionAnnotation] != karmada.Spec.KarmadaVersion {
+		if err := ctrl.deleteJob(ctx, got); err != nil {
+			return "", "", "", err
+		}
+		return installv1alpha1.HookResultRunning, "the job of the previous run is being deleted", job.Name, nil
+	}
+	if errors.IsNotFound(err) {
+		if hook.JobRef != nil {
+			return installv1alpha1.HookResultRunning, "waiting for the job to be created", job.Name, nil
+		}
+		if _, err := ctrl.client.BatchV1().Jobs(job.Namespace).Create(ctx, job, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return "", "", "", err
+		}
+		audit.Record(ctx, audit.Create, job, nil)
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "HookStarted", "Started the %s hook %s", phase, hook.Name)
+		return installv1alpha1.HookResultRunning, "", job.Name, nil
+	}
+
+	switch {
+	case jobConditionTrue(got, batchv1.JobComplete):
+		return installv1alpha1.HookResultSucceeded, "", job.Name, nil
+	case jobConditionTrue(got, batchv1.JobFailed):
+		message := "the job failed"
+		for _, c := range got.Status.Conditions {
+			if c.Type == batchv1.JobFailed && c.Message != "" {
+				message = c.Message
+			}
+		}
+		return installv1alpha1.HookResultFailed, message, job.Name, nil
+	}
+	return installv1alpha1.HookResultRunning, "", job.Name, nil
+}
+
+// hookJob returns the Job created from the template of the hook.
+func hookJob(karmada *installv1alpha1.Karmada, phase installv1alpha1.HookPhase, hook *installv1alpha1.Hook) (*batchv1.Job, error) {
+	template := hook.Template.DeepCopy()
+	job := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: template.ObjectMeta,
+		Spec:       template.Spec,
+	}
+	job.Name = fmt.Sprintf("%s-%s-%s", karmada.Name, phase, hook.Name)
+	job.Namespace = karmada.Namespace
+	if job.Annotations == nil {
+		job.Annotations = map[string]string{}
+	}
+	job.Annotations[constants.HookVersionAnnotation] = karmada.Spec.KarmadaVersion
+	if job.Spec.Template.Spec.RestartPolicy == "" {
+		job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+	util.SetKarmadaInstanceLabel(job, karmada.Name)
+
+	controllerutil.SetOwnerReference(karmada, job, scheme.Scheme)
+	if err := patchutil.Apply(job, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// hookStatus returns the status of the last run of the hook, or nil if it has never been run.
+func hookStatus(karmada *installv1alpha1.Karmada, phase installv1alpha1.HookPhase, name string) *installv1alpha1.HookStatus {
+	for i := range karmada.Status.Hooks {
+		if s := &karmada.Status.Hooks[i]; s.Phase == phase && s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// recordHookResult records the result of the run of the hook in the status of the karmada, and emits
+// an event once the run is complete.
+func (ctrl *KarmadaController) recordHookResult(ctx context.Context, karmada *installv1alpha1.Karmada, phase installv1alpha1.HookPhase, name, jobName string, result installv1alpha1.HookResult, message string) error {
+	version := karmada.Spec.KarmadaVersion
+	changed := false
+	err := ctrl.updateKarmadaStatus(ctx, karmada, func(status *installv1alpha1.KarmadaStatus) {
+		var last *installv1alpha1.HookStatus
+		for i := range status.Hooks {
+			if s := &status.Hooks[i]; s.Phase == phase && s.Name == name {
+				last = s
+			}
+		}
+		if last == nil {
+			status.Hooks = append(status.Hooks, installv1alpha1.HookStatus{Phase: phase, Name: name})
+			last = &status.Hooks[len(status.Hooks)-1]
+		}
+		if last.Result != result || last.Version != version {
+			now := metav1.Now()
+			last.LastTransitionTime = &now
+			changed = true
+		}
+		last.Version = version
+		last.JobName = jobName
+		last.Result = result
+		last.Message = message
+	})
+	if err != nil || !changed {
+		return err
+	}
+
+	switch result {
+	case installv1alpha1.HookResultSucceeded:
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "HookSucceeded", "The %s hook %s succeeded", phase, name)
+	case installv1alpha1.HookResultFailed:
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "HookFailed", "The %s hook %s failed: %s", phase, name, message)
+	}
+	return nil
+}
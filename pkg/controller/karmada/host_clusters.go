@@ -0,0 +1,437 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/features"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+)
+
+// hostClusterComponents are the components which are run in the additional host clusters as well. They
+// only depend on etcd and on each other, which they reach through the Services in the same namespace.
+var hostClusterComponents = []string{
+	constants.KarmadaComponentKubeAPIServer,
+	constants.KarmadaComponentAggregratedAPIServer,
+	constants.KarmadaComponentWebhook,
+}
+
+// multiHostClusterEnabled returns whether the karmada has additional host clusters to run its apiservers in.
+func multiHostClusterEnabled(karmada *installv1alpha1.Karmada) bool {
+	return karmada.Spec.HostClusters != nil && len(karmada.Spec.HostClusters.Clusters) > 0 &&
+		utilfeature.DefaultFeatureGate.Enabled(features.MultiHostCluster)
+}
+
+// globalEndpoint returns spec.hostClusters.globalEndpoint if the karmada has additional host clusters.
+func globalEndpoint(karmada *installv1alpha1.Karmada) string {
+	if !multiHostClusterEnabled(karmada) {
+		return ""
+	}
+	return karmada.Spec.HostClusters.GlobalEndpoint
+}
+
+// EnsureHostClusters runs the apiservers and the etcd members of the karmada in its additional host clusters,
+// and cleans up the host clusters which are removed from spec.hostClusters. The states of the host clusters are
+// recorded in the status even if some of them failed, so that the others are cleaned up once they're removed.
+func (ctrl *KarmadaController) EnsureHostClusters(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if karmada.Spec.HostClusters != nil && !utilfeature.DefaultFeatureGate.Enabled(features.MultiHostCluster) {
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "FeatureGateDisabled",
+			"spec.hostClusters is ignored since the %s feature gate is disabled", features.MultiHostCluster)
+	}
+	var desired []installv1alpha1.KarmadaHostCluster
+	if multiHostClusterEnabled(karmada) {
+		desired = karmada.Spec.HostClusters.Clusters
+	}
+
+	var statuses []installv1alpha1.KarmadaHostClusterStatus
+	var errs []error
+	names := sets.NewString()
+	for _, hostCluster := range desired {
+		names.Insert(hostCluster.Name)
+		status := installv1alpha1.KarmadaHostClusterStatus{
+			Name:                 hostCluster.Name,
+			KubeconfigSecretName: hostCluster.KubeconfigSecretRef.Name,
+		}
+		// the etcd member is kept track of even if the host cluster can't be reached for now.
+		for _, last := range karmada.Status.HostClusters {
+			if last.Name == hostCluster.Name {
+				status.EtcdPeerURL = last.EtcdPeerURL
+			}
+		}
+		if err := ctrl.ensureHostCluster(ctx, karmada, &status); err != nil {
+			status.Message = err.Error()
+			errs = append(errs, fmt.Errorf("host cluster %s: %v", hostCluster.Name, err))
+		}
+		statuses = append(statuses, status)
+	}
+	for _, status := range karmada.Status.HostClusters {
+		if names.Has(status.Name) {
+			continue
+		}
+		err := ctrl.removeHostClusterEtcdMember(ctx, karmada, status)
+		if err == nil {
+			status.EtcdPeerURL = ""
+			err = ctrl.removeHostCluster(ctx, karmada, status.KubeconfigSecretName)
+		}
+		if err != nil {
+			// the host cluster is kept in the status until it's cleaned up.
+			status.Message = err.Error()
+			statuses = append(statuses, status)
+			errs = append(errs, fmt.Errorf("host cluster %s: %v", status.Name, err))
+		}
+	}
+
+	var condition *metav1.Condition
+	if etcdDistributed(karmada) {
+		c, err := ctrl.etcdVotersCondition(ctx, karmada)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		condition = &c
+	}
+	if err := ctrl.updateHostClustersStatus(ctx, karmada, statuses, condition); err != nil {
+		return err
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// RemoveHostClusters cleans up all the host clusters recorded in the status of the karmada, which is deleted.
+func (ctrl *KarmadaController) RemoveHostClusters(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	var errs []error
+	for _, status := range karmada.Status.HostClusters {
+		if err := ctrl.removeHostCluster(ctx, karmada, status.KubeconfigSecretName); err != nil {
+			errs = append(errs, fmt.Errorf("host cluster %s: %v", status.Name, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// updateHostClustersStatus records the states of the host clusters and the EtcdVotersBalanced condition,
+// which is removed if it's nil.
+func (ctrl *KarmadaController) updateHostClustersStatus(ctx context.Context, karmada *installv1alpha1.Karmada, statuses []installv1alpha1.KarmadaHostClusterStatus, condition *metav1.Condition) error {
+	return ctrl.updateKarmadaStatus(ctx, karmada, func(status *installv1alpha1.KarmadaStatus) {
+		status.HostClusters = statuses
+		if condition == nil {
+			meta.RemoveStatusCondition(&status.Conditions, installv1alpha1.KarmadaConditionEtcdVotersBalanced)
+			return
+		}
+		condition.Type = installv1alpha1.KarmadaConditionEtcdVotersBalanced
+		condition.ObservedGeneration = karmada.Generation
+		meta.SetStatusCondition(&status.Conditions, *condition)
+	})
+}
+
+// hostClusterClient returns the client of the host cluster whose kubeconfig is held by the given Secret
+// in the namespace of the karmada.
+func (ctrl *KarmadaController) hostClusterClient(ctx context.Context, karmada *installv1alpha1.Karmada, secretName string) (kubernetes.Interface, error) {
+	secret, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	kubeconfig, ok := secret.Data["kubeconfig"]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no kubeconfig key", karmada.Namespace, secretName)
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig in secret %s/%s: %v", karmada.Namespace, secretName, err)
+	}
+	config.Wrap(dryrun.WrapperFor())
+	return kubernetes.NewForConfig(config)
+}
+
+// ensureHostCluster runs the etcd member and the apiservers in the host cluster of the status, and records
+// their state into it. The Services are rendered for the host cluster, while the StatefulSet of etcd, the
+// Deployments, and the Secrets and the ConfigMaps they mount, are copied from the host cluster of the karmada,
+// so that they're identical to the local ones except for how etcd is reached.
+func (ctrl *KarmadaController) ensureHostCluster(ctx context.Context, karmada *installv1alpha1.Karmada, status *installv1alpha1.KarmadaHostClusterStatus) error {
+	hostClient, err := ctrl.hostClusterClient(ctx, karmada, status.KubeconfigSecretName)
+	if err != nil {
+		return err
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: karmada.Namespace}}
+	result, err := clientutil.CreateOrUpdateNamespace(ctx, hostClient, ns)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, ns, result)
+	if err != nil {
+		return err
+	}
+
+	for _, render := range []func(*installv1alpha1.Karmada) (*corev1.Service, error){
+		kubeAPIServerService,
+		karmadaAggregatedAPIServerService,
+		karmadaWebhookService,
+	} {
+		svc, err := render(karmada)
+		if err != nil {
+			return err
+		}
+		svc.OwnerReferences = nil
+		if _, err := clientutil.CreateOrUpdateService(ctx, hostClient, svc); err != nil {
+			return err
+		}
+	}
+
+	// the certificate of the karmada-apiserver is shared by all the host clusters, so it's valid for
+	// the load balancers of all of them.
+	if karmada.Spec.APIServer.ServiceType == corev1.ServiceTypeLoadBalancer {
+		svc, err := hostClient.CoreV1().Services(karmada.Namespace).Get(ctx, constants.KarmadaComponentKubeAPIServer, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		status.Endpoints = loadBalancerAddresses(svc)
+		if err := ctrl.EnsureKubeAPIServerCertSANs(ctx, karmada, status.Endpoints); err != nil {
+			return err
+		}
+	}
+
+	etcdServers, err := ctrl.ensureHostClusterEtcd(ctx, karmada, hostClient, status)
+	if err != nil {
+		return err
+	}
+	var deployments []*appsv1.Deployment
+	var podSpecs []corev1.PodSpec
+	for _, name := range hostClusterComponents {
+		local, err := ctrl.client.AppsV1().Deployments(karmada.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		deployment := hostClusterDeployment(karmada, local, etcdServers)
+		deployments = append(deployments, deployment)
+		podSpecs = append(podSpecs, deployment.Spec.Template.Spec)
+	}
+	if err := ctrl.copyHostClusterVolumes(ctx, karmada, hostClient, podSpecs); err != nil {
+		return err
+	}
+	for _, deployment := range deployments {
+		if _, err := clientutil.CreateOrUpdateDeployment(ctx, hostClient, deployment); err != nil {
+			return err
+		}
+	}
+
+	apiserver, err := hostClient.AppsV1().Deployments(karmada.Namespace).Get(ctx, constants.KarmadaComponentKubeAPIServer, metav1.GetOptions{})
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	status.ReadyReplicas = apiserver.Status.ReadyReplicas
+	if karmada.Spec.APIServer.ServiceType == corev1.ServiceTypeLoadBalancer && len(status.Endpoints) == 0 {
+		return fmt.Errorf("waiting for the load balancer of %s to be provisioned", constants.KarmadaComponentKubeAPIServer)
+	}
+	return nil
+}
+
+// hostClusterDeployment returns the copy of the local deployment of a component for an additional host
+// cluster, which isn't owned by the karmada and stores data in the given etcd servers if they're set.
+func hostClusterDeployment(karmada *installv1alpha1.Karmada, local *appsv1.Deployment, etcdServers string) *appsv1.Deployment {
+	annotations := make(map[string]string, len(local.Annotations))
+	for key, value := range local.Annotations {
+		if key != "deployment.kubernetes.io/revision" {
+			annotations[key] = value
+		}
+	}
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        local.Name,
+			Namespace:   local.Namespace,
+			Labels:      local.Labels,
+			Annotations: annotations,
+		},
+		Spec: *local.Spec.DeepCopy(),
+	}
+	if etcdServers != "" {
+		for i := range deployment.Spec.Template.Spec.Containers {
+			container := &deployment.Spec.Template.Spec.Containers[i]
+			setEtcdServersArg(container.Command, etcdServers)
+			setEtcdServersArg(container.Args, etcdServers)
+		}
+	}
+	util.SetKarmadaInstanceLabel(deployment, karmada.Name)
+	return deployment
+}
+
+// setEtcdServersArg overwrites the --etcd-servers flag in the command or the args of a container.
+func setEtcdServersArg(args []string, etcdServers string) {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "--etcd-servers=") {
+			args[i] = fmt.Sprintf("--etcd-servers=%s", etcdServers)
+		}
+	}
+}
+
+// copyHostClusterVolumes copies the Secrets and the ConfigMaps mounted by the pods, and their image pull
+// Secrets, from the host cluster of the karmada into an additional host cluster.
+func (ctrl *KarmadaController) copyHostClusterVolumes(ctx context.Context, karmada *installv1alpha1.Karmada, hostClient kubernetes.Interface, podSpecs []corev1.PodSpec) error {
+	secrets, configMaps := sets.NewString(), sets.NewString()
+	for _, podSpec := range podSpecs {
+		for _, ref := range podSpec.ImagePullSecrets {
+			secrets.Insert(ref.Name)
+		}
+		for _, volume := range podSpec.Volumes {
+			switch {
+			case volume.Secret != nil:
+				secrets.Insert(volume.Secret.SecretName)
+			case volume.ConfigMap != nil:
+				configMaps.Insert(volume.ConfigMap.Name)
+			case volume.Projected != nil:
+				for _, source := range volume.Projected.Sources {
+					if source.Secret != nil {
+						secrets.Insert(source.Secret.Name)
+					}
+					if source.ConfigMap != nil {
+						configMaps.Insert(source.ConfigMap.Name)
+					}
+				}
+			}
+		}
+	}
+
+	for _, name := range secrets.List() {
+		local, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		secret := &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{Name: local.Name, Namespace: local.Namespace, Labels: local.Labels},
+			Type:       local.Type,
+			Data:       local.Data,
+		}
+		util.SetKarmadaInstanceLabel(secret, karmada.Name)
+		if _, err := clientutil.CreateOrUpdateSecret(ctx, hostClient, secret); err != nil {
+			return err
+		}
+	}
+	for _, name := range configMaps.List() {
+		local, err := ctrl.client.CoreV1().ConfigMaps(karmada.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		cm := &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: local.Name, Namespace: local.Namespace, Labels: local.Labels},
+			Data:       local.Data,
+			BinaryData: local.BinaryData,
+		}
+		util.SetKarmadaInstanceLabel(cm, karmada.Name)
+		if _, err := clientutil.CreateOrUpdateConfigMap(ctx, hostClient, cm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeHostCluster deletes the objects of the karmada from the host cluster whose kubeconfig is held by
+// the given Secret. The namespace and the data volume of the etcd member are left, since they may be shared
+// or still needed. The host cluster is skipped if the
+// Secret is gone, since it can't be reached anymore.
+func (ctrl *KarmadaController) removeHostCluster(ctx context.Context, karmada *installv1alpha1.Karmada, secretName string) error {
+	hostClient, err := ctrl.hostClusterClient(ctx, karmada, secretName)
+	if errors.IsNotFound(err) {
+		klog.InfoS("Skipped cleaning up the host cluster since its kubeconfig is gone", "karmada", klog.KObj(karmada), "secret", secretName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	opts := metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{constants.KarmadaInstanceLabel: karmada.Name}).String(),
+	}
+	var refs []audit.ObjectReference
+	statefulSets, err := hostClient.AppsV1().StatefulSets(karmada.Namespace).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, obj := range statefulSets.Items {
+		refs = append(refs, audit.ObjectReference{Kind: "StatefulSet", Namespace: obj.Namespace, Name: obj.Name})
+	}
+	deployments, err := hostClient.AppsV1().Deployments(karmada.Namespace).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, obj := range deployments.Items {
+		refs = append(refs, audit.ObjectReference{Kind: "Deployment", Namespace: obj.Namespace, Name: obj.Name})
+	}
+	services, err := hostClient.CoreV1().Services(karmada.Namespace).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, obj := range services.Items {
+		refs = append(refs, audit.ObjectReference{Kind: "Service", Namespace: obj.Namespace, Name: obj.Name})
+	}
+	secrets, err := hostClient.CoreV1().Secrets(karmada.Namespace).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, obj := range secrets.Items {
+		refs = append(refs, audit.ObjectReference{Kind: "Secret", Namespace: obj.Namespace, Name: obj.Name})
+	}
+	configMaps, err := hostClient.CoreV1().ConfigMaps(karmada.Namespace).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, obj := range configMaps.Items {
+		refs = append(refs, audit.ObjectReference{Kind: "ConfigMap", Namespace: obj.Namespace, Name: obj.Name})
+	}
+
+	for _, ref := range refs {
+		var err error
+		switch ref.Kind {
+		case "StatefulSet":
+			err = hostClient.AppsV1().StatefulSets(ref.Namespace).Delete(ctx, ref.Name, metav1.DeleteOptions{})
+		case "Deployment":
+			err = hostClient.AppsV1().Deployments(ref.Namespace).Delete(ctx, ref.Name, metav1.DeleteOptions{})
+		case "Service":
+			err = hostClient.CoreV1().Services(ref.Namespace).Delete(ctx, ref.Name, metav1.DeleteOptions{})
+		case "Secret":
+			err = hostClient.CoreV1().Secrets(ref.Namespace).Delete(ctx, ref.Name, metav1.DeleteOptions{})
+		case "ConfigMap":
+			err = hostClient.CoreV1().ConfigMaps(ref.Namespace).Delete(ctx, ref.Name, metav1.DeleteOptions{})
+		}
+		audit.RecordDeletion(ctx, ref, err)
+		if err := client.IgnoreNotFound(err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/util/registry"
+)
+
+// imagePinningEnabled returns whether the images of the components of the karmada are pinned to digests.
+func imagePinningEnabled(karmada *installv1alpha1.Karmada) bool {
+	policy := karmada.Spec.ImagePolicy
+	return policy != nil && (policy.PinDigests || policy.Verification != nil)
+}
+
+// pinImages pins the images of the containers of the pod to the digests their tags are resolved to,
+// if the image policy of the karmada requires so. The images already pinned, e.g. by patches, are
+// left alone.
+func (ctrl *KarmadaController) pinImages(ctx context.Context, karmada *installv1alpha1.Karmada, podSpec *corev1.PodSpec) error {
+	if !imagePinningEnabled(karmada) {
+		return nil
+	}
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			image := containers[i].Image
+			if strings.Contains(image, "@") {
+				continue
+			}
+			digest, err := ctrl.resolveImage(ctx, karmada, image)
+			if err != nil {
+				return err
+			}
+			containers[i].Image = registry.WithDigest(image, digest)
+		}
+	}
+	return nil
+}
+
+// resolveImage returns the digest of the image. The digest recorded in the status of the karmada is
+// reused, otherwise the tag is resolved and the digest is recorded. The signature of the image is
+// verified once if the image policy requires so.
+func (ctrl *KarmadaController) resolveImage(ctx context.Context, karmada *installv1alpha1.Karmada, image string) (string, error) {
+	verification := karmada.Spec.ImagePolicy.Verification
+	var resolved *installv1alpha1.ResolvedImage
+	for i := range karmada.Status.ResolvedImages {
+		if karmada.Status.ResolvedImages[i].Image == image {
+			resolved = karmada.Status.ResolvedImages[i].DeepCopy()
+		}
+	}
+	if resolved != nil && (verification == nil || resolved.Verified) {
+		return resolved.Digest, nil
+	}
+
+	if resolved == nil {
+		digest, err := ctrl.imageClient.ResolveDigest(ctx, image)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve the digest of image %s: %v", image, err)
+		}
+		resolved = &installv1alpha1.ResolvedImage{Image: image, Digest: digest}
+	}
+	if verification != nil {
+		keys, err := ctrl.imageVerificationKeys(ctx, karmada)
+		if err != nil {
+			return "", err
+		}
+		if err := ctrl.imageClient.VerifySignature(ctx, image, resolved.Digest, keys); err != nil {
+			ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "ImageVerificationFailed", "Failed to verify image %s: %v", image, err)
+			return "", err
+		}
+		resolved.Verified = true
+	}
+
+	if err := ctrl.recordResolvedImage(ctx, karmada, *resolved); err != nil {
+		return "", err
+	}
+	ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "ImageResolved", "Resolved image %s to %s", image, resolved.Digest)
+	return resolved.Digest, nil
+}
+
+// imageVerificationKeys returns the public keys the signatures of the images are verified with.
+func (ctrl *KarmadaController) imageVerificationKeys(ctx context.Context, karmada *installv1alpha1.Karmada) ([]crypto.PublicKey, error) {
+	name := karmada.Spec.ImagePolicy.Verification.PublicKeysSecretRef.Name
+	secret, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the public keys of the image verification: %v", err)
+	}
+	keys, err := registry.ParsePublicKeys(secret.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public keys in secret %s: %v", name, err)
+	}
+	return keys, nil
+}
+
+// recordResolvedImage records the resolved image in the status of the karmada. The images previously
+// resolved for the same repository are dropped, so that the images of the old versions don't pile up.
+func (ctrl *KarmadaController) recordResolvedImage(ctx context.Context, karmada *installv1alpha1.Karmada, resolved installv1alpha1.ResolvedImage) error {
+	repository := imageRepository(resolved.Image)
+	return ctrl.updateKarmadaStatus(ctx, karmada, func(status *installv1alpha1.KarmadaStatus) {
+		images := []installv1alpha1.ResolvedImage{resolved}
+		for _, existing := range status.ResolvedImages {
+			if existing.Image != resolved.Image && imageRepository(existing.Image) != repository {
+				images = append(images, existing)
+			}
+		}
+		sort.Slice(images, func(i, j int) bool {
+			return images[i].Image < images[j].Image
+		})
+		status.ResolvedImages = images
+	})
+}
+
+// imageRepository returns the repository of the image, that is the image without its tag.
+func imageRepository(image string) string {
+	named, err := registry.ParseImage(image)
+	if err != nil {
+		return image
+	}
+	return named.Name()
+}
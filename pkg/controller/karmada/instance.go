@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+)
+
+// namespaceInstance returns the karmada which the namespace is assigned to. The components of a karmada
+// have fixed names, so only one karmada is installed per namespace and each of them is served by its
+// own firefly-karmada-manager. The karmada which is already installed keeps the namespace, otherwise the
+// oldest karmada in the namespace wins.
+func (ctrl *KarmadaController) namespaceInstance(namespace string) (*installv1alpha1.Karmada, error) {
+	karmadas, err := ctrl.karmadasLister.Karmadas(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var instance *installv1alpha1.Karmada
+	for _, karmada := range karmadas {
+		if instance == nil || preferredInstance(karmada, instance) {
+			instance = karmada
+		}
+	}
+	return instance, nil
+}
+
+// preferredInstance returns whether the karmada a is preferred over b for the namespace. An installed
+// karmada is never demoted by a newer one, even if they're created in the same second.
+func preferredInstance(a, b *installv1alpha1.Karmada) bool {
+	if installedA, installedB := installedInstance(a), installedInstance(b); installedA != installedB {
+		return installedA
+	}
+	return olderThan(a, b)
+}
+
+// installedInstance returns whether the karmada has been accepted for its namespace, that is, it has the
+// Accepted condition or the finalizer which is only added once it's accepted.
+func installedInstance(karmada *installv1alpha1.Karmada) bool {
+	return meta.IsStatusConditionTrue(karmada.Status.Conditions, installv1alpha1.KarmadaConditionAccepted) ||
+		controllerutil.ContainsFinalizer(karmada, KarmadaControllerFinalizerName)
+}
+
+func olderThan(a, b *installv1alpha1.Karmada) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// ensureNamespaceInstance updates the Accepted condition of the karmada and reports whether the
+// karmada is the one installed in its namespace.
+func (ctrl *KarmadaController) ensureNamespaceInstance(ctx context.Context, karmada *installv1alpha1.Karmada) (bool, error) {
+	instance, err := ctrl.namespaceInstance(karmada.Namespace)
+	if err != nil {
+		return false, err
+	}
+
+	if instance != nil && instance.UID != karmada.UID {
+		return false, ctrl.updateKarmadaCondition(ctx, karmada, installv1alpha1.KarmadaConditionAccepted, &metav1.Condition{
+			Status:  metav1.ConditionFalse,
+			Reason:  "NamespaceConflict",
+			Message: fmt.Sprintf("karmada %s is already installed in namespace %s, only one karmada is allowed per namespace", instance.Name, karmada.Namespace),
+		})
+	}
+	return true, ctrl.updateKarmadaCondition(ctx, karmada, installv1alpha1.KarmadaConditionAccepted, &metav1.Condition{
+		Status:  metav1.ConditionTrue,
+		Reason:  "Accepted",
+		Message: "karmada is installed in the namespace",
+	})
+}
+
+// enqueueNamespaceInstances enqueues the other karmadas in the namespace of the given karmada,
+// so that one of them can be installed once the given one is gone.
+func (ctrl *KarmadaController) enqueueNamespaceInstances(karmada *installv1alpha1.Karmada) {
+	karmadas, err := ctrl.karmadasLister.Karmadas(karmada.Namespace).List(labels.Everything())
+	if err != nil {
+		return
+	}
+	for _, k := range karmadas {
+		if k.UID != karmada.UID {
+			ctrl.enqueue(k)
+		}
+	}
+}
+
+// updateKarmadaCondition sets the condition of the given type of the karmada to the given
+// one, or removes it if the given one is nil. The status is updated only if it's changed.
+func (ctrl *KarmadaController) updateKarmadaCondition(ctx context.Context, karmada *installv1alpha1.Karmada, conditionType string, condition *metav1.Condition) error {
+	return ctrl.updateKarmadaStatus(ctx, karmada, func(status *installv1alpha1.KarmadaStatus) {
+		if condition == nil {
+			meta.RemoveStatusCondition(&status.Conditions, conditionType)
+			return
+		}
+		condition.Type = conditionType
+		condition.ObservedGeneration = karmada.Generation
+		meta.SetStatusCondition(&status.Conditions, *condition)
+	})
+}
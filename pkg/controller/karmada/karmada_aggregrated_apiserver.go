@@ -17,6 +17,7 @@ limitations under the License.
 package karmada
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -35,13 +36,14 @@ import (
 	"github.com/carlory/firefly/pkg/util"
 	clientutil "github.com/carlory/firefly/pkg/util/client"
 	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
 )
 
-func (ctrl *KarmadaController) EnsureKarmadaAggregatedAPIServer(karmada *installv1alpha1.Karmada) error {
-	if err := ctrl.EnsureKarmadaAggregatedAPIServerService(karmada); err != nil {
+func (ctrl *KarmadaController) EnsureKarmadaAggregatedAPIServer(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if err := ctrl.EnsureKarmadaAggregatedAPIServerService(ctx, karmada); err != nil {
 		return err
 	}
-	if err := ctrl.EnsureKarmadaAggregatedAPIServerDeployment(karmada); err != nil {
+	if err := ctrl.EnsureKarmadaAggregatedAPIServerDeployment(ctx, karmada); err != nil {
 		return err
 	}
 	podLabel := fmt.Sprintf("app=%s", constants.KarmadaComponentAggregratedAPIServer)
@@ -49,10 +51,21 @@ func (ctrl *KarmadaController) EnsureKarmadaAggregatedAPIServer(karmada *install
 	if err != nil {
 		return err
 	}
-	return ctrl.EnsureKarmadaAggregatedAPIServerAPIService(karmada)
+	return ctrl.EnsureKarmadaAggregatedAPIServerAPIService(ctx, karmada)
 }
 
-func (ctrl *KarmadaController) EnsureKarmadaAggregatedAPIServerService(karmada *installv1alpha1.Karmada) error {
+func (ctrl *KarmadaController) EnsureKarmadaAggregatedAPIServerService(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	svc, err := karmadaAggregatedAPIServerService(karmada)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateService(ctx, ctrl.client, svc)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, svc, result)
+	return err
+}
+
+// karmadaAggregatedAPIServerService returns the karmada-aggregated-apiserver service of the karmada.
+func karmadaAggregatedAPIServerService(karmada *installv1alpha1.Karmada) (*corev1.Service, error) {
 	componentName := constants.KarmadaComponentAggregratedAPIServer
 	svc := &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -78,11 +91,24 @@ func (ctrl *KarmadaController) EnsureKarmadaAggregatedAPIServerService(karmada *
 			},
 		},
 	}
+	util.SetKarmadaInstanceLabel(svc, karmada.Name)
 	controllerutil.SetOwnerReference(karmada, svc, scheme.Scheme)
-	return clientutil.CreateOrUpdateService(ctrl.client, svc)
+	if err := patchutil.Apply(svc, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return svc, nil
 }
 
-func (ctrl *KarmadaController) EnsureKarmadaAggregatedAPIServerDeployment(karmada *installv1alpha1.Karmada) error {
+func (ctrl *KarmadaController) EnsureKarmadaAggregatedAPIServerDeployment(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	deployment, err := karmadaAggregatedAPIServerDeployment(karmada)
+	if err != nil {
+		return err
+	}
+	return ctrl.ensureDeployment(ctx, karmada, deployment, karmada.Spec.APIServer.KarmadaAggregratedAPIServer.Autoscaling)
+}
+
+// karmadaAggregatedAPIServerDeployment returns the karmada-aggregated-apiserver deployment of the karmada.
+func karmadaAggregatedAPIServerDeployment(karmada *installv1alpha1.Karmada) (*appsv1.Deployment, error) {
 	componentName := constants.KarmadaComponentAggregratedAPIServer
 	server := karmada.Spec.APIServer.KarmadaAggregratedAPIServer
 	repository := karmada.Spec.ImageRepository
@@ -121,7 +147,7 @@ func (ctrl *KarmadaController) EnsureKarmadaAggregatedAPIServerDeployment(karmad
 			defaultArgs["feature-gates"] = fmt.Sprintf("%s,%s=%t", defaultArgs["feature-gates"], feature, enabled)
 		}
 	}
-	computedArgs := maputil.MergeStringMaps(defaultArgs, server.ExtraArgs)
+	computedArgs := maputil.MergeStringMaps(defaultArgs, util.LoggingArgs(server.Logging), server.ExtraArgs)
 	args := maputil.ConvertToCommandOrArgs(computedArgs)
 
 	deployment := &appsv1.Deployment{
@@ -221,12 +247,18 @@ func (ctrl *KarmadaController) EnsureKarmadaAggregatedAPIServerDeployment(karmad
 		},
 	}
 
+	util.ApplyComponentExtras(&deployment.Spec.Template.Spec, server.ComponentExtras)
+	util.SetKarmadaInstanceLabel(deployment, karmada.Name)
+
 	controllerutil.SetOwnerReference(karmada, deployment, scheme.Scheme)
-	return clientutil.CreateOrUpdateDeployment(ctrl.client, deployment)
+	if err := patchutil.Apply(deployment, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return deployment, nil
 }
 
-func (ctrl *KarmadaController) EnsureKarmadaAggregatedAPIServerAPIService(karmada *installv1alpha1.Karmada) error {
-	clientConfig, err := ctrl.GenerateClientConfig(karmada)
+func (ctrl *KarmadaController) EnsureKarmadaAggregatedAPIServerAPIService(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
 	if err != nil {
 		return err
 	}
@@ -253,7 +285,10 @@ func (ctrl *KarmadaController) EnsureKarmadaAggregatedAPIServerAPIService(karmad
 			ExternalName: fmt.Sprintf("%s.%s.svc", constants.KarmadaComponentAggregratedAPIServer, karmada.Namespace),
 		},
 	}
-	if err = clientutil.CreateOrUpdateService(kubeClient, svc); err != nil {
+	if err := patchutil.Apply(svc, karmada.Spec.Patches); err != nil {
+		return err
+	}
+	if _, err = clientutil.CreateOrUpdateService(ctx, kubeClient, svc); err != nil {
 		return err
 	}
 
@@ -279,5 +314,10 @@ func (ctrl *KarmadaController) EnsureKarmadaAggregatedAPIServerAPIService(karmad
 			VersionPriority: 10,
 		},
 	}
-	return clientutil.CreateOrUpdateAPIService(aaClient, apisvc)
+	if err := patchutil.Apply(apisvc, karmada.Spec.Patches); err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateAPIService(ctx, aaClient, apisvc)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, apisvc, result)
+	return err
 }
@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	clientset "k8s.io/client-go/kubernetes"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -41,7 +42,20 @@ import (
 	fireflyclient "github.com/carlory/firefly/pkg/generated/clientset/versioned"
 	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
 	installlisters "github.com/carlory/firefly/pkg/generated/listers/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/helm"
 	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/adoption"
+	"github.com/carlory/firefly/pkg/util/apply"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/capabilities"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/coalesce"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	informerutil "github.com/carlory/firefly/pkg/util/informer"
+	"github.com/carlory/firefly/pkg/util/notification"
+	phaseutil "github.com/carlory/firefly/pkg/util/phase"
+	"github.com/carlory/firefly/pkg/util/registry"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
 )
 
 const (
@@ -60,7 +74,11 @@ const (
 func NewKarmadaController(
 	client clientset.Interface,
 	fireflyClient fireflyclient.Interface,
-	karmadaInformer installinformers.KarmadaInformer) (*KarmadaController, error) {
+	karmadaInformer installinformers.KarmadaInformer,
+	restConfig *rest.Config,
+	hostCapabilities *capabilities.Capabilities,
+	resyncPeriod time.Duration,
+	rateLimiter workqueue.RateLimiter) (*KarmadaController, error) {
 	broadcaster := record.NewBroadcaster()
 	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "karmada-controller"})
 
@@ -68,22 +86,33 @@ func NewKarmadaController(
 		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("karmada_controller", client.CoreV1().RESTClient().GetRateLimiter())
 	}
 
+	applier, err := apply.NewApplier(restConfig, "firefly-karmada-controller")
+	if err != nil {
+		return nil, err
+	}
+
 	ctrl := &KarmadaController{
 		client:           client,
 		fireflyClient:    fireflyClient,
 		karmadasLister:   karmadaInformer.Lister(),
 		karmadasSynced:   karmadaInformer.Informer().HasSynced,
-		queue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "karmada"),
+		queue:            coalesce.NewQueue(workqueue.NewNamedRateLimitingQueue(rateLimiter, "karmada"), coalesce.DefaultWindow, coalesce.DefaultInterval),
 		workerLoopPeriod: time.Second,
 		eventBroadcaster: broadcaster,
 		eventRecorder:    recorder,
+		applier:          applier,
+		chartFetcher:     helm.NewFetcher(),
+		crdFetcher:       newCRDFetcher(),
+		imageClient:      registry.NewClient(),
+
+		hostCapabilities: hostCapabilities,
 	}
 
-	karmadaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	informerutil.AddEventHandler(karmadaInformer.Informer(), cache.ResourceEventHandlerFuncs{
 		AddFunc:    ctrl.addKarmada,
 		UpdateFunc: ctrl.updateKarmada,
 		DeleteFunc: ctrl.deleteKarmada,
-	})
+	}, resyncPeriod)
 
 	return ctrl, nil
 }
@@ -97,12 +126,24 @@ type KarmadaController struct {
 	karmadasLister installlisters.KarmadaLister
 	karmadasSynced cache.InformerSynced
 
+	// applier applies unstructured objects, e.g. the objects rendered from charts.
+	applier *apply.Applier
+	// chartFetcher loads the charts referenced by spec.chart.
+	chartFetcher *helm.Fetcher
+	// crdFetcher loads the CRDs of the karmada versions.
+	crdFetcher *crdFetcher
+	// imageClient resolves and verifies the images of the components according to spec.imagePolicy.
+	imageClient *registry.Client
+	// hostCapabilities tells the optional APIs served by the host cluster, e.g. the PodMonitors of
+	// the Prometheus Operator, which the monitored components are scraped by if they're available.
+	hostCapabilities *capabilities.Capabilities
+
 	// Karmada that need to be updated. A channel is inappropriate here,
 	// because it allows services with lots of pods to be serviced much
 	// more often than services with few pods; it also would cause a
 	// service that's inserted multiple times to be processed more than
 	// necessary.
-	queue workqueue.RateLimitingInterface
+	queue *coalesce.Queue
 
 	// workerLoopPeriod is the time between worker runs. The workers process the queue of service and pod changes.
 	workerLoopPeriod time.Duration
@@ -149,7 +190,9 @@ func (ctrl *KarmadaController) processNextWorkItem(ctx context.Context) bool {
 	}
 	defer ctrl.queue.Done(key)
 
+	ctx, span := tracingutil.StartReconcile(ctx, "karmada", key.(string))
 	err := ctrl.syncKarmada(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
 	ctrl.handleErr(err, key)
 
 	return true
@@ -165,6 +208,11 @@ func (ctrl *KarmadaController) updateKarmada(old, cur interface{}) {
 	oldKarmada := old.(*installv1alpha1.Karmada)
 	curKarmada := cur.(*installv1alpha1.Karmada)
 	klog.V(4).InfoS("Updating karmada", "karmada", klog.KObj(oldKarmada))
+	// Status-only updates are coalesced, the changes of the spec or the metadata are synced at once.
+	if !coalesce.Changed(oldKarmada, curKarmada) {
+		ctrl.enqueueCoalesced(curKarmada)
+		return
+	}
 	ctrl.enqueue(curKarmada)
 }
 
@@ -184,6 +232,7 @@ func (ctrl *KarmadaController) deleteKarmada(obj interface{}) {
 	}
 	klog.V(4).InfoS("Deleting karmada", "karmada", klog.KObj(karmada))
 	ctrl.enqueue(karmada)
+	ctrl.enqueueNamespaceInstances(karmada)
 }
 
 func (ctrl *KarmadaController) enqueue(karmada *installv1alpha1.Karmada) {
@@ -195,6 +244,27 @@ func (ctrl *KarmadaController) enqueue(karmada *installv1alpha1.Karmada) {
 	ctrl.queue.Add(key)
 }
 
+// enqueueCoalesced enqueues the karmada after the bursts of its status updates, which are written by
+// several controllers, have settled.
+func (ctrl *KarmadaController) enqueueCoalesced(karmada *installv1alpha1.Karmada) {
+	key, err := cache.MetaNamespaceKeyFunc(karmada)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.AddCoalesced(key)
+}
+
+// enqueueAfter requeues the karmada after the given duration.
+func (ctrl *KarmadaController) enqueueAfter(karmada *installv1alpha1.Karmada, duration time.Duration) {
+	key, err := cache.MetaNamespaceKeyFunc(karmada)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.AddAfter(key, duration)
+}
+
 func (ctrl *KarmadaController) handleErr(err error, key interface{}) {
 	if err == nil || errors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
 		ctrl.queue.Forget(key)
@@ -206,6 +276,7 @@ func (ctrl *KarmadaController) handleErr(err error, key interface{}) {
 		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
 	}
 
+	notification.ReconcileFailed("karmada", audit.ObjectReference{Kind: "Karmada", Namespace: ns, Name: name}, ctrl.queue.NumRequeues(key)+1, err)
 	if ctrl.queue.NumRequeues(key) < maxRetries {
 		klog.V(2).InfoS("Error syncing karmada, retrying", "karmada", klog.KRef(ns, name), "err", err)
 		ctrl.queue.AddRateLimited(key)
@@ -242,24 +313,18 @@ func (ctrl *KarmadaController) syncKarmada(ctx context.Context, key string) erro
 	// Deep-copy otherwise we are mutating our cache.
 	// TODO: Deep-copy only when needed.
 	karmada = karmada.DeepCopy()
-
-	// examine DeletionTimestamp to determine if object is under deletion
-	if karmada.DeletionTimestamp.IsZero() {
-		// The object is not being deleted, so if it does not have our finalizer,
-		// then lets add the finalizer and update the object. This is equivalent
-		// registering our finalizer.
-		if !controllerutil.ContainsFinalizer(karmada, KarmadaControllerFinalizerName) {
-			controllerutil.AddFinalizer(karmada, KarmadaControllerFinalizerName)
-			karmada, err = ctrl.fireflyClient.InstallV1alpha1().Karmadas(karmada.Namespace).Update(ctx, karmada, metav1.UpdateOptions{})
-			if err != nil {
-				return err
-			}
-		}
-	} else {
+	// the mutations performed with ctx are audited as triggered by the karmada.
+	ctx = audit.WithTrigger(ctx, karmada)
+	ctx = dryrun.ForObject(ctx, karmada)
+	ctx = adoption.ForObject(ctx, karmada)
+
+	// examine DeletionTimestamp to determine if object is under deletion. A karmada with our finalizer
+	// is cleaned up even if it's no longer accepted, otherwise its finalizer and resources are leaked.
+	if !karmada.DeletionTimestamp.IsZero() {
 		// The object is being deleted
 		if controllerutil.ContainsFinalizer(karmada, KarmadaControllerFinalizerName) {
 			// our finalizer is present, so lets handle any external dependency
-			if err := ctrl.deleteUnableGCResources(karmada); err != nil {
+			if err := ctrl.deleteUnableGCResources(ctx, karmada); err != nil {
 				// if fail to delete the external dependency here, return with error
 				// so that it can be retried
 				return err
@@ -276,82 +341,255 @@ func (ctrl *KarmadaController) syncKarmada(ctx context.Context, key string) erro
 		}
 	}
 
-	klog.InfoS("Syncing karmada", "karmada", klog.KObj(karmada))
-
-	if err := ctrl.genCerts(karmada, nil); err != nil {
-		klog.ErrorS(err, "Failed to generate certs", "namespace", namespace)
+	accepted, err := ctrl.ensureNamespaceInstance(ctx, karmada)
+	if err != nil {
 		return err
 	}
+	if !accepted {
+		// nothing is installed for a rejected karmada, and it has no finalizer.
+		klog.V(2).InfoS("Karmada is rejected because another karmada is installed in the namespace", "karmada", klog.KObj(karmada))
+		return nil
+	}
 
-	if err := ctrl.EnsureEtcd(karmada); err != nil {
-		return err
+	// The object is not being deleted, so if it does not have our finalizer,
+	// then lets add the finalizer and update the object. This is equivalent
+	// registering our finalizer.
+	if karmada.DeletionTimestamp.IsZero() && !controllerutil.ContainsFinalizer(karmada, KarmadaControllerFinalizerName) {
+		controllerutil.AddFinalizer(karmada, KarmadaControllerFinalizerName)
+		karmada, err = ctrl.fireflyClient.InstallV1alpha1().Karmadas(karmada.Namespace).Update(ctx, karmada, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
 	}
 
-	if err := ctrl.EnsureAPIServer(karmada); err != nil {
+	if karmada.Spec.Paused {
+		klog.V(2).InfoS("Karmada is paused, skip syncing", "karmada", klog.KObj(karmada))
+		return ctrl.syncPausedKarmada(ctx, karmada)
+	}
+	if err := ctrl.updateKarmadaCondition(ctx, karmada, installv1alpha1.KarmadaConditionPaused, nil); err != nil {
 		return err
 	}
 
-	if err := ctrl.EnsureControllerManager(karmada); err != nil {
+	if restoring(karmada) {
+		// etcd and its data volume must stay deleted until the volume is restored.
+		klog.V(2).InfoS("Karmada is being restored from a backup, skip syncing", "karmada", klog.KObj(karmada), "backup", karmada.Status.Restore.BackupName)
+		return nil
+	}
+
+	klog.InfoS("Syncing karmada", "karmada", klog.KObj(karmada))
+
+	if karmada.Spec.Chart != nil {
+		if err := ctrl.EnsureChart(ctx, karmada); err != nil {
+			return ctrl.reconcileFailed(ctx, karmada, "ChartFailed", err)
+		}
+		return nil
+	}
+
+	if passed, err := ctrl.EnsureUpgradePreflight(ctx, karmada); err != nil {
+		return ctrl.reconcileFailed(ctx, karmada, "UpgradePreflightFailed", err)
+	} else if !passed {
+		return nil
+	}
+
+	if installed := karmada.Status.KarmadaVersion; installed != "" && installed != karmada.Spec.KarmadaVersion {
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "UpgradeStarted", "Upgrading karmada from %s to %s", installed, karmada.Spec.KarmadaVersion)
+	}
+
+	if err := ctrl.EnsureRolloutResumed(ctx, karmada); err != nil {
+		return ctrl.reconcileFailed(ctx, karmada, "RolloutResumeFailed", err)
+	}
+
+	if err := ctrl.EnsureHostCapabilities(ctx, karmada); err != nil {
+		return ctrl.reconcileFailed(ctx, karmada, "HostCapabilitiesFailed", err)
+	}
+
+	if done, err := ctrl.EnsureHooks(ctx, karmada, false); err != nil {
+		return ctrl.reconcileFailed(ctx, karmada, "HooksFailed", err)
+	} else if !done {
+		klog.V(2).InfoS("Waiting for the pre hooks to complete", "karmada", klog.KObj(karmada))
+		return nil
+	}
+
+	if err := ctrl.genCerts(ctx, karmada, nil); err != nil {
+		klog.ErrorS(err, "Failed to generate certs", "namespace", namespace)
+		return ctrl.reconcileFailed(ctx, karmada, "CertsFailed", err)
+	}
+	if err := ctrl.EnsureSecretsEncrypted(ctx, karmada); err != nil {
+		return ctrl.reconcileFailed(ctx, karmada, "SecretsEncryptionFailed", err)
+	}
+
+	// The monitoring and the network policies don't depend on the control plane, so that a slow
+	// component of the control plane, e.g. etcd waiting for its volumes, doesn't hold them back.
+	ctx = withSharedKarmada(ctx, karmada)
+	err = ctrl.reconcileConcurrently(ctx, karmada,
+		ctrl.ensureControlPlane,
+		func(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+			if err := ctrl.EnsureMonitoring(ctx, karmada); err != nil {
+				return ctrl.reconcileFailed(ctx, karmada, "MonitoringFailed", err)
+			}
+			return nil
+		},
+		func(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+			if err := ctrl.EnsureNetworkPolicies(ctx, karmada); err != nil {
+				return ctrl.reconcileFailed(ctx, karmada, "NetworkPolicyFailed", err)
+			}
+			return nil
+		},
+	)
+	if err != nil {
 		return err
 	}
-	if err := ctrl.EnsureScheduler(karmada); err != nil {
+	if rolloutHeld(ctx) {
+		klog.V(2).InfoS("The changes of some components are held back by the update strategy", "karmada", klog.KObj(karmada))
+		return nil
+	}
+	if err := ctrl.EnsureSeed(ctx, karmada); err != nil {
+		return ctrl.reconcileFailed(ctx, karmada, "SeedFailed", err)
+	}
+	if done, err := ctrl.EnsureHooks(ctx, karmada, true); err != nil {
+		return ctrl.reconcileFailed(ctx, karmada, "HooksFailed", err)
+	} else if !done {
+		klog.V(2).InfoS("Waiting for the post hooks to complete", "karmada", klog.KObj(karmada))
+		return nil
+	}
+	if err := ctrl.updateInstalledVersion(ctx, karmada); err != nil {
 		return err
 	}
+	if err := ctrl.EnsureVerification(ctx, karmada); err != nil {
+		return ctrl.reconcileFailed(ctx, karmada, "VerificationFailed", err)
+	}
 	return nil
 }
 
-func (ctrl *KarmadaController) EnsureAPIServer(karmada *installv1alpha1.Karmada) error {
-	kubeClient, err := ctrl.EnsureKubeAPIServer(karmada)
-	if err != nil {
-		return err
+// reconcileFailed emits a warning event on the karmada for the failed step of the reconciliation
+// and returns the error.
+func (ctrl *KarmadaController) reconcileFailed(ctx context.Context, karmada *installv1alpha1.Karmada, reason string, err error) error {
+	ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, reason, "Failed to reconcile karmada: %v", err)
+	return err
+}
+
+// ensureControlPlane reconciles the components of the control plane. The components are reconciled
+// in the order of their dependencies, e.g. etcd before the apiserver, while the independent ones, e.g.
+// the controller managers and the schedulers, are reconciled concurrently.
+func (ctrl *KarmadaController) ensureControlPlane(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if err := ctrl.EnsureEtcd(ctx, karmada); err != nil {
+		return ctrl.reconcileFailed(ctx, karmada, "EtcdFailed", err)
 	}
 
-	klog.InfoS("karmada-apiserver is ready", "karmada", klog.KObj(karmada))
+	if err := ctrl.EnsureAPIServer(ctx, karmada); err != nil {
+		return ctrl.reconcileFailed(ctx, karmada, "APIServerFailed", err)
+	}
 
-	_, err = kubeClient.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "karmada-system"}}, metav1.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
-		return err
+	if err := ctrl.EnsureKarmadaBootstrap(ctx, karmada); err != nil {
+		return ctrl.reconcileFailed(ctx, karmada, "BootstrapFailed", err)
 	}
 
-	if err := ctrl.EnsureKarmadaAggregatedAPIServer(karmada); err != nil {
-		return err
+	if err := ctrl.EnsureKubeAPIServerExposure(ctx, karmada); err != nil {
+		return ctrl.reconcileFailed(ctx, karmada, "APIServerExposureFailed", err)
 	}
 
-	if err := ctrl.EnsureKarmadaCRDs(karmada); err != nil {
-		return err
+	if err := ctrl.EnsureKubeconfigSecrets(ctx, karmada); err != nil {
+		return ctrl.reconcileFailed(ctx, karmada, "KubeconfigFailed", err)
 	}
-	if err := ctrl.EnsureKaramdaWebhook(karmada); err != nil {
-		return err
+
+	if err := ctrl.EnsureHostClusters(ctx, karmada); err != nil {
+		return ctrl.reconcileFailed(ctx, karmada, "HostClustersFailed", err)
 	}
-	return nil
+
+	return ctrl.reconcileConcurrently(ctx, karmada,
+		func(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+			if err := ctrl.EnsureControllerManager(ctx, karmada); err != nil {
+				return ctrl.reconcileFailed(ctx, karmada, "ControllerManagerFailed", err)
+			}
+			return nil
+		},
+		func(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+			if err := ctrl.EnsureScheduler(ctx, karmada); err != nil {
+				return ctrl.reconcileFailed(ctx, karmada, "SchedulerFailed", err)
+			}
+			return nil
+		},
+	)
 }
 
-func (ctrl *KarmadaController) EnsureControllerManager(karmada *installv1alpha1.Karmada) error {
-	if err := ctrl.EnsureKubeControllerManager(karmada); err != nil {
-		return err
+// updateInstalledVersion records the version and the generation of the karmada once all of its components
+// are reconciled.
+func (ctrl *KarmadaController) updateInstalledVersion(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	installed := karmada.Status.KarmadaVersion
+	if installed == karmada.Spec.KarmadaVersion && karmada.Status.ObservedGeneration == karmada.Generation {
+		return nil
 	}
 
-	if err := ctrl.EnsureKarmadaControllerManager(karmada); err != nil {
+	karmada.Status.KarmadaVersion = karmada.Spec.KarmadaVersion
+	karmada.Status.ObservedGeneration = karmada.Generation
+	phaseutil.SetKarmadaPhase(karmada)
+	updated, err := ctrl.fireflyClient.InstallV1alpha1().Karmadas(karmada.Namespace).UpdateStatus(ctx, karmada, metav1.UpdateOptions{})
+	if err != nil {
 		return err
 	}
-	if err := ctrl.EnsureFireflyKarmadaManager(karmada); err != nil {
-		return err
+	karmada.ResourceVersion = updated.ResourceVersion
+
+	switch installed {
+	case karmada.Spec.KarmadaVersion:
+		// only the generation is recorded.
+	case "":
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "InstallCompleted", "Installed karmada %s", karmada.Spec.KarmadaVersion)
+	default:
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "UpgradeCompleted", "Upgraded karmada from %s to %s", installed, karmada.Spec.KarmadaVersion)
 	}
 	return nil
 }
 
-func (ctrl *KarmadaController) EnsureScheduler(karmada *installv1alpha1.Karmada) error {
-	if err := ctrl.EnsureKarmadaScheduler(karmada); err != nil {
+func (ctrl *KarmadaController) EnsureAPIServer(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	kubeClient, err := ctrl.EnsureKubeAPIServer(ctx, karmada)
+	if err != nil {
 		return err
 	}
-	if err := ctrl.EnsureKarmadaDescheduler(karmada); err != nil {
+
+	klog.InfoS("karmada-apiserver is ready", "karmada", klog.KObj(karmada))
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: constants.KarmadaSystemNamespace}}
+	result, err := clientutil.CreateOrUpdateNamespace(ctx, kubeClient, ns)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, ns, result)
+	if err != nil {
 		return err
 	}
-	return nil
+
+	// the webhook and the aggregated apiservers only depend on the karmada-apiserver.
+	return ctrl.reconcileConcurrently(ctx, karmada,
+		ctrl.EnsureKarmadaAggregatedAPIServer,
+		ctrl.EnsureKaramdaWebhook,
+		ctrl.EnsureKarmadaSearch,
+		ctrl.EnsureKarmadaMetricsAdapter,
+	)
+}
+
+func (ctrl *KarmadaController) EnsureControllerManager(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	return ctrl.reconcileConcurrently(ctx, karmada,
+		ctrl.EnsureKubeControllerManager,
+		ctrl.EnsureKarmadaControllerManager,
+		ctrl.EnsureFireflyKarmadaManager,
+		ctrl.EnsureMulticlusterCloudProvider,
+	)
 }
 
-func (ctrl *KarmadaController) deleteUnableGCResources(karmada *installv1alpha1.Karmada) error {
-	bindingName := constants.FireflyComponentKarmadaManager
-	err := ctrl.client.RbacV1beta1().ClusterRoleBindings().Delete(context.Background(), bindingName, metav1.DeleteOptions{})
+func (ctrl *KarmadaController) EnsureScheduler(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	return ctrl.reconcileConcurrently(ctx, karmada,
+		ctrl.EnsureKarmadaScheduler,
+		ctrl.EnsureKarmadaDescheduler,
+	)
+}
+
+func (ctrl *KarmadaController) deleteUnableGCResources(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	// the objects in the additional host clusters aren't owned by the karmada.
+	if err := ctrl.RemoveHostClusters(ctx, karmada); err != nil {
+		return err
+	}
+	if karmada.Spec.Chart != nil {
+		return ctrl.RemoveChart(ctx, karmada)
+	}
+
+	bindingName := fireflyKarmadaManagerClusterRoleBindingName(karmada)
+	err := ctrl.client.RbacV1().ClusterRoleBindings().Delete(context.Background(), bindingName, metav1.DeleteOptions{})
 	return client.IgnoreNotFound(err)
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package karmada
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -29,15 +30,25 @@ import (
 	"github.com/carlory/firefly/pkg/constants"
 	"github.com/carlory/firefly/pkg/scheme"
 	"github.com/carlory/firefly/pkg/util"
-	clientutil "github.com/carlory/firefly/pkg/util/client"
 	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
 )
 
-func (ctrl *KarmadaController) EnsureKarmadaControllerManager(karmada *installv1alpha1.Karmada) error {
-	return ctrl.EnsureKarmadaControllerManagerDeployment(karmada)
+func (ctrl *KarmadaController) EnsureKarmadaControllerManager(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	return ctrl.EnsureKarmadaControllerManagerDeployment(ctx, karmada)
 }
 
-func (ctrl *KarmadaController) EnsureKarmadaControllerManagerDeployment(karmada *installv1alpha1.Karmada) error {
+func (ctrl *KarmadaController) EnsureKarmadaControllerManagerDeployment(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	deployment, err := karmadaControllerManagerDeployment(karmada)
+	if err != nil {
+		return err
+	}
+	ctrl.annotateForScraping(karmada, constants.KarmadaComponentControllerManager, &deployment.Spec.Template)
+	return ctrl.ensureDeployment(ctx, karmada, deployment, nil)
+}
+
+// karmadaControllerManagerDeployment returns the karmada-controller-manager deployment of the karmada.
+func karmadaControllerManagerDeployment(karmada *installv1alpha1.Karmada) (*appsv1.Deployment, error) {
 	componentName := constants.KarmadaComponentControllerManager
 	kcm := karmada.Spec.ControllerManager.KarmadaControllerManager
 
@@ -63,8 +74,8 @@ func (ctrl *KarmadaController) EnsureKarmadaControllerManagerDeployment(karmada
 		"secure-port":                     "10357",
 		"v":                               "4",
 	}
-	if kcm.Controllers != nil {
-		defaultArgs["controllers"] = strings.Join(kcm.Controllers, ",")
+	if controllers := karmadaControllers(karmada); controllers != nil {
+		defaultArgs["controllers"] = strings.Join(controllers, ",")
 	}
 	featureGates := karmada.Spec.FeatureGates
 	for feature, enabled := range featureGates {
@@ -74,7 +85,7 @@ func (ctrl *KarmadaController) EnsureKarmadaControllerManagerDeployment(karmada
 			defaultArgs["feature-gates"] = fmt.Sprintf("%s,%s=%t", defaultArgs["feature-gates"], feature, enabled)
 		}
 	}
-	computedArgs := maputil.MergeStringMaps(defaultArgs, kcm.ExtraArgs)
+	computedArgs := maputil.MergeStringMaps(defaultArgs, util.LoggingArgs(kcm.Logging), kcm.ExtraArgs)
 	args := maputil.ConvertToCommandOrArgs(computedArgs)
 
 	deployment := &appsv1.Deployment{
@@ -127,6 +138,11 @@ func (ctrl *KarmadaController) EnsureKarmadaControllerManagerDeployment(karmada
 			},
 		},
 	}
+	util.ApplyComponentExtras(&deployment.Spec.Template.Spec, kcm.ComponentExtras)
+	util.SetKarmadaInstanceLabel(deployment, karmada.Name)
 	controllerutil.SetOwnerReference(karmada, deployment, scheme.Scheme)
-	return clientutil.CreateOrUpdateDeployment(ctrl.client, deployment)
+	if err := patchutil.Apply(deployment, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return deployment, nil
 }
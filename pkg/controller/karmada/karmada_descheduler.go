@@ -18,11 +18,13 @@ package karmada
 
 import (
 	"context"
+	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -30,11 +32,20 @@ import (
 	"github.com/carlory/firefly/pkg/constants"
 	"github.com/carlory/firefly/pkg/scheme"
 	"github.com/carlory/firefly/pkg/util"
-	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/audit"
 	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
 )
 
-func (ctrl *KarmadaController) EnsureKarmadaDescheduler(karmada *installv1alpha1.Karmada) error {
+func (ctrl *KarmadaController) EnsureKarmadaDescheduler(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if karmadaDeschedulerEnabled(karmada) {
+		return ctrl.EnsureKarmadaDeschedulerDeployment(ctx, karmada)
+	}
+	return ctrl.RemoveKarmadaDescheduler(ctx, karmada)
+}
+
+// karmadaDeschedulerEnabled returns whether the karmada-descheduler is installed for the karmada.
+func karmadaDeschedulerEnabled(karmada *installv1alpha1.Karmada) bool {
 	var enabled bool
 	if karmada.Spec.Scheduler.KarmadaDescheduler.Enable != nil {
 		enabled = *karmada.Spec.Scheduler.KarmadaDescheduler.Enable
@@ -42,20 +53,34 @@ func (ctrl *KarmadaController) EnsureKarmadaDescheduler(karmada *installv1alpha1
 	if version.CompareKubeAwareVersionStrings("v1.1.0", karmada.Spec.KarmadaVersion) < 0 {
 		enabled = false
 	}
-
-	if enabled {
-		return ctrl.EnsureKarmadaDeschedulerDeployment(karmada)
+	// the descheduler evicts replicas based on the results of the estimators.
+	if !pointer.BoolDeref(karmada.Spec.Scheduler.KarmadaSchedulerEstimator.Enable, true) {
+		enabled = false
 	}
-	return ctrl.RemoveKarmadaDescheduler(karmada)
+	return enabled
 }
 
-func (ctrl *KarmadaController) RemoveKarmadaDescheduler(karmada *installv1alpha1.Karmada) error {
+func (ctrl *KarmadaController) RemoveKarmadaDescheduler(ctx context.Context, karmada *installv1alpha1.Karmada) error {
 	componentName := constants.KarmadaComponentDescheduler
-	err := ctrl.client.AppsV1().Deployments(karmada.Namespace).Delete(context.TODO(), componentName, metav1.DeleteOptions{})
-	return client.IgnoreNotFound(err)
+	err := ctrl.client.AppsV1().Deployments(karmada.Namespace).Delete(ctx, componentName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Deployment", Namespace: karmada.Namespace, Name: componentName}, err)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	return ctrl.RemovePodDisruptionBudget(ctx, karmada, componentName)
 }
 
-func (ctrl *KarmadaController) EnsureKarmadaDeschedulerDeployment(karmada *installv1alpha1.Karmada) error {
+func (ctrl *KarmadaController) EnsureKarmadaDeschedulerDeployment(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	deployment, err := karmadaDeschedulerDeployment(karmada)
+	if err != nil {
+		return err
+	}
+	ctrl.annotateForScraping(karmada, constants.KarmadaComponentDescheduler, &deployment.Spec.Template)
+	return ctrl.ensureDeployment(ctx, karmada, deployment, nil)
+}
+
+// karmadaDeschedulerDeployment returns the karmada-descheduler deployment of the karmada.
+func karmadaDeschedulerDeployment(karmada *installv1alpha1.Karmada) (*appsv1.Deployment, error) {
 	componentName := constants.KarmadaComponentDescheduler
 	scheduler := karmada.Spec.Scheduler.KarmadaDescheduler
 
@@ -79,7 +104,11 @@ func (ctrl *KarmadaController) EnsureKarmadaDeschedulerDeployment(karmada *insta
 		"kubeconfig":   "/etc/kubeconfig",
 		"v":            "4",
 	}
-	computedArgs := maputil.MergeStringMaps(defaultArgs, scheduler.ExtraArgs)
+	if port := karmada.Spec.Scheduler.KarmadaSchedulerEstimator.Service.Port; port != 0 {
+		defaultArgs["scheduler-estimator-port"] = strconv.Itoa(int(port))
+	}
+	setEstimatorClientTLSArgs(karmada, defaultArgs)
+	computedArgs := maputil.MergeStringMaps(defaultArgs, util.LoggingArgs(scheduler.Logging), scheduler.ExtraArgs)
 	args := maputil.ConvertToCommandOrArgs(computedArgs)
 
 	deployment := &appsv1.Deployment{
@@ -133,6 +162,13 @@ func (ctrl *KarmadaController) EnsureKarmadaDeschedulerDeployment(karmada *insta
 		},
 	}
 
+	mountEstimatorClientTLS(karmada, &deployment.Spec.Template.Spec)
+	util.ApplyComponentExtras(&deployment.Spec.Template.Spec, scheduler.ComponentExtras)
+	util.SetKarmadaInstanceLabel(deployment, karmada.Name)
+
 	controllerutil.SetOwnerReference(karmada, deployment, scheme.Scheme)
-	return clientutil.CreateOrUpdateDeployment(ctrl.client, deployment)
+	if err := patchutil.Apply(deployment, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return deployment, nil
 }
@@ -0,0 +1,537 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	aggregator "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+// metricsReaderClusterRoleName is the name of the ClusterRole aggregated to the view, edit and admin roles
+// of the karmada, which allows reading the metrics served by the karmada-metrics-adapter.
+const metricsReaderClusterRoleName = "firefly:aggregated-metrics-reader"
+
+// federatedHPAControllers are the controllers of the karmada-controller-manager scaling the workloads by
+// the FederatedHPAs and the CronFederatedHPAs.
+var federatedHPAControllers = []string{"federatedHorizontalPodAutoscaler", "cronFederatedHorizontalPodAutoscaler"}
+
+// metricsAPIServices are the APIServices served by the karmada-metrics-adapter.
+var metricsAPIServices = []apiregistrationv1.APIServiceSpec{
+	{Group: "metrics.k8s.io", Version: "v1beta1", GroupPriorityMinimum: 100, VersionPriority: 100},
+	{Group: "custom.metrics.k8s.io", Version: "v1beta1", GroupPriorityMinimum: 100, VersionPriority: 100},
+	{Group: "custom.metrics.k8s.io", Version: "v1beta2", GroupPriorityMinimum: 100, VersionPriority: 200},
+}
+
+// metricsAPIServiceName returns the name of the APIService of the group version.
+func metricsAPIServiceName(spec apiregistrationv1.APIServiceSpec) string {
+	return fmt.Sprintf("%s.%s", spec.Version, spec.Group)
+}
+
+// karmadaMetricsAdapterEnabled returns whether the FederatedHPA is enabled for the karmada, that is the
+// karmada-metrics-adapter is installed.
+func karmadaMetricsAdapterEnabled(karmada *installv1alpha1.Karmada) bool {
+	return pointer.BoolDeref(karmada.Spec.APIServer.KarmadaMetricsAdapter.Enable, false)
+}
+
+// karmadaControllers returns the controllers enabled in the karmada-controller-manager, with the
+// controllers of the FederatedHPA added if it's enabled, or nil if they're left to its defaults.
+func karmadaControllers(karmada *installv1alpha1.Karmada) []string {
+	controllers := karmada.Spec.ControllerManager.KarmadaControllerManager.Controllers
+	if controllers == nil || !karmadaMetricsAdapterEnabled(karmada) {
+		return controllers
+	}
+
+	disabled := sets.NewString()
+	for _, controller := range federatedHPAControllers {
+		disabled.Insert("-" + controller)
+	}
+	var enabled []string
+	for _, controller := range controllers {
+		if !disabled.Has(controller) {
+			enabled = append(enabled, controller)
+		}
+	}
+	// the controllers are enabled by "*", unless they're disabled explicitly.
+	if listed := sets.NewString(enabled...); !listed.Has("*") {
+		for _, controller := range federatedHPAControllers {
+			if !listed.Has(controller) {
+				enabled = append(enabled, controller)
+			}
+		}
+	}
+	return enabled
+}
+
+// EnsureKarmadaMetricsAdapter installs the components of the FederatedHPA if it's enabled and reports
+// whether the metrics of the member clusters are served by the HPAReady condition, otherwise removes them.
+func (ctrl *KarmadaController) EnsureKarmadaMetricsAdapter(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if !karmadaMetricsAdapterEnabled(karmada) {
+		if err := ctrl.RemoveKarmadaMetricsAdapter(ctx, karmada); err != nil {
+			return err
+		}
+		return ctrl.updateKarmadaCondition(ctx, karmada, installv1alpha1.KarmadaConditionHPAReady, nil)
+	}
+
+	if err := ctrl.ensureKarmadaMetricsAdapter(ctx, karmada); err != nil {
+		if updateErr := ctrl.updateKarmadaCondition(ctx, karmada, installv1alpha1.KarmadaConditionHPAReady, &metav1.Condition{
+			Status:  metav1.ConditionFalse,
+			Reason:  "InstallFailed",
+			Message: err.Error(),
+		}); updateErr != nil {
+			klog.ErrorS(updateErr, "Failed to update karmada status", "karmada", klog.KObj(karmada))
+		}
+		return err
+	}
+
+	condition := &metav1.Condition{
+		Status:  metav1.ConditionTrue,
+		Reason:  "Ready",
+		Message: "karmada-metrics-adapter is ready and the metrics of the member clusters are served",
+	}
+	if err := ctrl.verifyMetricsPipeline(ctx, karmada); err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "MetricsUnavailable"
+		condition.Message = err.Error()
+	}
+	return ctrl.updateKarmadaCondition(ctx, karmada, installv1alpha1.KarmadaConditionHPAReady, condition)
+}
+
+func (ctrl *KarmadaController) ensureKarmadaMetricsAdapter(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if err := ctrl.EnsureKarmadaMetricsAdapterService(ctx, karmada); err != nil {
+		return err
+	}
+	if err := ctrl.EnsureKarmadaMetricsAdapterDeployment(ctx, karmada); err != nil {
+		return err
+	}
+	podLabel := fmt.Sprintf("app=%s", constants.KarmadaComponentMetricsAdapter)
+	err := util.NewKubeWaiter(ctrl.client, 10*time.Second).WaitForPodsWithLabel(karmada.Namespace, podLabel)
+	if err != nil {
+		return err
+	}
+	return ctrl.EnsureKarmadaMetricsAdapterAPIServices(ctx, karmada)
+}
+
+// verifyMetricsPipeline verifies that the metrics APIServices are available in the karmada-apiserver and
+// the metrics of the pods of the member clusters are served through them, which the FederatedHPAs are
+// scaled by.
+func (ctrl *KarmadaController) verifyMetricsPipeline(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+	aaClient, err := aggregator.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	for _, spec := range metricsAPIServices {
+		name := metricsAPIServiceName(spec)
+		apisvc, err := aaClient.ApiregistrationV1().APIServices().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if !apiServiceAvailable(apisvc) {
+			return fmt.Errorf("APIService %s is not available", name)
+		}
+	}
+
+	// the pods are listed in one page, the metrics are read from all the member clusters anyway.
+	if err := kubeClient.Discovery().RESTClient().Get().AbsPath("/apis/metrics.k8s.io/v1beta1/pods").Param("limit", "1").Do(ctx).Error(); err != nil {
+		return fmt.Errorf("failed to read the resource metrics of the member clusters: %v", err)
+	}
+	if err := kubeClient.Discovery().RESTClient().Get().AbsPath("/apis/custom.metrics.k8s.io/v1beta2").Do(ctx).Error(); err != nil {
+		return fmt.Errorf("failed to discover the custom metrics of the member clusters: %v", err)
+	}
+	return nil
+}
+
+// apiServiceAvailable returns whether the APIService is reported available by the karmada-apiserver.
+func apiServiceAvailable(apisvc *apiregistrationv1.APIService) bool {
+	for _, condition := range apisvc.Status.Conditions {
+		if condition.Type == apiregistrationv1.Available {
+			return condition.Status == apiregistrationv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func (ctrl *KarmadaController) RemoveKarmadaMetricsAdapter(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	componentName := constants.KarmadaComponentMetricsAdapter
+
+	// the APIServices are removed first, otherwise the karmada-apiserver keeps discovering
+	// unavailable api groups. They're only registered if the component has been enabled.
+	if meta.FindStatusCondition(karmada.Status.Conditions, installv1alpha1.KarmadaConditionHPAReady) != nil {
+		if err := ctrl.RemoveKarmadaMetricsAdapterAPIServices(ctx, karmada); err != nil {
+			return err
+		}
+	}
+
+	err := ctrl.client.AppsV1().Deployments(karmada.Namespace).Delete(ctx, componentName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Deployment", Namespace: karmada.Namespace, Name: componentName}, err)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	if err := ctrl.RemovePodDisruptionBudget(ctx, karmada, componentName); err != nil {
+		return err
+	}
+	err = ctrl.client.CoreV1().Services(karmada.Namespace).Delete(ctx, componentName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Service", Namespace: karmada.Namespace, Name: componentName}, err)
+	return client.IgnoreNotFound(err)
+}
+
+func (ctrl *KarmadaController) RemoveKarmadaMetricsAdapterAPIServices(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+	aaClient, err := aggregator.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	for _, spec := range metricsAPIServices {
+		name := metricsAPIServiceName(spec)
+		err = aaClient.ApiregistrationV1().APIServices().Delete(ctx, name, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "APIService", Name: name}, err)
+		if client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	err = kubeClient.RbacV1().ClusterRoles().Delete(ctx, metricsReaderClusterRoleName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "ClusterRole", Name: metricsReaderClusterRoleName}, err)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	err = kubeClient.CoreV1().Services(constants.KarmadaSystemNamespace).Delete(ctx, constants.KarmadaComponentMetricsAdapter, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Service", Namespace: constants.KarmadaSystemNamespace, Name: constants.KarmadaComponentMetricsAdapter}, err)
+	return client.IgnoreNotFound(err)
+}
+
+func (ctrl *KarmadaController) EnsureKarmadaMetricsAdapterService(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	svc, err := karmadaMetricsAdapterService(karmada)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateService(ctx, ctrl.client, svc)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, svc, result)
+	return err
+}
+
+// karmadaMetricsAdapterService returns the karmada-metrics-adapter service of the karmada.
+func karmadaMetricsAdapterService(karmada *installv1alpha1.Karmada) (*corev1.Service, error) {
+	componentName := constants.KarmadaComponentMetricsAdapter
+	svc := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      componentName,
+			Namespace: karmada.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: map[string]string{"app": componentName},
+			Ports: []corev1.ServicePort{
+				{
+					Protocol: corev1.ProtocolTCP,
+					Port:     443,
+					TargetPort: intstr.IntOrString{
+						Type:   intstr.Int,
+						IntVal: 443,
+					},
+				},
+			},
+		},
+	}
+	util.SetKarmadaInstanceLabel(svc, karmada.Name)
+	controllerutil.SetOwnerReference(karmada, svc, scheme.Scheme)
+	if err := patchutil.Apply(svc, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+func (ctrl *KarmadaController) EnsureKarmadaMetricsAdapterDeployment(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	deployment, err := karmadaMetricsAdapterDeployment(karmada)
+	if err != nil {
+		return err
+	}
+	return ctrl.ensureDeployment(ctx, karmada, deployment, nil)
+}
+
+// karmadaMetricsAdapterDeployment returns the karmada-metrics-adapter deployment of the karmada.
+func karmadaMetricsAdapterDeployment(karmada *installv1alpha1.Karmada) (*appsv1.Deployment, error) {
+	componentName := constants.KarmadaComponentMetricsAdapter
+	adapter := karmada.Spec.APIServer.KarmadaMetricsAdapter
+	repository := karmada.Spec.ImageRepository
+	if adapter.ImageRepository != "" {
+		repository = adapter.ImageRepository
+	}
+	imageName := constants.KarmadaComponentMetricsAdapter
+	if adapter.ImageName != "" {
+		imageName = adapter.ImageName
+	}
+	tag := karmada.Spec.KarmadaVersion
+	if adapter.ImageTag != "" {
+		tag = adapter.ImageTag
+	}
+
+	defaultArgs := map[string]string{
+		"kubeconfig":                "/etc/kubeconfig",
+		"authentication-kubeconfig": "/etc/kubeconfig",
+		"authorization-kubeconfig":  "/etc/kubeconfig",
+		"client-ca-file":            "/etc/kubernetes/pki/ca.crt",
+		"audit-log-path":            "-",
+		"audit-log-maxage":          "0",
+		"audit-log-maxbackup":       "0",
+		"tls-cert-file":             "/etc/kubernetes/pki/apiserver.crt",
+		"tls-private-key-file":      "/etc/kubernetes/pki/apiserver.key",
+		"secure-port":               "443",
+	}
+	computedArgs := maputil.MergeStringMaps(defaultArgs, util.LoggingArgs(adapter.Logging), adapter.ExtraArgs)
+	args := maputil.ConvertToCommandOrArgs(computedArgs)
+
+	probe := func(path string) corev1.ProbeHandler {
+		return corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: path,
+				Port: intstr.IntOrString{
+					Type:   intstr.Int,
+					IntVal: 443,
+				},
+				Scheme: corev1.URISchemeHTTPS,
+			},
+		}
+	}
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      componentName,
+			Namespace: karmada.Namespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": componentName},
+			},
+			Replicas: adapter.Replicas,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": componentName},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:            componentName,
+							Image:           util.ComponentImageName(repository, imageName, tag),
+							ImagePullPolicy: "IfNotPresent",
+							Command:         []string{"/bin/karmada-metrics-adapter"},
+							Args:            args,
+							Resources:       adapter.Resources,
+							LivenessProbe: &corev1.Probe{
+								FailureThreshold:    8,
+								ProbeHandler:        probe("/livez"),
+								InitialDelaySeconds: 10,
+								PeriodSeconds:       10,
+								SuccessThreshold:    1,
+								TimeoutSeconds:      15,
+							},
+							ReadinessProbe: &corev1.Probe{
+								FailureThreshold: 3,
+								ProbeHandler:     probe("/readyz"),
+								PeriodSeconds:    1,
+								SuccessThreshold: 1,
+								TimeoutSeconds:   15,
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "k8s-certs",
+									MountPath: "/etc/kubernetes/pki",
+									ReadOnly:  true,
+								},
+								{
+									Name:      "kubeconfig",
+									MountPath: "/etc/kubeconfig",
+									SubPath:   "kubeconfig",
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "k8s-certs",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: "karmada-cert",
+								},
+							},
+						},
+						{
+							Name: "kubeconfig",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: "karmada-kubeconfig",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	util.ApplyComponentExtras(&deployment.Spec.Template.Spec, adapter.ComponentExtras)
+	util.SetKarmadaInstanceLabel(deployment, karmada.Name)
+
+	controllerutil.SetOwnerReference(karmada, deployment, scheme.Scheme)
+	if err := patchutil.Apply(deployment, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return deployment, nil
+}
+
+// EnsureKarmadaMetricsAdapterAPIServices registers the karmada-metrics-adapter as the metrics APIServices
+// of the karmada-apiserver, and allows the view, edit and admin roles to read the metrics.
+func (ctrl *KarmadaController) EnsureKarmadaMetricsAdapterAPIServices(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+	aaClient, err := aggregator.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	svc := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      constants.KarmadaComponentMetricsAdapter,
+			Namespace: constants.KarmadaSystemNamespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:         corev1.ServiceTypeExternalName,
+			ExternalName: fmt.Sprintf("%s.%s.svc", constants.KarmadaComponentMetricsAdapter, karmada.Namespace),
+		},
+	}
+	if err := patchutil.Apply(svc, karmada.Spec.Patches); err != nil {
+		return err
+	}
+	if _, err = clientutil.CreateOrUpdateService(ctx, kubeClient, svc); err != nil {
+		return err
+	}
+
+	clusterRole := &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "ClusterRole",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: metricsReaderClusterRoleName,
+			Labels: map[string]string{
+				"rbac.authorization.k8s.io/aggregate-to-view":  "true",
+				"rbac.authorization.k8s.io/aggregate-to-edit":  "true",
+				"rbac.authorization.k8s.io/aggregate-to-admin": "true",
+			},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"metrics.k8s.io", "custom.metrics.k8s.io"},
+				Resources: []string{"*"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+		},
+	}
+	if _, err := clientutil.CreateOrUpdateClusterRole(ctx, kubeClient, clusterRole); err != nil {
+		return err
+	}
+
+	for _, spec := range metricsAPIServices {
+		apisvc := &apiregistrationv1.APIService{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "APIService",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   metricsAPIServiceName(spec),
+				Labels: map[string]string{"app": constants.KarmadaComponentMetricsAdapter, "apiserver": "true"},
+			},
+			Spec: apiregistrationv1.APIServiceSpec{
+				InsecureSkipTLSVerify: true,
+				Group:                 spec.Group,
+				GroupPriorityMinimum:  spec.GroupPriorityMinimum,
+				Service: &apiregistrationv1.ServiceReference{
+					Name:      constants.KarmadaComponentMetricsAdapter,
+					Namespace: constants.KarmadaSystemNamespace,
+				},
+				Version:         spec.Version,
+				VersionPriority: spec.VersionPriority,
+			},
+		}
+		if err := patchutil.Apply(apisvc, karmada.Spec.Patches); err != nil {
+			return err
+		}
+		result, err := clientutil.CreateOrUpdateAPIService(ctx, aaClient, apisvc)
+		clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, apisvc, result)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -17,26 +17,151 @@ limitations under the License.
 package karmada
 
 import (
+	"context"
 	"fmt"
+	"path"
+	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
 
 	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
 	"github.com/carlory/firefly/pkg/constants"
 	"github.com/carlory/firefly/pkg/scheme"
 	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
 	clientutil "github.com/carlory/firefly/pkg/util/client"
 	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
 )
 
-func (ctrl *KarmadaController) EnsureKarmadaScheduler(karmada *installv1alpha1.Karmada) error {
-	return ctrl.EnsureKarmadaSchedulerDeployment(karmada)
+const (
+	// karmadaSchedulerConfigName is the name of the configmap holding the configuration of the karmada-scheduler.
+	karmadaSchedulerConfigName = "karmada-scheduler-config"
+	karmadaSchedulerConfigDir  = "/etc/karmada-scheduler"
+	karmadaSchedulerConfigKey  = "config.yaml"
+	// defaultSchedulerName is the name of the default profile of the karmada-scheduler.
+	defaultSchedulerName = "default-scheduler"
+)
+
+// schedulerConfiguration is the configuration file of the karmada-scheduler.
+type schedulerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	Profiles         []installv1alpha1.KarmadaSchedulerProfile `json:"profiles"`
+	EstimatorTimeout *metav1.Duration                          `json:"estimatorTimeout,omitempty"`
+}
+
+func (ctrl *KarmadaController) EnsureKarmadaScheduler(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if err := ctrl.EnsureKarmadaSchedulerConfig(ctx, karmada); err != nil {
+		return err
+	}
+	return ctrl.EnsureKarmadaSchedulerDeployment(ctx, karmada)
+}
+
+// EnsureKarmadaSchedulerConfig creates or updates the configmap holding the configuration of the
+// karmada-scheduler if it's set by the karmada, otherwise deletes it.
+func (ctrl *KarmadaController) EnsureKarmadaSchedulerConfig(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if karmada.Spec.Scheduler.KarmadaScheduler.Config == nil {
+		err := ctrl.client.CoreV1().ConfigMaps(karmada.Namespace).Delete(ctx, karmadaSchedulerConfigName, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "ConfigMap", Namespace: karmada.Namespace, Name: karmadaSchedulerConfigName}, err)
+		return client.IgnoreNotFound(err)
+	}
+
+	cm, err := karmadaSchedulerConfigMap(karmada)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateConfigMap(ctx, ctrl.client, cm)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, cm, result)
+	return err
+}
+
+// karmadaSchedulerConfigMap returns the configmap holding the configuration of the karmada-scheduler.
+func karmadaSchedulerConfigMap(karmada *installv1alpha1.Karmada) (*corev1.ConfigMap, error) {
+	config, err := karmadaSchedulerConfig(karmada)
+	if err != nil {
+		return nil, err
+	}
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      karmadaSchedulerConfigName,
+			Namespace: karmada.Namespace,
+		},
+		Data: map[string]string{
+			karmadaSchedulerConfigKey: config,
+		},
+	}
+	util.SetKarmadaInstanceLabel(cm, karmada.Name)
+
+	controllerutil.SetOwnerReference(karmada, cm, scheme.Scheme)
+	if err := patchutil.Apply(cm, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
+
+// karmadaSchedulerConfig renders the configuration file of the karmada-scheduler, or returns an empty
+// string if its configuration isn't set by the karmada. The plugins of the configuration are the ones
+// of the default profile followed by the additional profiles.
+func karmadaSchedulerConfig(karmada *installv1alpha1.Karmada) (string, error) {
+	spec := karmada.Spec.Scheduler.KarmadaScheduler.Config
+	if spec == nil {
+		return "", nil
+	}
+
+	config := schedulerConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "scheduler.config.karmada.io/v1alpha1",
+			Kind:       "KarmadaSchedulerConfiguration",
+		},
+		Profiles: []installv1alpha1.KarmadaSchedulerProfile{
+			{SchedulerName: defaultSchedulerName, Plugins: spec.Plugins},
+		},
+	}
+	names := sets.NewString(defaultSchedulerName)
+	for _, profile := range spec.Profiles {
+		if profile.SchedulerName == "" {
+			return "", fmt.Errorf("karmada-scheduler profile has no schedulerName")
+		}
+		if names.Has(profile.SchedulerName) {
+			return "", fmt.Errorf("duplicate karmada-scheduler profile %q", profile.SchedulerName)
+		}
+		names.Insert(profile.SchedulerName)
+		config.Profiles = append(config.Profiles, profile)
+	}
+	if pointer.BoolDeref(karmada.Spec.Scheduler.KarmadaSchedulerEstimator.Enable, true) {
+		config.EstimatorTimeout = spec.EstimatorTimeout
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (ctrl *KarmadaController) EnsureKarmadaSchedulerDeployment(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	deployment, err := karmadaSchedulerDeployment(karmada)
+	if err != nil {
+		return err
+	}
+	ctrl.annotateForScraping(karmada, constants.KarmadaComponentScheduler, &deployment.Spec.Template)
+	return ctrl.ensureDeployment(ctx, karmada, deployment, nil)
 }
 
-func (ctrl *KarmadaController) EnsureKarmadaSchedulerDeployment(karmada *installv1alpha1.Karmada) error {
+// karmadaSchedulerDeployment returns the karmada-scheduler deployment of the karmada.
+func karmadaSchedulerDeployment(karmada *installv1alpha1.Karmada) (*appsv1.Deployment, error) {
 	componentName := constants.KarmadaComponentScheduler
 	scheduler := karmada.Spec.Scheduler.KarmadaScheduler
 
@@ -55,13 +180,25 @@ func (ctrl *KarmadaController) EnsureKarmadaSchedulerDeployment(karmada *install
 		tag = scheduler.ImageTag
 	}
 
+	estimatorEnabled := pointer.BoolDeref(karmada.Spec.Scheduler.KarmadaSchedulerEstimator.Enable, true)
 	defaultArgs := map[string]string{
 		"bind-address":               "0.0.0.0",
 		"kubeconfig":                 "/etc/kubeconfig",
 		"secure-port":                "10351",
-		"enable-scheduler-estimator": "true",
+		"enable-scheduler-estimator": strconv.FormatBool(estimatorEnabled),
 		"v":                          "4",
 	}
+	if port := karmada.Spec.Scheduler.KarmadaSchedulerEstimator.Service.Port; estimatorEnabled && port != 0 {
+		defaultArgs["scheduler-estimator-port"] = strconv.Itoa(int(port))
+	}
+	setEstimatorClientTLSArgs(karmada, defaultArgs)
+	config, err := karmadaSchedulerConfig(karmada)
+	if err != nil {
+		return nil, err
+	}
+	if config != "" {
+		defaultArgs["config"] = path.Join(karmadaSchedulerConfigDir, karmadaSchedulerConfigKey)
+	}
 	featureGates := karmada.Spec.FeatureGates
 	for feature, enabled := range featureGates {
 		if defaultArgs["feature-gates"] == "" {
@@ -70,7 +207,7 @@ func (ctrl *KarmadaController) EnsureKarmadaSchedulerDeployment(karmada *install
 			defaultArgs["feature-gates"] = fmt.Sprintf("%s,%s=%t", defaultArgs["feature-gates"], feature, enabled)
 		}
 	}
-	computedArgs := maputil.MergeStringMaps(defaultArgs, scheduler.ExtraArgs)
+	computedArgs := maputil.MergeStringMaps(defaultArgs, util.LoggingArgs(scheduler.Logging), scheduler.ExtraArgs)
 	args := maputil.ConvertToCommandOrArgs(computedArgs)
 
 	deployment := &appsv1.Deployment{
@@ -124,6 +261,41 @@ func (ctrl *KarmadaController) EnsureKarmadaSchedulerDeployment(karmada *install
 		},
 	}
 
+	mountEstimatorClientTLS(karmada, &deployment.Spec.Template.Spec)
+	mountKarmadaSchedulerConfig(&deployment.Spec.Template, config)
+	util.ApplyComponentExtras(&deployment.Spec.Template.Spec, scheduler.ComponentExtras)
+	util.SetKarmadaInstanceLabel(deployment, karmada.Name)
+
 	controllerutil.SetOwnerReference(karmada, deployment, scheme.Scheme)
-	return clientutil.CreateOrUpdateDeployment(ctrl.client, deployment)
+	if err := patchutil.Apply(deployment, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return deployment, nil
+}
+
+// mountKarmadaSchedulerConfig mounts the configmap holding the configuration of the karmada-scheduler
+// into its pod if the configuration is set, and annotates the pod with the hash of the configuration.
+func mountKarmadaSchedulerConfig(template *corev1.PodTemplateSpec, config string) {
+	if config == "" {
+		return
+	}
+	template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+		Name: "config",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: karmadaSchedulerConfigName},
+			},
+		},
+	})
+	container := &template.Spec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "config",
+		MountPath: karmadaSchedulerConfigDir,
+		ReadOnly:  true,
+	})
+
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[constants.SchedulerConfigHashAnnotation] = contentHash(config)
 }
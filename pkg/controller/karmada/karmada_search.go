@@ -0,0 +1,407 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	aggregator "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+const karmadaSearchAPIServiceName = "v1alpha1.search.karmada.io"
+
+func (ctrl *KarmadaController) EnsureKarmadaSearch(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if !karmadaSearchEnabled(karmada) {
+		if err := ctrl.RemoveKarmadaSearch(ctx, karmada); err != nil {
+			return err
+		}
+		return ctrl.updateKarmadaCondition(ctx, karmada, installv1alpha1.KarmadaConditionSearchReady, nil)
+	}
+
+	if err := ctrl.ensureKarmadaSearch(ctx, karmada); err != nil {
+		if updateErr := ctrl.updateKarmadaCondition(ctx, karmada, installv1alpha1.KarmadaConditionSearchReady, &metav1.Condition{
+			Status:  metav1.ConditionFalse,
+			Reason:  "InstallFailed",
+			Message: err.Error(),
+		}); updateErr != nil {
+			klog.ErrorS(updateErr, "Failed to update karmada status", "karmada", klog.KObj(karmada))
+		}
+		return err
+	}
+	return ctrl.updateKarmadaCondition(ctx, karmada, installv1alpha1.KarmadaConditionSearchReady, &metav1.Condition{
+		Status:  metav1.ConditionTrue,
+		Reason:  "Ready",
+		Message: "karmada-search is ready and its APIService is registered",
+	})
+}
+
+// karmadaSearchEnabled returns whether the karmada-search is installed for the karmada.
+func karmadaSearchEnabled(karmada *installv1alpha1.Karmada) bool {
+	return pointer.BoolDeref(karmada.Spec.APIServer.KarmadaSearch.Enable, false)
+}
+
+func (ctrl *KarmadaController) ensureKarmadaSearch(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if err := ctrl.EnsureKarmadaSearchService(ctx, karmada); err != nil {
+		return err
+	}
+	if err := ctrl.EnsureKarmadaSearchDeployment(ctx, karmada); err != nil {
+		return err
+	}
+	podLabel := fmt.Sprintf("app=%s", constants.KarmadaComponentSearch)
+	err := util.NewKubeWaiter(ctrl.client, 10*time.Second).WaitForPodsWithLabel(karmada.Namespace, podLabel)
+	if err != nil {
+		return err
+	}
+	return ctrl.EnsureKarmadaSearchAPIService(ctx, karmada)
+}
+
+func (ctrl *KarmadaController) RemoveKarmadaSearch(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	componentName := constants.KarmadaComponentSearch
+
+	// the APIService is removed first, otherwise the karmada-apiserver keeps discovering
+	// an unavailable api group. It's only registered if the component has been enabled.
+	if meta.FindStatusCondition(karmada.Status.Conditions, installv1alpha1.KarmadaConditionSearchReady) != nil {
+		if err := ctrl.RemoveKarmadaSearchAPIService(ctx, karmada); err != nil {
+			return err
+		}
+	}
+
+	err := ctrl.client.AppsV1().Deployments(karmada.Namespace).Delete(ctx, componentName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Deployment", Namespace: karmada.Namespace, Name: componentName}, err)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	if err := ctrl.RemovePodDisruptionBudget(ctx, karmada, componentName); err != nil {
+		return err
+	}
+	err = ctrl.client.CoreV1().Services(karmada.Namespace).Delete(ctx, componentName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Service", Namespace: karmada.Namespace, Name: componentName}, err)
+	return client.IgnoreNotFound(err)
+}
+
+func (ctrl *KarmadaController) RemoveKarmadaSearchAPIService(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+	aaClient, err := aggregator.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	err = aaClient.ApiregistrationV1().APIServices().Delete(ctx, karmadaSearchAPIServiceName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "APIService", Name: karmadaSearchAPIServiceName}, err)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	err = kubeClient.CoreV1().Services(constants.KarmadaSystemNamespace).Delete(ctx, constants.KarmadaComponentSearch, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Service", Namespace: constants.KarmadaSystemNamespace, Name: constants.KarmadaComponentSearch}, err)
+	return client.IgnoreNotFound(err)
+}
+
+func (ctrl *KarmadaController) EnsureKarmadaSearchService(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	svc, err := karmadaSearchService(karmada)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateService(ctx, ctrl.client, svc)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, svc, result)
+	return err
+}
+
+// karmadaSearchService returns the karmada-search service of the karmada.
+func karmadaSearchService(karmada *installv1alpha1.Karmada) (*corev1.Service, error) {
+	componentName := constants.KarmadaComponentSearch
+	svc := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      componentName,
+			Namespace: karmada.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: map[string]string{"app": componentName},
+			Ports: []corev1.ServicePort{
+				{
+					Protocol: corev1.ProtocolTCP,
+					Port:     443,
+					TargetPort: intstr.IntOrString{
+						Type:   intstr.Int,
+						IntVal: 443,
+					},
+				},
+			},
+		},
+	}
+	util.SetKarmadaInstanceLabel(svc, karmada.Name)
+	controllerutil.SetOwnerReference(karmada, svc, scheme.Scheme)
+	if err := patchutil.Apply(svc, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+func (ctrl *KarmadaController) EnsureKarmadaSearchDeployment(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	deployment, err := karmadaSearchDeployment(karmada)
+	if err != nil {
+		return err
+	}
+	return ctrl.ensureDeployment(ctx, karmada, deployment, nil)
+}
+
+// karmadaSearchDeployment returns the karmada-search deployment of the karmada.
+func karmadaSearchDeployment(karmada *installv1alpha1.Karmada) (*appsv1.Deployment, error) {
+	componentName := constants.KarmadaComponentSearch
+	search := karmada.Spec.APIServer.KarmadaSearch
+	repository := karmada.Spec.ImageRepository
+	if search.ImageRepository != "" {
+		repository = search.ImageRepository
+	}
+	imageName := constants.KarmadaComponentSearch
+	if search.ImageName != "" {
+		imageName = search.ImageName
+	}
+	tag := karmada.Spec.KarmadaVersion
+	if search.ImageTag != "" {
+		tag = search.ImageTag
+	}
+
+	defaultArgs := map[string]string{
+		"kubeconfig":                "/etc/kubeconfig",
+		"authentication-kubeconfig": "/etc/kubeconfig",
+		"authorization-kubeconfig":  "/etc/kubeconfig",
+		"etcd-cafile":               "/etc/kubernetes/pki/etcd-ca.crt",
+		"etcd-certfile":             "/etc/kubernetes/pki/etcd-client.crt",
+		"etcd-keyfile":              "/etc/kubernetes/pki/etcd-client.key",
+		"etcd-servers":              fmt.Sprintf("https://%s.%s.svc:2379", constants.KarmadaComponentEtcd, karmada.Namespace),
+		"audit-log-path":            "-",
+		"feature-gates":             "APIPriorityAndFairness=false",
+		"audit-log-maxage":          "0",
+		"audit-log-maxbackup":       "0",
+		"tls-cert-file":             "/etc/kubernetes/pki/apiserver.crt",
+		"tls-private-key-file":      "/etc/kubernetes/pki/apiserver.key",
+	}
+	featureGates := karmada.Spec.FeatureGates
+	for feature, enabled := range featureGates {
+		if defaultArgs["feature-gates"] == "" {
+			defaultArgs["feature-gates"] = fmt.Sprintf("%s=%t", feature, enabled)
+		} else {
+			defaultArgs["feature-gates"] = fmt.Sprintf("%s,%s=%t", defaultArgs["feature-gates"], feature, enabled)
+		}
+	}
+	computedArgs := maputil.MergeStringMaps(defaultArgs, util.LoggingArgs(search.Logging), search.ExtraArgs)
+	args := maputil.ConvertToCommandOrArgs(computedArgs)
+
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      componentName,
+			Namespace: karmada.Namespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": componentName},
+			},
+			Replicas: search.Replicas,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": componentName},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:            "karmada-search",
+							Image:           util.ComponentImageName(repository, imageName, tag),
+							ImagePullPolicy: "IfNotPresent",
+							Command:         []string{"/bin/karmada-search"},
+							Args:            args,
+							Resources:       search.Resources,
+							LivenessProbe: &corev1.Probe{
+								FailureThreshold: 8,
+								ProbeHandler: corev1.ProbeHandler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path: "/livez",
+										Port: intstr.IntOrString{
+											Type:   intstr.Int,
+											IntVal: 443,
+										},
+										Scheme: corev1.URISchemeHTTPS,
+									},
+								},
+								InitialDelaySeconds: 10,
+								PeriodSeconds:       10,
+								SuccessThreshold:    1,
+								TimeoutSeconds:      15,
+							},
+							ReadinessProbe: &corev1.Probe{
+								FailureThreshold: 3,
+								ProbeHandler: corev1.ProbeHandler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path: "/readyz",
+										Port: intstr.IntOrString{
+											Type:   intstr.Int,
+											IntVal: 443,
+										},
+										Scheme: corev1.URISchemeHTTPS,
+									},
+								},
+								PeriodSeconds:    1,
+								SuccessThreshold: 1,
+								TimeoutSeconds:   15,
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "k8s-certs",
+									MountPath: "/etc/kubernetes/pki",
+									ReadOnly:  true,
+								},
+								{
+									Name:      "kubeconfig",
+									MountPath: "/etc/kubeconfig",
+									SubPath:   "kubeconfig",
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "k8s-certs",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: "karmada-cert",
+								},
+							},
+						},
+						{
+							Name: "kubeconfig",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: "karmada-kubeconfig",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	util.ApplyComponentExtras(&deployment.Spec.Template.Spec, search.ComponentExtras)
+	util.SetKarmadaInstanceLabel(deployment, karmada.Name)
+
+	controllerutil.SetOwnerReference(karmada, deployment, scheme.Scheme)
+	if err := patchutil.Apply(deployment, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return deployment, nil
+}
+
+func (ctrl *KarmadaController) EnsureKarmadaSearchAPIService(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+	aaClient, err := aggregator.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	svc := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      constants.KarmadaComponentSearch,
+			Namespace: constants.KarmadaSystemNamespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:         corev1.ServiceTypeExternalName,
+			ExternalName: fmt.Sprintf("%s.%s.svc", constants.KarmadaComponentSearch, karmada.Namespace),
+		},
+	}
+	if err := patchutil.Apply(svc, karmada.Spec.Patches); err != nil {
+		return err
+	}
+	if _, err = clientutil.CreateOrUpdateService(ctx, kubeClient, svc); err != nil {
+		return err
+	}
+
+	apisvc := &apiregistrationv1.APIService{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "APIService",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   karmadaSearchAPIServiceName,
+			Labels: map[string]string{"app": "karmada-search", "apiserver": "true"},
+		},
+		Spec: apiregistrationv1.APIServiceSpec{
+			InsecureSkipTLSVerify: true,
+			Group:                 "search.karmada.io",
+			GroupPriorityMinimum:  2000,
+			Service: &apiregistrationv1.ServiceReference{
+				Name:      constants.KarmadaComponentSearch,
+				Namespace: constants.KarmadaSystemNamespace,
+			},
+			Version:         "v1alpha1",
+			VersionPriority: 10,
+		},
+	}
+	if err := patchutil.Apply(apisvc, karmada.Spec.Patches); err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateAPIService(ctx, aaClient, apisvc)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, apisvc, result)
+	return err
+}
@@ -17,19 +17,23 @@ limitations under the License.
 package karmada
 
 import (
+	"bytes"
 	"context"
-	"encoding/base64"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/yaml"
@@ -38,24 +42,135 @@ import (
 	"github.com/carlory/firefly/pkg/constants"
 	"github.com/carlory/firefly/pkg/scheme"
 	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/certs"
 	clientutil "github.com/carlory/firefly/pkg/util/client"
 	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
 )
 
-func (ctrl *KarmadaController) EnsureKaramdaWebhook(karmada *installv1alpha1.Karmada) error {
-	if err := ctrl.EnsureKarmadaWebhookConfiguration(karmada); err != nil {
+const (
+	// webhookCertRenewBefore is how long before the expiration the serving certificate of the
+	// karmada-webhook is renewed.
+	webhookCertRenewBefore = 30 * 24 * time.Hour
+)
+
+// EnsureKaramdaWebhook deploys the karmada-webhook with its own serving certificate signed by
+// the karmada CA, and registers it to the karmada-apiserver with the CA injected as the caBundle.
+func (ctrl *KarmadaController) EnsureKaramdaWebhook(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	certSecret, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, "karmada-cert", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if err := ctrl.EnsureKarmadaWebhookCert(ctx, karmada, certSecret); err != nil {
 		return err
 	}
-	if err := ctrl.EnsureKaramdaWebhookService(karmada); err != nil {
+	if err := ctrl.EnsureKarmadaWebhookConfiguration(ctx, karmada, certSecret.Data["ca.crt"]); err != nil {
 		return err
 	}
-	if err := ctrl.EnsureKaramdaWebhookDeployment(karmada); err != nil {
+	if err := ctrl.EnsureKaramdaWebhookService(ctx, karmada); err != nil {
+		return err
+	}
+	if err := ctrl.EnsureKaramdaWebhookDeployment(ctx, karmada); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (ctrl *KarmadaController) EnsureKaramdaWebhookService(karmada *installv1alpha1.Karmada) error {
+// karmadaWebhookCertSecretName returns the name of the Secret holding the serving certificate of the karmada-webhook.
+func karmadaWebhookCertSecretName() string {
+	return fmt.Sprintf("%s-cert", constants.KarmadaComponentWebhook)
+}
+
+// karmadaWebhookHosts returns the hosts the karmada-webhook service is reachable at, and the custom
+// SANs of spec.apiServer.certSANs.
+func karmadaWebhookHosts(karmada *installv1alpha1.Karmada) []string {
+	hosts := []string{
+		constants.KarmadaComponentWebhook,
+		fmt.Sprintf("%s.%s", constants.KarmadaComponentWebhook, karmada.Namespace),
+		fmt.Sprintf("%s.%s.svc", constants.KarmadaComponentWebhook, karmada.Namespace),
+		fmt.Sprintf("%s.%s.svc.%s", constants.KarmadaComponentWebhook, karmada.Namespace, karmada.Spec.Networking.DNSDomain),
+	}
+	return append(hosts, apiServerCertSANs(karmada)...)
+}
+
+// EnsureKarmadaWebhookCert issues the serving certificate of the karmada-webhook with the karmada CA.
+// The certificate is reused until it's going to expire, the CA is rotated or its SANs aren't the
+// hosts of the karmada-webhook service and the custom SANs. The karmada-webhook reloads it from the mounted Secret.
+func (ctrl *KarmadaController) EnsureKarmadaWebhookCert(ctx context.Context, karmada *installv1alpha1.Karmada, certSecret *corev1.Secret) error {
+	caCert, caKey, err := parseCA(certSecret)
+	if err != nil {
+		return err
+	}
+
+	secretName := karmadaWebhookCertSecretName()
+	hosts := karmadaWebhookHosts(karmada)
+	if ctrl.reusableWebhookCert(ctx, karmada.Namespace, secretName, certSecret.Data["ca.crt"], caCert, hosts) {
+		return nil
+	}
+
+	notAfter := time.Now().Add(certs.Duration365d).UTC()
+	certCfg := certs.NewCertConfig(constants.KarmadaComponentWebhook, []string{}, altNamesForHosts(hosts), &notAfter)
+	cert, key, err := certs.NewCertAndKey(caCert, caKey, certCfg)
+	if err != nil {
+		return err
+	}
+	keyData, err := keyutil.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		return err
+	}
+
+	secret := SecretFromSpec(karmada.Namespace, secretName, corev1.SecretTypeOpaque, map[string]string{
+		"tls.crt": string(certs.EncodeCertPEM(cert)),
+		"tls.key": string(keyData),
+		"ca.crt":  string(certSecret.Data["ca.crt"]),
+	})
+	util.SetKarmadaInstanceLabel(secret, karmada.Name)
+	controllerutil.SetOwnerReference(karmada, secret, scheme.Scheme)
+	result, err := clientutil.CreateOrUpdateSecret(ctx, ctrl.client, secret)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, secret, result)
+	if err != nil {
+		return err
+	}
+	ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "CertificateRotated", "Issued a new serving certificate for %s", constants.KarmadaComponentWebhook)
+	return nil
+}
+
+// reusableWebhookCert returns whether the serving certificate of the karmada-webhook is still valid,
+// is signed for exactly the hosts, and is issued by the current CA.
+func (ctrl *KarmadaController) reusableWebhookCert(ctx context.Context, namespace, secretName string, caData []byte, caCert *x509.Certificate, hosts []string) bool {
+	secret, err := ctrl.client.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return false
+	}
+	if !bytes.Equal(secret.Data["ca.crt"], caData) {
+		return false
+	}
+	servingCerts, err := certutil.ParseCertsPEM(secret.Data["tls.crt"])
+	if err != nil {
+		return false
+	}
+	if time.Now().Add(webhookCertRenewBefore).After(servingCerts[0].NotAfter) {
+		return false
+	}
+	if err := servingCerts[0].CheckSignatureFrom(caCert); err != nil {
+		return false
+	}
+	// the certificate is issued again once a custom SAN is removed.
+	return certSANs(servingCerts[0]).Equal(sets.NewString(normalizedCertSANs(hosts)...))
+}
+
+func (ctrl *KarmadaController) EnsureKaramdaWebhookService(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	svc, err := karmadaWebhookService(karmada)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateService(ctx, ctrl.client, svc)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, svc, result)
+	return err
+}
+
+// karmadaWebhookService returns the karmada-webhook service of the karmada.
+func karmadaWebhookService(karmada *installv1alpha1.Karmada) (*corev1.Service, error) {
 	componentName := constants.KarmadaComponentWebhook
 	svc := &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -81,11 +196,24 @@ func (ctrl *KarmadaController) EnsureKaramdaWebhookService(karmada *installv1alp
 			},
 		},
 	}
+	util.SetKarmadaInstanceLabel(svc, karmada.Name)
 	controllerutil.SetOwnerReference(karmada, svc, scheme.Scheme)
-	return clientutil.CreateOrUpdateService(ctrl.client, svc)
+	if err := patchutil.Apply(svc, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+func (ctrl *KarmadaController) EnsureKaramdaWebhookDeployment(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	deployment, err := karmadaWebhookDeployment(karmada)
+	if err != nil {
+		return err
+	}
+	return ctrl.ensureDeployment(ctx, karmada, deployment, nil)
 }
 
-func (ctrl *KarmadaController) EnsureKaramdaWebhookDeployment(karmada *installv1alpha1.Karmada) error {
+// karmadaWebhookDeployment returns the karmada-webhook deployment of the karmada.
+func karmadaWebhookDeployment(karmada *installv1alpha1.Karmada) (*appsv1.Deployment, error) {
 	componentName := constants.KarmadaComponentWebhook
 	webhook := karmada.Spec.Webhook.KarmadaWebhook
 
@@ -111,7 +239,7 @@ func (ctrl *KarmadaController) EnsureKaramdaWebhookDeployment(karmada *installv1
 		"cert-dir":     "/var/serving-cert",
 		"v":            "4",
 	}
-	computedArgs := maputil.MergeStringMaps(defaultArgs, webhook.ExtraArgs)
+	computedArgs := maputil.MergeStringMaps(defaultArgs, util.LoggingArgs(webhook.Logging), webhook.ExtraArgs)
 	args := maputil.ConvertToCommandOrArgs(computedArgs)
 
 	deployment := &appsv1.Deployment{
@@ -173,7 +301,7 @@ func (ctrl *KarmadaController) EnsureKaramdaWebhookDeployment(karmada *installv1
 							Name: "cert",
 							VolumeSource: corev1.VolumeSource{
 								Secret: &corev1.SecretVolumeSource{
-									SecretName: fmt.Sprintf("%s-cert", componentName),
+									SecretName: karmadaWebhookCertSecretName(),
 								},
 							},
 						},
@@ -182,12 +310,20 @@ func (ctrl *KarmadaController) EnsureKaramdaWebhookDeployment(karmada *installv1
 			},
 		},
 	}
+	util.ApplyComponentExtras(&deployment.Spec.Template.Spec, webhook.ComponentExtras)
+	util.SetKarmadaInstanceLabel(deployment, karmada.Name)
 	controllerutil.SetOwnerReference(karmada, deployment, scheme.Scheme)
-	return clientutil.CreateOrUpdateDeployment(ctrl.client, deployment)
+	if err := patchutil.Apply(deployment, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return deployment, nil
 }
 
-func (ctrl *KarmadaController) EnsureKarmadaWebhookConfiguration(karmada *installv1alpha1.Karmada) error {
-	clientConfig, err := ctrl.GenerateClientConfig(karmada)
+// EnsureKarmadaWebhookConfiguration registers the karmada-webhook to the karmada-apiserver. The caBundle
+// of the webhooks is refreshed with the given CA, so that the webhooks keep trusting the serving
+// certificate once the CA is rotated.
+func (ctrl *KarmadaController) EnsureKarmadaWebhookConfiguration(ctx context.Context, karmada *installv1alpha1.Karmada, caBundle []byte) error {
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
 	if err != nil {
 		return err
 	}
@@ -196,23 +332,17 @@ func (ctrl *KarmadaController) EnsureKarmadaWebhookConfiguration(karmada *instal
 		return err
 	}
 
-	karmadaCert, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(context.TODO(), "karmada-cert", metav1.GetOptions{})
-	if err != nil {
-		return err
-	}
-	caCrt := karmadaCert.Data["ca.crt"]
-	caBunlde := base64.StdEncoding.EncodeToString(caCrt)
-	if err := createValidatingWebhookConfiguration(client, validatingConfig(caBunlde, karmada)); err != nil {
+	if err := createValidatingWebhookConfiguration(ctx, client, validatingConfig(karmada), caBundle); err != nil {
 		return err
 	}
 
-	if err := createMutatingWebhookConfiguration(client, mutatingConfig(caBunlde, karmada)); err != nil {
+	if err := createMutatingWebhookConfiguration(ctx, client, mutatingConfig(karmada), caBundle); err != nil {
 		return err
 	}
 	return nil
 }
 
-func mutatingConfig(caBundle string, karmada *installv1alpha1.Karmada) string {
+func mutatingConfig(karmada *installv1alpha1.Karmada) string {
 	return fmt.Sprintf(`apiVersion: admissionregistration.k8s.io/v1
 kind: MutatingWebhookConfiguration
 metadata:
@@ -228,8 +358,7 @@ webhooks:
         resources: ["propagationpolicies"]
         scope: "Namespaced"
     clientConfig:
-      url: https://%[3]s.%[1]s.svc:443/mutate-propagationpolicy
-      caBundle: %[2]s
+      url: https://%[2]s.%[1]s.svc:443/mutate-propagationpolicy
     failurePolicy: Fail
     sideEffects: None
     admissionReviewVersions: ["v1"]
@@ -242,8 +371,7 @@ webhooks:
         resources: ["clusterpropagationpolicies"]
         scope: "Cluster"
     clientConfig:
-      url: https://%[3]s.%[1]s.svc:443/mutate-clusterpropagationpolicy
-      caBundle: %[2]s
+      url: https://%[2]s.%[1]s.svc:443/mutate-clusterpropagationpolicy
     failurePolicy: Fail
     sideEffects: None
     admissionReviewVersions: ["v1"]
@@ -256,8 +384,7 @@ webhooks:
         resources: ["overridepolicies"]
         scope: "Namespaced"
     clientConfig:
-      url: https://%[3]s.%[1]s.svc:443/mutate-overridepolicy
-      caBundle: %[2]s
+      url: https://%[2]s.%[1]s.svc:443/mutate-overridepolicy
     failurePolicy: Fail
     sideEffects: None
     admissionReviewVersions: ["v1"]
@@ -270,15 +397,14 @@ webhooks:
         resources: ["works"]
         scope: "Namespaced"
     clientConfig:
-      url: https://%[3]s.%[1]s.svc:443/mutate-work
-      caBundle: %[2]s
+      url: https://%[2]s.%[1]s.svc:443/mutate-work
     failurePolicy: Fail
     sideEffects: None
     admissionReviewVersions: ["v1"]
-    timeoutSeconds: 3`, karmada.Namespace, caBundle, constants.KarmadaComponentWebhook)
+    timeoutSeconds: 3`, karmada.Namespace, constants.KarmadaComponentWebhook)
 }
 
-func validatingConfig(caBundle string, karmada *installv1alpha1.Karmada) string {
+func validatingConfig(karmada *installv1alpha1.Karmada) string {
 	return fmt.Sprintf(`apiVersion: admissionregistration.k8s.io/v1
 kind: ValidatingWebhookConfiguration
 metadata:
@@ -294,8 +420,7 @@ webhooks:
         resources: ["propagationpolicies"]
         scope: "Namespaced"
     clientConfig:
-      url: https://%[3]s.%[1]s.svc:443/validate-propagationpolicy
-      caBundle: %[2]s
+      url: https://%[2]s.%[1]s.svc:443/validate-propagationpolicy
     failurePolicy: Fail
     sideEffects: None
     admissionReviewVersions: ["v1"]
@@ -308,8 +433,7 @@ webhooks:
         resources: ["clusterpropagationpolicies"]
         scope: "Cluster"
     clientConfig:
-      url: https://%[3]s.%[1]s.svc:443/validate-clusterpropagationpolicy
-      caBundle: %[2]s
+      url: https://%[2]s.%[1]s.svc:443/validate-clusterpropagationpolicy
     failurePolicy: Fail
     sideEffects: None
     admissionReviewVersions: ["v1"]
@@ -322,8 +446,7 @@ webhooks:
         resources: ["overridepolicies"]
         scope: "Namespaced"
     clientConfig:
-      url: https://%[3]s.%[1]s.svc:443/validate-overridepolicy
-      caBundle: %[2]s
+      url: https://%[2]s.%[1]s.svc:443/validate-overridepolicy
     failurePolicy: Fail
     sideEffects: None
     admissionReviewVersions: ["v1"]
@@ -336,8 +459,7 @@ webhooks:
         resources: ["clusteroverridepolicies"]
         scope: "Cluster"
     clientConfig:
-      url: https://%[3]s.%[1]s.svc:443/validate-clusteroverridepolicy
-      caBundle: %[2]s
+      url: https://%[2]s.%[1]s.svc:443/validate-clusteroverridepolicy
     failurePolicy: Fail
     sideEffects: None
     admissionReviewVersions: ["v1"]
@@ -350,68 +472,41 @@ webhooks:
         resources: ["resourceexploringwebhookconfigurations"]
         scope: "Cluster"
     clientConfig:
-      url: https://%[3]s.%[1]s.svc:443/validate-resourceexploringwebhookconfiguration
-      caBundle: %[2]s
+      url: https://%[2]s.%[1]s.svc:443/validate-resourceexploringwebhookconfiguration
     failurePolicy: Fail
     sideEffects: None
     admissionReviewVersions: ["v1"]
-    timeoutSeconds: 3`, karmada.Namespace, caBundle, constants.KarmadaComponentWebhook)
+    timeoutSeconds: 3`, karmada.Namespace, constants.KarmadaComponentWebhook)
 }
 
-func createValidatingWebhookConfiguration(c kubernetes.Interface, staticYaml string) error {
+func createValidatingWebhookConfiguration(ctx context.Context, c kubernetes.Interface, staticYaml string, caBundle []byte) error {
 	obj := admissionregistrationv1.ValidatingWebhookConfiguration{}
 
 	if err := json.Unmarshal(StaticYamlToJSONByte(staticYaml), &obj); err != nil {
 		klog.Errorln("Error convert json byte to admissionregistration v1 ValidatingWebhookConfiguration struct.")
 		return err
 	}
-
-	_, err := c.AdmissionregistrationV1().ValidatingWebhookConfigurations().Create(context.TODO(), &obj, metav1.CreateOptions{})
-	if err != nil {
-		if errors.IsAlreadyExists(err) {
-			latest, err := c.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.TODO(), obj.Name, metav1.GetOptions{})
-			if err != nil {
-				klog.Errorln("Error get validating webhook configuration.")
-				return err
-			}
-			obj.ResourceVersion = latest.ResourceVersion
-			_, err = c.AdmissionregistrationV1().ValidatingWebhookConfigurations().Update(context.TODO(), &obj, metav1.UpdateOptions{})
-			if err != nil {
-				klog.Errorln("Error update validating webhook configuration.")
-				return err
-			}
-			return nil
-		}
-		return err
+	for i := range obj.Webhooks {
+		obj.Webhooks[i].ClientConfig.CABundle = caBundle
 	}
-	return nil
+
+	_, err := clientutil.CreateOrUpdateValidatingWebhookConfiguration(ctx, c, &obj)
+	return err
 }
 
-func createMutatingWebhookConfiguration(c kubernetes.Interface, staticYaml string) error {
+func createMutatingWebhookConfiguration(ctx context.Context, c kubernetes.Interface, staticYaml string, caBundle []byte) error {
 	obj := admissionregistrationv1.MutatingWebhookConfiguration{}
 
 	if err := json.Unmarshal(StaticYamlToJSONByte(staticYaml), &obj); err != nil {
 		klog.Errorln("Error convert json byte to admissionregistration v1 MutatingWebhookConfiguration struct.")
 		return err
 	}
-
-	_, err := c.AdmissionregistrationV1().MutatingWebhookConfigurations().Create(context.TODO(), &obj, metav1.CreateOptions{})
-	if err != nil {
-		if errors.IsAlreadyExists(err) {
-			latest, err := c.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), obj.Name, metav1.GetOptions{})
-			if err != nil {
-				return err
-			}
-			obj.ResourceVersion = latest.ResourceVersion
-			_, err = c.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(context.TODO(), &obj, metav1.UpdateOptions{})
-			if err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
+	for i := range obj.Webhooks {
+		obj.Webhooks[i].ClientConfig.CABundle = caBundle
 	}
-	return nil
+
+	_, err := clientutil.CreateOrUpdateMutatingWebhookConfiguration(ctx, c, &obj)
+	return err
 }
 
 // StaticYamlToJSONByte  Static yaml file conversion JSON Byte
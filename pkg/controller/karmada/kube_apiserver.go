@@ -17,13 +17,17 @@ limitations under the License.
 package karmada
 
 import (
+	"context"
 	"fmt"
+	"path"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -33,18 +37,29 @@ import (
 	"github.com/carlory/firefly/pkg/util"
 	clientutil "github.com/carlory/firefly/pkg/util/client"
 	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
 )
 
+// oidcCADir is where the certificate authority of the OpenID Connect provider is mounted into the kube-apiserver.
+const oidcCADir = "/etc/kubernetes/oidc"
+
 // EnsureKubeAPIServer ensures the kube-apiserver components exists and returns a kubeclient if it's ready.
-func (ctrl *KarmadaController) EnsureKubeAPIServer(karmada *installv1alpha1.Karmada) (kubernetes.Interface, error) {
-	if err := ctrl.EnsureKubeAPIServerService(karmada); err != nil {
+func (ctrl *KarmadaController) EnsureKubeAPIServer(ctx context.Context, karmada *installv1alpha1.Karmada) (kubernetes.Interface, error) {
+	if err := ctrl.EnsureKubeAPIServerService(ctx, karmada); err != nil {
+		return nil, err
+	}
+	if err := ctrl.EnsureKubeAPIServerAuditPolicy(ctx, karmada); err != nil {
+		return nil, err
+	}
+	encryptionConfig, err := ctrl.EnsureKubeAPIServerEncryptionConfig(ctx, karmada)
+	if err != nil {
 		return nil, err
 	}
-	if err := ctrl.EnsureKubeAPIServerDeployment(karmada); err != nil {
+	if err := ctrl.EnsureKubeAPIServerDeployment(ctx, karmada, encryptionConfig); err != nil {
 		return nil, err
 	}
 
-	clientConfig, err := ctrl.GenerateClientConfig(karmada)
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
 	if err != nil {
 		return nil, err
 	}
@@ -56,12 +71,30 @@ func (ctrl *KarmadaController) EnsureKubeAPIServer(karmada *installv1alpha1.Karm
 	if err := util.NewKubeWaiter(client, 10*time.Second).WaitForKubeAPI(); err != nil {
 		return nil, err
 	}
+	if err := ctrl.EnsureEncryptionKeyRotation(ctx, karmada); err != nil {
+		return nil, err
+	}
 	return client, nil
 }
 
 // EnsureKubeAPIServerService ensures the kube-apiserver service exists.
-func (ctrl *KarmadaController) EnsureKubeAPIServerService(karmada *installv1alpha1.Karmada) error {
+func (ctrl *KarmadaController) EnsureKubeAPIServerService(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	svc, err := kubeAPIServerService(karmada)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateService(ctx, ctrl.client, svc)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, svc, result)
+	return err
+}
+
+// kubeAPIServerService returns the kube-apiserver service of the karmada.
+func kubeAPIServerService(karmada *installv1alpha1.Karmada) (*corev1.Service, error) {
 	componentName := constants.KarmadaComponentKubeAPIServer
+	serviceType := karmada.Spec.APIServer.ServiceType
+	if serviceType == "" {
+		serviceType = corev1.ServiceTypeClusterIP
+	}
 	svc := &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
@@ -72,7 +105,7 @@ func (ctrl *KarmadaController) EnsureKubeAPIServerService(karmada *installv1alph
 			Namespace: karmada.Namespace,
 		},
 		Spec: corev1.ServiceSpec{
-			Type:     corev1.ServiceTypeClusterIP,
+			Type:     serviceType,
 			Selector: map[string]string{"app": componentName},
 			Ports: []corev1.ServicePort{
 				{
@@ -87,12 +120,27 @@ func (ctrl *KarmadaController) EnsureKubeAPIServerService(karmada *installv1alph
 			},
 		},
 	}
+	util.SetKarmadaInstanceLabel(svc, karmada.Name)
 	controllerutil.SetOwnerReference(karmada, svc, scheme.Scheme)
-	return clientutil.CreateOrUpdateService(ctrl.client, svc)
+	if err := patchutil.Apply(svc, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return svc, nil
 }
 
-// EnsureKubeAPIServerDeployment ensures the kube-apiserver deployment exists.
-func (ctrl *KarmadaController) EnsureKubeAPIServerDeployment(karmada *installv1alpha1.Karmada) error {
+// EnsureKubeAPIServerDeployment ensures the kube-apiserver deployment exists. The encryptionConfig is
+// the EncryptionConfiguration of the kube-apiserver, if the resources are encrypted at rest.
+func (ctrl *KarmadaController) EnsureKubeAPIServerDeployment(ctx context.Context, karmada *installv1alpha1.Karmada, encryptionConfig string) error {
+	deployment, err := kubeAPIServerDeployment(karmada)
+	if err != nil {
+		return err
+	}
+	mountEncryptionConfig(deployment, encryptionConfig)
+	return ctrl.ensureDeployment(ctx, karmada, deployment, karmada.Spec.APIServer.KubeAPIServer.Autoscaling)
+}
+
+// kubeAPIServerDeployment returns the kube-apiserver deployment of the karmada.
+func kubeAPIServerDeployment(karmada *installv1alpha1.Karmada) (*appsv1.Deployment, error) {
 	componentName := constants.KarmadaComponentKubeAPIServer
 	server := karmada.Spec.APIServer.KubeAPIServer
 
@@ -132,7 +180,7 @@ func (ctrl *KarmadaController) EnsureKubeAPIServerDeployment(karmada *installv1a
 		"disable-admission-plugins":          "StorageObjectInUseProtection,ServiceAccount",
 		"runtime-config":                     "",
 		"secure-port":                        "5443",
-		"service-account-issuer":             fmt.Sprintf("https://kubernetes.default.svc.%s", karmada.Spec.Networking.DNSDomain),
+		"service-account-issuer":             serviceAccountIssuer(karmada),
 		"service-account-key-file":           "/etc/kubernetes/pki/karmada.key",
 		"service-account-signing-key-file":   "/etc/kubernetes/pki/karmada.key",
 		"service-cluster-ip-range":           karmada.Spec.Networking.ServiceSubnet,
@@ -153,8 +201,15 @@ func (ctrl *KarmadaController) EnsureKubeAPIServerDeployment(karmada *installv1a
 			defaultArgs["feature-gates"] = fmt.Sprintf("%s,%s=%t", defaultArgs["feature-gates"], feature, enabled)
 		}
 	}
-	computedArgs := maputil.MergeStringMaps(defaultArgs, server.ExtraArgs)
-	args := maputil.ConvertToCommandOrArgs(computedArgs)
+	if audiences := karmada.Spec.APIServer.APIAudiences; len(audiences) > 0 {
+		defaultArgs["api-audiences"] = strings.Join(audiences, ",")
+	}
+	setOIDCArgs(karmada, defaultArgs)
+	if err := setAuditArgs(karmada, defaultArgs); err != nil {
+		return nil, err
+	}
+	computedArgs := maputil.MergeStringMaps(defaultArgs, util.LoggingArgs(server.Logging), server.ExtraArgs)
+	args := append(maputil.ConvertToCommandOrArgs(computedArgs), oidcRequiredClaimArgs(karmada)...)
 
 	deployment := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
@@ -239,6 +294,85 @@ func (ctrl *KarmadaController) EnsureKubeAPIServerDeployment(karmada *installv1a
 			},
 		},
 	}
+	mountOIDCCA(karmada, &deployment.Spec.Template.Spec)
+	mountAudit(karmada, &deployment.Spec.Template)
+	util.ApplyComponentExtras(&deployment.Spec.Template.Spec, server.ComponentExtras)
+	util.SetKarmadaInstanceLabel(deployment, karmada.Name)
 	controllerutil.SetOwnerReference(karmada, deployment, scheme.Scheme)
-	return clientutil.CreateOrUpdateDeployment(ctrl.client, deployment)
+	if err := patchutil.Apply(deployment, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return deployment, nil
+}
+
+// serviceAccountIssuer returns the issuer of the service account tokens issued by the karmada-apiserver.
+func serviceAccountIssuer(karmada *installv1alpha1.Karmada) string {
+	if issuer := karmada.Spec.APIServer.ServiceAccountIssuer; issuer != "" {
+		return issuer
+	}
+	return fmt.Sprintf("https://kubernetes.default.svc.%s", karmada.Spec.Networking.DNSDomain)
+}
+
+// setOIDCArgs sets the flags of the kube-apiserver which authenticate the users by OpenID Connect,
+// if it's configured by the karmada.
+func setOIDCArgs(karmada *installv1alpha1.Karmada, args map[string]string) {
+	oidc := karmada.Spec.APIServer.OIDC
+	if oidc == nil {
+		return
+	}
+	args["oidc-issuer-url"] = oidc.IssuerURL
+	args["oidc-client-id"] = oidc.ClientID
+	if oidc.CASecretRef != nil {
+		args["oidc-ca-file"] = path.Join(oidcCADir, "ca.crt")
+	}
+	optionalArgs := map[string]string{
+		"oidc-username-claim":  oidc.UsernameClaim,
+		"oidc-username-prefix": oidc.UsernamePrefix,
+		"oidc-groups-claim":    oidc.GroupsClaim,
+		"oidc-groups-prefix":   oidc.GroupsPrefix,
+		"oidc-signing-algs":    strings.Join(oidc.SigningAlgs, ","),
+	}
+	for flag, value := range optionalArgs {
+		if value != "" {
+			args[flag] = value
+		}
+	}
+}
+
+// oidcRequiredClaimArgs returns the --oidc-required-claim flags of the kube-apiserver, which are
+// repeated for each required claim, so that they can't be set in the map of args.
+func oidcRequiredClaimArgs(karmada *installv1alpha1.Karmada) []string {
+	oidc := karmada.Spec.APIServer.OIDC
+	if oidc == nil {
+		return nil
+	}
+	var args []string
+	for _, claim := range sets.StringKeySet(oidc.RequiredClaims).List() {
+		args = append(args, fmt.Sprintf("--oidc-required-claim=%s=%s", claim, oidc.RequiredClaims[claim]))
+	}
+	return args
+}
+
+// mountOIDCCA mounts the Secret holding the certificate authority of the OpenID Connect provider
+// into the pod of the kube-apiserver, if it's set by the karmada.
+func mountOIDCCA(karmada *installv1alpha1.Karmada, podSpec *corev1.PodSpec) {
+	oidc := karmada.Spec.APIServer.OIDC
+	if oidc == nil || oidc.CASecretRef == nil {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "oidc-ca",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: oidc.CASecretRef.Name,
+				Items:      []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+			},
+		},
+	})
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "oidc-ca",
+		MountPath: oidcCADir,
+		ReadOnly:  true,
+	})
 }
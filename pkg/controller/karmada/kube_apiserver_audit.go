@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+const (
+	// kubeAPIServerAuditPolicyName is the name of the configmap holding the audit policy set inline by the karmada.
+	kubeAPIServerAuditPolicyName = "karmada-apiserver-audit-policy"
+	auditPolicyDir               = "/etc/kubernetes/audit"
+	auditPolicyKey               = "policy.yaml"
+	auditLogDir                  = "/var/log/karmada-apiserver"
+	auditWebhookDir              = "/etc/kubernetes/audit-webhook"
+
+	defaultAuditLogSidecarImage = "docker.io/library/busybox:1.35"
+)
+
+// EnsureKubeAPIServerAuditPolicy creates or updates the configmap holding the audit policy of the
+// karmada-apiserver if it's set inline by the karmada, otherwise deletes it.
+func (ctrl *KarmadaController) EnsureKubeAPIServerAuditPolicy(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if auditSpec := karmada.Spec.APIServer.Audit; auditSpec == nil || auditSpec.Policy == "" {
+		err := ctrl.client.CoreV1().ConfigMaps(karmada.Namespace).Delete(ctx, kubeAPIServerAuditPolicyName, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "ConfigMap", Namespace: karmada.Namespace, Name: kubeAPIServerAuditPolicyName}, err)
+		return client.IgnoreNotFound(err)
+	}
+
+	cm, err := kubeAPIServerAuditPolicyConfigMap(karmada)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateConfigMap(ctx, ctrl.client, cm)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, cm, result)
+	return err
+}
+
+// kubeAPIServerAuditPolicyConfigMap returns the configmap holding the audit policy set inline by the karmada.
+func kubeAPIServerAuditPolicyConfigMap(karmada *installv1alpha1.Karmada) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kubeAPIServerAuditPolicyName,
+			Namespace: karmada.Namespace,
+		},
+		Data: map[string]string{
+			auditPolicyKey: karmada.Spec.APIServer.Audit.Policy,
+		},
+	}
+	util.SetKarmadaInstanceLabel(cm, karmada.Name)
+
+	controllerutil.SetOwnerReference(karmada, cm, scheme.Scheme)
+	if err := patchutil.Apply(cm, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
+
+// setAuditArgs sets the flags of the kube-apiserver which audit the requests, if the audit is
+// configured by the karmada.
+func setAuditArgs(karmada *installv1alpha1.Karmada, args map[string]string) error {
+	auditSpec := karmada.Spec.APIServer.Audit
+	if auditSpec == nil {
+		return nil
+	}
+	if (auditSpec.Policy == "") == (auditSpec.PolicySecretRef == nil) {
+		return fmt.Errorf("exactly one of policy and policySecretRef must be set for the audit of the karmada-apiserver")
+	}
+	if auditSpec.Log == nil && auditSpec.Webhook == nil {
+		return fmt.Errorf("no backend is set for the audit of the karmada-apiserver")
+	}
+
+	args["audit-policy-file"] = path.Join(auditPolicyDir, auditPolicyKey)
+	if log := auditSpec.Log; log != nil {
+		args["audit-log-path"] = path.Join(auditLogDir, "audit.log")
+		if log.MaxAge != nil {
+			args["audit-log-maxage"] = strconv.Itoa(int(*log.MaxAge))
+		}
+		if log.MaxBackups != nil {
+			args["audit-log-maxbackup"] = strconv.Itoa(int(*log.MaxBackups))
+		}
+		if log.MaxSize != nil {
+			args["audit-log-maxsize"] = strconv.Itoa(int(*log.MaxSize))
+		}
+	}
+	if webhook := auditSpec.Webhook; webhook != nil {
+		args["audit-webhook-config-file"] = path.Join(auditWebhookDir, "kubeconfig")
+		if webhook.Mode != "" {
+			args["audit-webhook-mode"] = webhook.Mode
+		}
+	}
+	return nil
+}
+
+// mountAudit mounts the audit policy and the credentials of the webhook backend into the pod of the
+// kube-apiserver, and adds the sidecar container streaming the audit log, if the audit is configured
+// by the karmada.
+func mountAudit(karmada *installv1alpha1.Karmada, template *corev1.PodTemplateSpec) {
+	auditSpec := karmada.Spec.APIServer.Audit
+	if auditSpec == nil {
+		return
+	}
+	podSpec := &template.Spec
+	container := &podSpec.Containers[0]
+
+	policyVolume := corev1.Volume{Name: "audit-policy"}
+	if auditSpec.PolicySecretRef != nil {
+		policyVolume.Secret = &corev1.SecretVolumeSource{
+			SecretName: auditSpec.PolicySecretRef.Name,
+			Items:      []corev1.KeyToPath{{Key: auditPolicyKey, Path: auditPolicyKey}},
+		}
+	} else {
+		policyVolume.ConfigMap = &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: kubeAPIServerAuditPolicyName},
+		}
+		if template.Annotations == nil {
+			template.Annotations = map[string]string{}
+		}
+		template.Annotations[constants.AuditPolicyHashAnnotation] = contentHash(auditSpec.Policy)
+	}
+	podSpec.Volumes = append(podSpec.Volumes, policyVolume)
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "audit-policy",
+		MountPath: auditPolicyDir,
+		ReadOnly:  true,
+	})
+
+	if webhook := auditSpec.Webhook; webhook != nil {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "audit-webhook",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: webhook.KubeconfigSecretRef.Name,
+					Items:      []corev1.KeyToPath{{Key: "kubeconfig", Path: "kubeconfig"}},
+				},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "audit-webhook",
+			MountPath: auditWebhookDir,
+			ReadOnly:  true,
+		})
+	}
+
+	if log := auditSpec.Log; log != nil {
+		image := defaultAuditLogSidecarImage
+		if log.SidecarImage != "" {
+			image = log.SidecarImage
+		}
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name:         "audit-log",
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "audit-log",
+			MountPath: auditLogDir,
+		})
+		// the log file is followed by name, so that the sidecar keeps streaming it after it's rotated.
+		podSpec.Containers = append(podSpec.Containers, corev1.Container{
+			Name:            "audit-log",
+			Image:           image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{"tail", "-n", "+1", "-F", path.Join(auditLogDir, "audit.log")},
+			VolumeMounts:    []corev1.VolumeMount{{Name: "audit-log", MountPath: auditLogDir, ReadOnly: true}},
+		})
+	}
+}
@@ -0,0 +1,561 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+const (
+	// encryptionConfigSecretName is the name of the Secret holding the EncryptionConfiguration of the
+	// karmada-apiserver and the keys of the encryption at rest.
+	encryptionConfigSecretName = "karmada-encryption-config"
+	encryptionConfigDir        = "/etc/kubernetes/encryption"
+	encryptionConfigKey        = "encryption-config.yaml"
+	// encryptionKeysKey is the key of the Secret which holds the active keys, the first of which
+	// encrypts the written resources.
+	encryptionKeysKey = "keys"
+	// encryptionStagedKeyKey is the key of the Secret which holds the key being rolled out by a rotation.
+	// It only decrypts the resources until it's rolled out to all the karmada-apiservers.
+	encryptionStagedKeyKey = "staged-key"
+	// encryptionResourcesKey is the key of the Secret which holds the encrypted resources, so that they
+	// can still be decrypted once the encryption is unset.
+	encryptionResourcesKey = "resources"
+
+	// encryptionKeyLength is the length of the generated keys, which is accepted by both aescbc and secretbox.
+	encryptionKeyLength = 32
+
+	// reencryptJobName is the name of the job which rewrites the encrypted resources with the rotated key.
+	reencryptJobName = "karmada-reencrypt-resources"
+
+	// encryptionRotationCheckInterval is the interval at which a karmada is requeued while the key of its
+	// encryption at rest is being rotated, since the rollouts and the jobs are not watched.
+	encryptionRotationCheckInterval = 10 * time.Second
+)
+
+// encryptionKey is a key of the aescbc and secretbox providers.
+type encryptionKey struct {
+	Name   string `json:"name"`
+	Secret string `json:"secret"`
+}
+
+// encryptionConfiguration is the EncryptionConfiguration of the karmada-apiserver.
+type encryptionConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	Resources []encryptionResourceConfiguration `json:"resources"`
+}
+
+type encryptionResourceConfiguration struct {
+	Resources []string                   `json:"resources"`
+	Providers []encryptionProviderConfig `json:"providers"`
+}
+
+type encryptionProviderConfig struct {
+	AESCBC    *encryptionKeysConfig `json:"aescbc,omitempty"`
+	Secretbox *encryptionKeysConfig `json:"secretbox,omitempty"`
+	KMS       *encryptionKMSConfig  `json:"kms,omitempty"`
+	Identity  *struct{}             `json:"identity,omitempty"`
+}
+
+type encryptionKeysConfig struct {
+	Keys []encryptionKey `json:"keys"`
+}
+
+type encryptionKMSConfig struct {
+	Name      string           `json:"name"`
+	Endpoint  string           `json:"endpoint"`
+	CacheSize *int32           `json:"cachesize,omitempty"`
+	Timeout   *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// generateEncryptionKey returns a random key named after the current time.
+func generateEncryptionKey() (encryptionKey, error) {
+	secret := make([]byte, encryptionKeyLength)
+	if _, err := rand.Read(secret); err != nil {
+		return encryptionKey{}, err
+	}
+	return encryptionKey{
+		Name:   fmt.Sprintf("key%d", time.Now().Unix()),
+		Secret: base64.StdEncoding.EncodeToString(secret),
+	}, nil
+}
+
+// encryptionKeys returns the active keys and the staged key held by the Secret.
+func encryptionKeys(secret *corev1.Secret) ([]encryptionKey, *encryptionKey, error) {
+	var keys []encryptionKey
+	if err := json.Unmarshal(secret.Data[encryptionKeysKey], &keys); err != nil {
+		return nil, nil, fmt.Errorf("invalid keys of secret %s: %v", secret.Name, err)
+	}
+	data, ok := secret.Data[encryptionStagedKeyKey]
+	if !ok {
+		return keys, nil, nil
+	}
+	staged := &encryptionKey{}
+	if err := json.Unmarshal(data, staged); err != nil {
+		return nil, nil, fmt.Errorf("invalid staged key of secret %s: %v", secret.Name, err)
+	}
+	return keys, staged, nil
+}
+
+// setEncryptionKeys sets the keys into the Secret, along with the EncryptionConfiguration using them.
+func setEncryptionKeys(karmada *installv1alpha1.Karmada, secret *corev1.Secret, keys []encryptionKey, staged *encryptionKey) error {
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	secret.Data[encryptionKeysKey] = data
+	delete(secret.Data, encryptionStagedKeyKey)
+	if staged != nil {
+		data, err := json.Marshal(staged)
+		if err != nil {
+			return err
+		}
+		secret.Data[encryptionStagedKeyKey] = data
+	}
+
+	if spec := karmada.Spec.APIServer.Encryption; spec != nil {
+		resources := spec.Resources
+		if len(resources) == 0 {
+			resources = []string{"secrets"}
+		}
+		secret.Data[encryptionResourcesKey] = []byte(strings.Join(resources, ","))
+	}
+	config, err := renderEncryptionConfig(karmada, strings.Split(string(secret.Data[encryptionResourcesKey]), ","), keys, staged)
+	if err != nil {
+		return err
+	}
+	secret.Data[encryptionConfigKey] = []byte(config)
+	return nil
+}
+
+// renderEncryptionConfig renders the EncryptionConfiguration of the karmada-apiserver. The providers
+// which aren't used for encryption follow the one which is, so that the resources written by them
+// can still be decrypted.
+func renderEncryptionConfig(karmada *installv1alpha1.Karmada, resources []string, keys []encryptionKey, staged *encryptionKey) (string, error) {
+	readKeys := keys
+	if staged != nil {
+		readKeys = append(append([]encryptionKey{}, keys...), *staged)
+	}
+	aescbc := encryptionProviderConfig{AESCBC: &encryptionKeysConfig{Keys: readKeys}}
+	secretbox := encryptionProviderConfig{Secretbox: &encryptionKeysConfig{Keys: readKeys}}
+	identity := encryptionProviderConfig{Identity: &struct{}{}}
+
+	var providers []encryptionProviderConfig
+	spec := karmada.Spec.APIServer.Encryption
+	switch {
+	case spec == nil:
+		providers = []encryptionProviderConfig{identity, aescbc, secretbox}
+	case spec.Provider == installv1alpha1.EncryptionProviderKMS:
+		if spec.KMS == nil {
+			return "", fmt.Errorf("kms is required by the kms encryption provider")
+		}
+		kms := encryptionProviderConfig{KMS: &encryptionKMSConfig{
+			Name:      spec.KMS.Name,
+			Endpoint:  spec.KMS.Endpoint,
+			CacheSize: spec.KMS.CacheSize,
+			Timeout:   spec.KMS.Timeout,
+		}}
+		providers = []encryptionProviderConfig{kms, aescbc, secretbox, identity}
+	case spec.Provider == installv1alpha1.EncryptionProviderSecretbox:
+		providers = []encryptionProviderConfig{secretbox, aescbc, identity}
+	default:
+		providers = []encryptionProviderConfig{aescbc, secretbox, identity}
+	}
+
+	config := encryptionConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiserver.config.k8s.io/v1",
+			Kind:       "EncryptionConfiguration",
+		},
+		Resources: []encryptionResourceConfiguration{
+			{Resources: resources, Providers: providers},
+		},
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// EnsureKubeAPIServerEncryptionConfig ensures the Secret holding the EncryptionConfiguration of the
+// karmada-apiserver exists if the encryption at rest is set by the karmada, with a key generated at
+// install time, and stages a new key if a rotation is requested by the firefly.io/rotate-encryption-key
+// annotation. It returns the EncryptionConfiguration, or an empty string if the resources aren't encrypted.
+func (ctrl *KarmadaController) EnsureKubeAPIServerEncryptionConfig(ctx context.Context, karmada *installv1alpha1.Karmada) (string, error) {
+	spec := karmada.Spec.APIServer.Encryption
+	got, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, encryptionConfigSecretName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if spec == nil {
+			return "", nil
+		}
+		key, err := generateEncryptionKey()
+		if err != nil {
+			return "", err
+		}
+		secret := encryptionConfigSecret(karmada)
+		if err := setEncryptionKeys(karmada, secret, []encryptionKey{key}, nil); err != nil {
+			return "", err
+		}
+		// the rotation requested before the install is fulfilled by the generated key.
+		if trigger := karmada.Annotations[constants.RotateEncryptionKeyAnnotation]; trigger != "" {
+			metav1.SetMetaDataAnnotation(&secret.ObjectMeta, constants.RotateEncryptionKeyAnnotation, trigger)
+		}
+		if err := patchutil.Apply(secret, karmada.Spec.Patches); err != nil {
+			return "", err
+		}
+		if _, err := ctrl.client.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return "", err
+		}
+		audit.Record(ctx, audit.Create, secret, nil)
+		return string(secret.Data[encryptionConfigKey]), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	secret := got.DeepCopy()
+	keys, staged, err := encryptionKeys(secret)
+	if err != nil {
+		return "", err
+	}
+	trigger := karmada.Annotations[constants.RotateEncryptionKeyAnnotation]
+	if spec != nil && spec.Provider != installv1alpha1.EncryptionProviderKMS && staged == nil && len(keys) == 1 &&
+		trigger != "" && trigger != secret.Annotations[constants.RotateEncryptionKeyAnnotation] {
+		key, err := generateEncryptionKey()
+		if err != nil {
+			return "", err
+		}
+		staged = &key
+		metav1.SetMetaDataAnnotation(&secret.ObjectMeta, constants.RotateEncryptionKeyAnnotation, trigger)
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "EncryptionKeyRotationStarted", "Rotating the encryption key %s to %s", keys[0].Name, key.Name)
+	}
+	if err := setEncryptionKeys(karmada, secret, keys, staged); err != nil {
+		return "", err
+	}
+	if err := ctrl.updateEncryptionConfigSecret(ctx, got, secret); err != nil {
+		return "", err
+	}
+	return string(secret.Data[encryptionConfigKey]), nil
+}
+
+// encryptionConfigSecret returns an empty Secret holding the EncryptionConfiguration of the karmada-apiserver.
+func encryptionConfigSecret(karmada *installv1alpha1.Karmada) *corev1.Secret {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      encryptionConfigSecretName,
+			Namespace: karmada.Namespace,
+		},
+		Data: map[string][]byte{},
+	}
+	util.SetKarmadaInstanceLabel(secret, karmada.Name)
+	controllerutil.SetOwnerReference(karmada, secret, scheme.Scheme)
+	return secret
+}
+
+func (ctrl *KarmadaController) updateEncryptionConfigSecret(ctx context.Context, old, secret *corev1.Secret) error {
+	if equalSecretData(old, secret) {
+		return nil
+	}
+	updated, err := ctrl.client.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	audit.RecordUpdate(ctx, old, updated)
+	return nil
+}
+
+func equalSecretData(a, b *corev1.Secret) bool {
+	if len(a.Data) != len(b.Data) || len(a.Annotations) != len(b.Annotations) {
+		return false
+	}
+	for key, value := range a.Data {
+		if string(b.Data[key]) != string(value) {
+			return false
+		}
+	}
+	for key, value := range a.Annotations {
+		if b.Annotations[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// mountEncryptionConfig mounts the Secret holding the EncryptionConfiguration into the pod of the
+// kube-apiserver and passes it by the --encryption-provider-config flag, if the resources are encrypted.
+func mountEncryptionConfig(deployment *appsv1.Deployment, config string) {
+	if config == "" {
+		return
+	}
+	template := &deployment.Spec.Template
+	template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+		Name: "encryption-config",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: encryptionConfigSecretName,
+				Items:      []corev1.KeyToPath{{Key: encryptionConfigKey, Path: encryptionConfigKey}},
+			},
+		},
+	})
+	container := &template.Spec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "encryption-config",
+		MountPath: encryptionConfigDir,
+		ReadOnly:  true,
+	})
+	container.Args = append(container.Args, "--encryption-provider-config="+path.Join(encryptionConfigDir, encryptionConfigKey))
+
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[constants.EncryptionConfigHashAnnotation] = contentHash(config)
+}
+
+// EnsureEncryptionKeyRotation advances the rotation of the key of the encryption at rest, once the
+// karmada-apiservers have been restarted with the current EncryptionConfiguration. The staged key is
+// promoted to encrypt the resources, then the resources are rewritten by a job, and the old key is
+// dropped once the job is complete.
+func (ctrl *KarmadaController) EnsureEncryptionKeyRotation(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	got, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, encryptionConfigSecretName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	secret := got.DeepCopy()
+	keys, staged, err := encryptionKeys(secret)
+	if err != nil {
+		return err
+	}
+	if staged == nil && len(keys) <= 1 {
+		return nil
+	}
+
+	deployment, err := ctrl.client.AppsV1().Deployments(karmada.Namespace).Get(ctx, constants.KarmadaComponentKubeAPIServer, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if deployment.Spec.Template.Annotations[constants.EncryptionConfigHashAnnotation] != contentHash(string(secret.Data[encryptionConfigKey])) ||
+		!deploymentRolledOut(deployment) {
+		ctrl.enqueueAfter(karmada, encryptionRotationCheckInterval)
+		return nil
+	}
+
+	if staged != nil {
+		if err := setEncryptionKeys(karmada, secret, append([]encryptionKey{*staged}, keys...), nil); err != nil {
+			return err
+		}
+		if err := ctrl.updateEncryptionConfigSecret(ctx, got, secret); err != nil {
+			return err
+		}
+		// the karmada-apiserver is restarted with the promoted key by the next reconcile.
+		ctrl.enqueue(karmada)
+		return nil
+	}
+	return ctrl.reencryptResources(ctx, karmada, got, secret, keys)
+}
+
+// reencryptResources runs the job which rewrites the encrypted resources with the current key,
+// and drops the old keys held by the Secret once the job is complete.
+func (ctrl *KarmadaController) reencryptResources(ctx context.Context, karmada *installv1alpha1.Karmada, old, secret *corev1.Secret, keys []encryptionKey) error {
+	resources := strings.Split(string(secret.Data[encryptionResourcesKey]), ",")
+	job, err := reencryptJob(karmada, resources)
+	if err != nil {
+		return err
+	}
+	got, err := ctrl.client.BatchV1().Jobs(job.Namespace).Get(ctx, job.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err := ctrl.client.BatchV1().Jobs(job.Namespace).Create(ctx, job, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		audit.Record(ctx, audit.Create, job, nil)
+		ctrl.enqueueAfter(karmada, encryptionRotationCheckInterval)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case jobConditionTrue(got, batchv1.JobComplete):
+		if err := setEncryptionKeys(karmada, secret, keys[:1], nil); err != nil {
+			return err
+		}
+		if err := ctrl.updateEncryptionConfigSecret(ctx, old, secret); err != nil {
+			return err
+		}
+
+		now := metav1.Now()
+		if err := ctrl.updateKarmadaStatus(ctx, karmada, func(status *installv1alpha1.KarmadaStatus) {
+			status.EncryptionKeyRotationTime = &now
+		}); err != nil {
+			return err
+		}
+
+		if err := ctrl.deleteJob(ctx, got); err != nil {
+			return err
+		}
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "EncryptionKeyRotated", "Rotated the encryption key to %s", keys[0].Name)
+		ctrl.enqueue(karmada)
+		return nil
+	case jobConditionTrue(got, batchv1.JobFailed):
+		// the job is started over by the next reconcile, the old keys are kept until it's complete.
+		if err := ctrl.deleteJob(ctx, got); err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to rewrite the encrypted resources, see the logs of job %s", got.Name)
+	}
+
+	ctrl.enqueueAfter(karmada, encryptionRotationCheckInterval)
+	return nil
+}
+
+// reencryptJob returns the job which rewrites the encrypted resources of the karmada-apiserver, so that
+// they're encrypted with the current key.
+func reencryptJob(karmada *installv1alpha1.Karmada, resources []string) (*batchv1.Job, error) {
+	image := "docker.io/bitnami/kubectl:" + strings.TrimPrefix(karmada.Spec.KubernetesVersion, "v")
+	if spec := karmada.Spec.APIServer.Encryption; spec != nil && spec.ReencryptImage != "" {
+		image = spec.ReencryptImage
+	}
+	script := fmt.Sprintf("kubectl get %s --all-namespaces -o json | kubectl replace -f -", strings.Join(resources, ","))
+
+	job := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      reencryptJobName,
+			Namespace: karmada.Namespace,
+			Labels: map[string]string{
+				"app": reencryptJobName,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: pointer.Int32(3),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app": reencryptJobName,
+					},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:            "reencrypt",
+							Image:           image,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Command:         []string{"/bin/sh", "-c", script},
+							Env: []corev1.EnvVar{
+								{Name: "KUBECONFIG", Value: "/etc/kubeconfig"},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "kubeconfig",
+									MountPath: "/etc/kubeconfig",
+									SubPath:   "kubeconfig",
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "kubeconfig",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: "karmada-kubeconfig",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	util.SetKarmadaInstanceLabel(job, karmada.Name)
+
+	controllerutil.SetOwnerReference(karmada, job, scheme.Scheme)
+	if err := patchutil.Apply(job, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// deleteJob deletes the job along with its pods.
+func (ctrl *KarmadaController) deleteJob(ctx context.Context, job *batchv1.Job) error {
+	policy := metav1.DeletePropagationBackground
+	err := ctrl.client.BatchV1().Jobs(job.Namespace).Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: &policy})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	audit.RecordResult(ctx, audit.Delete, job, err)
+	return err
+}
+
+func jobConditionTrue(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// deploymentRolledOut returns whether all replicas of the deployment are updated and available.
+func deploymentRolledOut(deployment *appsv1.Deployment) bool {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false
+	}
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
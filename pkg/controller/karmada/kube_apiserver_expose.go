@@ -0,0 +1,313 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/capabilities"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+// EnsureKubeAPIServerExposure exposes the karmada-apiserver through the Ingress or the Gateway API
+// if required, and makes sure the certificate of the karmada-apiserver is valid for the external addresses
+// and the custom SANs.
+func (ctrl *KarmadaController) EnsureKubeAPIServerExposure(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if err := ctrl.EnsureKubeAPIServerIngress(ctx, karmada); err != nil {
+		return err
+	}
+	if err := ctrl.EnsureKubeAPIServerTLSRoute(ctx, karmada); err != nil {
+		return err
+	}
+
+	// the custom SANs are ensured first, so that the hosts which are dropped from them but still
+	// required by the exposure are added back.
+	if err := ctrl.EnsureAPIServerCertSANs(ctx, karmada); err != nil {
+		return err
+	}
+	hosts, err := ctrl.KubeAPIServerExternalHosts(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	return ctrl.EnsureKubeAPIServerCertSANs(ctx, karmada, hosts)
+}
+
+// exposedThroughGateway returns whether the karmada-apiserver is exposed through the Gateway API, which
+// is preferred to the Ingress if both are set but only used if it's installed into the host cluster.
+func (ctrl *KarmadaController) exposedThroughGateway(karmada *installv1alpha1.Karmada) bool {
+	return karmada.Spec.APIServer.Gateway != nil && ctrl.hostCapabilities.Has(capabilities.GatewayAPI)
+}
+
+// exposedThroughIngress returns whether the karmada-apiserver is exposed through the Ingress.
+func (ctrl *KarmadaController) exposedThroughIngress(karmada *installv1alpha1.Karmada) bool {
+	return karmada.Spec.APIServer.Ingress != nil && !ctrl.exposedThroughGateway(karmada)
+}
+
+func (ctrl *KarmadaController) EnsureKubeAPIServerIngress(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if !ctrl.exposedThroughIngress(karmada) {
+		componentName := constants.KarmadaComponentKubeAPIServer
+		err := ctrl.client.NetworkingV1().Ingresses(karmada.Namespace).Delete(ctx, componentName, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Ingress", Namespace: karmada.Namespace, Name: componentName}, err)
+		return client.IgnoreNotFound(err)
+	}
+	ingress, err := kubeAPIServerIngress(karmada)
+	if err != nil {
+		return err
+	}
+	result, err := clientutil.CreateOrUpdateIngress(ctx, ctrl.client, ingress)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, ingress, result)
+	return err
+}
+
+// kubeAPIServerIngress returns the ingress which exposes the kube-apiserver of the karmada,
+// which must have spec.apiServer.ingress set.
+func kubeAPIServerIngress(karmada *installv1alpha1.Karmada) (*networkingv1.Ingress, error) {
+	componentName := constants.KarmadaComponentKubeAPIServer
+	spec := karmada.Spec.APIServer.Ingress
+
+	defaultAnnotations := map[string]string{
+		"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS",
+	}
+	var tls []networkingv1.IngressTLS
+	if spec.TLSSecretName != "" {
+		tls = []networkingv1.IngressTLS{{Hosts: []string{spec.Hostname}, SecretName: spec.TLSSecretName}}
+	} else {
+		defaultAnnotations["nginx.ingress.kubernetes.io/ssl-passthrough"] = "true"
+	}
+
+	pathType := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.k8s.io/v1",
+			Kind:       "Ingress",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        componentName,
+			Namespace:   karmada.Namespace,
+			Annotations: maputil.MergeStringMaps(defaultAnnotations, spec.Annotations),
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: spec.IngressClassName,
+			TLS:              tls,
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: spec.Hostname,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: componentName,
+											Port: networkingv1.ServiceBackendPort{Number: 5443},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	util.SetKarmadaInstanceLabel(ingress, karmada.Name)
+	controllerutil.SetOwnerReference(karmada, ingress, scheme.Scheme)
+	if err := patchutil.Apply(ingress, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return ingress, nil
+}
+
+func (ctrl *KarmadaController) EnsureKubeAPIServerTLSRoute(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if !ctrl.exposedThroughGateway(karmada) {
+		return ctrl.applier.Delete(ctx, newKubeAPIServerTLSRoute(karmada))
+	}
+	route, err := kubeAPIServerTLSRoute(karmada)
+	if err != nil {
+		return err
+	}
+	_, err = ctrl.applier.Apply(ctx, route)
+	return err
+}
+
+// newKubeAPIServerTLSRoute returns the TLSRoute of the karmada, which only has its identity set.
+func newKubeAPIServerTLSRoute(karmada *installv1alpha1.Karmada) *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetAPIVersion("gateway.networking.k8s.io/v1alpha2")
+	route.SetKind("TLSRoute")
+	route.SetName(constants.KarmadaComponentKubeAPIServer)
+	route.SetNamespace(karmada.Namespace)
+	return route
+}
+
+// kubeAPIServerTLSRoute returns the TLSRoute which exposes the kube-apiserver of the karmada,
+// which must have spec.apiServer.gateway set.
+func kubeAPIServerTLSRoute(karmada *installv1alpha1.Karmada) (*unstructured.Unstructured, error) {
+	componentName := constants.KarmadaComponentKubeAPIServer
+	route := newKubeAPIServerTLSRoute(karmada)
+	spec := karmada.Spec.APIServer.Gateway
+
+	parentRef := map[string]interface{}{"name": spec.ParentRef.Name}
+	if spec.ParentRef.Namespace != "" {
+		parentRef["namespace"] = spec.ParentRef.Namespace
+	}
+	if spec.ParentRef.SectionName != "" {
+		parentRef["sectionName"] = spec.ParentRef.SectionName
+	}
+	route.Object["spec"] = map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"hostnames":  []interface{}{spec.Hostname},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"backendRefs": []interface{}{
+					map[string]interface{}{"name": componentName, "port": int64(5443)},
+				},
+			},
+		},
+	}
+	util.SetKarmadaInstanceLabel(route, karmada.Name)
+	controllerutil.SetOwnerReference(karmada, route, scheme.Scheme)
+	if err := patchutil.Apply(route, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return route, nil
+}
+
+// loadBalancerCheckInterval is the interval at which a karmada is requeued while the load balancer
+// of its karmada-apiserver is being provisioned.
+const loadBalancerCheckInterval = 10 * time.Second
+
+// KubeAPIServerExternalHosts returns the host names and IPs which the karmada-apiserver is
+// accessed with from outside of the host cluster. The addresses of a load balancer which is
+// still being provisioned are left out, and the karmada is requeued until they're assigned.
+func (ctrl *KarmadaController) KubeAPIServerExternalHosts(ctx context.Context, karmada *installv1alpha1.Karmada) ([]string, error) {
+	var hosts []string
+	if endpoint := karmada.Spec.ControlPlaneEndpoint; endpoint != "" {
+		if host, _, err := net.SplitHostPort(endpoint); err == nil {
+			endpoint = host
+		}
+		hosts = append(hosts, endpoint)
+	}
+	if server := karmada.Spec.Kubeconfig.Server; server != "" {
+		u, err := url.Parse(server)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubeconfig server %q: %v", server, err)
+		}
+		hosts = append(hosts, u.Hostname())
+	}
+	if ingress := karmada.Spec.APIServer.Ingress; ingress != nil {
+		hosts = append(hosts, ingress.Hostname)
+	}
+	if gateway := karmada.Spec.APIServer.Gateway; gateway != nil {
+		hosts = append(hosts, gateway.Hostname)
+	}
+	if endpoint := globalEndpoint(karmada); endpoint != "" {
+		if host, _, err := net.SplitHostPort(endpoint); err == nil {
+			endpoint = host
+		}
+		hosts = append(hosts, endpoint)
+	}
+
+	if karmada.Spec.APIServer.ServiceType == corev1.ServiceTypeLoadBalancer {
+		addresses, err := ctrl.kubeAPIServerLoadBalancerAddresses(ctx, karmada)
+		if err != nil {
+			return nil, err
+		}
+		if len(addresses) == 0 {
+			// the rest of the karmada isn't held back by the load balancer, the certificate is re-signed
+			// for its addresses once it's provisioned.
+			klog.V(2).InfoS("Waiting for the load balancer to be provisioned", "karmada", klog.KObj(karmada), "service", constants.KarmadaComponentKubeAPIServer)
+			ctrl.enqueueAfter(karmada, loadBalancerCheckInterval)
+		}
+		hosts = append(hosts, addresses...)
+	}
+	return hosts, nil
+}
+
+func (ctrl *KarmadaController) kubeAPIServerLoadBalancerAddresses(ctx context.Context, karmada *installv1alpha1.Karmada) ([]string, error) {
+	svc, err := ctrl.client.CoreV1().Services(karmada.Namespace).Get(ctx, constants.KarmadaComponentKubeAPIServer, metav1.GetOptions{})
+	if err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return loadBalancerAddresses(svc), nil
+}
+
+// loadBalancerAddresses returns the IPs and the host names of the load balancer of the Service.
+func loadBalancerAddresses(svc *corev1.Service) []string {
+	var addresses []string
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			addresses = append(addresses, ingress.IP)
+		}
+		if ingress.Hostname != "" {
+			addresses = append(addresses, ingress.Hostname)
+		}
+	}
+	return addresses
+}
+
+// EnsureKubeAPIServerCertSANs re-signs the certificate of the karmada-apiserver with the karmada CA
+// if some of the given hosts are not in its SANs. The karmada-apiserver reloads it automatically.
+func (ctrl *KarmadaController) EnsureKubeAPIServerCertSANs(ctx context.Context, karmada *installv1alpha1.Karmada, hosts []string) error {
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	certSecret, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, "karmada-cert", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	caCert, caKey, err := parseCA(certSecret)
+	if err != nil {
+		return err
+	}
+	certData, keyData, err := resignCertForHosts(certSecret.Data["apiserver.crt"], caCert, caKey, hosts)
+	if err != nil || certData == nil {
+		return err
+	}
+
+	certSecret.Data["apiserver.crt"] = certData
+	certSecret.Data["apiserver.key"] = keyData
+	_, err = ctrl.client.CoreV1().Secrets(karmada.Namespace).Update(ctx, certSecret, metav1.UpdateOptions{})
+	audit.RecordResult(ctx, audit.Update, certSecret, err)
+	if err != nil {
+		return err
+	}
+	ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "CertificateRotated", "Re-signed the certificate of %s for hosts %v", constants.KarmadaComponentKubeAPIServer, hosts)
+	return nil
+}
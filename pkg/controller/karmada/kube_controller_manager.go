@@ -17,27 +17,58 @@ limitations under the License.
 package karmada
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
 	"github.com/carlory/firefly/pkg/constants"
 	"github.com/carlory/firefly/pkg/scheme"
 	"github.com/carlory/firefly/pkg/util"
-	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/audit"
 	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
 )
 
-func (ctrl *KarmadaController) EnsureKubeControllerManager(karmada *installv1alpha1.Karmada) error {
-	return ctrl.EnsureKubeControllerManagerDeployment(karmada)
+func (ctrl *KarmadaController) EnsureKubeControllerManager(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if kubeControllerManagerEnabled(karmada) {
+		return ctrl.EnsureKubeControllerManagerDeployment(ctx, karmada)
+	}
+	return ctrl.RemoveKubeControllerManager(ctx, karmada)
+}
+
+// kubeControllerManagerEnabled returns whether the kube-controller-manager is installed for the karmada.
+func kubeControllerManagerEnabled(karmada *installv1alpha1.Karmada) bool {
+	return pointer.BoolDeref(karmada.Spec.ControllerManager.KubeControllerManager.Enable, true)
 }
 
-func (ctrl *KarmadaController) EnsureKubeControllerManagerDeployment(karmada *installv1alpha1.Karmada) error {
+func (ctrl *KarmadaController) RemoveKubeControllerManager(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	componentName := constants.KarmadaComponentKubeControllerManager
+	err := ctrl.client.AppsV1().Deployments(karmada.Namespace).Delete(ctx, componentName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Deployment", Namespace: karmada.Namespace, Name: componentName}, err)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	return ctrl.RemovePodDisruptionBudget(ctx, karmada, componentName)
+}
+
+func (ctrl *KarmadaController) EnsureKubeControllerManagerDeployment(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	deployment, err := kubeControllerManagerDeployment(karmada)
+	if err != nil {
+		return err
+	}
+	return ctrl.ensureDeployment(ctx, karmada, deployment, nil)
+}
+
+// kubeControllerManagerDeployment returns the kube-controller-manager deployment of the karmada.
+func kubeControllerManagerDeployment(karmada *installv1alpha1.Karmada) (*appsv1.Deployment, error) {
 	componentName := constants.KarmadaComponentKubeControllerManager
 	kcm := karmada.Spec.ControllerManager.KubeControllerManager
 
@@ -89,7 +120,7 @@ func (ctrl *KarmadaController) EnsureKubeControllerManagerDeployment(karmada *in
 			defaultArgs["feature-gates"] = fmt.Sprintf("%s,%s=%t", defaultArgs["feature-gates"], feature, enabled)
 		}
 	}
-	computedArgs := maputil.MergeStringMaps(defaultArgs, kcm.ExtraArgs)
+	computedArgs := maputil.MergeStringMaps(defaultArgs, util.LoggingArgs(kcm.Logging), kcm.ExtraArgs)
 	args := maputil.ConvertToCommandOrArgs(computedArgs)
 
 	deployment := &appsv1.Deployment{
@@ -155,6 +186,11 @@ func (ctrl *KarmadaController) EnsureKubeControllerManagerDeployment(karmada *in
 			},
 		},
 	}
+	util.ApplyComponentExtras(&deployment.Spec.Template.Spec, kcm.ComponentExtras)
+	util.SetKarmadaInstanceLabel(deployment, karmada.Name)
 	controllerutil.SetOwnerReference(karmada, deployment, scheme.Scheme)
-	return clientutil.CreateOrUpdateDeployment(ctrl.client, deployment)
+	if err := patchutil.Apply(deployment, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return deployment, nil
 }
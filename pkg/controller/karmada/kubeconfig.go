@@ -0,0 +1,294 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/certs"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/kms"
+)
+
+const (
+	// readOnlyUserName is the user of the read-only kubeconfig.
+	readOnlyUserName = "firefly:readonly"
+
+	// clientCertRenewBefore is how long before the expiration the client certificates of the
+	// kubeconfigs issued by firefly, e.g. the read-only one, are renewed.
+	clientCertRenewBefore = 30 * 24 * time.Hour
+)
+
+// AdminKubeconfigSecretName returns the name of the Secret holding the admin kubeconfig of the karmada.
+func AdminKubeconfigSecretName(karmada *installv1alpha1.Karmada) string {
+	return util.ComponentName("admin-kubeconfig", karmada.Name)
+}
+
+// ReadOnlyKubeconfigSecretName returns the name of the Secret holding the read-only kubeconfig of the karmada.
+func ReadOnlyKubeconfigSecretName(karmada *installv1alpha1.Karmada) string {
+	return util.ComponentName("readonly-kubeconfig", karmada.Name)
+}
+
+// KarmadaKubeconfigSecret returns the Secret `karmada-kubeconfig`, which holds the kubeconfig used by the
+// components to access the karmada-apiserver through its in-cluster service with the given certificates.
+func KarmadaKubeconfigSecret(karmada *installv1alpha1.Karmada, caCert, clientKey, clientCert []byte) (*corev1.Secret, error) {
+	server := fmt.Sprintf("https://%s.%s.svc.%s:%v", constants.KarmadaComponentKubeAPIServer, karmada.Namespace, karmada.Spec.Networking.DNSDomain, 5443)
+	config := certs.CreateWithCerts(server, "karmada-admin", "karmada-admin", caCert, clientKey, clientCert)
+	configBytes, err := clientcmd.Write(*config)
+	if err != nil {
+		return nil, fmt.Errorf("failure while serializing admin kubeConfig. %v", err)
+	}
+
+	secret := SecretFromSpec(karmada.Namespace, "karmada-kubeconfig", corev1.SecretTypeOpaque, map[string]string{"kubeconfig": string(configBytes)})
+	util.SetKarmadaInstanceLabel(secret, karmada.Name)
+	controllerutil.SetOwnerReference(karmada, secret, scheme.Scheme)
+	return secret, nil
+}
+
+// KubeconfigServer returns the address of the karmada-apiserver written into the published kubeconfigs.
+func (ctrl *KarmadaController) KubeconfigServer(ctx context.Context, karmada *installv1alpha1.Karmada) (string, error) {
+	if karmada.Spec.Kubeconfig.Server != "" {
+		return karmada.Spec.Kubeconfig.Server, nil
+	}
+	if endpoint := globalEndpoint(karmada); endpoint != "" {
+		if _, _, err := net.SplitHostPort(endpoint); err != nil {
+			endpoint = net.JoinHostPort(endpoint, "5443")
+		}
+		return fmt.Sprintf("https://%s", endpoint), nil
+	}
+	if endpoint := karmada.Spec.ControlPlaneEndpoint; endpoint != "" {
+		if _, _, err := net.SplitHostPort(endpoint); err != nil {
+			endpoint = net.JoinHostPort(endpoint, "5443")
+		}
+		return fmt.Sprintf("https://%s", endpoint), nil
+	}
+	if ctrl.exposedThroughGateway(karmada) {
+		return fmt.Sprintf("https://%s", karmada.Spec.APIServer.Gateway.Hostname), nil
+	}
+	if ctrl.exposedThroughIngress(karmada) {
+		return fmt.Sprintf("https://%s", karmada.Spec.APIServer.Ingress.Hostname), nil
+	}
+	if karmada.Spec.APIServer.ServiceType == corev1.ServiceTypeLoadBalancer {
+		addresses, err := ctrl.kubeAPIServerLoadBalancerAddresses(ctx, karmada)
+		if err != nil {
+			return "", err
+		}
+		if len(addresses) > 0 {
+			return fmt.Sprintf("https://%s", net.JoinHostPort(addresses[0], "5443")), nil
+		}
+	}
+	return fmt.Sprintf("https://%s.%s.svc.%s:%v", constants.KarmadaComponentKubeAPIServer, karmada.Namespace, karmada.Spec.Networking.DNSDomain, 5443), nil
+}
+
+// EnsureKubeconfigSecrets publishes the admin kubeconfig and optionally the read-only kubeconfig
+// of the karmada. They're derived from the current certificates, so they're kept up to date.
+func (ctrl *KarmadaController) EnsureKubeconfigSecrets(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	certSecret, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, "karmada-cert", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	server, err := ctrl.KubeconfigServer(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	config := certs.CreateWithCerts(server, "karmada-admin", "karmada", certSecret.Data["ca.crt"], certSecret.Data["karmada.key"], certSecret.Data["karmada.crt"])
+	configBytes, err := clientcmd.Write(*config)
+	if err != nil {
+		return fmt.Errorf("failure while serializing admin kubeconfig. %v", err)
+	}
+	secret := SecretFromSpec(karmada.Namespace, AdminKubeconfigSecretName(karmada), corev1.SecretTypeOpaque, map[string]string{"kubeconfig": string(configBytes)})
+	util.SetKarmadaInstanceLabel(secret, karmada.Name)
+	controllerutil.SetOwnerReference(karmada, secret, scheme.Scheme)
+	if _, err := ctrl.createOrUpdateEncryptedSecret(ctx, karmada, secret); err != nil {
+		return err
+	}
+
+	if !karmada.Spec.Kubeconfig.ReadOnly {
+		return ctrl.RemoveReadOnlyKubeconfigSecret(ctx, karmada)
+	}
+	return ctrl.EnsureReadOnlyKubeconfigSecret(ctx, karmada, certSecret, server)
+}
+
+// RemoveReadOnlyKubeconfigSecret revokes the read-only kubeconfig once it's turned off. The binding of
+// its user is deleted before the kubeconfig, so that it's retried until the kubeconfig is gone. Otherwise
+// a copy of the kubeconfig would still be able to view the resources of the karmada.
+func (ctrl *KarmadaController) RemoveReadOnlyKubeconfigSecret(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	secretName := ReadOnlyKubeconfigSecretName(karmada)
+	_, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if err := ctrl.RemoveReadOnlyClusterRoleBinding(ctx, karmada); err != nil {
+		return err
+	}
+	err = ctrl.client.CoreV1().Secrets(karmada.Namespace).Delete(ctx, secretName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Secret", Namespace: karmada.Namespace, Name: secretName}, err)
+	return client.IgnoreNotFound(err)
+}
+
+// EnsureReadOnlyKubeconfigSecret publishes a kubeconfig whose user is only allowed to view the resources
+// of the karmada. The client certificate is reused until it's going to expire or the CA is rotated.
+func (ctrl *KarmadaController) EnsureReadOnlyKubeconfigSecret(ctx context.Context, karmada *installv1alpha1.Karmada, certSecret *corev1.Secret, server string) error {
+	if err := ctrl.EnsureReadOnlyClusterRoleBinding(ctx, karmada); err != nil {
+		return err
+	}
+
+	caCert, caKey, err := parseCA(certSecret)
+	if err != nil {
+		return err
+	}
+
+	secretName := ReadOnlyKubeconfigSecretName(karmada)
+	certData, keyData := ctrl.reusableClientCert(ctx, karmada.Namespace, secretName, readOnlyUserName, caCert)
+	if certData == nil {
+		certData, keyData, err = newClientCert(caCert, caKey, readOnlyUserName)
+		if err != nil {
+			return err
+		}
+		ctrl.eventRecorder.Event(karmada, corev1.EventTypeNormal, "CertificateRotated", "Issued a new client certificate for the read-only kubeconfig")
+	}
+
+	config := certs.CreateWithCerts(server, readOnlyUserName, "karmada", certSecret.Data["ca.crt"], keyData, certData)
+	configBytes, err := clientcmd.Write(*config)
+	if err != nil {
+		return fmt.Errorf("failure while serializing read-only kubeconfig. %v", err)
+	}
+	secret := SecretFromSpec(karmada.Namespace, secretName, corev1.SecretTypeOpaque, map[string]string{"kubeconfig": string(configBytes)})
+	util.SetKarmadaInstanceLabel(secret, karmada.Name)
+	controllerutil.SetOwnerReference(karmada, secret, scheme.Scheme)
+	result, err := ctrl.createOrUpdateEncryptedSecret(ctx, karmada, secret)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, secret, result)
+	return err
+}
+
+// newClientCert issues a client certificate of the user signed by the CA, which is valid for a year.
+func newClientCert(caCert *x509.Certificate, caKey crypto.Signer, userName string) ([]byte, []byte, error) {
+	notAfter := time.Now().Add(certs.Duration365d).UTC()
+	certCfg := certs.NewCertConfig(userName, []string{}, certutil.AltNames{}, &notAfter)
+	cert, key, err := certs.NewCertAndKey(caCert, caKey, certCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyData, err := keyutil.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return certs.EncodeCertPEM(cert), keyData, nil
+}
+
+// reusableClientCert returns the client certificate and key of the user in the kubeconfig published
+// in the secret if they're still valid. Otherwise nil is returned.
+func (ctrl *KarmadaController) reusableClientCert(ctx context.Context, namespace, secretName, userName string, caCert *x509.Certificate) ([]byte, []byte) {
+	secret, err := ctrl.client.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil
+	}
+	kubeconfig, err := kms.SecretData(ctx, secret, "kubeconfig")
+	if err != nil {
+		return nil, nil
+	}
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, nil
+	}
+	authInfo, ok := config.AuthInfos[userName]
+	if !ok {
+		return nil, nil
+	}
+	clientCerts, err := certutil.ParseCertsPEM(authInfo.ClientCertificateData)
+	if err != nil {
+		return nil, nil
+	}
+	if time.Now().Add(clientCertRenewBefore).After(clientCerts[0].NotAfter) {
+		return nil, nil
+	}
+	if err := clientCerts[0].CheckSignatureFrom(caCert); err != nil {
+		return nil, nil
+	}
+	return authInfo.ClientCertificateData, authInfo.ClientKeyData
+}
+
+// EnsureReadOnlyClusterRoleBinding binds the user of the read-only kubeconfig to the view ClusterRole of the karmada.
+func (ctrl *KarmadaController) EnsureReadOnlyClusterRoleBinding(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	crb := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: readOnlyUserName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:     rbacv1.UserKind,
+				Name:     readOnlyUserName,
+				APIGroup: rbacv1.GroupName,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind:     "ClusterRole",
+			Name:     "view",
+			APIGroup: rbacv1.GroupName,
+		},
+	}
+	result, err := clientutil.CreateOrUpdateClusterRoleBinding(ctx, kubeClient, crb)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, crb, result)
+	return err
+}
+
+// RemoveReadOnlyClusterRoleBinding unbinds the user of the read-only kubeconfig from the view ClusterRole of the karmada.
+func (ctrl *KarmadaController) RemoveReadOnlyClusterRoleBinding(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	err = kubeClient.RbacV1().ClusterRoleBindings().Delete(ctx, readOnlyUserName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "ClusterRoleBinding", Name: readOnlyUserName}, err)
+	return client.IgnoreNotFound(err)
+}
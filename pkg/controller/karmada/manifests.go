@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/helm"
+	"github.com/carlory/firefly/pkg/util/manifest"
+)
+
+// RenderManifests returns the objects which the controller applies to the host cluster for the
+// karmada, without applying them. The fetcher loads spec.chart if it's set.
+//
+// The objects generated at install time, that is the certificates and the kubeconfigs, and the
+// objects applied to the karmada-apiserver once it's up, are not part of the manifests.
+func RenderManifests(ctx context.Context, fetcher *helm.Fetcher, karmada *installv1alpha1.Karmada) ([]runtime.Object, error) {
+	var bundle manifest.Bundle
+	if karmada.Spec.Chart != nil {
+		objs, err := renderChart(ctx, fetcher, karmada)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			bundle.Add(obj, nil)
+		}
+		return bundle.Objects()
+	}
+
+	bundle.Add(etcdService(karmada))
+	bundle.Add(etcdStatefulSet(karmada))
+	if etcdDefragmentationEnabled(karmada) {
+		bundle.Add(etcdDefragCronJob(karmada))
+	}
+	bundle.Add(kubeAPIServerService(karmada))
+	if audit := karmada.Spec.APIServer.Audit; audit != nil && audit.Policy != "" {
+		bundle.Add(kubeAPIServerAuditPolicyConfigMap(karmada))
+	}
+	bundle.Add(kubeAPIServerDeployment(karmada))
+	bundle.Add(karmadaAggregatedAPIServerService(karmada))
+	bundle.Add(karmadaAggregatedAPIServerDeployment(karmada))
+	bundle.Add(karmadaWebhookService(karmada))
+	bundle.Add(karmadaWebhookDeployment(karmada))
+	if karmadaSearchEnabled(karmada) {
+		bundle.Add(karmadaSearchService(karmada))
+		bundle.Add(karmadaSearchDeployment(karmada))
+	}
+	if karmadaMetricsAdapterEnabled(karmada) {
+		bundle.Add(karmadaMetricsAdapterService(karmada))
+		bundle.Add(karmadaMetricsAdapterDeployment(karmada))
+	}
+	if karmada.Spec.APIServer.Ingress != nil {
+		bundle.Add(kubeAPIServerIngress(karmada))
+	}
+	if karmada.Spec.APIServer.Gateway != nil {
+		bundle.Add(kubeAPIServerTLSRoute(karmada))
+	}
+	if kubeControllerManagerEnabled(karmada) {
+		bundle.Add(kubeControllerManagerDeployment(karmada))
+	}
+	bundle.Add(karmadaControllerManagerDeployment(karmada))
+	if fireflyKarmadaManagerEnabled(karmada) {
+		bundle.Add(fireflyKarmadaManagerServiceAccount(karmada))
+		bundle.Add(fireflyKarmadaManagerClusterRoleBinding(karmada))
+		bundle.Add(fireflyKarmadaManagerRoleBinding(karmada))
+		bundle.Add(fireflyKarmadaManagerDeployment(karmada))
+	}
+	if multiclusterCloudProviderEnabled(karmada) {
+		bundle.Add(multiclusterCloudProviderDeployment(karmada))
+	}
+	if karmada.Spec.Scheduler.KarmadaScheduler.Config != nil {
+		bundle.Add(karmadaSchedulerConfigMap(karmada))
+	}
+	bundle.Add(karmadaSchedulerDeployment(karmada))
+	if karmadaDeschedulerEnabled(karmada) {
+		bundle.Add(karmadaDeschedulerDeployment(karmada))
+	}
+	for _, obj := range podMonitors(karmada) {
+		bundle.Add(obj, nil)
+	}
+	if karmada.Spec.NetworkPolicy.Enabled {
+		policies, err := networkPolicies(karmada)
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range policies {
+			bundle.Add(policy, nil)
+		}
+	}
+	return bundle.Objects()
+}
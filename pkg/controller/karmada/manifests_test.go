@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// testKarmada returns a defaulted karmada with the optional components enabled.
+func testKarmada() *installv1alpha1.Karmada {
+	karmada := &installv1alpha1.Karmada{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "karmada-test", UID: "8f6b4c2e-1d3a-4e5f-9a7b-0c1d2e3f4a5b"},
+	}
+	installv1alpha1.SetDefaults_Karmada(karmada)
+	karmada.Spec.APIServer.KarmadaSearch.Enable = pointerBool(true)
+	karmada.Spec.APIServer.KarmadaMetricsAdapter.Enable = pointerBool(true)
+	karmada.Spec.Scheduler.KarmadaDescheduler.Enable = pointerBool(true)
+	karmada.Spec.ControllerManager.MulticlusterCloudProvider.Enable = pointerBool(true)
+	karmada.Spec.Etcd.Local = &installv1alpha1.LocalEtcd{
+		Defragmentation: &installv1alpha1.EtcdDefragmentation{Schedule: "0 3 * * *"},
+	}
+	karmada.Spec.APIServer.Audit = &installv1alpha1.APIServerAudit{
+		Policy: "apiVersion: audit.k8s.io/v1\nkind: Policy\nrules:\n- level: Metadata\n",
+		Log:    &installv1alpha1.AuditLogBackend{},
+	}
+	return karmada
+}
+
+func pointerBool(b bool) *bool {
+	return &b
+}
+
+// TestManifests compares the objects rendered for the components of a karmada with the golden files
+// in testdata. Run the test with -update to update them after an intended change.
+func TestManifests(t *testing.T) {
+	karmada := testKarmada()
+	manifests := map[string]func() (runtime.Object, error){
+		"etcd-service":                            func() (runtime.Object, error) { return etcdService(karmada) },
+		"etcd-statefulset":                        func() (runtime.Object, error) { return etcdStatefulSet(karmada) },
+		"etcd-defrag-cronjob":                     func() (runtime.Object, error) { return etcdDefragCronJob(karmada) },
+		"kube-apiserver-service":                  func() (runtime.Object, error) { return kubeAPIServerService(karmada) },
+		"kube-apiserver-deployment":               func() (runtime.Object, error) { return kubeAPIServerDeployment(karmada) },
+		"kube-apiserver-audit-policy-configmap":   func() (runtime.Object, error) { return kubeAPIServerAuditPolicyConfigMap(karmada) },
+		"kube-controller-manager-deployment":      func() (runtime.Object, error) { return kubeControllerManagerDeployment(karmada) },
+		"karmada-aggregated-apiserver-service":    func() (runtime.Object, error) { return karmadaAggregatedAPIServerService(karmada) },
+		"karmada-aggregated-apiserver-deployment": func() (runtime.Object, error) { return karmadaAggregatedAPIServerDeployment(karmada) },
+		"karmada-controller-manager-deployment":   func() (runtime.Object, error) { return karmadaControllerManagerDeployment(karmada) },
+		"karmada-descheduler-deployment":          func() (runtime.Object, error) { return karmadaDeschedulerDeployment(karmada) },
+		"karmada-metrics-adapter-service":         func() (runtime.Object, error) { return karmadaMetricsAdapterService(karmada) },
+		"karmada-metrics-adapter-deployment":      func() (runtime.Object, error) { return karmadaMetricsAdapterDeployment(karmada) },
+		"karmada-scheduler-configmap":             func() (runtime.Object, error) { return karmadaSchedulerConfigMap(karmada) },
+		"karmada-scheduler-deployment":            func() (runtime.Object, error) { return karmadaSchedulerDeployment(karmada) },
+		"karmada-search-service":                  func() (runtime.Object, error) { return karmadaSearchService(karmada) },
+		"karmada-search-deployment":               func() (runtime.Object, error) { return karmadaSearchDeployment(karmada) },
+		"karmada-webhook-service":                 func() (runtime.Object, error) { return karmadaWebhookService(karmada) },
+		"karmada-webhook-deployment":              func() (runtime.Object, error) { return karmadaWebhookDeployment(karmada) },
+		"multicluster-cloud-provider-deployment":  func() (runtime.Object, error) { return multiclusterCloudProviderDeployment(karmada) },
+		"firefly-karmada-manager-deployment":      func() (runtime.Object, error) { return fireflyKarmadaManagerDeployment(karmada) },
+	}
+	for name, render := range manifests {
+		t.Run(name, func(t *testing.T) {
+			obj, err := render()
+			if err != nil {
+				t.Fatalf("failed to render %s: %v", name, err)
+			}
+			compareGolden(t, name, obj)
+		})
+	}
+}
+
+// compareGolden compares the YAML of the object with the golden file of the name, or updates the
+// golden file with it if the test is run with -update.
+func compareGolden(t *testing.T, name string, obj runtime.Object) {
+	t.Helper()
+	got, err := yaml.Marshal(obj)
+	if err != nil {
+		t.Fatalf("failed to marshal %s: %v", name, err)
+	}
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("failed to update %s: %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s, run the test with -update to create it: %v", path, err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s differs from %s, run the test with -update if the change is intended:\n%s", name, path, got)
+	}
+}
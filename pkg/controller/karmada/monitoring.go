@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/util/capabilities"
+	chartutil "github.com/carlory/firefly/pkg/util/chart"
+	"github.com/carlory/firefly/pkg/util/monitoring"
+)
+
+// etcdMetricsPort is the port on which etcd serves its metrics over plain http when the
+// karmada is monitored. The client port requires client certificates.
+const etcdMetricsPort = 2381
+
+// estimatorHealthPort is the port on which the karmada-scheduler-estimators serve their health endpoint
+// and metrics over plain http.
+const estimatorHealthPort = 10351
+
+// metricsEndpoints are the metrics endpoints of the components which serve their metrics
+// over plain http. The apiservers and the webhook only serve them over authenticated https.
+var metricsEndpoints = []monitoring.Endpoint{
+	{Component: constants.KarmadaComponentEtcd, Port: etcdMetricsPort, Path: "/metrics"},
+	{Component: constants.KarmadaComponentControllerManager, Port: 8080, Path: "/metrics"},
+	{Component: constants.KarmadaComponentScheduler, Port: 10351, Path: "/metrics"},
+	{Component: constants.KarmadaComponentDescheduler, Port: 10358, Path: "/metrics"},
+}
+
+// monitored returns whether the component of the karmada is monitored.
+func monitored(karmada *installv1alpha1.Karmada, component string) bool {
+	if !karmada.Spec.Monitoring.Enabled {
+		return false
+	}
+	if component == constants.KarmadaComponentDescheduler {
+		return karmadaDeschedulerEnabled(karmada)
+	}
+	return true
+}
+
+// podMonitors returns the PodMonitors of the monitored components of the karmada.
+func podMonitors(karmada *installv1alpha1.Karmada) []*unstructured.Unstructured {
+	var objs []*unstructured.Unstructured
+	for _, endpoint := range metricsEndpoints {
+		if monitored(karmada, endpoint.Component) {
+			objs = append(objs, monitoring.PodMonitor(karmada.Namespace, endpoint, karmada.Spec.Monitoring))
+		}
+	}
+	return objs
+}
+
+// EnsureMonitoring creates a PodMonitor for each monitored component of the karmada if the
+// Prometheus Operator is installed, and deletes the PodMonitors of the components which are
+// no longer monitored.
+func (ctrl *KarmadaController) EnsureMonitoring(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if !ctrl.hostCapabilities.Has(capabilities.PodMonitors) {
+		return nil
+	}
+	for _, endpoint := range metricsEndpoints {
+		if monitored(karmada, endpoint.Component) {
+			continue
+		}
+		obj := monitoring.PodMonitor(karmada.Namespace, endpoint, karmada.Spec.Monitoring)
+		if err := ctrl.applier.Delete(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return chartutil.Apply(ctx, ctrl.applier, karmada, installv1alpha1.SchemeGroupVersion.WithKind("Karmada"), podMonitors(karmada))
+}
+
+// annotateForScraping adds the scrape annotations to the pod template of the component if it's
+// monitored but the Prometheus Operator is not installed.
+func (ctrl *KarmadaController) annotateForScraping(karmada *installv1alpha1.Karmada, component string, template *corev1.PodTemplateSpec) {
+	if ctrl.hostCapabilities.Has(capabilities.PodMonitors) || !monitored(karmada, component) {
+		return
+	}
+	for _, endpoint := range metricsEndpoints {
+		if endpoint.Component == component {
+			monitoring.Annotate(template, endpoint)
+		}
+	}
+}
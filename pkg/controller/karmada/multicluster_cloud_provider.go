@@ -0,0 +1,431 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/apply"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/certs"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	maputil "github.com/carlory/firefly/pkg/util/map"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+const (
+	// multiclusterCloudProviderUserName is the user of the client certificate of the
+	// multicluster-cloud-provider, which is also the name of its ClusterRole and ClusterRoleBinding.
+	multiclusterCloudProviderUserName = "firefly:multicluster-cloud-provider"
+
+	// multiclusterCloudProviderKubeconfigSecretName is the name of the Secret holding the kubeconfig
+	// of the multicluster-cloud-provider.
+	multiclusterCloudProviderKubeconfigSecretName = "multicluster-cloud-provider-kubeconfig"
+
+	// multiclusterCloudProviderPort is the port on which the multicluster-cloud-provider serves its health checks.
+	multiclusterCloudProviderPort = 10368
+
+	// multiclusterIngressGroup is the api group of the MultiClusterIngresses and MultiClusterServices.
+	multiclusterIngressGroup = "networking.karmada.io"
+)
+
+func (ctrl *KarmadaController) EnsureMulticlusterCloudProvider(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if !multiclusterCloudProviderEnabled(karmada) {
+		return ctrl.RemoveMulticlusterCloudProvider(ctx, karmada)
+	}
+
+	if err := ctrl.EnsureMulticlusterCloudProviderResources(ctx, karmada); err != nil {
+		return err
+	}
+	if err := ctrl.EnsureMulticlusterCloudProviderKubeconfigSecret(ctx, karmada); err != nil {
+		return err
+	}
+	return ctrl.EnsureMulticlusterCloudProviderDeployment(ctx, karmada)
+}
+
+// multiclusterCloudProviderEnabled returns whether the multicluster-cloud-provider is installed for the karmada.
+func multiclusterCloudProviderEnabled(karmada *installv1alpha1.Karmada) bool {
+	return pointer.BoolDeref(karmada.Spec.ControllerManager.MulticlusterCloudProvider.Enable, false)
+}
+
+// RemoveMulticlusterCloudProvider deletes the host cluster resources of the multicluster-cloud-provider.
+// Its RBAC in the karmada-apiserver is only deleted if its kubeconfig is found, that is the component
+// has been installed, so that the karmada-apiserver isn't accessed for the karmadas never using it.
+func (ctrl *KarmadaController) RemoveMulticlusterCloudProvider(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	componentName := constants.KarmadaComponentMulticlusterCloudProvider
+	err := ctrl.client.AppsV1().Deployments(karmada.Namespace).Delete(ctx, componentName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Deployment", Namespace: karmada.Namespace, Name: componentName}, err)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	if err := ctrl.RemovePodDisruptionBudget(ctx, karmada, componentName); err != nil {
+		return err
+	}
+
+	_, err = ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, multiclusterCloudProviderKubeconfigSecretName, metav1.GetOptions{})
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if err := ctrl.RemoveMulticlusterCloudProviderRBAC(ctx, karmada); err != nil {
+		return err
+	}
+	err = ctrl.client.CoreV1().Secrets(karmada.Namespace).Delete(ctx, multiclusterCloudProviderKubeconfigSecretName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Secret", Namespace: karmada.Namespace, Name: multiclusterCloudProviderKubeconfigSecretName}, err)
+	return client.IgnoreNotFound(err)
+}
+
+// EnsureMulticlusterCloudProviderResources applies the CRDs of the MultiClusterIngresses and
+// MultiClusterServices of spec.karmadaVersion and the RBAC of the multicluster-cloud-provider
+// into the karmada-apiserver. The CRDs are part of the ones bootstrapped for the karmada, they're
+// applied again in case they have been removed, e.g. by hand.
+func (ctrl *KarmadaController) EnsureMulticlusterCloudProviderResources(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	applier, err := apply.NewApplier(clientConfig, bootstrapFieldManager)
+	if err != nil {
+		return err
+	}
+
+	version := karmada.Spec.KarmadaVersion
+	crds, err := ctrl.crdFetcher.Fetch(ctx, version)
+	if err != nil {
+		return err
+	}
+	for _, crd := range crds {
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		if group != multiclusterIngressGroup {
+			continue
+		}
+		// the field manager is the bootstrap one, so the label must be kept.
+		labels := crd.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[BootstrapVersionLabel] = version
+		crd.SetLabels(labels)
+		if _, err := applier.Apply(ctx, crd); err != nil {
+			return err
+		}
+	}
+
+	objs, err := multiclusterCloudProviderRBAC()
+	if err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		if _, err := applier.Apply(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveMulticlusterCloudProviderRBAC deletes the RBAC of the multicluster-cloud-provider from the karmada-apiserver.
+func (ctrl *KarmadaController) RemoveMulticlusterCloudProviderRBAC(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	applier, err := apply.NewApplier(clientConfig, bootstrapFieldManager)
+	if err != nil {
+		return err
+	}
+	objs, err := multiclusterCloudProviderRBAC()
+	if err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		if err := applier.Delete(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// multiclusterCloudProviderRBAC returns the ClusterRole and ClusterRoleBinding of the user of the
+// multicluster-cloud-provider. It reconciles the MultiClusterIngresses and MultiClusterServices
+// with the services and endpoints of the member clusters, and elects its leader by a Lease.
+func multiclusterCloudProviderRBAC() ([]*unstructured.Unstructured, error) {
+	return unstructuredObjects(
+		&rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: multiclusterCloudProviderUserName},
+			Rules: []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{multiclusterIngressGroup},
+					Resources: []string{"multiclusteringresses", "multiclusterservices"},
+					Verbs:     []string{"get", "list", "watch", "update", "patch"},
+				},
+				{
+					APIGroups: []string{multiclusterIngressGroup},
+					Resources: []string{"multiclusteringresses/status", "multiclusterservices/status"},
+					Verbs:     []string{"update", "patch"},
+				},
+				{
+					APIGroups: []string{"cluster.karmada.io"},
+					Resources: []string{"clusters"},
+					Verbs:     []string{"get", "list", "watch"},
+				},
+				{
+					APIGroups: []string{"work.karmada.io"},
+					Resources: []string{"resourcebindings", "works"},
+					Verbs:     []string{"get", "list", "watch"},
+				},
+				{
+					APIGroups: []string{""},
+					Resources: []string{"services", "endpoints", "secrets"},
+					Verbs:     []string{"get", "list", "watch"},
+				},
+				{
+					APIGroups: []string{"discovery.k8s.io"},
+					Resources: []string{"endpointslices"},
+					Verbs:     []string{"get", "list", "watch"},
+				},
+				{
+					APIGroups: []string{"", "events.k8s.io"},
+					Resources: []string{"events"},
+					Verbs:     []string{"create", "update", "patch"},
+				},
+				{
+					APIGroups: []string{"coordination.k8s.io"},
+					Resources: []string{"leases"},
+					Verbs:     []string{"get", "create", "update"},
+				},
+			},
+		},
+		&rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: multiclusterCloudProviderUserName},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     multiclusterCloudProviderUserName,
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					APIGroup: rbacv1.GroupName,
+					Kind:     rbacv1.UserKind,
+					Name:     multiclusterCloudProviderUserName,
+				},
+			},
+		},
+	)
+}
+
+// EnsureMulticlusterCloudProviderKubeconfigSecret publishes the kubeconfig of the multicluster-cloud-provider,
+// which accesses the karmada-apiserver through its in-cluster service. The client certificate is reused until
+// it's going to expire or the CA is rotated.
+func (ctrl *KarmadaController) EnsureMulticlusterCloudProviderKubeconfigSecret(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	certSecret, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, "karmada-cert", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	caCert, caKey, err := parseCA(certSecret)
+	if err != nil {
+		return err
+	}
+
+	secretName := multiclusterCloudProviderKubeconfigSecretName
+	certData, keyData := ctrl.reusableClientCert(ctx, karmada.Namespace, secretName, multiclusterCloudProviderUserName, caCert)
+	if certData == nil {
+		certData, keyData, err = newClientCert(caCert, caKey, multiclusterCloudProviderUserName)
+		if err != nil {
+			return err
+		}
+		ctrl.eventRecorder.Event(karmada, corev1.EventTypeNormal, "CertificateRotated", "Issued a new client certificate for the multicluster-cloud-provider")
+	}
+
+	server := fmt.Sprintf("https://%s.%s.svc.%s:%v", constants.KarmadaComponentKubeAPIServer, karmada.Namespace, karmada.Spec.Networking.DNSDomain, 5443)
+	config := certs.CreateWithCerts(server, multiclusterCloudProviderUserName, "karmada", certSecret.Data["ca.crt"], keyData, certData)
+	configBytes, err := clientcmd.Write(*config)
+	if err != nil {
+		return fmt.Errorf("failure while serializing multicluster-cloud-provider kubeconfig. %v", err)
+	}
+	secret := SecretFromSpec(karmada.Namespace, secretName, corev1.SecretTypeOpaque, map[string]string{"kubeconfig": string(configBytes)})
+	util.SetKarmadaInstanceLabel(secret, karmada.Name)
+	controllerutil.SetOwnerReference(karmada, secret, scheme.Scheme)
+	result, err := clientutil.CreateOrUpdateSecret(ctx, ctrl.client, secret)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, secret, result)
+	return err
+}
+
+func (ctrl *KarmadaController) EnsureMulticlusterCloudProviderDeployment(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	deployment, err := multiclusterCloudProviderDeployment(karmada)
+	if err != nil {
+		return err
+	}
+	return ctrl.ensureDeployment(ctx, karmada, deployment, nil)
+}
+
+// multiclusterCloudProviderDeployment returns the multicluster-cloud-provider deployment of the karmada.
+func multiclusterCloudProviderDeployment(karmada *installv1alpha1.Karmada) (*appsv1.Deployment, error) {
+	componentName := constants.KarmadaComponentMulticlusterCloudProvider
+	provider := karmada.Spec.ControllerManager.MulticlusterCloudProvider
+	providerName := provider.Provider
+	if providerName == "" {
+		providerName = "fake"
+	}
+	binary := "multicluster-provider-" + providerName
+
+	// the image is released separately from karmada, see the ImageMeta of the component.
+	repository := "docker.io/karmada"
+	if provider.ImageRepository != "" {
+		repository = provider.ImageRepository
+	}
+
+	imageName := binary
+	if provider.ImageName != "" {
+		imageName = provider.ImageName
+	}
+
+	tag := "latest"
+	if provider.ImageTag != "" {
+		tag = provider.ImageTag
+	}
+
+	ingressClass := provider.IngressClass
+	if ingressClass == "" {
+		ingressClass = "karmada.io/" + providerName
+	}
+
+	defaultArgs := map[string]string{
+		"kubeconfig":             "/etc/kubeconfig",
+		"bind-address":           "0.0.0.0",
+		"secure-port":            fmt.Sprint(multiclusterCloudProviderPort),
+		"multicluster-provider":  providerName,
+		"provider-ingress-class": ingressClass,
+		"v":                      "4",
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "kubeconfig",
+			MountPath: "/etc/kubeconfig",
+			SubPath:   "kubeconfig",
+		},
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: "kubeconfig",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: multiclusterCloudProviderKubeconfigSecretName,
+				},
+			},
+		},
+	}
+	if cloudConfig := provider.CloudConfig; cloudConfig != nil {
+		configPath := path.Join("/etc/multicluster-provider", cloudConfig.Key)
+		defaultArgs["cloud-config"] = configPath
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "cloud-config",
+			MountPath: configPath,
+			SubPath:   cloudConfig.Key,
+			ReadOnly:  true,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "cloud-config",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: cloudConfig.Name,
+					Optional:   cloudConfig.Optional,
+				},
+			},
+		})
+	}
+	computedArgs := maputil.MergeStringMaps(defaultArgs, util.LoggingArgs(provider.Logging), provider.ExtraArgs)
+	args := maputil.ConvertToCommandOrArgs(computedArgs)
+
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      componentName,
+			Namespace: karmada.Namespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": componentName},
+			},
+			Replicas: provider.Replicas,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": componentName},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:            componentName,
+							Image:           util.ComponentImageName(repository, imageName, tag),
+							ImagePullPolicy: "IfNotPresent",
+							Command:         []string{path.Join("/bin", binary)},
+							Args:            args,
+							Resources:       provider.Resources,
+							LivenessProbe: &corev1.Probe{
+								FailureThreshold: 3,
+								ProbeHandler: corev1.ProbeHandler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path: "/healthz",
+										Port: intstr.IntOrString{
+											Type:   intstr.Int,
+											IntVal: multiclusterCloudProviderPort,
+										},
+										Scheme: corev1.URISchemeHTTP,
+									},
+								},
+								InitialDelaySeconds: 15,
+								PeriodSeconds:       15,
+								TimeoutSeconds:      5,
+							},
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+
+	util.ApplyComponentExtras(&deployment.Spec.Template.Spec, provider.ComponentExtras)
+	util.SetKarmadaInstanceLabel(deployment, karmada.Name)
+
+	controllerutil.SetOwnerReference(karmada, deployment, scheme.Scheme)
+	if err := patchutil.Apply(deployment, karmada.Spec.Patches); err != nil {
+		return nil, err
+	}
+	return deployment, nil
+}
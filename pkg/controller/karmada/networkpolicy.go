@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/networkpolicy"
+	patchutil "github.com/carlory/firefly/pkg/util/patch"
+)
+
+// appLabels returns the labels of the pods of the components.
+func appLabels(components ...string) []map[string]string {
+	labels := make([]map[string]string, 0, len(components))
+	for _, component := range components {
+		labels = append(labels, map[string]string{"app": component})
+	}
+	return labels
+}
+
+// networkPolicies returns the NetworkPolicies of the karmada, which restrict the traffic to etcd
+// to the apiservers storing data in it and its peers, and the traffic to the scheduler estimators
+// to the schedulers and the health checks of the firefly-karmada-manager. The estimators are selected by their component label, since each member
+// cluster has its own.
+func networkPolicies(karmada *installv1alpha1.Karmada) ([]*networkingv1.NetworkPolicy, error) {
+	etcdRules := []networkpolicy.Rule{
+		{
+			Ports: []int32{2379},
+			From: appLabels(
+				constants.KarmadaComponentKubeAPIServer,
+				constants.KarmadaComponentAggregratedAPIServer,
+				constants.KarmadaComponentSearch,
+			),
+		},
+		{
+			Ports: []int32{2380},
+			From:  appLabels(constants.KarmadaComponentEtcd),
+		},
+	}
+	if etcdDistributed(karmada) {
+		// the apiservers and the etcd members in the additional host clusters come through the etcd-external Service.
+		etcdRules = append(etcdRules, networkpolicy.Rule{Ports: []int32{2379, 2380}})
+	}
+	if monitored(karmada, constants.KarmadaComponentEtcd) {
+		// the metrics are scraped by Prometheus, which runs outside of the namespace.
+		etcdRules = append(etcdRules, networkpolicy.Rule{Ports: []int32{etcdMetricsPort}})
+	}
+
+	policies := []*networkingv1.NetworkPolicy{
+		networkpolicy.NetworkPolicy(karmada.Namespace, constants.KarmadaComponentEtcd,
+			map[string]string{"app": constants.KarmadaComponentEtcd}, etcdRules...),
+		networkpolicy.NetworkPolicy(karmada.Namespace, constants.KarmadaComponentSchedulerEstimator,
+			map[string]string{constants.ComponentLabel: constants.ComponentEstimator},
+			networkpolicy.Rule{From: appLabels(constants.KarmadaComponentScheduler, constants.KarmadaComponentDescheduler)},
+			// the health endpoints and the metrics are scraped by the health checks of the estimators.
+			networkpolicy.Rule{Ports: []int32{estimatorHealthPort}, From: appLabels(constants.FireflyComponentKarmadaManager)}),
+	}
+	for _, policy := range policies {
+		util.SetKarmadaInstanceLabel(policy, karmada.Name)
+		controllerutil.SetOwnerReference(karmada, policy, scheme.Scheme)
+		if err := patchutil.Apply(policy, karmada.Spec.Patches); err != nil {
+			return nil, err
+		}
+	}
+	return policies, nil
+}
+
+// EnsureNetworkPolicies creates or updates the NetworkPolicies of the karmada if they're enabled,
+// otherwise deletes them.
+func (ctrl *KarmadaController) EnsureNetworkPolicies(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if !karmada.Spec.NetworkPolicy.Enabled {
+		return ctrl.RemoveNetworkPolicies(ctx, karmada)
+	}
+
+	policies, err := networkPolicies(karmada)
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		result, err := clientutil.CreateOrUpdateNetworkPolicy(ctx, ctrl.client, policy)
+		clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, policy, result)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveNetworkPolicies deletes the NetworkPolicies of the karmada, if any.
+func (ctrl *KarmadaController) RemoveNetworkPolicies(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	for _, name := range []string{constants.KarmadaComponentEtcd, constants.KarmadaComponentSchedulerEstimator} {
+		err := ctrl.client.NetworkingV1().NetworkPolicies(karmada.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "NetworkPolicy", Namespace: karmada.Namespace, Name: name}, err)
+		if err := client.IgnoreNotFound(err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+)
+
+// pausedStatusInterval is the interval at which the status of a paused karmada is collected.
+const pausedStatusInterval = 30 * time.Second
+
+// syncPausedKarmada collects the status of a paused karmada without changing any of its resources: the
+// host capabilities, the apiservers in the additional host clusters, and the readiness of the components,
+// which is told by the message of the Paused condition. The phase is summarized from the status on update.
+func (ctrl *KarmadaController) syncPausedKarmada(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	var errs []error
+	if err := ctrl.EnsureHostCapabilities(ctx, karmada); err != nil {
+		errs = append(errs, err)
+	}
+	if err := ctrl.observeHostClusters(ctx, karmada); err != nil {
+		errs = append(errs, err)
+	}
+
+	message := "the reconciliation of the karmada is paused"
+	notReady, err := ctrl.notReadyComponents(ctx, karmada)
+	if err != nil {
+		errs = append(errs, err)
+	} else if len(notReady) > 0 {
+		message += fmt.Sprintf(", the components not ready: %s", strings.Join(notReady, ", "))
+	}
+	if err := ctrl.updateKarmadaCondition(ctx, karmada, installv1alpha1.KarmadaConditionPaused, &metav1.Condition{
+		Status:  metav1.ConditionTrue,
+		Reason:  "Paused",
+		Message: message,
+	}); err != nil {
+		errs = append(errs, err)
+	}
+	// none of the resources the status is collected from is watched.
+	ctrl.enqueueAfter(karmada, pausedStatusInterval)
+	return utilerrors.NewAggregate(errs)
+}
+
+// notReadyComponents returns the Deployments and the StatefulSets of the karmada in the host cluster whose
+// replicas aren't all ready, with their ready and desired replicas, e.g. karmada-scheduler (1/2).
+func (ctrl *KarmadaController) notReadyComponents(ctx context.Context, karmada *installv1alpha1.Karmada) ([]string, error) {
+	opts := metav1.ListOptions{LabelSelector: labels.SelectorFromSet(labels.Set{constants.KarmadaInstanceLabel: karmada.Name}).String()}
+	var notReady []string
+	deployments, err := ctrl.client.AppsV1().Deployments(karmada.Namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, deployment := range deployments.Items {
+		desired := pointer.Int32Deref(deployment.Spec.Replicas, 1)
+		if deployment.Status.ReadyReplicas < desired {
+			notReady = append(notReady, fmt.Sprintf("%s (%d/%d)", deployment.Name, deployment.Status.ReadyReplicas, desired))
+		}
+	}
+	statefulSets, err := ctrl.client.AppsV1().StatefulSets(karmada.Namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, sts := range statefulSets.Items {
+		desired := pointer.Int32Deref(sts.Spec.Replicas, 1)
+		if sts.Status.ReadyReplicas < desired {
+			notReady = append(notReady, fmt.Sprintf("%s (%d/%d)", sts.Name, sts.Status.ReadyReplicas, desired))
+		}
+	}
+	sort.Strings(notReady)
+	return notReady, nil
+}
+
+// observeHostClusters refreshes the ready replicas and the load balancer addresses of the apiservers in the
+// host clusters recorded in the status, without reconciling them. The host clusters which can't be reached
+// are told by their messages.
+func (ctrl *KarmadaController) observeHostClusters(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if len(karmada.Status.HostClusters) == 0 {
+		return nil
+	}
+	statuses := make([]installv1alpha1.KarmadaHostClusterStatus, 0, len(karmada.Status.HostClusters))
+	for _, status := range karmada.Status.HostClusters {
+		status.Message = ""
+		if err := ctrl.observeHostCluster(ctx, karmada, &status); err != nil {
+			status.Message = err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	return ctrl.updateKarmadaStatus(ctx, karmada, func(status *installv1alpha1.KarmadaStatus) {
+		status.HostClusters = statuses
+	})
+}
+
+func (ctrl *KarmadaController) observeHostCluster(ctx context.Context, karmada *installv1alpha1.Karmada, status *installv1alpha1.KarmadaHostClusterStatus) error {
+	hostClient, err := ctrl.hostClusterClient(ctx, karmada, status.KubeconfigSecretName)
+	if err != nil {
+		return err
+	}
+	if karmada.Spec.APIServer.ServiceType == corev1.ServiceTypeLoadBalancer {
+		svc, err := hostClient.CoreV1().Services(karmada.Namespace).Get(ctx, constants.KarmadaComponentKubeAPIServer, metav1.GetOptions{})
+		if client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		if err == nil {
+			status.Endpoints = loadBalancerAddresses(svc)
+		}
+	}
+	apiserver, err := hostClient.AppsV1().Deployments(karmada.Namespace).Get(ctx, constants.KarmadaComponentKubeAPIServer, metav1.GetOptions{})
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	status.ReadyReplicas = apiserver.Status.ReadyReplicas
+	return nil
+}
@@ -0,0 +1,336 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+)
+
+// canaryCheckInterval is the interval at which a karmada is requeued while the canary replicas of
+// one of its components are becoming available.
+const canaryCheckInterval = 10 * time.Second
+
+// rolloutDeployment prepares the deployment of a component to be rolled out according to
+// spec.updateStrategy. It returns false if the deployment must not be updated yet, that is the
+// rollout is paused or the canary replicas of the component are not available yet.
+func (ctrl *KarmadaController) rolloutDeployment(ctx context.Context, karmada *installv1alpha1.Karmada, deployment *appsv1.Deployment) (bool, error) {
+	rollout, err := ctrl.prepareRollout(ctx, karmada, deployment)
+	if err == nil && !rollout {
+		holdRollout(ctx)
+	}
+	return rollout, err
+}
+
+func (ctrl *KarmadaController) prepareRollout(ctx context.Context, karmada *installv1alpha1.Karmada, deployment *appsv1.Deployment) (bool, error) {
+	strategy := karmada.Spec.UpdateStrategy
+	if maxUnavailable := strategy.MaxUnavailable; maxUnavailable != nil && deployment.Spec.Strategy.Type != appsv1.RecreateDeploymentStrategyType {
+		if deployment.Spec.Strategy.RollingUpdate == nil {
+			deployment.Spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{}
+		}
+		if deployment.Spec.Strategy.RollingUpdate.MaxUnavailable == nil {
+			value := *maxUnavailable
+			deployment.Spec.Strategy.RollingUpdate.MaxUnavailable = &value
+		}
+	}
+
+	template, err := json.Marshal(deployment.Spec.Template)
+	if err != nil {
+		return false, err
+	}
+	hash := contentHash(string(template))
+	metav1.SetMetaDataAnnotation(&deployment.ObjectMeta, constants.PodTemplateHashAnnotation, hash)
+
+	got, err := ctrl.client.AppsV1().Deployments(deployment.Namespace).Get(ctx, deployment.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if equality.Semantic.DeepEqual(got.Spec.Selector, includeCanaryPods(deployment.Spec.Selector)) {
+		// the deployment was created before its selector excluded the canary pods. The selector is
+		// immutable, so it's kept rather than replacing the deployment. Its replica sets select the
+		// pods by their pod-template-hash label as well, so the canary pods are not adopted anyway.
+		deployment.Spec.Selector = got.Spec.Selector
+	}
+	if current := got.Annotations[constants.PodTemplateHashAnnotation]; current == "" || current == hash {
+		// there is no change to roll out, the canary of a reverted or completed change is removed.
+		return true, ctrl.removeCanary(ctx, karmada, deployment.Name)
+	}
+
+	rollout := rolloutStatus(ctx, karmada)
+	if rollout != nil && rollout.Paused && rollout.Component != deployment.Name {
+		klog.V(2).InfoS("Rollout is paused, holding the changes of the component", "karmada", klog.KObj(karmada), "component", deployment.Name, "pausedBy", rollout.Component)
+		return false, nil
+	}
+
+	if strategy.Canary != nil {
+		available, err := ctrl.ensureCanary(ctx, karmada, deployment, hash)
+		if err != nil || !available {
+			return false, err
+		}
+	}
+
+	for _, component := range strategy.PauseAfter {
+		if component == deployment.Name {
+			return true, ctrl.pauseRollout(ctx, karmada, deployment.Name, installv1alpha1.RolloutPausedAfterComponent,
+				fmt.Sprintf("the rollout is paused after the update of %s, change the %s annotation to resume it", deployment.Name, constants.ResumeRolloutAnnotation))
+		}
+	}
+	return true, nil
+}
+
+// ensureCanary rolls out the change of the deployment to its canary replicas, and returns true once they
+// are available. The rollout is paused if they don't become available in time.
+func (ctrl *KarmadaController) ensureCanary(ctx context.Context, karmada *installv1alpha1.Karmada, deployment *appsv1.Deployment, hash string) (bool, error) {
+	canary := canaryDeployment(deployment, karmada.Spec.UpdateStrategy.Canary)
+	// the canary is recorded before it's created, so that it's removed even if the creation is lost.
+	if err := ctrl.updateKarmadaStatus(ctx, karmada, func(status *installv1alpha1.KarmadaStatus) {
+		if status.Rollout == nil {
+			status.Rollout = &installv1alpha1.RolloutStatus{}
+		}
+		if !sets.NewString(status.Rollout.Canaries...).Has(deployment.Name) {
+			status.Rollout.Canaries = append(status.Rollout.Canaries, deployment.Name)
+		}
+	}); err != nil {
+		return false, err
+	}
+	result, err := clientutil.CreateOrUpdateDeployment(ctx, ctrl.client, canary)
+	clientutil.RecordOperationResult(ctx, ctrl.eventRecorder, karmada, canary, result)
+	if err != nil {
+		return false, err
+	}
+	if err := ctrl.EnsurePodDisruptionBudget(ctx, karmada, canary.Name, canary.Labels, canary.Spec.Selector, *canary.Spec.Replicas); err != nil {
+		return false, err
+	}
+
+	got, err := ctrl.client.AppsV1().Deployments(canary.Namespace).Get(ctx, canary.Name, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	if rollout := rolloutStatus(ctx, karmada); rollout != nil && rollout.Paused && rollout.Component == deployment.Name &&
+		rollout.Reason == installv1alpha1.RolloutCanaryFailed && result != clientutil.OperationResultNone {
+		// the failed change is superseded by another one, whose canary is tried afresh.
+		if err := ctrl.resumeRollout(ctx, karmada, ""); err != nil {
+			return false, err
+		}
+	}
+
+	replicas := *canary.Spec.Replicas
+	observed := got.Generation <= got.Status.ObservedGeneration
+	switch {
+	case observed && got.Status.Replicas == replicas && got.Status.UpdatedReplicas == replicas && got.Status.AvailableReplicas >= replicas:
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "CanaryAvailable", "The canary replicas of %s are available, updating it", deployment.Name)
+		return true, nil
+	case observed && progressDeadlineExceeded(got):
+		if err := ctrl.pauseRollout(ctx, karmada, deployment.Name, installv1alpha1.RolloutCanaryFailed,
+			fmt.Sprintf("the canary replicas of %s didn't become available in time, fix the change or change the %s annotation to retry it", deployment.Name, constants.ResumeRolloutAnnotation)); err != nil {
+			return false, err
+		}
+		return false, nil
+	default:
+		ctrl.enqueueAfter(karmada, canaryCheckInterval)
+		return false, nil
+	}
+}
+
+// canaryDeployment returns the canary deployment of the deployment of a component. Its pods are labeled
+// as the ones of the component, so that they serve its traffic as well, and are told apart by the canary
+// label, which the selector of the deployment of the component excludes.
+func canaryDeployment(deployment *appsv1.Deployment, strategy *installv1alpha1.CanaryStrategy) *appsv1.Deployment {
+	canary := deployment.DeepCopy()
+	canary.Name = canaryName(deployment.Name)
+	canary.ResourceVersion = ""
+	delete(canary.Annotations, constants.DeploymentSpecHashAnnotation)
+	metav1.SetMetaDataLabel(&canary.ObjectMeta, constants.CanaryLabel, "true")
+	metav1.SetMetaDataLabel(&canary.Spec.Template.ObjectMeta, constants.CanaryLabel, "true")
+	selector := includeCanaryPods(canary.Spec.Selector)
+	if selector.MatchLabels == nil {
+		selector.MatchLabels = map[string]string{}
+	}
+	selector.MatchLabels[constants.CanaryLabel] = "true"
+	canary.Spec.Selector = selector
+	canary.Spec.Replicas = strategy.Replicas
+	canary.Spec.ProgressDeadlineSeconds = strategy.ProgressDeadlineSeconds
+	return canary
+}
+
+func canaryName(component string) string {
+	return component + "-canary"
+}
+
+// excludeCanaryPods returns a copy of the selector of the deployment of a component which doesn't select
+// the pods of its canary.
+func excludeCanaryPods(selector *metav1.LabelSelector) *metav1.LabelSelector {
+	selector = includeCanaryPods(selector)
+	selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
+		Key:      constants.CanaryLabel,
+		Operator: metav1.LabelSelectorOpDoesNotExist,
+	})
+	return selector
+}
+
+// includeCanaryPods returns a copy of the selector without the requirements on the canary label.
+func includeCanaryPods(selector *metav1.LabelSelector) *metav1.LabelSelector {
+	selector = selector.DeepCopy()
+	var requirements []metav1.LabelSelectorRequirement
+	for _, requirement := range selector.MatchExpressions {
+		if requirement.Key != constants.CanaryLabel {
+			requirements = append(requirements, requirement)
+		}
+	}
+	selector.MatchExpressions = requirements
+	return selector
+}
+
+// removeCanary deletes the canary deployment of the component along with its PodDisruptionBudget, if it's
+// recorded in status.rollout.canaries, and drops it from there. Nothing is called otherwise, since it's
+// called for every component on every reconcile.
+func (ctrl *KarmadaController) removeCanary(ctx context.Context, karmada *installv1alpha1.Karmada, component string) error {
+	if karmada.Status.Rollout == nil || !sets.NewString(karmada.Status.Rollout.Canaries...).Has(component) {
+		return nil
+	}
+	name := canaryName(component)
+	err := ctrl.client.AppsV1().Deployments(karmada.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Deployment", Namespace: karmada.Namespace, Name: name}, err)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	if err := ctrl.RemovePodDisruptionBudget(ctx, karmada, name); err != nil {
+		return err
+	}
+	return ctrl.updateKarmadaStatus(ctx, karmada, func(status *installv1alpha1.KarmadaStatus) {
+		if status.Rollout != nil {
+			status.Rollout.Canaries = sets.NewString(status.Rollout.Canaries...).Delete(component).List()
+		}
+	})
+}
+
+// progressDeadlineExceeded returns true if the deployment controller reports the deployment failed to progress.
+func progressDeadlineExceeded(deployment *appsv1.Deployment) bool {
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing {
+			return condition.Status == corev1.ConditionFalse && condition.Reason == "ProgressDeadlineExceeded"
+		}
+	}
+	return false
+}
+
+// rolloutStatus returns the rollout status of the karmada as of the start of its concurrent reconciliation,
+// so that all of its components are held or rolled out alike, no matter the order in which they pause
+// the rollout.
+func rolloutStatus(ctx context.Context, karmada *installv1alpha1.Karmada) *installv1alpha1.RolloutStatus {
+	if shared, ok := ctx.Value(sharedKarmadaKey{}).(*sharedKarmada); ok {
+		return shared.rollout
+	}
+	return karmada.Status.Rollout
+}
+
+// pauseRollout pauses the rollout of the karmada because of the component, unless it's paused already.
+func (ctrl *KarmadaController) pauseRollout(ctx context.Context, karmada *installv1alpha1.Karmada, component, reason, message string) error {
+	paused := false
+	err := ctrl.updateKarmadaStatus(ctx, karmada, func(status *installv1alpha1.KarmadaStatus) {
+		if status.Rollout != nil && status.Rollout.Paused {
+			return
+		}
+		if status.Rollout == nil {
+			status.Rollout = &installv1alpha1.RolloutStatus{}
+		}
+		now := metav1.Now()
+		status.Rollout.Paused = true
+		status.Rollout.Component = component
+		status.Rollout.Reason = reason
+		status.Rollout.Message = message
+		status.Rollout.LastTransitionTime = &now
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               installv1alpha1.KarmadaConditionRolloutPaused,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: karmada.Generation,
+			Reason:             reason,
+			Message:            message,
+		})
+		paused = true
+	})
+	if err == nil && paused {
+		eventType := corev1.EventTypeNormal
+		if reason == installv1alpha1.RolloutCanaryFailed {
+			eventType = corev1.EventTypeWarning
+		}
+		ctrl.eventRecorder.Eventf(karmada, eventType, reason, "Paused the rollout: %s", message)
+	}
+	return err
+}
+
+// resumeRollout resumes the paused rollout of the karmada. The trigger is the value of the
+// resume annotation, it's empty if the rollout is resumed by the controller itself.
+func (ctrl *KarmadaController) resumeRollout(ctx context.Context, karmada *installv1alpha1.Karmada, trigger string) error {
+	return ctrl.updateKarmadaStatus(ctx, karmada, func(status *installv1alpha1.KarmadaStatus) {
+		if status.Rollout == nil {
+			status.Rollout = &installv1alpha1.RolloutStatus{}
+		}
+		if trigger != "" {
+			status.Rollout.ResumedBy = trigger
+		}
+		if !status.Rollout.Paused {
+			return
+		}
+		now := metav1.Now()
+		status.Rollout.Paused = false
+		status.Rollout.Component = ""
+		status.Rollout.Reason = ""
+		status.Rollout.Message = ""
+		status.Rollout.LastTransitionTime = &now
+		meta.RemoveStatusCondition(&status.Conditions, installv1alpha1.KarmadaConditionRolloutPaused)
+	})
+}
+
+// EnsureRolloutResumed resumes the paused rollout of the karmada if the resume annotation is changed.
+// The canary of the component which failed is removed, so that it's tried afresh.
+func (ctrl *KarmadaController) EnsureRolloutResumed(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	trigger := karmada.Annotations[constants.ResumeRolloutAnnotation]
+	rollout := karmada.Status.Rollout
+	if trigger == "" || (rollout != nil && rollout.ResumedBy == trigger) {
+		return nil
+	}
+	if rollout != nil && rollout.Paused {
+		if rollout.Reason == installv1alpha1.RolloutCanaryFailed {
+			if err := ctrl.removeCanary(ctx, karmada, rollout.Component); err != nil {
+				return err
+			}
+		}
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "RolloutResumed", "Resumed the rollout paused by %s", rollout.Component)
+	}
+	return ctrl.resumeRollout(ctx, karmada, trigger)
+}
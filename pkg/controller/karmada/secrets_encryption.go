@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/kms"
+)
+
+// encryptedSecretKeys returns the keys of the secrets generated for a karmada, by the names of the secrets,
+// which are encrypted by the KMS if secrets encryption is configured. They're the keys which only firefly
+// reads: the private keys of the CAs which only firefly signs certificates with, and the published admin
+// and read-only kubeconfigs, which fireflyctl get-kubeconfig decrypts for users. The keys mounted into the
+// components are kept in plain text since the components can't reach the KMS, that is the private key of the
+// karmada CA which the kube-controller-manager signs certificates with, the certificates and the private keys
+// of the components, e.g. the etcd client key, and the karmada-kubeconfig Secret.
+func encryptedSecretKeys(karmada *installv1alpha1.Karmada) map[string][]string {
+	return map[string][]string{
+		"karmada-cert": {"etcd-ca.key", "front-proxy-ca.key"},
+		fmt.Sprintf("%s-cert", constants.KarmadaComponentEtcd): {"etcd-ca.key"},
+		AdminKubeconfigSecretName(karmada):                     {"kubeconfig"},
+		ReadOnlyKubeconfigSecretName(karmada):                  {"kubeconfig"},
+	}
+}
+
+// encryptSecret encrypts the keys of the secret which are encrypted by the KMS, it's called before the
+// secret is created so that the keys are never stored in plain text.
+func encryptSecret(ctx context.Context, karmada *installv1alpha1.Karmada, secret *corev1.Secret) error {
+	_, err := kms.EncryptSecretData(ctx, secret, encryptedSecretKeys(karmada)[secret.Name]...)
+	return err
+}
+
+// createOrUpdateEncryptedSecret creates or updates the secret whose keys are encrypted by the KMS. A value is
+// encrypted with a new data encryption key every time, so the stored values which decrypt to the desired ones
+// are kept rather than updating the secret on every reconcile.
+func (ctrl *KarmadaController) createOrUpdateEncryptedSecret(ctx context.Context, karmada *installv1alpha1.Karmada, secret *corev1.Secret) (clientutil.OperationResult, error) {
+	current, err := ctrl.client.CoreV1().Secrets(secret.Namespace).Get(ctx, secret.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		current = nil
+	} else if err != nil {
+		return clientutil.OperationResultNone, err
+	}
+	for _, key := range encryptedSecretKeys(karmada)[secret.Name] {
+		desired, ok := secret.StringData[key]
+		if !ok || current == nil {
+			continue
+		}
+		if stored, err := kms.SecretData(ctx, current, key); err == nil && string(stored) == desired {
+			delete(secret.StringData, key)
+			if secret.Data == nil {
+				secret.Data = map[string][]byte{}
+			}
+			secret.Data[key] = current.Data[key]
+		}
+	}
+	if err := encryptSecret(ctx, karmada, secret); err != nil {
+		return clientutil.OperationResultNone, err
+	}
+	return clientutil.CreateOrUpdateSecret(ctx, ctrl.client, secret)
+}
+
+// EnsureSecretsEncrypted encrypts the keys of the secrets of the karmada which are encrypted by the KMS,
+// if they were stored in plain text before secrets encryption was configured.
+func (ctrl *KarmadaController) EnsureSecretsEncrypted(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if kms.Default() == nil {
+		return nil
+	}
+	for name, keys := range encryptedSecretKeys(karmada) {
+		secret, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			// the kubeconfigs are published later, encrypted.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		encrypted, err := kms.EncryptSecretData(ctx, secret, keys...)
+		if err != nil {
+			return err
+		}
+		if !encrypted {
+			continue
+		}
+		_, err = ctrl.client.CoreV1().Secrets(karmada.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+		audit.RecordResult(ctx, audit.Update, secret, err)
+		if err != nil {
+			return err
+		}
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "SecretEncrypted", "Encrypted %v of secret %s with the KMS", keys, name)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/util/apply"
+)
+
+const (
+	// SeedHashLabel is the label of the seeded namespaces whose value is the hash of their seed. The
+	// namespace is labeled last, so that its label tells the seed which has been applied.
+	SeedHashLabel = "install.firefly.io/seed-hash"
+
+	// seedObjectName is the name of the ResourceQuota, the LimitRange and the PropagationPolicy of a
+	// seeded namespace.
+	seedObjectName = "firefly-seed"
+
+	// seedFieldManager is the field manager of the seeded objects.
+	seedFieldManager = "firefly-karmada-seed"
+)
+
+// EnsureSeed applies the namespaces of spec.seed, along with their ResourceQuotas, LimitRanges and
+// PropagationPolicies, into the karmada-apiserver. A namespace is only applied when its seed is
+// changed, see SeedHashLabel, so that the objects changed by the tenants aren't reverted on every
+// reconciliation.
+func (ctrl *KarmadaController) EnsureSeed(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if karmada.Spec.Seed == nil || len(karmada.Spec.Seed.Namespaces) == 0 {
+		return nil
+	}
+
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	client, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	var applier *apply.Applier
+	for i := range karmada.Spec.Seed.Namespaces {
+		seed := &karmada.Spec.Seed.Namespaces[i]
+		hash, err := seedHash(seed)
+		if err != nil {
+			return err
+		}
+		ns, err := client.CoreV1().Namespaces().Get(ctx, seed.Name, metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err == nil && ns.Labels[SeedHashLabel] == hash {
+			continue
+		}
+
+		if applier == nil {
+			// the applier discovers the resources of the karmada-apiserver, which is only worth it
+			// if a namespace is to be applied.
+			if applier, err = apply.NewApplier(clientConfig, seedFieldManager); err != nil {
+				return err
+			}
+		}
+		if err := applySeedNamespace(ctx, applier, seed, hash); err != nil {
+			return fmt.Errorf("failed to seed namespace %s: %v", seed.Name, err)
+		}
+		klog.V(2).InfoS("Seeded namespace into karmada", "karmada", klog.KObj(karmada), "namespace", seed.Name)
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "Seeded", "Seeded namespace %s into the karmada", seed.Name)
+	}
+	return nil
+}
+
+// seedHash returns the hash of the seed of a namespace.
+func seedHash(seed *installv1alpha1.KarmadaSeedNamespace) (string, error) {
+	content, err := json.Marshal(seed)
+	if err != nil {
+		return "", err
+	}
+	return contentHash(string(content)), nil
+}
+
+// applySeedNamespace applies the objects of the seed of a namespace, and removes the ones which are
+// not seeded anymore. The namespace is applied first so that its objects can be created, and labeled
+// with the hash last.
+func applySeedNamespace(ctx context.Context, applier *apply.Applier, seed *installv1alpha1.KarmadaSeedNamespace, hash string) error {
+	namespace := &corev1.Namespace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{Name: seed.Name, Labels: map[string]string{}},
+	}
+	for k, v := range seed.Labels {
+		namespace.Labels[k] = v
+	}
+	objs, err := unstructuredObjects(namespace)
+	if err != nil {
+		return err
+	}
+	if _, err := applier.Apply(ctx, objs[0]); err != nil {
+		return err
+	}
+
+	var applied, removed []runtime.Object
+	quota := &corev1.ResourceQuota{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ResourceQuota"},
+		ObjectMeta: metav1.ObjectMeta{Name: seedObjectName, Namespace: seed.Name},
+	}
+	if seed.ResourceQuota != nil {
+		quota.Spec = *seed.ResourceQuota
+		applied = append(applied, quota)
+	} else {
+		removed = append(removed, quota)
+	}
+
+	limitRange := &corev1.LimitRange{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "LimitRange"},
+		ObjectMeta: metav1.ObjectMeta{Name: seedObjectName, Namespace: seed.Name},
+	}
+	if seed.LimitRange != nil {
+		limitRange.Spec = *seed.LimitRange
+		applied = append(applied, limitRange)
+	} else {
+		removed = append(removed, limitRange)
+	}
+
+	policy := &policyv1alpha1.PropagationPolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: policyv1alpha1.SchemeGroupVersion.String(), Kind: "PropagationPolicy"},
+		ObjectMeta: metav1.ObjectMeta{Name: seedObjectName, Namespace: seed.Name},
+	}
+	if seed.PropagationPolicy != nil {
+		policy.Spec = seedPropagationSpec(seed.Name, seed.PropagationPolicy)
+		applied = append(applied, policy)
+	} else {
+		removed = append(removed, policy)
+	}
+
+	if objs, err = unstructuredObjects(applied...); err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		if _, err := applier.Apply(ctx, obj); err != nil {
+			return err
+		}
+	}
+	if objs, err = unstructuredObjects(removed...); err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		if err := applier.Delete(ctx, obj); err != nil {
+			return err
+		}
+	}
+
+	namespace.Labels[SeedHashLabel] = hash
+	if objs, err = unstructuredObjects(namespace); err != nil {
+		return err
+	}
+	_, err = applier.Apply(ctx, objs[0])
+	return err
+}
+
+// seedPropagationSpec returns the spec of the default PropagationPolicy of a seeded namespace.
+func seedPropagationSpec(namespace string, policy *installv1alpha1.KarmadaSeedPropagationPolicy) policyv1alpha1.PropagationSpec {
+	spec := policyv1alpha1.PropagationSpec{
+		PropagateDeps: policy.PropagateDeps,
+	}
+	for _, selector := range policy.ResourceSelectors {
+		spec.ResourceSelectors = append(spec.ResourceSelectors, policyv1alpha1.ResourceSelector{
+			APIVersion: selector.APIVersion,
+			Kind:       selector.Kind,
+			Namespace:  namespace,
+		})
+	}
+	if len(policy.Clusters) > 0 {
+		spec.Placement.ClusterAffinity = &policyv1alpha1.ClusterAffinity{
+			ClusterNames: policy.Clusters,
+		}
+	}
+	return spec
+}
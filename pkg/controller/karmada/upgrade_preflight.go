@@ -0,0 +1,336 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+)
+
+const (
+	// upgradePreflightRetryInterval is the interval the failed upgrade preflight checks are run again at.
+	upgradePreflightRetryInterval = time.Minute
+
+	// etcdSpaceThreshold is the max ratio of the size of the etcd database to its quota an upgrade is
+	// allowed at, since the upgrade rewrites the CRDs and the objects migrated by the components.
+	etcdSpaceThreshold = 0.8
+)
+
+// etcdDBSizeMetrics are the metrics of the karmada-apiserver reporting the size of the etcd database,
+// the first one replaces the second one since kubernetes 1.28.
+var etcdDBSizeMetrics = []string{"apiserver_storage_size_bytes", "apiserver_storage_db_total_size_in_bytes"}
+
+// crdsGVR is the resource of the CRDs in the karmada-apiserver.
+var crdsGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// EnsureUpgradePreflight runs the preflight checks before the karmada is upgraded from status.karmadaVersion
+// to spec.karmadaVersion, and returns whether the upgrade may go on. A refused upgrade is checked again
+// periodically, or forced by the firefly.io/force-upgrade annotation.
+//
+// The checks aren't run again once they passed for the upgrade, since the upgrade itself restarts the
+// components they probe.
+func (ctrl *KarmadaController) EnsureUpgradePreflight(ctx context.Context, karmada *installv1alpha1.Karmada) (bool, error) {
+	from, to := karmada.Status.KarmadaVersion, karmada.Spec.KarmadaVersion
+	if from == "" || from == to {
+		return true, nil
+	}
+	if last := karmada.Status.UpgradePreflight; last != nil && last.Passed && last.FromVersion == from && last.ToVersion == to {
+		return true, nil
+	}
+
+	preflight := &installv1alpha1.UpgradePreflightStatus{
+		FromVersion: from,
+		ToVersion:   to,
+		Checks:      ctrl.runUpgradePreflightChecks(ctx, karmada, from, to),
+	}
+	var failed []string
+	for _, check := range preflight.Checks {
+		if !check.Passed {
+			failed = append(failed, string(check.Name))
+		}
+	}
+	preflight.Passed = len(failed) == 0
+	if !preflight.Passed && karmada.Annotations[constants.ForceUpgradeAnnotation] == to {
+		preflight.Passed, preflight.Forced = true, true
+	}
+
+	condition := &metav1.Condition{
+		Status:  metav1.ConditionTrue,
+		Reason:  "Passed",
+		Message: fmt.Sprintf("the upgrade from %s to %s passed the preflight checks", from, to),
+	}
+	switch {
+	case preflight.Forced:
+		condition.Reason = "Forced"
+		condition.Message = fmt.Sprintf("the upgrade from %s to %s is forced in spite of the failed preflight checks: %s", from, to, strings.Join(failed, ", "))
+	case !preflight.Passed:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Failed"
+		condition.Message = fmt.Sprintf("the upgrade from %s to %s is refused since the preflight checks failed: %s, see status.upgradePreflight", from, to, strings.Join(failed, ", "))
+	}
+
+	changed := !equality.Semantic.DeepEqual(karmada.Status.UpgradePreflight, preflight)
+	if err := ctrl.updateKarmadaStatus(ctx, karmada, func(status *installv1alpha1.KarmadaStatus) {
+		status.UpgradePreflight = preflight
+	}); err != nil {
+		return false, err
+	}
+	if err := ctrl.updateKarmadaCondition(ctx, karmada, installv1alpha1.KarmadaConditionUpgradePreflightPassed, condition); err != nil {
+		return false, err
+	}
+	if changed {
+		switch {
+		case preflight.Forced:
+			ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "UpgradeForced", "Upgrading karmada from %s to %s in spite of the failed preflight checks: %s", from, to, strings.Join(failed, ", "))
+		case !preflight.Passed:
+			ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "UpgradeRefused", "Refused to upgrade karmada from %s to %s since the preflight checks failed: %s", from, to, strings.Join(failed, ", "))
+		}
+	}
+
+	if !preflight.Passed {
+		klog.V(2).InfoS("The upgrade of karmada is refused by the preflight checks", "karmada", klog.KObj(karmada), "from", from, "to", to, "failed", failed)
+		ctrl.enqueueAfter(karmada, upgradePreflightRetryInterval)
+	}
+	return preflight.Passed, nil
+}
+
+// runUpgradePreflightChecks runs all the upgrade preflight checks of the karmada.
+func (ctrl *KarmadaController) runUpgradePreflightChecks(ctx context.Context, karmada *installv1alpha1.Karmada, from, to string) []installv1alpha1.UpgradePreflightCheck {
+	checks := []installv1alpha1.UpgradePreflightCheck{checkVersionSkew(from, to)}
+
+	var kubeClient kubernetes.Interface
+	var dynamicClient dynamic.Interface
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
+	if err == nil {
+		if kubeClient, err = kubernetes.NewForConfig(clientConfig); err == nil {
+			dynamicClient, err = dynamic.NewForConfig(clientConfig)
+		}
+	}
+	if err != nil {
+		for _, name := range []installv1alpha1.UpgradePreflightCheckName{
+			installv1alpha1.UpgradePreflightCheckEtcdHealth,
+			installv1alpha1.UpgradePreflightCheckEtcdSpace,
+			installv1alpha1.UpgradePreflightCheckStoredVersions,
+		} {
+			checks = append(checks, installv1alpha1.UpgradePreflightCheck{
+				Name:    name,
+				Message: fmt.Sprintf("failed to build the client of the karmada-apiserver: %v", err),
+			})
+		}
+	} else {
+		restClient := kubeClient.Discovery().RESTClient()
+		checks = append(checks,
+			checkEtcdHealth(ctx, restClient),
+			checkEtcdSpace(ctx, karmada, restClient),
+			ctrl.checkStoredVersions(ctx, dynamicClient, to),
+		)
+	}
+
+	return append(checks, ctrl.checkAgentVersionSkew(ctx, karmada, to))
+}
+
+// checkVersionSkew refuses to downgrade the karmada, to upgrade it across major versions, or to skip a
+// minor version, which the components and the storage migrations of karmada aren't tested with.
+func checkVersionSkew(from, to string) installv1alpha1.UpgradePreflightCheck {
+	check := installv1alpha1.UpgradePreflightCheck{Name: installv1alpha1.UpgradePreflightCheckVersionSkew}
+	fromVersion, err := semver.NewVersion(from)
+	if err != nil {
+		check.Message = fmt.Sprintf("the installed version %s is not a semantic version, the skew can't be verified", from)
+		return check
+	}
+	toVersion, err := semver.NewVersion(to)
+	if err != nil {
+		check.Message = fmt.Sprintf("the version %s is not a semantic version, the skew can't be verified", to)
+		return check
+	}
+
+	switch {
+	case toVersion.LessThan(fromVersion):
+		check.Message = fmt.Sprintf("downgrading karmada from %s to %s is not supported", from, to)
+	case toVersion.Major() != fromVersion.Major():
+		check.Message = fmt.Sprintf("upgrading karmada across major versions from %s to %s is not supported", from, to)
+	case toVersion.Minor() > fromVersion.Minor()+1:
+		check.Message = fmt.Sprintf("upgrading karmada from %s to %s skips minor versions, upgrade to v%d.%d first", from, to, fromVersion.Major(), fromVersion.Minor()+1)
+	default:
+		check.Passed = true
+		check.Message = fmt.Sprintf("upgrading karmada from %s to %s is within one minor version", from, to)
+	}
+	return check
+}
+
+// checkEtcdHealth verifies that the etcd of the karmada is healthy through the readyz endpoint of the
+// karmada-apiserver.
+func checkEtcdHealth(ctx context.Context, client rest.Interface) installv1alpha1.UpgradePreflightCheck {
+	check := installv1alpha1.UpgradePreflightCheck{Name: installv1alpha1.UpgradePreflightCheckEtcdHealth}
+	if _, err := client.Get().AbsPath("/readyz/etcd").Do(ctx).Raw(); err != nil {
+		check.Message = fmt.Sprintf("etcd is unhealthy: %v", err)
+		return check
+	}
+	check.Passed = true
+	check.Message = "etcd is healthy"
+	return check
+}
+
+// checkEtcdSpace verifies that the database of the local etcd, whose size is reported by the metrics of
+// the karmada-apiserver, has room for the upgrade. The quota of an external etcd is unknown, so it's
+// not checked.
+func checkEtcdSpace(ctx context.Context, karmada *installv1alpha1.Karmada, client rest.Interface) installv1alpha1.UpgradePreflightCheck {
+	check := installv1alpha1.UpgradePreflightCheck{Name: installv1alpha1.UpgradePreflightCheckEtcdSpace}
+	if karmada.Spec.Etcd.Local == nil {
+		check.Passed = true
+		check.Message = "the quota of the external etcd is unknown, its space is not checked"
+		return check
+	}
+
+	body, err := client.Get().AbsPath("/metrics").Do(ctx).Raw()
+	if err != nil {
+		check.Message = fmt.Sprintf("failed to get the metrics of the karmada-apiserver: %v", err)
+		return check
+	}
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		check.Message = fmt.Sprintf("failed to parse the metrics of the karmada-apiserver: %v", err)
+		return check
+	}
+	size := -1.0
+	for _, name := range etcdDBSizeMetrics {
+		for _, metric := range families[name].GetMetric() {
+			if value := metric.GetGauge().GetValue(); value > size {
+				size = value
+			}
+		}
+	}
+
+	quotaBytes := EtcdQuotaBytes(karmada)
+	quota := resource.NewQuantity(quotaBytes, resource.BinarySI)
+	switch {
+	case size < 0:
+		check.Passed = true
+		check.Message = "the karmada-apiserver reports no size of the etcd database, its space is not checked"
+	case size > etcdSpaceThreshold*float64(quotaBytes):
+		check.Message = fmt.Sprintf("the etcd database uses %s of its %s quota, compact and defragment it first", resource.NewQuantity(int64(size), resource.BinarySI), quota)
+	default:
+		check.Passed = true
+		check.Message = fmt.Sprintf("the etcd database uses %s of its %s quota", resource.NewQuantity(int64(size), resource.BinarySI), quota)
+	}
+	return check
+}
+
+// checkStoredVersions verifies that the objects of the CRDs of karmada are only stored in the versions
+// which are still served by the CRDs of the upgraded version, otherwise they can't be read once the CRDs
+// are replaced.
+func (ctrl *KarmadaController) checkStoredVersions(ctx context.Context, client dynamic.Interface, to string) installv1alpha1.UpgradePreflightCheck {
+	check := installv1alpha1.UpgradePreflightCheck{Name: installv1alpha1.UpgradePreflightCheckStoredVersions}
+	crds, err := ctrl.crdFetcher.Fetch(ctx, to)
+	if err != nil {
+		check.Message = fmt.Sprintf("failed to fetch the CRDs of karmada %s: %v", to, err)
+		return check
+	}
+
+	var unserved []string
+	for _, crd := range crds {
+		served := sets.NewString()
+		versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+		for _, version := range versions {
+			if version, ok := version.(map[string]interface{}); ok && version["served"] == true {
+				served.Insert(fmt.Sprint(version["name"]))
+			}
+		}
+
+		current, err := client.Resource(crdsGVR).Get(ctx, crd.GetName(), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			check.Message = fmt.Sprintf("failed to get CRD %s: %v", crd.GetName(), err)
+			return check
+		}
+		stored, _, _ := unstructured.NestedStringSlice(current.Object, "status", "storedVersions")
+		for _, version := range stored {
+			if !served.Has(version) {
+				unserved = append(unserved, crd.GetName()+"/"+version)
+			}
+		}
+	}
+
+	if len(unserved) > 0 {
+		check.Message = fmt.Sprintf("objects are stored in versions which karmada %s no longer serves: %s, migrate them to a served version and drop the versions from status.storedVersions of the CRDs first", to, strings.Join(unserved, ", "))
+		return check
+	}
+	check.Passed = true
+	check.Message = fmt.Sprintf("the objects are stored in versions served by karmada %s", to)
+	return check
+}
+
+// checkAgentVersionSkew verifies that the karmada-agents of the member clusters in pull mode whose image
+// tags are pinned, which aren't upgraded along with the karmada, are neither newer than the upgraded
+// karmada nor more than one minor version older.
+func (ctrl *KarmadaController) checkAgentVersionSkew(ctx context.Context, karmada *installv1alpha1.Karmada, to string) installv1alpha1.UpgradePreflightCheck {
+	check := installv1alpha1.UpgradePreflightCheck{Name: installv1alpha1.UpgradePreflightCheckAgentVersionSkew}
+	toVersion, err := semver.NewVersion(to)
+	if err != nil {
+		check.Message = fmt.Sprintf("the version %s is not a semantic version, the skew can't be verified", to)
+		return check
+	}
+	registrations, err := ctrl.fireflyClient.InstallV1alpha1().ClusterRegistrations(karmada.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		check.Message = fmt.Sprintf("failed to list the cluster registrations: %v", err)
+		return check
+	}
+
+	var skewed []string
+	for _, cr := range registrations.Items {
+		tag := cr.Spec.Agent.ImageTag
+		if cr.Spec.Karmada.Name != karmada.Name || cr.Spec.SyncMode != installv1alpha1.ClusterSyncModePull || tag == "" {
+			continue
+		}
+		agentVersion, err := semver.NewVersion(tag)
+		if err != nil || agentVersion.Major() != toVersion.Major() || agentVersion.Minor()+1 < toVersion.Minor() || agentVersion.GreaterThan(toVersion) {
+			skewed = append(skewed, fmt.Sprintf("%s (%s)", cr.Name, tag))
+		}
+	}
+
+	if len(skewed) > 0 {
+		check.Message = fmt.Sprintf("the pinned karmada-agents of the clusters %s would be out of the supported skew with karmada %s, upgrade them first", strings.Join(skewed, ", "), to)
+		return check
+	}
+	check.Passed = true
+	check.Message = fmt.Sprintf("the karmada-agents are within one minor version of karmada %s", to)
+	return check
+}
@@ -0,0 +1,336 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	karmadaversioned "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+)
+
+const (
+	// smokeTestName is the name of the namespace, the Deployment and the PropagationPolicy created by
+	// the smoke tests in the karmada-apiserver.
+	smokeTestName = "firefly-smoke-test"
+
+	// smokeTestImage is the image of the Deployment of the propagation test. The Deployment has no
+	// replicas, so that the image is never pulled in the member clusters.
+	smokeTestImage = "registry.k8s.io/pause:3.8"
+
+	// defaultVerificationTimeout is how long the smoke tests wait for the objects they create if
+	// spec.verification.timeout is empty.
+	defaultVerificationTimeout = 5 * time.Minute
+)
+
+// smokeTestClients are the clients of the karmada-apiserver the smoke tests are run with.
+type smokeTestClients struct {
+	kubeClient    kubernetes.Interface
+	karmadaClient karmadaversioned.Interface
+}
+
+// smokeTest is a test of the smoke test suite. It returns the result of the test, and a message
+// indicating details about it. An error is reported as a running test, which is run again until
+// the suite times out.
+type smokeTest struct {
+	name string
+	run  func(ctx context.Context, clients *smokeTestClients, karmada *installv1alpha1.Karmada) (installv1alpha1.TestResult, string, error)
+}
+
+// EnsureVerification runs the smoke test suite of spec.verification against the installed version of
+// the karmada, and reports the results in status.testReport. The suite is run once per installed
+// version, and the karmada is requeued until all of its tests are complete. The objects created by
+// the tests are removed once the suite is complete.
+func (ctrl *KarmadaController) EnsureVerification(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	if karmada.Spec.Verification == nil {
+		return nil
+	}
+	if dryrun.Enabled(ctx) {
+		klog.V(2).InfoS("Skipping the smoke tests in dry-run mode", "karmada", klog.KObj(karmada))
+		return nil
+	}
+
+	version := karmada.Status.KarmadaVersion
+	report := karmada.Status.TestReport.DeepCopy()
+	if report != nil && report.Version == version && report.Result != installv1alpha1.TestResultRunning {
+		return nil
+	}
+	if report == nil || report.Version != version {
+		now := metav1.Now()
+		report = &installv1alpha1.TestReport{Version: version, Result: installv1alpha1.TestResultRunning, StartTime: &now}
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "SmokeTestsStarted", "Started the smoke tests of karmada %s", version)
+	}
+
+	clientConfig, err := ctrl.GenerateClientConfig(ctx, karmada)
+	if err != nil {
+		return err
+	}
+	clients := &smokeTestClients{}
+	if clients.kubeClient, err = kubernetes.NewForConfig(clientConfig); err != nil {
+		return err
+	}
+	if clients.karmadaClient, err = karmadaversioned.NewForConfig(clientConfig); err != nil {
+		return err
+	}
+
+	timeout := defaultVerificationTimeout
+	if karmada.Spec.Verification.Timeout != nil {
+		timeout = karmada.Spec.Verification.Timeout.Duration
+	}
+	timedOut := time.Since(report.StartTime.Time) > timeout
+
+	tests := []smokeTest{
+		{name: "namespace", run: testNamespace},
+		{name: "propagation", run: testPropagation},
+		{name: "clusterpedia", run: ctrl.testClusterpedia},
+	}
+	results := make([]installv1alpha1.TestCaseStatus, 0, len(tests))
+	for _, test := range tests {
+		if last := testCaseStatus(report, test.name); last != nil && last.Result != installv1alpha1.TestResultRunning {
+			results = append(results, *last)
+			continue
+		}
+
+		var result installv1alpha1.TestResult
+		var message string
+		if test.name != "namespace" && testCaseResult(results, "namespace") != installv1alpha1.TestResultPassed {
+			// the other tests create their objects in the namespace of the namespace test.
+			result, message = installv1alpha1.TestResultSkipped, "the namespace test didn't pass"
+			if testCaseResult(results, "namespace") == installv1alpha1.TestResultRunning {
+				result, message = installv1alpha1.TestResultRunning, "waiting for the namespace test to pass"
+			}
+		} else if result, message, err = test.run(ctx, clients, karmada); err != nil {
+			result, message = installv1alpha1.TestResultRunning, err.Error()
+		}
+		if result == installv1alpha1.TestResultRunning && timedOut {
+			result, message = installv1alpha1.TestResultFailed, fmt.Sprintf("timed out after %s: %s", timeout, message)
+		}
+		results = append(results, installv1alpha1.TestCaseStatus{Name: test.name, Result: result, Message: message})
+	}
+	report.Tests = results
+	report.Result = suiteResult(results)
+
+	if report.Result != installv1alpha1.TestResultRunning {
+		now := metav1.Now()
+		report.CompletionTime = &now
+		if err := cleanupSmokeTests(ctx, clients); err != nil {
+			return err
+		}
+	}
+	if err := ctrl.updateKarmadaStatus(ctx, karmada, func(status *installv1alpha1.KarmadaStatus) {
+		status.TestReport = report
+	}); err != nil {
+		return err
+	}
+
+	switch report.Result {
+	case installv1alpha1.TestResultRunning:
+		ctrl.enqueueAfter(karmada, hookCheckInterval)
+	case installv1alpha1.TestResultFailed:
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "SmokeTestsFailed", "The smoke tests of karmada %s failed: %s", version, strings.Join(failedTests(results), ", "))
+	default:
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "SmokeTestsPassed", "The smoke tests of karmada %s passed", version)
+	}
+	return nil
+}
+
+// testNamespace creates the namespace of the smoke tests and waits for it to be active.
+func testNamespace(ctx context.Context, clients *smokeTestClients, karmada *installv1alpha1.Karmada) (installv1alpha1.TestResult, string, error) {
+	ns, err := clients.kubeClient.CoreV1().Namespaces().Get(ctx, smokeTestName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		ns = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: smokeTestName}}
+		ns, err = clients.kubeClient.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if ns.DeletionTimestamp != nil {
+		return installv1alpha1.TestResultRunning, "waiting for the namespace of the previous run to be deleted", nil
+	}
+	if ns.Status.Phase != corev1.NamespaceActive {
+		return installv1alpha1.TestResultRunning, fmt.Sprintf("waiting for the namespace %s to be active", smokeTestName), nil
+	}
+	return installv1alpha1.TestResultPassed, fmt.Sprintf("created the namespace %s", smokeTestName), nil
+}
+
+// testPropagation propagates a Deployment without replicas to the member clusters of spec.verification,
+// or to all the member clusters if it's empty, and waits for it to be applied to them.
+func testPropagation(ctx context.Context, clients *smokeTestClients, karmada *installv1alpha1.Karmada) (installv1alpha1.TestResult, string, error) {
+	clusters := karmada.Spec.Verification.Clusters
+	if len(clusters) == 0 {
+		list, err := clients.karmadaClient.ClusterV1alpha1().Clusters().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return "", "", err
+		}
+		if len(list.Items) == 0 {
+			return installv1alpha1.TestResultSkipped, "no member clusters have joined the karmada", nil
+		}
+	}
+
+	labels := map[string]string{"app": smokeTestName}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: smokeTestName, Namespace: smokeTestName},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32(0),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "pause", Image: smokeTestImage}},
+				},
+			},
+		},
+	}
+	if _, err := clientutil.CreateOrUpdateDeployment(ctx, clients.kubeClient, deployment); err != nil {
+		return "", "", err
+	}
+
+	policy := &policyv1alpha1.PropagationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: smokeTestName, Namespace: smokeTestName},
+		Spec: policyv1alpha1.PropagationSpec{
+			ResourceSelectors: []policyv1alpha1.ResourceSelector{{APIVersion: "apps/v1", Kind: "Deployment", Name: smokeTestName}},
+		},
+	}
+	if len(clusters) != 0 {
+		policy.Spec.Placement.ClusterAffinity = &policyv1alpha1.ClusterAffinity{ClusterNames: clusters}
+	}
+	if _, err := clients.karmadaClient.PolicyV1alpha1().PropagationPolicies(smokeTestName).Create(ctx, policy, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return "", "", err
+	}
+
+	// the ResourceBinding of a resource template is named after the name and the kind of the template.
+	binding, err := clients.karmadaClient.WorkV1alpha2().ResourceBindings(smokeTestName).Get(ctx, smokeTestName+"-deployment", metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return installv1alpha1.TestResultRunning, "waiting for the deployment to be bound to the propagation policy", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if len(binding.Spec.Clusters) == 0 {
+		return installv1alpha1.TestResultRunning, "waiting for the deployment to be scheduled", nil
+	}
+	applied := make(map[string]bool, len(binding.Status.AggregatedStatus))
+	for _, item := range binding.Status.AggregatedStatus {
+		applied[item.ClusterName] = item.Applied
+	}
+	for _, target := range binding.Spec.Clusters {
+		if !applied[target.Name] {
+			return installv1alpha1.TestResultRunning, fmt.Sprintf("waiting for the deployment to be applied to the cluster %s", target.Name), nil
+		}
+	}
+	return installv1alpha1.TestResultPassed, fmt.Sprintf("propagated the deployment to %d clusters", len(binding.Spec.Clusters)), nil
+}
+
+// testClusterpedia queries the resources of the member clusters through the clusterpedia which uses the
+// karmada as its control plane. It's skipped if there's no such clusterpedia.
+func (ctrl *KarmadaController) testClusterpedia(ctx context.Context, clients *smokeTestClients, karmada *installv1alpha1.Karmada) (installv1alpha1.TestResult, string, error) {
+	clusterpedias, err := ctrl.fireflyClient.InstallV1alpha1().Clusterpedias(karmada.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", "", err
+	}
+	var name string
+	for _, clusterpedia := range clusterpedias.Items {
+		if provider := clusterpedia.Spec.ControlplaneProvider; provider != nil && provider.Karmada != nil && provider.Karmada.Name == karmada.Name {
+			name = clusterpedia.Name
+			break
+		}
+	}
+	if name == "" {
+		return installv1alpha1.TestResultSkipped, "no clusterpedia uses the karmada as its control plane", nil
+	}
+
+	// the clusterpedia-apiserver is registered into the karmada-apiserver by the v1beta1.clusterpedia.io
+	// APIService.
+	err = clients.kubeClient.Discovery().RESTClient().Get().
+		AbsPath("/apis/clusterpedia.io/v1beta1/resources/api/v1/namespaces").
+		Param("limit", "1").
+		Do(ctx).
+		Error()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to query the clusterpedia %s: %v", name, err)
+	}
+	return installv1alpha1.TestResultPassed, fmt.Sprintf("queried the namespaces of the member clusters through the clusterpedia %s", name), nil
+}
+
+// cleanupSmokeTests removes the namespace of the smoke tests, along with the objects created in it.
+func cleanupSmokeTests(ctx context.Context, clients *smokeTestClients) error {
+	err := clients.kubeClient.CoreV1().Namespaces().Delete(ctx, smokeTestName, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// testCaseStatus returns the status of the named test of the report, or nil if it isn't run yet.
+func testCaseStatus(report *installv1alpha1.TestReport, name string) *installv1alpha1.TestCaseStatus {
+	for i := range report.Tests {
+		if report.Tests[i].Name == name {
+			return &report.Tests[i]
+		}
+	}
+	return nil
+}
+
+// testCaseResult returns the result of the named test.
+func testCaseResult(results []installv1alpha1.TestCaseStatus, name string) installv1alpha1.TestResult {
+	for _, result := range results {
+		if result.Name == name {
+			return result.Result
+		}
+	}
+	return ""
+}
+
+// suiteResult returns the result of the suite: Running while a test is running, Failed if a test
+// failed and Passed otherwise. The skipped tests don't fail the suite.
+func suiteResult(results []installv1alpha1.TestCaseStatus) installv1alpha1.TestResult {
+	suite := installv1alpha1.TestResultPassed
+	for _, result := range results {
+		switch result.Result {
+		case installv1alpha1.TestResultRunning:
+			return installv1alpha1.TestResultRunning
+		case installv1alpha1.TestResultFailed:
+			suite = installv1alpha1.TestResultFailed
+		}
+	}
+	return suite
+}
+
+// failedTests returns the names of the failed tests.
+func failedTests(results []installv1alpha1.TestCaseStatus) []string {
+	var names []string
+	for _, result := range results {
+		if result.Result == installv1alpha1.TestResultFailed {
+			names = append(names, result.Name)
+		}
+	}
+	return names
+}
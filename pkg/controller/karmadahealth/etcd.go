@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmadahealth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	karmadacontroller "github.com/carlory/firefly/pkg/controller/karmada"
+)
+
+const (
+	// etcdDBSizeMetric is the metric of etcd reporting the size of its database.
+	etcdDBSizeMetric = "etcd_mvcc_db_total_size_in_bytes"
+	// etcdDBSizeInUseMetric is the metric of etcd reporting the size of its database in use.
+	etcdDBSizeInUseMetric = "etcd_mvcc_db_total_size_in_use_in_bytes"
+	// etcdQuotaMetric is the metric of etcd reporting the space quota of its database.
+	etcdQuotaMetric = "etcd_server_quota_backend_bytes"
+)
+
+// etcdHealth is the response of the health endpoint of etcd.
+type etcdHealth struct {
+	Health string `json:"health"`
+	Reason string `json:"reason"`
+}
+
+// probeEtcdStorage probes the database of the local etcd through the client port of etcd with the etcd
+// client certificate of the karmada-apiserver. It returns nil for an external etcd.
+func (ctrl *KarmadaHealthController) probeEtcdStorage(ctx context.Context, karmada *installv1alpha1.Karmada) *installv1alpha1.EtcdStatus {
+	if karmada.Spec.Etcd.External != nil {
+		return nil
+	}
+	status := &installv1alpha1.EtcdStatus{}
+	cronJob, err := ctrl.client.BatchV1().CronJobs(karmada.Namespace).Get(ctx, karmadacontroller.EtcdDefragCronJobName, metav1.GetOptions{})
+	if err == nil {
+		status.LastDefragmentationTime = cronJob.Status.LastSuccessfulTime
+	}
+
+	client, err := ctrl.etcdClient(ctx, karmada)
+	if err != nil {
+		status.Message = err.Error()
+		return status
+	}
+	endpoint := fmt.Sprintf("https://%s.%s.svc:2379", constants.KarmadaComponentEtcd, karmada.Namespace)
+
+	var health etcdHealth
+	if err := getEtcd(ctx, client, endpoint+"/health", func(resp *http.Response) error {
+		return json.NewDecoder(resp.Body).Decode(&health)
+	}); err != nil {
+		status.Message = fmt.Sprintf("failed to get the health of etcd: %v", err)
+		return status
+	}
+	// etcd reports its active alarms as the reason of its health, e.g. "ALARM NOSPACE".
+	for _, reason := range strings.Split(health.Reason, ",") {
+		if alarm := strings.TrimPrefix(strings.TrimSpace(reason), "ALARM "); alarm != strings.TrimSpace(reason) {
+			status.Alarms = append(status.Alarms, alarm)
+		}
+	}
+
+	if err := getEtcd(ctx, client, endpoint+"/metrics", func(resp *http.Response) error {
+		var parser expfmt.TextParser
+		families, err := parser.TextToMetricFamilies(resp.Body)
+		if err != nil {
+			return err
+		}
+		status.DBSize = etcdGauge(families[etcdDBSizeMetric].GetMetric())
+		status.DBSizeInUse = etcdGauge(families[etcdDBSizeInUseMetric].GetMetric())
+		status.Quota = etcdGauge(families[etcdQuotaMetric].GetMetric())
+		return nil
+	}); err != nil {
+		status.Message = fmt.Sprintf("failed to get the metrics of etcd: %v", err)
+	}
+	return status
+}
+
+// etcdClient returns an http client authenticated to the local etcd by the etcd client certificate in
+// the Secret karmada-cert.
+func (ctrl *KarmadaHealthController) etcdClient(ctx context.Context, karmada *installv1alpha1.Karmada) (*http.Client, error) {
+	certSecret, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, "karmada-cert", metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the etcd client certificate: %v", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(certSecret.Data["etcd-ca.crt"]) {
+		return nil, fmt.Errorf("failed to parse the etcd CA")
+	}
+	cert, err := tls.X509KeyPair(certSecret.Data["etcd-client.crt"], certSecret.Data["etcd-client.key"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the etcd client certificate: %v", err)
+	}
+	return &http.Client{
+		Timeout: probeTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:      roots,
+				Certificates: []tls.Certificate{cert},
+				MinVersion:   tls.VersionTLS12,
+			},
+		},
+	}, nil
+}
+
+// getEtcd gets the given url of etcd and reads the response by the given function.
+func getEtcd(ctx context.Context, client *http.Client, url string, read func(resp *http.Response) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// the health endpoint responds 503 with the reason if etcd is unhealthy, e.g. alarmed.
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return read(resp)
+}
+
+// etcdGauge returns the value of the gauge of etcd in bytes, or nil if etcd doesn't report it.
+func etcdGauge(metrics []*dto.Metric) *resource.Quantity {
+	if len(metrics) == 0 {
+		return nil
+	}
+	return resource.NewQuantity(int64(metrics[0].GetGauge().GetValue()), resource.BinarySI)
+}
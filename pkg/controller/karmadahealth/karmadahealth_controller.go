@@ -0,0 +1,304 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmadahealth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/prometheus/ratelimiter"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	fireflyclient "github.com/carlory/firefly/pkg/generated/clientset/versioned"
+	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
+	installlisters "github.com/carlory/firefly/pkg/generated/listers/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	"github.com/carlory/firefly/pkg/util/notification"
+	phaseutil "github.com/carlory/firefly/pkg/util/phase"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
+)
+
+const (
+	// maxRetries is the number of times a karmada will be retried before it is dropped out of the queue.
+	// With the current rate-limiter in use (5ms*2^(maxRetries-1)) the following numbers represent the
+	// sequence of delays between successive queuings of a karmada.
+	//
+	// 5ms, 10ms, 20ms, 40ms, 80ms, 160ms, 320ms, 640ms, 1.3s, 2.6s, 5.1s, 10.2s, 20.4s, 41s, 82s
+	maxRetries = 15
+
+	// healthCheckPeriod is how often the components of an installed karmada are probed.
+	healthCheckPeriod = 30 * time.Second
+)
+
+// NewKarmadaHealthController returns a new *Controller.
+func NewKarmadaHealthController(
+	client clientset.Interface,
+	fireflyClient fireflyclient.Interface,
+	karmadaInformer installinformers.KarmadaInformer) (*KarmadaHealthController, error) {
+	broadcaster := record.NewBroadcaster()
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "karmadahealth-controller"})
+
+	if client != nil && client.CoreV1().RESTClient().GetRateLimiter() != nil {
+		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("karmadahealth_controller", client.CoreV1().RESTClient().GetRateLimiter())
+	}
+
+	ctrl := &KarmadaHealthController{
+		client:           client,
+		fireflyClient:    fireflyClient,
+		karmadasLister:   karmadaInformer.Lister(),
+		karmadasSynced:   karmadaInformer.Informer().HasSynced,
+		queue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "karmadahealth"),
+		workerLoopPeriod: time.Second,
+		eventBroadcaster: broadcaster,
+		eventRecorder:    recorder,
+	}
+
+	karmadaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.addKarmada,
+		UpdateFunc: ctrl.updateKarmada,
+	})
+
+	return ctrl, nil
+}
+
+// KarmadaHealthController probes the karmada-apiserver, the etcd and the karmada-webhook of the
+// installed karmadas periodically, reports their health by the conditions of the karmadas, and
+// remediates the unhealthy components if it's enabled.
+type KarmadaHealthController struct {
+	client           clientset.Interface
+	fireflyClient    fireflyclient.Interface
+	eventBroadcaster record.EventBroadcaster
+	eventRecorder    record.EventRecorder
+
+	karmadasLister installlisters.KarmadaLister
+	karmadasSynced cache.InformerSynced
+
+	// Karmadas that need to be probed. A channel is inappropriate here,
+	// because it allows a karmada to be inserted multiple times and be
+	// processed more than necessary.
+	queue workqueue.RateLimitingInterface
+
+	// workerLoopPeriod is the time between worker runs. The workers process the queue of karmada changes.
+	workerLoopPeriod time.Duration
+}
+
+// Run will not return until stopCh is closed. workers determines how many
+// karmadas will be handled in parallel.
+func (ctrl *KarmadaHealthController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+
+	// Start events processing pipeline.
+	ctrl.eventBroadcaster.StartStructuredLogging(0)
+	ctrl.eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: ctrl.client.CoreV1().Events("")})
+	defer ctrl.eventBroadcaster.Shutdown()
+
+	defer ctrl.queue.ShutDown()
+
+	klog.Infof("Starting karmada health controller")
+	defer klog.Infof("Shutting down karmada health controller")
+
+	if !cache.WaitForNamedCacheSync("karmadahealth", ctx.Done(), ctrl.karmadasSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, ctrl.worker, ctrl.workerLoopPeriod)
+	}
+	<-ctx.Done()
+}
+
+// worker runs a worker thread that just dequeues items, processes them, and
+// marks them done. You may run as many of these in parallel as you wish; the
+// workqueue guarantees that they will not end up processing the same karmada
+// at the same time.
+func (ctrl *KarmadaHealthController) worker(ctx context.Context) {
+	for ctrl.processNextWorkItem(ctx) {
+	}
+}
+
+func (ctrl *KarmadaHealthController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := ctrl.queue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.queue.Done(key)
+
+	ctx, span := tracingutil.StartReconcile(ctx, "karmadahealth", key.(string))
+	err := ctrl.syncKarmadaHealth(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.handleErr(err, key)
+
+	return true
+}
+
+func (ctrl *KarmadaHealthController) addKarmada(obj interface{}) {
+	karmada := obj.(*installv1alpha1.Karmada)
+	klog.V(4).InfoS("Adding karmada", "karmada", klog.KObj(karmada))
+	ctrl.enqueue(karmada)
+}
+
+// updateKarmada enqueues the karmada when its spec is changed or it's installed. The other updates,
+// e.g. the conditions reported by this controller, are picked up by the periodic probing.
+func (ctrl *KarmadaHealthController) updateKarmada(old, cur interface{}) {
+	oldKarmada := old.(*installv1alpha1.Karmada)
+	curKarmada := cur.(*installv1alpha1.Karmada)
+	if oldKarmada.Generation == curKarmada.Generation && oldKarmada.Status.KarmadaVersion == curKarmada.Status.KarmadaVersion {
+		return
+	}
+	klog.V(4).InfoS("Updating karmada", "karmada", klog.KObj(oldKarmada))
+	ctrl.enqueue(curKarmada)
+}
+
+func (ctrl *KarmadaHealthController) enqueue(karmada *installv1alpha1.Karmada) {
+	key, err := cache.MetaNamespaceKeyFunc(karmada)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.Add(key)
+}
+
+func (ctrl *KarmadaHealthController) handleErr(err error, key interface{}) {
+	if err == nil || errors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+		ctrl.queue.Forget(key)
+		return
+	}
+
+	ns, name, keyErr := cache.SplitMetaNamespaceKey(key.(string))
+	if keyErr != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+	}
+
+	notification.ReconcileFailed("karmadahealth", audit.ObjectReference{Kind: "Karmada", Namespace: ns, Name: name}, ctrl.queue.NumRequeues(key)+1, err)
+	if ctrl.queue.NumRequeues(key) < maxRetries {
+		klog.V(2).InfoS("Error syncing karmada health, retrying", "karmada", klog.KRef(ns, name), "err", err)
+		ctrl.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	klog.V(2).InfoS("Dropping karmada health out of the queue", "karmada", klog.KRef(ns, name), "err", err)
+	ctrl.queue.Forget(key)
+	// keep probing the karmada even though its status can't be updated for now.
+	ctrl.queue.AddAfter(key, healthCheckPeriod)
+}
+
+func (ctrl *KarmadaHealthController) syncKarmadaHealth(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+		return err
+	}
+
+	startTime := time.Now()
+	klog.V(4).InfoS("Started syncing karmada health", "karmada", klog.KRef(namespace, name), "startTime", startTime)
+	defer func() {
+		klog.V(4).InfoS("Finished syncing karmada health", "karmada", klog.KRef(namespace, name), "duration", time.Since(startTime))
+	}()
+
+	karmada, err := ctrl.karmadasLister.Karmadas(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		klog.V(2).InfoS("Karmada has been deleted", "karmada", klog.KRef(namespace, name))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !karmada.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	// The components are probed once the karmada is installed by firefly, the karmadas installed
+	// from a chart are never marked installed.
+	if karmada.Status.KarmadaVersion == "" {
+		klog.V(4).InfoS("Karmada is not installed yet, skip probing", "karmada", klog.KObj(karmada))
+		return nil
+	}
+
+	// Deep-copy otherwise we are mutating our cache.
+	karmada = karmada.DeepCopy()
+	ctx = audit.WithTrigger(ctx, karmada)
+	ctx = dryrun.ForObject(ctx, karmada)
+
+	remediate := karmada.Spec.HealthCheck.AutoRemediation && !karmada.Spec.Paused
+	if remediate {
+		// The kubeconfig is checked before probing, since the karmada-apiserver is probed with it.
+		if err := ctrl.ensureKubeconfigSecret(ctx, karmada); err != nil {
+			ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "RemediationFailed", "Failed to re-render the Secret karmada-kubeconfig: %v", err)
+		}
+	}
+	if err := ctrl.checkCrashLoopingComponents(ctx, karmada, remediate); err != nil {
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "RemediationFailed", "Failed to check the crash-looping components: %v", err)
+	}
+
+	conditions := ctrl.probe(ctx, karmada)
+	etcd := ctrl.probeEtcdStorage(ctx, karmada)
+	if err := ctrl.updateStatus(ctx, karmada, conditions, etcd); err != nil {
+		return err
+	}
+
+	ctrl.queue.AddAfter(key, healthCheckPeriod)
+	return nil
+}
+
+// updateStatus sets the given health conditions and the state of the etcd database of the karmada. The
+// status is updated only if it's changed.
+func (ctrl *KarmadaHealthController) updateStatus(ctx context.Context, karmada *installv1alpha1.Karmada, conditions []metav1.Condition, etcd *installv1alpha1.EtcdStatus) error {
+	oldStatus := karmada.Status.DeepCopy()
+	for _, condition := range conditions {
+		condition.ObservedGeneration = karmada.Generation
+		meta.SetStatusCondition(&karmada.Status.Conditions, condition)
+	}
+	karmada.Status.Etcd = etcd
+	phaseutil.SetKarmadaPhase(karmada)
+	if equality.Semantic.DeepEqual(oldStatus, &karmada.Status) {
+		return nil
+	}
+	if etcd != nil {
+		var oldAlarms []string
+		if oldStatus.Etcd != nil {
+			oldAlarms = oldStatus.Etcd.Alarms
+		}
+		if raised := sets.NewString(etcd.Alarms...).Difference(sets.NewString(oldAlarms...)); raised.Len() > 0 {
+			ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "EtcdAlarmed", "etcd raised the alarms %s", strings.Join(raised.List(), ", "))
+		}
+	}
+	for _, condition := range conditions {
+		old := meta.FindStatusCondition(oldStatus.Conditions, condition.Type)
+		if condition.Status == metav1.ConditionFalse && (old == nil || old.Status != metav1.ConditionFalse) {
+			ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "ComponentUnhealthy", "%s: %s", condition.Type, condition.Message)
+		}
+	}
+	_, err := ctrl.fireflyClient.InstallV1alpha1().Karmadas(karmada.Namespace).UpdateStatus(ctx, karmada, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmadahealth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	utilresource "github.com/carlory/firefly/pkg/util/resource"
+)
+
+const (
+	// the user-agent name is used when talking to karmada-apiserver
+	userAgentName = "karmadahealth-controller"
+
+	// probeTimeout is the timeout of each probe.
+	probeTimeout = 5 * time.Second
+)
+
+// probe probes the components of the karmada and returns their health conditions.
+func (ctrl *KarmadaHealthController) probe(ctx context.Context, karmada *installv1alpha1.Karmada) []metav1.Condition {
+	apiServer, etcd := ctrl.probeAPIServer(ctx, karmada)
+	webhook := ctrl.probeWebhook(ctx, karmada)
+	return []metav1.Condition{apiServer, etcd, webhook}
+}
+
+// probeAPIServer probes the readiness of the karmada-apiserver and the health of its etcd through the
+// readyz endpoints of the karmada-apiserver. The etcd is unknown if the karmada-apiserver is unreachable.
+func (ctrl *KarmadaHealthController) probeAPIServer(ctx context.Context, karmada *installv1alpha1.Karmada) (metav1.Condition, metav1.Condition) {
+	apiServer := metav1.Condition{Type: installv1alpha1.KarmadaConditionAPIServerHealthy}
+	etcd := metav1.Condition{Type: installv1alpha1.KarmadaConditionEtcdHealthy}
+
+	config, err := utilresource.GetClientConfigFromKubeConfigSecret(ctrl.client, karmada.Namespace, "karmada-kubeconfig", userAgentName)
+	if err == nil {
+		config.Timeout = probeTimeout
+		var client kubernetes.Interface
+		if client, err = kubernetes.NewForConfig(config); err == nil {
+			return probeReadyz(ctx, client.Discovery().RESTClient(), apiServer, etcd)
+		}
+	}
+	apiServer.Status, apiServer.Reason, apiServer.Message = metav1.ConditionUnknown, "KubeconfigUnavailable", err.Error()
+	etcd.Status, etcd.Reason, etcd.Message = metav1.ConditionUnknown, "KubeconfigUnavailable", err.Error()
+	return apiServer, etcd
+}
+
+func probeReadyz(ctx context.Context, client rest.Interface, apiServer, etcd metav1.Condition) (metav1.Condition, metav1.Condition) {
+	code, body, err := get(ctx, client, "/readyz")
+	switch {
+	case code == 0:
+		apiServer.Status, apiServer.Reason, apiServer.Message = metav1.ConditionFalse, "Unreachable", err.Error()
+		etcd.Status, etcd.Reason, etcd.Message = metav1.ConditionUnknown, "APIServerUnreachable", "karmada-apiserver is unreachable"
+		return apiServer, etcd
+	case err != nil:
+		apiServer.Status, apiServer.Reason, apiServer.Message = metav1.ConditionFalse, "NotReady", failedChecks(body, err)
+	default:
+		apiServer.Status, apiServer.Reason, apiServer.Message = metav1.ConditionTrue, "Ready", "karmada-apiserver is ready"
+	}
+
+	code, body, err = get(ctx, client, "/readyz/etcd")
+	switch {
+	case code == 0:
+		etcd.Status, etcd.Reason, etcd.Message = metav1.ConditionUnknown, "APIServerUnreachable", err.Error()
+	case err != nil:
+		etcd.Status, etcd.Reason, etcd.Message = metav1.ConditionFalse, "Unhealthy", failedChecks(body, err)
+	default:
+		etcd.Status, etcd.Reason, etcd.Message = metav1.ConditionTrue, "Healthy", "etcd is healthy"
+	}
+	return apiServer, etcd
+}
+
+// get gets the given path of the karmada-apiserver with verbose output, and returns the status code
+// and the body of the response. The status code is 0 if there is no response.
+func get(ctx context.Context, client rest.Interface, path string) (int, string, error) {
+	var code int
+	body, err := client.Get().AbsPath(path).Param("verbose", "true").Do(ctx).StatusCode(&code).Raw()
+	return code, string(body), err
+}
+
+// failedChecks returns the failed checks listed by the verbose output of a readyz endpoint,
+// or the error if there are none.
+func failedChecks(body string, err error) string {
+	var failed []string
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "[-]") {
+			failed = append(failed, strings.TrimPrefix(line, "[-]"))
+		}
+	}
+	if len(failed) == 0 {
+		return err.Error()
+	}
+	return strings.Join(failed, "; ")
+}
+
+// probeWebhook checks whether the karmada-webhook is reachable through its service with a serving
+// certificate which is trusted by the karmada CA, the same way the karmada-apiserver calls it.
+func (ctrl *KarmadaHealthController) probeWebhook(ctx context.Context, karmada *installv1alpha1.Karmada) metav1.Condition {
+	condition := metav1.Condition{Type: installv1alpha1.KarmadaConditionWebhookHealthy}
+
+	certSecret, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, "karmada-cert", metav1.GetOptions{})
+	if err != nil {
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionUnknown, "CertificatesUnavailable", err.Error()
+		return condition
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(certSecret.Data["ca.crt"]) {
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionUnknown, "CertificatesUnavailable", "failed to parse the karmada CA"
+		return condition
+	}
+
+	host := fmt.Sprintf("%s.%s.svc", constants.KarmadaComponentWebhook, karmada.Namespace)
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: probeTimeout},
+		Config:    &tls.Config{RootCAs: roots, ServerName: host, MinVersion: tls.VersionTLS12},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, "443"))
+	if err != nil {
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionFalse, "Unreachable", err.Error()
+		return condition
+	}
+	conn.Close()
+	condition.Status, condition.Reason, condition.Message = metav1.ConditionTrue, "Reachable", "karmada-webhook is reachable"
+	return condition
+}
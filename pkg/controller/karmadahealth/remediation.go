@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karmadahealth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	karmadacontroller "github.com/carlory/firefly/pkg/controller/karmada"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+)
+
+// crashLoopRestartThreshold is the number of restarts after which a crash-looping container is
+// remediated. Since the restarted pod starts over from no restarts, it also limits how often a
+// component which keeps crashing is restarted.
+const crashLoopRestartThreshold = 5
+
+// checkCrashLoopingComponents emits an event for each crash-looping container of the karmada, and
+// deletes its pod if remediate is true, so that it's recreated by its workload without the back-off.
+func (ctrl *KarmadaHealthController) checkCrashLoopingComponents(ctx context.Context, karmada *installv1alpha1.Karmada, remediate bool) error {
+	pods, err := ctrl.client.CoreV1().Pods(karmada.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{constants.KarmadaInstanceLabel: karmada.Name}).String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		container := crashLoopingContainer(pod)
+		if container == nil {
+			continue
+		}
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeWarning, "ComponentCrashLooping", "Container %s of pod %s is crash-looping after %d restarts", container.Name, pod.Name, container.RestartCount)
+		if !remediate || !pod.DeletionTimestamp.IsZero() || metav1.GetControllerOf(pod) == nil {
+			continue
+		}
+
+		klog.InfoS("Restarting crash-looping component", "karmada", klog.KObj(karmada), "pod", klog.KObj(pod), "container", container.Name)
+		err := ctrl.client.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name}, err)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "RestartingComponent", "Deleted pod %s to restart its crash-looping container %s", pod.Name, container.Name)
+	}
+	return nil
+}
+
+// crashLoopingContainer returns the status of the first container of the pod which is backing off
+// from crashing and has been restarted at least crashLoopRestartThreshold times.
+func crashLoopingContainer(pod *corev1.Pod) *corev1.ContainerStatus {
+	for i := range pod.Status.ContainerStatuses {
+		status := &pod.Status.ContainerStatuses[i]
+		if waiting := status.State.Waiting; waiting != nil && waiting.Reason == "CrashLoopBackOff" && status.RestartCount >= crashLoopRestartThreshold {
+			return status
+		}
+	}
+	return nil
+}
+
+// ensureKubeconfigSecret re-renders the Secret karmada-kubeconfig from the karmada certificates if it's
+// missing or its kubeconfig doesn't match them. The Secret is created only once by the karmada controller,
+// so it's never repaired otherwise.
+func (ctrl *KarmadaHealthController) ensureKubeconfigSecret(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	certSecret, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, "karmada-cert", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	problem := "it's missing"
+	secret, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, "karmada-kubeconfig", metav1.GetOptions{})
+	if err == nil {
+		problem = kubeconfigProblem(secret, certSecret)
+		if problem == "" {
+			return nil
+		}
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	desired, err := karmadacontroller.KarmadaKubeconfigSecret(karmada, certSecret.Data["ca.crt"], certSecret.Data["karmada.key"], certSecret.Data["karmada.crt"])
+	if err != nil {
+		return err
+	}
+	if _, err := clientutil.CreateOrUpdateSecret(ctx, ctrl.client, desired); err != nil {
+		return err
+	}
+	ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "KubeconfigRerendered", "Re-rendered the Secret karmada-kubeconfig because %s", problem)
+	return nil
+}
+
+// kubeconfigProblem returns why the kubeconfig held by the secret can't be used with the karmada
+// certificates held by certSecret, or an empty string if it's fine.
+func kubeconfigProblem(secret, certSecret *corev1.Secret) string {
+	config, err := clientcmd.Load(secret.Data["kubeconfig"])
+	if err != nil {
+		return fmt.Sprintf("its kubeconfig can't be parsed: %v", err)
+	}
+	kubeContext, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return "the current context of its kubeconfig is not found"
+	}
+	cluster, authInfo := config.Clusters[kubeContext.Cluster], config.AuthInfos[kubeContext.AuthInfo]
+	if cluster == nil || authInfo == nil {
+		return "the cluster or the user of its kubeconfig is not found"
+	}
+	if !bytes.Equal(cluster.CertificateAuthorityData, certSecret.Data["ca.crt"]) {
+		return "the CA of its kubeconfig doesn't match the karmada CA"
+	}
+	if !bytes.Equal(authInfo.ClientCertificateData, certSecret.Data["karmada.crt"]) || !bytes.Equal(authInfo.ClientKeyData, certSecret.Data["karmada.key"]) {
+		return "the client certificate of its kubeconfig doesn't match the karmada certificates"
+	}
+	return ""
+}
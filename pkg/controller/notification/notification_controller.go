@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/notification"
+)
+
+// conditionReady is the type of the Ready condition, which is set by all the installations.
+const conditionReady = "Ready"
+
+// NotificationController alerts when an installation degrades, that is its Ready condition turns false
+// while its spec is unchanged, and when it recovers.
+type NotificationController struct {
+	informerSynced []cache.InformerSynced
+
+	lock sync.Mutex
+	// degraded is the installations which are alerted as degraded and not recovered yet.
+	degraded map[audit.ObjectReference]bool
+}
+
+// NewNotificationController returns a new *NotificationController.
+func NewNotificationController(
+	karmadaInformer installinformers.KarmadaInformer,
+	clusterpediaInformer installinformers.ClusterpediaInformer,
+	addonInformer installinformers.AddonInformer,
+	clusterRegistrationInformer installinformers.ClusterRegistrationInformer,
+	submarinerInformer installinformers.SubmarinerInformer,
+	secretSyncInformer installinformers.SecretSyncInformer) *NotificationController {
+	ctrl := &NotificationController{
+		degraded: map[audit.ObjectReference]bool{},
+	}
+
+	for _, informer := range []cache.SharedIndexInformer{
+		karmadaInformer.Informer(),
+		clusterpediaInformer.Informer(),
+		addonInformer.Informer(),
+		clusterRegistrationInformer.Informer(),
+		submarinerInformer.Informer(),
+		secretSyncInformer.Informer(),
+	} {
+		informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: ctrl.updateInstallation,
+			DeleteFunc: ctrl.deleteInstallation,
+		})
+		ctrl.informerSynced = append(ctrl.informerSynced, informer.HasSynced)
+	}
+	return ctrl
+}
+
+// Run begins watching the installations.
+func (ctrl *NotificationController) Run(ctx context.Context) {
+	klog.InfoS("Starting notification controller")
+	defer klog.InfoS("Shutting down notification controller")
+
+	if !cache.WaitForNamedCacheSync("notification", ctx.Done(), ctrl.informerSynced...) {
+		return
+	}
+	<-ctx.Done()
+}
+
+func (ctrl *NotificationController) updateInstallation(old, cur interface{}) {
+	oldObj, ok := old.(runtime.Object)
+	if !ok {
+		return
+	}
+	curObj, ok := cur.(runtime.Object)
+	if !ok {
+		return
+	}
+	oldAccessor, err := meta.Accessor(oldObj)
+	if err != nil {
+		return
+	}
+	curAccessor, err := meta.Accessor(curObj)
+	if err != nil {
+		return
+	}
+	oldReady := meta.FindStatusCondition(conditionsOf(oldObj), conditionReady)
+	curReady := meta.FindStatusCondition(conditionsOf(curObj), conditionReady)
+	if oldReady == nil || curReady == nil {
+		return
+	}
+
+	ref := audit.Reference(curObj)
+	switch {
+	case oldReady.Status == metav1.ConditionTrue && curReady.Status == metav1.ConditionFalse:
+		// a change of the spec or the deletion makes the installation progress rather than degrade.
+		if curAccessor.GetGeneration() != oldAccessor.GetGeneration() || curAccessor.GetDeletionTimestamp() != nil {
+			return
+		}
+		ctrl.lock.Lock()
+		ctrl.degraded[ref] = true
+		ctrl.lock.Unlock()
+		notification.Notify(notification.Alert{
+			Reason:  notification.Degraded,
+			Object:  ref,
+			Message: curReady.Reason + ": " + curReady.Message,
+		})
+	case oldReady.Status != metav1.ConditionTrue && curReady.Status == metav1.ConditionTrue:
+		ctrl.lock.Lock()
+		degraded := ctrl.degraded[ref]
+		delete(ctrl.degraded, ref)
+		ctrl.lock.Unlock()
+		if degraded {
+			notification.Notify(notification.Alert{
+				Reason:  notification.Recovered,
+				Object:  ref,
+				Message: curReady.Message,
+			})
+		}
+	}
+}
+
+func (ctrl *NotificationController) deleteInstallation(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if o, ok := obj.(runtime.Object); ok {
+		ctrl.lock.Lock()
+		delete(ctrl.degraded, audit.Reference(o))
+		ctrl.lock.Unlock()
+	}
+}
+
+// conditionsOf returns the status conditions of the installation.
+func conditionsOf(obj runtime.Object) []metav1.Condition {
+	switch o := obj.(type) {
+	case *installv1alpha1.Karmada:
+		return o.Status.Conditions
+	case *installv1alpha1.Clusterpedia:
+		return o.Status.Conditions
+	case *installv1alpha1.Addon:
+		return o.Status.Conditions
+	case *installv1alpha1.ClusterRegistration:
+		return o.Status.Conditions
+	case *installv1alpha1.Submariner:
+		return o.Status.Conditions
+	case *installv1alpha1.SecretSync:
+		return o.Status.Conditions
+	}
+	return nil
+}
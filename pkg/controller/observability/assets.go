@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/monitoring"
+)
+
+// GrafanaDashboardLabel is the label by which the dashboard sidecar of Grafana discovers the
+// configmaps holding dashboards.
+const GrafanaDashboardLabel = "grafana_dashboard"
+
+var (
+	//go:embed dashboards/*.json
+	dashboards embed.FS
+
+	//go:embed rules/*.yaml
+	rules embed.FS
+)
+
+// asset renders the embedded file for the installation. The placeholders are substituted
+// literally, since both Grafana and Prometheus have template syntaxes of their own.
+func asset(fs embed.FS, path string, inst *installation) (string, error) {
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	replacer := strings.NewReplacer(
+		"${FIREFLY_NAMESPACE}", inst.obj.GetNamespace(),
+		"${FIREFLY_NAME}", inst.obj.GetName(),
+		"${FIREFLY_UID}", string(inst.obj.GetUID()),
+	)
+	return replacer.Replace(string(data)), nil
+}
+
+// dashboardConfigMap returns the configmap which holds the Grafana dashboard of the installation.
+func dashboardConfigMap(inst *installation) (*unstructured.Unstructured, error) {
+	dashboard, err := asset(dashboards, fmt.Sprintf("dashboards/%s.json", inst.kind), inst)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"data": map[string]interface{}{
+			inst.kind + ".json": dashboard,
+		},
+	}}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace(inst.obj.GetNamespace())
+	obj.SetName(util.ComponentName(inst.kind+"-grafana-dashboard", inst.obj.GetName()))
+	obj.SetLabels(mergeLabels(inst.monitoring.Labels, map[string]string{GrafanaDashboardLabel: "1"}))
+	return obj, nil
+}
+
+// prometheusRule returns the PrometheusRule which holds the alerts of the installation.
+func prometheusRule(inst *installation) (*unstructured.Unstructured, error) {
+	data, err := asset(rules, fmt.Sprintf("rules/%s.yaml", inst.kind), inst)
+	if err != nil {
+		return nil, err
+	}
+	spec := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(data), &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse the rules of %s: %v", inst.kind, err)
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetGroupVersionKind(monitoring.PrometheusRuleGVK)
+	obj.SetNamespace(inst.obj.GetNamespace())
+	obj.SetName(util.ComponentName(inst.kind+"-rules", inst.obj.GetName()))
+	if len(inst.monitoring.Labels) > 0 {
+		obj.SetLabels(inst.monitoring.Labels)
+	}
+	return obj, nil
+}
+
+func mergeLabels(labels ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, l := range labels {
+		for k, v := range l {
+			merged[k] = v
+		}
+	}
+	return merged
+}
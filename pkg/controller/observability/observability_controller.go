@@ -0,0 +1,356 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/prometheus/ratelimiter"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
+	installlisters "github.com/carlory/firefly/pkg/generated/listers/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/apply"
+	"github.com/carlory/firefly/pkg/util/audit"
+	chartutil "github.com/carlory/firefly/pkg/util/chart"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	"github.com/carlory/firefly/pkg/util/notification"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
+)
+
+const (
+	// maxRetries is the number of times an installation will be retried before it is dropped out of the queue.
+	// With the current rate-limiter in use (5ms*2^(maxRetries-1)) the following numbers represent the
+	// sequence of delays between successive queuings of an installation.
+	//
+	// 5ms, 10ms, 20ms, 40ms, 80ms, 160ms, 320ms, 640ms, 1.3s, 2.6s, 5.1s, 10.2s, 20.4s, 41s, 82s
+	maxRetries = 15
+
+	kindKarmada      = "karmada"
+	kindClusterpedia = "clusterpedia"
+)
+
+// installation is a karmada or clusterpedia whose observability is provisioned.
+type installation struct {
+	// kind is the lowercase kind of the installation, which names its assets.
+	kind string
+	gvk  schema.GroupVersionKind
+	obj  interface {
+		metav1.Object
+		runtime.Object
+	}
+	paused     bool
+	monitoring installv1alpha1.Monitoring
+}
+
+// NewObservabilityController returns a new *Controller.
+func NewObservabilityController(
+	client clientset.Interface,
+	karmadaInformer installinformers.KarmadaInformer,
+	clusterpediaInformer installinformers.ClusterpediaInformer,
+	restConfig *rest.Config,
+	prometheusRulesAvailable bool) (*ObservabilityController, error) {
+	broadcaster := record.NewBroadcaster()
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "observability-controller"})
+
+	if client != nil && client.CoreV1().RESTClient().GetRateLimiter() != nil {
+		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("observability_controller", client.CoreV1().RESTClient().GetRateLimiter())
+	}
+
+	applier, err := apply.NewApplier(restConfig, "firefly-observability-controller")
+	if err != nil {
+		return nil, err
+	}
+
+	ctrl := &ObservabilityController{
+		client:              client,
+		karmadasLister:      karmadaInformer.Lister(),
+		karmadasSynced:      karmadaInformer.Informer().HasSynced,
+		clusterpediasLister: clusterpediaInformer.Lister(),
+		clusterpediasSynced: clusterpediaInformer.Informer().HasSynced,
+		queue:               workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "observability"),
+		workerLoopPeriod:    time.Second,
+		eventBroadcaster:    broadcaster,
+		eventRecorder:       recorder,
+		applier:             applier,
+
+		prometheusRulesAvailable: prometheusRulesAvailable,
+	}
+
+	karmadaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.enqueueFunc(kindKarmada),
+		UpdateFunc: func(old, cur interface{}) { ctrl.enqueueFunc(kindKarmada)(cur) },
+	})
+	clusterpediaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.enqueueFunc(kindClusterpedia),
+		UpdateFunc: func(old, cur interface{}) { ctrl.enqueueFunc(kindClusterpedia)(cur) },
+	})
+
+	return ctrl, nil
+}
+
+// ObservabilityController provisions the Grafana dashboards and the Prometheus alerts of the
+// monitored karmadas and clusterpedias. The dashboards are kept by configmaps, which are picked
+// up by the dashboard sidecar of Grafana, and the alerts by PrometheusRules if the Prometheus
+// Operator is installed on the host cluster.
+type ObservabilityController struct {
+	client           clientset.Interface
+	eventBroadcaster record.EventBroadcaster
+	eventRecorder    record.EventRecorder
+
+	karmadasLister installlisters.KarmadaLister
+	karmadasSynced cache.InformerSynced
+
+	clusterpediasLister installlisters.ClusterpediaLister
+	clusterpediasSynced cache.InformerSynced
+
+	// applier applies the dashboards and the alerts.
+	applier *apply.Applier
+
+	// prometheusRulesAvailable indicates whether the PrometheusRule CRD of the Prometheus Operator
+	// is installed on the host cluster.
+	prometheusRulesAvailable bool
+
+	// Installations that need to be updated. A channel is inappropriate here,
+	// because it allows an installation to be inserted multiple times and be
+	// processed more than necessary.
+	queue workqueue.RateLimitingInterface
+
+	// workerLoopPeriod is the time between worker runs. The workers process the queue of installation changes.
+	workerLoopPeriod time.Duration
+}
+
+// Run will not return until stopCh is closed. workers determines how many
+// installations will be handled in parallel.
+func (ctrl *ObservabilityController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+
+	// Start events processing pipeline.
+	ctrl.eventBroadcaster.StartStructuredLogging(0)
+	ctrl.eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: ctrl.client.CoreV1().Events("")})
+	defer ctrl.eventBroadcaster.Shutdown()
+
+	defer ctrl.queue.ShutDown()
+
+	klog.Infof("Starting observability controller")
+	defer klog.Infof("Shutting down observability controller")
+
+	if !cache.WaitForNamedCacheSync("observability", ctx.Done(), ctrl.karmadasSynced, ctrl.clusterpediasSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, ctrl.worker, ctrl.workerLoopPeriod)
+	}
+	<-ctx.Done()
+}
+
+// worker runs a worker thread that just dequeues items, processes them, and
+// marks them done. You may run as many of these in parallel as you wish; the
+// workqueue guarantees that they will not end up processing the same installation
+// at the same time.
+func (ctrl *ObservabilityController) worker(ctx context.Context) {
+	for ctrl.processNextWorkItem(ctx) {
+	}
+}
+
+func (ctrl *ObservabilityController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := ctrl.queue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.queue.Done(key)
+
+	ctx, span := tracingutil.StartReconcile(ctx, "observability", key.(string))
+	err := ctrl.syncInstallation(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.handleErr(err, key)
+
+	return true
+}
+
+// enqueueFunc returns a handler which enqueues the installations of the given kind. Deletions
+// are not handled, since the dashboards and the alerts are garbage collected with their owners.
+func (ctrl *ObservabilityController) enqueueFunc(kind string) func(obj interface{}) {
+	return func(obj interface{}) {
+		key, err := cache.MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+		ctrl.queue.Add(kind + "/" + key)
+	}
+}
+
+// splitKey returns the kind, namespace and name of the installation from the key.
+func splitKey(key string) (kind, namespace, name string, err error) {
+	parts := strings.SplitN(key, "/", 2)
+	if len(parts) != 2 {
+		return "", "", "", fmt.Errorf("unexpected key format: %q", key)
+	}
+	namespace, name, err = cache.SplitMetaNamespaceKey(parts[1])
+	return parts[0], namespace, name, err
+}
+
+func (ctrl *ObservabilityController) handleErr(err error, key interface{}) {
+	if err == nil || errors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+		ctrl.queue.Forget(key)
+		return
+	}
+
+	kind, ns, name, keyErr := splitKey(key.(string))
+	if keyErr != nil {
+		klog.ErrorS(err, "Failed to split observability cache key", "cacheKey", key)
+	}
+
+	notification.ReconcileFailed("observability", audit.ObjectReference{Kind: kind, Namespace: ns, Name: name}, ctrl.queue.NumRequeues(key)+1, err)
+	if ctrl.queue.NumRequeues(key) < maxRetries {
+		klog.V(2).InfoS("Error syncing observability, retrying", kind, klog.KRef(ns, name), "err", err)
+		ctrl.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	klog.V(2).InfoS("Dropping observability out of the queue", kind, klog.KRef(ns, name), "err", err)
+	ctrl.queue.Forget(key)
+}
+
+// getInstallation returns the installation of the given kind from the listers.
+func (ctrl *ObservabilityController) getInstallation(kind, namespace, name string) (*installation, error) {
+	switch kind {
+	case kindKarmada:
+		karmada, err := ctrl.karmadasLister.Karmadas(namespace).Get(name)
+		if err != nil {
+			return nil, err
+		}
+		return &installation{
+			kind:       kind,
+			gvk:        installv1alpha1.SchemeGroupVersion.WithKind("Karmada"),
+			obj:        karmada,
+			paused:     karmada.Spec.Paused,
+			monitoring: karmada.Spec.Monitoring,
+		}, nil
+	case kindClusterpedia:
+		clusterpedia, err := ctrl.clusterpediasLister.Clusterpedias(namespace).Get(name)
+		if err != nil {
+			return nil, err
+		}
+		return &installation{
+			kind:       kind,
+			gvk:        installv1alpha1.SchemeGroupVersion.WithKind("Clusterpedia"),
+			obj:        clusterpedia,
+			paused:     clusterpedia.Spec.Paused,
+			monitoring: clusterpedia.Spec.Monitoring,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown kind %q", kind)
+}
+
+func (ctrl *ObservabilityController) syncInstallation(ctx context.Context, key string) error {
+	kind, namespace, name, err := splitKey(key)
+	if err != nil {
+		klog.ErrorS(err, "Failed to split observability cache key", "cacheKey", key)
+		return err
+	}
+
+	startTime := time.Now()
+	klog.V(4).InfoS("Started syncing observability", kind, klog.KRef(namespace, name), "startTime", startTime)
+	defer func() {
+		klog.V(4).InfoS("Finished syncing observability", kind, klog.KRef(namespace, name), "duration", time.Since(startTime))
+	}()
+
+	inst, err := ctrl.getInstallation(kind, namespace, name)
+	if errors.IsNotFound(err) {
+		klog.V(2).InfoS("Installation has been deleted", kind, klog.KRef(namespace, name))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !inst.obj.GetDeletionTimestamp().IsZero() {
+		// The dashboards and the alerts are garbage collected with the installation.
+		return nil
+	}
+	if inst.paused {
+		klog.V(2).InfoS("Installation is paused, skip syncing observability", kind, klog.KObj(inst.obj))
+		return nil
+	}
+
+	ctx = audit.WithTrigger(ctx, inst.obj)
+	ctx = dryrun.ForObject(ctx, inst.obj)
+
+	if err := ctrl.EnsureObservability(ctx, inst); err != nil {
+		ctrl.eventRecorder.Eventf(inst.obj, corev1.EventTypeWarning, "ObservabilityFailed", "Failed to provision the dashboards and alerts: %v", err)
+		return err
+	}
+	return nil
+}
+
+// EnsureObservability applies the dashboards and the alerts of the installation if it's monitored,
+// otherwise deletes them.
+func (ctrl *ObservabilityController) EnsureObservability(ctx context.Context, inst *installation) error {
+	objs, err := ctrl.observabilityObjects(inst)
+	if err != nil {
+		return err
+	}
+	if inst.monitoring.Enabled {
+		return chartutil.Apply(ctx, ctrl.applier, inst.obj, inst.gvk, objs)
+	}
+	for _, obj := range objs {
+		if err := ctrl.applier.Delete(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// observabilityObjects returns the dashboards and the alerts of the installation. The alerts are
+// left out if the Prometheus Operator is not installed.
+func (ctrl *ObservabilityController) observabilityObjects(inst *installation) ([]*unstructured.Unstructured, error) {
+	dashboard, err := dashboardConfigMap(inst)
+	if err != nil {
+		return nil, err
+	}
+	objs := []*unstructured.Unstructured{dashboard}
+	if ctrl.prometheusRulesAvailable {
+		rule, err := prometheusRule(inst)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, rule)
+	}
+	return objs, nil
+}
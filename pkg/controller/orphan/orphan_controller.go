@@ -0,0 +1,333 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphan
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	karmadacontroller "github.com/carlory/firefly/pkg/controller/karmada"
+	fireflyclient "github.com/carlory/firefly/pkg/generated/clientset/versioned"
+	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
+	installlisters "github.com/carlory/firefly/pkg/generated/listers/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/util/audit"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
+)
+
+const (
+	// maxRetries is the number of times a resource will be retried before it is dropped out of the queue.
+	// With the current rate-limiter in use (5ms*2^(maxRetries-1)) the following numbers represent the
+	// sequence of delays between successive queuings of a resource.
+	//
+	// 5ms, 10ms, 20ms, 40ms, 80ms, 160ms, 320ms, 640ms, 1.3s, 2.6s, 5.1s, 10.2s, 20.4s, 41s, 82s
+	maxRetries = 15
+)
+
+// ManagedResources maps the namespaced resources of the host cluster which are checked for orphans to their kinds.
+var ManagedResources = map[schema.GroupVersionResource]string{
+	{Group: "apps", Version: "v1", Resource: "deployments"}:                     "Deployment",
+	{Group: "apps", Version: "v1", Resource: "statefulsets"}:                    "StatefulSet",
+	{Group: "", Version: "v1", Resource: "services"}:                            "Service",
+	{Group: "", Version: "v1", Resource: "secrets"}:                             "Secret",
+	{Group: "", Version: "v1", Resource: "serviceaccounts"}:                     "ServiceAccount",
+	{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"}:          "PodDisruptionBudget",
+	{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}: "HorizontalPodAutoscaler",
+	{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}:          "Ingress",
+	{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}:    "NetworkPolicy",
+}
+
+// ManagedSelector selects the host cluster resources managed by firefly.
+func ManagedSelector() labels.Selector {
+	return labels.SelectorFromSet(labels.Set{constants.ManagedByLabel: constants.ManagedByFirefly})
+}
+
+// NewOrphanController returns a new *Controller. The informers of the resources are expected
+// to be metadata-only informers of the resources selected by ManagedSelector.
+func NewOrphanController(
+	metadataClient metadata.Interface,
+	fireflyClient fireflyclient.Interface,
+	karmadaInformer installinformers.KarmadaInformer,
+	resourceInformers map[schema.GroupVersionResource]informers.GenericInformer) (*OrphanController, error) {
+	ctrl := &OrphanController{
+		metadataClient:   metadataClient,
+		fireflyClient:    fireflyClient,
+		karmadasLister:   karmadaInformer.Lister(),
+		karmadasSynced:   karmadaInformer.Informer().HasSynced,
+		resources:        make(map[schema.GroupResource]schema.GroupVersionResource, len(resourceInformers)),
+		listers:          make(map[schema.GroupVersionResource]cache.GenericLister, len(resourceInformers)),
+		queue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "orphan"),
+		workerLoopPeriod: time.Second,
+	}
+
+	karmadaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: ctrl.updateKarmada,
+		DeleteFunc: ctrl.deleteKarmada,
+	})
+
+	for gvr, informer := range resourceInformers {
+		gvr := gvr
+		ctrl.resources[gvr.GroupResource()] = gvr
+		ctrl.listers[gvr] = informer.Lister()
+		ctrl.resourcesSynced = append(ctrl.resourcesSynced, informer.Informer().HasSynced)
+		informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				ctrl.enqueue(gvr, obj)
+			},
+			UpdateFunc: func(old, cur interface{}) {
+				ctrl.enqueue(gvr, cur)
+			},
+		})
+	}
+
+	return ctrl, nil
+}
+
+// OrphanController deletes the host cluster resources managed by firefly whose install object, or
+// the component of the install object they belong to, no longer exists. Most of them are owned by
+// their install objects and collected by the garbage collector of kubernetes, this controller guards
+// against the leaks which are left behind when the spec of an install object shrinks, or the owner
+// references are missing.
+type OrphanController struct {
+	metadataClient metadata.Interface
+	fireflyClient  fireflyclient.Interface
+
+	karmadasLister installlisters.KarmadaLister
+	karmadasSynced cache.InformerSynced
+
+	// resources maps the group resources of the managed resources to their versions.
+	resources       map[schema.GroupResource]schema.GroupVersionResource
+	listers         map[schema.GroupVersionResource]cache.GenericLister
+	resourcesSynced []cache.InformerSynced
+
+	// Resources that need to be checked. A channel is inappropriate here,
+	// because it allows a resource to be inserted multiple times and be
+	// processed more than necessary.
+	queue workqueue.RateLimitingInterface
+
+	// workerLoopPeriod is the time between worker runs. The workers process the queue of resource changes.
+	workerLoopPeriod time.Duration
+}
+
+// Run will not return until stopCh is closed. workers determines how many
+// resources will be handled in parallel.
+func (ctrl *OrphanController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer ctrl.queue.ShutDown()
+
+	klog.Infof("Starting orphan controller")
+	defer klog.Infof("Shutting down orphan controller")
+
+	if !cache.WaitForNamedCacheSync("orphan", ctx.Done(), append(ctrl.resourcesSynced, ctrl.karmadasSynced)...) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, ctrl.worker, ctrl.workerLoopPeriod)
+	}
+	<-ctx.Done()
+}
+
+// worker runs a worker thread that just dequeues items, processes them, and
+// marks them done. You may run as many of these in parallel as you wish; the
+// workqueue guarantees that they will not end up processing the same resource
+// at the same time.
+func (ctrl *OrphanController) worker(ctx context.Context) {
+	for ctrl.processNextWorkItem(ctx) {
+	}
+}
+
+func (ctrl *OrphanController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := ctrl.queue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.queue.Done(key)
+
+	ctx, span := tracingutil.StartReconcile(ctx, "orphan", key.(string))
+	err := ctrl.syncResource(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.handleErr(err, key)
+
+	return true
+}
+
+// updateKarmada enqueues the resources of the karmada when its spec is changed, since some of its
+// components may be disabled.
+func (ctrl *OrphanController) updateKarmada(old, cur interface{}) {
+	oldKarmada := old.(*installv1alpha1.Karmada)
+	curKarmada := cur.(*installv1alpha1.Karmada)
+	if oldKarmada.Generation == curKarmada.Generation {
+		return
+	}
+	klog.V(4).InfoS("Updating karmada", "karmada", klog.KObj(oldKarmada))
+	ctrl.enqueueKarmadaResources(curKarmada)
+}
+
+func (ctrl *OrphanController) deleteKarmada(obj interface{}) {
+	karmada, ok := obj.(*installv1alpha1.Karmada)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("couldn't get object from tombstone %#v", obj))
+			return
+		}
+		karmada, ok = tombstone.Obj.(*installv1alpha1.Karmada)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("tombstone contained object that is not a Karmada %#v", obj))
+			return
+		}
+	}
+	klog.V(4).InfoS("Deleting karmada", "karmada", klog.KObj(karmada))
+	ctrl.enqueueKarmadaResources(karmada)
+}
+
+// enqueueKarmadaResources enqueues all managed resources which belong to the karmada.
+func (ctrl *OrphanController) enqueueKarmadaResources(karmada *installv1alpha1.Karmada) {
+	selector := labels.SelectorFromSet(labels.Set{constants.KarmadaInstanceLabel: karmada.Name})
+	for gvr, lister := range ctrl.listers {
+		objs, err := lister.ByNamespace(karmada.Namespace).List(selector)
+		if err != nil {
+			utilruntime.HandleError(err)
+			continue
+		}
+		for _, obj := range objs {
+			ctrl.enqueue(gvr, obj)
+		}
+	}
+}
+
+// enqueue adds the resource to the queue by the key in the form of resource.group/namespace/name.
+func (ctrl *OrphanController) enqueue(gvr schema.GroupVersionResource, obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.Add(gvr.GroupResource().String() + "/" + key)
+}
+
+func (ctrl *OrphanController) handleErr(err error, key interface{}) {
+	if err == nil {
+		ctrl.queue.Forget(key)
+		return
+	}
+
+	if ctrl.queue.NumRequeues(key) < maxRetries {
+		klog.V(2).InfoS("Error syncing resource, retrying", "resource", key, "err", err)
+		ctrl.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	klog.V(2).InfoS("Dropping resource out of the queue", "resource", key, "err", err)
+	ctrl.queue.Forget(key)
+}
+
+func (ctrl *OrphanController) syncResource(ctx context.Context, key string) error {
+	parts := strings.SplitN(key, "/", 3)
+	if len(parts) != 3 {
+		klog.ErrorS(nil, "Invalid resource key", "cacheKey", key)
+		return nil
+	}
+	gvr, ok := ctrl.resources[schema.ParseGroupResource(parts[0])]
+	if !ok {
+		klog.ErrorS(nil, "Unknown resource", "cacheKey", key)
+		return nil
+	}
+	namespace, name := parts[1], parts[2]
+
+	startTime := time.Now()
+	klog.V(4).InfoS("Started syncing resource", "resource", gvr.Resource, "object", klog.KRef(namespace, name), "startTime", startTime)
+	defer func() {
+		klog.V(4).InfoS("Finished syncing resource", "resource", gvr.Resource, "object", klog.KRef(namespace, name), "duration", time.Since(startTime))
+	}()
+
+	obj, err := ctrl.listers[gvr].ByNamespace(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	accessor := obj.(*metav1.PartialObjectMetadata)
+	if !accessor.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	reason, err := ctrl.orphaned(ctx, accessor)
+	if err != nil || reason == "" {
+		return err
+	}
+
+	klog.InfoS("Deleting orphaned resource", "resource", gvr.Resource, "object", klog.KObj(accessor), "reason", reason)
+	uid := accessor.UID
+	err = ctrl.metadataClient.Resource(gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &uid},
+	})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: ManagedResources[gvr], Namespace: namespace, Name: name}, err)
+	if errors.IsNotFound(err) || errors.IsConflict(err) {
+		// the resource is deleted or recreated in the meantime.
+		return nil
+	}
+	return err
+}
+
+// orphaned returns why the resource is orphaned, or an empty string if it's not. A resource is orphaned
+// if the karmada it belongs to no longer exists, or its component is disabled by the karmada. The absence
+// of the karmada is confirmed with the apiserver, since the cache may not have caught up with a new karmada.
+func (ctrl *OrphanController) orphaned(ctx context.Context, obj *metav1.PartialObjectMetadata) (string, error) {
+	karmadaName := obj.Labels[constants.KarmadaInstanceLabel]
+	if karmadaName == "" {
+		return "", nil
+	}
+
+	karmada, err := ctrl.karmadasLister.Karmadas(obj.Namespace).Get(karmadaName)
+	if errors.IsNotFound(err) {
+		karmada, err = ctrl.fireflyClient.InstallV1alpha1().Karmadas(obj.Namespace).Get(ctx, karmadaName, metav1.GetOptions{})
+	}
+	if errors.IsNotFound(err) {
+		return fmt.Sprintf("karmada %s no longer exists", karmadaName), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if !karmada.DeletionTimestamp.IsZero() {
+		// the resources are removed along with the karmada.
+		return "", nil
+	}
+	if karmadacontroller.ComponentDisabled(karmada, obj.Name) {
+		return fmt.Sprintf("component %s is disabled by karmada %s", obj.Name, karmadaName), nil
+	}
+	return "", nil
+}
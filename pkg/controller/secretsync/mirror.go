@@ -0,0 +1,479 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsync
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	karmadaversioned "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/util/audit"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	utilresource "github.com/carlory/firefly/pkg/util/resource"
+)
+
+const (
+	// the user-agent name is used when talking to karmada-apiserver
+	userAgentName = "secretsync-controller"
+
+	// SecretSyncLabel is the label set on all mirrors of a secret sync.
+	// Its value is the name of the secret sync.
+	SecretSyncLabel = "secretsync.install.firefly.io/name"
+
+	// SecretSyncHashAnnotation is the annotation set on all mirrors of a secret sync.
+	// Its value is the hash of the data of the source object.
+	SecretSyncHashAnnotation = "secretsync.install.firefly.io/hash"
+
+	// karmadaKubeconfigSecretName is the name of the secret which holds the kubeconfig of karmada-apiserver.
+	karmadaKubeconfigSecretName = "karmada-kubeconfig"
+
+	secretKind    = "Secret"
+	configMapKind = "ConfigMap"
+)
+
+// sources are the objects of the host cluster selected by a secret sync.
+type sources struct {
+	secrets    []*corev1.Secret
+	configMaps []*corev1.ConfigMap
+}
+
+// targetClient talks to the karmada-apiserver targeted by a secret sync.
+type targetClient struct {
+	kubeClient    clientset.Interface
+	karmadaClient karmadaversioned.Interface
+	eventRecorder record.EventRecorder
+}
+
+func (ctrl *SecretSyncController) newTargetClient(secretSync *installv1alpha1.SecretSync) (*targetClient, error) {
+	clientConfig, err := utilresource.GetClientConfigFromKubeConfigSecret(ctrl.client, secretSync.Namespace, karmadaKubeconfigSecretName, userAgentName)
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := clientset.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	karmadaClient, err := karmadaversioned.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &targetClient{
+		kubeClient:    kubeClient,
+		karmadaClient: karmadaClient,
+		eventRecorder: ctrl.eventRecorder,
+	}, nil
+}
+
+// targetNamespace returns the namespace in the karmada which the objects of the secret sync are mirrored into.
+func targetNamespace(secretSync *installv1alpha1.SecretSync) string {
+	if secretSync.Spec.TargetNamespace != "" {
+		return secretSync.Spec.TargetNamespace
+	}
+	return secretSync.Namespace
+}
+
+// selects returns true if the object of the given kind is named or selected by the secret sync.
+func selects(secretSync *installv1alpha1.SecretSync, kind string, obj metav1.Object) bool {
+	names := secretSync.Spec.Secrets
+	if kind == configMapKind {
+		names = secretSync.Spec.ConfigMaps
+	}
+	for _, name := range names {
+		if name == obj.GetName() {
+			return true
+		}
+	}
+	if secretSync.Spec.Selector == nil {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(secretSync.Spec.Selector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(obj.GetLabels()))
+}
+
+// mirrors returns true if the object of the given kind is mirrored by the secret sync.
+func mirrors(secretSync *installv1alpha1.SecretSync, kind, name string) bool {
+	for _, object := range secretSync.Status.Objects {
+		if object.Kind == kind && object.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// selectSources returns the Secrets and the ConfigMaps in the namespace of the secret sync which are named
+// or selected by it, sorted by name. The service account tokens are never mirrored, since they are only
+// valid in the host cluster.
+func (ctrl *SecretSyncController) selectSources(secretSync *installv1alpha1.SecretSync) (*sources, error) {
+	secrets := map[string]*corev1.Secret{}
+	for _, name := range secretSync.Spec.Secrets {
+		secret, err := ctrl.secretsLister.Secrets(secretSync.Namespace).Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %s: %v", name, err)
+		}
+		secrets[name] = secret
+	}
+	configMaps := map[string]*corev1.ConfigMap{}
+	for _, name := range secretSync.Spec.ConfigMaps {
+		configMap, err := ctrl.configMapsLister.ConfigMaps(secretSync.Namespace).Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get configmap %s: %v", name, err)
+		}
+		configMaps[name] = configMap
+	}
+
+	if secretSync.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(secretSync.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector: %v", err)
+		}
+		selectedSecrets, err := ctrl.secretsLister.Secrets(secretSync.Namespace).List(selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, secret := range selectedSecrets {
+			secrets[secret.Name] = secret
+		}
+		selectedConfigMaps, err := ctrl.configMapsLister.ConfigMaps(secretSync.Namespace).List(selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, configMap := range selectedConfigMaps {
+			configMaps[configMap.Name] = configMap
+		}
+	}
+
+	s := &sources{}
+	for _, secret := range secrets {
+		if secret.Type == corev1.SecretTypeServiceAccountToken {
+			continue
+		}
+		s.secrets = append(s.secrets, secret)
+	}
+	for _, configMap := range configMaps {
+		s.configMaps = append(s.configMaps, configMap)
+	}
+	sort.Slice(s.secrets, func(i, j int) bool { return s.secrets[i].Name < s.secrets[j].Name })
+	sort.Slice(s.configMaps, func(i, j int) bool { return s.configMaps[i].Name < s.configMaps[j].Name })
+	return s, nil
+}
+
+// secretHash returns the hash of the type and the data of the secret.
+func secretHash(secret *corev1.Secret) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "type=%s;", secret.Type)
+	writeData(h, secret.Data)
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// configMapHash returns the hash of the data of the configmap.
+func configMapHash(configMap *corev1.ConfigMap) string {
+	h := sha256.New()
+	data := make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData))
+	for k, v := range configMap.Data {
+		data["data/"+k] = []byte(v)
+	}
+	for k, v := range configMap.BinaryData {
+		data["binaryData/"+k] = v
+	}
+	writeData(h, data)
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// writeData writes the data to the hash in the order of the keys, with the lengths of the keys and
+// the values, so that the hash doesn't depend on the order of the map.
+func writeData(h interface{ Write([]byte) (int, error) }, data map[string][]byte) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "%d:%s;%d:", len(k), k, len(data[k]))
+		h.Write(data[k])
+	}
+}
+
+// mirrorObjectMeta returns the metadata of the mirror of a source object of the secret sync.
+func mirrorObjectMeta(secretSync *installv1alpha1.SecretSync, name, hash string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        name,
+		Namespace:   targetNamespace(secretSync),
+		Labels:      map[string]string{SecretSyncLabel: secretSync.Name},
+		Annotations: map[string]string{SecretSyncHashAnnotation: hash},
+	}
+}
+
+// checkOwnership returns an error if the existing object isn't a mirror of the secret sync, so that
+// the objects created by the users in the karmada are never overwritten.
+func checkOwnership(secretSync *installv1alpha1.SecretSync, kind string, existing metav1.Object) error {
+	if existing.GetLabels()[SecretSyncLabel] != secretSync.Name {
+		return fmt.Errorf("%s %s/%s already exists in the karmada and isn't managed by the secret sync", kind, existing.GetNamespace(), existing.GetName())
+	}
+	return nil
+}
+
+// ensureNamespace creates the target namespace in the karmada if it doesn't exist.
+func (c *targetClient) ensureNamespace(ctx context.Context, name string) error {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	_, err := clientutil.CreateOrUpdateNamespace(ctx, c.kubeClient, namespace)
+	return err
+}
+
+// mirrorObjects mirrors the sources into the target namespace of the secret sync and returns the
+// mirrored objects. The mirrors whose hash is not changed are left as they are.
+func (c *targetClient) mirrorObjects(ctx context.Context, secretSync *installv1alpha1.SecretSync, s *sources) ([]installv1alpha1.SecretSyncObject, error) {
+	if err := c.ensureNamespace(ctx, targetNamespace(secretSync)); err != nil {
+		return nil, err
+	}
+
+	var objects []installv1alpha1.SecretSyncObject
+	for _, secret := range s.secrets {
+		object, err := c.mirrorSecret(ctx, secretSync, secret)
+		if err != nil {
+			return objects, err
+		}
+		objects = append(objects, object)
+	}
+	for _, configMap := range s.configMaps {
+		object, err := c.mirrorConfigMap(ctx, secretSync, configMap)
+		if err != nil {
+			return objects, err
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+func (c *targetClient) mirrorSecret(ctx context.Context, secretSync *installv1alpha1.SecretSync, source *corev1.Secret) (installv1alpha1.SecretSyncObject, error) {
+	object := installv1alpha1.SecretSyncObject{Kind: secretKind, Name: source.Name, Hash: secretHash(source)}
+	existing, err := c.kubeClient.CoreV1().Secrets(targetNamespace(secretSync)).Get(ctx, source.Name, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return object, err
+	}
+	if err == nil {
+		if err := checkOwnership(secretSync, secretKind, existing); err != nil {
+			return object, err
+		}
+		if existing.Annotations[SecretSyncHashAnnotation] == object.Hash {
+			return object, nil
+		}
+	}
+
+	mirror := &corev1.Secret{
+		ObjectMeta: mirrorObjectMeta(secretSync, source.Name, object.Hash),
+		Type:       source.Type,
+		Data:       source.Data,
+	}
+	result, err := clientutil.CreateOrUpdateSecret(ctx, c.kubeClient, mirror)
+	if err != nil {
+		return object, err
+	}
+	c.recordMirrorResult(ctx, secretSync, mirror, result)
+	return object, nil
+}
+
+func (c *targetClient) mirrorConfigMap(ctx context.Context, secretSync *installv1alpha1.SecretSync, source *corev1.ConfigMap) (installv1alpha1.SecretSyncObject, error) {
+	object := installv1alpha1.SecretSyncObject{Kind: configMapKind, Name: source.Name, Hash: configMapHash(source)}
+	existing, err := c.kubeClient.CoreV1().ConfigMaps(targetNamespace(secretSync)).Get(ctx, source.Name, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return object, err
+	}
+	if err == nil {
+		if err := checkOwnership(secretSync, configMapKind, existing); err != nil {
+			return object, err
+		}
+		if existing.Annotations[SecretSyncHashAnnotation] == object.Hash {
+			return object, nil
+		}
+	}
+
+	mirror := &corev1.ConfigMap{
+		ObjectMeta: mirrorObjectMeta(secretSync, source.Name, object.Hash),
+		Data:       source.Data,
+		BinaryData: source.BinaryData,
+	}
+	result, err := clientutil.CreateOrUpdateConfigMap(ctx, c.kubeClient, mirror)
+	if err != nil {
+		return object, err
+	}
+	c.recordMirrorResult(ctx, secretSync, mirror, result)
+	return object, nil
+}
+
+// recordMirrorResult emits an event on the secret sync once a mirror is created, or updated after
+// its source is rotated.
+func (c *targetClient) recordMirrorResult(ctx context.Context, secretSync *installv1alpha1.SecretSync, mirror metav1.Object, result clientutil.OperationResult) {
+	if dryrun.Enabled(ctx) {
+		return
+	}
+	switch result {
+	case clientutil.OperationResultCreated:
+		c.eventRecorder.Eventf(secretSync, corev1.EventTypeNormal, "Mirrored", "Mirrored %s into the karmada", mirror.GetName())
+	case clientutil.OperationResultUpdated:
+		c.eventRecorder.Eventf(secretSync, corev1.EventTypeNormal, "Rotated", "Mirrored the changed %s into the karmada", mirror.GetName())
+	}
+}
+
+// pruneObjects deletes the mirrors in old which are not in current from the given namespace of the karmada.
+// The mirrors which are changed to be owned by others are left as they are.
+func (c *targetClient) pruneObjects(ctx context.Context, secretSync *installv1alpha1.SecretSync, namespace string, old, current []installv1alpha1.SecretSyncObject) error {
+	keep := make(map[string]bool, len(current))
+	for _, object := range current {
+		keep[object.Kind+"/"+object.Name] = true
+	}
+
+	for _, object := range old {
+		if keep[object.Kind+"/"+object.Name] {
+			continue
+		}
+		var existing metav1.Object
+		var err error
+		switch object.Kind {
+		case secretKind:
+			existing, err = c.kubeClient.CoreV1().Secrets(namespace).Get(ctx, object.Name, metav1.GetOptions{})
+		case configMapKind:
+			existing, err = c.kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, object.Name, metav1.GetOptions{})
+		default:
+			continue
+		}
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if checkOwnership(secretSync, object.Kind, existing) != nil {
+			continue
+		}
+
+		switch object.Kind {
+		case secretKind:
+			err = c.kubeClient.CoreV1().Secrets(namespace).Delete(ctx, object.Name, metav1.DeleteOptions{})
+		case configMapKind:
+			err = c.kubeClient.CoreV1().ConfigMaps(namespace).Delete(ctx, object.Name, metav1.DeleteOptions{})
+		}
+		audit.RecordDeletion(ctx, audit.ObjectReference{Kind: object.Kind, Namespace: namespace, Name: object.Name}, err)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		klog.V(2).InfoS("Pruned mirror of secret sync", "kind", object.Kind, "object", klog.KRef(namespace, object.Name))
+	}
+	return nil
+}
+
+// mergeObjects returns the objects in current, and the ones in old which are not in current.
+func mergeObjects(old, current []installv1alpha1.SecretSyncObject) []installv1alpha1.SecretSyncObject {
+	merged := append([]installv1alpha1.SecretSyncObject{}, current...)
+	for _, object := range old {
+		found := false
+		for _, c := range current {
+			if c.Kind == object.Kind && c.Name == object.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, object)
+		}
+	}
+	return merged
+}
+
+// propagationPolicyName returns the name of the PropagationPolicy of the secret sync.
+func propagationPolicyName(secretSync *installv1alpha1.SecretSync) string {
+	return fmt.Sprintf("firefly-secretsync-%s", secretSync.Name)
+}
+
+// ensurePropagationPolicy propagates the mirrors of the secret sync to its target clusters.
+func (c *targetClient) ensurePropagationPolicy(ctx context.Context, secretSync *installv1alpha1.SecretSync, objects []installv1alpha1.SecretSyncObject) error {
+	namespace := targetNamespace(secretSync)
+	if len(secretSync.Spec.Clusters) == 0 || len(objects) == 0 {
+		return c.removePropagationPolicy(ctx, secretSync, namespace)
+	}
+
+	selectors := make([]policyv1alpha1.ResourceSelector, 0, len(objects))
+	for _, object := range objects {
+		selectors = append(selectors, policyv1alpha1.ResourceSelector{
+			APIVersion: "v1",
+			Kind:       object.Kind,
+			Namespace:  namespace,
+			Name:       object.Name,
+		})
+	}
+
+	policy := &policyv1alpha1.PropagationPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      propagationPolicyName(secretSync),
+			Namespace: namespace,
+			Labels:    map[string]string{SecretSyncLabel: secretSync.Name},
+		},
+		Spec: policyv1alpha1.PropagationSpec{
+			ResourceSelectors: selectors,
+			Placement: policyv1alpha1.Placement{
+				ClusterAffinity: &policyv1alpha1.ClusterAffinity{
+					ClusterNames: secretSync.Spec.Clusters,
+				},
+			},
+		},
+	}
+
+	policies := c.karmadaClient.PolicyV1alpha1().PropagationPolicies(namespace)
+	existing, err := policies.Get(ctx, policy.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = policies.Create(ctx, policy, metav1.CreateOptions{})
+		audit.RecordResult(ctx, audit.Create, policy, err)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	old := existing.DeepCopy()
+	existing.Labels = policy.Labels
+	existing.Spec = policy.Spec
+	updated, err := policies.Update(ctx, existing, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	audit.RecordUpdate(ctx, old, updated)
+	return nil
+}
+
+// removePropagationPolicy removes the PropagationPolicy of the secret sync from the given namespace.
+func (c *targetClient) removePropagationPolicy(ctx context.Context, secretSync *installv1alpha1.SecretSync, namespace string) error {
+	err := c.karmadaClient.PolicyV1alpha1().PropagationPolicies(namespace).Delete(ctx, propagationPolicyName(secretSync), metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "PropagationPolicy", Namespace: namespace, Name: propagationPolicyName(secretSync)}, err)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,548 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/prometheus/ratelimiter"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	fireflyclient "github.com/carlory/firefly/pkg/generated/clientset/versioned"
+	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
+	installlisters "github.com/carlory/firefly/pkg/generated/listers/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/coalesce"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	informerutil "github.com/carlory/firefly/pkg/util/informer"
+	"github.com/carlory/firefly/pkg/util/notification"
+	phaseutil "github.com/carlory/firefly/pkg/util/phase"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
+)
+
+const (
+	// maxRetries is the number of times a secret sync will be retried before it is dropped out of the queue.
+	// With the current rate-limiter in use (5ms*2^(maxRetries-1)) the following numbers represent the
+	// sequence of delays between successive queuings of a secret sync.
+	//
+	// 5ms, 10ms, 20ms, 40ms, 80ms, 160ms, 320ms, 640ms, 1.3s, 2.6s, 5.1s, 10.2s, 20.4s, 41s, 82s
+	maxRetries = 15
+
+	// name of the secretsync controller finalizer
+	SecretSyncControllerFinalizerName = "secretsync.install.firefly.io/finalizer"
+)
+
+// NewSecretSyncController returns a new *Controller.
+func NewSecretSyncController(
+	client clientset.Interface,
+	fireflyClient fireflyclient.Interface,
+	secretSyncInformer installinformers.SecretSyncInformer,
+	karmadaInformer installinformers.KarmadaInformer,
+	secretInformer coreinformers.SecretInformer,
+	configMapInformer coreinformers.ConfigMapInformer,
+	resyncPeriod time.Duration,
+	rateLimiter workqueue.RateLimiter) (*SecretSyncController, error) {
+	broadcaster := record.NewBroadcaster()
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "secretsync-controller"})
+
+	if client != nil && client.CoreV1().RESTClient().GetRateLimiter() != nil {
+		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("secretsync_controller", client.CoreV1().RESTClient().GetRateLimiter())
+	}
+
+	ctrl := &SecretSyncController{
+		client:            client,
+		fireflyClient:     fireflyClient,
+		secretSyncsLister: secretSyncInformer.Lister(),
+		secretSyncsSynced: secretSyncInformer.Informer().HasSynced,
+		karmadasLister:    karmadaInformer.Lister(),
+		karmadasSynced:    karmadaInformer.Informer().HasSynced,
+		secretsLister:     secretInformer.Lister(),
+		secretsSynced:     secretInformer.Informer().HasSynced,
+		configMapsLister:  configMapInformer.Lister(),
+		configMapsSynced:  configMapInformer.Informer().HasSynced,
+		queue:             coalesce.NewQueue(workqueue.NewNamedRateLimitingQueue(rateLimiter, "secretsync"), coalesce.DefaultWindow, coalesce.DefaultInterval),
+		workerLoopPeriod:  time.Second,
+		eventBroadcaster:  broadcaster,
+		eventRecorder:     recorder,
+	}
+
+	informerutil.AddEventHandler(secretSyncInformer.Informer(), cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.addSecretSync,
+		UpdateFunc: ctrl.updateSecretSync,
+		DeleteFunc: ctrl.deleteSecretSync,
+	}, resyncPeriod)
+
+	// Secret syncs are requeued when their target karmada is created or changed,
+	// so that a secret sync waiting for its karmada is mirrored as soon as possible.
+	karmadaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.addKarmada,
+		UpdateFunc: ctrl.updateKarmada,
+	})
+
+	// Secret syncs are requeued when their sources are changed, so that the rotated
+	// credentials are mirrored as soon as possible.
+	sourceHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.enqueueSecretSyncsForSource,
+		UpdateFunc: func(old, cur interface{}) { ctrl.enqueueSecretSyncsForSource(cur) },
+		DeleteFunc: ctrl.enqueueSecretSyncsForSource,
+	}
+	secretInformer.Informer().AddEventHandler(sourceHandler)
+	configMapInformer.Informer().AddEventHandler(sourceHandler)
+
+	return ctrl, nil
+}
+
+// SecretSyncController mirrors the Secrets and the ConfigMaps selected by the secret syncs into
+// their karmadas.
+type SecretSyncController struct {
+	client           clientset.Interface
+	fireflyClient    fireflyclient.Interface
+	eventBroadcaster record.EventBroadcaster
+	eventRecorder    record.EventRecorder
+
+	secretSyncsLister installlisters.SecretSyncLister
+	secretSyncsSynced cache.InformerSynced
+
+	karmadasLister installlisters.KarmadaLister
+	karmadasSynced cache.InformerSynced
+
+	secretsLister corelisters.SecretLister
+	secretsSynced cache.InformerSynced
+
+	configMapsLister corelisters.ConfigMapLister
+	configMapsSynced cache.InformerSynced
+
+	// Secret syncs that need to be updated. A channel is inappropriate here,
+	// because it allows a secret sync to be inserted multiple times and be
+	// processed more than necessary.
+	queue *coalesce.Queue
+
+	// workerLoopPeriod is the time between worker runs. The workers process the queue of secret sync changes.
+	workerLoopPeriod time.Duration
+}
+
+// Run will not return until stopCh is closed. workers determines how many
+// secret syncs will be handled in parallel.
+func (ctrl *SecretSyncController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+
+	// Start events processing pipeline.
+	ctrl.eventBroadcaster.StartStructuredLogging(0)
+	ctrl.eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: ctrl.client.CoreV1().Events("")})
+	defer ctrl.eventBroadcaster.Shutdown()
+
+	defer ctrl.queue.ShutDown()
+
+	klog.Infof("Starting secretsync controller")
+	defer klog.Infof("Shutting down secretsync controller")
+
+	if !cache.WaitForNamedCacheSync("secretsync", ctx.Done(), ctrl.secretSyncsSynced, ctrl.karmadasSynced, ctrl.secretsSynced, ctrl.configMapsSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, ctrl.worker, ctrl.workerLoopPeriod)
+	}
+	<-ctx.Done()
+}
+
+// worker runs a worker thread that just dequeues items, processes them, and
+// marks them done. You may run as many of these in parallel as you wish; the
+// workqueue guarantees that they will not end up processing the same secret sync
+// at the same time.
+func (ctrl *SecretSyncController) worker(ctx context.Context) {
+	for ctrl.processNextWorkItem(ctx) {
+	}
+}
+
+func (ctrl *SecretSyncController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := ctrl.queue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.queue.Done(key)
+
+	ctx, span := tracingutil.StartReconcile(ctx, "secretsync", key.(string))
+	err := ctrl.syncSecretSync(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.handleErr(err, key)
+
+	return true
+}
+
+func (ctrl *SecretSyncController) addSecretSync(obj interface{}) {
+	secretSync := obj.(*installv1alpha1.SecretSync)
+	klog.V(4).InfoS("Adding secret sync", "secretSync", klog.KObj(secretSync))
+	ctrl.enqueue(secretSync)
+}
+
+func (ctrl *SecretSyncController) updateSecretSync(old, cur interface{}) {
+	oldSecretSync := old.(*installv1alpha1.SecretSync)
+	curSecretSync := cur.(*installv1alpha1.SecretSync)
+	klog.V(4).InfoS("Updating secret sync", "secretSync", klog.KObj(oldSecretSync))
+	ctrl.enqueue(curSecretSync)
+}
+
+func (ctrl *SecretSyncController) deleteSecretSync(obj interface{}) {
+	secretSync, ok := obj.(*installv1alpha1.SecretSync)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("couldn't get object from tombstone %#v", obj))
+			return
+		}
+		secretSync, ok = tombstone.Obj.(*installv1alpha1.SecretSync)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("tombstone contained object that is not a SecretSync %#v", obj))
+			return
+		}
+	}
+	klog.V(4).InfoS("Deleting secret sync", "secretSync", klog.KObj(secretSync))
+	ctrl.enqueue(secretSync)
+}
+
+func (ctrl *SecretSyncController) addKarmada(obj interface{}) {
+	karmada := obj.(*installv1alpha1.Karmada)
+	ctrl.enqueueSecretSyncsForKarmada(karmada)
+}
+
+func (ctrl *SecretSyncController) updateKarmada(old, cur interface{}) {
+	curKarmada := cur.(*installv1alpha1.Karmada)
+	ctrl.enqueueSecretSyncsForKarmada(curKarmada)
+}
+
+// enqueueSecretSyncsForKarmada enqueues all secret syncs which target the given karmada.
+func (ctrl *SecretSyncController) enqueueSecretSyncsForKarmada(karmada *installv1alpha1.Karmada) {
+	secretSyncs, err := ctrl.secretSyncsLister.SecretSyncs(karmada.Namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, secretSync := range secretSyncs {
+		if secretSync.Spec.Karmada.Name == karmada.Name {
+			ctrl.enqueueCoalesced(secretSync)
+		}
+	}
+}
+
+// enqueueSecretSyncsForSource enqueues all secret syncs in the namespace of the given Secret or
+// ConfigMap which select it.
+func (ctrl *SecretSyncController) enqueueSecretSyncsForSource(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	var kind string
+	switch obj.(type) {
+	case *corev1.Secret:
+		kind = "Secret"
+	case *corev1.ConfigMap:
+		kind = "ConfigMap"
+	default:
+		return
+	}
+	source, err := meta.Accessor(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	secretSyncs, err := ctrl.secretSyncsLister.SecretSyncs(source.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, secretSync := range secretSyncs {
+		if selects(secretSync, kind, source) || mirrors(secretSync, kind, source.GetName()) {
+			ctrl.enqueueCoalesced(secretSync)
+		}
+	}
+}
+
+func (ctrl *SecretSyncController) enqueue(secretSync *installv1alpha1.SecretSync) {
+	key, err := cache.MetaNamespaceKeyFunc(secretSync)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.Add(key)
+}
+
+// enqueueCoalesced enqueues the secret sync after the bursts of events of its secondary objects have settled.
+func (ctrl *SecretSyncController) enqueueCoalesced(secretSync *installv1alpha1.SecretSync) {
+	key, err := cache.MetaNamespaceKeyFunc(secretSync)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.AddCoalesced(key)
+}
+
+func (ctrl *SecretSyncController) handleErr(err error, key interface{}) {
+	if err == nil || errors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+		ctrl.queue.Forget(key)
+		return
+	}
+
+	ns, name, keyErr := cache.SplitMetaNamespaceKey(key.(string))
+	if keyErr != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+	}
+
+	notification.ReconcileFailed("secretsync", audit.ObjectReference{Kind: "SecretSync", Namespace: ns, Name: name}, ctrl.queue.NumRequeues(key)+1, err)
+	if ctrl.queue.NumRequeues(key) < maxRetries {
+		klog.V(2).InfoS("Error syncing secret sync, retrying", "secretSync", klog.KRef(ns, name), "err", err)
+		ctrl.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	klog.V(2).InfoS("Dropping secret sync out of the queue", "secretSync", klog.KRef(ns, name), "err", err)
+	ctrl.queue.Forget(key)
+}
+
+func (ctrl *SecretSyncController) syncSecretSync(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+		return err
+	}
+
+	startTime := time.Now()
+	klog.V(4).InfoS("Started syncing secret sync", "secretSync", klog.KRef(namespace, name), "startTime", startTime)
+	defer func() {
+		klog.V(4).InfoS("Finished syncing secret sync", "secretSync", klog.KRef(namespace, name), "duration", time.Since(startTime))
+	}()
+
+	secretSync, err := ctrl.secretSyncsLister.SecretSyncs(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		klog.V(2).InfoS("Secret sync has been deleted", "secretSync", klog.KRef(namespace, name))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Deep-copy otherwise we are mutating our cache.
+	secretSync = secretSync.DeepCopy()
+	ctx = audit.WithTrigger(ctx, secretSync)
+	ctx = dryrun.ForObject(ctx, secretSync)
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if secretSync.DeletionTimestamp.IsZero() {
+		// The object is not being deleted, so if it does not have our finalizer,
+		// then lets add the finalizer and update the object. This is equivalent
+		// registering our finalizer.
+		if !controllerutil.ContainsFinalizer(secretSync, SecretSyncControllerFinalizerName) {
+			controllerutil.AddFinalizer(secretSync, SecretSyncControllerFinalizerName)
+			secretSync, err = ctrl.fireflyClient.InstallV1alpha1().SecretSyncs(secretSync.Namespace).Update(ctx, secretSync, metav1.UpdateOptions{})
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		// The object is being deleted
+		if controllerutil.ContainsFinalizer(secretSync, SecretSyncControllerFinalizerName) {
+			// our finalizer is present, so lets handle any external dependency
+			if err := ctrl.deleteUnableGCResources(ctx, secretSync); err != nil {
+				// if fail to delete the external dependency here, return with error
+				// so that it can be retried
+				return err
+			}
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(secretSync, SecretSyncControllerFinalizerName)
+			_, err := ctrl.fireflyClient.InstallV1alpha1().SecretSyncs(secretSync.Namespace).Update(ctx, secretSync, metav1.UpdateOptions{})
+			if err != nil {
+				return err
+			}
+			// Stop reconciliation as the item is being deleted
+			return nil
+		}
+	}
+
+	if secretSync.Spec.Paused {
+		klog.V(2).InfoS("Secret sync is paused, skip syncing", "secretSync", klog.KObj(secretSync))
+		return ctrl.updatePausedCondition(ctx, secretSync)
+	}
+	if err := ctrl.updatePausedCondition(ctx, secretSync); err != nil {
+		return err
+	}
+
+	klog.InfoS("Syncing secret sync", "secretSync", klog.KObj(secretSync))
+
+	karmadaName := secretSync.Spec.Karmada.Name
+	karmada, err := ctrl.karmadasLister.Karmadas(secretSync.Namespace).Get(karmadaName)
+	if errors.IsNotFound(err) {
+		// The secret sync will be requeued once the karmada is created.
+		return ctrl.updateStatus(ctx, secretSync, secretSync.Status.Objects, metav1.ConditionFalse, "KarmadaNotFound",
+			fmt.Sprintf("karmada %s not found", karmadaName))
+	}
+	if err != nil {
+		return err
+	}
+	if !karmada.DeletionTimestamp.IsZero() {
+		return ctrl.updateStatus(ctx, secretSync, secretSync.Status.Objects, metav1.ConditionFalse, "KarmadaTerminating",
+			fmt.Sprintf("karmada %s is terminating", karmadaName))
+	}
+
+	objects, err := ctrl.EnsureSecretSync(ctx, secretSync)
+	if err != nil {
+		ctrl.eventRecorder.Eventf(secretSync, corev1.EventTypeWarning, "SyncFailed", "Failed to mirror the objects: %v", err)
+		if updateErr := ctrl.updateStatus(ctx, secretSync, objects, metav1.ConditionFalse, "SyncFailed", err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "Failed to update secret sync status", "secretSync", klog.KObj(secretSync))
+		}
+		return err
+	}
+	return ctrl.updateStatus(ctx, secretSync, objects, metav1.ConditionTrue, "Synced", "all objects are mirrored")
+}
+
+// EnsureSecretSync mirrors the objects selected by the secret sync into the target karmada, and propagates
+// them to the target clusters. It returns the objects which are mirrored now.
+func (ctrl *SecretSyncController) EnsureSecretSync(ctx context.Context, secretSync *installv1alpha1.SecretSync) ([]installv1alpha1.SecretSyncObject, error) {
+	sources, err := ctrl.selectSources(secretSync)
+	if err != nil {
+		return secretSync.Status.Objects, err
+	}
+
+	tc, err := ctrl.newTargetClient(secretSync)
+	if err != nil {
+		return secretSync.Status.Objects, err
+	}
+
+	// the mirrors in the previous target namespace are pruned once the target namespace is changed.
+	oldNamespace := secretSync.Status.TargetNamespace
+	if oldNamespace == "" {
+		oldNamespace = targetNamespace(secretSync)
+	}
+	if oldNamespace != targetNamespace(secretSync) {
+		if err := tc.removePropagationPolicy(ctx, secretSync, oldNamespace); err != nil {
+			return secretSync.Status.Objects, err
+		}
+		if err := tc.pruneObjects(ctx, secretSync, oldNamespace, secretSync.Status.Objects, nil); err != nil {
+			return secretSync.Status.Objects, err
+		}
+		secretSync.Status.Objects = nil
+	}
+	secretSync.Status.TargetNamespace = targetNamespace(secretSync)
+
+	objects, err := tc.mirrorObjects(ctx, secretSync, sources)
+	if err != nil {
+		// keep track of the objects which might have been mirrored, so that they can be pruned later.
+		return mergeObjects(secretSync.Status.Objects, objects), err
+	}
+	if err := tc.pruneObjects(ctx, secretSync, targetNamespace(secretSync), secretSync.Status.Objects, objects); err != nil {
+		return mergeObjects(secretSync.Status.Objects, objects), err
+	}
+
+	if err := tc.ensurePropagationPolicy(ctx, secretSync, objects); err != nil {
+		return objects, err
+	}
+	return objects, nil
+}
+
+func (ctrl *SecretSyncController) deleteUnableGCResources(ctx context.Context, secretSync *installv1alpha1.SecretSync) error {
+	karmada, err := ctrl.karmadasLister.Karmadas(secretSync.Namespace).Get(secretSync.Spec.Karmada.Name)
+	if errors.IsNotFound(err) || (err == nil && !karmada.DeletionTimestamp.IsZero()) {
+		// The whole control plane is gone or going away, nothing to clean up.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	tc, err := ctrl.newTargetClient(secretSync)
+	if err != nil {
+		return err
+	}
+	namespace := secretSync.Status.TargetNamespace
+	if namespace == "" {
+		namespace = targetNamespace(secretSync)
+	}
+	if err := tc.removePropagationPolicy(ctx, secretSync, namespace); err != nil {
+		return err
+	}
+	return tc.pruneObjects(ctx, secretSync, namespace, secretSync.Status.Objects, nil)
+}
+
+// updatePausedCondition sets the Paused condition of the secret sync if it's paused, otherwise removes it.
+// The status is updated only if it's changed.
+func (ctrl *SecretSyncController) updatePausedCondition(ctx context.Context, secretSync *installv1alpha1.SecretSync) error {
+	oldStatus := secretSync.Status.DeepCopy()
+	if secretSync.Spec.Paused {
+		secretSync.Status.ObservedGeneration = secretSync.Generation
+		meta.SetStatusCondition(&secretSync.Status.Conditions, metav1.Condition{
+			Type:               installv1alpha1.SecretSyncConditionPaused,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: secretSync.Generation,
+			Reason:             "Paused",
+			Message:            "the reconciliation of the secret sync is paused",
+		})
+	} else {
+		meta.RemoveStatusCondition(&secretSync.Status.Conditions, installv1alpha1.SecretSyncConditionPaused)
+	}
+	phaseutil.SetSecretSyncPhase(secretSync)
+	if equality.Semantic.DeepEqual(oldStatus, &secretSync.Status) {
+		return nil
+	}
+	updated, err := ctrl.fireflyClient.InstallV1alpha1().SecretSyncs(secretSync.Namespace).UpdateStatus(ctx, secretSync, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	secretSync.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
+// updateStatus updates the objects and the Ready condition of the secret sync. The status is updated
+// only if it's changed, since the secret syncs are requeued on every change of their sources.
+func (ctrl *SecretSyncController) updateStatus(ctx context.Context, secretSync *installv1alpha1.SecretSync, objects []installv1alpha1.SecretSyncObject, status metav1.ConditionStatus, reason, message string) error {
+	oldStatus := secretSync.Status.DeepCopy()
+	secretSync.Status.ObservedGeneration = secretSync.Generation
+	secretSync.Status.Objects = objects
+	meta.SetStatusCondition(&secretSync.Status.Conditions, metav1.Condition{
+		Type:               installv1alpha1.SecretSyncConditionReady,
+		Status:             status,
+		ObservedGeneration: secretSync.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	phaseutil.SetSecretSyncPhase(secretSync)
+	if equality.Semantic.DeepEqual(oldStatus, &secretSync.Status) {
+		return nil
+	}
+	_, err := ctrl.fireflyClient.InstallV1alpha1().SecretSyncs(secretSync.Namespace).UpdateStatus(ctx, secretSync, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,380 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package submariner
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	karmadacontroller "github.com/carlory/firefly/pkg/controller/karmada"
+	"github.com/carlory/firefly/pkg/helm"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/audit"
+	chartutil "github.com/carlory/firefly/pkg/util/chart"
+	"github.com/carlory/firefly/pkg/util/kms"
+)
+
+const (
+	// chartRepository is the chart repository of Submariner.
+	chartRepository = "https://submariner-io.github.io/submariner-charts/charts"
+
+	// brokerNamespace is the namespace of the broker in the karmada-apiserver.
+	brokerNamespace = "submariner-k8s-broker"
+	// brokerClientServiceAccountName is the name of the service account the member clusters access the broker with.
+	brokerClientServiceAccountName = "submariner-k8s-broker-client"
+	// brokerClientTokenSecretName is the name of the secret holding the token of brokerClientServiceAccountName.
+	brokerClientTokenSecretName = "submariner-k8s-broker-client-token"
+
+	// operatorNamespace is the namespace of the submariner-operator in the member clusters.
+	operatorNamespace = "submariner-operator"
+
+	// defaultCableDriver is the cable driver used if it's not set by the submariner.
+	defaultCableDriver = "libreswan"
+
+	// pskLength is the length in bytes of the pre-shared key of the IPsec tunnels between the gateways.
+	pskLength = 64
+)
+
+// submarinerGVK is the kind of the Submariners rendered by the chart of the submariner-operator.
+var submarinerGVK = schema.GroupVersionKind{Group: "submariner.io", Version: "v1alpha1", Kind: "Submariner"}
+
+// errBrokerTokenNotIssued means that the token of the broker hasn't been issued by the
+// karmada-kube-controller-manager yet.
+var errBrokerTokenNotIssued = errors.New("the token of the broker is not issued yet")
+
+// broker holds what the member clusters connect to the broker with.
+type broker struct {
+	server string
+	// ca is the base64 encoded certificate authority of the karmada-apiserver.
+	ca    string
+	token string
+}
+
+// EnsureSubmariner installs the broker into the karmada-apiserver, and propagates the submariner-operator
+// to the member clusters of the submariner. It returns the resources which are owned by the submariner now.
+func (ctrl *SubmarinerController) EnsureSubmariner(ctx context.Context, submariner *installv1alpha1.Submariner, karmada *installv1alpha1.Karmada) ([]installv1alpha1.AddonResource, error) {
+	tc, err := ctrl.newTargetClient(submariner)
+	if err != nil {
+		return submariner.Status.Resources, err
+	}
+
+	brokerObjs, err := ctrl.brokerObjects(ctx, submariner)
+	if err != nil {
+		return submariner.Status.Resources, err
+	}
+	applied, resources, err := tc.applyObjects(ctx, submariner, brokerObjs)
+	if err != nil {
+		// keep track of the resources which might have been created, so that they can be pruned later.
+		return mergeResources(submariner.Status.Resources, resources), err
+	}
+	b, err := ctrl.brokerOf(ctx, submariner, karmada, applied[len(applied)-1])
+	if err != nil {
+		return mergeResources(submariner.Status.Resources, resources), err
+	}
+
+	operatorObjs, err := ctrl.operatorObjects(ctx, submariner, b)
+	if err != nil {
+		return mergeResources(submariner.Status.Resources, resources), err
+	}
+	_, operatorResources, err := tc.applyObjects(ctx, submariner, operatorObjs)
+	resources = append(resources, operatorResources...)
+	if err != nil {
+		return mergeResources(submariner.Status.Resources, resources), err
+	}
+	// the resources aren't propagated until the ClusterPropagationPolicy is applied, which is
+	// applied after the OverridePolicy, so that they're propagated with the settings of each
+	// cluster in the first place.
+	policies, err := policyObjects(submariner, operatorResources)
+	if err != nil {
+		return mergeResources(submariner.Status.Resources, resources), err
+	}
+	_, policyResources, err := tc.applyObjects(ctx, submariner, policies)
+	resources = append(resources, policyResources...)
+	if err != nil {
+		return mergeResources(submariner.Status.Resources, resources), err
+	}
+
+	if err := tc.pruneResources(ctx, submariner.Status.Resources, resources); err != nil {
+		return mergeResources(submariner.Status.Resources, resources), err
+	}
+	return resources, nil
+}
+
+// brokerObjects returns the objects of the broker, the last of which is the secret holding the
+// token of the member clusters.
+func (ctrl *SubmarinerController) brokerObjects(ctx context.Context, submariner *installv1alpha1.Submariner) ([]*unstructured.Unstructured, error) {
+	ref := submariner.Spec.BrokerChart
+	if ref == nil {
+		ref = &installv1alpha1.ChartReference{Repository: chartRepository, Name: "submariner-k8s-broker"}
+	}
+	release := helm.ReleaseOptions{Name: "submariner-k8s-broker", Namespace: brokerNamespace, IncludeCRDs: true}
+	values := helm.Values{
+		"serviceAccounts": map[string]interface{}{
+			"client": map[string]interface{}{"create": true, "name": brokerClientServiceAccountName},
+		},
+	}
+	objs, err := chartutil.Render(ctx, ctrl.chartFetcher, ref, release, values)
+	if err != nil {
+		return nil, err
+	}
+
+	// the service account token isn't issued for the service accounts since kubernetes 1.24.
+	token := &unstructured.Unstructured{}
+	token.SetAPIVersion("v1")
+	token.SetKind("Secret")
+	token.SetNamespace(brokerNamespace)
+	token.SetName(brokerClientTokenSecretName)
+	token.SetAnnotations(map[string]string{corev1.ServiceAccountNameKey: brokerClientServiceAccountName})
+	token.Object["type"] = string(corev1.SecretTypeServiceAccountToken)
+	return append([]*unstructured.Unstructured{namespaceObject(brokerNamespace)}, append(objs, token)...), nil
+}
+
+// brokerOf returns what the member clusters connect to the broker with, the token is read from the
+// given secret once it's issued.
+func (ctrl *SubmarinerController) brokerOf(ctx context.Context, submariner *installv1alpha1.Submariner, karmada *installv1alpha1.Karmada, tokenSecret *unstructured.Unstructured) (*broker, error) {
+	encodedToken, _, _ := unstructured.NestedString(tokenSecret.Object, "data", corev1.ServiceAccountTokenKey)
+	ca, _, _ := unstructured.NestedString(tokenSecret.Object, "data", corev1.ServiceAccountRootCAKey)
+	if encodedToken == "" || ca == "" {
+		return nil, errBrokerTokenNotIssued
+	}
+	token, err := base64.StdEncoding.DecodeString(encodedToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the token of the broker: %v", err)
+	}
+
+	server := submariner.Spec.BrokerServer
+	if server == "" {
+		kubeconfigSecret, err := ctrl.client.CoreV1().Secrets(karmada.Namespace).Get(ctx, karmadacontroller.AdminKubeconfigSecretName(karmada), metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		kubeconfig, err := kms.SecretData(ctx, kubeconfigSecret, "kubeconfig")
+		if err != nil {
+			return nil, err
+		}
+		config, err := clientcmd.Load(kubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the admin kubeconfig of karmada %s: %v", karmada.Name, err)
+		}
+		current, ok := config.Contexts[config.CurrentContext]
+		if !ok || config.Clusters[current.Cluster] == nil {
+			return nil, fmt.Errorf("the admin kubeconfig of karmada %s has no current cluster", karmada.Name)
+		}
+		u, err := url.Parse(config.Clusters[current.Cluster].Server)
+		if err != nil {
+			return nil, err
+		}
+		// Submariner expects the address of the broker without the scheme.
+		server = u.Host
+	}
+	return &broker{server: server, ca: ca, token: string(token)}, nil
+}
+
+// operatorObjects returns the objects of the submariner-operator which are propagated to the member clusters.
+func (ctrl *SubmarinerController) operatorObjects(ctx context.Context, submariner *installv1alpha1.Submariner, b *broker) ([]*unstructured.Unstructured, error) {
+	psk, err := ctrl.ensurePSK(ctx, submariner)
+	if err != nil {
+		return nil, err
+	}
+	cableDriver := submariner.Spec.CableDriver
+	if cableDriver == "" {
+		cableDriver = defaultCableDriver
+	}
+	values := helm.Values{
+		"ipsec": map[string]interface{}{"psk": psk},
+		"broker": map[string]interface{}{
+			"server":    b.server,
+			"token":     b.token,
+			"namespace": brokerNamespace,
+			"ca":        b.ca,
+		},
+		"submariner": map[string]interface{}{
+			"natEnabled":       submariner.Spec.NATTraversal,
+			"serviceDiscovery": submariner.Spec.ServiceDiscovery,
+			"cableDriver":      cableDriver,
+		},
+	}
+	userValues, err := chartutil.ParseValues(submariner.Spec.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := submariner.Spec.OperatorChart
+	if ref == nil {
+		ref = &installv1alpha1.ChartReference{Repository: chartRepository, Name: "submariner-operator"}
+	}
+	release := helm.ReleaseOptions{Name: "submariner", Namespace: operatorNamespace, IncludeCRDs: true}
+	objs, err := chartutil.Render(ctx, ctrl.chartFetcher, ref, release, values, userValues)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*unstructured.Unstructured{namespaceObject(operatorNamespace)}, objs...), nil
+}
+
+// ensurePSK returns the pre-shared key of the IPsec tunnels between the gateways, which is generated
+// once and kept in the secret `<name>-submariner-psk` in the namespace of the submariner.
+func (ctrl *SubmarinerController) ensurePSK(ctx context.Context, submariner *installv1alpha1.Submariner) (string, error) {
+	name := submariner.Name + "-submariner-psk"
+	secret, err := ctrl.client.CoreV1().Secrets(submariner.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil && len(secret.Data["psk"]) > 0 {
+		return string(secret.Data["psk"]), nil
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", err
+	}
+
+	key := make([]byte, pskLength)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	psk := base64.StdEncoding.EncodeToString(key)
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: submariner.Namespace,
+			Labels:    map[string]string{SubmarinerLabel: submariner.Name},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{"psk": []byte(psk)},
+	}
+	controllerutil.SetOwnerReference(submariner, secret, scheme.Scheme)
+	_, err = ctrl.client.CoreV1().Secrets(submariner.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	audit.RecordResult(ctx, audit.Create, secret, err)
+	if err != nil {
+		return "", err
+	}
+	return psk, nil
+}
+
+// policyObjects returns the OverridePolicy which sets the settings of each cluster on the Submariners,
+// and the ClusterPropagationPolicy which propagates the resources of the submariner-operator, including
+// its crds, to the member clusters.
+func policyObjects(submariner *installv1alpha1.Submariner, operatorResources []installv1alpha1.AddonResource) ([]*unstructured.Unstructured, error) {
+	clusterNames := make([]string, 0, len(submariner.Spec.Clusters))
+	for _, cluster := range submariner.Spec.Clusters {
+		clusterNames = append(clusterNames, cluster.Name)
+	}
+
+	var selectors, submarinerSelectors []policyv1alpha1.ResourceSelector
+	for _, r := range operatorResources {
+		selector := policyv1alpha1.ResourceSelector{
+			APIVersion: r.APIVersion,
+			Kind:       r.Kind,
+			Namespace:  r.Namespace,
+			Name:       r.Name,
+		}
+		if r.APIVersion == submarinerGVK.GroupVersion().String() && r.Kind == submarinerGVK.Kind {
+			submarinerSelectors = append(submarinerSelectors, selector)
+		}
+		selectors = append(selectors, selector)
+	}
+	if len(submarinerSelectors) == 0 {
+		return nil, fmt.Errorf("the chart of the submariner-operator doesn't render a Submariner")
+	}
+
+	propagationPolicy := &policyv1alpha1.ClusterPropagationPolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: policyv1alpha1.SchemeGroupVersion.String(), Kind: "ClusterPropagationPolicy"},
+		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("firefly-submariner-%s", submariner.Name)},
+		Spec: policyv1alpha1.PropagationSpec{
+			ResourceSelectors: selectors,
+			Placement: policyv1alpha1.Placement{
+				ClusterAffinity: &policyv1alpha1.ClusterAffinity{ClusterNames: clusterNames},
+			},
+		},
+	}
+
+	overridePolicy := &policyv1alpha1.OverridePolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: policyv1alpha1.SchemeGroupVersion.String(), Kind: "OverridePolicy"},
+		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("firefly-submariner-%s", submariner.Name), Namespace: operatorNamespace},
+		Spec:       policyv1alpha1.OverrideSpec{ResourceSelectors: submarinerSelectors},
+	}
+	for _, cluster := range submariner.Spec.Clusters {
+		overriders, err := clusterOverriders(cluster)
+		if err != nil {
+			return nil, err
+		}
+		overridePolicy.Spec.OverrideRules = append(overridePolicy.Spec.OverrideRules, policyv1alpha1.RuleWithCluster{
+			TargetCluster: &policyv1alpha1.ClusterAffinity{ClusterNames: []string{cluster.Name}},
+			Overriders:    policyv1alpha1.Overriders{Plaintext: overriders},
+		})
+	}
+
+	var objs []*unstructured.Unstructured
+	for _, policy := range []runtime.Object{overridePolicy, propagationPolicy} {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(policy)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, &unstructured.Unstructured{Object: content})
+	}
+	return objs, nil
+}
+
+// clusterOverriders returns the overriders which set the id and the CIDRs of the given cluster on its Submariner.
+func clusterOverriders(cluster installv1alpha1.SubmarinerCluster) ([]policyv1alpha1.PlaintextOverrider, error) {
+	fields := []struct {
+		path  string
+		value string
+	}{
+		{"/spec/clusterID", cluster.Name},
+		{"/spec/clusterCIDR", cluster.ClusterCIDR},
+		{"/spec/serviceCIDR", cluster.ServiceCIDR},
+	}
+	var overriders []policyv1alpha1.PlaintextOverrider
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		raw, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		// add replaces the field if it exists.
+		overriders = append(overriders, policyv1alpha1.PlaintextOverrider{
+			Path:     field.path,
+			Operator: policyv1alpha1.OverriderOpAdd,
+			Value:    apiextensionsv1.JSON{Raw: raw},
+		})
+	}
+	return overriders, nil
+}
+
+// namespaceObject returns the namespace of the given name.
+func namespaceObject(name string) *unstructured.Unstructured {
+	ns := &unstructured.Unstructured{}
+	ns.SetAPIVersion("v1")
+	ns.SetKind("Namespace")
+	ns.SetName(name)
+	return ns
+}
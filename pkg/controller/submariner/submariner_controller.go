@@ -0,0 +1,452 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package submariner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/prometheus/ratelimiter"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	fireflyclient "github.com/carlory/firefly/pkg/generated/clientset/versioned"
+	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
+	installlisters "github.com/carlory/firefly/pkg/generated/listers/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/helm"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/coalesce"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	informerutil "github.com/carlory/firefly/pkg/util/informer"
+	"github.com/carlory/firefly/pkg/util/notification"
+	phaseutil "github.com/carlory/firefly/pkg/util/phase"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
+)
+
+const (
+	// maxRetries is the number of times a submariner will be retried before it is dropped out of the queue.
+	// With the current rate-limiter in use (5ms*2^(maxRetries-1)) the following numbers represent the
+	// sequence of delays between successive queuings of a submariner.
+	//
+	// 5ms, 10ms, 20ms, 40ms, 80ms, 160ms, 320ms, 640ms, 1.3s, 2.6s, 5.1s, 10.2s, 20.4s, 41s, 82s
+	maxRetries = 15
+
+	// name of the submariner controller finalizer
+	SubmarinerControllerFinalizerName = "submariner.install.firefly.io/finalizer"
+
+	// brokerTokenPollPeriod is how often the token of the broker is checked until it's issued.
+	brokerTokenPollPeriod = 5 * time.Second
+)
+
+// NewSubmarinerController returns a new *Controller.
+func NewSubmarinerController(
+	client clientset.Interface,
+	fireflyClient fireflyclient.Interface,
+	submarinerInformer installinformers.SubmarinerInformer,
+	karmadaInformer installinformers.KarmadaInformer,
+	resyncPeriod time.Duration,
+	rateLimiter workqueue.RateLimiter) (*SubmarinerController, error) {
+	broadcaster := record.NewBroadcaster()
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "submariner-controller"})
+
+	if client != nil && client.CoreV1().RESTClient().GetRateLimiter() != nil {
+		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("submariner_controller", client.CoreV1().RESTClient().GetRateLimiter())
+	}
+
+	ctrl := &SubmarinerController{
+		client:            client,
+		fireflyClient:     fireflyClient,
+		submarinersLister: submarinerInformer.Lister(),
+		submarinersSynced: submarinerInformer.Informer().HasSynced,
+		karmadasLister:    karmadaInformer.Lister(),
+		karmadasSynced:    karmadaInformer.Informer().HasSynced,
+		queue:             coalesce.NewQueue(workqueue.NewNamedRateLimitingQueue(rateLimiter, "submariner"), coalesce.DefaultWindow, coalesce.DefaultInterval),
+		workerLoopPeriod:  time.Second,
+		eventBroadcaster:  broadcaster,
+		eventRecorder:     recorder,
+		chartFetcher:      helm.NewFetcher(),
+	}
+
+	informerutil.AddEventHandler(submarinerInformer.Informer(), cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.addSubmariner,
+		UpdateFunc: ctrl.updateSubmariner,
+		DeleteFunc: ctrl.deleteSubmariner,
+	}, resyncPeriod)
+
+	// Submariners are requeued when their karmada is created or changed,
+	// so that a submariner waiting for its karmada is installed as soon as possible.
+	karmadaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.addKarmada,
+		UpdateFunc: ctrl.updateKarmada,
+	})
+
+	return ctrl, nil
+}
+
+// SubmarinerController installs the broker of Submariner into the karmada-apiserver of a hosted karmada,
+// and propagates the submariner-operator to its member clusters.
+type SubmarinerController struct {
+	client           clientset.Interface
+	fireflyClient    fireflyclient.Interface
+	eventBroadcaster record.EventBroadcaster
+	eventRecorder    record.EventRecorder
+
+	submarinersLister installlisters.SubmarinerLister
+	submarinersSynced cache.InformerSynced
+
+	karmadasLister installlisters.KarmadaLister
+	karmadasSynced cache.InformerSynced
+
+	// chartFetcher loads the charts of the broker and the submariner-operator.
+	chartFetcher *helm.Fetcher
+
+	// Submariners that need to be updated. A channel is inappropriate here,
+	// because it allows a submariner to be inserted multiple times and be
+	// processed more than necessary.
+	queue *coalesce.Queue
+
+	// workerLoopPeriod is the time between worker runs. The workers process the queue of submariner changes.
+	workerLoopPeriod time.Duration
+}
+
+// Run will not return until stopCh is closed. workers determines how many
+// submariners will be handled in parallel.
+func (ctrl *SubmarinerController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+
+	// Start events processing pipeline.
+	ctrl.eventBroadcaster.StartStructuredLogging(0)
+	ctrl.eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: ctrl.client.CoreV1().Events("")})
+	defer ctrl.eventBroadcaster.Shutdown()
+
+	defer ctrl.queue.ShutDown()
+
+	klog.Infof("Starting submariner controller")
+	defer klog.Infof("Shutting down submariner controller")
+
+	if !cache.WaitForNamedCacheSync("submariner", ctx.Done(), ctrl.submarinersSynced, ctrl.karmadasSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, ctrl.worker, ctrl.workerLoopPeriod)
+	}
+	<-ctx.Done()
+}
+
+// worker runs a worker thread that just dequeues items, processes them, and
+// marks them done. You may run as many of these in parallel as you wish; the
+// workqueue guarantees that they will not end up processing the same submariner
+// at the same time.
+func (ctrl *SubmarinerController) worker(ctx context.Context) {
+	for ctrl.processNextWorkItem(ctx) {
+	}
+}
+
+func (ctrl *SubmarinerController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := ctrl.queue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.queue.Done(key)
+
+	ctx, span := tracingutil.StartReconcile(ctx, "submariner", key.(string))
+	err := ctrl.syncSubmariner(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.handleErr(err, key)
+
+	return true
+}
+
+func (ctrl *SubmarinerController) addSubmariner(obj interface{}) {
+	submariner := obj.(*installv1alpha1.Submariner)
+	klog.V(4).InfoS("Adding submariner", "submariner", klog.KObj(submariner))
+	ctrl.enqueue(submariner)
+}
+
+func (ctrl *SubmarinerController) updateSubmariner(old, cur interface{}) {
+	oldSubmariner := old.(*installv1alpha1.Submariner)
+	curSubmariner := cur.(*installv1alpha1.Submariner)
+	klog.V(4).InfoS("Updating submariner", "submariner", klog.KObj(oldSubmariner))
+	ctrl.enqueue(curSubmariner)
+}
+
+func (ctrl *SubmarinerController) deleteSubmariner(obj interface{}) {
+	submariner, ok := obj.(*installv1alpha1.Submariner)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("couldn't get object from tombstone %#v", obj))
+			return
+		}
+		submariner, ok = tombstone.Obj.(*installv1alpha1.Submariner)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("tombstone contained object that is not a Submariner %#v", obj))
+			return
+		}
+	}
+	klog.V(4).InfoS("Deleting submariner", "submariner", klog.KObj(submariner))
+	ctrl.enqueue(submariner)
+}
+
+func (ctrl *SubmarinerController) addKarmada(obj interface{}) {
+	karmada := obj.(*installv1alpha1.Karmada)
+	ctrl.enqueueSubmarinersForKarmada(karmada)
+}
+
+func (ctrl *SubmarinerController) updateKarmada(old, cur interface{}) {
+	curKarmada := cur.(*installv1alpha1.Karmada)
+	ctrl.enqueueSubmarinersForKarmada(curKarmada)
+}
+
+// enqueueSubmarinersForKarmada enqueues all submariners of the given karmada.
+func (ctrl *SubmarinerController) enqueueSubmarinersForKarmada(karmada *installv1alpha1.Karmada) {
+	submariners, err := ctrl.submarinersLister.Submariners(karmada.Namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, submariner := range submariners {
+		if submariner.Spec.Karmada.Name == karmada.Name {
+			ctrl.enqueueCoalesced(submariner)
+		}
+	}
+}
+
+func (ctrl *SubmarinerController) enqueue(submariner *installv1alpha1.Submariner) {
+	key, err := cache.MetaNamespaceKeyFunc(submariner)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.Add(key)
+}
+
+// enqueueCoalesced enqueues the submariner after the bursts of events of its secondary objects have settled.
+func (ctrl *SubmarinerController) enqueueCoalesced(submariner *installv1alpha1.Submariner) {
+	key, err := cache.MetaNamespaceKeyFunc(submariner)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	ctrl.queue.AddCoalesced(key)
+}
+
+func (ctrl *SubmarinerController) handleErr(err error, key interface{}) {
+	if err == nil || errors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+		ctrl.queue.Forget(key)
+		return
+	}
+
+	ns, name, keyErr := cache.SplitMetaNamespaceKey(key.(string))
+	if keyErr != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+	}
+
+	notification.ReconcileFailed("submariner", audit.ObjectReference{Kind: "Submariner", Namespace: ns, Name: name}, ctrl.queue.NumRequeues(key)+1, err)
+	if ctrl.queue.NumRequeues(key) < maxRetries {
+		klog.V(2).InfoS("Error syncing submariner, retrying", "submariner", klog.KRef(ns, name), "err", err)
+		ctrl.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	klog.V(2).InfoS("Dropping submariner out of the queue", "submariner", klog.KRef(ns, name), "err", err)
+	ctrl.queue.Forget(key)
+}
+
+func (ctrl *SubmarinerController) syncSubmariner(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.ErrorS(err, "Failed to split meta namespace cache key", "cacheKey", key)
+		return err
+	}
+
+	startTime := time.Now()
+	klog.V(4).InfoS("Started syncing submariner", "submariner", klog.KRef(namespace, name), "startTime", startTime)
+	defer func() {
+		klog.V(4).InfoS("Finished syncing submariner", "submariner", klog.KRef(namespace, name), "duration", time.Since(startTime))
+	}()
+
+	submariner, err := ctrl.submarinersLister.Submariners(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		klog.V(2).InfoS("Submariner has been deleted", "submariner", klog.KRef(namespace, name))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Deep-copy otherwise we are mutating our cache.
+	submariner = submariner.DeepCopy()
+	ctx = audit.WithTrigger(ctx, submariner)
+	ctx = dryrun.ForObject(ctx, submariner)
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if submariner.DeletionTimestamp.IsZero() {
+		// The object is not being deleted, so if it does not have our finalizer,
+		// then lets add the finalizer and update the object. This is equivalent
+		// registering our finalizer.
+		if !controllerutil.ContainsFinalizer(submariner, SubmarinerControllerFinalizerName) {
+			controllerutil.AddFinalizer(submariner, SubmarinerControllerFinalizerName)
+			submariner, err = ctrl.fireflyClient.InstallV1alpha1().Submariners(submariner.Namespace).Update(ctx, submariner, metav1.UpdateOptions{})
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		// The object is being deleted
+		if controllerutil.ContainsFinalizer(submariner, SubmarinerControllerFinalizerName) {
+			// our finalizer is present, so lets handle any external dependency
+			if err := ctrl.deleteUnableGCResources(ctx, submariner); err != nil {
+				// if fail to delete the external dependency here, return with error
+				// so that it can be retried
+				return err
+			}
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(submariner, SubmarinerControllerFinalizerName)
+			_, err := ctrl.fireflyClient.InstallV1alpha1().Submariners(submariner.Namespace).Update(ctx, submariner, metav1.UpdateOptions{})
+			if err != nil {
+				return err
+			}
+			// Stop reconciliation as the item is being deleted
+			return nil
+		}
+	}
+
+	if submariner.Spec.Paused {
+		klog.V(2).InfoS("Submariner is paused, skip syncing", "submariner", klog.KObj(submariner))
+		return ctrl.updatePausedCondition(ctx, submariner)
+	}
+	if err := ctrl.updatePausedCondition(ctx, submariner); err != nil {
+		return err
+	}
+
+	klog.InfoS("Syncing submariner", "submariner", klog.KObj(submariner))
+
+	karmadaName := submariner.Spec.Karmada.Name
+	karmada, err := ctrl.karmadasLister.Karmadas(submariner.Namespace).Get(karmadaName)
+	if errors.IsNotFound(err) {
+		// The submariner will be requeued once the karmada is created.
+		return ctrl.updateStatus(ctx, submariner, submariner.Status.Resources, metav1.ConditionFalse, "KarmadaNotFound",
+			fmt.Sprintf("karmada %s not found", karmadaName))
+	}
+	if err != nil {
+		return err
+	}
+	if !karmada.DeletionTimestamp.IsZero() {
+		return ctrl.updateStatus(ctx, submariner, submariner.Status.Resources, metav1.ConditionFalse, "KarmadaTerminating",
+			fmt.Sprintf("karmada %s is terminating", karmadaName))
+	}
+
+	resources, err := ctrl.EnsureSubmariner(ctx, submariner, karmada)
+	if err == errBrokerTokenNotIssued {
+		ctrl.queue.AddAfter(key, brokerTokenPollPeriod)
+		return ctrl.updateStatus(ctx, submariner, resources, metav1.ConditionFalse, "WaitingForBrokerToken",
+			"waiting for the token of the broker to be issued by the karmada-kube-controller-manager")
+	}
+	if err != nil {
+		ctrl.eventRecorder.Eventf(submariner, corev1.EventTypeWarning, "InstallFailed", "Failed to install submariner: %v", err)
+		if updateErr := ctrl.updateStatus(ctx, submariner, resources, metav1.ConditionFalse, "InstallFailed", err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "Failed to update submariner status", "submariner", klog.KObj(submariner))
+		}
+		return err
+	}
+	if !meta.IsStatusConditionTrue(submariner.Status.Conditions, installv1alpha1.SubmarinerConditionReady) {
+		ctrl.eventRecorder.Eventf(submariner, corev1.EventTypeNormal, "Installed", "Installed the broker and propagated the submariner-operator to %d clusters", len(submariner.Spec.Clusters))
+	}
+	return ctrl.updateStatus(ctx, submariner, resources, metav1.ConditionTrue, "Installed", "the broker and the submariner-operator are installed")
+}
+
+// deleteUnableGCResources uninstalls the submariner from the karmada-apiserver, so that the
+// submariner-operator is removed from the member clusters as well.
+func (ctrl *SubmarinerController) deleteUnableGCResources(ctx context.Context, submariner *installv1alpha1.Submariner) error {
+	karmada, err := ctrl.karmadasLister.Karmadas(submariner.Namespace).Get(submariner.Spec.Karmada.Name)
+	if errors.IsNotFound(err) || (err == nil && !karmada.DeletionTimestamp.IsZero()) {
+		// The whole control plane is gone or going away, nothing to clean up.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	tc, err := ctrl.newTargetClient(submariner)
+	if err != nil {
+		return err
+	}
+	return tc.pruneResources(ctx, submariner.Status.Resources, nil)
+}
+
+// updatePausedCondition sets the Paused condition of the submariner if it's paused, otherwise removes it.
+// The status is updated only if it's changed.
+func (ctrl *SubmarinerController) updatePausedCondition(ctx context.Context, submariner *installv1alpha1.Submariner) error {
+	oldStatus := submariner.Status.DeepCopy()
+	if submariner.Spec.Paused {
+		submariner.Status.ObservedGeneration = submariner.Generation
+		meta.SetStatusCondition(&submariner.Status.Conditions, metav1.Condition{
+			Type:               installv1alpha1.SubmarinerConditionPaused,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: submariner.Generation,
+			Reason:             "Paused",
+			Message:            "the reconciliation of the submariner is paused",
+		})
+	} else {
+		meta.RemoveStatusCondition(&submariner.Status.Conditions, installv1alpha1.SubmarinerConditionPaused)
+	}
+	phaseutil.SetSubmarinerPhase(submariner)
+	if equality.Semantic.DeepEqual(oldStatus, &submariner.Status) {
+		return nil
+	}
+	updated, err := ctrl.fireflyClient.InstallV1alpha1().Submariners(submariner.Namespace).UpdateStatus(ctx, submariner, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	submariner.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
+func (ctrl *SubmarinerController) updateStatus(ctx context.Context, submariner *installv1alpha1.Submariner, resources []installv1alpha1.AddonResource, status metav1.ConditionStatus, reason, message string) error {
+	submariner.Status.ObservedGeneration = submariner.Generation
+	submariner.Status.Resources = resources
+	meta.SetStatusCondition(&submariner.Status.Conditions, metav1.Condition{
+		Type:               installv1alpha1.SubmarinerConditionReady,
+		Status:             status,
+		ObservedGeneration: submariner.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	phaseutil.SetSubmarinerPhase(submariner)
+	_, err := ctrl.fireflyClient.InstallV1alpha1().Submariners(submariner.Namespace).UpdateStatus(ctx, submariner, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package submariner
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/util/apply"
+	utilresource "github.com/carlory/firefly/pkg/util/resource"
+)
+
+const (
+	// the user-agent name is used when talking to karmada-apiserver
+	userAgentName = "submariner-controller"
+
+	// fieldManager is the field manager used to apply the resources of a submariner.
+	fieldManager = "firefly-submariner-controller"
+
+	// SubmarinerLabel is the label set on all resources installed by a submariner.
+	// Its value is the name of the submariner.
+	SubmarinerLabel = "submariner.install.firefly.io/name"
+
+	// karmadaKubeconfigSecretName is the name of the secret which holds the kubeconfig of karmada-apiserver.
+	karmadaKubeconfigSecretName = "karmada-kubeconfig"
+)
+
+// targetClient talks to the karmada-apiserver of a submariner.
+type targetClient struct {
+	applier *apply.Applier
+}
+
+func (ctrl *SubmarinerController) newTargetClient(submariner *installv1alpha1.Submariner) (*targetClient, error) {
+	clientConfig, err := utilresource.GetClientConfigFromKubeConfigSecret(ctrl.client, submariner.Namespace, karmadaKubeconfigSecretName, userAgentName)
+	if err != nil {
+		return nil, err
+	}
+	applier, err := apply.NewApplier(clientConfig, fieldManager)
+	if err != nil {
+		return nil, err
+	}
+	return &targetClient{applier: applier}, nil
+}
+
+// applyObjects applies the objects with server-side apply, and returns the applied objects and their resources.
+func (c *targetClient) applyObjects(ctx context.Context, submariner *installv1alpha1.Submariner, objs []*unstructured.Unstructured) ([]*unstructured.Unstructured, []installv1alpha1.AddonResource, error) {
+	var applied []*unstructured.Unstructured
+	var resources []installv1alpha1.AddonResource
+	for _, obj := range objs {
+		obj = obj.DeepCopy()
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[SubmarinerLabel] = submariner.Name
+		obj.SetLabels(labels)
+
+		got, err := c.applier.Apply(ctx, obj)
+		if err != nil {
+			return applied, resources, err
+		}
+		applied = append(applied, got)
+		resources = append(resources, resourceOf(obj))
+	}
+	return applied, resources, nil
+}
+
+// pruneResources deletes the resources in old which are not in current.
+func (c *targetClient) pruneResources(ctx context.Context, old, current []installv1alpha1.AddonResource) error {
+	keep := make(map[installv1alpha1.AddonResource]bool, len(current))
+	for _, r := range current {
+		keep[r] = true
+	}
+
+	// delete in reverse install order, so that namespaces and crds are deleted last.
+	for i := len(old) - 1; i >= 0; i-- {
+		r := old[i]
+		if keep[r] {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(r.APIVersion)
+		obj.SetKind(r.Kind)
+		obj.SetNamespace(r.Namespace)
+		obj.SetName(r.Name)
+		if err := c.applier.Delete(ctx, obj); err != nil {
+			return err
+		}
+		klog.V(2).InfoS("Pruned submariner resource", "kind", r.Kind, "object", klog.KObj(obj))
+	}
+	return nil
+}
+
+// resourceOf returns the resource identifying the given object.
+func resourceOf(obj *unstructured.Unstructured) installv1alpha1.AddonResource {
+	return installv1alpha1.AddonResource{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+}
+
+// mergeResources returns the union of the given resource lists.
+func mergeResources(a, b []installv1alpha1.AddonResource) []installv1alpha1.AddonResource {
+	seen := make(map[installv1alpha1.AddonResource]bool, len(a)+len(b))
+	var merged []installv1alpha1.AddonResource
+	for _, list := range [][]installv1alpha1.AddonResource{a, b} {
+		for _, r := range list {
+			if seen[r] {
+				continue
+			}
+			seen[r] = true
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,307 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controllermanager builds the context the controllers of the firefly-controller-manager are
+// started with, and starts them. Platforms which embed the install logic of firefly, e.g. the karmada
+// controller, build the ControllerContext with NewControllerContext and start the controllers with
+// StartControllers instead of importing the cmd packages, which are not meant to be imported.
+package controllermanager
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/apiserver/pkg/server/mux"
+	cacheddiscovery "k8s.io/client-go/discovery/cached"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/restmapper"
+	genericcontrollermanager "k8s.io/controller-manager/app"
+	"k8s.io/controller-manager/controller"
+	controllerhealthz "k8s.io/controller-manager/pkg/healthz"
+	"k8s.io/controller-manager/pkg/informerfactory"
+	"k8s.io/klog/v2"
+
+	"github.com/carlory/firefly/pkg/clientbuilder"
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/controller/apis/config"
+	fireflyversioned "github.com/carlory/firefly/pkg/generated/clientset/versioned"
+	fireflyinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions"
+	"github.com/carlory/firefly/pkg/util/backoff"
+	"github.com/carlory/firefly/pkg/util/capabilities"
+	"github.com/carlory/firefly/pkg/util/expectations"
+	informerutil "github.com/carlory/firefly/pkg/util/informer"
+	"github.com/carlory/firefly/pkg/util/resolver"
+	"github.com/carlory/firefly/pkg/util/ttlcache"
+)
+
+// ControllerStartJitter is the Jitter used when starting controller managers
+const ControllerStartJitter = 1.0
+
+// ControllersDisabledByDefault is the set of controllers which is disabled by default
+var ControllersDisabledByDefault = sets.NewString(
+	"observability",
+)
+
+// ControllerContext defines the context object for controller
+type ControllerContext struct {
+	// ClientBuilder will provide a client for this controller to use
+	ClientBuilder clientbuilder.FireflyControllerClientBuilder
+
+	// KubeInformerFactory gives access to kubernetes informers for the controller.
+	KubeInformerFactory informers.SharedInformerFactory
+
+	// FireflyInformerFactory gives access to firefly informers for the controller.
+	FireflyInformerFactory fireflyinformers.SharedInformerFactory
+
+	// ObjectOrMetadataInformerFactory gives access to informers for typed resources
+	// and dynamic resources by their metadata. All generic controllers currently use
+	// object metadata - if a future controller needs access to the full object this
+	// would become GenericInformerFactory and take a dynamic client.
+	ObjectOrMetadataInformerFactory informerfactory.InformerFactory
+
+	// FilteredFactories gives access to kubernetes and metadata-only informers which only list and watch
+	// a part of the resources, such as the ones with a label.
+	FilteredFactories *informerutil.FilteredFactories
+
+	// ComponentConfig provides access to init options for a given controller
+	ComponentConfig fireflyctrlmgrconfig.FireflyControllerManagerConfiguration
+
+	// DeferredDiscoveryRESTMapper is a RESTMapper that will defer
+	// initialization of the RESTMapper until the first mapping is
+	// requested.
+	RESTMapper *restmapper.DeferredDiscoveryRESTMapper
+
+	// AvailableResources is a map listing currently available resources
+	AvailableResources map[schema.GroupVersionResource]bool
+
+	// HostClusterCapabilities tells the optional capabilities of the host cluster, e.g. the Prometheus Operator,
+	// from AvailableResources, so that the controllers vary what they create by them.
+	HostClusterCapabilities *capabilities.Capabilities
+
+	// InformersStarted is closed after all of the controllers have been initialized and are running.  After this point it is safe,
+	// for an individual controller to start the shared informers. Before it is closed, they should not.
+	InformersStarted chan struct{}
+
+	// ResyncPeriod generates a duration each time it is invoked; this is so that
+	// multiple controllers don't get into lock-step and all hammer the apiserver
+	// with list requests simultaneously.
+	ResyncPeriod func() time.Duration
+
+	// Expectations gives access to the create/delete expectations of the controllers, keyed by the
+	// controller name, so that they don't act on stale informer caches.
+	Expectations *expectations.Registry
+
+	// TTLCaches gives access to the expiring caches shared by the controllers, keyed by the cache name.
+	TTLCaches *ttlcache.Registry
+
+	// ObjectResolver resolves object references and owner references of the objects in the host cluster,
+	// the resolved objects are cached for a short while to avoid redundant GETs during reconcile storms.
+	ObjectResolver *resolver.Resolver
+}
+
+// IsControllerEnabled checks if the context's controllers enabled or not
+func (c ControllerContext) IsControllerEnabled(name string) bool {
+	return genericcontrollermanager.IsControllerEnabled(name, ControllersDisabledByDefault, c.ComponentConfig.Generic.Controllers)
+}
+
+// StartInformers starts the shared informer factories and closes InformersStarted, it's called once
+// all of the controllers have been started.
+func (c ControllerContext) StartInformers(stopCh <-chan struct{}) {
+	c.KubeInformerFactory.Start(stopCh)
+	c.FireflyInformerFactory.Start(stopCh)
+	c.ObjectOrMetadataInformerFactory.Start(stopCh)
+	c.FilteredFactories.Start(stopCh)
+	close(c.InformersStarted)
+}
+
+// WaitForCacheSync waits for the caches of all the informers started by the shared informer
+// factories to be synced, it returns false if stopCh is closed before that.
+func (c ControllerContext) WaitForCacheSync(stopCh <-chan struct{}) bool {
+	return informerutil.AllSynced(c.KubeInformerFactory.WaitForCacheSync(stopCh)) &&
+		informerutil.AllSynced(c.FireflyInformerFactory.WaitForCacheSync(stopCh)) &&
+		c.FilteredFactories.WaitForCacheSync(stopCh)
+}
+
+// InitFunc is used to launch a particular controller. It returns a controller
+// that can optionally implement other interfaces so that the controller manager
+// can support the requested features.
+// The returned controller may be nil, which will be considered an anonymous controller
+// that requests no additional features from the controller manager.
+// Any error returned will cause the controller process to `Fatal`
+// The bool indicates whether the controller was enabled.
+type InitFunc func(ctx context.Context, controllerCtx ControllerContext) (controller controller.Interface, enabled bool, err error)
+
+// ResyncPeriod returns a function which generates a duration between minResyncPeriod and twice of it
+// each time it is invoked; this is so that multiple controllers don't get into lock-step and all
+// hammer the apiserver with list requests simultaneously.
+func ResyncPeriod(minResyncPeriod time.Duration) func() time.Duration {
+	return func() time.Duration {
+		factor := rand.Float64() + 1
+		return time.Duration(float64(minResyncPeriod.Nanoseconds()) * factor)
+	}
+}
+
+// GetAvailableResources gets the map which contains all available resources of the apiserver
+// TODO: In general, any controller checking this needs to be dynamic so
+// users don't have to restart their controller manager if they change the apiserver.
+// Until we get there, the structure here needs to be exposed for the construction of a proper ControllerContext.
+func GetAvailableResources(clientBuilder clientbuilder.FireflyControllerClientBuilder) (map[schema.GroupVersionResource]bool, error) {
+	client := clientBuilder.ClientOrDie("controller-discovery")
+	discoveryClient := client.Discovery()
+	_, resourceMap, err := discoveryClient.ServerGroupsAndResources()
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("unable to get all supported resources from server: %v", err))
+	}
+	if len(resourceMap) == 0 {
+		return nil, fmt.Errorf("unable to get any supported resources from server")
+	}
+
+	allResources := map[schema.GroupVersionResource]bool{}
+	for _, apiResourceList := range resourceMap {
+		version, err := schema.ParseGroupVersion(apiResourceList.GroupVersion)
+		if err != nil {
+			return nil, err
+		}
+		for _, apiResource := range apiResourceList.APIResources {
+			allResources[version.WithResource(apiResource.Name)] = true
+		}
+	}
+
+	return allResources, nil
+}
+
+// NewControllerContext creates a context struct containing references to resources needed by the
+// controllers such as the clientBuilder. rootClientBuilder is only used for the shared-informers and
+// discovery clients. Controllers should call informerutil.SetTransform on the informers they get from
+// the shared informer factories to keep the caches small. The errors caused by an unreachable apiserver
+// are marked as transient, see backoff.Retry.
+func NewControllerContext(componentConfig fireflyctrlmgrconfig.FireflyControllerManagerConfiguration, rootClientBuilder, clientBuilder clientbuilder.FireflyControllerClientBuilder, stop <-chan struct{}) (ControllerContext, error) {
+	resyncPeriod := ResyncPeriod(componentConfig.Generic.MinResyncPeriod.Duration)
+
+	versionedClient := rootClientBuilder.ClientOrDie("firefly-kube-shared-informers")
+	kubeSharedInformers := informers.NewSharedInformerFactory(versionedClient, resyncPeriod())
+
+	clientConfig := rootClientBuilder.ConfigOrDie("firefly-shared-informers")
+	fireflyClient := fireflyversioned.NewForConfigOrDie(clientConfig)
+	fireflySharedInformers := fireflyinformers.NewSharedInformerFactory(fireflyClient, resyncPeriod())
+
+	metadataClient := metadata.NewForConfigOrDie(rootClientBuilder.ConfigOrDie("firefly-metadata-informers"))
+	metadataInformers := metadatainformer.NewSharedInformerFactory(metadataClient, resyncPeriod())
+
+	filteredClient := rootClientBuilder.ClientOrDie("firefly-kube-filtered-shared-informers")
+	metadataFilteredClient := metadata.NewForConfigOrDie(rootClientBuilder.ConfigOrDie("firefly-metadata-filtered-informers"))
+	filteredInformers := informerutil.NewFilteredFactories(filteredClient, metadataFilteredClient, resyncPeriod)
+
+	// If apiserver is not running we should wait for some time and fail only then. This is particularly
+	// important when we start apiserver and controller manager at the same time.
+	if err := genericcontrollermanager.WaitForAPIServer(versionedClient, 10*time.Second); err != nil {
+		return ControllerContext{}, backoff.Transient(fmt.Errorf("failed to wait for apiserver being healthy: %v", err))
+	}
+
+	// Use a discovery client capable of being refreshed.
+	discoveryClient := rootClientBuilder.DiscoveryClientOrDie("firelfy-controller-discovery")
+	cachedClient := cacheddiscovery.NewMemCacheClient(discoveryClient)
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedClient)
+
+	availableResources, err := GetAvailableResources(rootClientBuilder)
+	if err != nil {
+		return ControllerContext{}, backoff.Transient(err)
+	}
+	// the restMapper is only reset once the context is built, so that no goroutine is left by the
+	// attempts which failed.
+	go wait.Until(func() {
+		restMapper.Reset()
+	}, 30*time.Second, stop)
+
+	ttlCaches := ttlcache.NewRegistry()
+	ctx := ControllerContext{
+		ClientBuilder:                   clientBuilder,
+		KubeInformerFactory:             kubeSharedInformers,
+		FireflyInformerFactory:          fireflySharedInformers,
+		ObjectOrMetadataInformerFactory: informerfactory.NewInformerFactory(kubeSharedInformers, metadataInformers),
+		FilteredFactories:               filteredInformers,
+		ComponentConfig:                 componentConfig,
+		RESTMapper:                      restMapper,
+		AvailableResources:              availableResources,
+		HostClusterCapabilities:         capabilities.New(availableResources),
+		InformersStarted:                make(chan struct{}),
+		ResyncPeriod:                    resyncPeriod,
+		Expectations:                    expectations.NewRegistry(),
+		TTLCaches:                       ttlCaches,
+		ObjectResolver:                  resolver.New(restMapper, clientBuilder.DynamicClientOrDie("firefly-object-resolver"), ttlCaches.For("object-resolver", resolver.DefaultTTL)),
+	}
+	return ctx, nil
+}
+
+// StartControllers starts a set of controllers with a specified ControllerContext. The debugging
+// handlers of the controllers are mounted onto unsecuredMux if it's not nil, and their health checks
+// are added to healthzHandler.
+func StartControllers(ctx context.Context, controllerCtx ControllerContext, controllers map[string]InitFunc,
+	unsecuredMux *mux.PathRecorderMux, healthzHandler *controllerhealthz.MutableHealthzHandler) error {
+	var controllerChecks []healthz.HealthChecker
+
+	for controllerName, initFn := range controllers {
+		if !controllerCtx.IsControllerEnabled(controllerName) {
+			klog.Warningf("%q is disabled", controllerName)
+			continue
+		}
+
+		time.Sleep(wait.Jitter(controllerCtx.ComponentConfig.Generic.ControllerStartInterval.Duration, ControllerStartJitter))
+
+		klog.V(1).Infof("Starting %q", controllerName)
+		ctrl, started, err := initFn(ctx, controllerCtx)
+		if err != nil {
+			klog.Errorf("Error starting %q", controllerName)
+			return err
+		}
+		if !started {
+			klog.Warningf("Skipping %q", controllerName)
+			continue
+		}
+		check := controllerhealthz.NamedPingChecker(controllerName)
+		if ctrl != nil {
+			// check if the controller supports and requests a debugHandler
+			// and it needs the unsecuredMux to mount the handler onto.
+			if debuggable, ok := ctrl.(controller.Debuggable); ok && unsecuredMux != nil {
+				if debugHandler := debuggable.DebuggingHandler(); debugHandler != nil {
+					basePath := "/debug/controllers/" + controllerName
+					unsecuredMux.UnlistedHandle(basePath, http.StripPrefix(basePath, debugHandler))
+					unsecuredMux.UnlistedHandlePrefix(basePath+"/", http.StripPrefix(basePath, debugHandler))
+				}
+			}
+			if healthCheckable, ok := ctrl.(controller.HealthCheckable); ok {
+				if realCheck := healthCheckable.HealthChecker(); realCheck != nil {
+					check = controllerhealthz.NamedHealthChecker(controllerName, realCheck)
+				}
+			}
+		}
+		controllerChecks = append(controllerChecks, check)
+
+		klog.Infof("Started %q", controllerName)
+	}
+
+	healthzHandler.AddHealthChecker(controllerChecks...)
+	return nil
+}
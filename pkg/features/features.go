@@ -0,0 +1,50 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	// Feature gates should be listed in alphabetical, case-sensitive
+	// (upper before any lower case character) order. This reduces the risk
+	// of code conflicts because changes are more likely to be scattered
+	// across the file.
+
+	// DeploymentArgsPatch patches only the args of the containers into a deployment of a component, if they're the
+	// only difference from the deployment applied last time, e.g. the log verbosity is changed.
+	DeploymentArgsPatch featuregate.Feature = "DeploymentArgsPatch"
+
+	// MultiHostCluster runs the apiservers of a karmada in the additional host clusters of its
+	// spec.hostClusters as well.
+	MultiHostCluster featuregate.Feature = "MultiHostCluster"
+)
+
+// AddFeatureGates adds the feature gates of firefly to the given mutable feature gate.
+// It's called by the firefly binaries in init, next to the logging feature gates.
+func AddFeatureGates(mutableFeatureGate featuregate.MutableFeatureGate) error {
+	return mutableFeatureGate.Add(defaultFireflyFeatureGates)
+}
+
+// defaultFireflyFeatureGates consists of all known firefly-specific feature keys.
+// To add a new feature, define a key for it above and add it here. The features will be
+// available throughout the firefly binaries by the --feature-gates flag.
+var defaultFireflyFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	DeploymentArgsPatch: {Default: true, PreRelease: featuregate.Beta},
+	MultiHostCluster:    {Default: false, PreRelease: featuregate.Alpha},
+}
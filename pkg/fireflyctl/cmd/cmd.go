@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"flag"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cliflag "k8s.io/component-base/cli/flag"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/carlory/firefly/pkg/fireflyctl/cmd/create"
+	"github.com/carlory/firefly/pkg/fireflyctl/cmd/getkubeconfig"
+	"github.com/carlory/firefly/pkg/fireflyctl/cmd/initialize"
+	"github.com/carlory/firefly/pkg/fireflyctl/cmd/options"
+	"github.com/carlory/firefly/pkg/fireflyctl/cmd/status"
+	"github.com/carlory/firefly/pkg/fireflyctl/cmd/uninstall"
+)
+
+// NewFireflyctlCommand creates the `fireflyctl` command and its nested children.
+func NewFireflyctlCommand(in io.Reader, out, err io.Writer) *cobra.Command {
+	// Parent command to which all subcommands are added.
+	cmds := &cobra.Command{
+		Use:   "fireflyctl",
+		Short: "fireflyctl installs and manages firefly in a host cluster",
+		Long:  "fireflyctl installs and manages firefly, and the karmadas it installs, in a host cluster",
+		RunE:  runHelp,
+	}
+
+	flags := cmds.PersistentFlags()
+	flags.SetNormalizeFunc(cliflag.WarnWordSepNormalizeFunc) // Warn for "_" flags
+
+	// Normalize all flags that are coming from other packages or pre-configurations
+	// a.k.a. change all "_" to "-". e.g. glog package
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+
+	kubeConfigFlags := genericclioptions.NewConfigFlags(true).WithDeprecatedPasswordFlag()
+	kubeConfigFlags.AddFlags(flags)
+	matchVersionKubeConfigFlags := cmdutil.NewMatchVersionFlags(kubeConfigFlags)
+	matchVersionKubeConfigFlags.AddFlags(cmds.PersistentFlags())
+
+	cmds.PersistentFlags().AddGoFlagSet(flag.CommandLine)
+
+	f := cmdutil.NewFactory(matchVersionKubeConfigFlags)
+
+	// From this point and forward we get warnings on flags that contain "_" separators
+	cmds.SetGlobalNormalizationFunc(cliflag.WarnWordSepNormalizeFunc)
+
+	ioStreams := genericclioptions.IOStreams{In: in, Out: out, ErrOut: err}
+
+	groups := templates.CommandGroups{
+		{
+			Message: "Installation Commands:",
+			Commands: []*cobra.Command{
+				initialize.NewCmdInit(f, ioStreams),
+				uninstall.NewCmdUninstall(f, ioStreams),
+			},
+		},
+		{
+			Message: "Basic Commands:",
+			Commands: []*cobra.Command{
+				create.NewCmdCreate(f, ioStreams),
+				status.NewCmdStatus(f, ioStreams),
+				getkubeconfig.NewCmdGetKubeconfig(f, ioStreams),
+			},
+		},
+	}
+
+	groups.Add(cmds)
+
+	filters := []string{"options"}
+	templates.ActsAsRootCommand(cmds, filters, groups...)
+	cmds.AddCommand(options.NewCmdOptions(ioStreams.Out))
+
+	return cmds
+}
+
+func runHelp(cmd *cobra.Command, args []string) error {
+	return cmd.Help()
+}
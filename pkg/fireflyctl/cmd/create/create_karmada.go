@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cliflag "k8s.io/component-base/cli/flag"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	fireflyversioned "github.com/carlory/firefly/pkg/generated/clientset/versioned"
+	fireflyscheme "github.com/carlory/firefly/pkg/generated/clientset/versioned/scheme"
+)
+
+// CreateKarmadaOptions defines flags and other configuration parameters for the `create karmada` command
+type CreateKarmadaOptions struct {
+	genericclioptions.IOStreams
+
+	PrintFlags *genericclioptions.PrintFlags
+	PrintObj   func(obj runtime.Object) error
+
+	Client         fireflyversioned.Interface
+	DryRunStrategy cmdutil.DryRunStrategy
+
+	Name             string
+	Namespace        string
+	EnforceNamespace bool
+
+	KarmadaVersion       string
+	KubernetesVersion    string
+	ImageRepository      string
+	ControlPlaneEndpoint string
+	ServiceSubnet        string
+	DNSDomain            string
+	FeatureGates         map[string]bool
+}
+
+var (
+	createKarmadaLong = templates.LongDesc(i18n.T(`
+		Create a karmada with the specified name.
+
+		The fields which are not set by the flags are defaulted by the firefly webhook.`))
+
+	createKarmadaExample = templates.Examples(i18n.T(`
+		# Create a karmada named karmada in the namespace firefly-system.
+		fireflyctl create karmada karmada -n firefly-system --karmada-version=v1.2.0
+
+		# Print the karmada instead of creating it.
+		fireflyctl create karmada karmada --karmada-version=v1.2.0 --dry-run=client -o yaml`))
+)
+
+// NewCreateKarmadaOptions creates new CreateKarmadaOptions for the `create karmada` command
+func NewCreateKarmadaOptions(ioStreams genericclioptions.IOStreams) *CreateKarmadaOptions {
+	return &CreateKarmadaOptions{
+		IOStreams:  ioStreams,
+		PrintFlags: genericclioptions.NewPrintFlags("created").WithTypeSetter(fireflyscheme.Scheme),
+	}
+}
+
+// NewCmdCreateKarmada creates the `create karmada` command
+func NewCmdCreateKarmada(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewCreateKarmadaOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "karmada NAME [--karmada-version=version] [--dry-run=server|client|none]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Create a karmada with the specified name"),
+		Long:                  createKarmadaLong,
+		Example:               createKarmadaExample,
+		Args:                  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+	cmdutil.AddDryRunFlag(cmd)
+
+	cmd.Flags().StringVar(&o.KarmadaVersion, "karmada-version", o.KarmadaVersion, "The version of the karmada.")
+	cmd.Flags().StringVar(&o.KubernetesVersion, "kubernetes-version", o.KubernetesVersion, "The version of the kube-apiserver of the karmada.")
+	cmd.Flags().StringVar(&o.ImageRepository, "image-repository", o.ImageRepository, "The container registry to pull the images of the karmada from.")
+	cmd.Flags().StringVar(&o.ControlPlaneEndpoint, "control-plane-endpoint", o.ControlPlaneEndpoint, "A stable IP address or DNS name for the karmada-apiserver.")
+	cmd.Flags().StringVar(&o.ServiceSubnet, "service-subnet", o.ServiceSubnet, "The subnet used by the services of the karmada.")
+	cmd.Flags().StringVar(&o.DNSDomain, "dns-domain", o.DNSDomain, "The dns domain used by the services of the karmada.")
+	cmd.Flags().Var(cliflag.NewMapStringBool(&o.FeatureGates), "feature-gates", "A set of key=value pairs that describe the feature gates enabled in the karmada.")
+	return cmd
+}
+
+// Complete fills in the CreateKarmadaOptions from the factory and the arguments.
+func (o *CreateKarmadaOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	o.Name = args[0]
+
+	var err error
+	o.Namespace, o.EnforceNamespace, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.DryRunStrategy, err = cmdutil.GetDryRunStrategy(cmd)
+	if err != nil {
+		return err
+	}
+	cmdutil.PrintFlagsWithDryRunStrategy(o.PrintFlags, o.DryRunStrategy)
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	o.PrintObj = func(obj runtime.Object) error {
+		return printer.PrintObj(obj, o.Out)
+	}
+
+	if o.DryRunStrategy == cmdutil.DryRunClient {
+		return nil
+	}
+	restConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.Client, err = fireflyversioned.NewForConfig(restConfig)
+	return err
+}
+
+// Run executes the `create karmada` command.
+func (o *CreateKarmadaOptions) Run(ctx context.Context) error {
+	karmada := o.newKarmada()
+	if o.DryRunStrategy != cmdutil.DryRunClient {
+		createOptions := metav1.CreateOptions{}
+		if o.DryRunStrategy == cmdutil.DryRunServer {
+			createOptions.DryRun = []string{metav1.DryRunAll}
+		}
+		var err error
+		karmada, err = o.Client.InstallV1alpha1().Karmadas(o.Namespace).Create(ctx, karmada, createOptions)
+		if err != nil {
+			return err
+		}
+	}
+	return o.PrintObj(karmada)
+}
+
+// newKarmada returns the karmada described by the flags.
+func (o *CreateKarmadaOptions) newKarmada() *installv1alpha1.Karmada {
+	return &installv1alpha1.Karmada{
+		TypeMeta: metav1.TypeMeta{APIVersion: installv1alpha1.SchemeGroupVersion.String(), Kind: "Karmada"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      o.Name,
+			Namespace: o.Namespace,
+		},
+		Spec: installv1alpha1.KarmadaSpec{
+			Networking: installv1alpha1.Networking{
+				ServiceSubnet: o.ServiceSubnet,
+				DNSDomain:     o.DNSDomain,
+			},
+			KubernetesVersion:    o.KubernetesVersion,
+			KarmadaVersion:       o.KarmadaVersion,
+			ControlPlaneEndpoint: o.ControlPlaneEndpoint,
+			ImageRepository:      o.ImageRepository,
+			FeatureGates:         o.FeatureGates,
+		},
+	}
+}
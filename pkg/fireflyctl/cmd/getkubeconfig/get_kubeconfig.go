@@ -0,0 +1,243 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package getkubeconfig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/controller/karmada"
+	fireflyversioned "github.com/carlory/firefly/pkg/generated/clientset/versioned"
+	"github.com/carlory/firefly/pkg/util/certs"
+	"github.com/carlory/firefly/pkg/util/kms"
+)
+
+// GetKubeconfigOptions defines flags and other configuration parameters for the `get-kubeconfig` command
+type GetKubeconfigOptions struct {
+	genericclioptions.IOStreams
+
+	Client        kubernetes.Interface
+	FireflyClient fireflyversioned.Interface
+
+	Namespace string
+
+	Karmada  string
+	ReadOnly bool
+	Server   string
+
+	User             string
+	Groups           []string
+	ClusterRole      string
+	BindingNamespace string
+	Expiration       time.Duration
+	Timeout          time.Duration
+
+	KMSProvider string
+	KMSEndpoint string
+}
+
+var (
+	getKubeconfigLong = templates.LongDesc(i18n.T(`
+		Print a kubeconfig of the karmada-apiserver of a karmada.
+
+		By default the admin kubeconfig published by firefly is printed, or the read-only
+		one with --read-only if spec.kubeconfig.readOnly of the karmada is set.
+
+		With --user, a kubeconfig of a new identity is minted instead: its client certificate
+		is requested by a CertificateSigningRequest, which is approved on behalf of the admin
+		of the karmada. With --clusterrole, the identity is bound to the ClusterRole, in the
+		whole karmada or in the namespace given by --binding-namespace.
+
+		If the firefly-controller-manager encrypts the secrets with a KMS, the published
+		kubeconfigs are decrypted with the KMS given by --kms-endpoint.`))
+
+	getKubeconfigExample = templates.Examples(i18n.T(`
+		# Print the admin kubeconfig of the karmada named karmada.
+		fireflyctl get-kubeconfig --karmada karmada -n firefly-system
+
+		# Print the read-only kubeconfig of the karmada, with the server reachable from here.
+		fireflyctl get-kubeconfig --karmada karmada --read-only --server https://karmada.example.com:5443
+
+		# Mint a kubeconfig of jane, who may edit the resources in the namespace team-a for a week.
+		fireflyctl get-kubeconfig --karmada karmada --user jane --clusterrole edit --binding-namespace team-a --expiration 168h`))
+)
+
+// NewGetKubeconfigOptions creates new GetKubeconfigOptions for the `get-kubeconfig` command
+func NewGetKubeconfigOptions(ioStreams genericclioptions.IOStreams) *GetKubeconfigOptions {
+	return &GetKubeconfigOptions{
+		IOStreams:   ioStreams,
+		Expiration:  certs.Duration365d,
+		Timeout:     time.Minute,
+		KMSProvider: kms.KMSv1,
+	}
+}
+
+// NewCmdGetKubeconfig creates the `get-kubeconfig` command
+func NewCmdGetKubeconfig(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewGetKubeconfigOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "get-kubeconfig --karmada NAME [--read-only | --user USER [--clusterrole ROLE]]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Print a kubeconfig of a karmada"),
+		Long:                  getKubeconfigLong,
+		Example:               getKubeconfigExample,
+		Args:                  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Karmada, "karmada", o.Karmada, "The name of the karmada.")
+	cmd.Flags().BoolVar(&o.ReadOnly, "read-only", o.ReadOnly, "If true, print the read-only kubeconfig of the karmada.")
+	cmd.Flags().StringVar(&o.Server, "server", o.Server, "The address of the karmada-apiserver to connect to and to write into the kubeconfig. Defaults to the one of the published kubeconfigs.")
+	cmd.Flags().StringVar(&o.User, "user", o.User, "If set, mint a kubeconfig of the user by a CertificateSigningRequest.")
+	cmd.Flags().StringSliceVar(&o.Groups, "group", o.Groups, "The groups of the minted user, only used with --user.")
+	cmd.Flags().StringVar(&o.ClusterRole, "clusterrole", o.ClusterRole, "The ClusterRole which the minted user is bound to, only used with --user.")
+	cmd.Flags().StringVar(&o.BindingNamespace, "binding-namespace", o.BindingNamespace, "If set, the minted user is only bound to the ClusterRole in this namespace of the karmada.")
+	cmd.Flags().DurationVar(&o.Expiration, "expiration", o.Expiration, "The requested validity of the client certificate of the minted user.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "The length of time to wait for the client certificate of the minted user to be issued.")
+	cmd.Flags().StringVar(&o.KMSProvider, "kms-provider", o.KMSProvider, fmt.Sprintf("The KMS provider which decrypts the published kubeconfigs, one of %s.", strings.Join(kms.Providers(), ", ")))
+	cmd.Flags().StringVar(&o.KMSEndpoint, "kms-endpoint", o.KMSEndpoint, "The endpoint of the KMS which the firefly-controller-manager encrypts the secrets with, required if the published kubeconfigs are encrypted.")
+	cmdutil.CheckErr(cmd.MarkFlagRequired("karmada"))
+	return cmd
+}
+
+// Complete fills in the GetKubeconfigOptions from the factory.
+func (o *GetKubeconfigOptions) Complete(f cmdutil.Factory, cmd *cobra.Command) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.FireflyClient, err = fireflyversioned.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	o.Client, err = f.KubernetesClientSet()
+	return err
+}
+
+// Validate checks the GetKubeconfigOptions for conflicts.
+func (o *GetKubeconfigOptions) Validate() error {
+	if o.User != "" && o.ReadOnly {
+		return fmt.Errorf("--read-only and --user are mutually exclusive")
+	}
+	if o.User == "" && (len(o.Groups) > 0 || o.ClusterRole != "" || o.BindingNamespace != "") {
+		return fmt.Errorf("--group, --clusterrole and --binding-namespace are only used with --user")
+	}
+	if o.BindingNamespace != "" && o.ClusterRole == "" {
+		return fmt.Errorf("--binding-namespace requires --clusterrole")
+	}
+	return nil
+}
+
+// Run executes the `get-kubeconfig` command.
+func (o *GetKubeconfigOptions) Run(ctx context.Context) error {
+	k, err := o.FireflyClient.InstallV1alpha1().Karmadas(o.Namespace).Get(ctx, o.Karmada, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	secretName := karmada.AdminKubeconfigSecretName(k)
+	if o.ReadOnly {
+		if !k.Spec.Kubeconfig.ReadOnly {
+			return fmt.Errorf("the read-only kubeconfig of karmada %s/%s is not published, set spec.kubeconfig.readOnly to publish it", k.Namespace, k.Name)
+		}
+		secretName = karmada.ReadOnlyKubeconfigSecretName(k)
+	}
+	config, err := o.publishedKubeconfig(ctx, k, secretName)
+	if err != nil {
+		return err
+	}
+	if o.User != "" {
+		config, err = o.mintKubeconfig(ctx, k, config)
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := clientcmd.Write(*config)
+	if err != nil {
+		return err
+	}
+	_, err = o.Out.Write(data)
+	return err
+}
+
+// publishedKubeconfig returns the kubeconfig published into the given Secret of the karmada,
+// pointing at --server if it's set.
+func (o *GetKubeconfigOptions) publishedKubeconfig(ctx context.Context, k *installv1alpha1.Karmada, secretName string) (*clientcmdapi.Config, error) {
+	secret, err := o.Client.CoreV1().Secrets(k.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the kubeconfig of karmada %s/%s, is it installed? %v", k.Namespace, k.Name, err)
+	}
+	data := secret.Data["kubeconfig"]
+	if kms.IsEncrypted(data) {
+		if data, err = o.decrypt(ctx, secret); err != nil {
+			return nil, err
+		}
+	}
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the kubeconfig in Secret %s/%s: %v", secret.Namespace, secretName, err)
+	}
+	if o.Server != "" {
+		for _, cluster := range config.Clusters {
+			cluster.Server = o.Server
+		}
+	}
+	return config, nil
+}
+
+// decrypt returns the kubeconfig in the Secret, which is encrypted by the KMS of the firefly-controller-manager.
+func (o *GetKubeconfigOptions) decrypt(ctx context.Context, secret *corev1.Secret) ([]byte, error) {
+	if o.KMSEndpoint == "" {
+		return nil, fmt.Errorf("the kubeconfig in Secret %s/%s is encrypted by a KMS, set --kms-endpoint to decrypt it", secret.Namespace, secret.Name)
+	}
+	provider, err := kms.NewProvider(o.KMSProvider, o.KMSEndpoint, o.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	encrypter, err := kms.NewEncrypter(provider)
+	if err != nil {
+		return nil, err
+	}
+	kms.SetDefault(encrypter)
+	return kms.SecretData(ctx, secret, "kubeconfig")
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package getkubeconfig
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+	"k8s.io/utils/pointer"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/util/certs"
+)
+
+// mintKubeconfig returns a kubeconfig of --user, whose client certificate is issued by the
+// karmada-apiserver. admin is the admin kubeconfig of the karmada, which is used to request
+// and approve the certificate, and to bind the user to --clusterrole.
+func (o *GetKubeconfigOptions) mintKubeconfig(ctx context.Context, k *installv1alpha1.Karmada, admin *clientcmdapi.Config) (*clientcmdapi.Config, error) {
+	restConfig, err := clientcmd.NewDefaultClientConfig(*admin, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	karmadaClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := certs.GeneratePrivateKey(x509.RSA)
+	if err != nil {
+		return nil, err
+	}
+	keyData, err := keyutil.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		return nil, err
+	}
+	certData, err := o.requestCertificate(ctx, karmadaClient, key)
+	if err != nil {
+		return nil, err
+	}
+	if o.ClusterRole != "" {
+		if err := o.ensureBinding(ctx, karmadaClient); err != nil {
+			return nil, err
+		}
+	}
+
+	current, ok := admin.Contexts[admin.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("the admin kubeconfig of karmada %s/%s has no current context", k.Namespace, k.Name)
+	}
+	cluster, ok := admin.Clusters[current.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("the admin kubeconfig of karmada %s/%s has no cluster %q", k.Namespace, k.Name, current.Cluster)
+	}
+	return certs.CreateWithCerts(cluster.Server, o.User, "karmada", cluster.CertificateAuthorityData, keyData, certData), nil
+}
+
+// requestCertificate requests a client certificate of --user for the key, approves the request
+// and waits until the certificate is issued.
+func (o *GetKubeconfigOptions) requestCertificate(ctx context.Context, client kubernetes.Interface, key interface{}) ([]byte, error) {
+	csrData, err := certutil.MakeCSR(key, &pkix.Name{CommonName: o.User, Organization: o.Groups}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	csr := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "fireflyctl-",
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:           csrData,
+			SignerName:        certificatesv1.KubeAPIServerClientSignerName,
+			ExpirationSeconds: pointer.Int32(int32(o.Expiration.Seconds())),
+			Usages: []certificatesv1.KeyUsage{
+				certificatesv1.UsageDigitalSignature,
+				certificatesv1.UsageKeyEncipherment,
+				certificatesv1.UsageClientAuth,
+			},
+		},
+	}
+	csr, err = client.CertificatesV1().CertificateSigningRequests().Create(ctx, csr, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to request a certificate of %s: %v", o.User, err)
+	}
+
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Type:    certificatesv1.CertificateApproved,
+		Status:  corev1.ConditionTrue,
+		Reason:  "FireflyctlApprove",
+		Message: "This CSR was approved by fireflyctl get-kubeconfig",
+	})
+	if _, err := client.CertificatesV1().CertificateSigningRequests().UpdateApproval(ctx, csr.Name, csr, metav1.UpdateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to approve CertificateSigningRequest %s: %v", csr.Name, err)
+	}
+
+	var certData []byte
+	err = wait.PollImmediate(constants.APICallRetryInterval, o.Timeout, func() (bool, error) {
+		got, err := client.CertificatesV1().CertificateSigningRequests().Get(ctx, csr.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, c := range got.Status.Conditions {
+			if c.Type == certificatesv1.CertificateDenied || c.Type == certificatesv1.CertificateFailed {
+				return false, fmt.Errorf("CertificateSigningRequest %s is %s: %s", csr.Name, c.Type, c.Message)
+			}
+		}
+		certData = got.Status.Certificate
+		return len(certData) > 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return nil, fmt.Errorf("timed out waiting for CertificateSigningRequest %s to be issued, is the csrsigning controller of the karmada enabled?", csr.Name)
+	}
+	return certData, err
+}
+
+// ensureBinding binds --user to --clusterrole in the karmada, or in --binding-namespace if it's set.
+func (o *GetKubeconfigOptions) ensureBinding(ctx context.Context, client kubernetes.Interface) error {
+	objectMeta := metav1.ObjectMeta{Name: fmt.Sprintf("fireflyctl:%s:%s", o.User, o.ClusterRole)}
+	subjects := []rbacv1.Subject{
+		{
+			Kind:     rbacv1.UserKind,
+			Name:     o.User,
+			APIGroup: rbacv1.GroupName,
+		},
+	}
+	roleRef := rbacv1.RoleRef{
+		Kind:     "ClusterRole",
+		Name:     o.ClusterRole,
+		APIGroup: rbacv1.GroupName,
+	}
+
+	var err error
+	if o.BindingNamespace == "" {
+		crb := &rbacv1.ClusterRoleBinding{ObjectMeta: objectMeta, Subjects: subjects, RoleRef: roleRef}
+		_, err = client.RbacV1().ClusterRoleBindings().Create(ctx, crb, metav1.CreateOptions{})
+	} else {
+		objectMeta.Namespace = o.BindingNamespace
+		rb := &rbacv1.RoleBinding{ObjectMeta: objectMeta, Subjects: subjects, RoleRef: roleRef}
+		_, err = client.RbacV1().RoleBindings(o.BindingNamespace).Create(ctx, rb, metav1.CreateOptions{})
+	}
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to bind %s to ClusterRole %s: %v", o.User, o.ClusterRole, err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initialize
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	clientset "k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/carlory/firefly/deploy"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/apply"
+)
+
+// FieldManager is the field manager of the objects applied by fireflyctl.
+const FieldManager = "fireflyctl"
+
+// InitOptions defines flags and other configuration parameters for the `init` command
+type InitOptions struct {
+	genericclioptions.IOStreams
+
+	Applier *apply.Applier
+	Client  clientset.Interface
+
+	Wait    bool
+	Timeout time.Duration
+}
+
+var (
+	initLong = templates.LongDesc(i18n.T(`
+		Install the firefly crds, controller manager and webhook into the host cluster.
+
+		The manifests are applied with server-side apply, so running init again upgrades
+		firefly to the manifests of this fireflyctl. The webhook requires cert-manager to
+		issue its serving certificate.`))
+
+	initExample = templates.Examples(i18n.T(`
+		# Install firefly into the cluster of the current context.
+		fireflyctl init
+
+		# Install firefly and wait until its components are running.
+		fireflyctl init --wait --timeout=5m`))
+)
+
+// NewInitOptions creates new InitOptions for the `init` command
+func NewInitOptions(ioStreams genericclioptions.IOStreams) *InitOptions {
+	return &InitOptions{
+		IOStreams: ioStreams,
+		Timeout:   5 * time.Minute,
+	}
+}
+
+// NewCmdInit creates the `init` command
+func NewCmdInit(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewInitOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "init",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Install firefly into the host cluster"),
+		Long:                  initLong,
+		Example:               initExample,
+		Args:                  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd))
+			cmdutil.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.Wait, "wait", o.Wait, "If true, wait until the pods of the components of firefly are running.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "The length of time to wait for the components of firefly, only used with --wait.")
+	return cmd
+}
+
+// Complete fills in the InitOptions from the factory.
+func (o *InitOptions) Complete(f cmdutil.Factory, cmd *cobra.Command) error {
+	restConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.Applier, err = apply.NewApplier(restConfig, FieldManager)
+	if err != nil {
+		return err
+	}
+	o.Client, err = f.KubernetesClientSet()
+	return err
+}
+
+// Run executes the `init` command.
+func (o *InitOptions) Run(ctx context.Context) error {
+	objs, err := deploy.Objects()
+	if err != nil {
+		return err
+	}
+
+	var deployments []*unstructured.Unstructured
+	for _, obj := range objs {
+		if _, err := o.Applier.Apply(ctx, obj); err != nil {
+			if meta.IsNoMatchError(err) && strings.HasSuffix(obj.GroupVersionKind().Group, "cert-manager.io") {
+				return fmt.Errorf("the webhook of firefly requires cert-manager, please install it first: %v", err)
+			}
+			return err
+		}
+		fmt.Fprintf(o.Out, "%s/%s applied\n", strings.ToLower(obj.GetKind()), obj.GetName())
+		if obj.GetKind() == "Deployment" {
+			deployments = append(deployments, obj)
+		}
+	}
+
+	if !o.Wait {
+		return nil
+	}
+	waiter := util.NewKubeWaiter(o.Client, o.Timeout)
+	for _, d := range deployments {
+		matchLabels, _, err := unstructured.NestedStringMap(d.Object, "spec", "selector", "matchLabels")
+		if err != nil {
+			return err
+		}
+		if err := waiter.WaitForPodsWithLabel(d.GetNamespace(), labels.SelectorFromSet(matchLabels).String()); err != nil {
+			return fmt.Errorf("timed out waiting for the pods of deployment %s/%s: %v", d.GetNamespace(), d.GetName(), err)
+		}
+		fmt.Fprintf(o.Out, "deployment/%s is running\n", d.GetName())
+	}
+	return nil
+}
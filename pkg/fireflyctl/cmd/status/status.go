@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	clientset "k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/carlory/firefly/deploy"
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	fireflyversioned "github.com/carlory/firefly/pkg/generated/clientset/versioned"
+	phaseutil "github.com/carlory/firefly/pkg/util/phase"
+)
+
+// StatusOptions defines flags and other configuration parameters for the `status` command
+type StatusOptions struct {
+	genericclioptions.IOStreams
+
+	Client        clientset.Interface
+	FireflyClient fireflyversioned.Interface
+
+	Namespace     string
+	AllNamespaces bool
+}
+
+var (
+	statusLong = templates.LongDesc(i18n.T(`
+		Print the readiness of the components of firefly, and of the karmadas, clusterpedias
+		and addons which it installs.
+
+		The readiness of an installation is read from its status: it's Ready once the controller
+		has observed its latest generation and installed the requested version. The conditions
+		reported by the controller are printed as well.`))
+
+	statusExample = templates.Examples(i18n.T(`
+		# Print the status of firefly and of the installations in the current namespace.
+		fireflyctl status
+
+		# Print the status of firefly and of the installations in all namespaces.
+		fireflyctl status -A`))
+)
+
+// NewStatusOptions creates new StatusOptions for the `status` command
+func NewStatusOptions(ioStreams genericclioptions.IOStreams) *StatusOptions {
+	return &StatusOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdStatus creates the `status` command
+func NewCmdStatus(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewStatusOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "status [-A]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Print the readiness of firefly and of the installations it manages"),
+		Long:                  statusLong,
+		Example:               statusExample,
+		Args:                  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd))
+			cmdutil.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, print the installations across all namespaces.")
+	return cmd
+}
+
+// Complete fills in the StatusOptions from the factory.
+func (o *StatusOptions) Complete(f cmdutil.Factory, cmd *cobra.Command) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+	if o.AllNamespaces {
+		o.Namespace = metav1.NamespaceAll
+	}
+
+	restConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.FireflyClient, err = fireflyversioned.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	o.Client, err = f.KubernetesClientSet()
+	return err
+}
+
+// Run executes the `status` command.
+func (o *StatusOptions) Run(ctx context.Context) error {
+	w := printers.GetNewTabWriter(o.Out)
+	if err := o.printComponents(ctx, w); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(o.Out)
+	if err := o.printInstallations(ctx, w); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// printComponents prints the readiness of the deployments of firefly.
+func (o *StatusOptions) printComponents(ctx context.Context, w io.Writer) error {
+	objs, err := deploy.Objects()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "COMPONENT\tREADY\tUP-TO-DATE\tAVAILABLE")
+	for _, obj := range objs {
+		if obj.GetKind() != "Deployment" {
+			continue
+		}
+		d, err := o.Client.AppsV1().Deployments(obj.GetNamespace()).Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			fmt.Fprintf(w, "%s\t<not installed>\t\t\n", obj.GetName())
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		var replicas int32 = 1
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		fmt.Fprintf(w, "%s\t%d/%d\t%d\t%d\n", d.Name, d.Status.ReadyReplicas, replicas, d.Status.UpdatedReplicas, d.Status.AvailableReplicas)
+	}
+	return nil
+}
+
+// printInstallations prints the readiness of the karmadas, clusterpedias and addons.
+func (o *StatusOptions) printInstallations(ctx context.Context, w io.Writer) error {
+	client := o.FireflyClient.InstallV1alpha1()
+	karmadas, err := client.Karmadas(o.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	clusterpedias, err := client.Clusterpedias(o.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	addons, err := client.Addons(o.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	if len(karmadas.Items)+len(clusterpedias.Items)+len(addons.Items) == 0 {
+		fmt.Fprintln(o.ErrOut, "No installations found.")
+		return nil
+	}
+
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tSTATUS\tVERSION\tCONDITIONS")
+	for _, k := range karmadas.Items {
+		phase := phaseutil.Summarize(&k.ObjectMeta, k.Status.ObservedGeneration, k.Status.Conditions, k.Status.KarmadaVersion == k.Spec.KarmadaVersion)
+		fmt.Fprintf(w, "%s\tkarmada/%s\t%s\t%s\t%s\n", k.Namespace, k.Name, phase, valueOrNone(k.Status.KarmadaVersion), conditions(k.Status.Conditions))
+	}
+	for _, cp := range clusterpedias.Items {
+		phase := phaseutil.Summarize(&cp.ObjectMeta, cp.Status.ObservedGeneration, cp.Status.Conditions, cp.Status.Version == cp.Spec.Version)
+		fmt.Fprintf(w, "%s\tclusterpedia/%s\t%s\t%s\t%s\n", cp.Namespace, cp.Name, phase, valueOrNone(cp.Status.Version), conditions(cp.Status.Conditions))
+	}
+	for _, addon := range addons.Items {
+		ready := meta.IsStatusConditionTrue(addon.Status.Conditions, installv1alpha1.AddonConditionReady)
+		phase := phaseutil.Summarize(&addon.ObjectMeta, addon.Status.ObservedGeneration, addon.Status.Conditions, ready)
+		fmt.Fprintf(w, "%s\taddon/%s\t%s\t%s\t%s\n", addon.Namespace, addon.Name, phase, "<none>", conditions(addon.Status.Conditions))
+	}
+	return nil
+}
+
+// conditions formats the conditions as a comma-separated list of type=status.
+func conditions(conditions []metav1.Condition) string {
+	if len(conditions) == 0 {
+		return "<none>"
+	}
+	list := make([]string, 0, len(conditions))
+	for _, c := range conditions {
+		list = append(list, fmt.Sprintf("%s=%s", c.Type, c.Status))
+	}
+	return strings.Join(list, ",")
+}
+
+func valueOrNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uninstall
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/carlory/firefly/deploy"
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/fireflyctl/cmd/initialize"
+	fireflyversioned "github.com/carlory/firefly/pkg/generated/clientset/versioned"
+	"github.com/carlory/firefly/pkg/util/apply"
+)
+
+// UninstallOptions defines flags and other configuration parameters for the `uninstall` command
+type UninstallOptions struct {
+	genericclioptions.IOStreams
+
+	Applier       *apply.Applier
+	FireflyClient fireflyversioned.Interface
+
+	Timeout time.Duration
+}
+
+var (
+	uninstallLong = templates.LongDesc(i18n.T(`
+		Uninstall firefly and everything it installed from the host cluster.
+
+		The karmadas, clusterpedias and addons are deleted first, and uninstall waits until
+		the controller manager has torn them down and released their finalizers. Then the
+		components and the crds of firefly are deleted. If the installations are not gone
+		within the timeout, nothing else is deleted, so that running uninstall again resumes
+		the teardown.`))
+
+	uninstallExample = templates.Examples(i18n.T(`
+		# Uninstall firefly from the cluster of the current context.
+		fireflyctl uninstall
+
+		# Uninstall firefly, waiting up to 30 minutes for the installations to be torn down.
+		fireflyctl uninstall --timeout=30m`))
+)
+
+// NewUninstallOptions creates new UninstallOptions for the `uninstall` command
+func NewUninstallOptions(ioStreams genericclioptions.IOStreams) *UninstallOptions {
+	return &UninstallOptions{
+		IOStreams: ioStreams,
+		Timeout:   10 * time.Minute,
+	}
+}
+
+// NewCmdUninstall creates the `uninstall` command
+func NewCmdUninstall(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewUninstallOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "uninstall",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Uninstall firefly and the installations it manages from the host cluster"),
+		Long:                  uninstallLong,
+		Example:               uninstallExample,
+		Args:                  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd))
+			cmdutil.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "The length of time to wait for the karmadas, clusterpedias and addons to be torn down.")
+	return cmd
+}
+
+// Complete fills in the UninstallOptions from the factory.
+func (o *UninstallOptions) Complete(f cmdutil.Factory, cmd *cobra.Command) error {
+	restConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.Applier, err = apply.NewApplier(restConfig, initialize.FieldManager)
+	if err != nil {
+		return err
+	}
+	o.FireflyClient, err = fireflyversioned.NewForConfig(restConfig)
+	return err
+}
+
+// Run executes the `uninstall` command.
+func (o *UninstallOptions) Run(ctx context.Context) error {
+	// the addons and the clusterpedias may be installed into a karmada, so they're torn
+	// down before the karmadas.
+	if err := o.deleteInstallations(ctx, "Addon", "Clusterpedia"); err != nil {
+		return err
+	}
+	if err := o.deleteInstallations(ctx, "Karmada"); err != nil {
+		return err
+	}
+
+	objs, err := deploy.Objects()
+	if err != nil {
+		return err
+	}
+	// delete in reverse install order, so that the crds and the namespace are deleted last.
+	for i := len(objs) - 1; i >= 0; i-- {
+		obj := objs[i]
+		if err := o.Applier.Delete(ctx, obj); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "%s/%s deleted\n", strings.ToLower(obj.GetKind()), obj.GetName())
+	}
+	return nil
+}
+
+// deleteInstallations deletes the installations of the given kinds in all namespaces and
+// waits until they are gone. Their finalizers are released by the controller manager once
+// it has torn them down, so it must be running.
+func (o *UninstallOptions) deleteInstallations(ctx context.Context, kinds ...string) error {
+	installations, err := o.listInstallations(ctx, kinds...)
+	if err != nil {
+		return err
+	}
+	for _, obj := range installations {
+		if err := o.Applier.Delete(ctx, obj); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "%s/%s/%s deleted\n", strings.ToLower(obj.GetKind()), obj.GetNamespace(), obj.GetName())
+	}
+
+	err = wait.PollImmediate(constants.APICallRetryInterval, o.Timeout, func() (bool, error) {
+		installations, err = o.listInstallations(ctx, kinds...)
+		if err != nil {
+			return false, err
+		}
+		return len(installations) == 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		remaining := make([]string, 0, len(installations))
+		for _, obj := range installations {
+			remaining = append(remaining, fmt.Sprintf("%s/%s/%s", strings.ToLower(obj.GetKind()), obj.GetNamespace(), obj.GetName()))
+		}
+		return fmt.Errorf("timed out waiting for %s to be torn down, is the firefly controller manager running?", strings.Join(remaining, ", "))
+	}
+	return err
+}
+
+// listInstallations returns the installations of the given kinds in all namespaces.
+// There are none if the crd of a kind is not installed.
+func (o *UninstallOptions) listInstallations(ctx context.Context, kinds ...string) ([]*unstructured.Unstructured, error) {
+	client := o.FireflyClient.InstallV1alpha1()
+	var objs []*unstructured.Unstructured
+	add := func(kind string, obj metav1.Object) {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(installv1alpha1.SchemeGroupVersion.WithKind(kind))
+		u.SetNamespace(obj.GetNamespace())
+		u.SetName(obj.GetName())
+		objs = append(objs, u)
+	}
+	for _, kind := range kinds {
+		switch kind {
+		case "Addon":
+			list, err := client.Addons(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, err
+			}
+			for i := range list.Items {
+				add(kind, &list.Items[i])
+			}
+		case "Clusterpedia":
+			list, err := client.Clusterpedias(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, err
+			}
+			for i := range list.Items {
+				add(kind, &list.Items[i])
+			}
+		case "Karmada":
+			list, err := client.Karmadas(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, err
+			}
+			for i := range list.Items {
+				add(kind, &list.Items[i])
+			}
+		}
+	}
+	return objs, nil
+}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/kubectl/pkg/util/templates"
 
 	"github.com/carlory/firefly/pkg/fkactl/cmd/options"
+	"github.com/carlory/firefly/pkg/fkactl/cmd/render"
 	"github.com/carlory/firefly/pkg/fkactl/cmd/validate"
 )
 
@@ -68,6 +69,12 @@ func NewFkactlCommand(in io.Reader, out, err io.Writer) *cobra.Command {
 				validate.NewCmdValidate(f, ioStreams),
 			},
 		},
+		{
+			Message: "Installation Commands:",
+			Commands: []*cobra.Command{
+				render.NewCmdRender(f, ioStreams),
+			},
+		},
 	}
 
 	groups.Add(cmds)
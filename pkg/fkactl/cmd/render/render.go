@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/controller/clusterpedia"
+	"github.com/carlory/firefly/pkg/controller/karmada"
+	fireflyscheme "github.com/carlory/firefly/pkg/generated/clientset/versioned/scheme"
+	"github.com/carlory/firefly/pkg/helm"
+	"github.com/carlory/firefly/pkg/util/manifest"
+)
+
+// RenderOptions defines flags and other configuration parameters for the `render` command
+type RenderOptions struct {
+	genericclioptions.IOStreams
+
+	Builder *resource.Builder
+	Fetcher *helm.Fetcher
+
+	Namespace        string
+	EnforceNamespace bool
+
+	FilenameOptions resource.FilenameOptions
+}
+
+var (
+	renderLong = templates.LongDesc(i18n.T(`
+		Render the manifests which the controllers apply to the host cluster for a Karmada or
+		a Clusterpedia, without applying them.
+
+		The output is a multi-document YAML, which can be committed to a GitOps repository
+		or diffed in CI. The certificates and the kubeconfigs generated at install time, and
+		the objects applied to the control planes once they're up, are not rendered.`))
+
+	renderExample = templates.Examples(i18n.T(`
+		# Render the manifests of the karmada in karmada.yaml.
+		fkactl render -f ./karmada.yaml
+
+		# Render the manifests of the clusterpedia passed into stdin.
+		cat clusterpedia.yaml | fkactl render -f -`))
+)
+
+// NewRenderOptions creates new RenderOptions for the `render` command
+func NewRenderOptions(ioStreams genericclioptions.IOStreams) *RenderOptions {
+	return &RenderOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdRender creates the `render` command
+func NewCmdRender(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewRenderOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "render -f FILENAME",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Render the manifests of a Karmada or a Clusterpedia by filename or stdin"),
+		Long:                  renderLong,
+		Example:               renderExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd))
+			cmdutil.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	cmdutil.AddFilenameOptionFlags(cmd, &o.FilenameOptions, "containing the Karmada or the Clusterpedia to render")
+	return cmd
+}
+
+// Complete fills in the RenderOptions from the factory.
+func (o *RenderOptions) Complete(f cmdutil.Factory, cmd *cobra.Command) error {
+	var err error
+	o.Namespace, o.EnforceNamespace, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+	// the objects are only decoded, nothing is read from the server.
+	o.Builder = f.NewBuilder().WithScheme(fireflyscheme.Scheme, installv1alpha1.SchemeGroupVersion).Local()
+	o.Fetcher = helm.NewFetcher()
+	return nil
+}
+
+// Run executes the `render` command.
+func (o *RenderOptions) Run(ctx context.Context) error {
+	infos, err := o.Builder.
+		NamespaceParam(o.Namespace).DefaultNamespace().
+		FilenameParam(o.EnforceNamespace, &o.FilenameOptions).
+		Flatten().
+		Do().Infos()
+	if err != nil {
+		return err
+	}
+	if len(infos) == 0 {
+		return fmt.Errorf("no objects passed to render")
+	}
+
+	var objs []runtime.Object
+	for _, info := range infos {
+		var rendered []runtime.Object
+		switch obj := info.Object.(type) {
+		case *installv1alpha1.Karmada:
+			obj.Namespace = info.Namespace
+			rendered, err = karmada.RenderManifests(ctx, o.Fetcher, obj)
+		case *installv1alpha1.Clusterpedia:
+			obj.Namespace = info.Namespace
+			rendered, err = clusterpedia.RenderManifests(ctx, o.Fetcher, obj)
+		default:
+			return fmt.Errorf("%s %q is neither a Karmada nor a Clusterpedia", info.Object.GetObjectKind().GroupVersionKind().Kind, info.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to render %s/%s: %v", info.Namespace, info.Name, err)
+		}
+		objs = append(objs, rendered...)
+	}
+	return manifest.WriteYAML(o.Out, objs)
+}
@@ -20,9 +20,12 @@ package v1alpha1
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 	"time"
 
 	v1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	installv1alpha1 "github.com/carlory/firefly/pkg/generated/applyconfiguration/install/v1alpha1"
 	scheme "github.com/carlory/firefly/pkg/generated/clientset/versioned/scheme"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
@@ -47,6 +50,8 @@ type ClusterpediaInterface interface {
 	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.ClusterpediaList, error)
 	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
 	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.Clusterpedia, err error)
+	Apply(ctx context.Context, clusterpedia *installv1alpha1.ClusterpediaApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Clusterpedia, err error)
+	ApplyStatus(ctx context.Context, clusterpedia *installv1alpha1.ClusterpediaApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Clusterpedia, err error)
 	ClusterpediaExpansion
 }
 
@@ -193,3 +198,59 @@ func (c *clusterpedias) Patch(ctx context.Context, name string, pt types.PatchTy
 		Into(result)
 	return
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied clusterpedia.
+func (c *clusterpedias) Apply(ctx context.Context, clusterpedia *installv1alpha1.ClusterpediaApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Clusterpedia, err error) {
+	if clusterpedia == nil {
+		return nil, fmt.Errorf("clusterpedia provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(clusterpedia)
+	if err != nil {
+		return nil, err
+	}
+	name := clusterpedia.Name
+	if name == nil {
+		return nil, fmt.Errorf("clusterpedia.Name must be provided to Apply")
+	}
+	result = &v1alpha1.Clusterpedia{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("clusterpedias").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *clusterpedias) ApplyStatus(ctx context.Context, clusterpedia *installv1alpha1.ClusterpediaApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Clusterpedia, err error) {
+	if clusterpedia == nil {
+		return nil, fmt.Errorf("clusterpedia provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(clusterpedia)
+	if err != nil {
+		return nil, err
+	}
+
+	name := clusterpedia.Name
+	if name == nil {
+		return nil, fmt.Errorf("clusterpedia.Name must be provided to Apply")
+	}
+
+	result = &v1alpha1.Clusterpedia{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("clusterpedias").
+		Name(*name).
+		SubResource("status").
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
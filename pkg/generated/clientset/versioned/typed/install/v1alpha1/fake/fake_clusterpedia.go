@@ -20,8 +20,11 @@ package fake
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 
 	v1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	installv1alpha1 "github.com/carlory/firefly/pkg/generated/applyconfiguration/install/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -140,3 +143,48 @@ func (c *FakeClusterpedias) Patch(ctx context.Context, name string, pt types.Pat
 	}
 	return obj.(*v1alpha1.Clusterpedia), err
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied clusterpedia.
+func (c *FakeClusterpedias) Apply(ctx context.Context, clusterpedia *installv1alpha1.ClusterpediaApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Clusterpedia, err error) {
+	if clusterpedia == nil {
+		return nil, fmt.Errorf("clusterpedia provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(clusterpedia)
+	if err != nil {
+		return nil, err
+	}
+	name := clusterpedia.Name
+	if name == nil {
+		return nil, fmt.Errorf("clusterpedia.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(clusterpediasResource, c.ns, *name, types.ApplyPatchType, data), &v1alpha1.Clusterpedia{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Clusterpedia), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakeClusterpedias) ApplyStatus(ctx context.Context, clusterpedia *installv1alpha1.ClusterpediaApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Clusterpedia, err error) {
+	if clusterpedia == nil {
+		return nil, fmt.Errorf("clusterpedia provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(clusterpedia)
+	if err != nil {
+		return nil, err
+	}
+	name := clusterpedia.Name
+	if name == nil {
+		return nil, fmt.Errorf("clusterpedia.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(clusterpediasResource, c.ns, *name, types.ApplyPatchType, data, "status"), &v1alpha1.Clusterpedia{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Clusterpedia), err
+}
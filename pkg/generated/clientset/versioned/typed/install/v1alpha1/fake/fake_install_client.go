@@ -28,6 +28,14 @@ type FakeInstallV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeInstallV1alpha1) Addons(namespace string) v1alpha1.AddonInterface {
+	return &FakeAddons{c, namespace}
+}
+
+func (c *FakeInstallV1alpha1) ClusterRegistrations(namespace string) v1alpha1.ClusterRegistrationInterface {
+	return &FakeClusterRegistrations{c, namespace}
+}
+
 func (c *FakeInstallV1alpha1) Clusterpedias(namespace string) v1alpha1.ClusterpediaInterface {
 	return &FakeClusterpedias{c, namespace}
 }
@@ -36,6 +44,14 @@ func (c *FakeInstallV1alpha1) Karmadas(namespace string) v1alpha1.KarmadaInterfa
 	return &FakeKarmadas{c, namespace}
 }
 
+func (c *FakeInstallV1alpha1) SecretSyncs(namespace string) v1alpha1.SecretSyncInterface {
+	return &FakeSecretSyncs{c, namespace}
+}
+
+func (c *FakeInstallV1alpha1) Submariners(namespace string) v1alpha1.SubmarinerInterface {
+	return &FakeSubmariners{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeInstallV1alpha1) RESTClient() rest.Interface {
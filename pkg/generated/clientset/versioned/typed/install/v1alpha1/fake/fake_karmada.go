@@ -20,8 +20,11 @@ package fake
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 
 	v1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	installv1alpha1 "github.com/carlory/firefly/pkg/generated/applyconfiguration/install/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -140,3 +143,48 @@ func (c *FakeKarmadas) Patch(ctx context.Context, name string, pt types.PatchTyp
 	}
 	return obj.(*v1alpha1.Karmada), err
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied karmada.
+func (c *FakeKarmadas) Apply(ctx context.Context, karmada *installv1alpha1.KarmadaApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Karmada, err error) {
+	if karmada == nil {
+		return nil, fmt.Errorf("karmada provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(karmada)
+	if err != nil {
+		return nil, err
+	}
+	name := karmada.Name
+	if name == nil {
+		return nil, fmt.Errorf("karmada.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(karmadasResource, c.ns, *name, types.ApplyPatchType, data), &v1alpha1.Karmada{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Karmada), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakeKarmadas) ApplyStatus(ctx context.Context, karmada *installv1alpha1.KarmadaApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Karmada, err error) {
+	if karmada == nil {
+		return nil, fmt.Errorf("karmada provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(karmada)
+	if err != nil {
+		return nil, err
+	}
+	name := karmada.Name
+	if name == nil {
+		return nil, fmt.Errorf("karmada.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(karmadasResource, c.ns, *name, types.ApplyPatchType, data, "status"), &v1alpha1.Karmada{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Karmada), err
+}
@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1alpha1
 
+type AddonExpansion interface{}
+
+type ClusterRegistrationExpansion interface{}
+
 type ClusterpediaExpansion interface{}
 
 type KarmadaExpansion interface{}
+
+type SecretSyncExpansion interface{}
+
+type SubmarinerExpansion interface{}
@@ -28,8 +28,12 @@ import (
 
 type InstallV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	AddonsGetter
+	ClusterRegistrationsGetter
 	ClusterpediasGetter
 	KarmadasGetter
+	SecretSyncsGetter
+	SubmarinersGetter
 }
 
 // InstallV1alpha1Client is used to interact with features provided by the install.firefly.io group.
@@ -37,6 +41,14 @@ type InstallV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *InstallV1alpha1Client) Addons(namespace string) AddonInterface {
+	return newAddons(c, namespace)
+}
+
+func (c *InstallV1alpha1Client) ClusterRegistrations(namespace string) ClusterRegistrationInterface {
+	return newClusterRegistrations(c, namespace)
+}
+
 func (c *InstallV1alpha1Client) Clusterpedias(namespace string) ClusterpediaInterface {
 	return newClusterpedias(c, namespace)
 }
@@ -45,6 +57,14 @@ func (c *InstallV1alpha1Client) Karmadas(namespace string) KarmadaInterface {
 	return newKarmadas(c, namespace)
 }
 
+func (c *InstallV1alpha1Client) SecretSyncs(namespace string) SecretSyncInterface {
+	return newSecretSyncs(c, namespace)
+}
+
+func (c *InstallV1alpha1Client) Submariners(namespace string) SubmarinerInterface {
+	return newSubmariners(c, namespace)
+}
+
 // NewForConfig creates a new InstallV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -53,10 +53,18 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=install.firefly.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("addons"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Install().V1alpha1().Addons().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("clusterregistrations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Install().V1alpha1().ClusterRegistrations().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("clusterpedias"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Install().V1alpha1().Clusterpedias().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("karmadas"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Install().V1alpha1().Karmadas().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("secretsyncs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Install().V1alpha1().SecretSyncs().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("submariners"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Install().V1alpha1().Submariners().Informer()}, nil
 
 	}
 
@@ -24,10 +24,18 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// Addons returns a AddonInformer.
+	Addons() AddonInformer
+	// ClusterRegistrations returns a ClusterRegistrationInformer.
+	ClusterRegistrations() ClusterRegistrationInformer
 	// Clusterpedias returns a ClusterpediaInformer.
 	Clusterpedias() ClusterpediaInformer
 	// Karmadas returns a KarmadaInformer.
 	Karmadas() KarmadaInformer
+	// SecretSyncs returns a SecretSyncInformer.
+	SecretSyncs() SecretSyncInformer
+	// Submariners returns a SubmarinerInformer.
+	Submariners() SubmarinerInformer
 }
 
 type version struct {
@@ -41,6 +49,16 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// Addons returns a AddonInformer.
+func (v *version) Addons() AddonInformer {
+	return &addonInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ClusterRegistrations returns a ClusterRegistrationInformer.
+func (v *version) ClusterRegistrations() ClusterRegistrationInformer {
+	return &clusterRegistrationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Clusterpedias returns a ClusterpediaInformer.
 func (v *version) Clusterpedias() ClusterpediaInformer {
 	return &clusterpediaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -50,3 +68,13 @@ func (v *version) Clusterpedias() ClusterpediaInformer {
 func (v *version) Karmadas() KarmadaInformer {
 	return &karmadaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// SecretSyncs returns a SecretSyncInformer.
+func (v *version) SecretSyncs() SecretSyncInformer {
+	return &secretSyncInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// Submariners returns a SubmarinerInformer.
+func (v *version) Submariners() SubmarinerInformer {
+	return &submarinerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -18,6 +18,22 @@ limitations under the License.
 
 package v1alpha1
 
+// AddonListerExpansion allows custom methods to be added to
+// AddonLister.
+type AddonListerExpansion interface{}
+
+// AddonNamespaceListerExpansion allows custom methods to be added to
+// AddonNamespaceLister.
+type AddonNamespaceListerExpansion interface{}
+
+// ClusterRegistrationListerExpansion allows custom methods to be added to
+// ClusterRegistrationLister.
+type ClusterRegistrationListerExpansion interface{}
+
+// ClusterRegistrationNamespaceListerExpansion allows custom methods to be added to
+// ClusterRegistrationNamespaceLister.
+type ClusterRegistrationNamespaceListerExpansion interface{}
+
 // ClusterpediaListerExpansion allows custom methods to be added to
 // ClusterpediaLister.
 type ClusterpediaListerExpansion interface{}
@@ -33,3 +49,19 @@ type KarmadaListerExpansion interface{}
 // KarmadaNamespaceListerExpansion allows custom methods to be added to
 // KarmadaNamespaceLister.
 type KarmadaNamespaceListerExpansion interface{}
+
+// SecretSyncListerExpansion allows custom methods to be added to
+// SecretSyncLister.
+type SecretSyncListerExpansion interface{}
+
+// SecretSyncNamespaceListerExpansion allows custom methods to be added to
+// SecretSyncNamespaceLister.
+type SecretSyncNamespaceListerExpansion interface{}
+
+// SubmarinerListerExpansion allows custom methods to be added to
+// SubmarinerLister.
+type SubmarinerListerExpansion interface{}
+
+// SubmarinerNamespaceListerExpansion allows custom methods to be added to
+// SubmarinerNamespaceLister.
+type SubmarinerNamespaceListerExpansion interface{}
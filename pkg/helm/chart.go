@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Metadata is the content of Chart.yaml.
+type Metadata struct {
+	APIVersion   string                   `json:"apiVersion,omitempty"`
+	Name         string                   `json:"name"`
+	Version      string                   `json:"version"`
+	AppVersion   string                   `json:"appVersion,omitempty"`
+	KubeVersion  string                   `json:"kubeVersion,omitempty"`
+	Description  string                   `json:"description,omitempty"`
+	Type         string                   `json:"type,omitempty"`
+	Dependencies []map[string]interface{} `json:"dependencies,omitempty"`
+}
+
+// File is a file in a chart.
+type File struct {
+	// Name is the path of the file relative to the root of the chart.
+	Name string
+	Data []byte
+}
+
+// Chart is a helm chart loaded into memory.
+type Chart struct {
+	Metadata *Metadata
+	// Values is the default values of the chart, i.e. the content of values.yaml.
+	Values map[string]interface{}
+	// Templates is the files under templates/.
+	Templates []*File
+	// Files is all other files of the chart.
+	Files []*File
+}
+
+// LoadDir loads a chart from the given directory.
+func LoadDir(dir string) (*Chart, error) {
+	var files []*File
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, &File{Name: filepath.ToSlash(rel), Data: data})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loadFiles(files)
+}
+
+// LoadArchive loads a chart from a gzipped tarball, which is the format of a packaged chart.
+func LoadArchive(r io.Reader) (*Chart, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var files []*File
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		// A packaged chart has a single top-level directory named after the chart.
+		name := path.Clean(hdr.Name)
+		if i := strings.Index(name, "/"); i >= 0 {
+			name = name[i+1:]
+		}
+		buf := &bytes.Buffer{}
+		if _, err := io.Copy(buf, tr); err != nil {
+			return nil, err
+		}
+		files = append(files, &File{Name: name, Data: buf.Bytes()})
+	}
+	return loadFiles(files)
+}
+
+func loadFiles(files []*File) (*Chart, error) {
+	chart := &Chart{Values: map[string]interface{}{}}
+	for _, f := range files {
+		switch {
+		case f.Name == "Chart.yaml":
+			chart.Metadata = &Metadata{}
+			if err := yaml.Unmarshal(f.Data, chart.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to parse Chart.yaml: %v", err)
+			}
+		case f.Name == "values.yaml":
+			if err := yaml.Unmarshal(f.Data, &chart.Values); err != nil {
+				return nil, fmt.Errorf("failed to parse values.yaml: %v", err)
+			}
+			if chart.Values == nil {
+				chart.Values = map[string]interface{}{}
+			}
+		case strings.HasPrefix(f.Name, "templates/"):
+			chart.Templates = append(chart.Templates, f)
+		case strings.HasPrefix(f.Name, "charts/"):
+			return nil, fmt.Errorf("chart dependencies are not supported: %s", f.Name)
+		default:
+			chart.Files = append(chart.Files, f)
+		}
+	}
+
+	if chart.Metadata == nil {
+		return nil, fmt.Errorf("Chart.yaml not found")
+	}
+	if chart.Metadata.Name == "" {
+		return nil, fmt.Errorf("the name of the chart is required")
+	}
+	if len(chart.Metadata.Dependencies) > 0 {
+		return nil, fmt.Errorf("chart dependencies are not supported")
+	}
+	return chart, nil
+}
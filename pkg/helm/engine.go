@@ -0,0 +1,282 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// ReleaseOptions describes the release which a chart is rendered for.
+type ReleaseOptions struct {
+	// Name is the name of the release.
+	Name string
+	// Namespace is the namespace of the release. Rendered objects without a
+	// namespace are put into it.
+	Namespace string
+	// KubeVersion is the version of the target cluster, e.g. v1.25.0.
+	KubeVersion string
+	// APIVersions is the group versions and group version kinds served by the target cluster.
+	APIVersions []string
+	// IncludeCRDs indicates that the crds under the crds/ directory of the chart are rendered
+	// as well, like `helm install` does.
+	IncludeCRDs bool
+}
+
+// Render renders the templates of the chart with the given values and returns the
+// resulting objects in install order. The values are merged into the default values of the chart.
+func Render(chart *Chart, values Values, opts ReleaseOptions) ([]*unstructured.Unstructured, error) {
+	rendered, err := renderTemplates(chart, MergeValues(chart.Values, values), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// sort by template name, so that the order of the objects is stable.
+	names := make([]string, 0, len(rendered))
+	for name := range rendered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var objs []*unstructured.Unstructured
+	for _, name := range names {
+		decoded, err := decodeDocuments(name, rendered[name])
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range decoded {
+			if obj.GetNamespace() == "" {
+				obj.SetNamespace(opts.Namespace)
+			}
+			objs = append(objs, obj)
+		}
+	}
+	if opts.IncludeCRDs {
+		// the crds aren't templates, and they're cluster-scoped.
+		for _, f := range chart.Files {
+			if !strings.HasPrefix(f.Name, "crds/") {
+				continue
+			}
+			switch path.Ext(f.Name) {
+			case ".yaml", ".yml", ".json":
+			default:
+				continue
+			}
+			decoded, err := decodeDocuments(path.Join(chart.Metadata.Name, f.Name), string(f.Data))
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, decoded...)
+		}
+	}
+	SortByKind(objs)
+	return objs, nil
+}
+
+// decodeDocuments decodes the objects of a multi-document yaml output by the named file of a chart.
+func decodeDocuments(name, s string) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	for _, doc := range splitDocuments(s) {
+		data, err := yaml.YAMLToJSON([]byte(doc))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the output of %s: %v", name, err)
+		}
+		if string(data) == "null" {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(data); err != nil {
+			return nil, fmt.Errorf("failed to decode the output of %s: %v", name, err)
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// splitDocuments splits a multi-document yaml into documents, skipping empty ones.
+func splitDocuments(s string) []string {
+	var docs []string
+	for _, doc := range strings.Split("\n"+s, "\n---") {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+// renderTemplates executes the templates of the chart and returns the output by template name.
+// Partials, whose names start with an underscore, and NOTES.txt are not rendered.
+func renderTemplates(chart *Chart, values Values, opts ReleaseOptions) (map[string]string, error) {
+	t := template.New("gotpl").Option("missingkey=zero")
+	t.Funcs(funcMap(t))
+
+	for _, f := range chart.Templates {
+		name := path.Join(chart.Metadata.Name, f.Name)
+		if _, err := t.New(name).Parse(string(f.Data)); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", name, err)
+		}
+	}
+
+	top := map[string]interface{}{
+		"Values": values,
+		"Release": map[string]interface{}{
+			"Name":      opts.Name,
+			"Namespace": opts.Namespace,
+			"Service":   "Helm",
+			"IsInstall": true,
+			"IsUpgrade": false,
+			"Revision":  1,
+		},
+		"Chart": map[string]interface{}{
+			"Name":        chart.Metadata.Name,
+			"Version":     chart.Metadata.Version,
+			"AppVersion":  chart.Metadata.AppVersion,
+			"Description": chart.Metadata.Description,
+			"Type":        chart.Metadata.Type,
+		},
+		"Capabilities": map[string]interface{}{
+			"KubeVersion": kubeVersion(opts.KubeVersion),
+			"APIVersions": apiVersions(opts.APIVersions),
+		},
+		"Files": newFiles(chart.Files),
+	}
+
+	rendered := map[string]string{}
+	for _, f := range chart.Templates {
+		name := path.Join(chart.Metadata.Name, f.Name)
+		base := path.Base(f.Name)
+		if strings.HasPrefix(base, "_") || base == "NOTES.txt" {
+			continue
+		}
+
+		data := make(map[string]interface{}, len(top)+1)
+		for k, v := range top {
+			data[k] = v
+		}
+		data["Template"] = map[string]interface{}{
+			"Name":     name,
+			"BasePath": path.Join(chart.Metadata.Name, "templates"),
+		}
+		var buf bytes.Buffer
+		if err := t.ExecuteTemplate(&buf, name, data); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %v", name, err)
+		}
+		// missingkey=zero renders missing keys of maps as "<no value>".
+		rendered[name] = strings.ReplaceAll(buf.String(), "<no value>", "")
+	}
+	return rendered, nil
+}
+
+// funcMap returns the functions available to the templates, which are the
+// sprig functions plus the helm specific ones.
+func funcMap(t *template.Template) template.FuncMap {
+	f := sprig.TxtFuncMap()
+	// templates must not read the environment of the controller.
+	delete(f, "env")
+	delete(f, "expandenv")
+
+	includedNames := map[string]int{}
+	f["include"] = func(name string, data interface{}) (string, error) {
+		// guard against infinite recursion
+		if includedNames[name] > 1000 {
+			return "", fmt.Errorf("rendering template has a nested reference name: %s", name)
+		}
+		includedNames[name]++
+		defer func() { includedNames[name]-- }()
+
+		var buf bytes.Buffer
+		if err := t.ExecuteTemplate(&buf, name, data); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+	f["tpl"] = func(text string, data interface{}) (string, error) {
+		clone, err := t.Clone()
+		if err != nil {
+			return "", err
+		}
+		if _, err := clone.New("tpl").Parse(text); err != nil {
+			return "", err
+		}
+		var buf bytes.Buffer
+		if err := clone.ExecuteTemplate(&buf, "tpl", data); err != nil {
+			return "", err
+		}
+		return strings.ReplaceAll(buf.String(), "<no value>", ""), nil
+	}
+	f["required"] = func(msg string, v interface{}) (interface{}, error) {
+		if v == nil {
+			return nil, errors.New(msg)
+		}
+		if s, ok := v.(string); ok && s == "" {
+			return nil, errors.New(msg)
+		}
+		return v, nil
+	}
+	f["toYaml"] = toYAML
+	f["fromYaml"] = func(s string) map[string]interface{} {
+		m := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(s), &m); err != nil {
+			m["Error"] = err.Error()
+		}
+		return m
+	}
+	f["fromYamlArray"] = func(s string) []interface{} {
+		var a []interface{}
+		if err := yaml.Unmarshal([]byte(s), &a); err != nil {
+			a = []interface{}{err.Error()}
+		}
+		return a
+	}
+	f["toJson"] = func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+	f["fromJson"] = func(s string) map[string]interface{} {
+		m := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			m["Error"] = err.Error()
+		}
+		return m
+	}
+	f["fromJsonArray"] = func(s string) []interface{} {
+		var a []interface{}
+		if err := json.Unmarshal([]byte(s), &a); err != nil {
+			a = []interface{}{err.Error()}
+		}
+		return a
+	}
+	// lookup is not supported, the same as `helm template`.
+	f["lookup"] = func(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+		return map[string]interface{}{}, nil
+	}
+	return f
+}
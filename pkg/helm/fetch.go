@@ -0,0 +1,459 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// BundledChartsDir is the directory of the charts shipped with firefly.
+	// A chart without a repository is loaded from it.
+	BundledChartsDir = "./charts"
+
+	// helmChartContentMediaType is the media type of the chart layer of an oci artifact.
+	helmChartContentMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+	ociManifestMediaType      = "application/vnd.oci.image.manifest.v1+json"
+
+	// maxChartSize is the max size of a chart archive or a repository index.
+	maxChartSize = 32 << 20
+)
+
+var (
+	insecureHostsLock sync.RWMutex
+	insecureHosts     = sets.NewString()
+)
+
+// SetInsecureHosts sets the hosts, e.g. a chart repository in the host cluster, which the charts may be
+// loaded from over plain http. The charts are only loaded over https from the other hosts, so that the
+// authors of the objects referring to charts can't reach arbitrary services in the cluster.
+func SetInsecureHosts(hosts []string) {
+	insecureHostsLock.Lock()
+	defer insecureHostsLock.Unlock()
+	insecureHosts = sets.NewString(hosts...)
+}
+
+// insecureHostAllowed returns whether the charts may be loaded from the host over plain http.
+func insecureHostAllowed(host string) bool {
+	insecureHostsLock.RLock()
+	defer insecureHostsLock.RUnlock()
+	return insecureHosts.Has(host)
+}
+
+// Fetcher loads charts from the bundled directory, chart repositories and oci registries.
+// Charts with a pinned version are cached in memory, and so are the fetched chart archives by their
+// digests, so that a chart of the latest version is only fetched again once a new version is released.
+type Fetcher struct {
+	httpClient *http.Client
+
+	lock    sync.Mutex
+	cache   map[string]*Chart
+	digests map[string]*Chart
+}
+
+// NewFetcher returns a new *Fetcher.
+func NewFetcher() *Fetcher {
+	return &Fetcher{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		cache:      map[string]*Chart{},
+		digests:    map[string]*Chart{},
+	}
+}
+
+// Fetch loads the chart with the given name and version from the repository.
+//
+// The repository can be one of:
+//   - empty, the chart is loaded from BundledChartsDir
+//   - https://<url>, a chart repository which serves index.yaml, or http://<url> if its host is
+//     allowed by SetInsecureHosts
+//   - oci://<registry>/<path>, an oci registry; the chart is at <path>/<name>
+//
+// If version is empty, the latest version is used.
+func (f *Fetcher) Fetch(ctx context.Context, repository, name, version string) (*Chart, error) {
+	key := strings.Join([]string{repository, name, version}, "|")
+	if version != "" {
+		f.lock.Lock()
+		chart, ok := f.cache[key]
+		f.lock.Unlock()
+		if ok {
+			return chart, nil
+		}
+	}
+
+	chart, err := f.fetch(ctx, repository, name, version)
+	if err != nil {
+		return nil, err
+	}
+	if version != "" && chart.Metadata.Version != version {
+		return nil, fmt.Errorf("chart %s: want version %s, got %s", name, version, chart.Metadata.Version)
+	}
+
+	if version != "" {
+		f.lock.Lock()
+		f.cache[key] = chart
+		f.lock.Unlock()
+	}
+	return chart, nil
+}
+
+func (f *Fetcher) fetch(ctx context.Context, repository, name, version string) (*Chart, error) {
+	switch {
+	case repository == "":
+		if name == "" || name == "." || name == ".." || filepath.Base(name) != name {
+			return nil, fmt.Errorf("invalid bundled chart name %q", name)
+		}
+		return loadPath(filepath.Join(BundledChartsDir, name))
+	case strings.HasPrefix(repository, "oci://"):
+		return f.fetchOCI(ctx, strings.TrimPrefix(repository, "oci://"), name, version)
+	case strings.HasPrefix(repository, "http://"), strings.HasPrefix(repository, "https://"):
+		return f.fetchFromRepository(ctx, repository, name, version)
+	}
+	return nil, fmt.Errorf("unsupported chart repository %q", repository)
+}
+
+func loadPath(p string) (*Chart, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return LoadDir(p)
+	}
+	file, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadArchive(file)
+}
+
+// indexFile is the index.yaml of a chart repository.
+type indexFile struct {
+	Entries map[string][]struct {
+		Version string   `json:"version"`
+		URLs    []string `json:"urls"`
+		Digest  string   `json:"digest"`
+	} `json:"entries"`
+}
+
+func (f *Fetcher) fetchFromRepository(ctx context.Context, repository, name, version string) (*Chart, error) {
+	base, err := url.Parse(strings.TrimSuffix(repository, "/") + "/")
+	if err != nil {
+		return nil, err
+	}
+	data, err := f.get(ctx, base.ResolveReference(&url.URL{Path: "index.yaml"}).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	index := &indexFile{}
+	if err := yaml.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("failed to parse the index of %s: %v", repository, err)
+	}
+
+	entries := index.Entries[name]
+	versions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		versions = append(versions, entry.Version)
+	}
+	chosen, err := chooseVersion(versions, version)
+	if err != nil {
+		return nil, fmt.Errorf("chart %s in %s: %v", name, repository, err)
+	}
+
+	for _, entry := range entries {
+		if entry.Version != chosen || len(entry.URLs) == 0 {
+			continue
+		}
+		u, err := url.Parse(entry.URLs[0])
+		if err != nil {
+			return nil, err
+		}
+		return f.fetchArchive(ctx, entry.Digest, func() ([]byte, error) {
+			return f.get(ctx, base.ResolveReference(u).String(), nil)
+		})
+	}
+	return nil, fmt.Errorf("chart %s-%s in %s has no url", name, chosen, repository)
+}
+
+// chooseVersion returns the wanted version if it's available, or the latest
+// stable version if the wanted version is empty.
+func chooseVersion(versions []string, want string) (string, error) {
+	var latest *semver.Version
+	for _, v := range versions {
+		if want != "" {
+			if v == want {
+				return v, nil
+			}
+			continue
+		}
+		sv, err := semver.NewVersion(v)
+		if err != nil || sv.Prerelease() != "" {
+			continue
+		}
+		if latest == nil || sv.GreaterThan(latest) {
+			latest = sv
+		}
+	}
+	if latest == nil {
+		if want != "" {
+			return "", fmt.Errorf("version %s not found", want)
+		}
+		return "", fmt.Errorf("no version found")
+	}
+	return latest.Original(), nil
+}
+
+func (f *Fetcher) fetchOCI(ctx context.Context, reference, name, version string) (*Chart, error) {
+	reference = strings.TrimSuffix(reference, "/")
+	i := strings.Index(reference, "/")
+	if i < 0 {
+		i = len(reference)
+	}
+	r := &ociRepository{
+		fetcher:  f,
+		registry: reference[:i],
+		name:     strings.TrimPrefix(path.Join(reference[i:], name), "/"),
+	}
+
+	if version == "" {
+		tags, err := r.tags(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// helm replaces "+" with "_" in tags because "+" isn't allowed.
+		for i := range tags {
+			tags[i] = strings.ReplaceAll(tags[i], "_", "+")
+		}
+		version, err = chooseVersion(tags, "")
+		if err != nil {
+			return nil, fmt.Errorf("chart %s: %v", r.name, err)
+		}
+	}
+
+	manifest := struct {
+		Layers []struct {
+			MediaType string `json:"mediaType"`
+			Digest    string `json:"digest"`
+		} `json:"layers"`
+	}{}
+	data, err := r.get(ctx, "manifests/"+strings.ReplaceAll(version, "+", "_"), ociManifestMediaType)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest of %s:%s: %v", r.name, version, err)
+	}
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != helmChartContentMediaType {
+			continue
+		}
+		return f.fetchArchive(ctx, layer.Digest, func() ([]byte, error) {
+			return r.get(ctx, "blobs/"+layer.Digest, "")
+		})
+	}
+	return nil, fmt.Errorf("%s:%s is not a helm chart", r.name, version)
+}
+
+// fetchArchive returns the chart archive of the digest from the cache, or fetches and caches it. An archive
+// without a digest isn't cached.
+func (f *Fetcher) fetchArchive(ctx context.Context, digest string, fetch func() ([]byte, error)) (*Chart, error) {
+	if digest != "" {
+		f.lock.Lock()
+		chart, ok := f.digests[digest]
+		f.lock.Unlock()
+		if ok {
+			return chart, nil
+		}
+	}
+	data, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	chart, err := LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if digest != "" {
+		f.lock.Lock()
+		f.digests[digest] = chart
+		f.lock.Unlock()
+	}
+	return chart, nil
+}
+
+// ociRepository talks to a repository of an oci registry with the distribution api.
+// Only anonymous access is supported.
+type ociRepository struct {
+	fetcher  *Fetcher
+	registry string
+	name     string
+	token    string
+}
+
+func (r *ociRepository) tags(ctx context.Context) ([]string, error) {
+	data, err := r.get(ctx, "tags/list", "")
+	if err != nil {
+		return nil, err
+	}
+	list := struct {
+		Tags []string `json:"tags"`
+	}{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	return list.Tags, nil
+}
+
+func (r *ociRepository) get(ctx context.Context, p, accept string) ([]byte, error) {
+	u := fmt.Sprintf("https://%s/v2/%s/%s", r.registry, r.name, p)
+	header := http.Header{}
+	if accept != "" {
+		header.Set("Accept", accept)
+	}
+	if r.token != "" {
+		header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	data, err := r.fetcher.get(ctx, u, header)
+	challenge, ok := err.(*unauthorizedError)
+	if !ok || r.token != "" {
+		return data, err
+	}
+
+	// get an anonymous token according to the challenge and try again.
+	token, err := r.fetcher.anonymousToken(ctx, challenge.authenticate, r.name)
+	if err != nil {
+		return nil, err
+	}
+	r.token = token
+	header.Set("Authorization", "Bearer "+r.token)
+	return r.fetcher.get(ctx, u, header)
+}
+
+// anonymousToken requests a bearer token according to a WWW-Authenticate challenge like
+// `Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:foo/bar:pull"`.
+func (f *Fetcher) anonymousToken(ctx context.Context, authenticate, name string) (string, error) {
+	if !strings.HasPrefix(authenticate, "Bearer ") {
+		return "", fmt.Errorf("unsupported authentication challenge %q", authenticate)
+	}
+	params := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(authenticate, "Bearer "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("no realm found in authentication challenge %q", authenticate)
+	}
+	if params["scope"] == "" {
+		params["scope"] = fmt.Sprintf("repository:%s:pull", name)
+	}
+
+	u, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("scope", params["scope"])
+	if params["service"] != "" {
+		q.Set("service", params["service"])
+	}
+	u.RawQuery = q.Encode()
+
+	data, err := f.get(ctx, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return "", err
+	}
+	if resp.Token != "" {
+		return resp.Token, nil
+	}
+	return resp.AccessToken, nil
+}
+
+// unauthorizedError is returned when a request is rejected with 401.
+type unauthorizedError struct {
+	url          string
+	authenticate string
+}
+
+func (e *unauthorizedError) Error() string {
+	return fmt.Sprintf("unauthorized to get %s", e.url)
+}
+
+func (f *Fetcher) get(ctx context.Context, u string, header http.Header) ([]byte, error) {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case parsed.Scheme == "https":
+	case parsed.Scheme == "http" && insecureHostAllowed(parsed.Host):
+	default:
+		return nil, fmt.Errorf("refusing to get %s, only https urls and http urls of the allowed insecure hosts are supported", u)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, &unauthorizedError{url: u, authenticate: resp.Header.Get("WWW-Authenticate")}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s: %s", u, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxChartSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxChartSize {
+		return nil, fmt.Errorf("failed to get %s: larger than %d bytes", u, maxChartSize)
+	}
+	return data, nil
+}
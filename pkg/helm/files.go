@@ -0,0 +1,146 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"encoding/base64"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"sigs.k8s.io/yaml"
+)
+
+// files is the `.Files` object of templates, which gives access to the
+// non-template files of a chart.
+type files map[string][]byte
+
+func newFiles(list []*File) files {
+	f := files{}
+	for _, file := range list {
+		f[file.Name] = file.Data
+	}
+	return f
+}
+
+// GetBytes returns the content of the file as bytes.
+func (f files) GetBytes(name string) []byte {
+	return f[name]
+}
+
+// Get returns the content of the file as a string.
+func (f files) Get(name string) string {
+	return string(f.GetBytes(name))
+}
+
+// Glob returns the files whose names match the given pattern.
+func (f files) Glob(pattern string) files {
+	matched := files{}
+	for name, data := range f {
+		if ok, _ := path.Match(pattern, name); ok {
+			matched[name] = data
+		}
+	}
+	return matched
+}
+
+// Lines returns the lines of the file.
+func (f files) Lines(name string) []string {
+	s := f.Get(name)
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, "\n")
+}
+
+// AsConfig returns the files as the data of a ConfigMap in yaml.
+func (f files) AsConfig() string {
+	m := map[string]string{}
+	for name, data := range f {
+		m[path.Base(name)] = string(data)
+	}
+	return toYAML(m)
+}
+
+// AsSecrets returns the files as the data of a Secret in yaml.
+func (f files) AsSecrets() string {
+	m := map[string]string{}
+	for name, data := range f {
+		m[path.Base(name)] = base64.StdEncoding.EncodeToString(data)
+	}
+	return toYAML(m)
+}
+
+func toYAML(v interface{}) string {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(data), "\n")
+}
+
+// capabilitiesKubeVersion is the `.Capabilities.KubeVersion` object of templates.
+type capabilitiesKubeVersion struct {
+	Version string
+	Major   string
+	Minor   string
+}
+
+// String implements fmt.Stringer.
+func (v capabilitiesKubeVersion) String() string {
+	return v.Version
+}
+
+// GitVersion is kept for compatibility with old charts.
+func (v capabilitiesKubeVersion) GitVersion() string {
+	return v.Version
+}
+
+// defaultKubeVersion is used when the version of the target cluster is unknown.
+const defaultKubeVersion = "v1.25.0"
+
+func kubeVersion(s string) capabilitiesKubeVersion {
+	if s == "" {
+		s = defaultKubeVersion
+	}
+	v, err := semver.NewVersion(s)
+	if err != nil {
+		v = semver.MustParse(defaultKubeVersion)
+	}
+	return capabilitiesKubeVersion{
+		Version: "v" + v.String(),
+		Major:   strconv.FormatUint(v.Major(), 10),
+		Minor:   strconv.FormatUint(v.Minor(), 10),
+	}
+}
+
+// capabilitiesAPIVersions is the `.Capabilities.APIVersions` object of templates.
+type capabilitiesAPIVersions []string
+
+func apiVersions(list []string) capabilitiesAPIVersions {
+	sorted := append([]string(nil), list...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// Has returns true if the given group version or group version kind is served.
+func (a capabilitiesAPIVersions) Has(apiVersion string) bool {
+	i := sort.SearchStrings(a, apiVersion)
+	return i < len(a) && a[i] == apiVersion
+}
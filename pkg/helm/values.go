@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"encoding/json"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Values is the values used to render a chart.
+type Values map[string]interface{}
+
+// ParseValues parses the given json or yaml document into values.
+// An empty document results in empty values.
+func ParseValues(data []byte) (Values, error) {
+	values := Values{}
+	if len(data) == 0 {
+		return values, nil
+	}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	if values == nil {
+		values = Values{}
+	}
+	return values, nil
+}
+
+// MergeValues merges the given values into a copy of base. Later values take precedence.
+// Maps are merged recursively, other values are replaced, and a null value removes the key,
+// which is the same as helm does.
+func MergeValues(base Values, overrides ...Values) Values {
+	merged := copyMap(base)
+	for _, override := range overrides {
+		mergeMaps(merged, override)
+	}
+	return merged
+}
+
+func mergeMaps(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if v == nil {
+			delete(dst, k)
+			continue
+		}
+		srcMap, srcIsMap := asMap(v)
+		dstMap, dstIsMap := asMap(dst[k])
+		if srcIsMap && dstIsMap {
+			mergeMaps(dstMap, srcMap)
+			dst[k] = dstMap
+			continue
+		}
+		if srcIsMap {
+			dst[k] = copyMap(srcMap)
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case Values:
+		return m, true
+	}
+	return nil, false
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if vm, ok := asMap(v); ok {
+			out[k] = copyMap(vm)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// ToValues converts the given object into values by its json representation.
+func ToValues(obj interface{}) (Values, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	return ParseValues(data)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func addDefaultingFuncs(scheme *runtime.Scheme) error {
+	return RegisterDefaults(scheme)
+}
+
+// SetDefaults_FireflyKarmadaManagerConfiguration sets the defaults of the fields which aren't set
+// by the configuration file. They're the same as the defaults of the flags.
+func SetDefaults_FireflyKarmadaManagerConfiguration(obj *FireflyKarmadaManagerConfiguration) {
+	if obj.Generic.Address == "" {
+		obj.Generic.Address = "0.0.0.0"
+	}
+	if len(obj.Generic.Controllers) == 0 {
+		obj.Generic.Controllers = []string{"*"}
+	}
+	if obj.Generic.MinResyncPeriod == (metav1.Duration{}) {
+		obj.Generic.MinResyncPeriod = metav1.Duration{Duration: 12 * time.Hour}
+	}
+	if obj.Generic.LeaderElection.ResourceName == "" {
+		obj.Generic.LeaderElection.ResourceName = "firefly-karmada-manager"
+	}
+	if obj.Generic.LeaderElection.ResourceNamespace == "" {
+		obj.Generic.LeaderElection.ResourceNamespace = "firefly-system"
+	}
+	if obj.StartupRetry.Timeout == nil {
+		obj.StartupRetry.Timeout = &metav1.Duration{Duration: 5 * time.Minute}
+	}
+	if obj.StartupRetry.InitialBackoff == (metav1.Duration{}) {
+		obj.StartupRetry.InitialBackoff = metav1.Duration{Duration: time.Second}
+	}
+	if len(obj.LeaderElectControllerGroups) == 0 {
+		obj.LeaderElectControllerGroups = []string{"*"}
+	}
+	if obj.EstimatorController.ConcurrentEstimatorSyncs == 0 {
+		obj.EstimatorController.ConcurrentEstimatorSyncs = 1
+	}
+	if obj.NodeController.ConcurrentNodeSyncs == 0 {
+		obj.NodeController.ConcurrentNodeSyncs = 1
+	}
+	if obj.NodeController.ExtendedResources == nil {
+		obj.NodeController.ExtendedResources = []string{"nvidia.com/gpu", "amd.com/gpu", "hugepages-*"}
+	}
+	if obj.NodeController.AcceleratorLabels == nil {
+		obj.NodeController.AcceleratorLabels = []string{"nvidia.com/gpu.product", "cloud.google.com/gke-accelerator", "node.kubernetes.io/instance-type"}
+	}
+	if obj.KubeanController.ConcurrentKubeanSyncs == 0 {
+		obj.KubeanController.ConcurrentKubeanSyncs = 1
+	}
+	if obj.PediaClusterController.ConcurrentPediaClusterSyncs == 0 {
+		obj.PediaClusterController.ConcurrentPediaClusterSyncs = 1
+	}
+}
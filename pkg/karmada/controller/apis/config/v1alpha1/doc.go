@@ -0,0 +1,24 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// +k8s:deepcopy-gen=package
+// +k8s:conversion-gen=github.com/carlory/firefly/pkg/karmada/controller/apis/config
+// +k8s:conversion-gen-external-types=github.com/carlory/firefly/pkg/karmada/controller/apis/config/v1alpha1
+// +k8s:defaulter-gen=TypeMeta
+// +groupName=fireflykarmadamanager.config.firefly.io
+
+// Package v1alpha1 is the v1alpha1 version of the configuration file of the firefly-karmada-manager.
+package v1alpha1
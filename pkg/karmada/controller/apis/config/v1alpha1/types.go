@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+	cmconfigv1alpha1 "k8s.io/controller-manager/config/v1alpha1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FireflyKarmadaManagerConfiguration contains elements describing firefly-karmada manager.
+// It's loaded from the file passed by the --config flag.
+type FireflyKarmadaManagerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Generic holds configuration for a generic controller-manager. Like kube-controller-manager,
+	// its top level fields are spelled by their Go names, e.g. Controllers and LeaderElection.
+	Generic cmconfigv1alpha1.GenericControllerManagerConfiguration `json:"generic"`
+
+	// Tracing holds the OTLP tracing configuration of the controllers. Tracing is disabled if it is nil.
+	// +optional
+	Tracing *tracingapi.TracingConfiguration `json:"tracing,omitempty"`
+
+	// DryRun makes the controllers send their mutations as server-side dry-run requests, so that
+	// the objects they would create, update or delete are only validated and audited.
+	DryRun bool `json:"dryRun"`
+
+	// UseServiceAccountCredentials makes each controller authenticate to the karmada apiserver as its
+	// own service account in the kube-system namespace, instead of sharing the credentials of the manager.
+	UseServiceAccountCredentials bool `json:"useServiceAccountCredentials"`
+
+	// TrafficShaping shapes the requests sent to the karmada apiserver by the controllers, so that their bulk
+	// operations don't starve the interactive users of the karmada apiserver.
+	// +optional
+	TrafficShaping TrafficShapingConfiguration `json:"trafficShaping,omitempty"`
+
+	// StartupRetry describes how the manager retries to start while the apiservers are unreachable.
+	// +optional
+	StartupRetry StartupRetryConfiguration `json:"startupRetry,omitempty"`
+
+	// ControllerGroups splits the controllers into groups, each of which is elected by its own lease
+	// named <LeaderElection.ResourceName>-<name>, so that the groups can be led by different replicas.
+	// The controllers in no group form the default group, which is elected by the lease of
+	// LeaderElection.ResourceName.
+	// +optional
+	ControllerGroups []ControllerGroup `json:"controllerGroups,omitempty"`
+	// LeaderElectControllerGroups is the list of the controller groups run by this replica. '*' means
+	// all the groups, and 'default' is the group of the controllers in no group.
+	LeaderElectControllerGroups []string `json:"leaderElectControllerGroups"`
+	// LeaderElectionLabels are set on the leader election leases, so that the leases of several
+	// firefly deployments sharing the lock namespace can be told apart.
+	// +optional
+	LeaderElectionLabels map[string]string `json:"leaderElectionLabels,omitempty"`
+	// LeaderElectionIdentitySuffix is appended to the holder identities of the leader election
+	// leases to name the deployment the holders belong to.
+	// +optional
+	LeaderElectionIdentitySuffix string `json:"leaderElectionIdentitySuffix,omitempty"`
+
+	// ExternalControllers are the controllers run as child processes of the manager, so that vendor-specific
+	// controllers can be added without being compiled into firefly. They're elected and toggled by their
+	// names like the built-in controllers.
+	// +optional
+	ExternalControllers []ExternalController `json:"externalControllers,omitempty"`
+
+	// EstimatorController holds configuration for EstimatorController related features.
+	EstimatorController EstimatorControllerConfiguration `json:"estimatorController"`
+	// NodeController holds configuration for NodeController related features.
+	NodeController NodeControllerConfiguration `json:"nodeController"`
+	// KubeanController holds configuration for KubeanController related features.
+	KubeanController KubeanControllerConfiguration `json:"kubeanController"`
+	// PediaClusterController holds configuration for PediaClusterController related features.
+	PediaClusterController PediaClusterControllerConfiguration `json:"pediaClusterController"`
+}
+
+// TrafficShapingConfiguration contains elements describing how the requests of the controllers are shaped.
+type TrafficShapingConfiguration struct {
+	// RequestTimeout is the timeout of each request sent by the clients of the controllers, which bounds
+	// the context of the request. The shared informers are not affected. 0 means no timeout.
+	// +optional
+	RequestTimeout metav1.Duration `json:"requestTimeout,omitempty"`
+	// BulkClients are the names of the clients sending bulk requests, e.g. firefly-node-controller. Their
+	// user agents are hinted with "bulk", and their requests are put into the workload-low priority level
+	// of the API Priority and Fairness by a FlowSchema if UseServiceAccountCredentials is set.
+	// +optional
+	BulkClients []string `json:"bulkClients,omitempty"`
+}
+
+// StartupRetryConfiguration contains elements describing how the manager retries to build the context of
+// the controllers while either apiserver is unreachable, e.g. when they're started at the same time. The
+// configuration errors aren't retried.
+type StartupRetryConfiguration struct {
+	// Timeout is how long the manager retries before it exits. 0 means it isn't retried. Defaults to 5m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// InitialBackoff is the interval before the first retry, which is doubled after each retry up to 30s.
+	// Defaults to 1s.
+	// +optional
+	InitialBackoff metav1.Duration `json:"initialBackoff,omitempty"`
+}
+
+// ControllerGroup is a group of controllers which are elected together.
+type ControllerGroup struct {
+	// Name is the name of the group, which is the suffix of the name of its lease.
+	Name string `json:"name"`
+	// Controllers is the list of the controllers in the group.
+	Controllers []string `json:"controllers"`
+}
+
+// ExternalController is a controller run as a child process of the manager while it leads the controller
+// group of the controller. The process is passed the kubeconfigs of the karmada apiserver and the host
+// cluster, and is restarted with a back-off if it exits.
+type ExternalController struct {
+	// Name is the name of the controller.
+	Name string `json:"name"`
+	// Command is the executable and the arguments of the process.
+	Command []string `json:"command"`
+	// Env is the additional environment variables of the process.
+	// +optional
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// EstimatorControllerConfiguration contains elements describing EstimatorController.
+type EstimatorControllerConfiguration struct {
+	// ConcurrentEstimatorSyncs is the number of clusters whose estimators are allowed to sync
+	// concurrently. Larger number = more responsive estimators, but more CPU (and network) load.
+	ConcurrentEstimatorSyncs int32 `json:"concurrentEstimatorSyncs"`
+}
+
+// NodeControllerConfiguration contains elements describing NodeController.
+type NodeControllerConfiguration struct {
+	// ConcurrentNodeSyncs is the number of nodes that are allowed to sync
+	// concurrently. Larger number = more responsive nodes, but more CPU (and network) load.
+	ConcurrentNodeSyncs int32 `json:"concurrentNodeSyncs"`
+	// ExtendedResources is the allowlist of the extended resources, e.g. nvidia.com/gpu and hugepages-1Gi,
+	// which are summarized for the member clusters along with cpu, memory and pods. An entry ending
+	// with '*' matches the resources prefixed with the rest of it.
+	ExtendedResources []string `json:"extendedResources,omitempty"`
+	// AcceleratorLabels is the keys of the node labels, e.g. the accelerator type, whose values are
+	// summarized for the member clusters.
+	AcceleratorLabels []string `json:"acceleratorLabels,omitempty"`
+}
+
+// KubeanControllerConfiguration contains elements describing KubeanController.
+type KubeanControllerConfiguration struct {
+	// ConcurrentKubeanSyncs is the number of kubean objects of each kind that are allowed to sync
+	// concurrently. Larger number = more responsive kubean clusters and manifests, but more CPU (and network) load.
+	ConcurrentKubeanSyncs int32 `json:"concurrentKubeanSyncs"`
+}
+
+// PediaClusterControllerConfiguration contains elements describing PediaClusterController.
+type PediaClusterControllerConfiguration struct {
+	// ConcurrentPediaClusterSyncs is the number of clusters whose pediaclusters are allowed to sync
+	// concurrently. Larger number = more responsive pediaclusters, but more CPU (and network) load.
+	ConcurrentPediaClusterSyncs int32 `json:"concurrentPediaClusterSyncs"`
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimator
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util"
+	"github.com/carlory/firefly/pkg/util/certs"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+)
+
+const (
+	// estimatorCertRenewBefore is how long before the expiration the certificates of the estimators
+	// and of their clients are renewed.
+	estimatorCertRenewBefore = 30 * 24 * time.Hour
+
+	// estimatorClientCommonName is the common name of the client certificate of the karmada-scheduler
+	// and the karmada-descheduler.
+	estimatorClientCommonName = "karmada-scheduler-estimator-client"
+)
+
+// EnsureEstimatorCerts issues the serving certificate of the estimator of the cluster and the client
+// certificate of the karmada-scheduler and the karmada-descheduler with the karmada CA if mTLS is enabled,
+// otherwise deletes them. It returns the serial number of the serving certificate, which is empty if the
+// estimator doesn't use a certificate issued by firefly.
+func (ctrl *EstimatorController) EnsureEstimatorCerts(ctx context.Context, karmada *installv1alpha1.Karmada, cluster *clusterv1alpha1.Cluster) (string, error) {
+	estimator := &karmada.Spec.Scheduler.KarmadaSchedulerEstimator
+	servingSecretName := GenerateEstimatorCertSecretName(karmada.Name, defaultEstimatorServicePrefix, cluster.Name)
+	if !estimator.MutualTLS {
+		err := ctrl.fireflyKubeClient.CoreV1().Secrets(karmada.Namespace).Delete(ctx, servingSecretName, metav1.DeleteOptions{})
+		if client.IgnoreNotFound(err) != nil {
+			return "", err
+		}
+		err = ctrl.fireflyKubeClient.CoreV1().Secrets(karmada.Namespace).Delete(ctx, constants.EstimatorClientCertSecret, metav1.DeleteOptions{})
+		return "", client.IgnoreNotFound(err)
+	}
+
+	certSecret, err := ctrl.fireflyKubeClient.CoreV1().Secrets(karmada.Namespace).Get(ctx, "karmada-cert", metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	caCert, caKey, err := certs.ParseCA(certSecret.Data["ca.crt"], certSecret.Data["ca.key"])
+	if err != nil {
+		return "", fmt.Errorf("invalid karmada CA: %v", err)
+	}
+	caData := certSecret.Data["ca.crt"]
+
+	if _, err := ctrl.ensureCertSecret(ctx, karmada, constants.EstimatorClientCertSecret, "", caData, caCert, caKey, estimatorClientCommonName, nil); err != nil {
+		return "", err
+	}
+
+	if settingsFor(estimator, cluster.Name).grpcTLS != nil {
+		err := ctrl.fireflyKubeClient.CoreV1().Secrets(karmada.Namespace).Delete(ctx, servingSecretName, metav1.DeleteOptions{})
+		return "", client.IgnoreNotFound(err)
+	}
+	estimatorName := GenerateEstimatorName(karmada.Name, defaultEstimatorServicePrefix, cluster.Name)
+	servingCert, err := ctrl.ensureCertSecret(ctx, karmada, servingSecretName, cluster.Name, caData, caCert, caKey, estimatorName, estimatorHosts(karmada, estimatorName))
+	if err != nil {
+		return "", err
+	}
+	return servingCert.SerialNumber.Text(16), nil
+}
+
+// estimatorHosts returns the hosts the service of the estimator is reachable at.
+func estimatorHosts(karmada *installv1alpha1.Karmada, estimatorName string) []string {
+	return []string{
+		estimatorName,
+		fmt.Sprintf("%s.%s", estimatorName, karmada.Namespace),
+		fmt.Sprintf("%s.%s.svc", estimatorName, karmada.Namespace),
+		fmt.Sprintf("%s.%s.svc.%s", estimatorName, karmada.Namespace, karmada.Spec.Networking.DNSDomain),
+	}
+}
+
+// ensureCertSecret issues a certificate for the common name and the hosts with the karmada CA, and stores it
+// in the Secret along with the CA. The certificate is reused until it's going to expire, the CA is rotated
+// or it doesn't cover the hosts. The Secret is labeled as an estimator resource if clusterName is not empty.
+func (ctrl *EstimatorController) ensureCertSecret(ctx context.Context, karmada *installv1alpha1.Karmada, secretName, clusterName string,
+	caData []byte, caCert *x509.Certificate, caKey crypto.Signer, commonName string, hosts []string) (*x509.Certificate, error) {
+	if cert := ctrl.reusableCert(ctx, karmada.Namespace, secretName, caData, caCert, hosts); cert != nil {
+		return cert, nil
+	}
+
+	notAfter := time.Now().Add(certs.Duration365d).UTC()
+	certCfg := certs.NewCertConfig(commonName, []string{}, certutil.AltNames{DNSNames: hosts}, &notAfter)
+	cert, key, err := certs.NewCertAndKey(caCert, caKey, certCfg)
+	if err != nil {
+		return nil, err
+	}
+	keyData, err := keyutil.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: karmada.Namespace,
+		},
+		Data: map[string][]byte{
+			"tls.crt": certs.EncodeCertPEM(cert),
+			"tls.key": keyData,
+			"ca.crt":  caData,
+		},
+	}
+	util.SetKarmadaInstanceLabel(secret, karmada.Name)
+	if clusterName != "" {
+		setEstimatorLabels(secret, clusterName)
+	}
+	controllerutil.SetOwnerReference(karmada, secret, scheme.Scheme)
+	if _, err := clientutil.CreateOrUpdateSecret(ctx, ctrl.fireflyKubeClient, secret); err != nil {
+		return nil, err
+	}
+	ctrl.eventRecorder.Eventf(karmada, corev1.EventTypeNormal, "CertificateRotated", "Issued a new certificate for %s", commonName)
+	return cert, nil
+}
+
+// reusableCert returns the certificate held by the Secret if it's still valid for the hosts, and is
+// issued by the current CA. Otherwise nil is returned.
+func (ctrl *EstimatorController) reusableCert(ctx context.Context, namespace, secretName string, caData []byte, caCert *x509.Certificate, hosts []string) *x509.Certificate {
+	secret, err := ctrl.fireflyKubeClient.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	if !bytes.Equal(secret.Data["ca.crt"], caData) {
+		return nil
+	}
+	parsed, err := certutil.ParseCertsPEM(secret.Data["tls.crt"])
+	if err != nil {
+		return nil
+	}
+	cert := parsed[0]
+	if time.Now().Add(estimatorCertRenewBefore).After(cert.NotAfter) {
+		return nil
+	}
+	if err := cert.CheckSignatureFrom(caCert); err != nil {
+		return nil
+	}
+	for _, host := range hosts {
+		if err := cert.VerifyHostname(host); err != nil {
+			return nil
+		}
+	}
+	return cert
+}
@@ -19,8 +19,10 @@ package estimator
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"reflect"
 	"strconv"
+	"sync"
 	"time"
 
 	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
@@ -33,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
@@ -40,13 +43,22 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/component-base/metrics/prometheus/ratelimiter"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	fireflyversioned "github.com/carlory/firefly/pkg/generated/clientset/versioned"
 	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
 	installlisters "github.com/carlory/firefly/pkg/generated/listers/install/v1alpha1"
 	"github.com/carlory/firefly/pkg/karmada/scheme"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/debug"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	"github.com/carlory/firefly/pkg/util/expectations"
+	"github.com/carlory/firefly/pkg/util/resolver"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
 )
 
 const (
@@ -60,6 +72,15 @@ const (
 	EstimatorControllerFinalizerName = "estimator.karmada.install.firefly.io/finalizer"
 )
 
+// +firefly:rbac:cluster=karmada,groups=cluster.karmada.io,resources=clusters,verbs=get;list;watch;update
+// +firefly:rbac:cluster=karmada,groups="",resources=secrets,verbs=get
+// +firefly:rbac:cluster=karmada,groups="",resources=events,verbs=create;update;patch
+// +firefly:rbac:cluster=host,groups=install.firefly.io,resources=karmadas,verbs=get;list;watch
+// +firefly:rbac:cluster=host,groups=install.firefly.io,resources=karmadas/status,verbs=update
+// +firefly:rbac:cluster=host,groups="",resources=pods,verbs=list;deletecollection
+// +firefly:rbac:cluster=host,groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +firefly:rbac:cluster=host,groups="",resources=services;secrets,verbs=get;create;update;delete
+
 // NewEstimatorController returns a new *Controller.
 func NewEstimatorController(
 	karmadaKubeClient clientset.Interface,
@@ -68,7 +89,11 @@ func NewEstimatorController(
 	estimatorNamespace string,
 	karmadaName string,
 	fireflyKubeClient clientset.Interface,
+	fireflyClient fireflyversioned.Interface,
 	fireflyKarmadaInformer installinformers.KarmadaInformer,
+	deploymentInformer informers.GenericInformer,
+	expectations *expectations.UIDTrackingControllerExpectations,
+	objectResolver *resolver.Resolver,
 ) (*EstimatorController, error) {
 	broadcaster := record.NewBroadcaster()
 	recorder := broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "estimator-controller"})
@@ -77,6 +102,7 @@ func NewEstimatorController(
 		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("estimator_controller", karmadaKubeClient.CoreV1().RESTClient().GetRateLimiter())
 	}
 
+	queue := debug.NewQueue(workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "cluster"))
 	ctrl := &EstimatorController{
 		karmadaKubeClient:    karmadaKubeClient,
 		karmadaClient:        karmadaClient,
@@ -85,12 +111,20 @@ func NewEstimatorController(
 		estimatorNamespace:   estimatorNamespace,
 		karmadaName:          karmadaName,
 		fireflyKubeClient:    fireflyKubeClient,
+		fireflyClient:        fireflyClient,
 		fireflyKarmadaLister: fireflyKarmadaInformer.Lister(),
 		fireflyKarmadaSynced: fireflyKarmadaInformer.Informer().HasSynced,
-		queue:                workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "cluster"),
+		deploymentsLister:    deploymentInformer.Lister(),
+		deploymentsSynced:    deploymentInformer.Informer().HasSynced,
+		expectations:         expectations,
+		objectResolver:       objectResolver,
+		queue:                queue,
+		debugRecorder:        debug.NewRecorder(queue),
 		workerLoopPeriod:     time.Second,
 		eventBroadcaster:     broadcaster,
 		eventRecorder:        recorder,
+		httpClient:           &http.Client{},
+		requests:             map[string]map[string]requestCounts{},
 	}
 
 	clusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -103,6 +137,14 @@ func NewEstimatorController(
 		UpdateFunc: ctrl.syncKarmada,
 	})
 
+	// the informer only watches the metadata of the estimator deployments, so that the deleted
+	// or modified estimators are restored, and the creations and deletions are observed.
+	deploymentInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.addDeployment,
+		UpdateFunc: ctrl.updateDeployment,
+		DeleteFunc: ctrl.deleteDeployment,
+	})
+
 	return ctrl, nil
 }
 
@@ -115,8 +157,17 @@ type EstimatorController struct {
 	estimatorNamespace   string
 	karmadaName          string
 	fireflyKubeClient    clientset.Interface
+	fireflyClient        fireflyversioned.Interface
 	fireflyKarmadaLister installlisters.KarmadaLister
 	fireflyKarmadaSynced cache.InformerSynced
+	deploymentsLister    cache.GenericLister
+	deploymentsSynced    cache.InformerSynced
+
+	// expectations holds the creations and deletions of the estimator deployments which are not
+	// observed yet, keyed by the cluster name, so that an estimator isn't created or deleted twice.
+	expectations *expectations.UIDTrackingControllerExpectations
+	// objectResolver resolves the Secrets of the clusters, which are read on each sync of the clusters.
+	objectResolver *resolver.Resolver
 
 	clustersLister clusterlisters.ClusterLister
 	clustersSynced cache.InformerSynced
@@ -127,9 +178,29 @@ type EstimatorController struct {
 	// service that's inserted multiple times to be processed more than
 	// necessary.
 	queue workqueue.RateLimitingInterface
+	// debugRecorder records the reconcile results and desired states for the debugging handler.
+	debugRecorder *debug.Recorder
 
 	// workerLoopPeriod is the time between worker runs. The workers process the queue of service and pod changes.
 	workerLoopPeriod time.Duration
+
+	// httpClient scrapes the health endpoints and the metrics of the estimators.
+	httpClient *http.Client
+	// requests holds the estimating request counts of the estimator pods scraped by the last health
+	// check, keyed by the estimator name and the pod uid.
+	requestsLock sync.Mutex
+	requests     map[string]map[string]requestCounts
+}
+
+// Name returns the name of the controller.
+func (ctrl *EstimatorController) Name() string {
+	return "estimator"
+}
+
+// DebuggingHandler returns a handler which exposes the queue, the last reconcile results
+// and the changes of the desired states of the controller.
+func (ctrl *EstimatorController) DebuggingHandler() http.Handler {
+	return ctrl.debugRecorder
 }
 
 // Run will not return until stopCh is closed. workers determines how many
@@ -147,13 +218,14 @@ func (ctrl *EstimatorController) Run(ctx context.Context, workers int) {
 	klog.Infof("Starting estimator controller")
 	defer klog.Infof("Shutting down estimator controller")
 
-	if !cache.WaitForNamedCacheSync("estimator", ctx.Done(), ctrl.clustersSynced, ctrl.fireflyKarmadaSynced) {
+	if !cache.WaitForNamedCacheSync("estimator", ctx.Done(), ctrl.clustersSynced, ctrl.fireflyKarmadaSynced, ctrl.deploymentsSynced) {
 		return
 	}
 
 	for i := 0; i < workers; i++ {
 		go wait.UntilWithContext(ctx, ctrl.worker, ctrl.workerLoopPeriod)
 	}
+	go ctrl.runHealthChecks(ctx)
 	<-ctx.Done()
 }
 
@@ -173,7 +245,11 @@ func (ctrl *EstimatorController) processNextWorkItem(ctx context.Context) bool {
 	}
 	defer ctrl.queue.Done(key)
 
+	startTime := time.Now()
+	ctx, span := tracingutil.StartReconcile(ctx, "estimator", key.(string))
 	err := ctrl.syncEstimator(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.debugRecorder.ObserveReconcile(key.(string), startTime, err)
 	ctrl.handleErr(err, key)
 
 	return true
@@ -210,12 +286,69 @@ func (ctrl *EstimatorController) deleteCluster(obj interface{}) {
 	ctrl.enqueue(cluster)
 }
 
+func (ctrl *EstimatorController) addDeployment(obj interface{}) {
+	deployment := obj.(*metav1.PartialObjectMetadata)
+	klog.V(4).InfoS("Adding estimator deployment", "deployment", klog.KObj(deployment))
+	if clusterName := deployment.Labels[constants.ClusterLabel]; clusterName != "" {
+		ctrl.expectations.CreationObserved(clusterName)
+	}
+	ctrl.enqueueDeploymentCluster(deployment)
+}
+
+func (ctrl *EstimatorController) updateDeployment(old, cur interface{}) {
+	oldDeployment := old.(*metav1.PartialObjectMetadata)
+	curDeployment := cur.(*metav1.PartialObjectMetadata)
+	if oldDeployment.Generation == curDeployment.Generation {
+		return
+	}
+	klog.V(4).InfoS("Updating estimator deployment", "deployment", klog.KObj(curDeployment))
+	ctrl.enqueueDeploymentCluster(curDeployment)
+}
+
+func (ctrl *EstimatorController) deleteDeployment(obj interface{}) {
+	deployment, ok := obj.(*metav1.PartialObjectMetadata)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("couldn't get object from tombstone %#v", obj))
+			return
+		}
+		deployment, ok = tombstone.Obj.(*metav1.PartialObjectMetadata)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("tombstone contained object that is not a PartialObjectMetadata %#v", obj))
+			return
+		}
+	}
+	klog.V(4).InfoS("Deleting estimator deployment", "deployment", klog.KObj(deployment))
+	if clusterName := deployment.Labels[constants.ClusterLabel]; clusterName != "" {
+		key, err := cache.MetaNamespaceKeyFunc(deployment)
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("couldn't get key for object %#v: %v", deployment, err))
+			return
+		}
+		ctrl.expectations.DeletionObserved(clusterName, key)
+	}
+	ctrl.enqueueDeploymentCluster(deployment)
+}
+
+// enqueueDeploymentCluster enqueues the member cluster which the estimator deployment belongs to.
+func (ctrl *EstimatorController) enqueueDeploymentCluster(deployment *metav1.PartialObjectMetadata) {
+	if clusterName := deployment.Labels[constants.ClusterLabel]; clusterName != "" {
+		ctrl.queue.Add(clusterName)
+	}
+}
+
 func (ctrl *EstimatorController) syncKarmada(old, cur interface{}) {
 	oldKarmada := old.(*installv1alpha1.Karmada)
 	curKarmada := cur.(*installv1alpha1.Karmada)
 
 	klog.V(4).InfoS("Sync karmada", "karmada", klog.KObj(oldKarmada))
 
+	// the Services of the estimators which have fallen back or been restored by the health checks are synced.
+	for _, clusterName := range fallbackChangedClusters(oldKarmada, curKarmada) {
+		ctrl.queue.Add(clusterName)
+	}
+
 	var needUpdate bool
 
 	oldEstimator := oldKarmada.Spec.Scheduler.KarmadaSchedulerEstimator
@@ -279,14 +412,25 @@ func (ctrl *EstimatorController) syncEstimator(ctx context.Context, key string)
 	cluster, err := ctrl.clustersLister.Get(key)
 	if errors.IsNotFound(err) {
 		klog.V(2).InfoS("Cluster has been deleted", "cluster", klog.KRef("", key))
+		ctrl.debugRecorder.Forget(key)
+		ctrl.expectations.DeleteExpectations(key)
 		return nil
 	}
 	if err != nil {
 		return err
 	}
 
+	// the estimator is neither created nor deleted again until its previous creation or deletion is
+	// observed, the cluster is synced again by the event of the estimator deployment.
+	if !ctrl.expectations.SatisfiedExpectations(key) {
+		klog.V(4).InfoS("Waiting for the changes of the estimator deployment to be observed", "cluster", klog.KRef("", key))
+		return nil
+	}
+
 	// Deep-copy otherwise we are mutating our cache.
 	cluster = cluster.DeepCopy()
+	ctx = audit.WithTrigger(ctx, cluster)
+	ctx = dryrun.ForObject(ctx, cluster)
 	// examine DeletionTimestamp to determine if object is under deletion
 	if cluster.DeletionTimestamp.IsZero() {
 		// The object is not being deleted, so if it does not have our finalizer,
@@ -346,46 +490,76 @@ func (ctrl *EstimatorController) syncEstimator(ctx context.Context, key string)
 		return nil
 	}
 
-	if cluster.Spec.SyncMode == clusterv1alpha1.Pull {
-		schedulerArgs := karmada.Spec.Scheduler.KarmadaScheduler.ExtraArgs
-		disableEstimatorVal, ok := schedulerArgs["disable-scheduler-estimator-in-pull-mode"]
-		if ok {
-			disableEstimator, err := strconv.ParseBool(disableEstimatorVal)
-			if err != nil {
-				return err
-			}
-			if disableEstimator {
-				return nil
-			}
-		}
+	if karmada.Spec.Paused {
+		klog.V(2).InfoS("Karmada is paused, skip syncing estimator", "karmada", klog.KRef(ctrl.estimatorNamespace, ctrl.karmadaName), "cluster", cluster.Name)
+		return nil
+	}
+
+	if !pointer.BoolDeref(karmada.Spec.Scheduler.KarmadaSchedulerEstimator.Enable, true) {
+		klog.V(2).InfoS("Estimator is disabled, removing it", "cluster", cluster.Name)
+		return ctrl.RemoveEstimator(ctx, karmada, cluster)
+	}
+
+	disabled, err := estimatorDisabledInPullMode(karmada, cluster)
+	if err != nil {
+		return err
+	}
+	if disabled {
+		return nil
 	}
 
 	klog.InfoS("Syncing estimator", "cluster", cluster.Name)
 	return ctrl.EnsureEstimator(ctx, karmada, cluster)
 }
 
+// estimatorDisabledInPullMode returns whether the cluster is in pull mode and the karmada-scheduler
+// doesn't consult the estimators of the clusters in pull mode, so that no estimator is deployed for it.
+func estimatorDisabledInPullMode(karmada *installv1alpha1.Karmada, cluster *clusterv1alpha1.Cluster) (bool, error) {
+	if cluster.Spec.SyncMode != clusterv1alpha1.Pull {
+		return false, nil
+	}
+	schedulerArgs := karmada.Spec.Scheduler.KarmadaScheduler.ExtraArgs
+	disableEstimatorVal, ok := schedulerArgs["disable-scheduler-estimator-in-pull-mode"]
+	if !ok {
+		return false, nil
+	}
+	return strconv.ParseBool(disableEstimatorVal)
+}
+
 func (ctrl *EstimatorController) EnsureEstimator(ctx context.Context, karmada *installv1alpha1.Karmada, cluster *clusterv1alpha1.Cluster) error {
 	if err := ctrl.EnsureEstimatorKubeconfigSecret(ctx, karmada, cluster); err != nil {
 		return err
 	}
 
-	if err := ctrl.EnsureEstimatorService(ctx, karmada, cluster); err != nil {
+	// the Service of an estimator which has fallen back is restored once the estimator is healthy again.
+	if estimatorFallenBack(karmada, cluster.Name) {
+		if err := ctrl.RemoveEstimatorService(ctx, karmada, cluster); err != nil {
+			return err
+		}
+	} else if err := ctrl.EnsureEstimatorService(ctx, karmada, cluster); err != nil {
 		return err
 	}
 
-	if err := ctrl.EnsureEstimatorDeployment(ctx, karmada, cluster); err != nil {
+	certSerial, err := ctrl.EnsureEstimatorCerts(ctx, karmada, cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := ctrl.EnsureEstimatorDeployment(ctx, karmada, cluster, certSerial); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (ctrl *EstimatorController) RemoveEstimator(ctx context.Context, karmada *installv1alpha1.Karmada, cluster *clusterv1alpha1.Cluster) error {
-	estimatorName := GenerateEstimatorName(karmada.Name, defaultEstimatorServicePrefix, cluster.Name)
-	err := ctrl.fireflyKubeClient.CoreV1().Services(karmada.Namespace).Delete(ctx, estimatorName, metav1.DeleteOptions{})
-	if err != nil && !errors.IsNotFound(err) {
+	if err := ctrl.RemoveEstimatorService(ctx, karmada, cluster); err != nil {
 		return err
 	}
-	err = ctrl.fireflyKubeClient.AppsV1().Deployments(karmada.Namespace).Delete(ctx, estimatorName, metav1.DeleteOptions{})
+	if err := ctrl.deleteEstimatorDeployment(ctx, karmada, cluster); err != nil {
+		return err
+	}
+	certSecretName := GenerateEstimatorCertSecretName(karmada.Name, defaultEstimatorServicePrefix, cluster.Name)
+	err := ctrl.fireflyKubeClient.CoreV1().Secrets(karmada.Namespace).Delete(ctx, certSecretName, metav1.DeleteOptions{})
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
@@ -393,3 +567,30 @@ func (ctrl *EstimatorController) RemoveEstimator(ctx context.Context, karmada *i
 	err = ctrl.fireflyKubeClient.CoreV1().Secrets(karmada.Namespace).Delete(ctx, secretName, metav1.DeleteOptions{})
 	return client.IgnoreNotFound(err)
 }
+
+// deleteEstimatorDeployment deletes the deployment of the estimator of the cluster, and expects its deletion
+// if the deployment is in the cache of the informer.
+func (ctrl *EstimatorController) deleteEstimatorDeployment(ctx context.Context, karmada *installv1alpha1.Karmada, cluster *clusterv1alpha1.Cluster) error {
+	estimatorName := GenerateEstimatorName(karmada.Name, defaultEstimatorServicePrefix, cluster.Name)
+	key := karmada.Namespace + "/" + estimatorName
+	_, err := ctrl.deploymentsLister.ByNamespace(karmada.Namespace).Get(estimatorName)
+	expected := err == nil && !dryrun.Enabled(ctx)
+	if expected {
+		if err := ctrl.expectations.ExpectDeletions(cluster.Name, []string{key}); err != nil {
+			return err
+		}
+	}
+	err = ctrl.fireflyKubeClient.AppsV1().Deployments(karmada.Namespace).Delete(ctx, estimatorName, metav1.DeleteOptions{})
+	if err != nil && expected {
+		// the deletion won't be observed.
+		ctrl.expectations.DeletionObserved(cluster.Name, key)
+	}
+	return client.IgnoreNotFound(err)
+}
+
+// RemoveEstimatorService deletes the Service of the estimator of the cluster, if any.
+func (ctrl *EstimatorController) RemoveEstimatorService(ctx context.Context, karmada *installv1alpha1.Karmada, cluster *clusterv1alpha1.Cluster) error {
+	estimatorName := GenerateEstimatorName(karmada.Name, defaultEstimatorServicePrefix, cluster.Name)
+	err := ctrl.fireflyKubeClient.CoreV1().Services(karmada.Namespace).Delete(ctx, estimatorName, metav1.DeleteOptions{})
+	return client.IgnoreNotFound(err)
+}
@@ -0,0 +1,417 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+)
+
+const (
+	// defaultHealthCheckPeriod is the period of the health checks if it's not specified, and the
+	// period after which the karmada is looked at again while the health checks are disabled.
+	defaultHealthCheckPeriod = 30 * time.Second
+
+	// defaultHealthCheckFailureThreshold is the failure threshold of the health checks if it's not specified.
+	defaultHealthCheckFailureThreshold = 3
+
+	// healthCheckTimeout is the timeout of a request to the health endpoint or the metrics of an estimator pod.
+	healthCheckTimeout = 5 * time.Second
+
+	// estimatingRequestsMetric is the counter of the estimating requests served by an estimator,
+	// whose result label is either success or error.
+	estimatingRequestsMetric = "karmada_scheduler_estimator_estimating_request_total"
+)
+
+// requestCounts are the numbers of the estimating requests served by an estimator pod.
+type requestCounts struct {
+	succeeded float64
+	failed    float64
+}
+
+// estimatorProbe is the result of a health check of an estimator.
+type estimatorProbe struct {
+	healthy bool
+	reason  string
+	message string
+}
+
+// runHealthChecks checks the health of the estimators periodically until the context is done.
+func (ctrl *EstimatorController) runHealthChecks(ctx context.Context) {
+	for {
+		period := ctrl.checkEstimators(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(period):
+		}
+	}
+}
+
+// checkEstimators checks the health of the estimators of the karmada, takes the failover of the
+// persistently unhealthy ones and reports their health in the status of the karmada. It returns
+// the period after which the estimators are checked again.
+func (ctrl *EstimatorController) checkEstimators(ctx context.Context) time.Duration {
+	karmada, err := ctrl.fireflyKarmadaLister.Karmadas(ctrl.estimatorNamespace).Get(ctrl.karmadaName)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to get karmada", "karmada", klog.KRef(ctrl.estimatorNamespace, ctrl.karmadaName))
+		}
+		return defaultHealthCheckPeriod
+	}
+	if karmada.DeletionTimestamp != nil || karmada.Spec.Paused {
+		return defaultHealthCheckPeriod
+	}
+
+	estimator := karmada.Spec.Scheduler.KarmadaSchedulerEstimator
+	healthCheck := estimator.HealthCheck
+	if healthCheck == nil || !pointer.BoolDeref(estimator.Enable, true) {
+		// the Services of the estimators which have fallen back are restored once the status is cleared.
+		if err := ctrl.updateEstimatorStatuses(ctx, karmada, nil); err != nil {
+			klog.ErrorS(err, "Failed to clear the estimator status of karmada", "karmada", klog.KObj(karmada))
+		}
+		return defaultHealthCheckPeriod
+	}
+
+	period := time.Duration(healthCheck.PeriodSeconds) * time.Second
+	if period <= 0 {
+		period = defaultHealthCheckPeriod
+	}
+	threshold := healthCheck.FailureThreshold
+	if threshold <= 0 {
+		threshold = defaultHealthCheckFailureThreshold
+	}
+
+	clusters, err := ctrl.clustersLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list clusters")
+		return period
+	}
+	now := metav1.Now()
+	checked := sets.NewString()
+	var statuses []installv1alpha1.EstimatorStatus
+	for _, cluster := range clusters {
+		if !cluster.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if disabled, err := estimatorDisabledInPullMode(karmada, cluster); err != nil || disabled {
+			continue
+		}
+
+		checked.Insert(GenerateEstimatorName(karmada.Name, defaultEstimatorServicePrefix, cluster.Name))
+		previous := findEstimatorStatus(karmada.Status.Estimators, cluster.Name)
+		probe, err := ctrl.probeEstimator(ctx, karmada, cluster)
+		if err != nil {
+			// the estimator isn't to blame, its status is kept until it's checked successfully.
+			klog.ErrorS(err, "Failed to check the health of estimator", "cluster", cluster.Name)
+			if previous != nil {
+				statuses = append(statuses, *previous)
+			}
+			continue
+		}
+
+		status := nextEstimatorStatus(previous, cluster.Name, probe, now)
+		if !status.Healthy {
+			if err := ctrl.failover(ctx, karmada, cluster, healthCheck.Failover, threshold, &status); err != nil {
+				klog.ErrorS(err, "Failed to fail over estimator", "cluster", cluster.Name)
+			}
+		} else if previous != nil && previous.FallenBack {
+			ctrl.eventRecorder.Event(cluster, corev1.EventTypeNormal, "EstimatorRestored", "The estimator is healthy again, its Service is restored")
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ClusterName < statuses[j].ClusterName
+	})
+	ctrl.pruneLastRequests(checked)
+
+	if err := ctrl.updateEstimatorStatuses(ctx, karmada, statuses); err != nil {
+		klog.ErrorS(err, "Failed to update the estimator status of karmada", "karmada", klog.KObj(karmada))
+	}
+	return period
+}
+
+// nextEstimatorStatus returns the status of the estimator of the cluster after the probe.
+func nextEstimatorStatus(previous *installv1alpha1.EstimatorStatus, clusterName string, probe estimatorProbe, now metav1.Time) installv1alpha1.EstimatorStatus {
+	status := installv1alpha1.EstimatorStatus{
+		ClusterName: clusterName,
+		Healthy:     probe.healthy,
+		Reason:      probe.reason,
+		Message:     probe.message,
+	}
+	if previous != nil {
+		status.LastTransitionTime = previous.LastTransitionTime
+		if !probe.healthy {
+			status.ConsecutiveFailures = previous.ConsecutiveFailures + 1
+			status.FallenBack = previous.FallenBack
+		}
+	} else if !probe.healthy {
+		status.ConsecutiveFailures = 1
+	}
+	if previous == nil || previous.Healthy != probe.healthy {
+		status.LastTransitionTime = &now
+	}
+	return status
+}
+
+// failover takes the failover of the estimator of the cluster if it has failed the health checks
+// for threshold times in a row. The pods of the estimator are recreated every threshold failures,
+// and the estimator falls back if it's still unhealthy after its pods are recreated. The Service of
+// the estimator is removed by the sync of the cluster triggered by the status update.
+func (ctrl *EstimatorController) failover(ctx context.Context, karmada *installv1alpha1.Karmada, cluster *clusterv1alpha1.Cluster,
+	policy installv1alpha1.EstimatorFailover, threshold int32, status *installv1alpha1.EstimatorStatus) error {
+	if policy == installv1alpha1.EstimatorFailoverNone || status.ConsecutiveFailures < threshold {
+		return nil
+	}
+
+	if policy == installv1alpha1.EstimatorFailoverFallback && !status.FallenBack && status.ConsecutiveFailures >= 2*threshold {
+		status.FallenBack = true
+		ctrl.eventRecorder.Eventf(cluster, corev1.EventTypeWarning, "EstimatorFallenBack",
+			"The estimator failed %d health checks in a row, the replicas are estimated by the general estimation until it's healthy", status.ConsecutiveFailures)
+	}
+
+	if status.ConsecutiveFailures%threshold != 0 {
+		return nil
+	}
+	estimatorName := GenerateEstimatorName(karmada.Name, defaultEstimatorServicePrefix, cluster.Name)
+	err := ctrl.fireflyKubeClient.CoreV1().Pods(karmada.Namespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{"app": estimatorName}).String(),
+	})
+	if err != nil {
+		return err
+	}
+	ctrl.eventRecorder.Eventf(cluster, corev1.EventTypeWarning, "EstimatorPodsRecreated",
+		"The estimator failed %d health checks in a row, its pods are recreated", status.ConsecutiveFailures)
+	return nil
+}
+
+// probeEstimator checks the health of the estimator of the cluster. An error is returned if the
+// estimator can't be checked, e.g. its pods can't be listed.
+func (ctrl *EstimatorController) probeEstimator(ctx context.Context, karmada *installv1alpha1.Karmada, cluster *clusterv1alpha1.Cluster) (estimatorProbe, error) {
+	estimatorName := GenerateEstimatorName(karmada.Name, defaultEstimatorServicePrefix, cluster.Name)
+	pods, err := ctrl.fireflyKubeClient.CoreV1().Pods(karmada.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{"app": estimatorName}).String(),
+	})
+	if err != nil {
+		return estimatorProbe{}, err
+	}
+
+	var readyPods []*corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp == nil && pod.Status.PodIP != "" && podReady(pod) {
+			readyPods = append(readyPods, pod)
+		}
+	}
+	if len(readyPods) == 0 {
+		return estimatorProbe{
+			reason:  "NoReadyPods",
+			message: fmt.Sprintf("None of the %d pods of the estimator is ready", len(pods.Items)),
+		}, nil
+	}
+
+	var delta requestCounts
+	current := map[string]requestCounts{}
+	for _, pod := range readyPods {
+		if err := ctrl.getEstimatorEndpoint(ctx, pod, "/healthz", nil); err != nil {
+			return estimatorProbe{
+				reason:  "HealthCheckFailed",
+				message: fmt.Sprintf("The health check of pod %s failed: %v", pod.Name, err),
+			}, nil
+		}
+
+		var counts requestCounts
+		err := ctrl.getEstimatorEndpoint(ctx, pod, "/metrics", func(resp *http.Response) error {
+			var parser expfmt.TextParser
+			families, err := parser.TextToMetricFamilies(resp.Body)
+			if err != nil {
+				return err
+			}
+			counts = estimatingRequests(families[estimatingRequestsMetric].GetMetric())
+			return nil
+		})
+		if err != nil {
+			// the metrics only refine the health check, the estimators serving none are judged by their health endpoints.
+			klog.V(4).InfoS("Failed to scrape the metrics of estimator", "pod", klog.KObj(pod), "err", err)
+			continue
+		}
+		current[string(pod.UID)] = counts
+
+		last, ok := ctrl.lastRequests(estimatorName)[string(pod.UID)]
+		if !ok {
+			continue
+		}
+		// the counters are reset if the estimator is restarted.
+		if counts.succeeded < last.succeeded || counts.failed < last.failed {
+			last = requestCounts{}
+		}
+		delta.succeeded += counts.succeeded - last.succeeded
+		delta.failed += counts.failed - last.failed
+	}
+	ctrl.setLastRequests(estimatorName, current)
+
+	if delta.failed > 0 && delta.succeeded == 0 {
+		return estimatorProbe{
+			reason:  "EstimatingFailed",
+			message: fmt.Sprintf("All of the %v estimating requests since the last health check failed", delta.failed),
+		}, nil
+	}
+	return estimatorProbe{healthy: true, reason: "Healthy"}, nil
+}
+
+// getEstimatorEndpoint gets the endpoint of the health port of the estimator pod. The response is
+// handled by the given function if its status is OK.
+func (ctrl *EstimatorController) getEstimatorEndpoint(ctx context.Context, pod *corev1.Pod, path string, handle func(*http.Response) error) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf("http://%s%s", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(estimatorHealthPort)), path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := ctrl.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", path, resp.Status)
+	}
+	if handle == nil {
+		return nil
+	}
+	return handle(resp)
+}
+
+// estimatingRequests sums up the estimating requests of the metrics by their result.
+func estimatingRequests(metrics []*dto.Metric) requestCounts {
+	var counts requestCounts
+	for _, metric := range metrics {
+		for _, label := range metric.GetLabel() {
+			if label.GetName() != "result" {
+				continue
+			}
+			switch label.GetValue() {
+			case "success":
+				counts.succeeded += metric.GetCounter().GetValue()
+			case "error":
+				counts.failed += metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return counts
+}
+
+// lastRequests returns the request counts of the pods of the estimator scraped by the last health check.
+func (ctrl *EstimatorController) lastRequests(estimatorName string) map[string]requestCounts {
+	ctrl.requestsLock.Lock()
+	defer ctrl.requestsLock.Unlock()
+	return ctrl.requests[estimatorName]
+}
+
+// setLastRequests records the request counts of the pods of the estimator, the ones of the pods
+// which are gone are dropped.
+func (ctrl *EstimatorController) setLastRequests(estimatorName string, counts map[string]requestCounts) {
+	ctrl.requestsLock.Lock()
+	defer ctrl.requestsLock.Unlock()
+	ctrl.requests[estimatorName] = counts
+}
+
+// pruneLastRequests drops the request counts of the estimators which are no longer checked.
+func (ctrl *EstimatorController) pruneLastRequests(checked sets.String) {
+	ctrl.requestsLock.Lock()
+	defer ctrl.requestsLock.Unlock()
+	for estimatorName := range ctrl.requests {
+		if !checked.Has(estimatorName) {
+			delete(ctrl.requests, estimatorName)
+		}
+	}
+}
+
+// updateEstimatorStatuses updates status.estimators of the karmada if it's changed.
+func (ctrl *EstimatorController) updateEstimatorStatuses(ctx context.Context, karmada *installv1alpha1.Karmada, statuses []installv1alpha1.EstimatorStatus) error {
+	if apiequality.Semantic.DeepEqual(karmada.Status.Estimators, statuses) {
+		return nil
+	}
+	karmada = karmada.DeepCopy()
+	karmada.Status.Estimators = statuses
+	_, err := ctrl.fireflyClient.InstallV1alpha1().Karmadas(karmada.Namespace).UpdateStatus(ctx, karmada, metav1.UpdateOptions{})
+	return err
+}
+
+// findEstimatorStatus returns the status of the estimator of the cluster, or nil if it's not found.
+func findEstimatorStatus(statuses []installv1alpha1.EstimatorStatus, clusterName string) *installv1alpha1.EstimatorStatus {
+	for i := range statuses {
+		if statuses[i].ClusterName == clusterName {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// estimatorFallenBack returns whether the estimator of the cluster has fallen back, so that its Service is removed.
+func estimatorFallenBack(karmada *installv1alpha1.Karmada, clusterName string) bool {
+	status := findEstimatorStatus(karmada.Status.Estimators, clusterName)
+	return status != nil && status.FallenBack
+}
+
+// fallbackChangedClusters returns the clusters whose estimators have fallen back or been restored
+// between the two versions of the karmada.
+func fallbackChangedClusters(old, cur *installv1alpha1.Karmada) []string {
+	var clusters []string
+	for _, status := range old.Status.Estimators {
+		if status.FallenBack != estimatorFallenBack(cur, status.ClusterName) {
+			clusters = append(clusters, status.ClusterName)
+		}
+	}
+	for _, status := range cur.Status.Estimators {
+		if status.FallenBack && findEstimatorStatus(old.Status.Estimators, status.ClusterName) == nil {
+			clusters = append(clusters, status.ClusterName)
+		}
+	}
+	return clusters
+}
+
+// podReady returns whether the pod is ready.
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
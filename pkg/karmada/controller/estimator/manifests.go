@@ -19,11 +19,15 @@ package estimator
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -34,18 +38,114 @@ import (
 	"github.com/carlory/firefly/pkg/scheme"
 	"github.com/carlory/firefly/pkg/util"
 	clientutil "github.com/carlory/firefly/pkg/util/client"
+	"github.com/carlory/firefly/pkg/util/dryrun"
 	maputil "github.com/carlory/firefly/pkg/util/map"
 )
 
 const (
 	defaultEstimatorServicePrefix = "karmada-scheduler-estimator"
+	// estimatorComponent is the value of the component label of the estimator resources.
+	estimatorComponent = constants.ComponentEstimator
+	// defaultEstimatorPort is the port of the estimator Services and the gRPC servers of the
+	// estimators if it's not specified.
+	defaultEstimatorPort = 10352
+	// estimatorTLSMountPath is the path where the TLS Secret of the estimator is mounted.
+	estimatorTLSMountPath = "/etc/estimator-tls"
+	// estimatorHealthPort is the port on which the estimators serve their health endpoint and metrics.
+	estimatorHealthPort = 10351
 )
 
+// estimatorSettings holds the settings of the estimator of a member cluster, which are the settings
+// of the karmada-scheduler-estimator component overridden by the cluster override.
+type estimatorSettings struct {
+	replicas    *int32
+	serviceType corev1.ServiceType
+	servicePort int32
+	serverPort  int32
+	grpcTLS     *installv1alpha1.EstimatorGRPCTLS
+	extraArgs   map[string]string
+}
+
+// settingsFor returns the settings of the estimator of the given member cluster.
+func settingsFor(estimator *installv1alpha1.KarmadaSchedulerEstimatorComponent, clusterName string) estimatorSettings {
+	settings := estimatorSettings{
+		replicas:    estimator.Replicas,
+		serviceType: estimator.Service.Type,
+		servicePort: estimator.Service.Port,
+		grpcTLS:     estimator.GRPCTLS,
+		extraArgs:   estimator.ExtraArgs,
+	}
+	if settings.serviceType == "" {
+		settings.serviceType = corev1.ServiceTypeClusterIP
+	}
+	if settings.servicePort == 0 {
+		settings.servicePort = defaultEstimatorPort
+	}
+	settings.serverPort = settings.servicePort
+
+	for i := range estimator.ClusterOverrides {
+		override := &estimator.ClusterOverrides[i]
+		if override.ClusterName != clusterName {
+			continue
+		}
+		if override.Replicas != nil {
+			settings.replicas = override.Replicas
+		}
+		if override.ServiceType != "" {
+			settings.serviceType = override.ServiceType
+		}
+		if override.ServerPort != 0 {
+			settings.serverPort = override.ServerPort
+		}
+		if override.GRPCTLS != nil {
+			settings.grpcTLS = override.GRPCTLS
+		}
+		settings.extraArgs = maputil.MergeStringMaps(settings.extraArgs, override.ExtraArgs)
+		break
+	}
+	return settings
+}
+
+// EstimatorSelector returns the selector of the host cluster resources created by the estimator
+// controller of the given karmada.
+func EstimatorSelector(karmadaName string) labels.Selector {
+	return labels.SelectorFromSet(labels.Set{
+		constants.ComponentLabel:       estimatorComponent,
+		constants.KarmadaInstanceLabel: karmadaName,
+	})
+}
+
+// setEstimatorLabels sets the labels by which the estimator resources are selected and mapped
+// back to their member cluster.
+func setEstimatorLabels(obj metav1.Object, clusterName string) {
+	objLabels := obj.GetLabels()
+	if objLabels == nil {
+		objLabels = make(map[string]string)
+	}
+	objLabels[constants.ComponentLabel] = estimatorComponent
+	objLabels[constants.ClusterLabel] = clusterName
+	obj.SetLabels(objLabels)
+}
+
+// KubeConfigFromSecret returns the kubeconfig of the cluster built from its Secret in the karmada, which is
+// resolved through the cache of the object resolver since it's read on each sync of the cluster.
 func (ctrl *EstimatorController) KubeConfigFromSecret(ctx context.Context, cluster *clusterv1alpha1.Cluster) (*clientcmdapi.Config, error) {
-	credentials, err := ctrl.karmadaKubeClient.CoreV1().Secrets(cluster.Spec.SecretRef.Namespace).Get(ctx, cluster.Spec.SecretRef.Name, metav1.GetOptions{})
+	if cluster.Spec.SecretRef == nil {
+		return nil, fmt.Errorf("cluster %s has no secret", cluster.Name)
+	}
+	obj, err := ctrl.objectResolver.Resolve(ctx, corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Namespace:  cluster.Spec.SecretRef.Namespace,
+		Name:       cluster.Spec.SecretRef.Name,
+	})
 	if err != nil {
 		return nil, err
 	}
+	credentials := &corev1.Secret{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), credentials); err != nil {
+		return nil, fmt.Errorf("failed to convert the secret of cluster %s: %v", cluster.Name, err)
+	}
 
 	cfg := &clientcmdapi.Config{
 		CurrentContext: cluster.Name,
@@ -101,12 +201,16 @@ func (ctrl *EstimatorController) EnsureEstimatorKubeconfigSecret(ctx context.Con
 			"kubeconfig": kubeconfigData,
 		},
 	}
+	util.SetKarmadaInstanceLabel(secret, karmada.Name)
+	setEstimatorLabels(secret, cluster.Name)
 	controllerutil.SetOwnerReference(karmada, secret, scheme.Scheme)
-	return clientutil.CreateOrUpdateSecret(ctrl.fireflyKubeClient, secret)
+	_, err = clientutil.CreateOrUpdateSecret(ctx, ctrl.fireflyKubeClient, secret)
+	return err
 }
 
 func (ctrl *EstimatorController) EnsureEstimatorService(ctx context.Context, karmada *installv1alpha1.Karmada, cluster *clusterv1alpha1.Cluster) error {
 	estimatorName := GenerateEstimatorName(karmada.Name, defaultEstimatorServicePrefix, cluster.Name)
+	settings := settingsFor(&karmada.Spec.Scheduler.KarmadaSchedulerEstimator, cluster.Name)
 	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      estimatorName,
@@ -116,11 +220,12 @@ func (ctrl *EstimatorController) EnsureEstimatorService(ctx context.Context, kar
 			},
 		},
 		Spec: corev1.ServiceSpec{
+			Type: settings.serviceType,
 			Ports: []corev1.ServicePort{
 				{
 					Name:       "estimator",
-					Port:       10352,
-					TargetPort: intstr.FromInt(10352),
+					Port:       settings.servicePort,
+					TargetPort: intstr.FromInt(int(settings.serverPort)),
 					Protocol:   corev1.ProtocolTCP,
 				},
 			},
@@ -129,21 +234,73 @@ func (ctrl *EstimatorController) EnsureEstimatorService(ctx context.Context, kar
 			},
 		},
 	}
+	util.SetKarmadaInstanceLabel(svc, karmada.Name)
+	setEstimatorLabels(svc, cluster.Name)
 	controllerutil.SetOwnerReference(karmada, svc, scheme.Scheme)
-	return clientutil.CreateOrUpdateService(ctrl.fireflyKubeClient, svc)
+	ctrl.debugRecorder.ObserveDesiredState(cluster.Name, svc)
+	_, err := clientutil.CreateOrUpdateService(ctx, ctrl.fireflyKubeClient, svc)
+	return err
 }
 
-func (ctrl *EstimatorController) EnsureEstimatorDeployment(ctx context.Context, karmada *installv1alpha1.Karmada, cluster *clusterv1alpha1.Cluster) error {
+// EnsureEstimatorDeployment creates or updates the deployment of the estimator of the cluster. certSerial is
+// the serial number of the serving certificate issued by firefly for the estimator, if any.
+func (ctrl *EstimatorController) EnsureEstimatorDeployment(ctx context.Context, karmada *installv1alpha1.Karmada, cluster *clusterv1alpha1.Cluster, certSerial string) error {
 	estimatorName := GenerateEstimatorName(karmada.Name, defaultEstimatorServicePrefix, cluster.Name)
 	repository := karmada.Spec.ImageRepository
 	version := karmada.Spec.KarmadaVersion
 	estimator := karmada.Spec.Scheduler.KarmadaSchedulerEstimator
+	settings := settingsFor(&estimator, cluster.Name)
+	if settings.grpcTLS == nil && certSerial != "" {
+		settings.grpcTLS = &installv1alpha1.EstimatorGRPCTLS{
+			SecretName: GenerateEstimatorCertSecretName(karmada.Name, defaultEstimatorServicePrefix, cluster.Name),
+		}
+	}
 
 	defaultArgs := map[string]string{
 		"kubeconfig":   "/etc/kuberentes/kubeconfig",
 		"cluster-name": cluster.Name,
+		"server-port":  strconv.Itoa(int(settings.serverPort)),
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: "kubeconfig",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: fmt.Sprintf("%s-kubeconfig", estimatorName),
+				},
+			},
+		},
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "kubeconfig",
+			MountPath: "/etc/kuberentes/kubeconfig",
+			SubPath:   "kubeconfig",
+		},
+	}
+	if tls := settings.grpcTLS; tls != nil {
+		defaultArgs["grpc-auth-cert-file"] = estimatorTLSMountPath + "/tls.crt"
+		defaultArgs["grpc-auth-key-file"] = estimatorTLSMountPath + "/tls.key"
+		if tls.InsecureSkipClientVerify {
+			defaultArgs["insecure-skip-grpc-client-verify"] = "true"
+		} else {
+			defaultArgs["grpc-client-ca-file"] = estimatorTLSMountPath + "/ca.crt"
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: "grpc-tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: tls.SecretName,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "grpc-tls",
+			MountPath: estimatorTLSMountPath,
+			ReadOnly:  true,
+		})
 	}
-	computedArgs := maputil.MergeStringMaps(defaultArgs, estimator.ExtraArgs)
+	computedArgs := maputil.MergeStringMaps(defaultArgs, util.LoggingArgs(estimator.Logging), settings.extraArgs)
 	args := maputil.ConvertToCommandOrArgs(computedArgs)
 
 	deployment := &appsv1.Deployment{
@@ -160,42 +317,28 @@ func (ctrl *EstimatorController) EnsureEstimatorDeployment(ctx context.Context,
 					"app": estimatorName,
 				},
 			},
-			Replicas: estimator.Replicas,
+			Replicas: settings.replicas,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{
-						"app": estimatorName,
+						"app":                    estimatorName,
+						constants.ComponentLabel: estimatorComponent,
 					},
 				},
 				Spec: corev1.PodSpec{
-					Volumes: []corev1.Volume{
-						{
-							Name: "kubeconfig",
-							VolumeSource: corev1.VolumeSource{
-								Secret: &corev1.SecretVolumeSource{
-									SecretName: fmt.Sprintf("%s-kubeconfig", estimatorName),
-								},
-							},
-						},
-					},
+					Volumes: volumes,
 					Containers: []corev1.Container{
 						{
-							Name:    estimatorName,
-							Image:   util.ComponentImageName(repository, constants.KarmadaComponentSchedulerEstimator, version),
-							Command: []string{"/bin/karmada-scheduler-estimator"},
-							Args:    args,
-							VolumeMounts: []corev1.VolumeMount{
-								{
-									Name:      "kubeconfig",
-									MountPath: "/etc/kuberentes/kubeconfig",
-									SubPath:   "kubeconfig",
-								},
-							},
+							Name:         estimatorName,
+							Image:        util.ComponentImageName(repository, constants.KarmadaComponentSchedulerEstimator, version),
+							Command:      []string{"/bin/karmada-scheduler-estimator"},
+							Args:         args,
+							VolumeMounts: volumeMounts,
 							LivenessProbe: &corev1.Probe{
 								ProbeHandler: corev1.ProbeHandler{
 									HTTPGet: &corev1.HTTPGetAction{
 										Path:   "/healthz",
-										Port:   intstr.FromInt(10351),
+										Port:   intstr.FromInt(estimatorHealthPort),
 										Scheme: corev1.URISchemeHTTP,
 									},
 								},
@@ -207,7 +350,7 @@ func (ctrl *EstimatorController) EnsureEstimatorDeployment(ctx context.Context,
 							Ports: []corev1.ContainerPort{
 								{
 									Name:          "estimator",
-									ContainerPort: 10352,
+									ContainerPort: settings.serverPort,
 									Protocol:      corev1.ProtocolTCP,
 								},
 							},
@@ -217,8 +360,29 @@ func (ctrl *EstimatorController) EnsureEstimatorDeployment(ctx context.Context,
 			},
 		},
 	}
+	util.ApplyComponentExtras(&deployment.Spec.Template.Spec, estimator.ComponentExtras)
+	if certSerial != "" {
+		deployment.Spec.Template.Annotations = map[string]string{constants.GRPCCertSerialAnnotation: certSerial}
+	}
+	util.SetKarmadaInstanceLabel(deployment, karmada.Name)
+	setEstimatorLabels(deployment, cluster.Name)
 	controllerutil.SetOwnerReference(karmada, deployment, scheme.Scheme)
-	return clientutil.CreateOrUpdateDeployment(ctrl.fireflyKubeClient, deployment)
+	ctrl.debugRecorder.ObserveDesiredState(cluster.Name, deployment)
+
+	// the creation is expected if the deployment isn't in the cache of the informer, it's observed
+	// right away if the deployment turns out to exist.
+	_, err := ctrl.deploymentsLister.ByNamespace(deployment.Namespace).Get(deployment.Name)
+	expected := errors.IsNotFound(err) && !dryrun.Enabled(ctx)
+	if expected {
+		if err := ctrl.expectations.ExpectCreations(cluster.Name, 1); err != nil {
+			return err
+		}
+	}
+	result, err := clientutil.CreateOrUpdateDeployment(ctx, ctrl.fireflyKubeClient, deployment)
+	if expected && (err != nil || result != clientutil.OperationResultCreated) {
+		ctrl.expectations.CreationObserved(cluster.Name)
+	}
+	return err
 }
 
 // GenerateEstimatorName generates the gRPC scheduler estimator service name which belongs to a cluster.
@@ -230,3 +394,8 @@ func GenerateEstimatorName(karmadaName, estimatorServicePrefix, clusterName stri
 func GenerateEstimatorKubeConfigSecretName(karmadaName, estimatorServicePrefix, clusterName string) string {
 	return fmt.Sprintf("%s-%s-kubeconfig", estimatorServicePrefix, clusterName)
 }
+
+// GenerateEstimatorCertSecretName generates the secret name which holds the serving certificate issued by firefly.
+func GenerateEstimatorCertSecretName(karmadaName, estimatorServicePrefix, clusterName string) string {
+	return fmt.Sprintf("%s-%s-cert", estimatorServicePrefix, clusterName)
+}
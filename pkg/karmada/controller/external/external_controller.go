@@ -0,0 +1,228 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package external runs the controllers which are not compiled into the firefly-karmada-manager as its
+// child processes, so that they're elected and toggled like the built-in controllers.
+package external
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// minRestartBackoff and maxRestartBackoff bound the delay before an exited process is restarted,
+	// which is doubled on each exit.
+	minRestartBackoff = time.Second
+	maxRestartBackoff = 5 * time.Minute
+	// stableRunDuration is how long a process must run to reset the restart back-off.
+	stableRunDuration = 10 * time.Minute
+	// terminationGracePeriod is how long a process is given to exit after SIGTERM before it's killed.
+	terminationGracePeriod = 30 * time.Second
+)
+
+// The environment variables set for the processes of the external controllers.
+const (
+	EnvControllerName    = "FIREFLY_CONTROLLER_NAME"
+	EnvKarmadaKubeconfig = "FIREFLY_KARMADA_KUBECONFIG"
+	EnvHostKubeconfig    = "FIREFLY_HOST_KUBECONFIG"
+	EnvKarmadaName       = "FIREFLY_KARMADA_NAME"
+	EnvKarmadaNamespace  = "FIREFLY_KARMADA_NAMESPACE"
+	EnvDryRun            = "FIREFLY_DRY_RUN"
+)
+
+// ConfigFunc returns the client config the process of an external controller is run with.
+type ConfigFunc func() (*rest.Config, error)
+
+// NewExternalController returns a new *ExternalController which runs the command with the credentials
+// of the configs returned by karmadaConfig and hostConfig, which are called before each run.
+func NewExternalController(
+	name string,
+	command []string,
+	env map[string]string,
+	karmadaConfig ConfigFunc,
+	hostConfig ConfigFunc,
+	karmadaNamespace string,
+	karmadaName string,
+	dryRun bool,
+) *ExternalController {
+	ctrl := &ExternalController{
+		name:          name,
+		command:       command,
+		karmadaConfig: karmadaConfig,
+		hostConfig:    hostConfig,
+	}
+	for key, value := range env {
+		ctrl.env = append(ctrl.env, key+"="+value)
+	}
+	ctrl.env = append(ctrl.env,
+		EnvControllerName+"="+name,
+		EnvKarmadaName+"="+karmadaName,
+		EnvKarmadaNamespace+"="+karmadaNamespace,
+		EnvDryRun+"="+strconv.FormatBool(dryRun),
+	)
+	return ctrl
+}
+
+// ExternalController runs the process of an external controller and restarts it with a back-off until
+// the context is done.
+type ExternalController struct {
+	name    string
+	command []string
+	env     []string
+
+	karmadaConfig ConfigFunc
+	hostConfig    ConfigFunc
+}
+
+// Name returns the name of the controller.
+func (ctrl *ExternalController) Name() string {
+	return ctrl.name
+}
+
+// Run will not return until ctx is done and the process has exited.
+func (ctrl *ExternalController) Run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+
+	klog.InfoS("Starting external controller", "controller", ctrl.name, "command", ctrl.command)
+	defer klog.InfoS("Shutting down external controller", "controller", ctrl.name)
+
+	// the kubeconfigs are rebuilt from the configs for each run. The token files, the certificate files
+	// and the exec plugins they refer to are read by the process itself, so it picks up their rotation
+	// while it's running as well.
+	dir, err := os.MkdirTemp("", "firefly-"+ctrl.name+"-")
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to create the kubeconfig directory of external controller %s: %v", ctrl.name, err))
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	backoff := minRestartBackoff
+	for {
+		startTime := time.Now()
+		err := ctrl.runProcess(ctx, dir)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(startTime) >= stableRunDuration {
+			backoff = minRestartBackoff
+		}
+		klog.ErrorS(err, "External controller exited, restarting", "controller", ctrl.name, "backoff", backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxRestartBackoff {
+			backoff = maxRestartBackoff
+		}
+	}
+}
+
+// runProcess runs the process until it exits or ctx is done, in which case the process is terminated.
+func (ctrl *ExternalController) runProcess(ctx context.Context, dir string) error {
+	karmadaConfig, err := ctrl.karmadaConfig()
+	if err != nil {
+		return fmt.Errorf("failed to build the karmada client config: %v", err)
+	}
+	karmadaKubeconfig := filepath.Join(dir, "karmada.kubeconfig")
+	if err := writeKubeconfig(karmadaKubeconfig, karmadaConfig); err != nil {
+		return fmt.Errorf("failed to write the karmada kubeconfig: %v", err)
+	}
+	hostConfig, err := ctrl.hostConfig()
+	if err != nil {
+		return fmt.Errorf("failed to build the host client config: %v", err)
+	}
+	hostKubeconfig := filepath.Join(dir, "host.kubeconfig")
+	if err := writeKubeconfig(hostKubeconfig, hostConfig); err != nil {
+		return fmt.Errorf("failed to write the host kubeconfig: %v", err)
+	}
+
+	cmd := exec.Command(ctrl.command[0], ctrl.command[1:]...)
+	cmd.Env = append(os.Environ(), ctrl.env...)
+	cmd.Env = append(cmd.Env, EnvKarmadaKubeconfig+"="+karmadaKubeconfig, EnvHostKubeconfig+"="+hostKubeconfig)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	klog.V(2).InfoS("Started process of external controller", "controller", ctrl.name, "pid", cmd.Process.Pid)
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	select {
+	case err := <-exited:
+		if err == nil {
+			return fmt.Errorf("the process exited unexpectedly")
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	klog.V(2).InfoS("Terminating process of external controller", "controller", ctrl.name, "pid", cmd.Process.Pid)
+	_ = cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-exited:
+	case <-time.After(terminationGracePeriod):
+		klog.InfoS("Killing process of external controller after the grace period", "controller", ctrl.name, "pid", cmd.Process.Pid)
+		_ = cmd.Process.Kill()
+		<-exited
+	}
+	return nil
+}
+
+// writeKubeconfig writes the kubeconfig of the client config to the path, which is only readable
+// by the manager.
+func writeKubeconfig(path string, config *rest.Config) error {
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters["default"] = &clientcmdapi.Cluster{
+		Server:                   config.Host,
+		TLSServerName:            config.ServerName,
+		InsecureSkipTLSVerify:    config.Insecure,
+		CertificateAuthority:     config.CAFile,
+		CertificateAuthorityData: config.CAData,
+	}
+	kubeconfig.AuthInfos["default"] = &clientcmdapi.AuthInfo{
+		ClientCertificate:     config.CertFile,
+		ClientCertificateData: config.CertData,
+		ClientKey:             config.KeyFile,
+		ClientKeyData:         config.KeyData,
+		Token:                 config.BearerToken,
+		TokenFile:             config.BearerTokenFile,
+		Impersonate:           config.Impersonate.UserName,
+		ImpersonateGroups:     config.Impersonate.Groups,
+		Username:              config.Username,
+		Password:              config.Password,
+		AuthProvider:          config.AuthProvider,
+		Exec:                  config.ExecProvider,
+	}
+	kubeconfig.Contexts["default"] = &clientcmdapi.Context{Cluster: "default", AuthInfo: "default"}
+	kubeconfig.CurrentContext = "default"
+	return clientcmd.WriteToFile(*kubeconfig, path)
+}
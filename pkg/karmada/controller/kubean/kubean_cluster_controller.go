@@ -40,6 +40,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/debug"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
 )
 
 const (
@@ -58,6 +60,11 @@ var (
 	clusterGVR = schema.GroupVersionResource{Group: "kubean.io", Version: "v1alpha1", Resource: "clusters"}
 )
 
+// +firefly:rbac:cluster=karmada,groups=kubean.io,resources=clusters,verbs=get;list;watch;update
+// +firefly:rbac:cluster=karmada,groups=kubean.io,resources=clusters/status,verbs=update
+// +firefly:rbac:cluster=karmada,groups="",resources=events,verbs=create;update;patch
+// +firefly:rbac:cluster=host,groups=kubean.io,resources=clusters,verbs=get;list;watch;create;update;delete
+
 // NewClusterController returns a new *Controller.
 func NewClusterController(
 	karmadaNamespace string,
@@ -73,6 +80,7 @@ func NewClusterController(
 		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("cluster_controller", client.CoreV1().RESTClient().GetRateLimiter())
 	}
 
+	queue := debug.NewQueue(workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "cluster"))
 	ctrl := &ClusterController{
 		karmadaNamespace:   karmadaNamespace,
 		client:             client,
@@ -82,7 +90,8 @@ func NewClusterController(
 		hostClusterClient:  fireflyDynamicClient.Resource(clusterGVR),
 		hostClustersLister: hostClustersInformer.Lister(),
 		hostClustersSynced: hostClustersInformer.Informer().HasSynced,
-		queue:              workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "cluster"),
+		queue:              queue,
+		debugRecorder:      debug.NewRecorder(queue),
 		workerLoopPeriod:   time.Second,
 		eventBroadcaster:   broadcaster,
 		eventRecorder:      recorder,
@@ -140,11 +149,18 @@ type ClusterController struct {
 	// service that's inserted multiple times to be processed more than
 	// necessary.
 	queue workqueue.RateLimitingInterface
+	// debugRecorder records the reconcile results and desired states for the debugging handler.
+	debugRecorder *debug.Recorder
 
 	// workerLoopPeriod is the time between worker runs. The workers process the queue of service and pod changes.
 	workerLoopPeriod time.Duration
 }
 
+// DebugRecorder returns the recorder of the debugging information of the controller.
+func (ctrl *ClusterController) DebugRecorder() *debug.Recorder {
+	return ctrl.debugRecorder
+}
+
 // Run will not return until stopCh is closed. workers determines how many
 // cluster will be handled in parallel.
 func (ctrl *ClusterController) Run(ctx context.Context, workers int) {
@@ -186,7 +202,11 @@ func (ctrl *ClusterController) processNextWorkItem(ctx context.Context) bool {
 	}
 	defer ctrl.queue.Done(key)
 
+	startTime := time.Now()
+	ctx, span := tracingutil.StartReconcile(ctx, "kubean-cluster", key.(string))
 	err := ctrl.syncCluster(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.debugRecorder.ObserveReconcile(key.(string), startTime, err)
 	ctrl.handleErr(err, key)
 
 	return true
@@ -270,6 +290,7 @@ func (ctrl *ClusterController) syncCluster(ctx context.Context, key string) erro
 	clusterObj, err := ctrl.clustersLister.Get(name)
 	if errors.IsNotFound(err) {
 		klog.V(2).InfoS("Cluster has been deleted", "cluster", name)
+		ctrl.debugRecorder.Forget(key)
 		return nil
 	}
 	if err != nil {
@@ -318,6 +339,7 @@ func (ctrl *ClusterController) syncCluster(ctx context.Context, key string) erro
 	hostCluster := convert_kubean_cluster_karmada_to_host(ctrl.karmadaNamespace, cluster)
 	klog.InfoS("convert kubean cluster from karmada to host", "karmada", klog.KObj(cluster), "host", klog.KObj(hostCluster))
 	dropInvaildFields(hostCluster)
+	ctrl.debugRecorder.ObserveDesiredState(key, hostCluster)
 
 	existing, err := ctrl.hostClusterClient.Get(ctx, hostCluster.GetName(), metav1.GetOptions{})
 	if err != nil {
@@ -39,8 +39,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/carlory/firefly/pkg/scheme"
+	"github.com/carlory/firefly/pkg/util/debug"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
 )
 
+// +firefly:rbac:cluster=karmada,groups=kubean.io,resources=clusters,verbs=get;list;watch
+// +firefly:rbac:cluster=karmada,groups="",resources=configmaps,verbs=get;list;watch;update
+// +firefly:rbac:cluster=karmada,groups="",resources=events,verbs=create;update;patch
+// +firefly:rbac:cluster=host,groups="",resources=configmaps,verbs=get;create;update;delete
+
 // NewClusterRefController returns a new *Controller.
 func NewClusterRefController(
 	client clientset.Interface,
@@ -55,6 +62,7 @@ func NewClusterRefController(
 		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("clusterref_controller", client.CoreV1().RESTClient().GetRateLimiter())
 	}
 
+	queue := debug.NewQueue(workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "configmap"))
 	ctrl := &ClusterRefController{
 		karmadaNamespace: karmadaNamespace,
 		client:           client,
@@ -63,7 +71,8 @@ func NewClusterRefController(
 		configMapLister:  configMapInformer.Lister(),
 		configMapSynced:  configMapInformer.Informer().HasSynced,
 		fireflyClient:    fireflyClient,
-		queue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "configmap"),
+		queue:            queue,
+		debugRecorder:    debug.NewRecorder(queue),
 		workerLoopPeriod: time.Second,
 		eventBroadcaster: broadcaster,
 		eventRecorder:    recorder,
@@ -98,11 +107,18 @@ type ClusterRefController struct {
 	// service that's inserted multiple times to be processed more than
 	// necessary.
 	queue workqueue.RateLimitingInterface
+	// debugRecorder records the reconcile results and desired states for the debugging handler.
+	debugRecorder *debug.Recorder
 
 	// workerLoopPeriod is the time between worker runs. The workers process the queue of service and pod changes.
 	workerLoopPeriod time.Duration
 }
 
+// DebugRecorder returns the recorder of the debugging information of the controller.
+func (ctrl *ClusterRefController) DebugRecorder() *debug.Recorder {
+	return ctrl.debugRecorder
+}
+
 // Run will not return until stopCh is closed. workers determines how many
 // cluster will be handled in parallel.
 func (ctrl *ClusterRefController) Run(ctx context.Context, workers int) {
@@ -144,7 +160,11 @@ func (ctrl *ClusterRefController) processNextWorkItem(ctx context.Context) bool
 	}
 	defer ctrl.queue.Done(key)
 
+	startTime := time.Now()
+	ctx, span := tracingutil.StartReconcile(ctx, "kubean-clusterref", key.(string))
 	err := ctrl.sync(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.debugRecorder.ObserveReconcile(key.(string), startTime, err)
 	ctrl.handleErr(err, key)
 
 	return true
@@ -229,6 +249,7 @@ func (ctrl *ClusterRefController) sync(ctx context.Context, key string) error {
 	cm, err := ctrl.configMapLister.ConfigMaps(namespace).Get(name)
 	if errors.IsNotFound(err) {
 		klog.V(2).InfoS("Cluster has been deleted", "cluster", name)
+		ctrl.debugRecorder.Forget(key)
 		return nil
 	}
 	if err != nil {
@@ -277,6 +298,7 @@ func (ctrl *ClusterRefController) sync(ctx context.Context, key string) error {
 	hostConfigMap := convert_confimap_from_karmada_to_host(ctrl.karmadaNamespace, cm)
 	klog.InfoS("convert the configmap from karmada to host", "karmada", klog.KObj(cm), "host", klog.KObj(hostConfigMap))
 	dropInvaildFields(hostConfigMap)
+	ctrl.debugRecorder.ObserveDesiredState(key, hostConfigMap)
 
 	existing, err := ctrl.fireflyClient.CoreV1().ConfigMaps(hostConfigMap.Namespace).Get(ctx, hostConfigMap.Name, metav1.GetOptions{})
 	if err != nil {
@@ -40,12 +40,18 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/carlory/firefly/pkg/karmada/scheme"
+	"github.com/carlory/firefly/pkg/util/debug"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
 )
 
 var (
 	manifestGVR = schema.GroupVersionResource{Group: "kubean.io", Version: "v1alpha1", Resource: "manifests"}
 )
 
+// +firefly:rbac:cluster=karmada,groups=kubean.io,resources=manifests,verbs=get;list;watch;create;update;delete
+// +firefly:rbac:cluster=karmada,groups="",resources=events,verbs=create;update;patch
+// +firefly:rbac:cluster=host,groups=kubean.io,resources=manifests,verbs=get;list;watch
+
 // NewManifestController returns a new *Controller.
 func NewManifestController(
 	kubeClient clientset.Interface,
@@ -60,6 +66,7 @@ func NewManifestController(
 		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("manifest_controller", kubeClient.CoreV1().RESTClient().GetRateLimiter())
 	}
 
+	queue := debug.NewQueue(workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "manifest"))
 	ctrl := &ManifestController{
 		kubeClient:         kubeClient,
 		manifestClient:     dynamicClient.Resource(manifestGVR),
@@ -67,7 +74,8 @@ func NewManifestController(
 		manifestSynced:     manifestInformer.Informer().HasSynced,
 		hostManifestLister: hostManifestInformer.Lister(),
 		hostManifestSynced: hostManifestInformer.Informer().HasSynced,
-		queue:              workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "manifest"),
+		queue:              queue,
+		debugRecorder:      debug.NewRecorder(queue),
 		workerLoopPeriod:   time.Second,
 		eventBroadcaster:   broadcaster,
 		eventRecorder:      recorder,
@@ -103,11 +111,18 @@ type ManifestController struct {
 	// service that's inserted multiple times to be processed more than
 	// necessary.
 	queue workqueue.RateLimitingInterface
+	// debugRecorder records the reconcile results and desired states for the debugging handler.
+	debugRecorder *debug.Recorder
 
 	// workerLoopPeriod is the time between worker runs. The workers process the queue of service and pod changes.
 	workerLoopPeriod time.Duration
 }
 
+// DebugRecorder returns the recorder of the debugging information of the controller.
+func (ctrl *ManifestController) DebugRecorder() *debug.Recorder {
+	return ctrl.debugRecorder
+}
+
 // Run will not return until stopCh is closed. workers determines how many
 // manifest will be handled in parallel.
 func (ctrl *ManifestController) Run(ctx context.Context, workers int) {
@@ -154,7 +169,11 @@ func (ctrl *ManifestController) processNextWorkItem(ctx context.Context) bool {
 	}
 	defer ctrl.queue.Done(key)
 
+	startTime := time.Now()
+	ctx, span := tracingutil.StartReconcile(ctx, "kubean-manifest", key.(string))
 	err := ctrl.syncManifest(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.debugRecorder.ObserveReconcile(key.(string), startTime, err)
 	ctrl.handleErr(err, key)
 
 	return true
@@ -233,6 +252,7 @@ func (ctrl *ManifestController) syncManifest(ctx context.Context, key string) er
 	hostObj, err := ctrl.hostManifestLister.Get(name)
 	if errors.IsNotFound(err) {
 		klog.V(2).InfoS("Manifest has been deleted", "manifest", klog.KRef("", name))
+		ctrl.debugRecorder.Forget(key)
 		obj, err := ctrl.manifestLister.Get(name)
 		if errors.IsNotFound(err) {
 			return nil
@@ -248,6 +268,7 @@ func (ctrl *ManifestController) syncManifest(ctx context.Context, key string) er
 	// TODO: Deep-copy only when needed.
 	hostManifest := hostObj.DeepCopyObject().(*unstructured.Unstructured)
 	dropInvaildFields(hostManifest)
+	ctrl.debugRecorder.ObserveDesiredState(key, hostManifest)
 
 	existingObj, err := ctrl.manifestLister.Get(hostManifest.GetName())
 	if errors.IsNotFound(err) {
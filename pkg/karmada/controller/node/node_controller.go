@@ -19,14 +19,20 @@ package node
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
+	karmadaversioned "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	clusterinformers "github.com/karmada-io/karmada/pkg/generated/informers/externalversions/cluster/v1alpha1"
+	clusterlisters "github.com/karmada-io/karmada/pkg/generated/listers/cluster/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	clientset "k8s.io/client-go/kubernetes"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -37,7 +43,10 @@ import (
 	"k8s.io/component-base/metrics/prometheus/ratelimiter"
 	"k8s.io/klog/v2"
 
+	"github.com/carlory/firefly/pkg/karmada/membercluster"
 	"github.com/carlory/firefly/pkg/karmada/scheme"
+	"github.com/carlory/firefly/pkg/util/debug"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
 )
 
 const (
@@ -49,11 +58,22 @@ const (
 	maxRetries = 15
 )
 
+// +firefly:rbac:cluster=karmada,groups="",resources=nodes,verbs=get;list;watch;create;update;delete
+// +firefly:rbac:cluster=karmada,groups="",resources=events,verbs=create;update;patch
+// +firefly:rbac:cluster=karmada,groups=cluster.karmada.io,resources=clusters,verbs=get;list;watch;patch
+// +firefly:rbac:cluster=karmada,groups=cluster.karmada.io,resources=clusters/proxy,verbs=get
+// +firefly:rbac:cluster=host,groups="",resources=nodes,verbs=get;list;watch
+
 // NewNodeController returns a new *Controller.
 func NewNodeController(
 	karmadaKubeClient clientset.Interface,
+	karmadaClient karmadaversioned.Interface,
+	memberClusters *membercluster.Cache,
 	nodeInformer coreinformers.NodeInformer,
-	karmadaNodeInformer coreinformers.NodeInformer,
+	karmadaNodeInformer informers.GenericInformer,
+	clusterInformer clusterinformers.ClusterInformer,
+	extendedResources []string,
+	acceleratorLabels []string,
 ) (*NodeController, error) {
 	broadcaster := record.NewBroadcaster()
 	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "node-controller"})
@@ -62,13 +82,21 @@ func NewNodeController(
 		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("node_controller", karmadaKubeClient.CoreV1().RESTClient().GetRateLimiter())
 	}
 
+	queue := debug.NewQueue(workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "node"))
 	ctrl := &NodeController{
 		karmadaKubeClient:  karmadaKubeClient,
+		karmadaClient:      karmadaClient,
+		memberClusters:     memberClusters,
 		nodeLister:         nodeInformer.Lister(),
 		nodeSynced:         nodeInformer.Informer().HasSynced,
 		karmadaNodeLister:  karmadaNodeInformer.Lister(),
 		karmadanNodeSynced: karmadaNodeInformer.Informer().HasSynced,
-		queue:              workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "node"),
+		clusterLister:      clusterInformer.Lister(),
+		clustersSynced:     clusterInformer.Informer().HasSynced,
+		extendedResources:  extendedResources,
+		acceleratorLabels:  acceleratorLabels,
+		queue:              queue,
+		debugRecorder:      debug.NewRecorder(queue),
 		workerLoopPeriod:   time.Second,
 		eventBroadcaster:   broadcaster,
 		eventRecorder:      recorder,
@@ -78,9 +106,10 @@ func NewNodeController(
 		UpdateFunc: ctrl.updateNode,
 		DeleteFunc: ctrl.deleteNode,
 	})
+	// only the metadata of the karmada nodes is watched, it's enough to find the nodes left behind.
 	karmadaNodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		UpdateFunc: ctrl.updateNode,
-		DeleteFunc: ctrl.deleteNode,
+		UpdateFunc: ctrl.updateKarmadaNode,
+		DeleteFunc: ctrl.deleteKarmadaNode,
 	})
 
 	return ctrl, nil
@@ -88,25 +117,56 @@ func NewNodeController(
 
 type NodeController struct {
 	karmadaKubeClient clientset.Interface
+	karmadaClient     karmadaversioned.Interface
 	eventBroadcaster  record.EventBroadcaster
 	eventRecorder     record.EventRecorder
 
+	// memberClusters holds the clients of the member clusters, which reach them through the cluster proxy.
+	memberClusters *membercluster.Cache
+
 	nodeLister         corelisters.NodeLister
 	nodeSynced         cache.InformerSynced
-	karmadaNodeLister  corelisters.NodeLister
+	karmadaNodeLister  cache.GenericLister
 	karmadanNodeSynced cache.InformerSynced
 
+	// the member clusters whose resource summaries are published.
+	clusterLister  clusterlisters.ClusterLister
+	clustersSynced cache.InformerSynced
+	// extendedResources is the allowlist of the extended resources which are summarized.
+	extendedResources []string
+	// acceleratorLabels is the keys of the node labels whose values are summarized.
+	acceleratorLabels []string
+
 	// Node that need to be updated. A channel is inappropriate here,
 	// because it allows services with lots of pods to be serviced much
 	// more often than services with few pods; it also would cause a
 	// service that's inserted multiple times to be processed more than
 	// necessary.
 	queue workqueue.RateLimitingInterface
+	// debugRecorder records the reconcile results and desired states for the debugging handler.
+	debugRecorder *debug.Recorder
 
 	// workerLoopPeriod is the time between worker runs. The workers process the queue of service and pod changes.
 	workerLoopPeriod time.Duration
 }
 
+// Name returns the name of the controller.
+func (ctrl *NodeController) Name() string {
+	return "node"
+}
+
+// DebuggingHandler returns a handler which exposes the queue, the last reconcile results
+// and the changes of the desired states of the controller.
+func (ctrl *NodeController) DebuggingHandler() http.Handler {
+	return ctrl.debugRecorder
+}
+
+// HasSynced returns whether the caches of the nodes and the member clusters are synced, the
+// controllers depending on the node controller are started once they are.
+func (ctrl *NodeController) HasSynced() bool {
+	return ctrl.nodeSynced() && ctrl.karmadanNodeSynced() && ctrl.clustersSynced()
+}
+
 // Run will not return until stopCh is closed. workers determines how many
 // node will be handled in parallel.
 func (ctrl *NodeController) Run(ctx context.Context, workers int) {
@@ -122,18 +182,21 @@ func (ctrl *NodeController) Run(ctx context.Context, workers int) {
 	klog.Infof("Starting node controller")
 	defer klog.Infof("Shutting down node controller")
 
-	if !cache.WaitForNamedCacheSync("node", ctx.Done(), ctrl.nodeSynced, ctrl.karmadanNodeSynced) {
+	if !cache.WaitForNamedCacheSync("node", ctx.Done(), ctrl.nodeSynced, ctrl.karmadanNodeSynced, ctrl.clustersSynced) {
 		return
 	}
 
 	nodes, _ := ctrl.karmadaNodeLister.List(labels.Everything())
 	for _, node := range nodes {
-		ctrl.addNode(node)
+		if accessor, err := meta.Accessor(node); err == nil {
+			ctrl.queue.Add(accessor.GetName())
+		}
 	}
 
 	for i := 0; i < workers; i++ {
 		go wait.UntilWithContext(ctx, ctrl.worker, ctrl.workerLoopPeriod)
 	}
+	go wait.UntilWithContext(ctx, ctrl.summarizeClusters, clusterSummaryPeriod)
 	<-ctx.Done()
 }
 
@@ -153,7 +216,11 @@ func (ctrl *NodeController) processNextWorkItem(ctx context.Context) bool {
 	}
 	defer ctrl.queue.Done(key)
 
+	startTime := time.Now()
+	ctx, span := tracingutil.StartReconcile(ctx, "node", key.(string))
 	err := ctrl.syncNode(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.debugRecorder.ObserveReconcile(key.(string), startTime, err)
 	ctrl.handleErr(err, key)
 
 	return true
@@ -190,6 +257,30 @@ func (ctrl *NodeController) deleteNode(obj interface{}) {
 	ctrl.enqueue(node)
 }
 
+func (ctrl *NodeController) updateKarmadaNode(old, cur interface{}) {
+	curNode := cur.(*metav1.PartialObjectMetadata)
+	klog.V(4).InfoS("Updating karmada node", "node", klog.KObj(curNode))
+	ctrl.queue.Add(curNode.Name)
+}
+
+func (ctrl *NodeController) deleteKarmadaNode(obj interface{}) {
+	node, ok := obj.(*metav1.PartialObjectMetadata)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("couldn't get object from tombstone %#v", obj))
+			return
+		}
+		node, ok = tombstone.Obj.(*metav1.PartialObjectMetadata)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("tombstone contained object that is not a PartialObjectMetadata %#v", obj))
+			return
+		}
+	}
+	klog.V(4).InfoS("Deleting karmada node", "node", klog.KObj(node))
+	ctrl.queue.Add(node.Name)
+}
+
 func (ctrl *NodeController) enqueue(node *corev1.Node) {
 	ctrl.queue.Add(node.Name)
 }
@@ -221,11 +312,15 @@ func (ctrl *NodeController) syncNode(ctx context.Context, key string) error {
 	node, err := ctrl.nodeLister.Get(key)
 	if errors.IsNotFound(err) || node.DeletionTimestamp != nil {
 		klog.V(2).InfoS("Node has been deleted", "node", klog.KRef("", key))
-		oldNode, err := ctrl.karmadaNodeLister.Get(key)
+		ctrl.debugRecorder.Forget(key)
+		_, err := ctrl.karmadaNodeLister.Get(key)
 		if errors.IsNotFound(err) {
 			return nil
 		}
-		return ctrl.karmadaKubeClient.CoreV1().Nodes().Delete(ctx, oldNode.Name, metav1.DeleteOptions{})
+		if err != nil {
+			return err
+		}
+		return ctrl.karmadaKubeClient.CoreV1().Nodes().Delete(ctx, key, metav1.DeleteOptions{})
 	}
 	if err != nil {
 		return err
@@ -235,13 +330,21 @@ func (ctrl *NodeController) syncNode(ctx context.Context, key string) error {
 	// TODO: Deep-copy only when needed.
 	clone := node.DeepCopy()
 	dropInvaildFields(clone)
+	ctrl.debugRecorder.ObserveDesiredState(key, clone)
 
-	karmadaNode, err := ctrl.karmadaNodeLister.Get(clone.Name)
+	obj, err := ctrl.karmadaNodeLister.Get(clone.Name)
 	if errors.IsNotFound(err) {
 		setKarmadaFields(nil, clone)
 		_, err := ctrl.karmadaKubeClient.CoreV1().Nodes().Create(ctx, clone, metav1.CreateOptions{})
 		return err
 	}
+	if err != nil {
+		return err
+	}
+	karmadaNode, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
 
 	setKarmadaFields(karmadaNode, clone)
 	_, err = ctrl.karmadaKubeClient.CoreV1().Nodes().Update(ctx, clone, metav1.UpdateOptions{})
@@ -259,7 +362,7 @@ func dropInvaildFields(node *corev1.Node) {
 	node.GenerateName = ""
 }
 
-func setKarmadaFields(oldNode, newNode *corev1.Node) {
+func setKarmadaFields(oldNode metav1.Object, newNode *corev1.Node) {
 	if newNode.Labels == nil {
 		newNode.Labels = make(map[string]string)
 	}
@@ -268,6 +371,6 @@ func setKarmadaFields(oldNode, newNode *corev1.Node) {
 	if oldNode == nil {
 		return
 	}
-	newNode.ResourceVersion = oldNode.ResourceVersion
-	newNode.UID = oldNode.UID
+	newNode.ResourceVersion = oldNode.GetResourceVersion()
+	newNode.UID = oldNode.GetUID()
 }
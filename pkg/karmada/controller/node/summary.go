@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/carlory/firefly/pkg/constants"
+)
+
+// clusterSummaryPeriod is the period at which the resource summaries of the member clusters are refreshed.
+const clusterSummaryPeriod = time.Minute
+
+// resourceSummary is the aggregated capacity and allocatable resources of the nodes of a member cluster,
+// and the number of its nodes by the values of the accelerator labels.
+type resourceSummary struct {
+	capacity    corev1.ResourceList
+	allocatable corev1.ResourceList
+	labels      map[string]map[string]int
+}
+
+// summarizeClusters refreshes the resource summaries published on the ready member clusters in push mode,
+// whose nodes can be listed through the cluster proxy of the karmada-aggregated-apiserver.
+func (ctrl *NodeController) summarizeClusters(ctx context.Context) {
+	clusters, err := ctrl.clusterLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list clusters")
+		return
+	}
+	for _, cluster := range clusters {
+		if cluster.Spec.SyncMode != clusterv1alpha1.Push || !cluster.DeletionTimestamp.IsZero() ||
+			!meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1alpha1.ClusterConditionReady) {
+			continue
+		}
+		if err := ctrl.summarizeCluster(ctx, cluster); err != nil {
+			klog.ErrorS(err, "Failed to summarize the resources of cluster", "cluster", klog.KObj(cluster))
+		}
+	}
+}
+
+// summarizeCluster publishes the aggregated resources of the nodes of the member cluster as annotations
+// of the cluster, unless they're unchanged.
+func (ctrl *NodeController) summarizeCluster(ctx context.Context, cluster *clusterv1alpha1.Cluster) error {
+	member, err := ctrl.memberClusters.Get(cluster.Name)
+	if err != nil {
+		return err
+	}
+	if !member.Healthy() {
+		return fmt.Errorf("member cluster is unhealthy: %v", member.LastError())
+	}
+	nodes, err := member.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	summary := ctrl.summarizeNodes(nodes.Items)
+	capacity, err := encodeResourceList(summary.capacity)
+	if err != nil {
+		return err
+	}
+	allocatable, err := encodeResourceList(summary.allocatable)
+	if err != nil {
+		return err
+	}
+	acceleratorLabels, err := json.Marshal(summary.labels)
+	if err != nil {
+		return err
+	}
+	if cluster.Annotations[constants.ClusterCapacityAnnotation] == capacity &&
+		cluster.Annotations[constants.ClusterAllocatableAnnotation] == allocatable &&
+		cluster.Annotations[constants.ClusterAcceleratorLabelsAnnotation] == string(acceleratorLabels) {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				constants.ClusterCapacityAnnotation:          capacity,
+				constants.ClusterAllocatableAnnotation:       allocatable,
+				constants.ClusterAcceleratorLabelsAnnotation: string(acceleratorLabels),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	klog.V(4).InfoS("Updating resource summary of cluster", "cluster", klog.KObj(cluster), "capacity", capacity, "allocatable", allocatable)
+	_, err = ctrl.karmadaClient.ClusterV1alpha1().Clusters().Patch(ctx, cluster.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// summarizeNodes sums up the cpu, memory, pods and the allowed extended resources, e.g. GPUs, of the nodes,
+// and counts the nodes by the values of the accelerator labels. The allocatable resources only count the
+// nodes which are ready and schedulable.
+func (ctrl *NodeController) summarizeNodes(nodes []corev1.Node) resourceSummary {
+	summary := resourceSummary{
+		capacity:    corev1.ResourceList{},
+		allocatable: corev1.ResourceList{},
+		labels:      map[string]map[string]int{},
+	}
+	for i := range nodes {
+		node := &nodes[i]
+		ctrl.addResources(summary.capacity, node.Status.Capacity)
+		if nodeReady(node) && !node.Spec.Unschedulable {
+			ctrl.addResources(summary.allocatable, node.Status.Allocatable)
+		}
+		for _, key := range ctrl.acceleratorLabels {
+			value, ok := node.Labels[key]
+			if !ok {
+				continue
+			}
+			if summary.labels[key] == nil {
+				summary.labels[key] = map[string]int{}
+			}
+			summary.labels[key][value]++
+		}
+	}
+	return summary
+}
+
+// addResources adds the summarized resources of list to total.
+func (ctrl *NodeController) addResources(total, list corev1.ResourceList) {
+	for name, quantity := range list {
+		if !ctrl.summarizedResource(name) {
+			continue
+		}
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+// summarizedResource returns whether the resource is summarized, that is cpu, memory, pods or an extended
+// resource in the allowlist.
+func (ctrl *NodeController) summarizedResource(name corev1.ResourceName) bool {
+	switch name {
+	case corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourcePods:
+		return true
+	}
+	for _, allowed := range ctrl.extendedResources {
+		if prefix := strings.TrimSuffix(allowed, "*"); prefix != allowed {
+			if strings.HasPrefix(string(name), prefix) {
+				return true
+			}
+		} else if string(name) == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// encodeResourceList encodes the resources as a JSON object of their canonical quantities.
+func encodeResourceList(list corev1.ResourceList) (string, error) {
+	quantities := make(map[corev1.ResourceName]string, len(list))
+	for name, quantity := range list {
+		quantities[name] = quantity.String()
+	}
+	data, err := json.Marshal(quantities)
+	return string(data), err
+}
@@ -0,0 +1,430 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pediacluster
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	clusterapi "github.com/clusterpedia-io/api/cluster/v1alpha2"
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	clusterinformers "github.com/karmada-io/karmada/pkg/generated/informers/externalversions/cluster/v1alpha1"
+	clusterlisters "github.com/karmada-io/karmada/pkg/generated/listers/cluster/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/prometheus/ratelimiter"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/constants"
+	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
+	installlisters "github.com/carlory/firefly/pkg/generated/listers/install/v1alpha1"
+	shardingutil "github.com/carlory/firefly/pkg/util/sharding"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
+)
+
+const (
+	// maxRetries is the number of times a cluster will be retried before it is dropped out of the queue.
+	// With the current rate-limiter in use (5ms*2^(maxRetries-1)) the following numbers represent the
+	// sequence of delays between successive queuings of a cluster.
+	//
+	// 5ms, 10ms, 20ms, 40ms, 80ms, 160ms, 320ms, 640ms, 1.3s, 2.6s, 5.1s, 10.2s, 20.4s, 41s, 82s
+	maxRetries = 15
+
+	// ManagedByLabel is the label set on the PediaClusters registered by the pediacluster controller.
+	ManagedByLabel = "pediacluster.karmada.install.firefly.io/managed-by"
+	managedByValue = "firefly-karmada-manager"
+)
+
+var pediaClusterGVR = schema.GroupVersionResource{Group: "cluster.clusterpedia.io", Version: "v1alpha2", Resource: "pediaclusters"}
+
+// +firefly:rbac:cluster=karmada,groups=cluster.karmada.io,resources=clusters,verbs=get;list;watch
+// +firefly:rbac:cluster=karmada,groups="",resources=secrets,verbs=get;list;watch
+// +firefly:rbac:cluster=karmada,groups=cluster.clusterpedia.io,resources=pediaclusters,verbs=get;create;update;delete
+// +firefly:rbac:cluster=host,groups=install.firefly.io,resources=clusterpedias,verbs=get;list;watch
+
+// NewPediaClusterController returns a new *Controller.
+func NewPediaClusterController(
+	karmadaKubeClient clientset.Interface,
+	karmadaDynamicClient dynamic.Interface,
+	clusterInformer clusterinformers.ClusterInformer,
+	secretInformer coreinformers.SecretInformer,
+	karmadaNamespace string,
+	karmadaName string,
+	fireflyClusterpediaInformer installinformers.ClusterpediaInformer,
+) (*PediaClusterController, error) {
+	if karmadaKubeClient != nil && karmadaKubeClient.CoreV1().RESTClient().GetRateLimiter() != nil {
+		ratelimiter.RegisterMetricAndTrackRateLimiterUsage("pediacluster_controller", karmadaKubeClient.CoreV1().RESTClient().GetRateLimiter())
+	}
+
+	ctrl := &PediaClusterController{
+		karmadaKubeClient:         karmadaKubeClient,
+		karmadaDynamicClient:      karmadaDynamicClient,
+		clustersLister:            clusterInformer.Lister(),
+		clustersSynced:            clusterInformer.Informer().HasSynced,
+		secretsLister:             secretInformer.Lister(),
+		secretsSynced:             secretInformer.Informer().HasSynced,
+		karmadaNamespace:          karmadaNamespace,
+		karmadaName:               karmadaName,
+		fireflyClusterpediaLister: fireflyClusterpediaInformer.Lister(),
+		fireflyClusterpediaSynced: fireflyClusterpediaInformer.Informer().HasSynced,
+		queue:                     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "pediacluster"),
+		workerLoopPeriod:          time.Second,
+	}
+
+	clusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.addCluster,
+		UpdateFunc: ctrl.updateCluster,
+		DeleteFunc: ctrl.deleteCluster,
+	})
+
+	// the token or the CA of a cluster may be rotated without any change of the cluster.
+	secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.syncSecret,
+		UpdateFunc: func(old, cur interface{}) { ctrl.syncSecret(cur) },
+		DeleteFunc: ctrl.syncSecret,
+	})
+
+	fireflyClusterpediaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.syncClusterpedia,
+		UpdateFunc: func(old, cur interface{}) { ctrl.syncClusterpedia(cur) },
+		DeleteFunc: ctrl.syncClusterpedia,
+	})
+
+	return ctrl, nil
+}
+
+// PediaClusterController registers the member clusters of the karmada into the clusterpedia
+// whose control plane is the karmada, so that they're searchable without manual steps.
+type PediaClusterController struct {
+	karmadaKubeClient    clientset.Interface
+	karmadaDynamicClient dynamic.Interface
+
+	karmadaNamespace          string
+	karmadaName               string
+	fireflyClusterpediaLister installlisters.ClusterpediaLister
+	fireflyClusterpediaSynced cache.InformerSynced
+
+	clustersLister clusterlisters.ClusterLister
+	clustersSynced cache.InformerSynced
+
+	secretsLister corelisters.SecretLister
+	secretsSynced cache.InformerSynced
+
+	// Cluster that need to be updated. A channel is inappropriate here,
+	// because it allows clusters to be serviced much more often than
+	// necessary.
+	queue workqueue.RateLimitingInterface
+
+	// workerLoopPeriod is the time between worker runs. The workers process the queue of cluster changes.
+	workerLoopPeriod time.Duration
+}
+
+// Run will not return until stopCh is closed. workers determines how many
+// cluster will be handled in parallel.
+func (ctrl *PediaClusterController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer ctrl.queue.ShutDown()
+
+	klog.Infof("Starting pediacluster controller")
+	defer klog.Infof("Shutting down pediacluster controller")
+
+	if !cache.WaitForNamedCacheSync("pediacluster", ctx.Done(), ctrl.clustersSynced, ctrl.secretsSynced, ctrl.fireflyClusterpediaSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, ctrl.worker, ctrl.workerLoopPeriod)
+	}
+	<-ctx.Done()
+}
+
+// worker runs a worker thread that just dequeues items, processes them, and
+// marks them done. You may run as many of these in parallel as you wish; the
+// workqueue guarantees that they will not end up processing the same cluster
+// at the same time.
+func (ctrl *PediaClusterController) worker(ctx context.Context) {
+	for ctrl.processNextWorkItem(ctx) {
+	}
+}
+
+func (ctrl *PediaClusterController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := ctrl.queue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.queue.Done(key)
+
+	ctx, span := tracingutil.StartReconcile(ctx, "pediacluster", key.(string))
+	err := ctrl.syncPediaCluster(ctx, key.(string))
+	tracingutil.EndReconcile(span, err)
+	ctrl.handleErr(err, key)
+
+	return true
+}
+
+func (ctrl *PediaClusterController) addCluster(obj interface{}) {
+	cluster := obj.(*clusterv1alpha1.Cluster)
+	klog.V(4).InfoS("Adding cluster", "cluster", klog.KObj(cluster))
+	ctrl.enqueue(cluster.Name)
+}
+
+func (ctrl *PediaClusterController) updateCluster(old, cur interface{}) {
+	oldCluster := old.(*clusterv1alpha1.Cluster)
+	curCluster := cur.(*clusterv1alpha1.Cluster)
+	klog.V(4).InfoS("Updating cluster", "cluster", klog.KObj(oldCluster))
+	ctrl.enqueue(curCluster.Name)
+}
+
+func (ctrl *PediaClusterController) deleteCluster(obj interface{}) {
+	cluster, ok := obj.(*clusterv1alpha1.Cluster)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("couldn't get object from tombstone %#v", obj))
+			return
+		}
+		cluster, ok = tombstone.Obj.(*clusterv1alpha1.Cluster)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("tombstone contained object that is not a Cluster %#v", obj))
+			return
+		}
+	}
+	klog.V(4).InfoS("Deleting cluster", "cluster", klog.KObj(cluster))
+	ctrl.enqueue(cluster.Name)
+}
+
+// syncClusterpedia requeues all the clusters when a clusterpedia is changed, because the
+// clusterpedia decides whether and how the clusters are registered.
+func (ctrl *PediaClusterController) syncClusterpedia(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	clusterpedia, ok := obj.(*installv1alpha1.Clusterpedia)
+	if !ok {
+		return
+	}
+	klog.V(4).InfoS("Sync clusterpedia", "clusterpedia", klog.KObj(clusterpedia))
+
+	clusters, err := ctrl.clustersLister.List(labels.Everything())
+	if err != nil {
+		klog.Warningf("Failed to list clusters: %v", err)
+	}
+	for _, cluster := range clusters {
+		ctrl.enqueue(cluster.Name)
+	}
+}
+
+// syncSecret requeues the clusters which refer to the secret, so that the rotated token or CA of a
+// cluster is picked up by its PediaCluster.
+func (ctrl *PediaClusterController) syncSecret(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+
+	clusters, err := ctrl.clustersLister.List(labels.Everything())
+	if err != nil {
+		klog.Warningf("Failed to list clusters: %v", err)
+	}
+	for _, cluster := range clusters {
+		ref := cluster.Spec.SecretRef
+		if ref != nil && ref.Namespace == secret.Namespace && ref.Name == secret.Name {
+			klog.V(4).InfoS("Sync secret of cluster", "secret", klog.KObj(secret), "cluster", klog.KObj(cluster))
+			ctrl.enqueue(cluster.Name)
+		}
+	}
+}
+
+func (ctrl *PediaClusterController) enqueue(name string) {
+	ctrl.queue.Add(name)
+}
+
+func (ctrl *PediaClusterController) handleErr(err error, key interface{}) {
+	if err == nil {
+		ctrl.queue.Forget(key)
+		return
+	}
+
+	if ctrl.queue.NumRequeues(key) < maxRetries {
+		klog.V(2).InfoS("Error syncing pediacluster, retrying", "cluster", klog.KRef("", key.(string)), "err", err)
+		ctrl.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	klog.V(2).InfoS("Dropping cluster out of the queue", "cluster", klog.KRef("", key.(string)), "err", err)
+	ctrl.queue.Forget(key)
+}
+
+func (ctrl *PediaClusterController) syncPediaCluster(ctx context.Context, key string) error {
+	startTime := time.Now()
+	klog.V(4).InfoS("Started syncing pediacluster", "cluster", klog.KRef("", key), "startTime", startTime)
+	defer func() {
+		klog.V(4).InfoS("Finished syncing pediacluster", "cluster", klog.KRef("", key), "duration", time.Since(startTime))
+	}()
+
+	cluster, err := ctrl.clustersLister.Get(key)
+	if errors.IsNotFound(err) {
+		klog.V(2).InfoS("Cluster has been deleted", "cluster", klog.KRef("", key))
+		return ctrl.RemovePediaCluster(ctx, key)
+	}
+	if err != nil {
+		return err
+	}
+
+	clusterpedia, err := ctrl.getClusterpedia()
+	if err != nil {
+		return err
+	}
+	if clusterpedia != nil && clusterpedia.Spec.Paused {
+		klog.V(2).InfoS("Clusterpedia is paused, skip syncing pediacluster", "clusterpedia", klog.KObj(clusterpedia), "cluster", klog.KObj(cluster))
+		return nil
+	}
+	if clusterpedia == nil || !cluster.DeletionTimestamp.IsZero() {
+		return ctrl.RemovePediaCluster(ctx, key)
+	}
+
+	if cluster.Spec.APIEndpoint == "" || cluster.Spec.SecretRef == nil {
+		klog.V(2).InfoS("Cluster has no api endpoint or secret, skip registering it", "cluster", klog.KObj(cluster))
+		return ctrl.RemovePediaCluster(ctx, key)
+	}
+
+	klog.InfoS("Syncing pediacluster", "cluster", cluster.Name)
+	return ctrl.EnsurePediaCluster(ctx, clusterpedia, cluster)
+}
+
+// getClusterpedia returns the clusterpedia which uses the karmada as its control plane and
+// wants its member clusters to be registered by the controller. It returns nil if there is no such one.
+func (ctrl *PediaClusterController) getClusterpedia() (*installv1alpha1.Clusterpedia, error) {
+	clusterpedias, err := ctrl.fireflyClusterpediaLister.Clusterpedias(ctrl.karmadaNamespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, clusterpedia := range clusterpedias {
+		provider := clusterpedia.Spec.ControlplaneProvider
+		if provider == nil || provider.Karmada == nil || provider.Karmada.Name != ctrl.karmadaName {
+			continue
+		}
+		if provider.ClusterRegistration != installv1alpha1.ClusterRegistrationController || !clusterpedia.DeletionTimestamp.IsZero() {
+			continue
+		}
+		return clusterpedia, nil
+	}
+	return nil, nil
+}
+
+func (ctrl *PediaClusterController) EnsurePediaCluster(ctx context.Context, clusterpedia *installv1alpha1.Clusterpedia, cluster *clusterv1alpha1.Cluster) error {
+	secret, err := ctrl.secretsLister.Secrets(cluster.Spec.SecretRef.Namespace).Get(cluster.Spec.SecretRef.Name)
+	if err != nil {
+		return err
+	}
+
+	provider := clusterpedia.Spec.ControlplaneProvider
+	syncResources := provider.SyncResources
+	if syncResources == nil {
+		syncResources = []clusterapi.ClusterGroupResources{}
+	}
+	pediaClusterLabels := map[string]string{ManagedByLabel: managedByValue}
+	if sharding := clusterpedia.Spec.ClusterpediaSynchroManager.Sharding; sharding != nil {
+		pediaClusterLabels[constants.ClusterSynchroShardLabel] = strconv.Itoa(int(shardingutil.Shard(sharding, cluster.Name)))
+	}
+	pediaCluster := &clusterapi.PediaCluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterapi.SchemeGroupVersion.String(),
+			Kind:       "PediaCluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   cluster.Name,
+			Labels: pediaClusterLabels,
+			// the PediaCluster is garbage collected with the cluster even if the controller misses the deletion.
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cluster, clusterv1alpha1.SchemeGroupVersion.WithKind("Cluster")),
+			},
+		},
+		Spec: clusterapi.ClusterSpec{
+			APIServer:              cluster.Spec.APIEndpoint,
+			TokenData:              secret.Data[clusterv1alpha1.SecretTokenKey],
+			SyncResources:          syncResources,
+			SyncAllCustomResources: provider.SyncAllCustomResources,
+		},
+	}
+	if !cluster.Spec.InsecureSkipTLSVerification {
+		pediaCluster.Spec.CAData = secret.Data[clusterv1alpha1.SecretCADataKey]
+	}
+
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pediaCluster)
+	if err != nil {
+		return err
+	}
+	obj := &unstructured.Unstructured{Object: data}
+
+	client := ctrl.karmadaDynamicClient.Resource(pediaClusterGVR)
+	old, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = client.Create(ctx, obj, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if old.GetLabels()[ManagedByLabel] != managedByValue {
+		klog.V(2).InfoS("PediaCluster is not managed by firefly, skip updating it", "cluster", cluster.Name)
+		return nil
+	}
+	obj.SetResourceVersion(old.GetResourceVersion())
+	_, err = client.Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
+
+// RemovePediaCluster removes the PediaCluster of the cluster if it's registered by the controller.
+func (ctrl *PediaClusterController) RemovePediaCluster(ctx context.Context, name string) error {
+	client := ctrl.karmadaDynamicClient.Resource(pediaClusterGVR)
+	old, err := client.Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if old.GetLabels()[ManagedByLabel] != managedByValue {
+		return nil
+	}
+	err = client.Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
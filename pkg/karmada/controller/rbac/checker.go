@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// permissionCheckInterval is the minimum interval between the SelfSubjectAccessReviews of a
+// controller, the health checks in between are answered by the result of the last review.
+const permissionCheckInterval = time.Minute
+
+// PermissionChecker is a health checker which reports the permissions a controller declares by its
+// +firefly:rbac markers but isn't granted, as reviewed by SelfSubjectAccessReviews of its clients.
+type PermissionChecker struct {
+	controller string
+	clients    map[Cluster]clientset.Interface
+	rules      map[Cluster][]rbacv1.PolicyRule
+
+	lock        sync.Mutex
+	lastChecked time.Time
+	missing     []string
+}
+
+// NewPermissionChecker creates a PermissionChecker of the controller. The rules of a cluster aren't
+// reviewed if its client is nil. It returns nil if the controller declares no rules.
+func NewPermissionChecker(controller string, karmadaClient, hostClient clientset.Interface) (*PermissionChecker, error) {
+	checker := &PermissionChecker{
+		controller: controller,
+		clients:    map[Cluster]clientset.Interface{},
+		rules:      map[Cluster][]rbacv1.PolicyRule{},
+	}
+	for cluster, client := range map[Cluster]clientset.Interface{ClusterKarmada: karmadaClient, ClusterHost: hostClient} {
+		if client == nil {
+			continue
+		}
+		rules, err := Rules(cluster, controller)
+		if err != nil {
+			return nil, err
+		}
+		if len(rules) == 0 {
+			continue
+		}
+		checker.clients[cluster] = client
+		checker.rules[cluster] = rules
+	}
+	if len(checker.rules) == 0 {
+		return nil, nil
+	}
+	return checker, nil
+}
+
+// Name returns the name of the health check.
+func (c *PermissionChecker) Name() string {
+	return c.controller + "-permissions"
+}
+
+// Check returns an error listing the missing permissions of the controller.
+func (c *PermissionChecker) Check(req *http.Request) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if time.Since(c.lastChecked) >= permissionCheckInterval {
+		missing, err := c.Review(req.Context())
+		if err != nil {
+			// the apiserver may be unavailable for a while, which is reported by other checks.
+			klog.ErrorS(err, "Failed to review the permissions of the controller", "controller", c.controller)
+		} else {
+			if len(missing) > 0 && !sets.NewString(missing...).Equal(sets.NewString(c.missing...)) {
+				klog.InfoS("The controller is missing permissions", "controller", c.controller, "permissions", missing)
+			}
+			c.missing = missing
+			c.lastChecked = time.Now()
+		}
+	}
+	if len(c.missing) > 0 {
+		return fmt.Errorf("missing permissions: %s", strings.Join(c.missing, ", "))
+	}
+	return nil
+}
+
+// Review returns the permissions which aren't allowed, in the form of <cluster>:<verb> <resource>.<group>.
+func (c *PermissionChecker) Review(ctx context.Context) ([]string, error) {
+	var missing []string
+	for _, cluster := range []Cluster{ClusterKarmada, ClusterHost} {
+		client, ok := c.clients[cluster]
+		if !ok {
+			continue
+		}
+		for _, rule := range c.rules[cluster] {
+			for _, group := range rule.APIGroups {
+				for _, resource := range rule.Resources {
+					resource, subresource, _ := strings.Cut(resource, "/")
+					for _, verb := range rule.Verbs {
+						review := &authorizationv1.SelfSubjectAccessReview{
+							Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+								ResourceAttributes: &authorizationv1.ResourceAttributes{
+									Group:       group,
+									Resource:    resource,
+									Subresource: subresource,
+									Verb:        verb,
+								},
+							},
+						}
+						result, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+						if err != nil {
+							return nil, err
+						}
+						if !result.Status.Allowed {
+							missing = append(missing, fmt.Sprintf("%s:%s %s", cluster, verb, qualifiedResource(group, resource, subresource)))
+						}
+					}
+				}
+			}
+		}
+	}
+	return missing, nil
+}
+
+func qualifiedResource(group, resource, subresource string) string {
+	if subresource != "" {
+		resource = resource + "/" + subresource
+	}
+	if group == "" {
+		return resource
+	}
+	return resource + "." + group
+}
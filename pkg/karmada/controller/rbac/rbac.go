@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rbac holds the ClusterRoles of the controllers of the firefly-karmada-manager, which are
+// generated from the +firefly:rbac markers in the controller packages by hack/rbac-gen.
+package rbac
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+//go:generate go run github.com/carlory/firefly/hack/rbac-gen --root .. --output-dir .
+
+// Cluster is the cluster which a controller is granted the rules on.
+type Cluster string
+
+const (
+	// ClusterKarmada is the karmada apiserver.
+	ClusterKarmada Cluster = "karmada"
+	// ClusterHost is the host cluster which the karmada is installed into.
+	ClusterHost Cluster = "host"
+)
+
+const (
+	// ControllerLabel is the label of the ClusterRoles whose value is the name of the controller.
+	ControllerLabel = "rbac.firefly.io/controller"
+	// ClusterLabel is the label of the ClusterRoles whose value is the cluster they're created in.
+	ClusterLabel = "rbac.firefly.io/cluster"
+	// ClusterRoleNamePrefix is the prefix of the names of the ClusterRoles, followed by the name of the controller.
+	ClusterRoleNamePrefix = "system:firefly-karmada-manager:"
+)
+
+// manifests holds karmada.yaml and host.yaml generated by hack/rbac-gen.
+//
+//go:embed *.yaml
+var manifests embed.FS
+
+// ClusterRoles returns the ClusterRoles of the controllers to be created in the given cluster.
+func ClusterRoles(cluster Cluster) ([]*rbacv1.ClusterRole, error) {
+	data, err := manifests.ReadFile(string(cluster) + ".yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	var roles []*rbacv1.ClusterRole
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		role := &rbacv1.ClusterRole{}
+		err := decoder.Decode(role)
+		if err == io.EOF {
+			return roles, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the ClusterRoles of the %s cluster: %v", cluster, err)
+		}
+		if role.Name == "" {
+			continue
+		}
+		roles = append(roles, role)
+	}
+}
+
+// Rules returns the rules the controller needs in the given cluster.
+func Rules(cluster Cluster, controller string) ([]rbacv1.PolicyRule, error) {
+	roles, err := ClusterRoles(cluster)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if role.Labels[ControllerLabel] == controller {
+			return role.Rules, nil
+		}
+	}
+	return nil, nil
+}
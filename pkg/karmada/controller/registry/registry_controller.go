@@ -0,0 +1,334 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	karmadaversioned "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	policyinformers "github.com/karmada-io/karmada/pkg/generated/informers/externalversions/policy/v1alpha1"
+	policylisters "github.com/karmada-io/karmada/pkg/generated/listers/policy/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	installinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions/install/v1alpha1"
+	installlisters "github.com/carlory/firefly/pkg/generated/listers/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	tracingutil "github.com/carlory/firefly/pkg/util/tracing"
+)
+
+const (
+	// maxRetries is the number of times the registry mirrors will be retried before they are dropped out of the queue.
+	// With the current rate-limiter in use (5ms*2^(maxRetries-1)) the following numbers represent the
+	// sequence of delays between successive queuings of the registry mirrors.
+	//
+	// 5ms, 10ms, 20ms, 40ms, 80ms, 160ms, 320ms, 640ms, 1.3s, 2.6s, 5.1s, 10.2s, 20.4s, 41s, 82s
+	maxRetries = 15
+
+	// PolicyName is the name of the ClusterOverridePolicy generated from the registry mirrors of the karmada.
+	PolicyName = "firefly-registry-mirrors"
+
+	// ManagedByLabel is the label set on the ClusterOverridePolicy generated by the registry controller.
+	ManagedByLabel = "registry.karmada.install.firefly.io/managed-by"
+	managedByValue = "firefly-karmada-manager"
+
+	// regionField is the field of the member clusters which the override rules select them by.
+	regionField = "region"
+)
+
+// workloadKinds are the kinds of the workloads whose images are overridden.
+var workloadKinds = []metav1.TypeMeta{
+	{APIVersion: "apps/v1", Kind: "Deployment"},
+	{APIVersion: "apps/v1", Kind: "StatefulSet"},
+	{APIVersion: "apps/v1", Kind: "DaemonSet"},
+	{APIVersion: "apps/v1", Kind: "ReplicaSet"},
+	{APIVersion: "batch/v1", Kind: "Job"},
+	{APIVersion: "batch/v1", Kind: "CronJob"},
+	{APIVersion: "v1", Kind: "Pod"},
+}
+
+// +firefly:rbac:cluster=karmada,groups=policy.karmada.io,resources=clusteroverridepolicies,verbs=get;list;watch;create;update;delete
+// +firefly:rbac:cluster=host,groups=install.firefly.io,resources=karmadas,verbs=get;list;watch
+
+// NewRegistryController returns a new *Controller.
+func NewRegistryController(
+	karmadaClient karmadaversioned.Interface,
+	overridePolicyInformer policyinformers.ClusterOverridePolicyInformer,
+	karmadaNamespace string,
+	karmadaName string,
+	fireflyKarmadaInformer installinformers.KarmadaInformer,
+) (*RegistryController, error) {
+	ctrl := &RegistryController{
+		karmadaClient:          karmadaClient,
+		overridePoliciesLister: overridePolicyInformer.Lister(),
+		overridePoliciesSynced: overridePolicyInformer.Informer().HasSynced,
+		karmadaNamespace:       karmadaNamespace,
+		karmadaName:            karmadaName,
+		fireflyKarmadaLister:   fireflyKarmadaInformer.Lister(),
+		fireflyKarmadaSynced:   fireflyKarmadaInformer.Informer().HasSynced,
+		queue:                  workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "registry"),
+		workerLoopPeriod:       time.Second,
+	}
+
+	// the generated policy is restored if it's changed or deleted by others.
+	overridePolicyInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			policy, ok := obj.(*policyv1alpha1.ClusterOverridePolicy)
+			return ok && policy.Name == PolicyName
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueue() },
+			UpdateFunc: func(old, cur interface{}) { ctrl.enqueue() },
+			DeleteFunc: func(obj interface{}) { ctrl.enqueue() },
+		},
+	})
+
+	fireflyKarmadaInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			karmada, ok := obj.(*installv1alpha1.Karmada)
+			return ok && karmada.Namespace == karmadaNamespace && karmada.Name == karmadaName
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueue() },
+			UpdateFunc: func(old, cur interface{}) { ctrl.enqueue() },
+			DeleteFunc: func(obj interface{}) { ctrl.enqueue() },
+		},
+	})
+
+	return ctrl, nil
+}
+
+// RegistryController generates the ClusterOverridePolicy which makes the member clusters of a region
+// pull the images of the workloads from the mirror registry of the region, see spec.registryMirrors
+// of the karmada.
+type RegistryController struct {
+	karmadaClient karmadaversioned.Interface
+
+	overridePoliciesLister policylisters.ClusterOverridePolicyLister
+	overridePoliciesSynced cache.InformerSynced
+
+	karmadaNamespace     string
+	karmadaName          string
+	fireflyKarmadaLister installlisters.KarmadaLister
+	fireflyKarmadaSynced cache.InformerSynced
+
+	// The registry mirrors that need to be synced. There's only one key, the name of the karmada,
+	// so that the changes of the karmada and the policy are coalesced.
+	queue workqueue.RateLimitingInterface
+
+	// workerLoopPeriod is the time between worker runs. The workers process the queue of registry mirror changes.
+	workerLoopPeriod time.Duration
+}
+
+// Run will not return until stopCh is closed. A single worker is started, since there's only one
+// policy to be synced.
+func (ctrl *RegistryController) Run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+	defer ctrl.queue.ShutDown()
+
+	klog.Infof("Starting registry controller")
+	defer klog.Infof("Shutting down registry controller")
+
+	if !cache.WaitForNamedCacheSync("registry", ctx.Done(), ctrl.overridePoliciesSynced, ctrl.fireflyKarmadaSynced) {
+		return
+	}
+
+	go wait.UntilWithContext(ctx, ctrl.worker, ctrl.workerLoopPeriod)
+	<-ctx.Done()
+}
+
+// worker runs a worker thread that just dequeues items, processes them, and
+// marks them done.
+func (ctrl *RegistryController) worker(ctx context.Context) {
+	for ctrl.processNextWorkItem(ctx) {
+	}
+}
+
+func (ctrl *RegistryController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := ctrl.queue.Get()
+	if quit {
+		return false
+	}
+	defer ctrl.queue.Done(key)
+
+	ctx, span := tracingutil.StartReconcile(ctx, "registry", key.(string))
+	err := ctrl.syncRegistryMirrors(ctx)
+	tracingutil.EndReconcile(span, err)
+	ctrl.handleErr(err, key)
+
+	return true
+}
+
+func (ctrl *RegistryController) enqueue() {
+	ctrl.queue.Add(ctrl.karmadaName)
+}
+
+func (ctrl *RegistryController) handleErr(err error, key interface{}) {
+	if err == nil {
+		ctrl.queue.Forget(key)
+		return
+	}
+
+	if ctrl.queue.NumRequeues(key) < maxRetries {
+		klog.V(2).InfoS("Error syncing registry mirrors, retrying", "karmada", klog.KRef(ctrl.karmadaNamespace, ctrl.karmadaName), "err", err)
+		ctrl.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	klog.V(2).InfoS("Dropping registry mirrors out of the queue", "karmada", klog.KRef(ctrl.karmadaNamespace, ctrl.karmadaName), "err", err)
+	ctrl.queue.Forget(key)
+}
+
+func (ctrl *RegistryController) syncRegistryMirrors(ctx context.Context) error {
+	startTime := time.Now()
+	klog.V(4).InfoS("Started syncing registry mirrors", "karmada", klog.KRef(ctrl.karmadaNamespace, ctrl.karmadaName), "startTime", startTime)
+	defer func() {
+		klog.V(4).InfoS("Finished syncing registry mirrors", "karmada", klog.KRef(ctrl.karmadaNamespace, ctrl.karmadaName), "duration", time.Since(startTime))
+	}()
+
+	karmada, err := ctrl.fireflyKarmadaLister.Karmadas(ctrl.karmadaNamespace).Get(ctrl.karmadaName)
+	if errors.IsNotFound(err) {
+		// the karmada-apiserver goes away with the karmada.
+		klog.V(2).InfoS("Karmada has been deleted", "karmada", klog.KRef(ctrl.karmadaNamespace, ctrl.karmadaName))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if karmada.Spec.Paused {
+		klog.V(2).InfoS("Karmada is paused, skip syncing registry mirrors", "karmada", klog.KObj(karmada))
+		return nil
+	}
+	ctx = audit.WithTrigger(ctx, karmada)
+	ctx = dryrun.ForObject(ctx, karmada)
+
+	if len(karmada.Spec.RegistryMirrors) == 0 || !karmada.DeletionTimestamp.IsZero() {
+		return ctrl.RemoveOverridePolicy(ctx)
+	}
+	return ctrl.EnsureOverridePolicy(ctx, karmada)
+}
+
+// overrideSpec returns the spec of the ClusterOverridePolicy generated from the registry mirrors,
+// with a rule per region.
+func overrideSpec(mirrors []installv1alpha1.RegistryMirror) policyv1alpha1.OverrideSpec {
+	var spec policyv1alpha1.OverrideSpec
+	for _, kind := range workloadKinds {
+		spec.ResourceSelectors = append(spec.ResourceSelectors, policyv1alpha1.ResourceSelector{
+			APIVersion: kind.APIVersion,
+			Kind:       kind.Kind,
+		})
+	}
+	for _, mirror := range mirrors {
+		spec.OverrideRules = append(spec.OverrideRules, policyv1alpha1.RuleWithCluster{
+			TargetCluster: &policyv1alpha1.ClusterAffinity{
+				FieldSelector: &policyv1alpha1.FieldSelector{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{
+						Key:      regionField,
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   []string{mirror.Region},
+					}},
+				},
+			},
+			Overriders: policyv1alpha1.Overriders{
+				ImageOverrider: []policyv1alpha1.ImageOverrider{{
+					Component: policyv1alpha1.Registry,
+					Operator:  policyv1alpha1.OverriderOpReplace,
+					Value:     mirror.Registry,
+				}},
+			},
+		})
+	}
+	return spec
+}
+
+// EnsureOverridePolicy creates or updates the ClusterOverridePolicy of the registry mirrors of the karmada.
+// A policy with the same name which isn't generated by the controller is left as it is.
+func (ctrl *RegistryController) EnsureOverridePolicy(ctx context.Context, karmada *installv1alpha1.Karmada) error {
+	policy := &policyv1alpha1.ClusterOverridePolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   PolicyName,
+			Labels: map[string]string{ManagedByLabel: managedByValue},
+		},
+		Spec: overrideSpec(karmada.Spec.RegistryMirrors),
+	}
+
+	policies := ctrl.karmadaClient.PolicyV1alpha1().ClusterOverridePolicies()
+	existing, err := ctrl.overridePoliciesLister.Get(PolicyName)
+	if errors.IsNotFound(err) {
+		_, err = policies.Create(ctx, policy, metav1.CreateOptions{})
+		audit.RecordResult(ctx, audit.Create, policy, err)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if existing.Labels[ManagedByLabel] != managedByValue {
+		klog.V(2).InfoS("ClusterOverridePolicy is not managed by firefly, skip updating it", "policy", PolicyName)
+		return nil
+	}
+	if equality.Semantic.DeepEqual(existing.Spec, policy.Spec) {
+		return nil
+	}
+
+	updated := existing.DeepCopy()
+	updated.Spec = policy.Spec
+	result, err := policies.Update(ctx, updated, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update ClusterOverridePolicy %s: %v", PolicyName, err)
+	}
+	audit.RecordUpdate(ctx, existing, result)
+	return nil
+}
+
+// RemoveOverridePolicy removes the ClusterOverridePolicy of the registry mirrors if it's generated by the controller.
+func (ctrl *RegistryController) RemoveOverridePolicy(ctx context.Context) error {
+	existing, err := ctrl.overridePoliciesLister.Get(PolicyName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.Labels[ManagedByLabel] != managedByValue {
+		return nil
+	}
+	err = ctrl.karmadaClient.PolicyV1alpha1().ClusterOverridePolicies().Delete(ctx, PolicyName, metav1.DeleteOptions{})
+	audit.RecordDeletion(ctx, audit.ObjectReference{Kind: "ClusterOverridePolicy", Name: PolicyName}, err)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,621 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controllermanager builds the clients and the context the controllers of the
+// firefly-karmada-manager are started with, and starts them. Platforms which embed the controllers
+// of a karmada build them with NewClientBuilders, NewControllerContext and StartControllers instead
+// of importing the cmd packages, which are not meant to be imported.
+package controllermanager
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	karmadaversioned "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	karmadainformers "github.com/karmada-io/karmada/pkg/generated/informers/externalversions"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/apiserver/pkg/server/mux"
+	"k8s.io/client-go/discovery"
+	cacheddiscovery "k8s.io/client-go/discovery/cached"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/tracing"
+	genericcontrollermanager "k8s.io/controller-manager/app"
+	"k8s.io/controller-manager/controller"
+	controllerhealthz "k8s.io/controller-manager/pkg/healthz"
+	"k8s.io/controller-manager/pkg/informerfactory"
+	"k8s.io/klog/v2"
+
+	"github.com/carlory/firefly/pkg/clientbuilder"
+	fireflyversioned "github.com/carlory/firefly/pkg/generated/clientset/versioned"
+	fireflyinformers "github.com/carlory/firefly/pkg/generated/informers/externalversions"
+	fireflyctrlmgrconfig "github.com/carlory/firefly/pkg/karmada/controller/apis/config"
+	"github.com/carlory/firefly/pkg/karmada/controller/rbac"
+	karmadafireflyinformers "github.com/carlory/firefly/pkg/karmada/generated/informers/externalversions"
+	"github.com/carlory/firefly/pkg/karmada/membercluster"
+	"github.com/carlory/firefly/pkg/util/backoff"
+	"github.com/carlory/firefly/pkg/util/capabilities"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	"github.com/carlory/firefly/pkg/util/expectations"
+	informerutil "github.com/carlory/firefly/pkg/util/informer"
+	"github.com/carlory/firefly/pkg/util/resolver"
+	"github.com/carlory/firefly/pkg/util/ttlcache"
+)
+
+// ControllerStartJitter is the Jitter used when starting controller managers
+const ControllerStartJitter = 1.0
+
+// ControllersDisabledByDefault is the set of controllers which is disabled by default
+var ControllersDisabledByDefault = sets.NewString()
+
+// ControllerContext defines the context object for controller
+type ControllerContext struct {
+	// KarmadaClientBuilder will provide a client for this controller to use
+	KarmadaClientBuilder clientbuilder.KarmadaControllerClientBuilder
+
+	// MemberClusterClientBuilder will provide the clients of the member clusters for this controller to use,
+	// which reach the member clusters through the cluster proxy of the karmada instead of their kubeconfigs.
+	MemberClusterClientBuilder clientbuilder.MemberClusterClientBuilder
+
+	// FireflyClientBuilder will provide a client for this controller to use
+	FireflyClientBuilder clientbuilder.FireflyControllerClientBuilder
+
+	// KarmadaKubeInformerFactory gives access to dynamic informers for the controller.
+	KarmadaDynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory
+
+	// KarmadaKubeInformerFactory gives access to kubernetes informers for the controller.
+	KarmadaKubeInformerFactory informers.SharedInformerFactory
+
+	// KarmadaFireflyInformerFactory gives access to karmada's firelfy informers for the controller.
+	KarmadaFireflyInformerFactory karmadafireflyinformers.SharedInformerFactory
+
+	// KarmadaInformerFactory gives access to firefly informers for the controller.
+	KarmadaInformerFactory karmadainformers.SharedInformerFactory
+
+	// FireflyDynamicInformerFactory gives access to dynamic informers for the controller.
+	FireflyDynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory
+
+	// FireflyKubeInformerFactory gives access to firefly informers for the controller.
+	FireflyKubeInformerFactory informers.SharedInformerFactory
+
+	// FireflyInformerFactory gives access to firefly informers for the controller.
+	FireflyInformerFactory fireflyinformers.SharedInformerFactory
+
+	// FireflyKubeFilteredFactories gives access to kubernetes and metadata-only informers of the host cluster
+	// which are filtered by the options requested by the controller, e.g. a namespace or a label selector.
+	FireflyKubeFilteredFactories *informerutil.FilteredFactories
+
+	// EstimatorNamespace is the namespace of scheduler-estimator
+	EstimatorNamespace string
+	// KarmadaName is the name of a firefly karmada object
+	KarmadaName string
+
+	// KarmadaMetadataInformerFactory gives access to metadata-only informers of the karmada apiserver,
+	// it's used by the controllers which only need the metadata of high-cardinality resources, e.g. nodes.
+	// Note that ObjectOrMetadataInformerFactory returns the typed informers for the built-in resources.
+	KarmadaMetadataInformerFactory metadatainformer.SharedInformerFactory
+
+	// ObjectOrMetadataInformerFactory gives access to informers for typed resources
+	// and dynamic resources by their metadata. All generic controllers currently use
+	// object metadata - if a future controller needs access to the full object this
+	// would become GenericInformerFactory and take a dynamic client.
+	ObjectOrMetadataInformerFactory informerfactory.InformerFactory
+
+	// ComponentConfig provides access to init options for a given controller
+	ComponentConfig fireflyctrlmgrconfig.FireflyKarmadaManagerConfiguration
+
+	// DeferredDiscoveryRESTMapper is a RESTMapper that will defer
+	// initialization of the RESTMapper until the first mapping is
+	// requested.
+	RESTMapper *restmapper.DeferredDiscoveryRESTMapper
+
+	// AvailableResources is a map listing currently available resources
+	AvailableResources map[schema.GroupVersionResource]bool
+
+	// HostClusterAvailableResources is a map listing currently available resources on the host cluster.
+	HostClusterAvailableResources map[schema.GroupVersionResource]bool
+
+	// HostClusterCapabilities tells the optional capabilities of the host cluster, e.g. the Prometheus Operator,
+	// from HostClusterAvailableResources, so that the controllers vary what they create by them.
+	HostClusterCapabilities *capabilities.Capabilities
+
+	// InformersStarted is closed after all of the controllers have been initialized and are running.  After this point it is safe,
+	// for an individual controller to start the shared informers. Before it is closed, they should not.
+	InformersStarted chan struct{}
+
+	// ResyncPeriod generates a duration each time it is invoked; this is so that
+	// multiple controllers don't get into lock-step and all hammer the apiserver
+	// with list requests simultaneously.
+	ResyncPeriod func() time.Duration
+
+	// Expectations gives access to the create/delete expectations of the controllers, keyed by the
+	// controller name, so that they don't act on stale informer caches.
+	Expectations *expectations.Registry
+
+	// TTLCaches gives access to the expiring caches shared by the controllers, keyed by the cache name.
+	TTLCaches *ttlcache.Registry
+
+	// ObjectResolver resolves object references and owner references of the objects in the karmada apiserver,
+	// the resolved objects are cached for a short while to avoid redundant GETs during reconcile storms.
+	ObjectResolver *resolver.Resolver
+
+	// MemberClusterCache holds the clients and the informers of the member clusters shared by the controllers,
+	// which are evicted when the clusters are unjoined, their credentials are rotated or they're unhealthy.
+	MemberClusterCache *membercluster.Cache
+}
+
+// IsControllerEnabled checks if the context's controllers enabled or not
+func (c ControllerContext) IsControllerEnabled(name string) bool {
+	return genericcontrollermanager.IsControllerEnabled(name, ControllersDisabledByDefault, c.ComponentConfig.Generic.Controllers)
+}
+
+// StartInformers starts the shared informer factories and closes InformersStarted, it's called once
+// all of the controllers have been started. The MemberClusterCache is run separately.
+func (c ControllerContext) StartInformers(stopCh <-chan struct{}) {
+	c.startFactories(stopCh)
+	close(c.InformersStarted)
+}
+
+// startFactories starts the informers requested from the shared informer factories so far. The
+// informers already started are left alone, so that it's called again for the informers requested
+// later.
+func (c ControllerContext) startFactories(stopCh <-chan struct{}) {
+	c.KarmadaDynamicInformerFactory.Start(stopCh)
+	c.KarmadaKubeInformerFactory.Start(stopCh)
+	c.KarmadaInformerFactory.Start(stopCh)
+	c.KarmadaFireflyInformerFactory.Start(stopCh)
+	c.FireflyDynamicInformerFactory.Start(stopCh)
+	c.FireflyKubeInformerFactory.Start(stopCh)
+	c.FireflyInformerFactory.Start(stopCh)
+	c.FireflyKubeFilteredFactories.Start(stopCh)
+	c.ObjectOrMetadataInformerFactory.Start(stopCh)
+}
+
+// WaitForCacheSync waits for the caches of all the informers started by the shared informer
+// factories to be synced, it returns false if stopCh is closed before that.
+func (c ControllerContext) WaitForCacheSync(stopCh <-chan struct{}) bool {
+	return informerutil.AllResourcesSynced(c.KarmadaDynamicInformerFactory.WaitForCacheSync(stopCh)) &&
+		informerutil.AllSynced(c.KarmadaKubeInformerFactory.WaitForCacheSync(stopCh)) &&
+		informerutil.AllSynced(c.KarmadaInformerFactory.WaitForCacheSync(stopCh)) &&
+		informerutil.AllSynced(c.KarmadaFireflyInformerFactory.WaitForCacheSync(stopCh)) &&
+		informerutil.AllResourcesSynced(c.KarmadaMetadataInformerFactory.WaitForCacheSync(stopCh)) &&
+		informerutil.AllResourcesSynced(c.FireflyDynamicInformerFactory.WaitForCacheSync(stopCh)) &&
+		informerutil.AllSynced(c.FireflyKubeInformerFactory.WaitForCacheSync(stopCh)) &&
+		informerutil.AllSynced(c.FireflyInformerFactory.WaitForCacheSync(stopCh)) &&
+		c.FireflyKubeFilteredFactories.WaitForCacheSync(stopCh)
+}
+
+// InitFunc is used to launch a particular controller. It returns a controller
+// that can optionally implement other interfaces so that the controller manager
+// can support the requested features.
+// The returned controller may be nil, which will be considered an anonymous controller
+// that requests no additional features from the controller manager.
+// Any error returned will cause the controller process to `Fatal`
+// The bool indicates whether the controller was enabled.
+type InitFunc func(ctx context.Context, controllerCtx ControllerContext) (controller controller.Interface, enabled bool, err error)
+
+// ResyncPeriod returns a function which generates a duration between minResyncPeriod and twice of it
+// each time it is invoked; this is so that multiple controllers don't get into lock-step and all
+// hammer the apiserver with list requests simultaneously.
+func ResyncPeriod(minResyncPeriod time.Duration) func() time.Duration {
+	return func() time.Duration {
+		factor := rand.Float64() + 1
+		return time.Duration(float64(minResyncPeriod.Nanoseconds()) * factor)
+	}
+}
+
+// ClientBuilderOptions are the options of the client builders built by NewClientBuilders.
+type ClientBuilderOptions struct {
+	// ComponentConfig tells whether the controllers use the credentials of their own service accounts
+	// and how their requests are shaped.
+	ComponentConfig fireflyctrlmgrconfig.FireflyKarmadaManagerConfiguration
+
+	// KarmadaKubeconfig and FireflyKubeconfig are the rest configs of the karmada apiserver and the
+	// kube-apiserver of the host cluster.
+	KarmadaKubeconfig *restclient.Config
+	FireflyKubeconfig *restclient.Config
+
+	// KarmadaCoreClient creates the tokens of the service accounts of the controllers in the karmada
+	// apiserver if ComponentConfig.UseServiceAccountCredentials is set.
+	KarmadaCoreClient v1core.CoreV1Interface
+
+	// UserAgent is the prefix of the user agents of the clients.
+	UserAgent string
+
+	// ClientConnectionOverrides overrides the QPS and Burst of the clients of the given controllers.
+	ClientConnectionOverrides map[string]clientbuilder.ClientConnectionOverride
+
+	// TracerProvider creates the spans of the requests sent with the service account tokens. It may be nil.
+	TracerProvider oteltrace.TracerProvider
+}
+
+// NewClientBuilders creates the client builders of the controllers. rootKarmadaClientBuilder is only used
+// for the shared-informers and discovery clients of karmada, whose requests are not shaped, otherwise their
+// watches would be closed by the request timeout.
+func NewClientBuilders(opts ClientBuilderOptions) (rootKarmadaClientBuilder, karmadaClientBuilder clientbuilder.KarmadaControllerClientBuilder, fireflyKubeClientBuilder clientbuilder.FireflyControllerClientBuilder) {
+	rootKarmadaClientBuilder = clientbuilder.NewSimpleKarmadaControllerClientBuilder(opts.KarmadaKubeconfig, opts.UserAgent, opts.ClientConnectionOverrides)
+
+	trafficShaping := clientbuilder.TrafficShaping{
+		RequestTimeout: opts.ComponentConfig.TrafficShaping.RequestTimeout.Duration,
+		BulkClients:    sets.NewString(opts.ComponentConfig.TrafficShaping.BulkClients...),
+	}
+	simpleKarmadaClientBuilder := clientbuilder.NewSimpleKarmadaControllerClientBuilder(opts.KarmadaKubeconfig, opts.UserAgent, opts.ClientConnectionOverrides)
+	simpleKarmadaClientBuilder.TrafficShaping = trafficShaping
+	karmadaClientBuilder = simpleKarmadaClientBuilder
+	if opts.ComponentConfig.UseServiceAccountCredentials {
+		// the credentials of the karmada kubeconfig are replaced with the tokens of the service accounts,
+		// so the wrappers of the requests are added to the anonymous config again.
+		tokenConfig := restclient.AnonymousClientConfig(opts.KarmadaKubeconfig)
+		tokenConfig.Wrap(dryrun.WrapperFor())
+		if opts.TracerProvider != nil {
+			tokenConfig.Wrap(tracing.WrapperFor(opts.TracerProvider))
+		}
+		dynamicKarmadaClientBuilder := clientbuilder.NewDynamicKarmadaControllerClientBuilder(tokenConfig, opts.KarmadaCoreClient, metav1.NamespaceSystem, opts.UserAgent, opts.ClientConnectionOverrides)
+		dynamicKarmadaClientBuilder.TrafficShaping = trafficShaping
+		karmadaClientBuilder = dynamicKarmadaClientBuilder
+	}
+
+	fireflyKubeClientBuilder = clientbuilder.NewSimpleFireflyControllerClientBuilder(opts.FireflyKubeconfig, opts.UserAgent, opts.ClientConnectionOverrides)
+	return
+}
+
+// GetKarmadaAvailableResources gets the map which contains all available resources of the karmada-apiserver
+// TODO: In general, any controller checking this needs to be dynamic so
+// users don't have to restart their controller manager if they change the apiserver.
+// Until we get there, the structure here needs to be exposed for the construction of a proper ControllerContext.
+func GetKarmadaAvailableResources(clientBuilder clientbuilder.KarmadaControllerClientBuilder) (map[schema.GroupVersionResource]bool, error) {
+	client := clientBuilder.ClientOrDie("controller-discovery")
+	discoveryClient := client.Discovery()
+	return GetAvailableResources(discoveryClient)
+}
+
+// GetHostClusterAvailableResources gets the map which contains all available resources of the kube-apiserver
+// on the host cluster.
+// TODO: In general, any controller checking this needs to be dynamic so
+// users don't have to restart their controller manager if they change the apiserver.
+// Until we get there, the structure here needs to be exposed for the construction of a proper ControllerContext.
+func GetHostClusterAvailableResources(clientBuilder clientbuilder.FireflyControllerClientBuilder) (map[schema.GroupVersionResource]bool, error) {
+	client := clientBuilder.ClientOrDie("controller-discovery")
+	discoveryClient := client.Discovery()
+	return GetAvailableResources(discoveryClient)
+}
+
+// GetAvailableResources gets the map which contains all available resources of the apiserver
+// TODO: In general, any controller checking this needs to be dynamic so
+// users don't have to restart their controller manager if they change the apiserver.
+// Until we get there, the structure here needs to be exposed for the construction of a proper ControllerContext.
+func GetAvailableResources(client discovery.DiscoveryInterface) (map[schema.GroupVersionResource]bool, error) {
+	_, resourceMap, err := client.ServerGroupsAndResources()
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("unable to get all supported resources from server: %v", err))
+	}
+	if len(resourceMap) == 0 {
+		return nil, fmt.Errorf("unable to get any supported resources from server")
+	}
+
+	allResources := map[schema.GroupVersionResource]bool{}
+	for _, apiResourceList := range resourceMap {
+		version, err := schema.ParseGroupVersion(apiResourceList.GroupVersion)
+		if err != nil {
+			return nil, err
+		}
+		for _, apiResource := range apiResourceList.APIResources {
+			allResources[version.WithResource(apiResource.Name)] = true
+		}
+	}
+
+	return allResources, nil
+}
+
+// ContextOptions are the options of the ControllerContext built by NewControllerContext.
+type ContextOptions struct {
+	// ComponentConfig provides access to init options for the controllers.
+	ComponentConfig fireflyctrlmgrconfig.FireflyKarmadaManagerConfiguration
+
+	// RootKarmadaClientBuilder is only used for the shared-informers and discovery clients of karmada.
+	RootKarmadaClientBuilder clientbuilder.KarmadaControllerClientBuilder
+	// KarmadaClientBuilder and FireflyClientBuilder provide the clients of the controllers.
+	KarmadaClientBuilder clientbuilder.KarmadaControllerClientBuilder
+	FireflyClientBuilder clientbuilder.FireflyControllerClientBuilder
+
+	// EstimatorNamespace is the namespace of scheduler-estimator
+	EstimatorNamespace string
+	// KarmadaName is the name of a firefly karmada object
+	KarmadaName string
+}
+
+// NewControllerContext creates a context struct containing references to resources needed by the
+// controllers such as the client builders. Controllers should call informerutil.SetTransform
+// on the informers they get from the shared informer factories to keep the caches small. The errors
+// caused by an unreachable apiserver are marked as transient, see backoff.Retry.
+func NewControllerContext(opts ContextOptions, stop <-chan struct{}) (ControllerContext, error) {
+	resyncPeriod := ResyncPeriod(opts.ComponentConfig.Generic.MinResyncPeriod.Duration)
+	rootKarmadaClientBuilder, fireflyKubeClientBuilder := opts.RootKarmadaClientBuilder, opts.FireflyClientBuilder
+
+	karmadaKubeClient := rootKarmadaClientBuilder.ClientOrDie("karmada-kube-shared-informers")
+	karmadaKubeSharedInformers := informers.NewSharedInformerFactory(karmadaKubeClient, resyncPeriod())
+
+	karmadaDynamicClient := rootKarmadaClientBuilder.DynamicClientOrDie("karmada-dynamic-shared-informers")
+	karmadaDynamicSharedInformers := dynamicinformer.NewDynamicSharedInformerFactory(karmadaDynamicClient, resyncPeriod())
+
+	karmadaFireflyClient := rootKarmadaClientBuilder.KarmadaFireflyClientOrDie("karmada-firefly-shared-informers")
+	karmadaFireflySharedInformers := karmadafireflyinformers.NewSharedInformerFactory(karmadaFireflyClient, resyncPeriod())
+
+	clientConfig := rootKarmadaClientBuilder.ConfigOrDie("karmada-shared-informers")
+	karmadaClient := karmadaversioned.NewForConfigOrDie(clientConfig)
+	karmadaSharedInformers := karmadainformers.NewSharedInformerFactory(karmadaClient, resyncPeriod())
+
+	metadataClient := metadata.NewForConfigOrDie(rootKarmadaClientBuilder.ConfigOrDie("firefly-metadata-informers"))
+	metadataInformers := metadatainformer.NewSharedInformerFactory(metadataClient, resyncPeriod())
+
+	// If apiserver is not running we should wait for some time and fail only then. This is particularly
+	// important when we start apiserver and controller manager at the same time.
+	if err := genericcontrollermanager.WaitForAPIServer(karmadaKubeClient, 10*time.Second); err != nil {
+		return ControllerContext{}, backoff.Transient(fmt.Errorf("failed to wait for apiserver being healthy: %v", err))
+	}
+
+	fireflyKubeClient := fireflyKubeClientBuilder.ClientOrDie("firefly-kube-shared-informers")
+	fireflyKubeSharedInformers := informers.NewSharedInformerFactory(fireflyKubeClient, resyncPeriod())
+
+	fireflyKubeFilteredClient := fireflyKubeClientBuilder.ClientOrDie("firefly-kube-filtered-shared-informers")
+	fireflyMetadataFilteredClient := metadata.NewForConfigOrDie(fireflyKubeClientBuilder.ConfigOrDie("firefly-metadata-filtered-informers"))
+	fireflyKubeFilteredInformers := informerutil.NewFilteredFactories(fireflyKubeFilteredClient, fireflyMetadataFilteredClient, resyncPeriod)
+
+	fireflyDynamicClient := fireflyKubeClientBuilder.DynamicClientOrDie("firefly-dynamic-shared-informers")
+	fireflyDynamicSharedInformers := dynamicinformer.NewDynamicSharedInformerFactory(fireflyDynamicClient, resyncPeriod())
+
+	fireflyClientConfig := fireflyKubeClientBuilder.ConfigOrDie("firefly-shared-informers")
+	fireflyClient := fireflyversioned.NewForConfigOrDie(fireflyClientConfig)
+	fireflySharedInformers := fireflyinformers.NewFilteredSharedInformerFactory(fireflyClient, resyncPeriod(), opts.EstimatorNamespace, nil)
+
+	// If apiserver is not running we should wait for some time and fail only then. This is particularly
+	// important when we start apiserver and controller manager at the same time.
+	if err := genericcontrollermanager.WaitForAPIServer(fireflyKubeClient, 10*time.Second); err != nil {
+		return ControllerContext{}, backoff.Transient(fmt.Errorf("failed to wait for apiserver being healthy: %v", err))
+	}
+
+	// Use a discovery client capable of being refreshed.
+	discoveryClient := rootKarmadaClientBuilder.DiscoveryClientOrDie("firelfy-controller-discovery")
+	cachedClient := cacheddiscovery.NewMemCacheClient(discoveryClient)
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedClient)
+
+	availableResources, err := GetKarmadaAvailableResources(rootKarmadaClientBuilder)
+	if err != nil {
+		return ControllerContext{}, backoff.Transient(err)
+	}
+
+	hostClusterAvailableResources, err := GetHostClusterAvailableResources(fireflyKubeClientBuilder)
+	if err != nil {
+		return ControllerContext{}, backoff.Transient(err)
+	}
+	// the restMapper is only reset once the context is built, so that no goroutine is left by the
+	// attempts which failed.
+	go wait.Until(func() {
+		restMapper.Reset()
+	}, 30*time.Second, stop)
+
+	ttlCaches := ttlcache.NewRegistry()
+	memberClusterClientBuilder := clientbuilder.NewSimpleMemberClusterClientBuilder(opts.KarmadaClientBuilder)
+	ctx := ControllerContext{
+		KarmadaClientBuilder:            opts.KarmadaClientBuilder,
+		MemberClusterClientBuilder:      memberClusterClientBuilder,
+		FireflyClientBuilder:            fireflyKubeClientBuilder,
+		KarmadaDynamicInformerFactory:   karmadaDynamicSharedInformers,
+		KarmadaKubeInformerFactory:      karmadaKubeSharedInformers,
+		KarmadaFireflyInformerFactory:   karmadaFireflySharedInformers,
+		KarmadaInformerFactory:          karmadaSharedInformers,
+		FireflyDynamicInformerFactory:   fireflyDynamicSharedInformers,
+		FireflyKubeInformerFactory:      fireflyKubeSharedInformers,
+		FireflyInformerFactory:          fireflySharedInformers,
+		FireflyKubeFilteredFactories:    fireflyKubeFilteredInformers,
+		KarmadaMetadataInformerFactory:  metadataInformers,
+		ObjectOrMetadataInformerFactory: informerfactory.NewInformerFactory(karmadaKubeSharedInformers, metadataInformers),
+		ComponentConfig:                 opts.ComponentConfig,
+		EstimatorNamespace:              opts.EstimatorNamespace,
+		KarmadaName:                     opts.KarmadaName,
+		RESTMapper:                      restMapper,
+		AvailableResources:              availableResources,
+		HostClusterAvailableResources:   hostClusterAvailableResources,
+		HostClusterCapabilities:         capabilities.New(hostClusterAvailableResources),
+		InformersStarted:                make(chan struct{}),
+		ResyncPeriod:                    resyncPeriod,
+		Expectations:                    expectations.NewRegistry(),
+		TTLCaches:                       ttlCaches,
+		ObjectResolver:                  resolver.New(restMapper, karmadaDynamicClient, ttlCaches.For("object-resolver", resolver.DefaultTTL)),
+		MemberClusterCache:              membercluster.NewCache(memberClusterClientBuilder, karmadaSharedInformers.Cluster().V1alpha1().Clusters(), resyncPeriod()),
+	}
+	return ctx, nil
+}
+
+// Dependencies maps the name of a controller to the names of the controllers it depends on, e.g. because
+// it relies on the objects they maintain. The dependencies which are disabled or skipped are ignored.
+type Dependencies map[string][]string
+
+// Readiness is implemented by the controllers others can depend on. HasSynced returns whether the
+// caches of the controller are warm, the controllers depending on it are only started once they are.
+// The controllers which don't implement it are ready once they're started.
+type Readiness interface {
+	HasSynced() bool
+}
+
+// StartControllers starts a set of controllers with a specified ControllerContext. The controllers are
+// started in the order of their dependencies, each once the controllers it depends on are ready, and
+// the others after ControllerStartInterval. The debugging handlers of the controllers are mounted onto
+// unsecuredMux if it's not nil, and their health checks and the reviews of their permissions are added
+// to healthzHandler.
+func StartControllers(ctx context.Context, controllerCtx ControllerContext, controllers map[string]InitFunc, dependencies Dependencies,
+	unsecuredMux *mux.PathRecorderMux, healthzHandler *controllerhealthz.MutableHealthzHandler) error {
+	var controllerChecks []healthz.HealthChecker
+
+	order, err := startOrder(controllers, dependencies)
+	if err != nil {
+		return err
+	}
+	// the controllers started so far, which may be nil.
+	started := map[string]controller.Interface{}
+
+	// the permissions of karmada are only reviewed if the controllers share the credentials of the
+	// manager, the service accounts of the controllers are granted by their own bindings.
+	var karmadaPermissionClient clientset.Interface
+	if !controllerCtx.ComponentConfig.UseServiceAccountCredentials {
+		karmadaPermissionClient = controllerCtx.KarmadaClientBuilder.ClientOrDie("firefly-permission-checker")
+	}
+	hostPermissionClient := controllerCtx.FireflyClientBuilder.ClientOrDie("firefly-permission-checker")
+
+	for _, controllerName := range order {
+		initFn := controllers[controllerName]
+		if !controllerCtx.IsControllerEnabled(controllerName) {
+			klog.Warningf("%q is disabled", controllerName)
+			continue
+		}
+
+		if len(dependencies[controllerName]) == 0 {
+			time.Sleep(wait.Jitter(controllerCtx.ComponentConfig.Generic.ControllerStartInterval.Duration, ControllerStartJitter))
+		} else if err := waitForDependencies(ctx, controllerCtx, controllerName, dependencies[controllerName], started); err != nil {
+			return err
+		}
+
+		klog.V(1).Infof("Starting %q", controllerName)
+		ctrl, enabled, err := initFn(ctx, controllerCtx)
+		if err != nil {
+			klog.Errorf("Error starting %q", controllerName)
+			return err
+		}
+		if !enabled {
+			klog.Warningf("Skipping %q", controllerName)
+			continue
+		}
+		started[controllerName] = ctrl
+		check := controllerhealthz.NamedPingChecker(controllerName)
+		if ctrl != nil {
+			// check if the controller supports and requests a debugHandler
+			// and it needs the unsecuredMux to mount the handler onto.
+			if debuggable, ok := ctrl.(controller.Debuggable); ok && unsecuredMux != nil {
+				if debugHandler := debuggable.DebuggingHandler(); debugHandler != nil {
+					basePath := "/debug/controllers/" + controllerName
+					unsecuredMux.UnlistedHandle(basePath, http.StripPrefix(basePath, debugHandler))
+					unsecuredMux.UnlistedHandlePrefix(basePath+"/", http.StripPrefix(basePath, debugHandler))
+				}
+			}
+			if healthCheckable, ok := ctrl.(controller.HealthCheckable); ok {
+				if realCheck := healthCheckable.HealthChecker(); realCheck != nil {
+					check = controllerhealthz.NamedHealthChecker(controllerName, realCheck)
+				}
+			}
+		}
+		controllerChecks = append(controllerChecks, check)
+
+		permissionChecker, err := rbac.NewPermissionChecker(controllerName, karmadaPermissionClient, hostPermissionClient)
+		if err != nil {
+			return fmt.Errorf("failed to check the permissions of %q: %v", controllerName, err)
+		}
+		if permissionChecker != nil {
+			controllerChecks = append(controllerChecks, permissionChecker)
+		}
+
+		klog.Infof("Started %q", controllerName)
+	}
+
+	healthzHandler.AddHealthChecker(controllerChecks...)
+	return nil
+}
+
+// startOrder returns the names of the controllers sorted by their dependencies, the controllers
+// which don't depend on each other are sorted by their names. The dependencies on the controllers
+// which aren't in the set are ignored.
+func startOrder(controllers map[string]InitFunc, dependencies Dependencies) ([]string, error) {
+	names := make([]string, 0, len(controllers))
+	for name := range controllers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	order := make([]string, 0, len(names))
+	// visiting holds the controllers whose dependencies are being visited, so that cycles are detected.
+	visiting, visited := map[string]bool{}, map[string]bool{}
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if visited[name] {
+			return nil
+		}
+		if visiting[name] {
+			return fmt.Errorf("the controllers depend on each other: %s", strings.Join(append(path, name), " -> "))
+		}
+		visiting[name] = true
+		for _, dependency := range dependencies[name] {
+			if _, ok := controllers[dependency]; !ok {
+				continue
+			}
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+		visiting[name], visited[name] = false, true
+		order = append(order, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// waitForDependencies waits for the started dependencies of the controller to be ready. Their informers
+// are started first, since the shared informer factories are only started once all of the controllers
+// are started otherwise.
+func waitForDependencies(ctx context.Context, controllerCtx ControllerContext, controllerName string, dependencies []string, started map[string]controller.Interface) error {
+	var synced []cache.InformerSynced
+	for _, dependency := range dependencies {
+		ctrl, ok := started[dependency]
+		if !ok {
+			klog.V(1).Infof("%q doesn't wait for %q which isn't started", controllerName, dependency)
+			continue
+		}
+		if readiness, ok := ctrl.(Readiness); ok {
+			synced = append(synced, readiness.HasSynced)
+		}
+	}
+	if len(synced) == 0 {
+		return nil
+	}
+
+	klog.Infof("Waiting for the dependencies of %q to be ready", controllerName)
+	controllerCtx.startFactories(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+		return fmt.Errorf("failed to wait for the dependencies of %q to be ready", controllerName)
+	}
+	return nil
+}
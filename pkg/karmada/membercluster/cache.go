@@ -0,0 +1,310 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package membercluster provides the clients and the informers of the member clusters of the karmada shared
+// by the controllers of the firefly-karmada-manager, so that a member cluster is only watched once.
+package membercluster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	clusterinformers "github.com/karmada-io/karmada/pkg/generated/informers/externalversions/cluster/v1alpha1"
+	clusterlisters "github.com/karmada-io/karmada/pkg/generated/listers/cluster/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/carlory/firefly/pkg/clientbuilder"
+)
+
+const (
+	// clientName is the name of the clients of the member clusters passed to the client builder.
+	clientName = "firefly-member-cluster-cache"
+
+	// healthCheckPeriod is the period at which the connections to the cached member clusters are probed.
+	healthCheckPeriod = 30 * time.Second
+
+	// unhealthyThreshold is the number of consecutive failed probes after which a member cluster is unhealthy.
+	unhealthyThreshold = 3
+)
+
+// EvictionHandler is called with the name of the member cluster whose clients and informers are evicted,
+// the controllers drop what they hold of the cluster and get the rebuilt ones from the cache again.
+type EvictionHandler func(cluster string)
+
+// Member is the clients and the informers of a member cluster. It must not be used once it's evicted,
+// which is told by Stopped.
+type Member struct {
+	// Name is the name of the member cluster.
+	Name string
+	// Client is the client of the member cluster.
+	Client clientset.Interface
+	// DynamicClient is the dynamic client of the member cluster.
+	DynamicClient dynamic.Interface
+	// InformerFactory gives access to the informers of the member cluster, which are started by ForResource.
+	InformerFactory dynamicinformer.DynamicSharedInformerFactory
+
+	// connection is the connection settings of the cluster the clients are built for.
+	connection clusterv1alpha1.ClusterSpec
+	stopCh     chan struct{}
+
+	lock sync.RWMutex
+	// failures is the number of consecutive failed probes.
+	failures  int
+	lastError error
+}
+
+// Healthy returns whether the member cluster is reachable, it's false after unhealthyThreshold
+// consecutive failed probes.
+func (m *Member) Healthy() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.failures < unhealthyThreshold
+}
+
+// LastError returns the error of the last failed probe, or nil if the last probe succeeded.
+func (m *Member) LastError() error {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.lastError
+}
+
+// Stopped is closed once the member is evicted, the informers of the member are stopped along with it.
+func (m *Member) Stopped() <-chan struct{} {
+	return m.stopCh
+}
+
+// ForResource returns the informer of the resource of the member cluster, which is started if it's not.
+func (m *Member) ForResource(gvr schema.GroupVersionResource) informers.GenericInformer {
+	informer := m.InformerFactory.ForResource(gvr)
+	m.InformerFactory.Start(m.stopCh)
+	return informer
+}
+
+// Cache holds the clients and the informers of the member clusters keyed by the cluster name. They're
+// built on first use through the cluster proxy of the karmada, and evicted when the cluster is unjoined,
+// its connection settings are changed, e.g. its credentials are rotated, or it recovers from being
+// unhealthy, since the watches of the informers may be stuck.
+type Cache struct {
+	builder       clientbuilder.MemberClusterClientBuilder
+	clusterLister clusterlisters.ClusterLister
+	resyncPeriod  time.Duration
+
+	lock             sync.Mutex
+	members          map[string]*Member
+	evictionHandlers []EvictionHandler
+}
+
+// NewCache returns a cache of the member clusters, whose informers are resynced at the given period.
+func NewCache(builder clientbuilder.MemberClusterClientBuilder, clusterInformer clusterinformers.ClusterInformer, resyncPeriod time.Duration) *Cache {
+	c := &Cache{
+		builder:       builder,
+		clusterLister: clusterInformer.Lister(),
+		resyncPeriod:  resyncPeriod,
+		members:       map[string]*Member{},
+	}
+	clusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: c.updateCluster,
+		DeleteFunc: c.deleteCluster,
+	})
+	return c
+}
+
+// AddEvictionHandler registers the handler called whenever a member cluster is evicted.
+func (c *Cache) AddEvictionHandler(handler EvictionHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.evictionHandlers = append(c.evictionHandlers, handler)
+}
+
+// Get returns the clients and the informers of the member cluster, which are built if they're not cached.
+func (c *Cache) Get(name string) (*Member, error) {
+	cluster, err := c.clusterLister.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if !cluster.DeletionTimestamp.IsZero() {
+		return nil, errors.NewNotFound(clusterv1alpha1.Resource("clusters"), name)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if member, ok := c.members[name]; ok {
+		return member, nil
+	}
+
+	client, err := c.builder.Client(name, clientName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the client of member cluster %s: %v", name, err)
+	}
+	dynamicClient, err := c.builder.DynamicClient(name, clientName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the dynamic client of member cluster %s: %v", name, err)
+	}
+	// the watches of the informers would be closed by the request timeout of the clients.
+	informerConfig, err := c.builder.Config(name, clientName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the config of member cluster %s: %v", name, err)
+	}
+	informerConfig.Timeout = 0
+	informerClient, err := dynamic.NewForConfig(informerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the informer client of member cluster %s: %v", name, err)
+	}
+	member := &Member{
+		Name:            name,
+		Client:          client,
+		DynamicClient:   dynamicClient,
+		InformerFactory: dynamicinformer.NewDynamicSharedInformerFactory(informerClient, c.resyncPeriod),
+		connection:      connectionOf(cluster),
+		stopCh:          make(chan struct{}),
+	}
+	c.members[name] = member
+	klog.V(2).InfoS("Built the clients of member cluster", "cluster", name)
+	return member, nil
+}
+
+// Evict stops the informers of the member cluster and drops its clients, they're built again by the next Get.
+func (c *Cache) Evict(name string, reason string) {
+	c.lock.Lock()
+	member, ok := c.members[name]
+	if ok {
+		delete(c.members, name)
+		close(member.stopCh)
+	}
+	handlers := c.evictionHandlers
+	c.lock.Unlock()
+	if !ok {
+		return
+	}
+
+	klog.V(2).InfoS("Evicted the clients of member cluster", "cluster", name, "reason", reason)
+	for _, handler := range handlers {
+		handler(name)
+	}
+}
+
+// Run probes the connections to the cached member clusters until the context is done.
+func (c *Cache) Run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+	wait.UntilWithContext(ctx, c.probeMembers, healthCheckPeriod)
+
+	c.lock.Lock()
+	names := make([]string, 0, len(c.members))
+	for name := range c.members {
+		names = append(names, name)
+	}
+	c.lock.Unlock()
+	for _, name := range names {
+		c.Evict(name, "shutdown")
+	}
+}
+
+func (c *Cache) probeMembers(ctx context.Context) {
+	c.lock.Lock()
+	members := make([]*Member, 0, len(c.members))
+	for _, member := range c.members {
+		members = append(members, member)
+	}
+	c.lock.Unlock()
+
+	for _, member := range members {
+		c.probe(ctx, member)
+	}
+}
+
+// probe checks the connection to the member cluster, which is evicted once it recovers from being unhealthy.
+func (c *Cache) probe(ctx context.Context, member *Member) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckPeriod/2)
+	defer cancel()
+	_, err := member.Client.Discovery().RESTClient().Get().AbsPath("/healthz").DoRaw(ctx)
+
+	member.lock.Lock()
+	wasHealthy := member.failures < unhealthyThreshold
+	if err != nil {
+		member.failures++
+		member.lastError = err
+	} else {
+		member.failures = 0
+		member.lastError = nil
+	}
+	healthy := member.failures < unhealthyThreshold
+	member.lock.Unlock()
+
+	switch {
+	case wasHealthy && !healthy:
+		klog.InfoS("Member cluster is unhealthy", "cluster", member.Name, "err", err)
+	case !wasHealthy && healthy:
+		c.Evict(member.Name, "recovered")
+	}
+}
+
+func (c *Cache) updateCluster(old, cur interface{}) {
+	cluster := cur.(*clusterv1alpha1.Cluster)
+	if !cluster.DeletionTimestamp.IsZero() {
+		c.Evict(cluster.Name, "unjoined")
+		return
+	}
+
+	c.lock.Lock()
+	member, ok := c.members[cluster.Name]
+	c.lock.Unlock()
+	if ok && !equality.Semantic.DeepEqual(member.connection, connectionOf(cluster)) {
+		c.Evict(cluster.Name, "connection changed")
+	}
+}
+
+func (c *Cache) deleteCluster(obj interface{}) {
+	cluster, ok := obj.(*clusterv1alpha1.Cluster)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("couldn't get object from tombstone %#v", obj))
+			return
+		}
+		cluster, ok = tombstone.Obj.(*clusterv1alpha1.Cluster)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("tombstone contained object that is not a cluster %#v", obj))
+			return
+		}
+	}
+	c.Evict(cluster.Name, "unjoined")
+}
+
+// connectionOf returns the settings of the cluster which the connection through the cluster proxy
+// depends on, a change of them, e.g. a rotated secret, makes the clients be built again.
+func connectionOf(cluster *clusterv1alpha1.Cluster) clusterv1alpha1.ClusterSpec {
+	return clusterv1alpha1.ClusterSpec{
+		APIEndpoint:                 cluster.Spec.APIEndpoint,
+		SecretRef:                   cluster.Spec.SecretRef,
+		ImpersonatorSecretRef:       cluster.Spec.ImpersonatorSecretRef,
+		InsecureSkipTLSVerification: cluster.Spec.InsecureSkipTLSVerification,
+		ProxyURL:                    cluster.Spec.ProxyURL,
+		ProxyHeader:                 cluster.Spec.ProxyHeader,
+	}
+}
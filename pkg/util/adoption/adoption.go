@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adoption
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/carlory/firefly/pkg/constants"
+)
+
+type adoptionKey struct{}
+
+// WithAdoption returns a copy of ctx under which the pre-existing objects which are not owned by
+// the owners of the desired objects are adopted by them.
+func WithAdoption(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adoptionKey{}, true)
+}
+
+// Enabled returns whether the pre-existing objects are adopted with ctx.
+func Enabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(adoptionKey{}).(bool)
+	return enabled
+}
+
+// ForObject returns a copy of ctx which adopts the pre-existing objects if the object is annotated with
+// constants.AdoptExistingResourcesAnnotation. Otherwise ctx is returned as is.
+func ForObject(ctx context.Context, obj metav1.Object) context.Context {
+	if obj.GetAnnotations()[constants.AdoptExistingResourcesAnnotation] == "true" {
+		return WithAdoption(ctx)
+	}
+	return ctx
+}
+
+// NotOwnedError is returned when an object to be reconciled already exists but is not owned by the
+// owners of the desired object, and the adoption is not enabled.
+type NotOwnedError struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (e *NotOwnedError) Error() string {
+	return fmt.Sprintf("%s %s/%s already exists and is not managed by firefly, annotate its owner with %s=true to adopt it",
+		e.Kind, e.Namespace, e.Name, constants.AdoptExistingResourcesAnnotation)
+}
+
+// Check checks whether the current object may be overwritten by the desired one of the given kind, and
+// returns whether it's adopted by doing so. It may if the desired object has no owners, or the current
+// one is owned by any of them already. Otherwise it's adopted if the adoption is enabled with ctx.
+func Check(ctx context.Context, kind string, current, desired metav1.Object) (bool, error) {
+	if len(desired.GetOwnerReferences()) == 0 || ownedBy(current, desired.GetOwnerReferences()) {
+		return false, nil
+	}
+	if !Enabled(ctx) {
+		return false, &NotOwnedError{Kind: kind, Namespace: current.GetNamespace(), Name: current.GetName()}
+	}
+	return true, nil
+}
+
+// ownedBy returns whether the object is owned by any of the owners.
+func ownedBy(obj metav1.Object, owners []metav1.OwnerReference) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		for _, owner := range owners {
+			if ref.UID == owner.UID {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	memory "k8s.io/client-go/discovery/cached"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/utils/pointer"
+
+	"github.com/carlory/firefly/pkg/util/audit"
+)
+
+// Applier applies unstructured objects with server-side apply.
+type Applier struct {
+	dynamicClient dynamic.Interface
+	mapper        meta.ResettableRESTMapper
+	fieldManager  string
+}
+
+// NewApplier returns a new *Applier which talks to the cluster of the given config.
+func NewApplier(config *rest.Config, fieldManager string) (*Applier, error) {
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &Applier{
+		dynamicClient: dynamicClient,
+		mapper:        restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+		fieldManager:  fieldManager,
+	}, nil
+}
+
+// RESTMapping returns the rest mapping of the given kind. The discovery cache is
+// invalidated once if the kind is unknown, because it may be introduced by a crd
+// installed just now.
+func (a *Applier) RESTMapping(gvk schema.GroupVersionKind) (*meta.RESTMapping, error) {
+	mapping, err := a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		a.mapper.Reset()
+		mapping, err = a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	return mapping, err
+}
+
+// IsNamespaced returns true if the given kind is namespace-scoped.
+func (a *Applier) IsNamespaced(gvk schema.GroupVersionKind) (bool, error) {
+	mapping, err := a.RESTMapping(gvk)
+	if err != nil {
+		return false, err
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+// resourceInterface returns the dynamic client for the given object. The namespace of the
+// object is defaulted or cleared according to the scope of its resource.
+func (a *Applier) resourceInterface(obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	mapping, err := a.RESTMapping(obj.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		obj.SetNamespace("")
+		return a.dynamicClient.Resource(mapping.Resource), nil
+	}
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(metav1.NamespaceDefault)
+	}
+	return a.dynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace()), nil
+}
+
+// Apply applies the object and takes the ownership of conflicting fields.
+// The namespace of obj is defaulted or cleared according to the scope of its resource.
+// The object is read before it is applied to audit whether it is created or changed.
+func (a *Applier) Apply(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	ri, err := a.resourceInterface(obj)
+	if err != nil {
+		return nil, err
+	}
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	existing, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	applied, err := ri.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: a.fieldManager, Force: pointer.Bool(true)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply %s %s: %v", obj.GetKind(), objectKey(obj), err)
+	}
+	if existing == nil {
+		audit.Record(ctx, audit.Create, applied, nil)
+	} else {
+		audit.RecordUpdate(ctx, existing, applied)
+	}
+	return applied, nil
+}
+
+// Get returns the object identified by obj as it's in the cluster.
+func (a *Applier) Get(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	ri, err := a.resourceInterface(obj)
+	if err != nil {
+		return nil, err
+	}
+	return ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+}
+
+// Delete deletes the object identified by obj. It's not an error if the object or
+// its resource type doesn't exist.
+func (a *Applier) Delete(ctx context.Context, obj *unstructured.Unstructured) error {
+	ri, err := a.resourceInterface(obj)
+	if meta.IsNoMatchError(err) {
+		// the resource type is gone, so is the object.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	err = ri.Delete(ctx, obj.GetName(), metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete %s %s: %v", obj.GetKind(), objectKey(obj), err)
+	}
+	audit.Record(ctx, audit.Delete, obj, nil)
+	return nil
+}
+
+func objectKey(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
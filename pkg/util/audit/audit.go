@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	"github.com/carlory/firefly/pkg/util/diff"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+)
+
+// Verb is the kind of a mutation.
+type Verb string
+
+const (
+	Create Verb = "create"
+	Update Verb = "update"
+	Delete Verb = "delete"
+)
+
+// ObjectReference identifies an object.
+type ObjectReference struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// String returns the reference in the form of kind/namespace/name.
+func (r ObjectReference) String() string {
+	return path.Join(r.Kind, r.Namespace, r.Name)
+}
+
+// Event is a mutation performed by a controller.
+type Event struct {
+	Time   metav1.Time     `json:"time"`
+	Verb   Verb            `json:"verb"`
+	Object ObjectReference `json:"object"`
+	// Trigger is the object whose reconcile performed the mutation.
+	Trigger *ObjectReference `json:"trigger,omitempty"`
+	// Diff is the comma separated paths of the fields changed by an update.
+	Diff string `json:"diff,omitempty"`
+	// DryRun is true if the mutation is only validated by the apiserver, not persisted.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// Sink keeps the audit events in addition to the logs. The events of dry-run mutations are only logged.
+type Sink interface {
+	Write(event Event)
+}
+
+var (
+	lock  sync.RWMutex
+	sinks []Sink
+)
+
+// RegisterSink registers the sink which is written by all subsequent events.
+func RegisterSink(sink Sink) {
+	lock.Lock()
+	defer lock.Unlock()
+	sinks = append(sinks, sink)
+}
+
+type triggerKey struct{}
+
+// WithTrigger returns a copy of ctx which records the given object as the trigger of the
+// mutations performed with it. Controllers call it with the object they are reconciling.
+func WithTrigger(ctx context.Context, obj runtime.Object) context.Context {
+	ref := Reference(obj)
+	return context.WithValue(ctx, triggerKey{}, &ref)
+}
+
+// Record logs the mutation of the object performed with ctx and writes it to the registered sinks.
+// The diff is the paths of the fields changed by an update.
+func Record(ctx context.Context, verb Verb, obj runtime.Object, diff []string) {
+	record(ctx, verb, Reference(obj), diff)
+}
+
+// RecordResult records the mutation of the object if it succeeded, that is err is nil.
+func RecordResult(ctx context.Context, verb Verb, obj runtime.Object, err error) {
+	if err == nil {
+		record(ctx, verb, Reference(obj), nil)
+	}
+}
+
+// RecordDeletion records the deletion of the referenced object if it succeeded, that is err is nil.
+// It's used when the object is deleted by its name.
+func RecordDeletion(ctx context.Context, ref ObjectReference, err error) {
+	if err == nil {
+		record(ctx, Delete, ref, nil)
+	}
+}
+
+// RecordUpdate records the update of an object from old to updated if it changed the object,
+// that is its resource version is bumped, and returns whether it changed the object. The resource
+// version is kept by a dry-run update, so it's considered to change the object if any field is changed.
+func RecordUpdate(ctx context.Context, old, updated runtime.Object) bool {
+	oldAccessor, err := meta.Accessor(old)
+	if err != nil {
+		return false
+	}
+	updatedAccessor, err := meta.Accessor(updated)
+	if err != nil {
+		return false
+	}
+	changes := Changes(old, updated)
+	if dryrun.Enabled(ctx) {
+		if len(changes) == 0 {
+			return false
+		}
+	} else if oldAccessor.GetResourceVersion() == updatedAccessor.GetResourceVersion() {
+		return false
+	}
+	record(ctx, Update, Reference(updated), changes)
+	return true
+}
+
+func record(ctx context.Context, verb Verb, ref ObjectReference, diff []string) {
+	event := Event{
+		Time:   metav1.Now(),
+		Verb:   verb,
+		Object: ref,
+		Diff:   strings.Join(diff, ","),
+		DryRun: dryrun.Enabled(ctx),
+	}
+	if trigger, ok := ctx.Value(triggerKey{}).(*ObjectReference); ok {
+		event.Trigger = trigger
+	}
+
+	trigger := ""
+	if event.Trigger != nil {
+		trigger = event.Trigger.String()
+	}
+	klog.InfoS("Audit", "verb", event.Verb, "object", event.Object.String(), "trigger", trigger, "diff", event.Diff, "dryRun", event.DryRun)
+	if event.DryRun {
+		return
+	}
+
+	lock.RLock()
+	defer lock.RUnlock()
+	for _, sink := range sinks {
+		sink.Write(event)
+	}
+}
+
+// Reference returns the reference of the object. Typed objects don't carry their kind,
+// so it falls back to the name of their type, which is their kind.
+func Reference(obj runtime.Object) ObjectReference {
+	ref := ObjectReference{Kind: obj.GetObjectKind().GroupVersionKind().Kind}
+	if ref.Kind == "" {
+		ref.Kind = reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	}
+	if accessor, err := meta.Accessor(obj); err == nil {
+		ref.Namespace = accessor.GetNamespace()
+		ref.Name = accessor.GetName()
+	}
+	return ref
+}
+
+// Changes returns the paths of the fields changed by an update from old to new. The status is
+// ignored because it isn't changed by an update of the object, but possibly by others in the meantime.
+func Changes(old, new runtime.Object) []string {
+	var changes []string
+	for _, path := range diff.Paths(old, new) {
+		if path != "status" && !strings.HasPrefix(path, "status.") {
+			changes = append(changes, path)
+		}
+	}
+	return changes
+}
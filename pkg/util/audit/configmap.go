@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// ConfigMapEventsKey is the key of the configmap data which holds the audit events, one json per line.
+const ConfigMapEventsKey = "events"
+
+// flushPeriod is the period in which the events are written to the configmap.
+const flushPeriod = 10 * time.Second
+
+// ConfigMapSink keeps the latest audit events in a configmap as a ring buffer.
+type ConfigMapSink struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+	maxEvents int
+
+	lock   sync.Mutex
+	events []Event
+	dirty  bool
+}
+
+// NewConfigMapSink returns a sink which keeps the latest maxEvents events in the given configmap.
+func NewConfigMapSink(client kubernetes.Interface, namespace, name string, maxEvents int) *ConfigMapSink {
+	return &ConfigMapSink{
+		client:    client,
+		namespace: namespace,
+		name:      name,
+		maxEvents: maxEvents,
+	}
+}
+
+// Write implements Sink. The event is written to the configmap in the next flush.
+func (s *ConfigMapSink) Write(event Event) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.append(event)
+	s.dirty = true
+}
+
+func (s *ConfigMapSink) append(events ...Event) {
+	s.events = append(s.events, events...)
+	if n := len(s.events) - s.maxEvents; n > 0 {
+		s.events = s.events[n:]
+	}
+}
+
+// Run loads the events kept by the configmap and flushes the events periodically until ctx is done.
+func (s *ConfigMapSink) Run(ctx context.Context) {
+	klog.InfoS("Starting audit configmap sink", "configmap", klog.KRef(s.namespace, s.name))
+	defer klog.InfoS("Shutting down audit configmap sink", "configmap", klog.KRef(s.namespace, s.name))
+
+	if err := s.load(ctx); err != nil {
+		klog.ErrorS(err, "Failed to load audit events", "configmap", klog.KRef(s.namespace, s.name))
+	}
+	wait.UntilWithContext(ctx, s.flush, flushPeriod)
+	// flush the events recorded in the last period.
+	s.flush(context.Background())
+}
+
+// load prepends the events kept by the configmap to the events recorded since the start.
+func (s *ConfigMapSink) load(ctx context.Context) error {
+	cm, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var kept []Event
+	scanner := bufio.NewScanner(strings.NewReader(cm.Data[ConfigMapEventsKey]))
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			klog.V(2).InfoS("Skipping malformed audit event", "configmap", klog.KObj(cm), "err", err)
+			continue
+		}
+		kept = append(kept, event)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	recorded := s.events
+	s.events = nil
+	s.append(kept...)
+	s.append(recorded...)
+	return nil
+}
+
+func (s *ConfigMapSink) flush(ctx context.Context) {
+	s.lock.Lock()
+	if !s.dirty {
+		s.lock.Unlock()
+		return
+	}
+	var b strings.Builder
+	for _, event := range s.events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			continue
+		}
+		b.Write(data)
+		b.WriteString("\n")
+	}
+	s.dirty = false
+	s.lock.Unlock()
+
+	if err := s.save(ctx, b.String()); err != nil {
+		klog.ErrorS(err, "Failed to write audit events", "configmap", klog.KRef(s.namespace, s.name))
+		s.lock.Lock()
+		s.dirty = true
+		s.lock.Unlock()
+	}
+}
+
+func (s *ConfigMapSink) save(ctx context.Context, events string) error {
+	cm, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: s.namespace, Name: s.name},
+			Data:       map[string]string{ConfigMapEventsKey: events},
+		}
+		_, err = s.client.CoreV1().ConfigMaps(s.namespace).Create(ctx, cm, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[ConfigMapEventsKey] = events
+	_, err = s.client.CoreV1().ConfigMaps(s.namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
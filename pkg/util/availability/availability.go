@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package availability
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+)
+
+// MaxReplicas returns the maximum number of replicas a workload may run, that is the max replicas of
+// its autoscaling if it's autoscaled, otherwise its replicas.
+func MaxReplicas(replicas *int32, autoscaling *installv1alpha1.Autoscaling) int32 {
+	if autoscaling != nil {
+		return autoscaling.MaxReplicas
+	}
+	if replicas != nil {
+		return *replicas
+	}
+	return 1
+}
+
+// applies returns whether the policy applies to a workload which runs at most maxReplicas replicas.
+func applies(policy installv1alpha1.AvailabilityPolicy, maxReplicas int32) bool {
+	return !policy.Disabled && maxReplicas > 1
+}
+
+// SpreadPods adds the topology spread constraints of the policy to the pod template of a workload, which
+// select its pods by the given selector. The constraints set on the template for the same topology keys,
+// e.g. by patches, are kept.
+func SpreadPods(policy installv1alpha1.AvailabilityPolicy, template *corev1.PodTemplateSpec, selector *metav1.LabelSelector, maxReplicas int32) {
+	if !applies(policy, maxReplicas) {
+		return
+	}
+
+	topologyKeys := policy.TopologyKeys
+	if len(topologyKeys) == 0 {
+		topologyKeys = []string{corev1.LabelHostname}
+	}
+	whenUnsatisfiable := policy.WhenUnsatisfiable
+	if whenUnsatisfiable == "" {
+		whenUnsatisfiable = corev1.ScheduleAnyway
+	}
+
+	existing := map[string]bool{}
+	for _, constraint := range template.Spec.TopologySpreadConstraints {
+		existing[constraint.TopologyKey] = true
+	}
+	for _, key := range topologyKeys {
+		if existing[key] {
+			continue
+		}
+		template.Spec.TopologySpreadConstraints = append(template.Spec.TopologySpreadConstraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       key,
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector:     selector.DeepCopy(),
+		})
+	}
+}
+
+// PodDisruptionBudget returns the PodDisruptionBudget of a workload with the given name, labels and selector,
+// or nil if the policy doesn't apply to it.
+func PodDisruptionBudget(policy installv1alpha1.AvailabilityPolicy, namespace, name string, labels map[string]string, selector *metav1.LabelSelector, maxReplicas int32) *policyv1.PodDisruptionBudget {
+	if !applies(policy, maxReplicas) {
+		return nil
+	}
+
+	maxUnavailable := intstr.FromInt(1)
+	if policy.MaxUnavailable != nil {
+		maxUnavailable = *policy.MaxUnavailable
+	}
+	pdbLabels := make(map[string]string, len(labels))
+	for k, v := range labels {
+		pdbLabels[k] = v
+	}
+	return &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "policy/v1",
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    pdbLabels,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector:       selector.DeepCopy(),
+		},
+	}
+}
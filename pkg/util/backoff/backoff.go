@@ -0,0 +1,38 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backoff
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// RateLimiter returns the rate limiter of the workqueue of a controller, which backs off each failed item
+// exponentially from initial to max. Like the default controller rate limiter, the overall retries are
+// limited to 10 qps with a burst of 100 as well. The default controller rate limiter is returned if either
+// of the durations is zero.
+func RateLimiter(initial, max time.Duration) workqueue.RateLimiter {
+	if initial <= 0 || max <= 0 {
+		return workqueue.DefaultControllerRateLimiter()
+	}
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(initial, max),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+	)
+}
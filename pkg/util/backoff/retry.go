@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backoff
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// maxRetryInterval caps the interval between the calls of Retry.
+const maxRetryInterval = 30 * time.Second
+
+// transientError is an error which may go away by itself, e.g. an apiserver which is unreachable for a while.
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+
+func (e *transientError) Unwrap() error { return e.err }
+
+// Transient marks the error as transient, so that it's retried by Retry. It returns nil if err is nil.
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &transientError{err: err}
+}
+
+// IsTransient tells whether the error, or an error it wraps, is marked as transient.
+func IsTransient(err error) bool {
+	var transient *transientError
+	return errors.As(err, &transient)
+}
+
+// Retry calls fn until it succeeds or returns an error which isn't transient, backing off exponentially
+// from initial up to 30s between the calls. It gives up with the last error once timeout elapses or the
+// context is done. fn is only called once if timeout is zero.
+func Retry(ctx context.Context, what string, timeout, initial time.Duration, fn func() error) error {
+	deadline := time.Now().Add(timeout)
+	interval := initial
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !IsTransient(err) {
+			return err
+		}
+		if interval <= 0 || time.Now().Add(interval).After(deadline) {
+			return err
+		}
+
+		klog.ErrorS(err, "Transient failure, retrying", "operation", what, "attempt", attempt, "after", interval)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(interval):
+		}
+		if interval *= 2; interval > maxRetryInterval {
+			interval = maxRetryInterval
+		}
+	}
+}
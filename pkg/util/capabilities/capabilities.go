@@ -0,0 +1,117 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package capabilities tells the optional APIs served by a cluster, e.g. the Prometheus Operator or the
+// Gateway API, so that the controllers vary what they create by the capabilities of the host cluster
+// instead of failing on the APIs which are not installed.
+package capabilities
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/carlory/firefly/pkg/util/monitoring"
+)
+
+// Capability is an optional feature of a cluster, which is available if all of its resources are served.
+type Capability string
+
+const (
+	// PodMonitors means the Prometheus Operator is installed, so that the components are scraped by PodMonitors.
+	PodMonitors Capability = "PodMonitors"
+	// PrometheusRules means the Prometheus Operator is installed, so that the alerting rules are loaded from PrometheusRules.
+	PrometheusRules Capability = "PrometheusRules"
+	// GatewayAPI means the Gateway API is installed, so that the karmada-apiserver is exposed through a TLSRoute.
+	GatewayAPI Capability = "GatewayAPI"
+	// Ingresses means the networking.k8s.io/v1 Ingresses are served.
+	Ingresses Capability = "Ingresses"
+)
+
+var (
+	// TLSRouteGVR is the GroupVersionResource of the TLSRoutes of the Gateway API.
+	TLSRouteGVR = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Resource: "tlsroutes"}
+	// IngressGVR is the GroupVersionResource of the Ingresses.
+	IngressGVR = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}
+)
+
+// requirements are the resources required by each capability.
+var requirements = map[Capability][]schema.GroupVersionResource{
+	PodMonitors:     {monitoring.PodMonitorGVR},
+	PrometheusRules: {monitoring.PrometheusRuleGVR},
+	GatewayAPI:      {TLSRouteGVR},
+	Ingresses:       {IngressGVR},
+}
+
+// Capabilities tells the capabilities of a cluster from the resources discovered from it.
+type Capabilities struct {
+	resources map[schema.GroupVersionResource]bool
+}
+
+// New returns the capabilities of the cluster which serves the given resources.
+func New(resources map[schema.GroupVersionResource]bool) *Capabilities {
+	return &Capabilities{resources: resources}
+}
+
+// Has returns whether all resources required by the capability are served.
+func (c *Capabilities) Has(capability Capability) bool {
+	return len(c.Missing(capability)) == 0
+}
+
+// Missing returns the resources required by the capability which are not served, an unknown
+// capability is never available.
+func (c *Capabilities) Missing(capability Capability) []schema.GroupVersionResource {
+	required, ok := requirements[capability]
+	if !ok {
+		return []schema.GroupVersionResource{{Resource: string(capability)}}
+	}
+	var missing []schema.GroupVersionResource
+	for _, gvr := range required {
+		if c == nil || !c.resources[gvr] {
+			missing = append(missing, gvr)
+		}
+	}
+	return missing
+}
+
+// Reason returns a human readable message telling why the capability is or isn't available.
+func (c *Capabilities) Reason(capability Capability) string {
+	missing := c.Missing(capability)
+	if len(missing) == 0 {
+		return fmt.Sprintf("%s are served by the cluster", resourceNames(requirements[capability]))
+	}
+	return fmt.Sprintf("%s are not served by the cluster", resourceNames(missing))
+}
+
+// Known returns all known capabilities in alphabetical order.
+func Known() []Capability {
+	known := make([]Capability, 0, len(requirements))
+	for capability := range requirements {
+		known = append(known, capability)
+	}
+	sort.Slice(known, func(i, j int) bool { return known[i] < known[j] })
+	return known
+}
+
+func resourceNames(gvrs []schema.GroupVersionResource) string {
+	names := make([]string, 0, len(gvrs))
+	for _, gvr := range gvrs {
+		names = append(names, gvr.GroupResource().String()+"/"+gvr.Version)
+	}
+	return strings.Join(names, ", ")
+}
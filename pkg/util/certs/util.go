@@ -46,6 +46,23 @@ const (
 	Duration365d = time.Hour * 24 * 365
 )
 
+// ParseCA parses the PEM encoded certificate and key of a CA.
+func ParseCA(certData, keyData []byte) (*x509.Certificate, crypto.Signer, error) {
+	caCerts, err := certutil.ParseCertsPEM(certData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the CA: %v", err)
+	}
+	caKey, err := keyutil.ParsePrivateKeyPEM(keyData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the key of the CA: %v", err)
+	}
+	signer, ok := caKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("the key of the CA is not a signer")
+	}
+	return caCerts[0], signer, nil
+}
+
 // NewPrivateKey returns a new private key.
 var NewPrivateKey = GeneratePrivateKey
 
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chart
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+	"github.com/carlory/firefly/pkg/helm"
+	"github.com/carlory/firefly/pkg/util/apply"
+)
+
+// Render fetches the referenced chart and renders it for the given release. The
+// values are merged in order, so the later ones take precedence.
+func Render(ctx context.Context, fetcher *helm.Fetcher, ref *installv1alpha1.ChartReference, release helm.ReleaseOptions, values ...helm.Values) ([]*unstructured.Unstructured, error) {
+	chart, err := fetcher.Fetch(ctx, ref.Repository, ref.Name, ref.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chart %s: %v", ref.Name, err)
+	}
+	objs, err := helm.Render(chart, helm.MergeValues(helm.Values{}, values...), release)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart %s: %v", ref.Name, err)
+	}
+	return objs, nil
+}
+
+// ParseValues parses the values held by a raw extension. A nil raw extension results in empty values.
+func ParseValues(raw *runtime.RawExtension) (helm.Values, error) {
+	if raw == nil {
+		return helm.Values{}, nil
+	}
+	return helm.ParseValues(raw.Raw)
+}
+
+// ImageRegistryValues returns the values which pass the container registry to a chart.
+// Most charts follow the `global.imageRegistry` convention.
+func ImageRegistryValues(registry string) helm.Values {
+	if registry == "" {
+		return helm.Values{}
+	}
+	return helm.Values{"global": map[string]interface{}{"imageRegistry": registry}}
+}
+
+// Apply applies the rendered objects. Namespaced objects in the namespace of the owner are
+// owned by it, so that they are garbage collected with it.
+func Apply(ctx context.Context, applier *apply.Applier, owner metav1.Object, ownerGVK schema.GroupVersionKind, objs []*unstructured.Unstructured) error {
+	for _, obj := range objs {
+		obj = obj.DeepCopy()
+		namespaced, err := applier.IsNamespaced(obj.GroupVersionKind())
+		if err != nil {
+			return err
+		}
+		if namespaced && obj.GetNamespace() == owner.GetNamespace() {
+			obj.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(owner, ownerGVK)})
+		}
+		if _, err := applier.Apply(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteUnowned deletes the rendered objects which are not garbage collected with the owner,
+// that is cluster-scoped objects and objects in other namespaces.
+func DeleteUnowned(ctx context.Context, applier *apply.Applier, owner metav1.Object, objs []*unstructured.Unstructured) error {
+	for i := len(objs) - 1; i >= 0; i-- {
+		obj := objs[i].DeepCopy()
+		namespaced, err := applier.IsNamespaced(obj.GroupVersionKind())
+		if meta.IsNoMatchError(err) {
+			// the resource type is gone, so is the object.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if namespaced && obj.GetNamespace() == owner.GetNamespace() {
+			continue
+		}
+		if err := applier.Delete(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chart
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/carlory/firefly/pkg/util/apply"
+	clientutil "github.com/carlory/firefly/pkg/util/client"
+)
+
+// inventoryKey is the key of the inventory ConfigMap which holds the applied objects.
+const inventoryKey = "objects"
+
+// objectReference is a reference to an object applied from a chart.
+type objectReference struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func referenceOf(obj *unstructured.Unstructured) objectReference {
+	return objectReference{APIVersion: obj.GetAPIVersion(), Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName()}
+}
+
+func (r objectReference) object() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(r.APIVersion)
+	obj.SetKind(r.Kind)
+	obj.SetNamespace(r.Namespace)
+	obj.SetName(r.Name)
+	return obj
+}
+
+// inventoryName returns the name of the ConfigMap which records the objects applied from the chart of the owner.
+func inventoryName(owner metav1.Object, ownerGVK schema.GroupVersionKind) string {
+	return fmt.Sprintf("%s-%s-chart-inventory", strings.ToLower(ownerGVK.Kind), owner.GetName())
+}
+
+// Install applies the rendered objects like Apply, and records them in the inventory of the owner, a
+// ConfigMap in its namespace. The objects recorded by the previous install which are not rendered anymore,
+// e.g. dropped from a new version of the chart, are pruned. The inventory records the objects before they're
+// applied, so that an object is never left behind by a failed install.
+func Install(ctx context.Context, client kubernetes.Interface, applier *apply.Applier, owner metav1.Object, ownerGVK schema.GroupVersionKind, objs []*unstructured.Unstructured) error {
+	previous, _, err := readInventory(ctx, client, owner, ownerGVK)
+	if err != nil {
+		return err
+	}
+	rendered := make([]objectReference, 0, len(objs))
+	renderedSet := map[objectReference]bool{}
+	for _, obj := range objs {
+		ref := referenceOf(obj)
+		rendered = append(rendered, ref)
+		renderedSet[ref] = true
+	}
+	var pruned []objectReference
+	for _, ref := range previous {
+		if !renderedSet[ref] {
+			pruned = append(pruned, ref)
+		}
+	}
+
+	if err := writeInventory(ctx, client, owner, ownerGVK, append(append([]objectReference{}, rendered...), pruned...)); err != nil {
+		return err
+	}
+	if err := Apply(ctx, applier, owner, ownerGVK, objs); err != nil {
+		return err
+	}
+	for i := len(pruned) - 1; i >= 0; i-- {
+		if err := applier.Delete(ctx, pruned[i].object()); err != nil {
+			return err
+		}
+	}
+	if len(pruned) == 0 {
+		return nil
+	}
+	return writeInventory(ctx, client, owner, ownerGVK, rendered)
+}
+
+// Uninstall deletes the objects recorded in the inventory of the owner which are not garbage collected with
+// it, that is cluster-scoped objects and objects in other namespaces. The chart isn't rendered again, so that
+// neither an unreachable repository nor a removed version blocks the deletion of the owner. It returns false
+// if the owner has no inventory, e.g. its chart was installed before the inventory was recorded.
+func Uninstall(ctx context.Context, client kubernetes.Interface, applier *apply.Applier, owner metav1.Object, ownerGVK schema.GroupVersionKind) (bool, error) {
+	refs, found, err := readInventory(ctx, client, owner, ownerGVK)
+	if err != nil || !found {
+		return false, err
+	}
+	objs := make([]*unstructured.Unstructured, 0, len(refs))
+	for _, ref := range refs {
+		objs = append(objs, ref.object())
+	}
+	return true, DeleteUnowned(ctx, applier, owner, objs)
+}
+
+// readInventory returns the objects recorded in the inventory of the owner, and whether it exists.
+func readInventory(ctx context.Context, client kubernetes.Interface, owner metav1.Object, ownerGVK schema.GroupVersionKind) ([]objectReference, bool, error) {
+	cm, err := client.CoreV1().ConfigMaps(owner.GetNamespace()).Get(ctx, inventoryName(owner, ownerGVK), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var refs []objectReference
+	if data := cm.Data[inventoryKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), &refs); err != nil {
+			return nil, false, fmt.Errorf("failed to parse the chart inventory %s/%s: %v", cm.Namespace, cm.Name, err)
+		}
+	}
+	return refs, true, nil
+}
+
+// writeInventory records the objects in the inventory of the owner, which is owned by the owner.
+func writeInventory(ctx context.Context, client kubernetes.Interface, owner metav1.Object, ownerGVK schema.GroupVersionKind, refs []objectReference) error {
+	data, err := json.Marshal(refs)
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            inventoryName(owner, ownerGVK),
+			Namespace:       owner.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(owner, ownerGVK)},
+		},
+		Data: map[string]string{inventoryKey: string(data)},
+	}
+	_, err = clientutil.CreateOrUpdateConfigMap(ctx, client, cm)
+	return err
+}
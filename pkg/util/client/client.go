@@ -18,102 +18,315 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"strings"
 
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	flowcontrolv1beta2 "k8s.io/api/flowcontrol/v1beta2"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	aggregator "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
+
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/features"
+	"github.com/carlory/firefly/pkg/util/adoption"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/dryrun"
 )
 
-// CreateOrUpdateService creates or updates a service
-func CreateOrUpdateService(client kubernetes.Interface, svc *corev1.Service) error {
-	got, err := client.CoreV1().Services(svc.Namespace).Get(context.TODO(), svc.Name, metav1.GetOptions{})
-	if err != nil {
-		if !errors.IsNotFound(err) {
-			return err
+// OperationResult is the action taken on an object by the CreateOrUpdate functions.
+type OperationResult string
+
+const (
+	// OperationResultNone means that the object is not changed.
+	OperationResultNone OperationResult = "unchanged"
+	// OperationResultCreated means that the object is created.
+	OperationResultCreated OperationResult = "created"
+	// OperationResultUpdated means that the object is updated.
+	OperationResultUpdated OperationResult = "updated"
+	// OperationResultAdopted means that a pre-existing object is adopted and updated.
+	OperationResultAdopted OperationResult = "adopted"
+)
+
+// RecordOperationResult emits a Normal event on the owner if the object is created or updated.
+// Nothing is emitted for a dry-run operation, which is only audited.
+func RecordOperationResult(ctx context.Context, recorder record.EventRecorder, owner, obj runtime.Object, result OperationResult) {
+	if dryrun.Enabled(ctx) {
+		return
+	}
+	var reason string
+	switch result {
+	case OperationResultCreated:
+		reason = "ComponentCreated"
+	case OperationResultUpdated:
+		reason = "ComponentUpdated"
+	case OperationResultAdopted:
+		reason = "ComponentAdopted"
+	default:
+		return
+	}
+
+	// the objects passed to the CreateOrUpdate functions are typed, whose type names are their kinds.
+	kind := reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	name := ""
+	if accessor, err := meta.Accessor(obj); err == nil {
+		name = accessor.GetName()
+		if ns := accessor.GetNamespace(); ns != "" {
+			name = fmt.Sprintf("%s/%s", ns, name)
 		}
-		_, err = client.CoreV1().Services(svc.Namespace).Create(context.TODO(), svc, metav1.CreateOptions{})
-		return err
 	}
-	svc.ResourceVersion = got.ResourceVersion
-	_, err = client.CoreV1().Services(svc.Namespace).Update(context.TODO(), svc, metav1.UpdateOptions{})
-	return err
+	recorder.Eventf(owner, corev1.EventTypeNormal, reason, "%s %s %s", kind, name, result)
 }
 
-// CreateOrUpdateDeployment creates or updates a deployment
-func CreateOrUpdateDeployment(client kubernetes.Interface, deployment *appsv1.Deployment) error {
-	got, err := client.AppsV1().Deployments(deployment.Namespace).Get(context.TODO(), deployment.Name, metav1.GetOptions{})
-	if err != nil {
-		if !errors.IsNotFound(err) {
-			return err
+// CreateOrUpdateService creates or patches a service, it's recreated if its cluster IP is changed
+func CreateOrUpdateService(ctx context.Context, client kubernetes.Interface, svc *corev1.Service) (OperationResult, error) {
+	return CreateOrPatch[*corev1.Service](ctx, client.CoreV1().Services(svc.Namespace), "Service", svc, serviceRecreated)
+}
+
+// CreateOrUpdateDeployment creates or patches a deployment. If only the args of its containers are changed
+// since it was applied last time, which is told by its DeploymentSpecHashAnnotation, they're patched alone.
+// The deployment is recreated if its selector, which is immutable, is changed, e.g. an adopted one.
+func CreateOrUpdateDeployment(ctx context.Context, client kubernetes.Interface, deployment *appsv1.Deployment) (OperationResult, error) {
+	if err := setDeploymentSpecHash(deployment); err != nil {
+		return OperationResultNone, err
+	}
+	deployments := client.AppsV1().Deployments(deployment.Namespace)
+	if utilfeature.DefaultFeatureGate.Enabled(features.DeploymentArgsPatch) {
+		got, err := deployments.Get(ctx, deployment.Name, metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return OperationResultNone, err
+		}
+		if err == nil {
+			if patch, ok := deploymentArgsPatch(got, deployment); ok {
+				adopted, err := adoption.Check(ctx, "Deployment", got, deployment)
+				if err != nil {
+					return OperationResultNone, err
+				}
+				if !adopted {
+					return patchDeploymentArgs(ctx, client, got, patch)
+				}
+			}
 		}
-		_, err = client.AppsV1().Deployments(deployment.Namespace).Create(context.TODO(), deployment, metav1.CreateOptions{})
-		return err
 	}
-	deployment.ResourceVersion = got.ResourceVersion
-	_, err = client.AppsV1().Deployments(deployment.Namespace).Update(context.TODO(), deployment, metav1.UpdateOptions{})
-	return err
+	return CreateOrPatch[*appsv1.Deployment](ctx, deployments, "Deployment", deployment, func(current, desired *appsv1.Deployment) bool {
+		return !equality.Semantic.DeepEqual(current.Spec.Selector, desired.Spec.Selector)
+	})
 }
 
-// CreateOrUpdateStatefulSet creates or updates a statefulset
-func CreateOrUpdateStatefulSet(client kubernetes.Interface, statefulset *appsv1.StatefulSet) error {
-	got, err := client.AppsV1().StatefulSets(statefulset.Namespace).Get(context.TODO(), statefulset.Name, metav1.GetOptions{})
+// patchDeploymentArgs patches the args of the containers of the current deployment by the json patch of
+// deploymentArgsPatch, without touching the rest of the deployment.
+func patchDeploymentArgs(ctx context.Context, client kubernetes.Interface, current *appsv1.Deployment, patch []byte) (OperationResult, error) {
+	updated, err := client.AppsV1().Deployments(current.Namespace).Patch(ctx, current.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
 	if err != nil {
-		if !errors.IsNotFound(err) {
-			return err
-		}
-		_, err = client.AppsV1().StatefulSets(statefulset.Namespace).Create(context.TODO(), statefulset, metav1.CreateOptions{})
-		return err
+		return OperationResultNone, err
 	}
-	statefulset.ResourceVersion = got.ResourceVersion
-	_, err = client.AppsV1().StatefulSets(statefulset.Namespace).Update(context.TODO(), statefulset, metav1.UpdateOptions{})
-	return err
+	if !audit.RecordUpdate(ctx, current, updated) {
+		return OperationResultNone, nil
+	}
+	return OperationResultUpdated, nil
 }
 
-// CreateOrUpdateSecret creates or updates a secret
-func CreateOrUpdateSecret(client kubernetes.Interface, secret *corev1.Secret) error {
-	got, err := client.CoreV1().Secrets(secret.Namespace).Get(context.TODO(), secret.Name, metav1.GetOptions{})
+// setDeploymentSpecHash sets the hash of the deployment except the args of its containers
+// into the DeploymentSpecHashAnnotation of the deployment.
+func setDeploymentSpecHash(deployment *appsv1.Deployment) error {
+	stripped := deployment.DeepCopy()
+	delete(stripped.Annotations, constants.DeploymentSpecHashAnnotation)
+	for i := range stripped.Spec.Template.Spec.InitContainers {
+		stripped.Spec.Template.Spec.InitContainers[i].Args = nil
+	}
+	for i := range stripped.Spec.Template.Spec.Containers {
+		stripped.Spec.Template.Spec.Containers[i].Args = nil
+	}
+	data, err := json.Marshal(struct {
+		Labels      map[string]string
+		Annotations map[string]string
+		Spec        appsv1.DeploymentSpec
+	}{stripped.Labels, stripped.Annotations, stripped.Spec})
 	if err != nil {
-		if !errors.IsNotFound(err) {
-			return err
-		}
-		_, err = client.CoreV1().Secrets(secret.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
 		return err
 	}
-	secret.ResourceVersion = got.ResourceVersion
-	_, err = client.CoreV1().Secrets(secret.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
-	return err
+	hasher := fnv.New32a()
+	hasher.Write(data)
+	if deployment.Annotations == nil {
+		deployment.Annotations = map[string]string{}
+	}
+	deployment.Annotations[constants.DeploymentSpecHashAnnotation] = strconv.FormatUint(uint64(hasher.Sum32()), 16)
+	return nil
 }
 
-// CreateOrUpdateConfigMap creates or updates a configmap
-func CreateOrUpdateConfigMap(client kubernetes.Interface, cm *corev1.ConfigMap) error {
-	got, err := client.CoreV1().ConfigMaps(cm.Namespace).Get(context.TODO(), cm.Name, metav1.GetOptions{})
-	if err != nil {
-		if !errors.IsNotFound(err) {
-			return err
+// deploymentArgsPatch returns a json patch replacing the args of the containers of the current
+// deployment with the desired ones, if they're the only difference between the desired deployment
+// and the one applied last time. The patch fails if the deployment is changed in the meantime.
+func deploymentArgsPatch(current, desired *appsv1.Deployment) ([]byte, bool) {
+	hash := desired.Annotations[constants.DeploymentSpecHashAnnotation]
+	if hash == "" || current.Annotations[constants.DeploymentSpecHashAnnotation] != hash {
+		return nil, false
+	}
+	currentContainers := current.Spec.Template.Spec.Containers
+	desiredContainers := desired.Spec.Template.Spec.Containers
+	if len(currentContainers) != len(desiredContainers) || !equalContainerArgs(current.Spec.Template.Spec.InitContainers, desired.Spec.Template.Spec.InitContainers) {
+		return nil, false
+	}
+
+	type operation struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}
+	ops := []operation{{Op: "test", Path: "/metadata/resourceVersion", Value: current.ResourceVersion}}
+	args := len(ops)
+	for i := range desiredContainers {
+		if currentContainers[i].Name != desiredContainers[i].Name {
+			return nil, false
 		}
-		_, err = client.CoreV1().ConfigMaps(cm.Namespace).Create(context.TODO(), cm, metav1.CreateOptions{})
-		return err
+		if reflect.DeepEqual(currentContainers[i].Args, desiredContainers[i].Args) {
+			continue
+		}
+		path := fmt.Sprintf("/spec/template/spec/containers/%d", i)
+		ops = append(ops,
+			operation{Op: "test", Path: path + "/name", Value: desiredContainers[i].Name},
+			operation{Op: "add", Path: path + "/args", Value: desiredContainers[i].Args},
+		)
+	}
+	if len(ops) == args {
+		// nothing but the args could be changed, so the deployment is patched as a whole to revert
+		// the changes made by others.
+		return nil, false
+	}
+	// the configuration applied last time is kept up to date, so that the next three-way merge patch
+	// doesn't take the args as changed.
+	applied, err := lastAppliedConfiguration("Deployment", desired)
+	if err != nil {
+		return nil, false
 	}
-	cm.ResourceVersion = got.ResourceVersion
-	_, err = client.CoreV1().ConfigMaps(cm.Namespace).Update(context.TODO(), cm, metav1.UpdateOptions{})
-	return err
+	ops = append(ops, operation{Op: "add", Path: "/metadata/annotations/" + jsonPointerEscaper.Replace(constants.LastAppliedConfigurationAnnotation), Value: string(applied)})
+	patch, err := json.Marshal(ops)
+	if err != nil {
+		return nil, false
+	}
+	return patch, true
 }
 
-// CreateOrUpdateAPIService creates or updates an apiservice
-func CreateOrUpdateAPIService(client aggregator.Interface, apisvc *apiregistrationv1.APIService) error {
-	got, err := client.ApiregistrationV1().APIServices().Get(context.TODO(), apisvc.Name, metav1.GetOptions{})
-	if err != nil {
-		if !errors.IsNotFound(err) {
-			return err
+// jsonPointerEscaper escapes a key as a reference token of a json pointer.
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// equalContainerArgs returns whether the containers of both lists have the same names and args.
+func equalContainerArgs(a, b []corev1.Container) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || !reflect.DeepEqual(a[i].Args, b[i].Args) {
+			return false
 		}
-		_, err = client.ApiregistrationV1().APIServices().Create(context.TODO(), apisvc, metav1.CreateOptions{})
-		return err
 	}
-	apisvc.ResourceVersion = got.ResourceVersion
-	_, err = client.ApiregistrationV1().APIServices().Update(context.TODO(), apisvc, metav1.UpdateOptions{})
-	return err
+	return true
+}
+
+// CreateOrUpdateStatefulSet creates or updates a statefulset
+func CreateOrUpdateStatefulSet(ctx context.Context, client kubernetes.Interface, statefulset *appsv1.StatefulSet) (OperationResult, error) {
+	return CreateOrPatch[*appsv1.StatefulSet](ctx, client.AppsV1().StatefulSets(statefulset.Namespace), "StatefulSet", statefulset, nil)
+}
+
+// CreateOrUpdateSecret creates or updates a secret
+func CreateOrUpdateSecret(ctx context.Context, client kubernetes.Interface, secret *corev1.Secret) (OperationResult, error) {
+	return CreateOrPatch[*corev1.Secret](ctx, client.CoreV1().Secrets(secret.Namespace), "Secret", secret, nil)
+}
+
+// CreateOrUpdateIngress creates or updates an ingress
+func CreateOrUpdateIngress(ctx context.Context, client kubernetes.Interface, ingress *networkingv1.Ingress) (OperationResult, error) {
+	return CreateOrPatch[*networkingv1.Ingress](ctx, client.NetworkingV1().Ingresses(ingress.Namespace), "Ingress", ingress, nil)
+}
+
+// CreateOrUpdateHorizontalPodAutoscaler creates or updates a horizontal pod autoscaler
+func CreateOrUpdateHorizontalPodAutoscaler(ctx context.Context, client kubernetes.Interface, hpa *autoscalingv2.HorizontalPodAutoscaler) (OperationResult, error) {
+	return CreateOrPatch[*autoscalingv2.HorizontalPodAutoscaler](ctx, client.AutoscalingV2().HorizontalPodAutoscalers(hpa.Namespace), "HorizontalPodAutoscaler", hpa, nil)
+}
+
+// CreateOrUpdatePodDisruptionBudget creates or updates a pod disruption budget
+func CreateOrUpdatePodDisruptionBudget(ctx context.Context, client kubernetes.Interface, pdb *policyv1.PodDisruptionBudget) (OperationResult, error) {
+	return CreateOrPatch[*policyv1.PodDisruptionBudget](ctx, client.PolicyV1().PodDisruptionBudgets(pdb.Namespace), "PodDisruptionBudget", pdb, nil)
+}
+
+// CreateOrUpdateNetworkPolicy creates or updates a network policy
+func CreateOrUpdateNetworkPolicy(ctx context.Context, client kubernetes.Interface, policy *networkingv1.NetworkPolicy) (OperationResult, error) {
+	return CreateOrPatch[*networkingv1.NetworkPolicy](ctx, client.NetworkingV1().NetworkPolicies(policy.Namespace), "NetworkPolicy", policy, nil)
+}
+
+// CreateOrUpdateConfigMap creates or updates a configmap
+func CreateOrUpdateConfigMap(ctx context.Context, client kubernetes.Interface, cm *corev1.ConfigMap) (OperationResult, error) {
+	return CreateOrPatch[*corev1.ConfigMap](ctx, client.CoreV1().ConfigMaps(cm.Namespace), "ConfigMap", cm, nil)
+}
+
+// CreateOrUpdateClusterRole creates or updates a cluster role
+func CreateOrUpdateClusterRole(ctx context.Context, client kubernetes.Interface, clusterRole *rbacv1.ClusterRole) (OperationResult, error) {
+	return CreateOrPatch[*rbacv1.ClusterRole](ctx, client.RbacV1().ClusterRoles(), "ClusterRole", clusterRole, nil)
+}
+
+// CreateOrUpdateAPIService creates or updates an apiservice
+func CreateOrUpdateAPIService(ctx context.Context, client aggregator.Interface, apisvc *apiregistrationv1.APIService) (OperationResult, error) {
+	return CreateOrPatch[*apiregistrationv1.APIService](ctx, client.ApiregistrationV1().APIServices(), "APIService", apisvc, nil)
+}
+
+// CreateOrUpdateCronJob creates or updates a cronjob
+func CreateOrUpdateCronJob(ctx context.Context, client kubernetes.Interface, cronJob *batchv1.CronJob) (OperationResult, error) {
+	return CreateOrPatch[*batchv1.CronJob](ctx, client.BatchV1().CronJobs(cronJob.Namespace), "CronJob", cronJob, nil)
+}
+
+// CreateOrUpdateFlowSchema creates or updates a flowschema
+func CreateOrUpdateFlowSchema(ctx context.Context, client kubernetes.Interface, flowSchema *flowcontrolv1beta2.FlowSchema) (OperationResult, error) {
+	return CreateOrPatch[*flowcontrolv1beta2.FlowSchema](ctx, client.FlowcontrolV1beta2().FlowSchemas(), "FlowSchema", flowSchema, nil)
+}
+
+// CreateOrUpdateNamespace creates or patches a namespace
+func CreateOrUpdateNamespace(ctx context.Context, client kubernetes.Interface, ns *corev1.Namespace) (OperationResult, error) {
+	return CreateOrPatch[*corev1.Namespace](ctx, client.CoreV1().Namespaces(), "Namespace", ns, nil)
+}
+
+// CreateOrUpdateServiceAccount creates or patches a service account
+func CreateOrUpdateServiceAccount(ctx context.Context, client kubernetes.Interface, sa *corev1.ServiceAccount) (OperationResult, error) {
+	return CreateOrPatch[*corev1.ServiceAccount](ctx, client.CoreV1().ServiceAccounts(sa.Namespace), "ServiceAccount", sa, nil)
+}
+
+// CreateOrUpdateClusterRoleBinding creates or patches a cluster role binding, it's recreated if its role is changed
+func CreateOrUpdateClusterRoleBinding(ctx context.Context, client kubernetes.Interface, crb *rbacv1.ClusterRoleBinding) (OperationResult, error) {
+	return CreateOrPatch[*rbacv1.ClusterRoleBinding](ctx, client.RbacV1().ClusterRoleBindings(), "ClusterRoleBinding", crb, func(current, desired *rbacv1.ClusterRoleBinding) bool {
+		return current.RoleRef != desired.RoleRef
+	})
+}
+
+// CreateOrUpdateRoleBinding creates or patches a role binding, it's recreated if its role is changed
+func CreateOrUpdateRoleBinding(ctx context.Context, client kubernetes.Interface, rb *rbacv1.RoleBinding) (OperationResult, error) {
+	return CreateOrPatch[*rbacv1.RoleBinding](ctx, client.RbacV1().RoleBindings(rb.Namespace), "RoleBinding", rb, func(current, desired *rbacv1.RoleBinding) bool {
+		return current.RoleRef != desired.RoleRef
+	})
+}
+
+// CreateOrUpdateValidatingWebhookConfiguration creates or patches a validating webhook configuration
+func CreateOrUpdateValidatingWebhookConfiguration(ctx context.Context, client kubernetes.Interface, config *admissionregistrationv1.ValidatingWebhookConfiguration) (OperationResult, error) {
+	return CreateOrPatch[*admissionregistrationv1.ValidatingWebhookConfiguration](ctx, client.AdmissionregistrationV1().ValidatingWebhookConfigurations(), "ValidatingWebhookConfiguration", config, nil)
+}
+
+// CreateOrUpdateMutatingWebhookConfiguration creates or patches a mutating webhook configuration
+func CreateOrUpdateMutatingWebhookConfiguration(ctx context.Context, client kubernetes.Interface, config *admissionregistrationv1.MutatingWebhookConfiguration) (OperationResult, error) {
+	return CreateOrPatch[*admissionregistrationv1.MutatingWebhookConfiguration](ctx, client.AdmissionregistrationV1().MutatingWebhookConfigurations(), "MutatingWebhookConfiguration", config, nil)
 }
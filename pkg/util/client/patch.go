@@ -0,0 +1,268 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	"github.com/carlory/firefly/pkg/constants"
+	"github.com/carlory/firefly/pkg/util/adoption"
+	"github.com/carlory/firefly/pkg/util/audit"
+	"github.com/carlory/firefly/pkg/util/dryrun"
+)
+
+// object is a typed object, e.g. *corev1.Service.
+type object interface {
+	runtime.Object
+	metav1.Object
+}
+
+// resourceClient is the part of a typed client of client-go used by CreateOrPatch, which is implemented
+// by e.g. client.CoreV1().Services(namespace).
+type resourceClient[T object] interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (T, error)
+	Create(ctx context.Context, obj T, opts metav1.CreateOptions) (T, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (T, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+}
+
+// RecreateFunc returns whether the current object has to be deleted and created again to become the
+// desired one, since the fields they differ in are immutable.
+type RecreateFunc[T object] func(current, desired T) bool
+
+// CreateOrPatch creates the desired object, or patches the current one into it by a three-way strategic
+// merge patch of the object applied last time, the desired object and the current object. So the fields
+// dropped from the desired object are removed, while the fields set by others, e.g. the defaults and the
+// labels added by other controllers, are kept, and nothing is sent if the object is up to date. The object
+// is deleted and created again if recreate tells that it differs in immutable fields.
+//
+// An object owned by others is only patched if its adoption is enabled, and the owners of an adopted
+// object are replaced with the desired ones.
+func CreateOrPatch[T object](ctx context.Context, client resourceClient[T], kind string, desired T, recreate RecreateFunc[T]) (OperationResult, error) {
+	modified, err := lastAppliedConfiguration(kind, desired)
+	if err != nil {
+		return OperationResultNone, err
+	}
+	annotations := desired.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[constants.LastAppliedConfigurationAnnotation] = string(modified)
+	desired.SetAnnotations(annotations)
+
+	got, err := client.Get(ctx, desired.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return OperationResultNone, err
+		}
+		if _, err := client.Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+			return OperationResultNone, err
+		}
+		audit.Record(ctx, audit.Create, desired, nil)
+		return OperationResultCreated, nil
+	}
+	adopted, err := adoption.Check(ctx, kind, got, desired)
+	if err != nil {
+		return OperationResultNone, err
+	}
+	if recreate != nil && recreate(got, desired) {
+		return recreateObject(ctx, client, got, desired, adopted)
+	}
+
+	patch, err := threeWayMergePatch(got, desired, adopted)
+	if err != nil {
+		return OperationResultNone, fmt.Errorf("failed to compute the patch of %s %s: %v", kind, desired.GetName(), err)
+	}
+	if string(patch) == "{}" {
+		return OperationResultNone, nil
+	}
+	updated, err := client.Patch(ctx, desired.GetName(), types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return OperationResultNone, err
+	}
+	if adopted {
+		audit.RecordUpdate(ctx, got, updated)
+		return OperationResultAdopted, nil
+	}
+	if !audit.RecordUpdate(ctx, got, updated) {
+		return OperationResultNone, nil
+	}
+	return OperationResultUpdated, nil
+}
+
+// threeWayMergePatch returns the strategic merge patch turning the current object into the desired one.
+// The owners of an adopted object are removed unless they're desired, as if they were applied last time.
+func threeWayMergePatch[T object](current, desired T, adopted bool) ([]byte, error) {
+	original := []byte(current.GetAnnotations()[constants.LastAppliedConfigurationAnnotation])
+	if adopted {
+		originalMap := map[string]interface{}{}
+		if len(original) > 0 {
+			if err := json.Unmarshal(original, &originalMap); err != nil {
+				return nil, err
+			}
+		}
+		metadata, _ := originalMap["metadata"].(map[string]interface{})
+		if metadata == nil {
+			metadata = map[string]interface{}{}
+		}
+		metadata["ownerReferences"] = current.GetOwnerReferences()
+		originalMap["metadata"] = metadata
+		data, err := json.Marshal(originalMap)
+		if err != nil {
+			return nil, err
+		}
+		original = data
+	}
+	modified, err := appliedJSON(desired)
+	if err != nil {
+		return nil, err
+	}
+	currentData, err := appliedJSON(current)
+	if err != nil {
+		return nil, err
+	}
+	lookupPatchMeta, err := strategicpatch.NewPatchMetaFromStruct(desired)
+	if err != nil {
+		return nil, err
+	}
+	return strategicpatch.CreateThreeWayMergePatch(original, modified, currentData, lookupPatchMeta, true)
+}
+
+// recreateObject deletes the current object and creates the desired one in its place. The deletion
+// is preconditioned on the uid of the current object, so that an object recreated in the meantime
+// is not deleted. The desired object can't be created by a dry-run, since the current one is kept.
+func recreateObject[T object](ctx context.Context, client resourceClient[T], current, desired T, adopted bool) (OperationResult, error) {
+	err := client.Delete(ctx, current.GetName(), metav1.DeleteOptions{
+		Preconditions: metav1.NewUIDPreconditions(string(current.GetUID())),
+	})
+	audit.RecordResult(ctx, audit.Delete, current, err)
+	if err != nil && !errors.IsNotFound(err) {
+		return OperationResultNone, err
+	}
+	if !dryrun.Enabled(ctx) {
+		if _, err := client.Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+			return OperationResultNone, err
+		}
+	}
+	audit.Record(ctx, audit.Create, desired, nil)
+	if adopted {
+		return OperationResultAdopted, nil
+	}
+	return OperationResultUpdated, nil
+}
+
+// lastAppliedConfiguration returns the value of the LastAppliedConfigurationAnnotation of the desired
+// object. The values of the data of a Secret are left out, so that they're not copied into its metadata,
+// only its keys are kept to tell the dropped ones.
+func lastAppliedConfiguration(kind string, desired object) ([]byte, error) {
+	content, err := appliedContent(desired)
+	if err != nil {
+		return nil, err
+	}
+	if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, constants.LastAppliedConfigurationAnnotation)
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+	if kind == "Secret" {
+		for _, field := range []string{"data", "stringData"} {
+			if data, ok := content[field].(map[string]interface{}); ok {
+				for key := range data {
+					data[key] = ""
+				}
+			}
+		}
+	}
+	return json.Marshal(content)
+}
+
+// appliedJSON returns the JSON of the fields of the object which are applied.
+func appliedJSON(obj object) ([]byte, error) {
+	content, err := appliedContent(obj)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(content)
+}
+
+// appliedContent returns the fields of the object which are applied, that is the object except its type
+// meta, its status and the fields managed by the apiserver.
+func appliedContent(obj object) (map[string]interface{}, error) {
+	copied, ok := obj.DeepCopyObject().(object)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T", obj)
+	}
+	copied.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+	copied.SetResourceVersion("")
+	copied.SetUID("")
+	copied.SetGeneration(0)
+	copied.SetCreationTimestamp(metav1.Time{})
+	copied.SetManagedFields(nil)
+	copied.SetSelfLink("")
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(copied)
+	if err != nil {
+		return nil, err
+	}
+	delete(content, "status")
+	return pruneNulls(content), nil
+}
+
+// pruneNulls removes the null fields of the content recursively, e.g. the zero creationTimestamp, which
+// would be taken as the fields to delete by a patch.
+func pruneNulls(content map[string]interface{}) map[string]interface{} {
+	for key, value := range content {
+		switch value := value.(type) {
+		case nil:
+			delete(content, key)
+		case map[string]interface{}:
+			pruneNulls(value)
+		case []interface{}:
+			for _, item := range value {
+				if item, ok := item.(map[string]interface{}); ok {
+					pruneNulls(item)
+				}
+			}
+		}
+	}
+	return content
+}
+
+// serviceRecreated returns whether the service has to be recreated to become the desired one, since the
+// cluster IP of a service is immutable, e.g. a headless service can't get a cluster IP allocated.
+func serviceRecreated(current, desired *corev1.Service) bool {
+	if desired.Spec.Type == corev1.ServiceTypeExternalName || current.Spec.Type == corev1.ServiceTypeExternalName {
+		return false
+	}
+	if desired.Spec.ClusterIP == "" {
+		return current.Spec.ClusterIP == corev1.ClusterIPNone
+	}
+	return desired.Spec.ClusterIP != current.Spec.ClusterIP
+}
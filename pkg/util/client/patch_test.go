@@ -0,0 +1,277 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	"github.com/carlory/firefly/pkg/constants"
+)
+
+// fakeClient is an in-memory resourceClient, which applies the strategic merge patches like the apiserver
+// and bumps the resource version of an object only if it's changed.
+type fakeClient[T object] struct {
+	objects  map[string]T
+	revision int
+
+	patches []string
+	deletes []metav1.DeleteOptions
+}
+
+func newFakeClient[T object]() *fakeClient[T] {
+	return &fakeClient[T]{objects: map[string]T{}}
+}
+
+func (c *fakeClient[T]) Get(ctx context.Context, name string, opts metav1.GetOptions) (T, error) {
+	obj, ok := c.objects[name]
+	if !ok {
+		var zero T
+		return zero, errors.NewNotFound(schema.GroupResource{}, name)
+	}
+	return obj.DeepCopyObject().(T), nil
+}
+
+func (c *fakeClient[T]) Create(ctx context.Context, obj T, opts metav1.CreateOptions) (T, error) {
+	if _, ok := c.objects[obj.GetName()]; ok {
+		var zero T
+		return zero, errors.NewAlreadyExists(schema.GroupResource{}, obj.GetName())
+	}
+	created := obj.DeepCopyObject().(T)
+	c.revision++
+	created.SetUID(types.UID(fmt.Sprintf("uid-%d", c.revision)))
+	created.SetResourceVersion(strconv.Itoa(c.revision))
+	c.objects[obj.GetName()] = created
+	return created.DeepCopyObject().(T), nil
+}
+
+func (c *fakeClient[T]) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (T, error) {
+	var zero T
+	current, ok := c.objects[name]
+	if !ok {
+		return zero, errors.NewNotFound(schema.GroupResource{}, name)
+	}
+	if pt != types.StrategicMergePatchType {
+		return zero, fmt.Errorf("unexpected patch type %s", pt)
+	}
+	c.patches = append(c.patches, string(data))
+
+	original, err := json.Marshal(current)
+	if err != nil {
+		return zero, err
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, data, current)
+	if err != nil {
+		return zero, err
+	}
+	updated := reflect.New(reflect.TypeOf(current).Elem()).Interface().(T)
+	if err := json.Unmarshal(patched, updated); err != nil {
+		return zero, err
+	}
+	if !equality.Semantic.DeepEqual(current, updated) {
+		c.revision++
+		updated.SetResourceVersion(strconv.Itoa(c.revision))
+	}
+	c.objects[name] = updated
+	return updated.DeepCopyObject().(T), nil
+}
+
+func (c *fakeClient[T]) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	c.deletes = append(c.deletes, opts)
+	current, ok := c.objects[name]
+	if !ok {
+		return errors.NewNotFound(schema.GroupResource{}, name)
+	}
+	if opts.Preconditions != nil && opts.Preconditions.UID != nil && *opts.Preconditions.UID != current.GetUID() {
+		return errors.NewConflict(schema.GroupResource{}, name, fmt.Errorf("the uid precondition failed"))
+	}
+	delete(c.objects, name)
+	return nil
+}
+
+func newService(labels map[string]string, clusterIP string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", Labels: labels},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: clusterIP,
+			Ports:     []corev1.ServicePort{{Name: "https", Port: 443}},
+		},
+	}
+}
+
+func TestCreateOrPatch(t *testing.T) {
+	tests := []struct {
+		name string
+		// applied is the service applied before, nil if it doesn't exist.
+		applied *corev1.Service
+		// changed changes the applied service by others, e.g. the apiserver or other controllers.
+		changed      func(svc *corev1.Service)
+		desired      *corev1.Service
+		wantResult   OperationResult
+		wantLabels   map[string]string
+		wantPatch    bool
+		wantRecreate bool
+	}{
+		{
+			name:       "create",
+			desired:    newService(map[string]string{"app": "test"}, ""),
+			wantResult: OperationResultCreated,
+			wantLabels: map[string]string{"app": "test"},
+		},
+		{
+			name:    "no-op",
+			applied: newService(map[string]string{"app": "test"}, ""),
+			changed: func(svc *corev1.Service) {
+				svc.Spec.ClusterIP = "10.0.0.1"
+			},
+			desired:    newService(map[string]string{"app": "test"}, ""),
+			wantResult: OperationResultNone,
+			wantLabels: map[string]string{"app": "test"},
+		},
+		{
+			name:       "patch",
+			applied:    newService(map[string]string{"app": "test"}, ""),
+			desired:    newService(map[string]string{"app": "test", "tier": "control-plane"}, ""),
+			wantResult: OperationResultUpdated,
+			wantLabels: map[string]string{"app": "test", "tier": "control-plane"},
+			wantPatch:  true,
+		},
+		{
+			name:    "field removal via the last applied configuration",
+			applied: newService(map[string]string{"app": "test", "tier": "control-plane"}, ""),
+			changed: func(svc *corev1.Service) {
+				svc.Labels["owner"] = "others"
+			},
+			desired:    newService(map[string]string{"app": "test"}, ""),
+			wantResult: OperationResultUpdated,
+			wantLabels: map[string]string{"app": "test", "owner": "others"},
+			wantPatch:  true,
+		},
+		{
+			name:         "recreate on an immutable field change",
+			applied:      newService(map[string]string{"app": "test"}, corev1.ClusterIPNone),
+			desired:      newService(map[string]string{"app": "test"}, ""),
+			wantResult:   OperationResultUpdated,
+			wantLabels:   map[string]string{"app": "test"},
+			wantRecreate: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.TODO()
+			client := newFakeClient[*corev1.Service]()
+			var uid types.UID
+			if test.applied != nil {
+				if _, err := CreateOrPatch[*corev1.Service](ctx, client, "Service", test.applied, serviceRecreated); err != nil {
+					t.Fatalf("failed to apply the service: %v", err)
+				}
+				current := client.objects[test.applied.Name]
+				if test.changed != nil {
+					test.changed(current)
+				}
+				uid = current.UID
+			}
+
+			result, err := CreateOrPatch[*corev1.Service](ctx, client, "Service", test.desired, serviceRecreated)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != test.wantResult {
+				t.Errorf("expected result %q, got %q", test.wantResult, result)
+			}
+			got := client.objects[test.desired.Name]
+			if !reflect.DeepEqual(got.Labels, test.wantLabels) {
+				t.Errorf("expected labels %v, got %v", test.wantLabels, got.Labels)
+			}
+			if test.wantPatch != (len(client.patches) > 0) {
+				t.Errorf("expected a patch sent: %v, got patches %v", test.wantPatch, client.patches)
+			}
+
+			if !test.wantRecreate {
+				if len(client.deletes) > 0 {
+					t.Errorf("expected no deletion, got %d", len(client.deletes))
+				}
+				return
+			}
+			if len(client.deletes) != 1 {
+				t.Fatalf("expected 1 deletion, got %d", len(client.deletes))
+			}
+			preconditions := client.deletes[0].Preconditions
+			if preconditions == nil || preconditions.UID == nil || *preconditions.UID != uid {
+				t.Errorf("expected the deletion preconditioned on uid %q, got %+v", uid, preconditions)
+			}
+			if got.UID == uid {
+				t.Errorf("expected the service recreated with a new uid")
+			}
+			if got.Spec.ClusterIP != "" {
+				t.Errorf("expected the cluster IP of the recreated service unset, got %q", got.Spec.ClusterIP)
+			}
+		})
+	}
+}
+
+func TestCreateOrPatchSecret(t *testing.T) {
+	ctx := context.TODO()
+	client := newFakeClient[*corev1.Secret]()
+	newSecret := func(data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+			Data:       data,
+		}
+	}
+
+	if _, err := CreateOrPatch[*corev1.Secret](ctx, client, "Secret", newSecret(map[string][]byte{
+		"tls.crt": []byte("cert"),
+		"tls.key": []byte("key"),
+	}), nil); err != nil {
+		t.Fatalf("failed to apply the secret: %v", err)
+	}
+	got := client.objects["test"]
+	applied := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(got.Annotations[constants.LastAppliedConfigurationAnnotation]), &applied); err != nil {
+		t.Fatalf("failed to decode the last applied configuration: %v", err)
+	}
+	if want := map[string]interface{}{"tls.crt": "", "tls.key": ""}; !reflect.DeepEqual(applied["data"], want) {
+		t.Errorf("expected the values of the data blanked in the last applied configuration, got %v", applied["data"])
+	}
+
+	result, err := CreateOrPatch[*corev1.Secret](ctx, client, "Secret", newSecret(map[string][]byte{
+		"tls.crt": []byte("renewed"),
+	}), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != OperationResultUpdated {
+		t.Errorf("expected result %q, got %q", OperationResultUpdated, result)
+	}
+	if want := map[string][]byte{"tls.crt": []byte("renewed")}; !equality.Semantic.DeepEqual(client.objects["test"].Data, want) {
+		t.Errorf("expected data %v, got %v", want, client.objects["test"].Data)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package coalesce provides a work queue which collapses the bursts of events of secondary objects,
+// e.g. the status updates of a karmada which are observed by all the controllers of its addons, into
+// a single reconcile of each key.
+package coalesce
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// DefaultWindow is how long a coalesced key waits for the rest of a burst before it's processed.
+	DefaultWindow = time.Second
+	// DefaultInterval is the minimum interval between two coalesced reconciles of the same key.
+	DefaultInterval = 5 * time.Second
+)
+
+// Queue wraps a rate limiting queue. The keys added by AddCoalesced are debounced by the window and
+// limited to one reconcile per interval, while the keys added by Add are processed as soon as possible,
+// e.g. when the reconciled object itself is changed.
+type Queue struct {
+	workqueue.RateLimitingInterface
+
+	clock    clock.Clock
+	window   time.Duration
+	interval time.Duration
+
+	lock sync.Mutex
+	// scheduled is the time at which each coalesced key was last scheduled to be processed.
+	scheduled map[interface{}]time.Time
+}
+
+// NewQueue returns a Queue which wraps the given queue with the given window and interval.
+func NewQueue(queue workqueue.RateLimitingInterface, window, interval time.Duration) *Queue {
+	return &Queue{
+		RateLimitingInterface: queue,
+		clock:                 clock.RealClock{},
+		window:                window,
+		interval:              interval,
+		scheduled:             map[interface{}]time.Time{},
+	}
+}
+
+// AddCoalesced adds the item after the window, unless it's already scheduled. If the item was processed
+// less than an interval ago, it's delayed until the interval has passed.
+func (q *Queue) AddCoalesced(item interface{}) {
+	now := q.clock.Now()
+
+	q.lock.Lock()
+	last, ok := q.scheduled[item]
+	if ok && last.After(now) {
+		q.lock.Unlock()
+		return
+	}
+	at := now.Add(q.window)
+	if ok && last.Add(q.interval).After(at) {
+		at = last.Add(q.interval)
+	}
+	q.scheduled[item] = at
+	q.lock.Unlock()
+
+	q.RateLimitingInterface.AddAfter(item, at.Sub(now))
+}
+
+// Forget stops tracking the item once its interval has passed, so that the keys of the deleted objects
+// don't pile up.
+func (q *Queue) Forget(item interface{}) {
+	q.lock.Lock()
+	if last, ok := q.scheduled[item]; ok && q.clock.Since(last) > q.interval {
+		delete(q.scheduled, item)
+	}
+	q.lock.Unlock()
+	q.RateLimitingInterface.Forget(item)
+}
+
+// Changed returns true if the update of an object may require an immediate reconcile, that is its spec,
+// labels, annotations, finalizers or deletion timestamp are changed. Other updates, e.g. the ones of its
+// status, can be coalesced.
+func Changed(old, cur metav1.Object) bool {
+	return old.GetGeneration() != cur.GetGeneration() ||
+		!reflect.DeepEqual(old.GetLabels(), cur.GetLabels()) ||
+		!reflect.DeepEqual(old.GetAnnotations(), cur.GetAnnotations()) ||
+		!reflect.DeepEqual(old.GetFinalizers(), cur.GetFinalizers()) ||
+		!old.GetDeletionTimestamp().Equal(cur.GetDeletionTimestamp())
+}
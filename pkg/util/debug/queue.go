@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Queue wraps a rate limiting queue to keep track of the keys in it, so that
+// the contents of the queue can be exposed by the debugging handler.
+type Queue struct {
+	workqueue.RateLimitingInterface
+
+	lock sync.Mutex
+	// waiting holds the keys which are queued, or will be queued after a delay.
+	waiting sets.String
+	// processing holds the keys which are being processed by the workers.
+	processing sets.String
+}
+
+// NewQueue returns a Queue which wraps the given queue.
+func NewQueue(queue workqueue.RateLimitingInterface) *Queue {
+	return &Queue{
+		RateLimitingInterface: queue,
+		waiting:               sets.NewString(),
+		processing:            sets.NewString(),
+	}
+}
+
+// Add marks item as needing processing.
+func (q *Queue) Add(item interface{}) {
+	q.wait(item)
+	q.RateLimitingInterface.Add(item)
+}
+
+// AddAfter adds an item to the queue after the indicated duration has passed.
+func (q *Queue) AddAfter(item interface{}, duration time.Duration) {
+	q.wait(item)
+	q.RateLimitingInterface.AddAfter(item, duration)
+}
+
+// AddRateLimited adds an item to the queue after the rate limiter says it's ok.
+func (q *Queue) AddRateLimited(item interface{}) {
+	q.wait(item)
+	q.RateLimitingInterface.AddRateLimited(item)
+}
+
+// Get blocks until it can return an item to be processed.
+func (q *Queue) Get() (interface{}, bool) {
+	item, shutdown := q.RateLimitingInterface.Get()
+	if !shutdown {
+		key := fmt.Sprint(item)
+		q.lock.Lock()
+		q.waiting.Delete(key)
+		q.processing.Insert(key)
+		q.lock.Unlock()
+	}
+	return item, shutdown
+}
+
+// Done marks item as done processing.
+func (q *Queue) Done(item interface{}) {
+	q.lock.Lock()
+	q.processing.Delete(fmt.Sprint(item))
+	q.lock.Unlock()
+	q.RateLimitingInterface.Done(item)
+}
+
+func (q *Queue) wait(item interface{}) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.waiting.Insert(fmt.Sprint(item))
+}
+
+// QueueStatus is the contents of a Queue.
+type QueueStatus struct {
+	Length     int      `json:"length"`
+	Waiting    []string `json:"waiting"`
+	Processing []string `json:"processing"`
+}
+
+// Status returns the contents of the queue.
+func (q *Queue) Status() QueueStatus {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return QueueStatus{
+		Length:     q.Len(),
+		Waiting:    q.waiting.List(),
+		Processing: q.processing.List(),
+	}
+}
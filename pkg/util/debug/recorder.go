@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/carlory/firefly/pkg/util/diff"
+)
+
+// Result is the result of the last reconcile of a key.
+type Result struct {
+	Time     time.Time `json:"time"`
+	Duration string    `json:"duration"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Status is the debugging information of a controller.
+type Status struct {
+	Queue   QueueStatus       `json:"queue"`
+	Results map[string]Result `json:"results"`
+	// Diffs holds the last change of the desired state of the objects managed by the controller.
+	Diffs map[string]string `json:"diffs"`
+}
+
+// Recorder records the reconcile results and the desired states computed by a controller,
+// and serves them together with the contents of its queue as JSON.
+type Recorder struct {
+	queue *Queue
+
+	lock    sync.RWMutex
+	results map[string]Result
+	// desired holds the last desired state of the objects keyed by the reconciled key,
+	// and then by the reference of the object.
+	desired map[string]map[string][]byte
+	diffs   map[string]map[string]string
+}
+
+var _ http.Handler = &Recorder{}
+
+// NewRecorder returns a Recorder of the controller which processes the given queue.
+func NewRecorder(queue *Queue) *Recorder {
+	return &Recorder{
+		queue:   queue,
+		results: make(map[string]Result),
+		desired: make(map[string]map[string][]byte),
+		diffs:   make(map[string]map[string]string),
+	}
+}
+
+// ObserveReconcile records the result of a reconcile of the key which started at startTime.
+func (r *Recorder) ObserveReconcile(key string, startTime time.Time, err error) {
+	result := Result{Time: startTime, Duration: time.Since(startTime).String()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.results[key] = result
+}
+
+// ObserveDesiredState records the desired state of an object computed when reconciling
+// the key, the change from the previous desired state of the object is kept as a diff.
+// Secrets are never recorded, their data must not be exposed by the debugging handler.
+func (r *Recorder) ObserveDesiredState(key string, obj runtime.Object) {
+	if _, ok := obj.(*corev1.Secret); ok {
+		return
+	}
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return
+	}
+	ref := reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	if accessor, err := meta.Accessor(obj); err == nil {
+		ref = path.Join(ref, accessor.GetNamespace(), accessor.GetName())
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.desired[key] == nil {
+		r.desired[key] = make(map[string][]byte)
+		r.diffs[key] = make(map[string]string)
+	}
+	if old, ok := r.desired[key][ref]; ok && string(old) != string(data) {
+		r.diffs[key][ref] = diff.YAML(yamlObject(old), yamlObject(data))
+	}
+	r.desired[key][ref] = data
+}
+
+// Forget drops everything recorded for the key, it's called when the object of the key is gone.
+func (r *Recorder) Forget(key string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.results, key)
+	delete(r.desired, key)
+	delete(r.diffs, key)
+}
+
+// Status returns the debugging information of the controller.
+func (r *Recorder) Status() Status {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	status := Status{
+		Queue:   r.queue.Status(),
+		Results: make(map[string]Result, len(r.results)),
+		Diffs:   make(map[string]string),
+	}
+	for key, result := range r.results {
+		status.Results[key] = result
+	}
+	for key, diffs := range r.diffs {
+		for ref, d := range diffs {
+			status.Diffs[key+" "+ref] = d
+		}
+	}
+	return status
+}
+
+// ServeHTTP serves the debugging information of the controller as JSON.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	serveJSON(w, r.Status())
+}
+
+// Handler returns a handler which serves the debugging information of several controllers
+// as JSON, it's used when a group of controllers is started together.
+func Handler(recorders map[string]*Recorder) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		statuses := make(map[string]Status, len(recorders))
+		for name, recorder := range recorders {
+			statuses[name] = recorder.Status()
+		}
+		serveJSON(w, statuses)
+	})
+}
+
+func serveJSON(w http.ResponseWriter, v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// yamlObject unmarshals the yaml so that the diff is computed on the same
+// representation of the objects.
+func yamlObject(data []byte) interface{} {
+	var obj interface{}
+	_ = yaml.Unmarshal(data, &obj)
+	return obj
+}
@@ -17,6 +17,11 @@ limitations under the License.
 package diff
 
 import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
 	"github.com/go-git/go-git/v5/utils/diff"
 	"github.com/sergi/go-diff/diffmatchpatch"
 	"sigs.k8s.io/yaml"
@@ -31,3 +36,89 @@ func PrettyYAML(src, dst interface{}) string {
 	dmp := diffmatchpatch.New()
 	return dmp.DiffPrettyText(diffs)
 }
+
+// YAML computes the (line oriented) modifications needed to turn the src into
+// the dst and reports the changed lines of the yaml prefixed by "-" or "+".
+func YAML(src, dst interface{}) string {
+	old, _ := yaml.Marshal(src)
+	new, _ := yaml.Marshal(dst)
+	var b strings.Builder
+	for _, d := range diff.Do(string(old), string(new)) {
+		var prefix string
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			prefix = "-"
+		case diffmatchpatch.DiffInsert:
+			prefix = "+"
+		default:
+			continue
+		}
+		for _, line := range strings.SplitAfter(d.Text, "\n") {
+			if line != "" {
+				b.WriteString(prefix + line)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Paths reports the paths of the fields which differ between the src and the dst,
+// e.g. spec.template.spec.containers. Lists are compared as a whole, and fields of
+// metadata maintained by the apiserver are ignored.
+func Paths(src, dst interface{}) []string {
+	old, err := toMap(src)
+	if err != nil {
+		return nil
+	}
+	new, err := toMap(dst)
+	if err != nil {
+		return nil
+	}
+	for _, m := range []map[string]interface{}{old, new} {
+		if metadata, ok := m["metadata"].(map[string]interface{}); ok {
+			for _, field := range ignoredMetadataFields {
+				delete(metadata, field)
+			}
+		}
+	}
+	var paths []string
+	walk("", old, new, &paths)
+	sort.Strings(paths)
+	return paths
+}
+
+var ignoredMetadataFields = []string{"resourceVersion", "generation", "managedFields", "creationTimestamp", "uid"}
+
+func toMap(obj interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	return m, json.Unmarshal(data, &m)
+}
+
+func walk(prefix string, old, new map[string]interface{}, paths *[]string) {
+	keys := map[string]struct{}{}
+	for k := range old {
+		keys[k] = struct{}{}
+	}
+	for k := range new {
+		keys[k] = struct{}{}
+	}
+	for k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		oldChild, oldIsMap := old[k].(map[string]interface{})
+		newChild, newIsMap := new[k].(map[string]interface{})
+		if oldIsMap && newIsMap {
+			walk(path, oldChild, newChild, paths)
+			continue
+		}
+		if !reflect.DeepEqual(old[k], new[k]) {
+			*paths = append(*paths, path)
+		}
+	}
+}
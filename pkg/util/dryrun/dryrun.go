@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"context"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/transport"
+
+	"github.com/carlory/firefly/pkg/constants"
+)
+
+type dryRunKey struct{}
+
+// WithDryRun returns a copy of ctx under which the mutations are only validated by the apiserver,
+// as long as the rest config of the client is wrapped by WrapperFor.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// Enabled returns whether the mutations performed with ctx are dry-run.
+func Enabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(dryRunKey{}).(bool)
+	return enabled
+}
+
+// ForObject returns a dry-run copy of ctx if the object is annotated with constants.DryRunAnnotation,
+// so that the reconciles of the object are previewed. Otherwise ctx is returned as is.
+func ForObject(ctx context.Context, obj metav1.Object) context.Context {
+	if obj.GetAnnotations()[constants.DryRunAnnotation] == "true" {
+		return WithDryRun(ctx)
+	}
+	return ctx
+}
+
+// WrapperFor returns a wrapper of the rest config transport which sends the mutating
+// requests as server-side dry-run requests if they are sent with a dry-run ctx.
+func WrapperFor() transport.WrapperFunc {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &roundTripper{delegate: rt}
+	}
+}
+
+type roundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !Enabled(req.Context()) {
+		return rt.delegate.RoundTrip(req)
+	}
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return rt.delegate.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the request, so it's cloned.
+	req = req.Clone(req.Context())
+	query := req.URL.Query()
+	query.Set("dryRun", metav1.DryRunAll)
+	req.URL.RawQuery = query.Encode()
+	return rt.delegate.RoundTrip(req)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expectations
+
+import "sync"
+
+// Registry hands out the expectations of the controllers started by a controller manager. The controllers
+// which are registered under the same name, e.g. the workers of a controller which is split into several
+// loops, share the same expectations.
+type Registry struct {
+	lock         sync.Mutex
+	expectations map[string]*UIDTrackingControllerExpectations
+}
+
+// NewRegistry returns an empty registry of expectations.
+func NewRegistry() *Registry {
+	return &Registry{expectations: map[string]*UIDTrackingControllerExpectations{}}
+}
+
+// For returns the expectations of the named controller, they are created on the first call.
+func (r *Registry) For(controllerName string) *UIDTrackingControllerExpectations {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	exp, ok := r.expectations[controllerName]
+	if !ok {
+		exp = NewUIDTrackingControllerExpectations(NewControllerExpectations())
+		r.expectations[controllerName] = exp
+	}
+	return exp
+}
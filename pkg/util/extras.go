@@ -0,0 +1,46 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+)
+
+// ApplyComponentExtras adds the extra volumes of a component to its pod, and the extra volume mounts and
+// environment variables to its container, which is the first container of the pod. The extra environment
+// variables replace the generated ones of the same names.
+func ApplyComponentExtras(podSpec *corev1.PodSpec, extras installv1alpha1.ComponentExtras) {
+	if len(podSpec.Containers) == 0 {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, extras.ExtraVolumes...)
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, extras.ExtraVolumeMounts...)
+	for _, env := range extras.Env {
+		replaced := false
+		for i := range container.Env {
+			if container.Env[i].Name == env.Name {
+				container.Env[i], replaced = env, true
+			}
+		}
+		if !replaced {
+			container.Env = append(container.Env, env)
+		}
+	}
+}
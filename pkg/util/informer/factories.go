@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informer
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+)
+
+// FilteredFactories holds the shared informer factories which are built with options, such as
+// a namespace or a label selector, for the controllers which only care about a small part of
+// the resources. Each controller gets its own factory, so that the informers of a controller
+// only list and watch the resources it asks for.
+type FilteredFactories struct {
+	client         clientset.Interface
+	metadataClient metadata.Interface
+	resyncPeriod   func() time.Duration
+
+	lock              sync.Mutex
+	factories         map[string]informers.SharedInformerFactory
+	metadataFactories map[string]metadatainformer.SharedInformerFactory
+}
+
+// NewFilteredFactories returns a new FilteredFactories which builds the factories with the given clients.
+func NewFilteredFactories(client clientset.Interface, metadataClient metadata.Interface, resyncPeriod func() time.Duration) *FilteredFactories {
+	return &FilteredFactories{
+		client:            client,
+		metadataClient:    metadataClient,
+		resyncPeriod:      resyncPeriod,
+		factories:         make(map[string]informers.SharedInformerFactory),
+		metadataFactories: make(map[string]metadatainformer.SharedInformerFactory),
+	}
+}
+
+// ForController returns the shared informer factory of the named controller. The factory is built
+// with the given options on the first call, the options of the subsequent calls are ignored.
+func (f *FilteredFactories) ForController(name string, options ...informers.SharedInformerOption) informers.SharedInformerFactory {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	factory, ok := f.factories[name]
+	if !ok {
+		factory = informers.NewSharedInformerFactoryWithOptions(f.client, f.resyncPeriod(), options...)
+		f.factories[name] = factory
+	}
+	return factory
+}
+
+// MetadataForController returns the metadata-only shared informer factory of the named controller,
+// which is enough for the controllers that only look at the labels, owners or existence of the objects.
+// The factory is built with the given namespace and options on the first call, the arguments of the
+// subsequent calls are ignored.
+func (f *FilteredFactories) MetadataForController(name, namespace string, tweakListOptions metadatainformer.TweakListOptionsFunc) metadatainformer.SharedInformerFactory {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	factory, ok := f.metadataFactories[name]
+	if !ok {
+		factory = metadatainformer.NewFilteredSharedInformerFactory(f.metadataClient, f.resyncPeriod(), namespace, tweakListOptions)
+		f.metadataFactories[name] = factory
+	}
+	return factory
+}
+
+// Start initializes all requested informers of the factories.
+func (f *FilteredFactories) Start(stopCh <-chan struct{}) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, factory := range f.factories {
+		factory.Start(stopCh)
+	}
+	for _, factory := range f.metadataFactories {
+		factory.Start(stopCh)
+	}
+}
+
+// WaitForCacheSync waits for the caches of all started informers of the factories to be synced,
+// it returns false if stopCh is closed before that.
+func (f *FilteredFactories) WaitForCacheSync(stopCh <-chan struct{}) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, factory := range f.factories {
+		if !AllSynced(factory.WaitForCacheSync(stopCh)) {
+			return false
+		}
+	}
+	for _, factory := range f.metadataFactories {
+		if !AllResourcesSynced(factory.WaitForCacheSync(stopCh)) {
+			return false
+		}
+	}
+	return true
+}
+
+// AllSynced reports whether all the informers in the result of WaitForCacheSync of a
+// typed shared informer factory are synced.
+func AllSynced(synced map[reflect.Type]bool) bool {
+	for _, ok := range synced {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// AllResourcesSynced reports whether all the informers in the result of WaitForCacheSync
+// of a dynamic or metadata-only shared informer factory are synced.
+func AllResourcesSynced(synced map[schema.GroupVersionResource]bool) bool {
+	for _, ok := range synced {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
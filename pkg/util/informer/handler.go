@@ -0,0 +1,33 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informer
+
+import (
+	"time"
+
+	"k8s.io/client-go/tools/cache"
+)
+
+// AddEventHandler adds the handler to the shared informer. The handler is resynced at the given period
+// if it's not zero, otherwise at the resync period of the shared informer.
+func AddEventHandler(informer cache.SharedInformer, handler cache.ResourceEventHandler, resyncPeriod time.Duration) {
+	if resyncPeriod == 0 {
+		informer.AddEventHandler(handler)
+		return
+	}
+	informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informer
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Getter is implemented by the typed and generic informers of the shared informer factories.
+type Getter interface {
+	Informer() cache.SharedIndexInformer
+}
+
+// StripUnusedFields is a cache.TransformFunc which drops the metadata that the controllers
+// never read, such as managedFields and the last-applied-configuration annotation, before
+// the objects are stored in the informer caches.
+func StripUnusedFields(obj interface{}) (interface{}, error) {
+	target := obj
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		target = tombstone.Obj
+	}
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		// not a kubernetes object, keep it as it is.
+		return obj, nil
+	}
+
+	accessor.SetManagedFields(nil)
+	if annotations := accessor.GetAnnotations(); annotations != nil {
+		if _, ok := annotations[corev1.LastAppliedConfigAnnotation]; ok {
+			delete(annotations, corev1.LastAppliedConfigAnnotation)
+			accessor.SetAnnotations(annotations)
+		}
+	}
+	return obj, nil
+}
+
+// transformed holds the informers StripUnusedFields is installed on, so that it's installed only once on
+// the informers shared by several controllers, which may have been started by the ones started first.
+var transformed sync.Map
+
+// SetTransform installs StripUnusedFields on the given informers. It must be called before
+// the shared informer factories are started, unless it's installed on the informers already.
+func SetTransform(informers ...Getter) error {
+	for _, getter := range informers {
+		informer := getter.Informer()
+		if _, ok := transformed.Load(informer); ok {
+			continue
+		}
+		if err := informer.SetTransform(StripUnusedFields); err != nil {
+			return err
+		}
+		transformed.Store(informer, true)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kms envelope-encrypts the sensitive data of the secrets generated by firefly with a key
+// encryption key held by an external KMS, so that the data is encrypted at rest even if the host
+// cluster doesn't encrypt the secrets itself. Each value is encrypted with a data encryption key,
+// which is encrypted by the KMS and stored along with the value. Only the values read by firefly
+// itself can be encrypted, the ones mounted into the components are kept in plain text.
+package kms
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apiserver/pkg/storage/value"
+	"k8s.io/apiserver/pkg/storage/value/encrypt/aes"
+	"k8s.io/apiserver/pkg/storage/value/encrypt/envelope"
+)
+
+// encryptedPrefix is the prefix of the values encrypted by an Encrypter.
+const encryptedPrefix = "firefly:enc:kms:v1:"
+
+// cacheSize is the number of the decrypted data encryption keys kept in memory.
+const cacheSize = 1000
+
+// Provider encrypts and decrypts the data encryption keys with a key encryption key held by an external KMS.
+type Provider interface {
+	Encrypt(data []byte) ([]byte, error)
+	Decrypt(data []byte) ([]byte, error)
+}
+
+// Factory returns a provider which talks to the KMS at the endpoint, each call times out after the timeout.
+type Factory func(endpoint string, timeout time.Duration) (Provider, error)
+
+// KMSv1 is the name of the provider talking to a Kubernetes KMS v1 plugin, e.g. the plugins of the
+// cloud providers or Vault, through a unix socket like unix:///var/run/kmsplugin/socket.sock.
+const KMSv1 = "kmsv1"
+
+var (
+	factoriesLock sync.RWMutex
+	factories     = map[string]Factory{
+		KMSv1: func(endpoint string, timeout time.Duration) (Provider, error) {
+			return envelope.NewGRPCService(endpoint, timeout)
+		},
+	}
+)
+
+// RegisterProvider registers the factory of a provider under the name, so that platforms embedding
+// firefly can plug their own KMS in.
+func RegisterProvider(name string, factory Factory) {
+	factoriesLock.Lock()
+	defer factoriesLock.Unlock()
+	factories[name] = factory
+}
+
+// Providers returns the sorted names of the registered providers.
+func Providers() []string {
+	factoriesLock.RLock()
+	defer factoriesLock.RUnlock()
+	var names []string
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewProvider returns the provider of the given name which talks to the KMS at the endpoint.
+func NewProvider(name, endpoint string, timeout time.Duration) (Provider, error) {
+	factoriesLock.RLock()
+	factory, ok := factories[name]
+	factoriesLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown KMS provider %q", name)
+	}
+	return factory(endpoint, timeout)
+}
+
+// Encrypter envelope-encrypts the values with the data encryption keys encrypted by a provider.
+type Encrypter struct {
+	transformer value.Transformer
+}
+
+// NewEncrypter returns an encrypter whose data encryption keys are encrypted by the provider.
+func NewEncrypter(provider Provider) (*Encrypter, error) {
+	transformer, err := envelope.NewEnvelopeTransformer(provider, cacheSize, aes.NewGCMTransformer)
+	if err != nil {
+		return nil, err
+	}
+	return &Encrypter{transformer: transformer}, nil
+}
+
+// Encrypt encrypts the value. The location, e.g. the namespace, name and key of the secret holding the
+// value, is authenticated, so that the encrypted value can't be moved elsewhere.
+func (e *Encrypter) Encrypt(ctx context.Context, data []byte, location string) ([]byte, error) {
+	encrypted, err := e.transformer.TransformToStorage(ctx, data, value.DefaultContext(location))
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(encryptedPrefix), encrypted...), nil
+}
+
+// Decrypt decrypts the value encrypted for the location, the values which aren't encrypted are returned as is.
+func (e *Encrypter) Decrypt(ctx context.Context, data []byte, location string) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	decrypted, _, err := e.transformer.TransformFromStorage(ctx, data[len(encryptedPrefix):], value.DefaultContext(location))
+	return decrypted, err
+}
+
+// IsEncrypted returns whether the value is encrypted by an Encrypter.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptedPrefix))
+}
+
+var (
+	lock     sync.RWMutex
+	defaults *Encrypter
+)
+
+// SetDefault sets the encrypter of the secrets generated by the controllers.
+func SetDefault(encrypter *Encrypter) {
+	lock.Lock()
+	defer lock.Unlock()
+	defaults = encrypter
+}
+
+// Default returns the encrypter of the secrets generated by the controllers, it's nil if the secrets
+// aren't encrypted.
+func Default() *Encrypter {
+	lock.RLock()
+	defer lock.RUnlock()
+	return defaults
+}
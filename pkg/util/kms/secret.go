@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kms
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// location returns the location of the key of the data of the secret.
+func location(secret *corev1.Secret, key string) string {
+	return path.Join(secret.Namespace, secret.Name, key)
+}
+
+// EncryptSecretData encrypts the given keys of the data of the secret with the default encrypter in place,
+// and returns whether any of them is encrypted. Nothing is encrypted if secrets encryption isn't configured,
+// and the keys which are already encrypted or missing are skipped.
+func EncryptSecretData(ctx context.Context, secret *corev1.Secret, keys ...string) (bool, error) {
+	encrypter := Default()
+	if encrypter == nil {
+		return false, nil
+	}
+	var encrypted bool
+	for _, key := range keys {
+		data, ok := secret.Data[key]
+		if stringData, found := secret.StringData[key]; found {
+			// the string data overwrites the data on write.
+			data, ok = []byte(stringData), true
+		}
+		if !ok || IsEncrypted(data) {
+			continue
+		}
+		ciphertext, err := encrypter.Encrypt(ctx, data, location(secret, key))
+		if err != nil {
+			return false, fmt.Errorf("failed to encrypt %s of secret %s/%s: %v", key, secret.Namespace, secret.Name, err)
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[key] = ciphertext
+		delete(secret.StringData, key)
+		encrypted = true
+	}
+	return encrypted, nil
+}
+
+// SecretData returns the key of the data of the secret, which is decrypted if it's encrypted.
+func SecretData(ctx context.Context, secret *corev1.Secret, key string) ([]byte, error) {
+	data := secret.Data[key]
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	encrypter := Default()
+	if encrypter == nil {
+		return nil, fmt.Errorf("%s of secret %s/%s is encrypted by a KMS, but secrets encryption isn't configured", key, secret.Namespace, secret.Name)
+	}
+	plaintext, err := encrypter.Decrypt(ctx, data, location(secret, key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s of secret %s/%s: %v", key, secret.Namespace, secret.Name, err)
+	}
+	return plaintext, nil
+}
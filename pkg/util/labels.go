@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/carlory/firefly/pkg/constants"
+)
+
+// SetKarmadaInstanceLabel labels the object with the name of the karmada it belongs to, and
+// marks it as managed by firefly. The pod templates of workloads are labeled with the karmada
+// as well, but their selectors are left untouched because they're immutable.
+func SetKarmadaInstanceLabel(obj metav1.Object, karmadaName string) {
+	labels := withKarmadaInstanceLabel(obj.GetLabels(), karmadaName)
+	labels[constants.ManagedByLabel] = constants.ManagedByFirefly
+	obj.SetLabels(labels)
+
+	var template *corev1.PodTemplateSpec
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		template = &o.Spec.Template
+	case *appsv1.StatefulSet:
+		template = &o.Spec.Template
+	case *appsv1.DaemonSet:
+		template = &o.Spec.Template
+	}
+	if template != nil {
+		template.Labels = withKarmadaInstanceLabel(template.Labels, karmadaName)
+	}
+}
+
+func withKarmadaInstanceLabel(labels map[string]string, karmadaName string) map[string]string {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[constants.KarmadaInstanceLabel] = karmadaName
+	return labels
+}
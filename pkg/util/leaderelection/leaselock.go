@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leaderelection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clientset "k8s.io/client-go/kubernetes"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// LeaseLock is a resourcelock.LeaseLock which labels the lease it holds, so that the leases of
+// several firefly deployments sharing a namespace can be told apart.
+type LeaseLock struct {
+	// LeaseMeta should contain a Name and a Namespace of a Lease object that the LeaderElector
+	// will attempt to lead. Its Labels are set on the lease when it is created or updated.
+	LeaseMeta  metav1.ObjectMeta
+	Client     coordinationv1client.LeasesGetter
+	LockConfig resourcelock.ResourceLockConfig
+	lease      *coordinationv1.Lease
+}
+
+var _ resourcelock.Interface = &LeaseLock{}
+
+// Get returns the election record from a Lease spec
+func (ll *LeaseLock) Get(ctx context.Context) (*resourcelock.LeaderElectionRecord, []byte, error) {
+	var err error
+	ll.lease, err = ll.Client.Leases(ll.LeaseMeta.Namespace).Get(ctx, ll.LeaseMeta.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	record := resourcelock.LeaseSpecToLeaderElectionRecord(&ll.lease.Spec)
+	recordByte, err := json.Marshal(*record)
+	if err != nil {
+		return nil, nil, err
+	}
+	return record, recordByte, nil
+}
+
+// Create attempts to create a labeled Lease
+func (ll *LeaseLock) Create(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	var err error
+	ll.lease, err = ll.Client.Leases(ll.LeaseMeta.Namespace).Create(ctx, &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ll.LeaseMeta.Name,
+			Namespace: ll.LeaseMeta.Namespace,
+			Labels:    ll.LeaseMeta.Labels,
+		},
+		Spec: resourcelock.LeaderElectionRecordToLeaseSpec(&ler),
+	}, metav1.CreateOptions{})
+	return err
+}
+
+// Update will update an existing Lease spec, and adds the labels the lease is missing.
+func (ll *LeaseLock) Update(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	if ll.lease == nil {
+		return errors.New("lease not initialized, call get or create first")
+	}
+	ll.lease.Spec = resourcelock.LeaderElectionRecordToLeaseSpec(&ler)
+	for key, value := range ll.LeaseMeta.Labels {
+		if ll.lease.Labels == nil {
+			ll.lease.Labels = make(map[string]string, len(ll.LeaseMeta.Labels))
+		}
+		ll.lease.Labels[key] = value
+	}
+
+	lease, err := ll.Client.Leases(ll.LeaseMeta.Namespace).Update(ctx, ll.lease, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+
+	ll.lease = lease
+	return nil
+}
+
+// RecordEvent in leader election while adding meta-data
+func (ll *LeaseLock) RecordEvent(s string) {
+	ll.recordEvent("LeaderElection", fmt.Sprintf("%v %v", ll.LockConfig.Identity, s))
+}
+
+func (ll *LeaseLock) recordEvent(reason, message string) {
+	if ll.LockConfig.EventRecorder == nil || ll.lease == nil {
+		return
+	}
+	subject := &coordinationv1.Lease{ObjectMeta: ll.lease.ObjectMeta}
+	// Populate the type meta, so we don't have to get it from the schema
+	subject.Kind = "Lease"
+	subject.APIVersion = coordinationv1.SchemeGroupVersion.String()
+	ll.LockConfig.EventRecorder.Eventf(subject, corev1.EventTypeNormal, reason, message)
+}
+
+// Describe is used to convert details on current resource lock
+// into a string
+func (ll *LeaseLock) Describe() string {
+	return fmt.Sprintf("%v/%v", ll.LeaseMeta.Namespace, ll.LeaseMeta.Name)
+}
+
+// Identity returns the Identity of the lock
+func (ll *LeaseLock) Identity() string {
+	return ll.LockConfig.Identity
+}
+
+// NewFromKubeconfig is resourcelock.NewFromKubeconfig with labels for the lease. The labels are
+// only supported by the leases lock type, the endpointsleases and configmapsleases locks are
+// still created by resourcelock.New. The returned lock reports the transitions of the lease.
+func NewFromKubeconfig(lockType string, ns string, name string, labels map[string]string, rlc resourcelock.ResourceLockConfig, kubeconfig *restclient.Config, renewDeadline time.Duration) (resourcelock.Interface, error) {
+	// shallow copy, do not modify the kubeconfig
+	config := *kubeconfig
+	timeout := renewDeadline / 2
+	if timeout < time.Second {
+		timeout = time.Second
+	}
+	config.Timeout = timeout
+	leaderElectionClient, err := clientset.NewForConfig(restclient.AddUserAgent(&config, "leader-election"))
+	if err != nil {
+		return nil, err
+	}
+
+	if lockType != resourcelock.LeasesResourceLock {
+		if len(labels) > 0 {
+			return nil, fmt.Errorf("labels are only supported by the %s lock, got %s", resourcelock.LeasesResourceLock, lockType)
+		}
+		lock, err := resourcelock.New(lockType, ns, name, leaderElectionClient.CoreV1(), leaderElectionClient.CoordinationV1(), rlc)
+		if err != nil {
+			return nil, err
+		}
+		return &transitionsLock{Interface: lock}, nil
+	}
+
+	leaseLock := &LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+			Labels:    labels,
+		},
+		Client:     leaderElectionClient.CoordinationV1(),
+		LockConfig: rlc,
+	}
+	return &transitionsLock{Interface: leaseLock, recordEvent: leaseLock.recordEvent}, nil
+}
+
+// ValidateLabels validates the labels of the leader election leases for the lock type.
+func ValidateLabels(lockType string, labels map[string]string) []error {
+	var errs []error
+	if len(labels) > 0 && lockType != resourcelock.LeasesResourceLock {
+		errs = append(errs, fmt.Errorf("leader-elect-labels are only supported by the %s lock, got %s", resourcelock.LeasesResourceLock, lockType))
+	}
+	for _, err := range metav1validation.ValidateLabels(labels, field.NewPath("leader-elect-labels")) {
+		errs = append(errs, err)
+	}
+	return errs
+}
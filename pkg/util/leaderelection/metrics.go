@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leaderelection
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	leaseTransitions = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "leader_election",
+			Name:           "lease_transitions",
+			Help:           "The number of times the leadership of the lease has changed hands, as recorded by the lease.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"name"},
+	)
+
+	registerMetrics sync.Once
+)
+
+// transitionsLock reports the leader transitions recorded by the resource lock as a metric, and
+// as an event once this candidate takes the lock over from another one.
+type transitionsLock struct {
+	resourcelock.Interface
+	// recordEvent records an event of the reason on the object of the lock. It is nil if the lock
+	// only supports the events of the leader election.
+	recordEvent func(reason, message string)
+
+	observed *resourcelock.LeaderElectionRecord
+}
+
+func (l *transitionsLock) Get(ctx context.Context) (*resourcelock.LeaderElectionRecord, []byte, error) {
+	record, recordByte, err := l.Interface.Get(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	l.observed = record
+	l.setTransitions(record.LeaderTransitions)
+	return record, recordByte, nil
+}
+
+func (l *transitionsLock) Create(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	if err := l.Interface.Create(ctx, ler); err != nil {
+		return err
+	}
+	l.setTransitions(ler.LeaderTransitions)
+	return nil
+}
+
+func (l *transitionsLock) Update(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	if err := l.Interface.Update(ctx, ler); err != nil {
+		return err
+	}
+	l.setTransitions(ler.LeaderTransitions)
+
+	// The transitions of the record are only bumped by the candidate taking the lock over.
+	if l.observed != nil && ler.LeaderTransitions > l.observed.LeaderTransitions {
+		message := fmt.Sprintf("%v took over the lock from %v, the leadership has changed hands %d times", l.Identity(), l.observed.HolderIdentity, ler.LeaderTransitions)
+		if l.recordEvent != nil {
+			l.recordEvent("LeaderTransition", message)
+		} else {
+			l.Interface.RecordEvent(fmt.Sprintf("took over the lock from %v", l.observed.HolderIdentity))
+		}
+	}
+	l.observed = &ler
+	return nil
+}
+
+func (l *transitionsLock) setTransitions(transitions int) {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(leaseTransitions)
+	})
+	leaseTransitions.WithLabelValues(l.Describe()).Set(float64(transitions))
+}
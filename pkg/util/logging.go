@@ -0,0 +1,39 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strconv"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+)
+
+// LoggingArgs returns the --v and --logging-format flags of a component configured by its logging.
+// They should be merged over the default args of the component and under its extra args.
+func LoggingArgs(logging *installv1alpha1.Logging) map[string]string {
+	args := map[string]string{}
+	if logging == nil {
+		return args
+	}
+	if logging.Verbosity != nil {
+		args["v"] = strconv.Itoa(int(*logging.Verbosity))
+	}
+	if logging.Format != "" {
+		args["logging-format"] = string(logging.Format)
+	}
+	return args
+}
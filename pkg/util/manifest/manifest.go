@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/carlory/firefly/pkg/scheme"
+)
+
+// Bundle collects the objects of a manifest bundle. It's filled by the functions which
+// build the objects, and keeps the first error returned by them.
+type Bundle struct {
+	objs []runtime.Object
+	err  error
+}
+
+// Add adds the object to the bundle, unless err or a previous error is not nil.
+func (b *Bundle) Add(obj runtime.Object, err error) {
+	if b.err != nil {
+		return
+	}
+	if err != nil {
+		b.err = err
+		return
+	}
+	b.objs = append(b.objs, obj)
+}
+
+// Objects returns the objects of the bundle, or the first error returned by the functions which built them.
+func (b *Bundle) Objects() ([]runtime.Object, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.objs, nil
+}
+
+// WriteYAML writes the objects to w as a multi-document yaml. Typed objects don't always
+// carry their kinds, so they're looked up in the scheme.
+func WriteYAML(w io.Writer, objs []runtime.Object) error {
+	for i, obj := range objs {
+		if obj.GetObjectKind().GroupVersionKind().Empty() {
+			gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+			if err != nil {
+				return err
+			}
+			obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+		}
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			if _, err := fmt.Fprintln(w, "---"); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ package util
 
 import (
 	"fmt"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -56,3 +57,17 @@ func ConvertToCommandOrArgs(m map[string]string) []string {
 	}
 	return array
 }
+
+// ConvertToFeatureGates converts a bool map to the value of a --feature-gates flag. The feature gates
+// are sorted, so that the flag is stable across reconciles.
+func ConvertToFeatureGates(m map[string]bool) string {
+	keys := sets.NewString()
+	for k := range m {
+		keys.Insert(k)
+	}
+	array := make([]string, 0, len(keys))
+	for _, v := range keys.List() {
+		array = append(array, fmt.Sprintf("%s=%t", v, m[v]))
+	}
+	return strings.Join(array, ",")
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+)
+
+var (
+	// PodMonitorGVK is the kind of the PodMonitors of the Prometheus Operator.
+	PodMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+	// PodMonitorGVR is the resource of the PodMonitors of the Prometheus Operator.
+	PodMonitorGVR = schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "podmonitors"}
+	// PrometheusRuleGVK is the kind of the PrometheusRules of the Prometheus Operator.
+	PrometheusRuleGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+	// PrometheusRuleGVR is the resource of the PrometheusRules of the Prometheus Operator.
+	PrometheusRuleGVR = schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "prometheusrules"}
+)
+
+const (
+	// ScrapeAnnotation, PortAnnotation and PathAnnotation are the annotations which Prometheus
+	// scrape configs conventionally use to discover the pods to scrape.
+	ScrapeAnnotation = "prometheus.io/scrape"
+	PortAnnotation   = "prometheus.io/port"
+	PathAnnotation   = "prometheus.io/path"
+)
+
+// Endpoint is the metrics endpoint of a component, which is served over plain http.
+type Endpoint struct {
+	// Component is the name of the component. Its pods are labeled with app=<Component>.
+	Component string
+	// Port is the port of the metrics endpoint.
+	Port int32
+	// Path is the path of the metrics endpoint.
+	Path string
+}
+
+// PodMonitorName returns the name of the PodMonitor of the component.
+func PodMonitorName(component string) string {
+	return component
+}
+
+// PodMonitor returns the PodMonitor which scrapes the endpoint of the pods in the namespace.
+func PodMonitor(namespace string, endpoint Endpoint, monitoring installv1alpha1.Monitoring) *unstructured.Unstructured {
+	podMetricsEndpoint := map[string]interface{}{
+		"targetPort": int64(endpoint.Port),
+		"path":       endpoint.Path,
+	}
+	if monitoring.Interval != "" {
+		podMetricsEndpoint["interval"] = monitoring.Interval
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"app": endpoint.Component},
+			},
+			"podMetricsEndpoints": []interface{}{podMetricsEndpoint},
+		},
+	}}
+	obj.SetGroupVersionKind(PodMonitorGVK)
+	obj.SetNamespace(namespace)
+	obj.SetName(PodMonitorName(endpoint.Component))
+	if len(monitoring.Labels) > 0 {
+		obj.SetLabels(monitoring.Labels)
+	}
+	return obj
+}
+
+// Annotate adds the scrape annotations of the endpoint to the pod template.
+func Annotate(template *corev1.PodTemplateSpec, endpoint Endpoint) {
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[ScrapeAnnotation] = "true"
+	template.Annotations[PortAnnotation] = strconv.Itoa(int(endpoint.Port))
+	template.Annotations[PathAnnotation] = endpoint.Path
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package networkpolicy builds the NetworkPolicies which restrict the traffic between the components.
+package networkpolicy
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Rule admits the traffic to some ports of the selected pods.
+type Rule struct {
+	// Ports are the TCP ports the traffic is admitted to. All ports if empty.
+	Ports []int32
+	// From are the labels of the pods in the same namespace the traffic is admitted from.
+	// Any source if empty.
+	From []map[string]string
+}
+
+// NetworkPolicy returns the NetworkPolicy with the given name, which only admits the ingress traffic
+// to the pods selected by podSelector that matches one of the rules.
+func NetworkPolicy(namespace, name string, podSelector map[string]string, rules ...Rule) *networkingv1.NetworkPolicy {
+	policy := &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.k8s.io/v1",
+			Kind:       "NetworkPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: podSelector},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     []networkingv1.NetworkPolicyIngressRule{},
+		},
+	}
+	for _, rule := range rules {
+		var ingress networkingv1.NetworkPolicyIngressRule
+		for _, port := range rule.Ports {
+			port := intstr.FromInt(int(port))
+			ingress.Ports = append(ingress.Ports, networkingv1.NetworkPolicyPort{Port: &port})
+		}
+		for _, labels := range rule.From {
+			ingress.From = append(ingress.From, networkingv1.NetworkPolicyPeer{
+				PodSelector: &metav1.LabelSelector{MatchLabels: labels},
+			})
+		}
+		policy.Spec.Ingress = append(policy.Spec.Ingress, ingress)
+	}
+	return policy
+}
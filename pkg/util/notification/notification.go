@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/carlory/firefly/pkg/util/audit"
+)
+
+// Reason is why an alert is sent.
+type Reason string
+
+const (
+	// Degraded means the Ready condition of an installation turned false without a change of its spec.
+	Degraded Reason = "Degraded"
+	// Recovered means a degraded installation is ready again.
+	Recovered Reason = "Recovered"
+	// ReconcileFailing means a controller failed to reconcile an object several times in a row.
+	ReconcileFailing Reason = "ReconcileFailing"
+)
+
+// Alert is a notification about the health of an object pushed to the external endpoints.
+type Alert struct {
+	Time   metav1.Time           `json:"time"`
+	Reason Reason                `json:"reason"`
+	Object audit.ObjectReference `json:"object"`
+	// Controller is the controller which reports the alert.
+	Controller string `json:"controller,omitempty"`
+	// Message is a human readable message indicating details about the alert.
+	Message string `json:"message,omitempty"`
+}
+
+// Sink pushes the alerts to an external endpoint.
+type Sink interface {
+	Write(alert Alert)
+}
+
+var (
+	lock  sync.Mutex
+	sinks []Sink
+	// minInterval is the min interval between the alerts of a reason for an object.
+	minInterval time.Duration
+	// reconcileFailureThreshold is the number of failed reconciles in a row which is alerted.
+	reconcileFailureThreshold int
+	// lastSent is the time the last alert of a reason for an object was sent within the min interval.
+	lastSent = map[alertKey]time.Time{}
+)
+
+type alertKey struct {
+	reason Reason
+	object audit.ObjectReference
+}
+
+// RegisterSink registers the sink which is written by all subsequent alerts.
+func RegisterSink(sink Sink) {
+	lock.Lock()
+	defer lock.Unlock()
+	sinks = append(sinks, sink)
+}
+
+// Configure sets the min interval between the alerts of a reason for an object, and the number of
+// failed reconciles in a row after which ReconcileFailed sends an alert. 0 disables the alerts of the
+// failed reconciles.
+func Configure(interval time.Duration, failureThreshold int) {
+	lock.Lock()
+	defer lock.Unlock()
+	minInterval = interval
+	reconcileFailureThreshold = failureThreshold
+}
+
+// Notify writes the alert to the registered sinks, unless an alert of the same reason was sent for the
+// object within the min interval. The alert is only logged if no sink is registered.
+func Notify(alert Alert) {
+	if alert.Time.IsZero() {
+		alert.Time = metav1.Now()
+	}
+	klog.InfoS("Alert", "reason", alert.Reason, "object", alert.Object.String(), "controller", alert.Controller, "message", alert.Message)
+
+	lock.Lock()
+	defer lock.Unlock()
+	if len(sinks) == 0 {
+		return
+	}
+	key := alertKey{reason: alert.Reason, object: alert.Object}
+	if last, ok := lastSent[key]; ok && alert.Time.Sub(last) < minInterval {
+		klog.V(4).InfoS("Alert throttled", "reason", alert.Reason, "object", alert.Object.String(), "lastSent", last)
+		return
+	}
+	// the alerts sent before the min interval don't throttle anymore, so they're pruned rather than
+	// kept for the objects which are deleted.
+	for k, last := range lastSent {
+		if alert.Time.Sub(last) >= minInterval {
+			delete(lastSent, k)
+		}
+	}
+	lastSent[key] = alert.Time.Time
+	for _, sink := range sinks {
+		sink.Write(alert)
+	}
+}
+
+// ReconcileFailed is called by the controllers whenever they fail to reconcile an object, failures is
+// the number of the failed reconciles of the object in a row. An alert is sent once it reaches the
+// configured threshold.
+func ReconcileFailed(controller string, object audit.ObjectReference, failures int, err error) {
+	lock.Lock()
+	threshold := reconcileFailureThreshold
+	lock.Unlock()
+	if threshold <= 0 || failures != threshold {
+		return
+	}
+	Notify(Alert{
+		Reason:     ReconcileFailing,
+		Object:     object,
+		Controller: controller,
+		Message:    fmt.Sprintf("failed to reconcile %s %d times in a row: %v", object.String(), failures, err),
+	})
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Format is the format of the body posted to a webhook.
+type Format string
+
+const (
+	// FormatJSON posts the alert as json.
+	FormatJSON Format = "json"
+	// FormatSlack posts the alert as a message of a slack incoming webhook.
+	FormatSlack Format = "slack"
+)
+
+const (
+	// queueSize is the number of the alerts which can be pending, the alerts are dropped beyond it.
+	queueSize = 100
+	// postTimeout is the timeout of posting an alert.
+	postTimeout = 10 * time.Second
+)
+
+// WebhookURLFromFile returns the url of the webhook held by the file, e.g. a key of a mounted Secret, since the
+// url of a webhook like a slack incoming webhook is a secret itself. The url is left out of the errors.
+func WebhookURLFromFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the url of the webhook: %v", err)
+	}
+	webhookURL := strings.TrimSpace(string(data))
+	if u, err := url.Parse(webhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("the url of the webhook in %s must be an http or https url", path)
+	}
+	return webhookURL, nil
+}
+
+// WebhookSink posts the alerts to a webhook.
+type WebhookSink struct {
+	url    string
+	format Format
+	client *http.Client
+	alerts chan Alert
+}
+
+// NewWebhookSink returns a sink which posts the alerts to the url in the given format.
+func NewWebhookSink(url string, format Format) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		format: format,
+		client: &http.Client{Timeout: postTimeout},
+		alerts: make(chan Alert, queueSize),
+	}
+}
+
+// Write implements Sink. The alert is posted asynchronously by Run, so that the controllers aren't
+// blocked by a slow webhook.
+func (s *WebhookSink) Write(alert Alert) {
+	select {
+	case s.alerts <- alert:
+	default:
+		klog.InfoS("Dropping alert, too many alerts are pending", "reason", alert.Reason, "object", alert.Object.String())
+	}
+}
+
+// Run posts the alerts until ctx is done.
+func (s *WebhookSink) Run(ctx context.Context) {
+	klog.InfoS("Starting notification webhook sink", "format", s.format)
+	defer klog.InfoS("Shutting down notification webhook sink")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case alert := <-s.alerts:
+			if err := s.post(ctx, alert); err != nil {
+				klog.ErrorS(err, "Failed to post alert", "reason", alert.Reason, "object", alert.Object.String())
+			}
+		}
+	}
+}
+
+func (s *WebhookSink) post(ctx context.Context, alert Alert) error {
+	var body interface{} = alert
+	if s.format == FormatSlack {
+		body = map[string]string{"text": slackText(alert)}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			// the url of the webhook is a secret, so it's left out of the error.
+			return fmt.Errorf("failed to post to the webhook: %v", urlErr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+func slackText(alert Alert) string {
+	text := fmt.Sprintf("*%s* %s", alert.Reason, alert.Object.String())
+	if alert.Controller != "" {
+		text += fmt.Sprintf(" (%s controller)", alert.Controller)
+	}
+	if alert.Message != "" {
+		text += "\n" + alert.Message
+	}
+	return text
+}
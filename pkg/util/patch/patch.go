@@ -0,0 +1,147 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	"sigs.k8s.io/yaml"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+)
+
+// patchScheme knows the kinds of the manifests generated by firefly. It's used to
+// look up the kind of a typed object and the schema of strategic merge patches.
+var patchScheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(patchScheme))
+	utilruntime.Must(apiregistrationv1.AddToScheme(patchScheme))
+}
+
+// Apply applies the patches whose targets select the object to it in order.
+// The object can be a typed object or an *unstructured.Unstructured.
+func Apply(obj runtime.Object, patches []installv1alpha1.Patch) error {
+	if len(patches) == 0 {
+		return nil
+	}
+
+	gvk, err := objectKind(obj)
+	if err != nil {
+		return err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	for i, p := range patches {
+		if !matches(p.Target, gvk, accessor.GetName()) {
+			continue
+		}
+		if err := apply(obj, gvk, p); err != nil {
+			return fmt.Errorf("failed to apply patches[%d] to %s %s: %v", i, gvk.Kind, accessor.GetName(), err)
+		}
+	}
+	return nil
+}
+
+func matches(target installv1alpha1.PatchTarget, gvk schema.GroupVersionKind, name string) bool {
+	if target.Group != gvk.Group || target.Kind != gvk.Kind {
+		return false
+	}
+	if target.Version != "" && target.Version != gvk.Version {
+		return false
+	}
+	return target.Name == "" || target.Name == name
+}
+
+func objectKind(obj runtime.Object) (schema.GroupVersionKind, error) {
+	if gvk := obj.GetObjectKind().GroupVersionKind(); !gvk.Empty() {
+		return gvk, nil
+	}
+	gvks, _, err := patchScheme.ObjectKinds(obj)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	return gvks[0], nil
+}
+
+func apply(obj runtime.Object, gvk schema.GroupVersionKind, p installv1alpha1.Patch) error {
+	patchData, err := yaml.YAMLToJSON([]byte(p.Patch))
+	if err != nil {
+		return fmt.Errorf("invalid patch: %v", err)
+	}
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch p.Type {
+	case installv1alpha1.PatchTypeJSON6902:
+		jp, err := jsonpatch.DecodePatch(patchData)
+		if err != nil {
+			return fmt.Errorf("invalid patch: %v", err)
+		}
+		patched, err = jp.Apply(original)
+		if err != nil {
+			return err
+		}
+	case installv1alpha1.PatchTypeStrategicMerge, "":
+		patched, err = strategicMergePatch(original, patchData, obj, gvk)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported patch type %q", p.Type)
+	}
+
+	// reset the object, otherwise the fields removed by the patch are kept.
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		u.Object = nil
+		return u.UnmarshalJSON(patched)
+	}
+	v := reflect.ValueOf(obj).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	return json.Unmarshal(patched, obj)
+}
+
+// strategicMergePatch applies a strategic merge patch with the schema of the typed object.
+// If the kind is unknown, a json merge patch is applied instead.
+func strategicMergePatch(original, patch []byte, obj runtime.Object, gvk schema.GroupVersionKind) ([]byte, error) {
+	dataStruct := obj
+	if _, ok := obj.(*unstructured.Unstructured); ok {
+		typed, err := patchScheme.New(gvk)
+		if err != nil {
+			return jsonpatch.MergePatch(original, patch)
+		}
+		dataStruct = typed
+	}
+	return strategicpatch.StrategicMergePatch(original, patch, dataStruct)
+}
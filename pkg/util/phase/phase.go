@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package phase
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+)
+
+// conditionReady is the type of the Ready condition shared by all the installations.
+const conditionReady = "Ready"
+
+// problemWhenTrue are the types of the conditions which report a problem when they're true. The other
+// conditions report a problem when they aren't true.
+var problemWhenTrue = sets.NewString(
+	installv1alpha1.KarmadaConditionPaused,
+	installv1alpha1.KarmadaConditionRolloutPaused,
+	installv1alpha1.KarmadaConditionEtcdDegraded,
+)
+
+// Summarize returns the phase of an installation from its status. It's Ready once the latest generation
+// has been observed, the installation is up to date and no condition reports a problem.
+func Summarize(obj metav1.Object, observedGeneration int64, conditions []metav1.Condition, upToDate bool) installv1alpha1.Phase {
+	switch {
+	case obj.GetDeletionTimestamp() != nil:
+		return installv1alpha1.PhaseDeleting
+	case meta.IsStatusConditionTrue(conditions, installv1alpha1.KarmadaConditionPaused):
+		return installv1alpha1.PhasePaused
+	case meta.IsStatusConditionFalse(conditions, installv1alpha1.KarmadaConditionAccepted):
+		return installv1alpha1.PhaseRejected
+	case observedGeneration < obj.GetGeneration() || !upToDate:
+		return installv1alpha1.PhaseProgressing
+	case firstProblem(conditions) != nil:
+		return installv1alpha1.PhaseProgressing
+	}
+	return installv1alpha1.PhaseReady
+}
+
+// firstProblem returns the first condition reporting a problem, or nil if there's none. The Ready
+// condition is skipped since it's summarized from the others.
+func firstProblem(conditions []metav1.Condition) *metav1.Condition {
+	for i := range conditions {
+		c := &conditions[i]
+		if c.Type == conditionReady {
+			continue
+		}
+		if problemWhenTrue.Has(c.Type) == (c.Status == metav1.ConditionTrue) {
+			return c
+		}
+	}
+	return nil
+}
+
+// setReady sets the Ready condition of an installation from its phase. Its reason is the phase, and its
+// message tells the first condition reporting a problem if there's one.
+func setReady(conditions *[]metav1.Condition, generation int64, phase installv1alpha1.Phase) {
+	condition := metav1.Condition{
+		Type:               conditionReady,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             string(phase),
+		Message:            fmt.Sprintf("the installation is %s", phase),
+	}
+	if phase == installv1alpha1.PhaseReady {
+		condition.Status = metav1.ConditionTrue
+	} else if problem := firstProblem(*conditions); problem != nil {
+		condition.Message = fmt.Sprintf("%s: %s", problem.Type, problem.Message)
+	}
+	meta.SetStatusCondition(conditions, condition)
+}
+
+// SetKarmadaPhase sets the phase and the Ready condition of the karmada from its status.
+func SetKarmadaPhase(karmada *installv1alpha1.Karmada) {
+	karmada.Status.Phase = Summarize(karmada, karmada.Status.ObservedGeneration, karmada.Status.Conditions,
+		karmada.Status.KarmadaVersion == karmada.Spec.KarmadaVersion)
+	setReady(&karmada.Status.Conditions, karmada.Generation, karmada.Status.Phase)
+}
+
+// SetClusterpediaPhase sets the phase and the Ready condition of the clusterpedia from its status.
+func SetClusterpediaPhase(clusterpedia *installv1alpha1.Clusterpedia) {
+	clusterpedia.Status.Phase = Summarize(clusterpedia, clusterpedia.Status.ObservedGeneration, clusterpedia.Status.Conditions,
+		clusterpedia.Status.Version == clusterpedia.Spec.Version)
+	setReady(&clusterpedia.Status.Conditions, clusterpedia.Generation, clusterpedia.Status.Phase)
+}
+
+// SetClusterRegistrationPhase sets the phase and the Ready condition of the registration from its status.
+func SetClusterRegistrationPhase(cr *installv1alpha1.ClusterRegistration) {
+	cr.Status.Phase = Summarize(cr, cr.Status.ObservedGeneration, cr.Status.Conditions, true)
+	setReady(&cr.Status.Conditions, cr.Generation, cr.Status.Phase)
+}
+
+// SetAddonPhase sets the phase of the addon from its status. The Ready condition of the addon is set by
+// the addon controller, and tells whether its resources are installed.
+func SetAddonPhase(addon *installv1alpha1.Addon) {
+	addon.Status.Phase = Summarize(addon, addon.Status.ObservedGeneration, addon.Status.Conditions,
+		meta.IsStatusConditionTrue(addon.Status.Conditions, installv1alpha1.AddonConditionReady))
+}
+
+// SetSubmarinerPhase sets the phase of the submariner from its status. The Ready condition of the
+// submariner is set by the submariner controller, and tells whether its components are installed.
+func SetSubmarinerPhase(submariner *installv1alpha1.Submariner) {
+	submariner.Status.Phase = Summarize(submariner, submariner.Status.ObservedGeneration, submariner.Status.Conditions,
+		meta.IsStatusConditionTrue(submariner.Status.Conditions, installv1alpha1.SubmarinerConditionReady))
+}
+
+// SetSecretSyncPhase sets the phase of the secret sync from its status. The Ready condition of the
+// secret sync is set by the secretsync controller, and tells whether its objects are mirrored.
+func SetSecretSyncPhase(secretSync *installv1alpha1.SecretSync) {
+	secretSync.Status.Phase = Summarize(secretSync, secretSync.Status.ObservedGeneration, secretSync.Status.Conditions,
+		meta.IsStatusConditionTrue(secretSync.Status.Conditions, installv1alpha1.SecretSyncConditionReady))
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// cosignSignatureMediaType is the media type of the layers of a cosign signature, whose content
+	// is the signed payload.
+	cosignSignatureMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// cosignSignatureAnnotation is the annotation of a layer of a cosign signature holding the
+	// base64-encoded signature of the payload.
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// signatureManifestMediaTypes are the media types of the manifests accepted for signatures.
+	signatureManifestMediaTypes = "application/vnd.oci.image.manifest.v1+json," +
+		"application/vnd.docker.distribution.manifest.v2+json"
+)
+
+// ParsePublicKeys parses the PEM-encoded public keys, e.g. the values of a Secret. The keys are
+// parsed in the order of their names so that the errors are stable.
+func ParsePublicKeys(data map[string][]byte) ([]crypto.PublicKey, error) {
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var keys []crypto.PublicKey
+	for _, name := range names {
+		block, _ := pem.Decode(data[name])
+		if block == nil {
+			return nil, fmt.Errorf("%s is not PEM-encoded", name)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key %s: %v", name, err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public key found")
+	}
+	return keys, nil
+}
+
+// simpleSigningPayload is the payload signed by cosign for an image.
+type simpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// VerifySignature verifies that the manifest of the image with the digest is signed by cosign with
+// one of the keys. The signatures are looked up by the tag cosign attaches them to in the repository
+// of the image. Keyless signatures, which are verified with the transparency log, are not supported.
+func (c *Client) VerifySignature(ctx context.Context, image, digest string, keys []crypto.PublicKey) error {
+	named, err := ParseImage(image)
+	if err != nil {
+		return err
+	}
+	repo := c.repository(named)
+
+	data, _, err := repo.get(ctx, "manifests/"+strings.Replace(digest, ":", "-", 1)+".sig", signatureManifestMediaTypes)
+	if _, ok := err.(*notFoundError); ok {
+		return fmt.Errorf("image %s@%s is not signed", named.Name(), digest)
+	}
+	if err != nil {
+		return err
+	}
+	manifest := struct {
+		Layers []struct {
+			MediaType   string            `json:"mediaType"`
+			Digest      string            `json:"digest"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"layers"`
+	}{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse the signatures of %s@%s: %v", named.Name(), digest, err)
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != cosignSignatureMediaType || layer.Annotations[cosignSignatureAnnotation] == "" {
+			continue
+		}
+		signature, err := base64.StdEncoding.DecodeString(layer.Annotations[cosignSignatureAnnotation])
+		if err != nil {
+			continue
+		}
+		payload, _, err := repo.get(ctx, "blobs/"+layer.Digest, "")
+		if err != nil {
+			return err
+		}
+		if fmt.Sprintf("sha256:%x", sha256.Sum256(payload)) != layer.Digest {
+			continue
+		}
+		signed := &simpleSigningPayload{}
+		if err := json.Unmarshal(payload, signed); err != nil || signed.Critical.Image.DockerManifestDigest != digest {
+			continue
+		}
+		for _, key := range keys {
+			if verify(key, payload, signature) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("image %s@%s has no signature verified by the public keys", named.Name(), digest)
+}
+
+// verify returns whether the signature of the payload is made by the private key of the public key,
+// as cosign signs with the different kinds of keys.
+func verify(key crypto.PublicKey, payload, signature []byte) bool {
+	hash := sha256.Sum256(payload)
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, hash[:], signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, payload, signature)
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registry resolves the tags of container images to digests and verifies their cosign
+// signatures with the distribution api of the registries.
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/distribution/distribution/v3/reference"
+)
+
+const (
+	// manifestMediaTypes are the media types of the manifests accepted when a tag is resolved,
+	// so that the digest of a multi-arch image is the digest of its index.
+	manifestMediaTypes = "application/vnd.oci.image.index.v1+json," +
+		"application/vnd.docker.distribution.manifest.list.v2+json," +
+		"application/vnd.oci.image.manifest.v1+json," +
+		"application/vnd.docker.distribution.manifest.v2+json"
+
+	// maxBlobSize is the max size of a manifest or a signature payload.
+	maxBlobSize = 4 << 20
+)
+
+// Client talks to container registries. Only anonymous access is supported.
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient returns a new *Client.
+func NewClient() *Client {
+	return &Client{httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// ParseImage parses an image reference, e.g. `ghcr.io/carlory/karmada-apiserver:v1.3.0`.
+// The images without a registry are pulled from docker hub.
+func ParseImage(image string) (reference.Named, error) {
+	return reference.ParseNormalizedNamed(image)
+}
+
+// WithDigest returns the image pinned to the digest. The tag of the image is kept for readability.
+func WithDigest(image, digest string) string {
+	return image + "@" + digest
+}
+
+// ResolveDigest returns the digest of the manifest the tag of the image points to. If the image is
+// already pinned to a digest, the digest is returned as is.
+func (c *Client) ResolveDigest(ctx context.Context, image string) (string, error) {
+	named, err := ParseImage(image)
+	if err != nil {
+		return "", err
+	}
+	if canonical, ok := named.(reference.Canonical); ok {
+		return canonical.Digest().String(), nil
+	}
+	tag := "latest"
+	if tagged, ok := named.(reference.Tagged); ok {
+		tag = tagged.Tag()
+	}
+
+	repo := c.repository(named)
+	data, header, err := repo.get(ctx, "manifests/"+tag, manifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+	if digest := header.Get("Docker-Content-Digest"); digest != "" {
+		return digest, nil
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data)), nil
+}
+
+func (c *Client) repository(named reference.Named) *repository {
+	domain := reference.Domain(named)
+	if domain == "docker.io" {
+		domain = "registry-1.docker.io"
+	}
+	return &repository{client: c, registry: domain, name: reference.Path(named)}
+}
+
+// repository talks to a repository of a registry, and caches the anonymous token it's granted.
+type repository struct {
+	client   *Client
+	registry string
+	name     string
+	token    string
+}
+
+func (r *repository) get(ctx context.Context, p, accept string) ([]byte, http.Header, error) {
+	u := fmt.Sprintf("https://%s/v2/%s/%s", r.registry, r.name, p)
+	header := http.Header{}
+	if accept != "" {
+		header.Set("Accept", accept)
+	}
+	if r.token != "" {
+		header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	data, respHeader, err := r.client.get(ctx, u, header)
+	challenge, ok := err.(*unauthorizedError)
+	if !ok || r.token != "" {
+		return data, respHeader, err
+	}
+
+	// get an anonymous token according to the challenge and try again.
+	token, err := r.client.anonymousToken(ctx, challenge.authenticate, r.name)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.token = token
+	header.Set("Authorization", "Bearer "+r.token)
+	return r.client.get(ctx, u, header)
+}
+
+// anonymousToken requests a bearer token according to a WWW-Authenticate challenge like
+// `Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:foo/bar:pull"`.
+func (c *Client) anonymousToken(ctx context.Context, authenticate, name string) (string, error) {
+	if !strings.HasPrefix(authenticate, "Bearer ") {
+		return "", fmt.Errorf("unsupported authentication challenge %q", authenticate)
+	}
+	params := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(authenticate, "Bearer "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("no realm found in authentication challenge %q", authenticate)
+	}
+	if params["scope"] == "" {
+		params["scope"] = fmt.Sprintf("repository:%s:pull", name)
+	}
+
+	u, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("scope", params["scope"])
+	if params["service"] != "" {
+		q.Set("service", params["service"])
+	}
+	u.RawQuery = q.Encode()
+
+	data, _, err := c.get(ctx, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return "", err
+	}
+	if resp.Token != "" {
+		return resp.Token, nil
+	}
+	return resp.AccessToken, nil
+}
+
+// unauthorizedError is returned when a request is rejected with 401.
+type unauthorizedError struct {
+	url          string
+	authenticate string
+}
+
+func (e *unauthorizedError) Error() string {
+	return fmt.Sprintf("unauthorized to get %s", e.url)
+}
+
+// notFoundError is returned when a request is rejected with 404.
+type notFoundError struct {
+	url string
+}
+
+func (e *notFoundError) Error() string {
+	return fmt.Sprintf("%s not found", e.url)
+}
+
+func (c *Client) get(ctx context.Context, u string, header http.Header) ([]byte, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return nil, nil, &unauthorizedError{url: u, authenticate: resp.Header.Get("WWW-Authenticate")}
+	case http.StatusNotFound:
+		return nil, nil, &notFoundError{url: u}
+	default:
+		return nil, nil, fmt.Errorf("failed to get %s: %s", u, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBlobSize+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data) > maxBlobSize {
+		return nil, nil, fmt.Errorf("failed to get %s: larger than %d bytes", u, maxBlobSize)
+	}
+	return data, resp.Header, nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resolver resolves the object references and the owner references of the objects managed by
+// firefly, it caches the resolved objects for a short while to avoid redundant GETs during reconcile storms.
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/carlory/firefly/pkg/util/ttlcache"
+)
+
+// DefaultTTL is how long a resolved object is served from the cache. It's short enough for the
+// controllers to notice the changes of the referenced objects on their next resync.
+const DefaultTTL = 10 * time.Second
+
+// Resolver resolves object references through the dynamic client.
+type Resolver struct {
+	mapper meta.RESTMapper
+	client dynamic.Interface
+	cache  *ttlcache.Cache
+}
+
+// New returns a resolver which caches the resolved objects in the given cache.
+func New(mapper meta.RESTMapper, client dynamic.Interface, cache *ttlcache.Cache) *Resolver {
+	return &Resolver{mapper: mapper, client: client, cache: cache}
+}
+
+// Resolve returns the referenced object. A NotFound error is returned if the object doesn't exist,
+// or if the reference has a uid and the object has been recreated since. The returned object is a
+// copy and may be mutated by the caller.
+func (r *Resolver) Resolve(ctx context.Context, ref corev1.ObjectReference) (*unstructured.Unstructured, error) {
+	gvr, namespaced, err := r.resourceFor(ref)
+	if err != nil {
+		return nil, err
+	}
+	namespace := ref.Namespace
+	if !namespaced {
+		namespace = ""
+	}
+
+	key := cacheKey(gvr, namespace, ref.Name)
+	if cached, ok := r.cache.Get(key); ok {
+		obj := cached.(*unstructured.Unstructured)
+		if ref.UID == "" || obj.GetUID() == ref.UID {
+			return obj.DeepCopy(), nil
+		}
+		r.cache.Delete(key)
+	}
+
+	obj, err := r.client.Resource(gvr).Namespace(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	r.cache.Set(key, obj)
+	if ref.UID != "" && obj.GetUID() != ref.UID {
+		return nil, apierrors.NewNotFound(gvr.GroupResource(), ref.Name)
+	}
+	return obj.DeepCopy(), nil
+}
+
+// ResolveOwner returns the owner of an object in the given namespace, the namespace is ignored if the
+// owner is cluster-scoped.
+func (r *Resolver) ResolveOwner(ctx context.Context, namespace string, owner metav1.OwnerReference) (*unstructured.Unstructured, error) {
+	return r.Resolve(ctx, corev1.ObjectReference{
+		APIVersion: owner.APIVersion,
+		Kind:       owner.Kind,
+		Namespace:  namespace,
+		Name:       owner.Name,
+		UID:        owner.UID,
+	})
+}
+
+// Invalidate evicts the referenced object from the cache, it's called by the controllers after they
+// have changed the object.
+func (r *Resolver) Invalidate(ref corev1.ObjectReference) {
+	gvr, namespaced, err := r.resourceFor(ref)
+	if err != nil {
+		return
+	}
+	namespace := ref.Namespace
+	if !namespaced {
+		namespace = ""
+	}
+	r.cache.Delete(cacheKey(gvr, namespace, ref.Name))
+}
+
+func (r *Resolver) resourceFor(ref corev1.ObjectReference) (schema.GroupVersionResource, bool, error) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+	mapping, err := r.mapper.RESTMapping(gv.WithKind(ref.Kind).GroupKind(), gv.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("failed to map %s %s: %v", ref.APIVersion, ref.Kind, err)
+	}
+	return mapping.Resource, mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+func cacheKey(gvr schema.GroupVersionResource, namespace, name string) string {
+	return gvr.String() + "/" + namespace + "/" + name
+}
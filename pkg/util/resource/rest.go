@@ -20,24 +20,34 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/otel"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/component-base/tracing"
+
+	"github.com/carlory/firefly/pkg/util/dryrun"
+	"github.com/carlory/firefly/pkg/util/kms"
 )
 
 // GetClientConfigFromKubeConfigSecret reads a kubeconfig from the given namespace and secretName and
-// returns a *rest.Config that the given user-agent is added.
+// returns a *rest.Config that the given user-agent is added. The requests sent with the config are
+// traced by the global tracer provider, so that they join the spans of the reconciles, and dry-run
+// if they are sent with a dry-run context.
 func GetClientConfigFromKubeConfigSecret(client kubernetes.Interface, namespace, secretName, clientUserAgent string) (*restclient.Config, error) {
 	kubeconfigSecret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), secretName, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	kubeconfig, ok := kubeconfigSecret.Data["kubeconfig"]
-	if !ok {
+	if _, ok := kubeconfigSecret.Data["kubeconfig"]; !ok {
 		return nil, fmt.Errorf("the secret %s doesn't contain the kubeconfig field in the namespace %s", secretName, namespace)
 	}
+	kubeconfig, err := kms.SecretData(context.TODO(), kubeconfigSecret, "kubeconfig")
+	if err != nil {
+		return nil, err
+	}
 
 	config, err := clientcmd.NewClientConfigFromBytes(kubeconfig)
 	if err != nil {
@@ -47,5 +57,7 @@ func GetClientConfigFromKubeConfigSecret(client kubernetes.Interface, namespace,
 	if err != nil {
 		return nil, err
 	}
+	clientConfig.Wrap(tracing.WrapperFor(otel.GetTracerProvider()))
+	clientConfig.Wrap(dryrun.WrapperFor())
 	return restclient.AddUserAgent(clientConfig, clientUserAgent), nil
 }
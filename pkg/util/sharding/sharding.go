@@ -0,0 +1,45 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sharding assigns the clusters synchronized by clusterpedia to the shards of its clustersynchro-manager.
+package sharding
+
+import (
+	"hash/fnv"
+
+	installv1alpha1 "github.com/carlory/firefly/pkg/apis/install/v1alpha1"
+)
+
+// Shard returns the index of the shard the cluster is assigned to by the sharding, which must have
+// at least one shard. The assignments of the Static policy which are out of range are ignored.
+func Shard(sharding *installv1alpha1.ClusterSynchroSharding, cluster string) int32 {
+	if sharding.Policy == installv1alpha1.ClusterSynchroShardingStatic {
+		for _, assignment := range sharding.Assignments {
+			if assignment.Shard < 0 || assignment.Shard >= sharding.Shards {
+				continue
+			}
+			for _, name := range assignment.Clusters {
+				if name == cluster {
+					return assignment.Shard
+				}
+			}
+		}
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(cluster))
+	return int32(h.Sum32() % uint32(sharding.Shards))
+}
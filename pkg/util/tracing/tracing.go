@@ -0,0 +1,52 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer used by the controllers.
+const instrumentationName = "github.com/carlory/firefly"
+
+// StartReconcile starts a span for a reconcile of the key by the given controller. Requests
+// sent to the apiserver with the returned context are recorded as child spans of it, as long as
+// the rest config of the client is wrapped by tracing.WrapperFor.
+//
+// The span is created by the global tracer provider, which is a noop unless tracing is enabled.
+func StartReconcile(ctx context.Context, controller, key string) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, controller+" reconcile",
+		trace.WithAttributes(
+			attribute.String("controller", controller),
+			attribute.String("key", key),
+		),
+	)
+}
+
+// EndReconcile ends the span of a reconcile, recording the error if it failed.
+func EndReconcile(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
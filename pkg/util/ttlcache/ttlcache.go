@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Firefly Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ttlcache provides the expiring caches shared by the controllers of a controller manager, so that
+// the objects which are fetched from the apiserver during reconcile storms are only fetched once in a while.
+package ttlcache
+
+import (
+	"sync"
+	"time"
+
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+)
+
+// Cache is an expiring cache whose entries are evicted after a default ttl.
+type Cache struct {
+	ttl     time.Duration
+	entries *utilcache.Expiring
+}
+
+// New returns an empty cache whose entries expire after the given ttl.
+func New(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, entries: utilcache.NewExpiring()}
+}
+
+// Get returns the unexpired value of the key, ok is false if there is no such value.
+func (c *Cache) Get(key string) (interface{}, bool) {
+	return c.entries.Get(key)
+}
+
+// Set stores the value of the key for the default ttl of the cache.
+func (c *Cache) Set(key string, value interface{}) {
+	c.entries.Set(key, value, c.ttl)
+}
+
+// SetWithTTL stores the value of the key for the given ttl.
+func (c *Cache) SetWithTTL(key string, value interface{}, ttl time.Duration) {
+	c.entries.Set(key, value, ttl)
+}
+
+// Delete evicts the value of the key, it's called when the value is known to be stale.
+func (c *Cache) Delete(key string) {
+	c.entries.Delete(key)
+}
+
+// Len returns the number of the entries in the cache, including the expired ones which are not evicted yet.
+func (c *Cache) Len() int {
+	return c.entries.Len()
+}
+
+// Registry hands out the caches of the controllers started by a controller manager. The caches are
+// shared by name, so that the controllers fetching the same objects benefit from each other's requests.
+type Registry struct {
+	lock   sync.Mutex
+	caches map[string]*Cache
+}
+
+// NewRegistry returns an empty registry of caches.
+func NewRegistry() *Registry {
+	return &Registry{caches: map[string]*Cache{}}
+}
+
+// For returns the named cache, it's created with the given ttl on the first call. The ttl of the
+// later calls is ignored.
+func (r *Registry) For(name string, ttl time.Duration) *Cache {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	c, ok := r.caches[name]
+	if !ok {
+		c = New(ttl)
+		r.caches[name] = c
+	}
+	return c
+}